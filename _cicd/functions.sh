@@ -8,7 +8,7 @@ set -o pipefail # return exit code of the last piped command
 
 VERSION="${LENSES_CLI_CUR_VERSION}"
 REVISION="${GIT_COMMIT:=$(git rev-parse HEAD)}"
-LDFLAGS="-s -w -X main.buildVersion=${VERSION} -X main.buildRevision=${REVISION} -X main.buildTime=$(date +%s)"
+LDFLAGS="-s -w -X github.com/lensesio/lenses-go/pkg/buildinfo.Version=${VERSION} -X github.com/lensesio/lenses-go/pkg/buildinfo.Commit=${REVISION} -X github.com/lensesio/lenses-go/pkg/buildinfo.Date=$(date +%s)"
 
 # thanks to http://mywiki.wooledge.org/BashFAQ/028
 LOCAL_ENV="${BASH_SOURCE%/*}/local.env"
@@ -75,9 +75,9 @@ cross-build() {
                 go build -ldflags "${LDFLAGS}" -v -o \
                     ./bin/lenses-cli-$GOOS-$GOARCH ./cmd/lenses-cli
             else
-                LDFLAGS_DEV="-X main.buildVersion=${VERSION} \
-                    -X main.buildRevision=${REVISION} \
-                    -X main.buildTime=$(date +%s)"
+                LDFLAGS_DEV="-X github.com/lensesio/lenses-go/pkg/buildinfo.Version=${VERSION} \
+                    -X github.com/lensesio/lenses-go/pkg/buildinfo.Commit=${REVISION} \
+                    -X github.com/lensesio/lenses-go/pkg/buildinfo.Date=$(date +%s)"
                 go build -ldflags "$LDFLAGS_DEV" -v -o ./bin/lenses-cli-$GOOS-$GOARCH \
                     ./cmd/lenses-cli
             fi
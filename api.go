@@ -0,0 +1,9 @@
+package lenses
+
+import v1 "github.com/lensesio/lenses-go/v1"
+
+// API is the Lenses REST API, as implemented by the `api.Client`, it's the stable `v1.Client`.
+type API = v1.Client
+
+// LiveStream is a live query's stream of messages, it's the `v1.LiveStream`.
+type LiveStream = v1.LiveStream
@@ -0,0 +1,9 @@
+package lenses
+
+import "github.com/lensesio/lenses-go/pkg/buildinfo"
+
+// BuildInfo returns the build metadata of the library and of the binary that uses it,
+// i.e the version of the lenses-go module and the supported range of the Lenses versions.
+func BuildInfo() buildinfo.Info {
+	return buildinfo.Get()
+}
@@ -5,12 +5,19 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/kataras/golog"
 	"github.com/lensesio/bite"
 	"github.com/lensesio/lenses-go/pkg/acl"
 	"github.com/lensesio/lenses-go/pkg/alert"
 	"github.com/lensesio/lenses-go/pkg/api"
 	"github.com/lensesio/lenses-go/pkg/audit"
+	"github.com/lensesio/lenses-go/pkg/backup"
+	"github.com/lensesio/lenses-go/pkg/batch"
+	"github.com/lensesio/lenses-go/pkg/buildinfo"
+	"github.com/lensesio/lenses-go/pkg/cluster"
 	config "github.com/lensesio/lenses-go/pkg/configs"
 	"github.com/lensesio/lenses-go/pkg/connection"
 	"github.com/lensesio/lenses-go/pkg/connector"
@@ -18,24 +25,32 @@ import (
 	"github.com/lensesio/lenses-go/pkg/consumers"
 	"github.com/lensesio/lenses-go/pkg/dataset"
 	"github.com/lensesio/lenses-go/pkg/elasticsearch"
+	"github.com/lensesio/lenses-go/pkg/exitcode"
 	"github.com/lensesio/lenses-go/pkg/export"
+	"github.com/lensesio/lenses-go/pkg/healthcheck"
 	imports "github.com/lensesio/lenses-go/pkg/import"
 	"github.com/lensesio/lenses-go/pkg/initcontainer"
 	"github.com/lensesio/lenses-go/pkg/license"
 	"github.com/lensesio/lenses-go/pkg/logs"
 	"github.com/lensesio/lenses-go/pkg/management"
+	"github.com/lensesio/lenses-go/pkg/plugin"
 	"github.com/lensesio/lenses-go/pkg/policy"
 	"github.com/lensesio/lenses-go/pkg/processor"
 	"github.com/lensesio/lenses-go/pkg/provision"
 	"github.com/lensesio/lenses-go/pkg/quota"
+	"github.com/lensesio/lenses-go/pkg/raw"
 	"github.com/lensesio/lenses-go/pkg/schemas"
+	"github.com/lensesio/lenses-go/pkg/search"
 	"github.com/lensesio/lenses-go/pkg/secret"
+	"github.com/lensesio/lenses-go/pkg/selfupdate"
 	"github.com/lensesio/lenses-go/pkg/shell"
 	"github.com/lensesio/lenses-go/pkg/sql"
 	"github.com/lensesio/lenses-go/pkg/topic"
 	"github.com/lensesio/lenses-go/pkg/topicsettings"
 	"github.com/lensesio/lenses-go/pkg/user"
+	"github.com/lensesio/lenses-go/pkg/utils"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 var (
@@ -45,31 +60,55 @@ var (
 		Version:     "blop",
 		ShowSpinner: false,
 		Setup:       setup,
+		Shutdown:    shutdown,
 	}
-
-	// buildRevision is the build revision (docker commit string or git rev-parse HEAD) but it's
-	// available only on the build state, on the cli executable - via the "--version" flag.
-	buildRevision = ""
-	// buildTime is the build unix time (in seconds since 1970-01-01 00:00:00 UTC), like the `buildRevision`,
-	// this is available on after the build state, inside the cli executable - via the "--version" flag.
-	//
-	// Note that this buildTime is not int64, it's type of string and it is provided at build time.
-	// Do not change!
-	buildTime    = ""
-	buildVersion = ""
 )
 
 func setup(cmd *cobra.Command, args []string) error {
+	if err := utils.ApplyJSONQuery(cmd); err != nil {
+		return err
+	}
+
+	if err := utils.ApplyTemplate(cmd); err != nil {
+		return err
+	}
+
+	config.StartAnsible(cmd)
+
+	// the steps of a `run` script share the configuration and the client of the `run` command.
+	if batch.Running && config.Client != nil {
+		if err := config.CheckProtected(cmd); err != nil {
+			return err
+		}
+		return config.CheckFeature(cmd)
+	}
+
 	ok, err := config.Manager.Load()
+	if err == nil {
+		if err = config.CheckProtected(cmd); err != nil {
+			return err
+		}
+		config.PrintContextHeader(cmd.ErrOrStderr())
+	}
+
+	if err := startTimeout(); err != nil {
+		return err
+	}
+
 	// if command is "configure" and the configuration is invalid at this point, don't give a failure,
 	// let the configure command give a tutorial for user in order to create a configuration file.
 	// Note that if clientConfig is valid and we are inside the configure command
 	// then the configure will normally continue and save the valid configuration (that normally came from flags).
 	topLevelSubCmd := strings.Split(cmd.CommandPath(), " ")[1]
-	if name := topLevelSubCmd; name == "configure" || name == "version" || name == "context" || name == "contexts" || name == "init-container" || strings.Contains(cmd.CommandPath(), " secrets ") {
+	if name := topLevelSubCmd; name == "configure" || name == "version" || name == "context" || name == "contexts" || name == "init-container" || name == "healthcheck" || name == "logout" || name == "update" || strings.Contains(cmd.CommandPath(), " secrets ") {
 		return nil
 	}
 
+	// the login asks for the missing credentials itself and creates a new session.
+	if cmd.Name() == "login" {
+		return err
+	}
+
 	// it's not nil, if context does not exist then it would throw an error.
 	currentConfig := config.Manager.Config.GetCurrent()
 	for !ok {
@@ -81,6 +120,12 @@ func setup(cmd *cobra.Command, args []string) error {
 			fmt.Fprintf(cmd.OutOrStdout(), "%#+v\n", *currentConfig)
 		}
 
+		// i.e a Kubernetes Job, there is no one to answer the configure survey.
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			return exitcode.WithCode(exitcode.Validation, fmt.Errorf("cannot retrieve credentials, set the %s and the %s or the %s and %s environment variables, or a configuration file",
+				config.EnvHost, config.EnvToken, config.EnvUser, config.EnvPassword))
+		}
+
 		fmt.Fprintln(cmd.OutOrStderr(), "cannot retrieve credentials, please configure below")
 		configureCmd := user.NewConfigureCommand("")
 		// disable any flags passed on the parent command before execute.
@@ -92,44 +137,66 @@ func setup(cmd *cobra.Command, args []string) error {
 		ok, err = config.Manager.Load()
 	}
 
-	// if login, remove the token so setupClient will generate a new one and save it to the home dir/lenses-cli.yml.
-	if cmd.Name() == "login" {
-		currentConfig.Token = ""
-
-		if basicAuth, isBasicAuth := currentConfig.Authentication.(api.BasicAuthentication); isBasicAuth {
-			//  and fire any errors if host or user or pass are not there.
-			if currentConfig.Host == "" || basicAuth.Username == "" || basicAuth.Password == "" {
-				// return fmt.Errorf("cannot retrieve credentials, please setup the configuration using the '%s' command first", "configure")
-				//
-				if err := user.NewConfigureCommand("").Execute(); err != nil {
-					return err
-				}
-
-				// add a new line, so the login's session welcome messages has its place.
-				fmt.Fprintln(cmd.OutOrStdout())
-			}
-		}
+	if err = config.SetupClient(); err != nil {
+		return err
+	}
 
-		return nil
+	return config.CheckFeature(cmd)
+}
+
+var timeoutOnce sync.Once
+
+// startTimeout bounds the whole invocation by the --invocation-timeout, it exits with the `exitcode.Timeout` when it elapses,
+// so a Kubernetes Job or a CI step never hangs.
+func startTimeout() error {
+	timeout, err := config.Manager.InvocationTimeout()
+	if err != nil || timeout == 0 {
+		return exitcode.WithCode(exitcode.Validation, err)
 	}
 
-	return config.SetupClient()
+	timeoutOnce.Do(func() {
+		time.AfterFunc(timeout, func() {
+			err := exitcode.WithCode(exitcode.Timeout, fmt.Errorf("the command did not complete within the timeout [%s]", timeout))
+			os.Exit(exitcode.Print(os.Stderr, config.Manager.ErrorFormat, err))
+		})
+	})
+
+	return nil
+}
+
+// shutdown runs after each command that succeeded.
+func shutdown(cmd *cobra.Command, args []string) error {
+	if err := config.FinishAnsible(cmd); err != nil {
+		return err
+	}
+
+	// the changelog should not fail a change that's already made.
+	if err := config.RecordChange(cmd, args); err != nil {
+		golog.Errorf("Failed to record the change to the history. [%s]", err.Error())
+	}
+
+	// the new version notice is for the interactive sessions only, it's best effort.
+	if cmd.CommandPath() != cmd.Root().Name()+" update" && term.IsTerminal(int(os.Stderr.Fd())) {
+		if err := selfupdate.Notify(cmd.ErrOrStderr(), buildinfo.Version); err != nil {
+			golog.Debugf("Failed to check for a new version. [%s]", err.Error())
+		}
+	}
+
+	return nil
 }
 
 func main() {
 
-	if buildRevision != "" {
+	if buildinfo.Commit != "" {
 		app.HelpTemplate = bite.HelpTemplate{
 			Name:                 "lenses-cli",
-			BuildRevision:        buildRevision,
-			BuildTime:            buildTime,
-			BuildVersion:         buildVersion,
+			BuildRevision:        buildinfo.Commit,
+			BuildTime:            buildinfo.Date,
+			BuildVersion:         buildinfo.Version,
 			ShowGoRuntimeVersion: true,
 		}
 	}
 
-	api.BuildVersion = buildVersion
-
 	if len(os.Args) == 1 || (string(os.Args[1]) != "secrets" && string(os.Args[1]) != "version") {
 		app.PersistentFlags = config.SetupConfigManager
 	} else {
@@ -144,7 +211,7 @@ func main() {
 	//Alert
 	app.AddCommand(alert.NewAlertGroupCommand())
 	app.AddCommand(alert.NewGetAlertsCommand())
-	app.AddCommand(alert.NewGetAlertChannelsCommand())
+	app.AddCommand(config.RequireFeature(api.FeatureAlertChannels, alert.NewGetAlertChannelsCommand()))
 
 	// Alert channel templates
 	app.AddCommand((alert.NewGetAlertChannelTemplatesCommand()))
@@ -156,7 +223,10 @@ func main() {
 	app.AddCommand((audit.NewGetAuditChannelTemplatesCommand()))
 
 	// Audit channels
-	app.AddCommand(audit.NewGetAuditChannelsCommand())
+	app.AddCommand(config.RequireFeature(api.FeatureAuditChannels, audit.NewGetAuditChannelsCommand()))
+
+	//Cluster
+	app.AddCommand(cluster.NewClusterGroupCommand())
 
 	//Config
 	app.AddCommand(config.NewGetConfigsCommand())
@@ -173,7 +243,7 @@ func main() {
 	app.AddCommand(export.NewExportGroupCommand())
 
 	//Import
-	app.AddCommand(imports.NewImportGroupCommand())
+	app.AddCommand(config.WithLock(imports.NewImportGroupCommand()))
 
 	//License
 	app.AddCommand(license.NewLicenseGroupCommand())
@@ -194,8 +264,8 @@ func main() {
 	app.AddCommand(topic.NewTopicGroupCommand())
 
 	//Elasticsearch Indexes
-	app.AddCommand(elasticsearch.IndexesCommand())
-	app.AddCommand(elasticsearch.IndexCommand())
+	app.AddCommand(config.RequireFeature(api.FeatureElasticsearch, elasticsearch.IndexesCommand()))
+	app.AddCommand(config.RequireFeature(api.FeatureElasticsearch, elasticsearch.IndexCommand()))
 
 	//Quotas
 	app.AddCommand(quota.NewGetQuotasCommand())
@@ -209,37 +279,79 @@ func main() {
 
 	//SQL
 	app.AddCommand(sql.NewLiveLSQLCommand())
+	app.AddCommand(sql.NewSQLGroupCommand())
+	app.AddCommand(sql.NewQueriesCommand())
+	app.AddCommand(sql.NewTailCommand())
+	app.AddCommand(sql.NewBenchCommand())
+	app.AddCommand(sql.NewLatencyCommand())
 
 	//User
 	app.AddCommand(user.NewGetConfigurationContextsCommand())
 	app.AddCommand(user.NewConfigurationContextCommand())
 	app.AddCommand(user.NewConfigureCommand(""))
 	app.AddCommand(user.NewLoginCommand(app))
+	app.AddCommand(user.NewLogoutCommand())
+	app.AddCommand(user.NewWhoAmICommand())
 	app.AddCommand(user.NewUserGroupCommand())
 
 	//Management
 	app.AddCommand(management.NewGroupsCommand())
 	app.AddCommand(management.NewUsersCommand())
-	app.AddCommand(management.NewServiceAccountsCommand())
+	app.AddCommand(config.RequireFeature(api.FeatureServiceAccounts, management.NewServiceAccountsCommand()))
 
 	// Connection
-	app.AddCommand(connection.NewConnectionGroupCommand())
+	app.AddCommand(config.RequireFeature(api.FeatureConnections, connection.NewConnectionGroupCommand()))
 
 	// Connection Template
-	app.AddCommand(conntemplate.NewConnectionTemplateGroupCommand())
+	app.AddCommand(config.RequireFeature(api.FeatureConnectionTemplates, conntemplate.NewConnectionTemplateGroupCommand()))
 
 	// Add init container command for kubernetes
 	app.AddCommand(initcontainer.NewInitConCommand())
 
-	app.AddCommand(topicsettings.NewTopicSettingsCmd())
-	app.AddCommand(dataset.NewDatasetGroupCmd())
+	// Add healthcheck command for probes and smoke tests
+	app.AddCommand(healthcheck.NewHealthcheckCommand())
+
+	app.AddCommand(config.RequireFeature(api.FeatureTopicSettings, topicsettings.NewTopicSettingsCmd()))
+	app.AddCommand(config.RequireFeature(api.FeatureDatasets, dataset.NewDatasetGroupCmd()))
 	app.AddCommand(schemas.NewSchemasCmd())
+	app.AddCommand(config.RequireFeature(api.FeatureDatasets, search.NewSearchCommand()))
 
 	// Add provision command for dynamic config
 	app.AddCommand(provision.NewProvisionCommand())
 
+	// Batch
+	app.AddCommand(batch.NewRunCommand())
+
+	// Backup
+	app.AddCommand(backup.NewBackupGroupCommand())
+
+	// Lock
+	app.AddCommand(config.NewLockCommand())
+
+	// History
+	app.AddCommand(config.NewHistoryCommand())
+
+	// Pass-through
+	app.AddCommand(raw.NewAPICommand())
+	app.AddCommand(raw.NewWSCommand())
+
+	// Version
+	app.AddCommand(buildinfo.NewVersionCommand())
+
+	// Self-update
+	app.AddCommand(selfupdate.NewUpdateCommand())
+
+	// Plugins, the registered commands and the executables on the PATH.
+	app.AddCommand(plugin.NewPluginGroupCommand())
+	for _, cmd := range plugin.Registered() {
+		app.AddCommand(cmd)
+	}
+	plugin.AddCommands(bite.Build(app), os.Getenv("PATH"))
+
 	if err := app.Run(os.Stdout, os.Args[1:]); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		if config.Ansible() {
+			os.Exit(config.PrintAnsibleError(os.Stdout, err))
+		}
+		os.Exit(exitcode.Print(os.Stderr, config.Manager.ErrorFormat, err))
 	}
 }
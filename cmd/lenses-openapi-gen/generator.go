@@ -0,0 +1,443 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// initialisms are the words written in upper case in the Go names, i.e "ID" of "controllerId".
+var initialisms = map[string]bool{"api": true, "acl": true, "http": true, "id": true, "json": true, "sql": true, "url": true, "uri": true}
+
+// goName returns the exported Go name of the "name" of the spec, i.e "ControllerID" of "controllerId" or "GetGroup" of "get-group".
+func goName(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+	for _, word := range words {
+		// split the camel case words.
+		start := 0
+		for i, r := range word {
+			if i > 0 && unicode.IsUpper(r) && !unicode.IsUpper(rune(word[i-1])) {
+				b.WriteString(title(word[start:i]))
+				start = i
+			}
+		}
+		b.WriteString(title(word[start:]))
+	}
+
+	return b.String()
+}
+
+func title(word string) string {
+	if initialisms[strings.ToLower(word)] {
+		return strings.ToUpper(word)
+	}
+
+	return strings.ToUpper(word[:1]) + word[1:]
+}
+
+// goParam returns the name of the argument of a parameter, i.e "groupName" of "group-name".
+func goParam(name string) string {
+	n := goName(name)
+	i := 0
+	for i < len(n) && unicode.IsUpper(rune(n[i])) {
+		i++
+	}
+	// keep the upper case of the next word, i.e "urlPath" of "URLPath".
+	if i > 1 && i < len(n) {
+		i--
+	}
+	n = strings.ToLower(n[:i]) + n[i:]
+
+	switch n {
+	case "type", "func", "range", "default", "interface", "select", "map", "chan", "package", "path", "query", "payload", "resp", "err", "result", "body":
+		return n + "Param"
+	}
+
+	return n
+}
+
+// goType returns the Go type of the "s" schema.
+func goType(s *Schema) (string, error) {
+	if s == nil {
+		return "interface{}", nil
+	}
+
+	if s.Ref != "" {
+		name, err := refName(s.Ref)
+		if err != nil {
+			return "", err
+		}
+		return goName(name), nil
+	}
+
+	switch s.Type {
+	case "string":
+		return "string", nil
+	case "integer":
+		if s.Format == "int64" {
+			return "int64", nil
+		}
+		return "int", nil
+	case "number":
+		return "float64", nil
+	case "boolean":
+		return "bool", nil
+	case "array":
+		item, err := goType(s.Items)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + item, nil
+	case "object":
+		return "map[string]interface{}", nil
+	default:
+		return "interface{}", nil
+	}
+}
+
+// zeroCheck returns the condition of a non-zero value of the "typ" variable "name", it's how an optional query parameter is omitted.
+func zeroCheck(name, typ string) string {
+	switch typ {
+	case "string":
+		return name + ` != ""`
+	case "bool":
+		return name
+	case "int", "int64", "float64":
+		return name + " != 0"
+	default:
+		return name + " != nil"
+	}
+}
+
+// comment writes the "text" as a doc comment, a line of the comment per line of the text.
+func comment(b *bytes.Buffer, text string) {
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		fmt.Fprintf(b, "// %s\n", strings.TrimSpace(line))
+	}
+}
+
+type generator struct {
+	spec    *Spec
+	imports map[string]bool
+	b       bytes.Buffer
+}
+
+// Generate returns the Go source of the "pkg" package with the types of the component schemas
+// and the methods of the `Client` of the operations of the "spec".
+func Generate(spec *Spec, pkg string) ([]byte, error) {
+	g := &generator{spec: spec, imports: make(map[string]bool)}
+
+	if err := g.schemas(); err != nil {
+		return nil, err
+	}
+
+	if err := g.operations(); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	out.WriteString("// Code generated by lenses-openapi-gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\n", pkg)
+
+	if len(g.imports) > 0 {
+		imports := make([]string, 0, len(g.imports))
+		for imp := range g.imports {
+			imports = append(imports, imp)
+		}
+		sort.Strings(imports)
+
+		out.WriteString("import (\n")
+		for _, imp := range imports {
+			fmt.Fprintf(&out, "\t%q\n", imp)
+		}
+		out.WriteString(")\n\n")
+	}
+
+	out.Write(g.b.Bytes())
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("unable to format the generated code: [%v]", err)
+	}
+
+	return src, nil
+}
+
+func (g *generator) schemas() error {
+	names := make([]string, 0, len(g.spec.Components.Schemas))
+	for name := range g.spec.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := g.schema(name, g.spec.Components.Schemas[name]); err != nil {
+			return fmt.Errorf("schema [%s]: [%v]", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (g *generator) schema(name string, s *Schema) error {
+	typeName := goName(name)
+	if s.Description != "" {
+		comment(&g.b, typeName+" "+lowerFirst(s.Description))
+	} else {
+		fmt.Fprintf(&g.b, "// %s is the %s schema of the API.\n", typeName, name)
+	}
+
+	if s.Type != "object" || len(s.Properties) == 0 {
+		typ, err := goType(s)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&g.b, "type %s %s\n\n", typeName, typ)
+		return nil
+	}
+
+	required := make(map[string]bool)
+	for _, r := range s.Required {
+		required[r] = true
+	}
+
+	props := make([]string, 0, len(s.Properties))
+	for prop := range s.Properties {
+		props = append(props, prop)
+	}
+	sort.Strings(props)
+
+	fmt.Fprintf(&g.b, "type %s struct {\n", typeName)
+	for _, prop := range props {
+		p := s.Properties[prop]
+		typ, err := goType(p)
+		if err != nil {
+			return fmt.Errorf("property [%s]: [%v]", prop, err)
+		}
+
+		if p.Description != "" {
+			comment(&g.b, p.Description)
+		}
+
+		tag := prop
+		if !required[prop] {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(&g.b, "\t%s %s `json:\"%s\" yaml:\"%s\"`\n", goName(prop), typ, tag, tag)
+	}
+	g.b.WriteString("}\n\n")
+
+	return nil
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// methods are the HTTP methods of the operations of a path, in the order of their generated methods.
+var methods = []string{"Get", "Post", "Put", "Patch", "Delete"}
+
+func (g *generator) operations() error {
+	paths := make([]string, 0, len(g.spec.Paths))
+	for path := range g.spec.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		item := g.spec.Paths[path]
+		ops := map[string]*Operation{"Get": item.Get, "Post": item.Post, "Put": item.Put, "Patch": item.Patch, "Delete": item.Delete}
+		for _, method := range methods {
+			op := ops[method]
+			if op == nil {
+				continue
+			}
+
+			if err := g.operation(path, method, item.Parameters, op); err != nil {
+				return fmt.Errorf("operation [%s %s]: [%v]", strings.ToUpper(method), path, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+type param struct {
+	Parameter
+	arg, typ string
+}
+
+func (g *generator) operation(path, method string, shared []Parameter, op *Operation) error {
+	if op.OperationID == "" {
+		return fmt.Errorf("missing operationId")
+	}
+
+	var pathParams, queryParams []param
+	for _, p := range append(append([]Parameter(nil), shared...), op.Parameters...) {
+		typ, err := goType(p.Schema)
+		if err != nil {
+			return fmt.Errorf("parameter [%s]: [%v]", p.Name, err)
+		}
+
+		pp := param{Parameter: p, arg: goParam(p.Name), typ: typ}
+		switch p.In {
+		case "path":
+			pathParams = append(pathParams, pp)
+		case "query":
+			queryParams = append(queryParams, pp)
+		}
+	}
+
+	var args []string
+	for _, p := range append(append([]param(nil), pathParams...), queryParams...) {
+		args = append(args, p.arg+" "+p.typ)
+	}
+
+	var bodyType string
+	if op.RequestBody != nil {
+		typ, err := goType(jsonSchema(op.RequestBody.Content))
+		if err != nil {
+			return fmt.Errorf("request body: [%v]", err)
+		}
+		bodyType = typ
+		args = append(args, "body "+typ)
+	}
+
+	resultType, err := g.resultType(op)
+	if err != nil {
+		return err
+	}
+
+	name := goName(op.OperationID)
+	doc := op.Summary
+	if doc == "" {
+		doc = op.Description
+	}
+	if doc != "" {
+		comment(&g.b, name+" "+lowerFirst(doc))
+	} else {
+		fmt.Fprintf(&g.b, "// %s sends the %s %s request.\n", name, strings.ToUpper(method), path)
+	}
+	if op.Deprecated {
+		g.b.WriteString("//\n// Deprecated: the endpoint is deprecated by the server.\n")
+	}
+
+	results := "error"
+	if resultType != "" {
+		results = "(result " + resultType + ", err error)"
+	}
+	fmt.Fprintf(&g.b, "func (c *Client) %s(%s) %s {\n", name, strings.Join(args, ", "), results)
+
+	g.path(strings.TrimPrefix(path, "/"), pathParams)
+
+	if len(queryParams) > 0 {
+		g.imports["net/url"] = true
+		g.b.WriteString("\tquery := url.Values{}\n")
+		for _, p := range queryParams {
+			value := p.arg
+			if p.typ != "string" {
+				g.imports["fmt"] = true
+				value = "fmt.Sprint(" + p.arg + ")"
+			}
+			fmt.Fprintf(&g.b, "\tif %s {\n\t\tquery.Set(%q, %s)\n\t}\n", zeroCheck(p.arg, p.typ), p.Name, value)
+		}
+		g.b.WriteString("\tif len(query) > 0 {\n\t\tpath += \"?\" + query.Encode()\n\t}\n\n")
+	}
+
+	returnErr := "return err"
+	if resultType != "" {
+		returnErr = "return"
+	}
+
+	payload, contentType := "nil", `"application/json"`
+	if bodyType != "" {
+		g.imports["encoding/json"] = true
+		payload = "payload"
+		fmt.Fprintf(&g.b, "\tpayload, err := json.Marshal(body)\n\tif err != nil {\n\t\t%s\n\t}\n\n", returnErr)
+	}
+
+	g.imports["net/http"] = true
+	fmt.Fprintf(&g.b, "\tresp, err := c.Do(http.Method%s, path, %s, %s)\n\tif err != nil {\n\t\t%s\n\t}\n\n", method, contentType, payload, returnErr)
+
+	if resultType != "" {
+		g.b.WriteString("\terr = c.ReadJSON(resp, &result)\n\treturn\n}\n\n")
+	} else {
+		g.b.WriteString("\treturn resp.Body.Close()\n}\n\n")
+	}
+
+	return nil
+}
+
+// resultType returns the Go type of the JSON body of the success response of the "op", empty if it has none.
+func (g *generator) resultType(op *Operation) (string, error) {
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		if s := jsonSchema(op.Responses[code].Content); s != nil {
+			typ, err := goType(s)
+			if err != nil {
+				return "", fmt.Errorf("response [%s]: [%v]", code, err)
+			}
+			return typ, nil
+		}
+	}
+
+	return "", nil
+}
+
+// path writes the statement of the "path" variable, the "p" with its parameters, i.e "api/v1/group/{name}", escaped.
+func (g *generator) path(p string, params []param) {
+	if len(params) == 0 {
+		fmt.Fprintf(&g.b, "\tpath := %q\n\n", p)
+		return
+	}
+
+	g.imports["net/url"] = true
+
+	byName := make(map[string]param, len(params))
+	for _, pp := range params {
+		byName[pp.Name] = pp
+	}
+
+	var parts []string
+	for p != "" {
+		start := strings.Index(p, "{")
+		end := strings.Index(p, "}")
+		if start < 0 || end < start {
+			parts = append(parts, fmt.Sprintf("%q", p))
+			break
+		}
+
+		if start > 0 {
+			parts = append(parts, fmt.Sprintf("%q", p[:start]))
+		}
+
+		name := p[start+1 : end]
+		arg := goParam(name)
+		if pp, ok := byName[name]; ok && pp.typ != "string" {
+			g.imports["fmt"] = true
+			arg = "fmt.Sprint(" + pp.arg + ")"
+		}
+		parts = append(parts, "url.PathEscape("+arg+")")
+		p = p[end+1:]
+	}
+
+	fmt.Fprintf(&g.b, "\tpath := %s\n\n", strings.Join(parts, " + "))
+}
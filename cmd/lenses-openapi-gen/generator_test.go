@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoName(t *testing.T) {
+	assert.Equal(t, "ControllerID", goName("controllerId"))
+	assert.Equal(t, "GetGroup", goName("get-group"))
+	assert.Equal(t, "GetSQLQueries", goName("getSqlQueries"))
+	assert.Equal(t, "Name", goName("name"))
+
+	assert.Equal(t, "groupName", goParam("group-name"))
+	assert.Equal(t, "id", goParam("id"))
+	assert.Equal(t, "urlPath", goParam("URLPath"))
+	assert.Equal(t, "typeParam", goParam("type"))
+}
+
+const testSpec = `{
+  "paths": {
+    "/api/v1/topics/{topic}/partitions/{partition}": {
+      "get": {
+        "operationId": "getPartitionMessages",
+        "summary": "Returns the messages of a partition.",
+        "parameters": [
+          {"name": "topic", "in": "path", "required": true, "schema": {"type": "string"}},
+          {"name": "partition", "in": "path", "required": true, "schema": {"type": "integer"}},
+          {"name": "limit", "in": "query", "schema": {"type": "integer"}},
+          {"name": "type", "in": "query", "schema": {"type": "string"}}
+        ],
+        "responses": {
+          "200": {
+            "description": "The messages.",
+            "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Message"}}}}
+          }
+        }
+      },
+      "delete": {
+        "operationId": "deletePartitionMessages",
+        "deprecated": true,
+        "parameters": [
+          {"name": "topic", "in": "path", "required": true, "schema": {"type": "string"}},
+          {"name": "partition", "in": "path", "required": true, "schema": {"type": "integer"}}
+        ],
+        "responses": {"204": {"description": "Deleted."}}
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "Message": {
+        "type": "object",
+        "required": ["offset"],
+        "properties": {
+          "offset": {"type": "integer", "format": "int64", "description": "The offset of the message."},
+          "value": {"type": "object"},
+          "headers": {"type": "array", "items": {"type": "string"}}
+        }
+      }
+    }
+  }
+}`
+
+func TestGenerate(t *testing.T) {
+	spec := new(Spec)
+	require.NoError(t, json.Unmarshal([]byte(testSpec), spec))
+
+	src, err := Generate(spec, "gen")
+	require.NoError(t, err)
+
+	code := string(src)
+	for _, expected := range []string{
+		"// Code generated by lenses-openapi-gen. DO NOT EDIT.",
+		"package gen",
+		"// Message is the Message schema of the API.",
+		"	Headers []string `json:\"headers,omitempty\" yaml:\"headers,omitempty\"`",
+		"	// The offset of the message.\n	Offset int64                  `json:\"offset\" yaml:\"offset\"`",
+		"	Value  map[string]interface{} `json:\"value,omitempty\" yaml:\"value,omitempty\"`",
+		"// GetPartitionMessages returns the messages of a partition.",
+		"func (c *Client) GetPartitionMessages(topic string, partition int, limit int, typeParam string) (result []Message, err error) {",
+		`	path := "api/v1/topics/" + url.PathEscape(topic) + "/partitions/" + url.PathEscape(fmt.Sprint(partition))`,
+		"	if limit != 0 {\n		query.Set(\"limit\", fmt.Sprint(limit))\n	}",
+		"	if typeParam != \"\" {\n		query.Set(\"type\", typeParam)\n	}",
+		"// Deprecated: the endpoint is deprecated by the server.",
+		"func (c *Client) DeletePartitionMessages(topic string, partition int) error {",
+		"	resp, err := c.Do(http.MethodDelete, path, \"application/json\", nil)",
+	} {
+		assert.Contains(t, code, expected)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	spec := new(Spec)
+	spec.Paths = map[string]PathItem{"/api/v1/group": {Get: &Operation{}}}
+
+	_, err := Generate(spec, "gen")
+	require.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "missing operationId"), err.Error())
+
+	spec = new(Spec)
+	spec.Components.Schemas = map[string]*Schema{"Group": {Type: "array", Items: &Schema{Ref: "definitions.yml#/Group"}}}
+
+	_, err = Generate(spec, "gen")
+	require.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "unsupported reference"), err.Error())
+}
+
+// TestGenerated checks that the generated code of the gen package is up to date with its OpenAPI definition.
+func TestGenerated(t *testing.T) {
+	spec, err := ReadSpec("../../pkg/api/gen/openapi.json")
+	require.NoError(t, err)
+
+	src, err := Generate(spec, "gen")
+	require.NoError(t, err)
+
+	generated, err := ioutil.ReadFile("../../pkg/api/gen/zz_generated.go")
+	require.NoError(t, err)
+
+	assert.Equal(t, string(src), string(generated), "run go generate ./pkg/api/gen")
+}
@@ -0,0 +1,39 @@
+// Command lenses-openapi-gen generates the typed client of the `gen` package, the structs of the component schemas
+// and the methods of the operations of the Lenses OpenAPI 3 definition, see its go:generate directive.
+//
+// Usage:
+//
+//	lenses-openapi-gen -spec openapi.json -package gen -out zz_generated.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+func main() {
+	var specFile, pkg, out string
+	flag.StringVar(&specFile, "spec", "openapi.json", "The OpenAPI 3 definition in JSON")
+	flag.StringVar(&pkg, "package", "gen", "The package name of the generated code")
+	flag.StringVar(&out, "out", "zz_generated.go", "The file of the generated code")
+	flag.Parse()
+
+	spec, err := ReadSpec(specFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	src, err := Generate(spec, pkg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to generate the code of [%s]: [%v]\n", specFile, err)
+		os.Exit(1)
+	}
+
+	if err = ioutil.WriteFile(out, src, 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
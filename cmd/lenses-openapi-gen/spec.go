@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// Spec is the part of an OpenAPI 3 definition that the generator reads, the paths and the component schemas.
+type Spec struct {
+	Paths      map[string]PathItem `json:"paths"`
+	Components struct {
+		Schemas map[string]*Schema `json:"schemas"`
+	} `json:"components"`
+}
+
+// PathItem describes the operations of a path.
+type PathItem struct {
+	Parameters []Parameter `json:"parameters"`
+	Get        *Operation  `json:"get"`
+	Post       *Operation  `json:"post"`
+	Put        *Operation  `json:"put"`
+	Patch      *Operation  `json:"patch"`
+	Delete     *Operation  `json:"delete"`
+}
+
+// Operation describes an operation of a path, a generated method of the client.
+type Operation struct {
+	OperationID string              `json:"operationId"`
+	Summary     string              `json:"summary"`
+	Description string              `json:"description"`
+	Deprecated  bool                `json:"deprecated"`
+	Parameters  []Parameter         `json:"parameters"`
+	RequestBody *Body               `json:"requestBody"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+// Parameter is a path or a query parameter of an operation.
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+// Body is the request body of an operation.
+type Body struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response is a response of an operation.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+// MediaType is the schema of a body of a content type.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema is a JSON schema of the spec, the generator supports the types of the JSON documents of the Lenses API.
+type Schema struct {
+	Ref         string             `json:"$ref"`
+	Type        string             `json:"type"`
+	Format      string             `json:"format"`
+	Description string             `json:"description"`
+	Properties  map[string]*Schema `json:"properties"`
+	Required    []string           `json:"required"`
+	Items       *Schema            `json:"items"`
+}
+
+// ReadSpec reads the OpenAPI 3 definition in JSON of the "filename".
+func ReadSpec(filename string) (*Spec, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := new(Spec)
+	if err = json.Unmarshal(b, spec); err != nil {
+		return nil, fmt.Errorf("invalid spec [%s]: [%v]", filename, err)
+	}
+
+	return spec, nil
+}
+
+const schemaRefPrefix = "#/components/schemas/"
+
+// refName returns the name of the component schema of the "ref", i.e "Group" of "#/components/schemas/Group".
+func refName(ref string) (string, error) {
+	if !strings.HasPrefix(ref, schemaRefPrefix) {
+		return "", fmt.Errorf("unsupported reference [%s], expected [%s<name>]", ref, schemaRefPrefix)
+	}
+
+	return strings.TrimPrefix(ref, schemaRefPrefix), nil
+}
+
+// jsonSchema returns the schema of the "application/json" content, if any.
+func jsonSchema(content map[string]MediaType) *Schema {
+	if m, ok := content["application/json"]; ok {
+		return m.Schema
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+// Package experimental is the client of the pre-release endpoints of the Lenses API, on top of the stable `v1.Client`.
+// Its methods may change or be removed in a minor version, when an endpoint is stable it's moved to the `v1.Client`
+// and the method here is kept as a deprecated shim for a release.
+package experimental
+
+import (
+	"github.com/lensesio/lenses-go/pkg/api"
+	v1 "github.com/lensesio/lenses-go/v1"
+)
+
+// Client is the stable `v1.Client` plus the pre-release endpoints, as implemented by the `api.Client`.
+type Client interface {
+	v1.Client
+
+	// schema versions with references
+	GetSchemaVersion(name string, version int) (schema api.SchemaVersion, err error)
+	GetSchemaVersions(name string) (versions []int, err error)
+	RegisterSchemaVersion(name string, schema api.SchemaVersion) (id int, err error)
+
+	// catalog search and topic annotations
+	AnnotateTopic(topicName string, annotation api.TopicAnnotation) (api.Topic, error)
+	Search(term string, opts api.SearchOptions) ([]api.SearchResult, error)
+
+	// config patches, JSON merge patches of the current configs
+	PatchClientsQuota(clientID string, patch api.KV) (api.QuotaConfig, error)
+	PatchConnectorConfig(clusterName, name string, patch api.ConnectorConfig) (api.ConnectorConfig, error)
+	PatchTopicConfig(topicName string, patch api.KV) (api.KV, error)
+	PatchUsersQuota(user, clientID string, patch api.KV) (api.QuotaConfig, error)
+
+	// server, users, connectors and SQL
+	ExplainSQL(sql string) (api.SQLExecutionPlan, error)
+	GetCurrentUser() (user api.CurrentUser, err error)
+	ServerInfo() (api.ServerInfo, error)
+	ValidateConnectorConfig(clusterName, connectorClass string, config api.ConnectorConfig) (v api.ConnectorConfigValidation, err error)
+}
+
+var _ Client = (*api.Client)(nil)
+
+// OpenConnection authenticates to the server of the "cfg" and returns its client, see `api.OpenConnection`.
+func OpenConnection(cfg api.ClientConfig, options ...api.ConnectionOption) (Client, error) {
+	client, err := api.OpenConnection(cfg, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
@@ -0,0 +1,140 @@
+package lenses
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	"github.com/lensesio/lenses-go/pkg/websocket"
+)
+
+// Option is a setting of the client of `NewClient` and of the live connection of `OpenLiveConnection`,
+// a new setting is a new option, so it's added without breaking the callers.
+type Option func(*settings)
+
+// settings are the values of the options, shared by the REST client and the live connection.
+type settings struct {
+	timeout   time.Duration
+	transport http.RoundTripper
+	userAgent string
+	basePath  string
+	token     string
+	insecure  bool
+}
+
+func newSettings(opts []Option) *settings {
+	s := new(settings)
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// WithTimeout sets the timeout of the connection establishment of the client and of the handshake of the live connection.
+func WithTimeout(timeout time.Duration) Option {
+	return func(s *settings) {
+		s.timeout = timeout
+	}
+}
+
+// WithTransport sets the HTTP transport of the client, i.e one with a proxy or custom TLS settings,
+// the TLS settings of an `*http.Transport` are used by the live connection too.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(s *settings) {
+		s.transport = transport
+	}
+}
+
+// WithUserAgent sets the User-Agent header of the requests of the client and of the handshake of the live connection,
+// i.e the name and version of the application.
+func WithUserAgent(userAgent string) Option {
+	return func(s *settings) {
+		s.userAgent = userAgent
+	}
+}
+
+// WithBasePath sets the path prefix of a Lenses behind a sub-path proxy, i.e "/lenses".
+func WithBasePath(basePath string) Option {
+	return func(s *settings) {
+		s.basePath = basePath
+	}
+}
+
+// WithToken sets the token of a previous session, instead of authenticating, and the token of the live query message.
+func WithToken(token string) Option {
+	return func(s *settings) {
+		s.token = token
+	}
+}
+
+// WithInsecure accepts an invalid certificate of the server, it does not change the TLS settings of a `WithTransport`.
+func WithInsecure() Option {
+	return func(s *settings) {
+		s.insecure = true
+	}
+}
+
+// NewClient authenticates to the Lenses of the "host", i.e "https://lenses.example.com", by the "auth"
+// and returns its client, the "opts" set the rest of its settings.
+//
+// Usage:
+// auth := api.BasicAuthentication{Username: "user", Password: "pass"}
+// client, err := lenses.NewClient("https://lenses.example.com", auth, lenses.WithTimeout(15*time.Second), lenses.WithUserAgent("my-app/1.0"))
+func NewClient(host string, auth api.Authentication, opts ...Option) (*api.Client, error) {
+	s := newSettings(opts)
+
+	config := api.ClientConfig{
+		Host:           host,
+		BasePath:       s.basePath,
+		Authentication: auth,
+		Token:          s.token,
+		Insecure:       s.insecure,
+	}
+	if s.timeout > 0 {
+		config.Timeout = s.timeout.String()
+	}
+
+	var options []api.ConnectionOption
+	if s.transport != nil {
+		options = append(options, api.UsingClient(&http.Client{Transport: s.transport}))
+	}
+	if s.userAgent != "" {
+		options = append(options, api.UsingUserAgent(s.userAgent))
+	}
+
+	return api.OpenConnection(config, options...)
+}
+
+// liveConfiguration returns the configuration of a live connection to the "host" which sends the "message".
+func (s *settings) liveConfiguration(host string, message websocket.Message) websocket.LiveConfiguration {
+	if message.Token == "" {
+		message.Token = s.token
+	}
+
+	config := websocket.LiveConfiguration{
+		Host:             host,
+		BasePath:         s.basePath,
+		Message:          message,
+		HandshakeTimeout: s.timeout,
+	}
+
+	if t, ok := s.transport.(*http.Transport); ok && t.TLSClientConfig != nil {
+		config.TLSClientConfig = t.TLSClientConfig.Clone()
+	} else if s.insecure {
+		config.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	if s.userAgent != "" {
+		config.Header = http.Header{"User-Agent": []string{s.userAgent}}
+	}
+
+	return config
+}
+
+// OpenLiveConnection opens a live connection to the Lenses of the "host" which runs the query of the "message",
+// with the same options as the `NewClient`.
+func OpenLiveConnection(host string, message websocket.Message, opts ...Option) (*websocket.LiveConnection, error) {
+	return websocket.OpenLiveConnection(newSettings(opts).liveConfiguration(host, message))
+}
@@ -0,0 +1,69 @@
+package lenses
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lensesio/lenses-go/pkg/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingTransport counts the requests it sends.
+type countingTransport struct {
+	requests int
+}
+
+func (t *countingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	t.requests++
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestNewClient(t *testing.T) {
+	var userAgent, path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent, path = r.UserAgent(), r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"controller":1,"brokers":[{"id":1,"host":"kafka","port":9092}]}`))
+	}))
+	defer server.Close()
+
+	transport := new(countingTransport)
+	client, err := NewClient(server.URL, nil,
+		WithToken("token"), WithTimeout(5*time.Second), WithTransport(transport), WithUserAgent("my-app/1.0"), WithBasePath("/lenses"))
+	require.NoError(t, err)
+	assert.Equal(t, "5s", client.Config.Timeout)
+
+	cluster, err := client.GetKafkaCluster()
+	require.NoError(t, err)
+	assert.Equal(t, 1, cluster.ControllerID)
+
+	assert.Equal(t, "my-app/1.0", userAgent)
+	assert.Equal(t, "/lenses/api/v1/kafka/cluster", path)
+	assert.Equal(t, 1, transport.requests)
+
+	_, err = NewClient(server.URL, nil)
+	assert.Error(t, err, "no token and no authentication")
+}
+
+func TestLiveConfiguration(t *testing.T) {
+	tlsConfig := &tls.Config{ServerName: "lenses"}
+	s := newSettings([]Option{WithToken("token"), WithTimeout(time.Second), WithUserAgent("my-app/1.0"),
+		WithTransport(&http.Transport{TLSClientConfig: tlsConfig})})
+
+	config := s.liveConfiguration("https://lenses.example.com", websocket.Message{SQL: "SELECT * FROM payments"})
+	assert.Equal(t, "token", config.Message.Token)
+	assert.Equal(t, "SELECT * FROM payments", config.Message.SQL)
+	assert.Equal(t, time.Second, config.HandshakeTimeout)
+	assert.Equal(t, "my-app/1.0", config.Header.Get("User-Agent"))
+	require.NotNil(t, config.TLSClientConfig)
+	assert.Equal(t, "lenses", config.TLSClientConfig.ServerName)
+
+	config = newSettings([]Option{WithInsecure()}).liveConfiguration("https://lenses.example.com", websocket.Message{Token: "message token"})
+	assert.Equal(t, "message token", config.Message.Token)
+	assert.True(t, config.TLSClientConfig.InsecureSkipVerify)
+	assert.Nil(t, config.Header)
+}
@@ -3,7 +3,7 @@ package acl
 import (
 	"errors"
 	"fmt"
-	"io/ioutil"
+	"os"
 	"regexp"
 	"sort"
 
@@ -11,9 +11,11 @@ import (
 	"github.com/lensesio/bite"
 	"github.com/lensesio/lenses-go/pkg/api"
 	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/manifest"
+	"github.com/lensesio/lenses-go/pkg/printer"
+	"github.com/lensesio/lenses-go/pkg/provider"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
-	"gopkg.in/yaml.v2"
 )
 
 var aclFlags *pflag.FlagSet
@@ -38,11 +40,14 @@ func NewGetACLsCommand() *cobra.Command {
 				return acls[i].ResourceName < acls[j].ResourceName
 			})
 
-			return bite.PrintObject(cmd, acls)
+			return printer.PrintObject(cmd, acls)
 		},
 	}
 
 	bite.CanPrintJSON(cmd)
+	printer.CanSelect(cmd)
+
+	cmd.AddCommand(config.WithLock(NewACLsSyncCommand()))
 
 	return cmd
 }
@@ -93,12 +98,26 @@ func NewCreateOrUpdateACLCommand() *cobra.Command {
 				return err
 			}
 
+			if err := config.Preflight("set ACL", config.PermissionManageKafkaSettings); err != nil {
+				return err
+			}
+
+			if config.Ansible() {
+				exists, err := aclExists(acl)
+				if err != nil {
+					return err
+				}
+
+				if exists {
+					return config.ReportChange(cmd, false, fmt.Sprintf("ACL \"%s\" exists", acl), nil, nil)
+				}
+			}
+
 			if err := config.Client.CreateOrUpdateACL(acl); err != nil {
 				return err
 			}
-			fmt.Fprintf(cmd.OutOrStdout(), "ACL \"%s\" was created/updated successfuly\n", acl)
 
-			return nil
+			return config.ReportChange(cmd, true, fmt.Sprintf("ACL \"%s\" was created/updated successfuly", acl), nil, acl)
 		},
 	}
 
@@ -120,17 +139,47 @@ func NewDeleteACLCommand() *cobra.Command {
 				return err
 			}
 
+			if err := config.Preflight("delete ACL", config.PermissionManageKafkaSettings); err != nil {
+				return err
+			}
+
+			if config.Ansible() {
+				exists, err := aclExists(acl)
+				if err != nil {
+					return err
+				}
+
+				if !exists {
+					return config.ReportChange(cmd, false, fmt.Sprintf("ACL \"%s\" does not exist", acl), nil, nil)
+				}
+			}
+
+			if err := config.Confirm("ACL resource", acl.ResourceName); err != nil {
+				return err
+			}
+
 			if err := config.Client.DeleteACL(acl); err != nil {
 				return fmt.Errorf("failed to delete ACL '%s'. [%s]", acl, err.Error())
 			}
 
-			fmt.Fprintf(cmd.OutOrStdout(), "ACL \"%s\" was deleted successfully\n", acl)
-			return nil
+			return config.ReportChange(cmd, true, fmt.Sprintf("ACL \"%s\" was deleted successfully", acl), acl, nil)
 		},
 	}
 	return cmd
 }
 
+// aclExists reports whether the "acl" is set, the server has all of its fields.
+func aclExists(acl api.ACL) (bool, error) {
+	if _, err := provider.ReadACL(config.Client, provider.ACLID(acl)); err != nil {
+		if errors.Is(err, provider.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
 // populateACL function will try populate an ACL struct either from a yaml file or from cli flags
 func populateACL(cmd *cobra.Command, args []string) (api.ACL, error) {
 	var acl api.ACL
@@ -162,14 +211,13 @@ func populateACL(cmd *cobra.Command, args []string) (api.ACL, error) {
 		}
 
 		fmt.Fprintf(cmd.OutOrStdout(), "loading values from YAML file '%s'...\n", args[0])
-		yamlFile, err := ioutil.ReadFile(args[0])
-		if err != nil {
+		if _, err := os.Stat(args[0]); err != nil {
 			return api.ACL{}, err
 		}
-		if err := yaml.Unmarshal(yamlFile, &acl); err != nil {
-			return api.ACL{}, err
+		if err := config.ReadManifest(args[0], manifest.KindACL, &acl); err != nil {
+			return acl, err
 		}
-		if err = isACLPopulated(acl); err != nil {
+		if err := isACLPopulated(acl); err != nil {
 			return acl, err
 		}
 		return acl, nil
@@ -0,0 +1,220 @@
+package acl
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/kataras/golog"
+	"github.com/lensesio/bite"
+	"github.com/lensesio/lenses-go/pkg/api"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/spf13/cobra"
+)
+
+const (
+	aclSyncCreate = "create"
+	aclSyncDelete = "delete"
+)
+
+// aclChange is an ACL that `acls sync` creates or deletes on the target context.
+type aclChange struct {
+	Action         string                `json:"action" yaml:"action" header:"Action"`
+	PermissionType api.ACLPermissionType `json:"permissionType" yaml:"permissionType" header:"Permission"`
+	Principal      string                `json:"principal" yaml:"principal" header:"Principal"`
+	Operation      api.ACLOperation      `json:"operation" yaml:"operation" header:"Operation"`
+	ResourceType   api.ACLResourceType   `json:"resourceType" yaml:"resourceType" header:"Resource Type"`
+	PatternType    string                `json:"patternType" yaml:"patternType" header:"Pattern type"`
+	ResourceName   string                `json:"resourceName" yaml:"resourceName" header:"Name"`
+	Host           string                `json:"host" yaml:"host" header:"Host"`
+}
+
+func newACLChange(action string, acl api.ACL) aclChange {
+	return aclChange{action, acl.PermissionType, acl.Principal, acl.Operation, acl.ResourceType, acl.PatternType, acl.ResourceName, acl.Host}
+}
+
+// ACL returns the ACL of the change.
+func (c aclChange) ACL() api.ACL {
+	return api.ACL{PermissionType: c.PermissionType, Principal: c.Principal, Operation: c.Operation,
+		ResourceType: c.ResourceType, PatternType: c.PatternType, ResourceName: c.ResourceName, Host: c.Host}
+}
+
+// aclKey returns the identity of the "acl", the server may return its enum fields in a different case than they were set.
+func aclKey(acl api.ACL) string {
+	return strings.Join([]string{
+		strings.ToUpper(string(acl.PermissionType)),
+		acl.Principal,
+		strings.ToUpper(string(acl.Operation)),
+		strings.ToUpper(string(acl.ResourceType)),
+		strings.ToUpper(acl.PatternType),
+		acl.ResourceName,
+		acl.Host,
+	}, "\x00")
+}
+
+// excludedPrincipal reports whether the "principal" matches any of the "exclude" patterns, i.e "User:admin" or "User:svc-*".
+func excludedPrincipal(principal string, exclude []string) bool {
+	for _, pattern := range exclude {
+		if matched, err := path.Match(pattern, principal); pattern == principal || (err == nil && matched) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// diffACLs returns the changes which make the "target" ACLs equal to the "source" ones, the ACLs of the "exclude"d principals
+// are left as they are. Creations come first, both sorted by principal and resource.
+func diffACLs(source, target []api.ACL, exclude []string) (changes []aclChange) {
+	sourceKeys := make(map[string]bool, len(source))
+	for _, acl := range source {
+		sourceKeys[aclKey(acl)] = true
+	}
+
+	targetKeys := make(map[string]bool, len(target))
+	for _, acl := range target {
+		targetKeys[aclKey(acl)] = true
+	}
+
+	var creates, deletes []aclChange
+	for _, acl := range source {
+		key := aclKey(acl)
+		if targetKeys[key] || excludedPrincipal(acl.Principal, exclude) {
+			continue
+		}
+		// duplicates are created once.
+		targetKeys[key] = true
+		creates = append(creates, newACLChange(aclSyncCreate, acl))
+	}
+
+	for _, acl := range target {
+		key := aclKey(acl)
+		if sourceKeys[key] || excludedPrincipal(acl.Principal, exclude) {
+			continue
+		}
+		sourceKeys[key] = true
+		deletes = append(deletes, newACLChange(aclSyncDelete, acl))
+	}
+
+	for _, c := range [][]aclChange{creates, deletes} {
+		sort.Slice(c, func(i, j int) bool {
+			if c[i].Principal != c[j].Principal {
+				return c[i].Principal < c[j].Principal
+			}
+			if c[i].ResourceType != c[j].ResourceType {
+				return c[i].ResourceType < c[j].ResourceType
+			}
+			return c[i].ResourceName < c[j].ResourceName
+		})
+	}
+
+	return append(creates, deletes...)
+}
+
+// NewACLsSyncCommand creates the `acls sync` command
+func NewACLsSyncCommand() *cobra.Command {
+	var (
+		from, to string
+		exclude  []string
+		dryRun   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Make the Kafka ACLs of a context equal to the ones of another context",
+		Long: `Compare the Kafka ACLs of two contexts of the configuration file and create the ones of the --from context that are missing from the --to context
+and delete the ones of the --to context that the --from context does not have. The ACLs of the --exclude principals, i.e the brokers or the environment's
+admins, are neither created nor deleted. --dry-run prints the changes without applying them.`,
+		Example: `acls sync --from staging --to prod --dry-run
+acls sync --from staging --to prod --exclude User:admin --exclude "User:svc-prod-*"`,
+		// it writes to the --to context, which is checked by `config.CheckProtectedContext` and locked by `config.WithLock`.
+		Annotations:      map[string]string{config.AnnotationMutating: "true", config.AnnotationLockContext: "to"},
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := bite.CheckRequiredFlags(cmd, bite.FlagPair{"from": from, "to": to}); err != nil {
+				return err
+			}
+
+			if from == to {
+				return fmt.Errorf("the --from and --to contexts are the same [%s]", from)
+			}
+
+			if !dryRun {
+				if err := config.CheckProtectedContext(to, cmd); err != nil {
+					return err
+				}
+			}
+
+			source, err := config.Manager.ContextClient(from)
+			if err != nil {
+				return err
+			}
+
+			target, err := config.Manager.ContextClient(to)
+			if err != nil {
+				return err
+			}
+
+			sourceACLs, err := source.GetACLs()
+			if err != nil {
+				golog.Errorf("Failed to retrieve the acls of context [%s]. [%s]", from, err.Error())
+				return err
+			}
+
+			targetACLs, err := target.GetACLs()
+			if err != nil {
+				golog.Errorf("Failed to retrieve the acls of context [%s]. [%s]", to, err.Error())
+				return err
+			}
+
+			changes := diffACLs(sourceACLs, targetACLs, exclude)
+			if len(changes) == 0 {
+				return bite.PrintInfo(cmd, "The ACLs of context [%s] are in sync with context [%s]", to, from)
+			}
+
+			if err = bite.PrintObject(cmd, changes); err != nil || dryRun {
+				return err
+			}
+
+			for _, c := range changes {
+				if c.Action == aclSyncDelete {
+					if err = config.Confirm("ACLs of context", to); err != nil {
+						return err
+					}
+					break
+				}
+			}
+
+			var failed int
+			for _, c := range changes {
+				if c.Action == aclSyncCreate {
+					err = target.CreateOrUpdateACL(c.ACL())
+				} else {
+					err = target.DeleteACL(c.ACL())
+				}
+
+				if err != nil {
+					failed++
+					golog.Errorf("Failed to %s ACL [%s] of context [%s]. [%s]", c.Action, c.ACL(), to, err.Error())
+				}
+			}
+
+			if failed > 0 {
+				return fmt.Errorf("[%d] of [%d] ACL changes failed", failed, len(changes))
+			}
+
+			return bite.PrintInfo(cmd, "[%d] ACL changes applied to context [%s]", len(changes), to)
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "The context of the configuration file whose ACLs are the desired ones")
+	cmd.Flags().StringVar(&to, "to", "", "The context of the configuration file whose ACLs are changed")
+	cmd.Flags().StringArrayVar(&exclude, "exclude", nil, "A principal, or a pattern of principals i.e 'User:svc-*', whose ACLs are left as they are, repeatable")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the changes without applying them")
+
+	bite.CanPrintJSON(cmd)
+
+	return cmd
+}
@@ -0,0 +1,34 @@
+package acl
+
+import (
+	"testing"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffACLs(t *testing.T) {
+	acl := func(principal, resource string) api.ACL {
+		return api.ACL{PermissionType: "Allow", Principal: principal, Operation: "Read",
+			ResourceType: "Topic", PatternType: "literal", ResourceName: resource, Host: "*"}
+	}
+
+	source := []api.ACL{acl("User:bob", "payments"), acl("User:alice", "orders"), acl("User:admin", "orders")}
+	// the server returns the enums upper-cased.
+	unchanged := acl("User:bob", "payments")
+	unchanged.PermissionType, unchanged.Operation, unchanged.ResourceType, unchanged.PatternType = "ALLOW", "READ", "TOPIC", "LITERAL"
+	target := []api.ACL{unchanged, acl("User:carol", "payments"), acl("User:svc-prod-1", "payments")}
+
+	changes := diffACLs(source, target, []string{"User:svc-prod-*"})
+	assert.Equal(t, []aclChange{
+		newACLChange(aclSyncCreate, acl("User:admin", "orders")),
+		newACLChange(aclSyncCreate, acl("User:alice", "orders")),
+		newACLChange(aclSyncDelete, acl("User:carol", "payments")),
+	}, changes)
+
+	changes = diffACLs(source, target, []string{"User:admin", "User:carol", "User:svc-prod-*"})
+	assert.Equal(t, []aclChange{newACLChange(aclSyncCreate, acl("User:alice", "orders"))}, changes)
+	assert.Equal(t, acl("User:alice", "orders"), changes[0].ACL())
+
+	assert.Empty(t, diffACLs(source, source, nil))
+}
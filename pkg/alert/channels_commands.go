@@ -8,6 +8,7 @@ import (
 	"github.com/lensesio/lenses-go/pkg"
 	"github.com/lensesio/lenses-go/pkg/api"
 	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/printer"
 	"github.com/spf13/cobra"
 )
 
@@ -35,14 +36,14 @@ func NewGetAlertChannelsCommand() *cobra.Command {
 				if err != nil {
 					return fmt.Errorf("failed to retrieve alerts' channels. Error: [%s]", err.Error())
 				}
-				return bite.PrintObject(cmd, alertchannelsWithDetails.Values)
+				return printer.PrintObject(cmd, alertchannelsWithDetails.Values)
 			}
 
 			alertchannels, err := config.Client.GetChannels(pkg.AlertChannelsPath, page, pageSize, sortField, sortOrder, templateName, channelName)
 			if err != nil {
 				return fmt.Errorf("failed to retrieve alerts' channels. Error: [%s]", err.Error())
 			}
-			return bite.PrintObject(cmd, alertchannels.Values)
+			return printer.PrintObject(cmd, alertchannels.Values)
 		},
 	}
 
@@ -56,6 +57,7 @@ func NewGetAlertChannelsCommand() *cobra.Command {
 
 	bite.CanBeSilent(cmd)
 	bite.CanPrintJSON(cmd)
+	printer.CanSelect(cmd)
 
 	cmd.AddCommand(NewDeleteAlertChannelCommand())
 	cmd.AddCommand(NewCreateAlertChannelCommand())
@@ -10,6 +10,8 @@ import (
 	"github.com/lensesio/bite"
 	"github.com/lensesio/lenses-go/pkg/api"
 	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/printer"
+	"github.com/lensesio/lenses-go/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -40,7 +42,7 @@ func NewGetAlertsCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:              "alerts",
 		Short:            "Print the registered alerts",
-		Example:          "alerts",
+		Example:          "alerts [--output=ndjson]",
 		TraverseChildren: true,
 		SilenceErrors:    true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -48,13 +50,25 @@ func NewGetAlertsCommand() *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("failed to retrieve alerts. Error: [%s]", err.Error())
 			}
-			return bite.PrintObject(cmd, alerts)
+
+			if utils.IsNDJSON(cmd) {
+				for _, alert := range alerts {
+					if err = utils.PrintNDJSON(cmd, alert); err != nil {
+						return err
+					}
+				}
+
+				return nil
+			}
+
+			return printer.PrintObject(cmd, alerts)
 		},
 	}
 
 	cmd.Flags().IntVar(&pageSize, "page-size", 25, "Size of items to be included in the list")
 
 	bite.CanPrintJSON(cmd)
+	printer.CanSelect(cmd)
 
 	cmd.AddCommand(DeleteAlertEventsCommand())
 
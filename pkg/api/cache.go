@@ -0,0 +1,170 @@
+package api
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	pathpkg "path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kataras/golog"
+)
+
+// DefaultCachePaths are the slowly changing lookups that the `UsingCache` caches by default,
+// the topics, the schema subjects (and the rest of the datasets), the topic config keys and the connector plugins.
+var DefaultCachePaths = []string{
+	topicsPath,
+	topicsAvailableConfigKeysPath,
+	"api/v1/datasets",
+	"api/proxy-connect/*/connector-plugins",
+}
+
+// UsingCache caches the successful responses of the GET requests of the "paths", the `DefaultCachePaths` if empty,
+// for the "ttl" and, if the server sends an ETag, revalidates them with the "If-None-Match" header after that.
+// A path may contain the wildcards of the `path.Match`, i.e "api/proxy-connect/*/connector-plugins".
+//
+// Any other request than a GET clears the cache, so a command reads its own changes.
+// The cache is shared by the calls of the client, i.e of a CLI invocation.
+func UsingCache(ttl time.Duration, paths ...string) ConnectionOption {
+	return func(c *Client) {
+		if len(paths) == 0 {
+			paths = DefaultCachePaths
+		}
+
+		c.cache = &responseCache{
+			ttl:     ttl,
+			paths:   paths,
+			now:     time.Now,
+			entries: make(map[string]*cacheEntry),
+		}
+	}
+}
+
+// responseCache is the `http.RoundTripper` of the `UsingCache`.
+type responseCache struct {
+	ttl   time.Duration
+	paths []string
+	now   func() time.Time
+	next  http.RoundTripper
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+type cacheEntry struct {
+	statusCode int
+	header     http.Header
+	body       []byte
+	etag       string
+	expires    time.Time
+}
+
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        http.StatusText(e.statusCode),
+		StatusCode:    e.statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
+
+// cacheable reports whether the "urlPath" ends with one of the cached paths,
+// the host may have a path prefix.
+func (rc *responseCache) cacheable(urlPath string) bool {
+	segments := strings.Split(strings.Trim(urlPath, "/"), "/")
+
+	for _, p := range rc.paths {
+		n := strings.Count(strings.Trim(p, "/"), "/") + 1
+		if n > len(segments) {
+			continue
+		}
+
+		if ok, _ := pathpkg.Match(strings.Trim(p, "/"), strings.Join(segments[len(segments)-n:], "/")); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (rc *responseCache) clear() {
+	rc.mu.Lock()
+	rc.entries = make(map[string]*cacheEntry)
+	rc.mu.Unlock()
+}
+
+func (rc *responseCache) get(key string) *cacheEntry {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.entries[key]
+}
+
+// set stores a copy of the "entry", which expires after the ttl, the stored entries are never modified.
+func (rc *responseCache) set(key string, entry *cacheEntry) {
+	stored := *entry
+	stored.expires = rc.now().Add(rc.ttl)
+
+	rc.mu.Lock()
+	rc.entries[key] = &stored
+	rc.mu.Unlock()
+}
+
+func (rc *responseCache) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		if req.Method != http.MethodHead && req.Method != http.MethodOptions {
+			rc.clear()
+		}
+		return rc.next.RoundTrip(req)
+	}
+
+	if !rc.cacheable(req.URL.Path) {
+		return rc.next.RoundTrip(req)
+	}
+
+	key := req.URL.String()
+	entry := rc.get(key)
+	if entry != nil && rc.now().Before(entry.expires) {
+		golog.Debugf("Client#Do.cache: hit [%s]", key)
+		return entry.response(req), nil
+	}
+
+	if entry != nil && entry.etag != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", entry.etag)
+	}
+
+	resp, err := rc.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		resp.Body.Close()
+		golog.Debugf("Client#Do.cache: not modified [%s]", key)
+		rc.set(key, entry)
+		return entry.response(req), nil
+	}
+
+	etag := resp.Header.Get("ETag")
+	if resp.StatusCode != http.StatusOK || (rc.ttl <= 0 && etag == "") {
+		return resp, nil
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	entry = &cacheEntry{statusCode: resp.StatusCode, header: resp.Header, body: body, etag: etag}
+	rc.set(key, entry)
+
+	return entry.response(req), nil
+}
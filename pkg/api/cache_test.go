@@ -0,0 +1,68 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsingCache(t *testing.T) {
+	requests := make(map[string]int)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.Method+" "+r.URL.Path]++
+
+		switch r.URL.Path {
+		case "/api/topics":
+			if r.Method == http.MethodGet {
+				w.Write([]byte(`[{"topicName":"orders"}]`))
+			}
+		case "/api/proxy-connect/dev/connector-plugins":
+			w.Header().Set("ETag", `"v1"`)
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Write([]byte(`[{"class":"FileStreamSink"}]`))
+		case "/api/quotas":
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer srv.Close()
+
+	now := time.Now()
+	client, err := OpenConnection(ClientConfig{Host: srv.URL, Token: "t0ken"}, UsingCache(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.cache.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		names, err := client.GetTopicsNames()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"orders"}, names)
+
+		_, err = client.GetQuotas()
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 1, requests["GET /api/topics"])
+	assert.Equal(t, 3, requests["GET /api/quotas"], "not a cached path")
+
+	// a change clears the cache.
+	assert.NoError(t, client.CreateTopic("payments", 1, 1, nil))
+	_, err = client.GetTopics()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, requests["GET /api/topics"])
+
+	plugins, err := client.GetConnectorPlugins("dev")
+	assert.NoError(t, err)
+
+	// expired, revalidated by the ETag.
+	now = now.Add(2 * time.Minute)
+	again, err := client.GetConnectorPlugins("dev")
+	assert.NoError(t, err)
+	assert.Equal(t, plugins, again)
+	assert.Equal(t, 2, requests["GET /api/proxy-connect/dev/connector-plugins"])
+}
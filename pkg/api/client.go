@@ -12,17 +12,19 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 	"unicode"
 
 	"github.com/kataras/golog"
 	"github.com/lensesio/lenses-go/pkg"
+	"github.com/lensesio/lenses-go/pkg/buildinfo"
 	"github.com/mitchellh/mapstructure"
 )
 
 // BuildVersion is the version that gets set at build time and which we need
-// to pass to the `Agent` header
-var BuildVersion string
+// to pass to the `Agent` header, it's the `buildinfo.Version` by default.
+var BuildVersion = buildinfo.Version
 
 // User represents the user of the client.
 type User struct {
@@ -46,6 +48,82 @@ type Client struct {
 
 	// the client is created on the `lenses#OpenConnection` function, it can be customized via options there.
 	client *http.Client
+
+	// onTokenRefresh is set by `UsingCachedToken`.
+	onTokenRefresh func(token string)
+	refreshMu      sync.Mutex
+	// tokenMu guards the `Config#Token`, which the `refreshToken` renews while other requests are sent.
+	tokenMu sync.RWMutex
+
+	// serverInfo is fetched once by `ServerInfo`.
+	serverInfo   *ServerInfo
+	serverInfoMu sync.Mutex
+
+	// cache is set by `UsingCache`.
+	cache *responseCache
+	// transportOptions are set by `UsingTransport`.
+	transportOptions *TransportOptions
+	// debugOut is set by `UsingDebugOut`.
+	debugOut io.Writer
+	// requestID is set by `UsingRequestID`, if empty each request gets a new one.
+	requestID string
+	// userAgent is set by `UsingUserAgent`, if empty it's the `UserAgent`.
+	userAgent string
+}
+
+// token returns the current `Config#Token`.
+func (c *Client) token() string {
+	c.tokenMu.RLock()
+	defer c.tokenMu.RUnlock()
+	return c.Config.Token
+}
+
+func (c *Client) setToken(token string) {
+	c.tokenMu.Lock()
+	c.Config.Token = token
+	c.tokenMu.Unlock()
+}
+
+// refreshToken renews an expired token through the `Config#Authentication`,
+// it reports whether the request that got the unauthorized response should be retried.
+func (c *Client) refreshToken(expired string) bool {
+	if c.onTokenRefresh == nil || c.Config.Authentication == nil {
+		return false
+	}
+
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	if c.token() != expired {
+		// renewed by a concurrent request.
+		return true
+	}
+
+	// authenticate through a copy without the expired token, so the requests sent in the meantime
+	// keep it and wait for the renewed one, and the authentication's own requests are never refreshed.
+	authConfig := *c.Config
+	authConfig.Token = ""
+	auth := &Client{
+		Config:                    &authConfig,
+		configFull:                c.configFull,
+		PersistentRequestModifier: c.PersistentRequestModifier,
+		client:                    c.client,
+		requestID:                 c.requestID,
+		userAgent:                 c.userAgent,
+	}
+
+	if err := c.Config.Authentication.Auth(auth); err != nil {
+		golog.Debugf("Client#refreshToken: [%v]", err)
+		return false
+	}
+
+	token := auth.token()
+	c.User = auth.User
+	c.setToken(token)
+
+	golog.Debugf("Client#refreshToken: token renewed for user [%s]", auth.User.Name)
+	c.onTokenRefresh(token)
+	return true
 }
 
 var noOpBuffer = new(bytes.Buffer)
@@ -101,10 +179,17 @@ type ResourceError struct {
 	Method     string `json:"method" header:"Method"`
 	URI        string `json:"uri" header:"Target"`
 	Body       string `json:"message" header:"Message"`
+	// RequestID is the `RequestIDHeader` of the failed request.
+	RequestID string `json:"requestId,omitempty" header:"Request ID"`
 }
 
 // String returns the detailed cause of the error.
 func (err ResourceError) String() string {
+	if err.RequestID != "" {
+		return fmt.Sprintf("client: [%s: %s] failed with status code [%d], request id [%s]:\n[%s]",
+			err.Method, err.URI, err.StatusCode, err.RequestID, err.Body)
+	}
+
 	return fmt.Sprintf("client: [%s: %s] failed with status code [%d]:\n[%s]",
 		err.Method, err.URI, err.StatusCode, err.Body)
 }
@@ -172,12 +257,18 @@ type jsonResourceErrorV2 struct {
 // Do is the lower level of a client call, manually sends an HTTP request to the lenses box backend based on the `Client#Config`
 // and returns an HTTP response.
 func (c *Client) Do(method, path, contentType string, send []byte, options ...RequestOption) (*http.Response, error) {
-	if path[0] == '/' { // remove beginning slash, if any.
-		path = path[1:]
+	return c.do(method, path, contentType, send, true, options...)
+}
+
+func (c *Client) do(method, path, contentType string, send []byte, canRefresh bool, options ...RequestOption) (*http.Response, error) {
+	uri := c.Config.URL(path)
+
+	requestID := c.requestID
+	if requestID == "" {
+		requestID = NewRequestID()
 	}
-	uri := c.Config.Host + "/" + path
 
-	golog.Debugf("Client#Do.req:\n\turi: %s:%s\n\tsend: %s", method, uri, string(send))
+	golog.Debugf("Client#Do.req:\n\turi: %s:%s\n\trequest id: %s\n\tsend: %s", method, uri, requestID, string(send))
 
 	req, err := http.NewRequest(method, uri, acquireBuffer(send))
 	if err != nil {
@@ -188,13 +279,17 @@ func (c *Client) Do(method, path, contentType string, send []byte, options ...Re
 	// Set explicit host and user-agent header
 	u, err := url.Parse(c.Config.Host)
 	hostHeader := u.Host
-	userAgentHeader := "lenses-cli/" + BuildVersion
+	userAgentHeader := c.userAgent
+	if userAgentHeader == "" {
+		userAgentHeader = UserAgent()
+	}
 	req.Header.Set("Host", hostHeader)
 	req.Header.Set("User-Agent", userAgentHeader)
+	req.Header.Set(RequestIDHeader, requestID)
 
 	// set the token header.
-	if c.Config.Token != "" {
-		req.Header.Set(xKafkaLensesTokenHeaderKey, c.Config.Token)
+	if token := c.token(); token != "" {
+		req.Header.Set(xKafkaLensesTokenHeaderKey, token)
 	}
 
 	// set the content type if any.
@@ -229,6 +324,9 @@ func (c *Client) Do(method, path, contentType string, send []byte, options ...Re
 
 	if !isAuthorized(resp) {
 		resp.Body.Close() // close the body here so we don't have leaks.
+		if token := req.Header.Get(xKafkaLensesTokenHeaderKey); canRefresh && token != "" && c.refreshToken(token) {
+			return c.do(method, path, contentType, send, false, options...)
+		}
 		return nil, ErrCredentialsMissing
 	}
 
@@ -286,7 +384,9 @@ func (c *Client) Do(method, path, contentType string, send []byte, options ...Re
 			errBody = fmt.Sprintf("Response returned status code %d", resp.StatusCode)
 		}
 
-		return nil, NewResourceError(resp.StatusCode, uri, method, errBody)
+		resErr := NewResourceError(resp.StatusCode, uri, method, errBody)
+		resErr.RequestID = req.Header.Get(RequestIDHeader)
+		return nil, resErr
 	}
 
 	return resp, nil
@@ -439,7 +539,7 @@ func (c *Client) ReadJSON(resp *http.Response, valuePtr interface{}) error {
 // GetAccessToken returns the access token that
 // generated from the `OpenConnection` or given by the configuration.
 func (c *Client) GetAccessToken() string {
-	return c.Config.Token
+	return c.token()
 }
 
 const logoutPath = "api/logout?token="
@@ -447,11 +547,12 @@ const logoutPath = "api/logout?token="
 // Logout invalidates the token and revoke its access.
 // A new Client, using `OpenConnection`, should be created in order to continue after this call.
 func (c *Client) Logout() error {
-	if c.Config.Token == "" {
+	token := c.token()
+	if token == "" {
 		return ErrCredentialsMissing
 	}
 
-	path := logoutPath + c.Config.Token
+	path := logoutPath + token
 	resp, err := c.Do(http.MethodGet, path, "", nil)
 	if err != nil {
 		return err
@@ -1222,7 +1323,7 @@ func (c *Client) DeleteTopicRecords(topicName string, fromPartition int, toOffse
 	path := fmt.Sprintf(topicRecordsPath, topicName, fromPartition, toOffset)
 
 	if toOffset < 0 || fromPartition < 0 {
-		return NewResourceError(http.StatusBadRequest, c.Config.Host+"/"+path, "DELETE", "offset and partition should be positive numbers")
+		return NewResourceError(http.StatusBadRequest, c.Config.URL(path), "DELETE", "offset and partition should be positive numbers")
 	}
 
 	resp, err := c.Do(http.MethodDelete, path, "", nil)
@@ -2276,6 +2377,7 @@ func (c *Client) RestartConnectorTask(clusterName, name string, taskID int) erro
 
 // ConnectorPlugin describes the entry data of the list that are being received from the `GetConnectorPlugins`.
 type ConnectorPlugin struct {
+	ClusterName string `json:"clusterName,omitempty"` // internal use only, not set by response.
 	// Class is the connector class name.
 	Class string `json:"class" header:"Class"`
 
@@ -2304,7 +2406,118 @@ func (c *Client) GetConnectorPlugins(clusterName string) (cp []ConnectorPlugin,
 		return
 	}
 
-	err = c.ReadJSON(resp, &cp)
+	if err = c.ReadJSON(resp, &cp); err != nil {
+		return
+	}
+
+	for i := range cp {
+		cp[i].ClusterName = clusterName
+	}
+
+	return
+}
+
+type (
+	// ConnectorConfigValidation describes the data that are being received from the `ValidateConnectorConfig`.
+	ConnectorConfigValidation struct {
+		// Name is the connector class name.
+		Name       string                           `json:"name"`
+		ErrorCount int                              `json:"error_count"`
+		Groups     []string                         `json:"groups"`
+		Configs    []ConnectorConfigValidationEntry `json:"configs"`
+	}
+
+	// ConnectorConfigValidationEntry describes a single config key's definition and validated value,
+	// see `ConnectorConfigValidation`.
+	ConnectorConfigValidationEntry struct {
+		Definition ConnectorConfigDefinition `json:"definition"`
+		Value      ConnectorConfigValue      `json:"value"`
+	}
+
+	// ConnectorConfigDefinition describes a config key as it is documented by the connector plugin.
+	ConnectorConfigDefinition struct {
+		Name          string   `json:"name" header:"Name"`
+		Type          string   `json:"type" header:"Type"`
+		Required      bool     `json:"required" header:"Required"`
+		DefaultValue  *string  `json:"default_value" header:"Default"`
+		Importance    string   `json:"importance" header:"Importance"`
+		Documentation string   `json:"documentation" header:"Documentation"`
+		Group         string   `json:"group" header:"Group"`
+		Dependents    []string `json:"dependents,omitempty" header:"-"`
+	}
+
+	// ConnectorConfigValue describes the validated value of a config key.
+	ConnectorConfigValue struct {
+		Name              string   `json:"name" header:"Key"`
+		Value             *string  `json:"value" header:"Value"`
+		RecommendedValues []string `json:"recommended_values,omitempty" header:"-"`
+		Errors            []string `json:"errors,omitempty" header:"Errors"`
+		Visible           bool     `json:"visible" header:"-"`
+	}
+)
+
+// Errors returns the config values that failed to validate, if any.
+func (v ConnectorConfigValidation) Errors() (values []ConnectorConfigValue) {
+	for _, entry := range v.Configs {
+		if len(entry.Value.Errors) > 0 {
+			values = append(values, entry.Value)
+		}
+	}
+
+	return
+}
+
+// Definitions returns the config key definitions of the validated connector plugin.
+func (v ConnectorConfigValidation) Definitions() (definitions []ConnectorConfigDefinition) {
+	for _, entry := range v.Configs {
+		definitions = append(definitions, entry.Definition)
+	}
+
+	return
+}
+
+// GetConnectorPluginConfig returns the documentation of the config keys that the connector plugin accepts.
+// It validates an empty config, which works on every Kafka Connect version,
+// because the validation response carries the definitions of all the config keys.
+func (c *Client) GetConnectorPluginConfig(clusterName, connectorClass string) ([]ConnectorConfigDefinition, error) {
+	v, err := c.ValidateConnectorConfig(clusterName, connectorClass, ConnectorConfig{"connector.class": connectorClass})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.Definitions(), nil
+}
+
+// ValidateConnectorConfig validates the provided configuration values against the configuration
+// definition of the connector plugin. The "connectorClass" can be the fully qualified class name
+// or its simple name. The response lists the errors per config key, see `ConnectorConfigValidation.Errors`.
+func (c *Client) ValidateConnectorConfig(clusterName, connectorClass string, config ConnectorConfig) (v ConnectorConfigValidation, err error) {
+	if clusterName == "" {
+		err = errRequired("clusterName")
+		return
+	}
+
+	if connectorClass == "" {
+		err = errRequired("connectorClass")
+		return
+	}
+
+	send, derr := json.Marshal(config)
+	if derr != nil {
+		err = derr
+		return
+	}
+
+	// # Validate the connector config
+	// PUT /api/proxy-connect/(string: clusterName)/connector-plugins/(string: class)/config/validate
+	path := fmt.Sprintf(pluginsPath+"/%s/config/validate", clusterName, connectorClass)
+	resp, respErr := c.Do(http.MethodPut, path, contentTypeJSON, send)
+	if respErr != nil {
+		err = respErr
+		return
+	}
+
+	err = c.ReadJSON(resp, &v)
 	return
 }
 
@@ -3553,6 +3766,33 @@ type SQLValidationResponse struct {
 	Suggestions []Suggestions     `json:"suggestions"`
 }
 
+// Errors returns the "error" lints as `InvalidSQLError`s with their line and column in the `Input`,
+// the "warning" lints are included too if "withWarnings" is true.
+func (v SQLValidationResponse) Errors(withWarnings bool) []InvalidSQLError {
+	var errs []InvalidSQLError
+
+	for _, lint := range v.Lints {
+		lintType := strings.ToLower(lint.Type)
+		if lintType != "error" && !(withWarnings && lintType == "warning") {
+			continue
+		}
+
+		line, col := position(v.Input, lint.Start)
+		errs = append(errs, InvalidSQLError{Line: line, Col: col, Message: lint.Text})
+	}
+
+	return errs
+}
+
+// Err returns an `InvalidSQLError` of the first "error" lint, if any, otherwise nil.
+func (v SQLValidationResponse) Err() error {
+	if errs := v.Errors(false); len(errs) > 0 {
+		return errs[0]
+	}
+
+	return nil
+}
+
 // ValidateSQL valids a Lenses sql statement
 func (c *Client) ValidateSQL(sql string, caret int) (SQLValidationResponse, error) {
 
@@ -59,7 +59,7 @@ func (auth BasicAuthentication) Auth(c *Client) error {
 	}
 
 	if err != nil {
-		return fmt.Errorf("%s or kerberos authentication is required", err.Error())
+		return fmt.Errorf("%w or kerberos authentication is required", err)
 	}
 
 	tokenBytes, err := c.ReadResponseBody(resp)
@@ -79,14 +79,14 @@ func (auth BasicAuthentication) Auth(c *Client) error {
 	})
 
 	if err != nil {
-		return fmt.Errorf("basic failure: %v", err)
+		return fmt.Errorf("basic failure: %w", err)
 	}
 
 	if err = c.ReadJSON(resp, &c.User); err != nil {
 		return err
 	}
 
-	c.Config.Token = c.User.Token // ...
+	c.setToken(c.User.Token) // ...
 	return nil
 }
 
@@ -157,14 +157,14 @@ func (auth KerberosAuthentication) Auth(c *Client) error {
 	}
 
 	if err != nil {
-		return fmt.Errorf("kerberos failure: unable to send SPNEGO header: %v", err)
+		return fmt.Errorf("kerberos failure: unable to send SPNEGO header: %w", err)
 	}
 
 	if err = c.ReadJSON(resp, &c.User); err != nil {
 		return err
 	}
 
-	c.Config.Token = c.User.Token // update the config's one as well for any case.
+	c.setToken(c.User.Token) // update the config's one as well for any case.
 	return nil
 }
 
@@ -0,0 +1,42 @@
+package api
+
+import "net/http"
+
+const kafkaClusterPath = "api/v1/kafka/cluster"
+
+// Broker describes a broker of the Kafka cluster.
+type Broker struct {
+	ID   int    `json:"id" yaml:"id" header:"ID"`
+	Host string `json:"host" yaml:"host" header:"Host"`
+	Port int    `json:"port" yaml:"port" header:"Port"`
+	Rack string `json:"rack,omitempty" yaml:"rack,omitempty" header:"Rack"`
+}
+
+// KafkaCluster describes the brokers of the Kafka cluster and its controller, see `GetKafkaCluster`.
+type KafkaCluster struct {
+	// ControllerID is the broker ID of the controller, `LeaderNone` if there is no active controller.
+	ControllerID int      `json:"controller" yaml:"controller"`
+	Brokers      []Broker `json:"brokers" yaml:"brokers"`
+}
+
+// Controller returns the controller broker and whether it's one of the live brokers.
+func (k KafkaCluster) Controller() (Broker, bool) {
+	for _, b := range k.Brokers {
+		if b.ID == k.ControllerID {
+			return b, true
+		}
+	}
+
+	return Broker{ID: k.ControllerID}, false
+}
+
+// GetKafkaCluster returns the live brokers of the Kafka cluster and its controller.
+func (c *Client) GetKafkaCluster() (cluster KafkaCluster, err error) {
+	resp, err := c.Do(http.MethodGet, kafkaClusterPath, "", nil)
+	if err != nil {
+		return
+	}
+
+	err = c.ReadJSON(resp, &cluster)
+	return
+}
@@ -58,6 +58,9 @@ type (
 	ClientConfig struct {
 		// Host is the network shema  address and port that your lenses backend box is listening on.
 		Host string `json:"host" yaml:"Host" survey:"host"`
+		// BasePath is the path prefix of a Lenses behind a sub-path proxy, i.e "/lenses",
+		// it's prepended to the paths of the REST and the websocket endpoints. See `URL`.
+		BasePath string `json:"basePath,omitempty" yaml:"BasePath,omitempty" survey:"-"`
 
 		// Authentication, in order to gain access using different kind of options.
 		//
@@ -88,6 +91,13 @@ type (
 		//
 		// Defaults to false.
 		Insecure bool `json:"insecure,omitempty" yaml:"Insecure,omitempty" survey:"insecure"`
+		// CACert is the base64 encoded PEM of the certificate authorities of the Lenses certificate,
+		// instead of the ones of the system, i.e of a private CA.
+		CACert string `json:"caCert,omitempty" yaml:"CACert,omitempty" survey:"-"`
+		// ClientCert and ClientKey are the base64 encoded PEM of the client certificate and of its private key,
+		// of a Lenses behind a proxy that requires mutual TLS.
+		ClientCert string `json:"clientCert,omitempty" yaml:"ClientCert,omitempty" survey:"-"`
+		ClientKey  string `json:"clientKey,omitempty" yaml:"ClientKey,omitempty" survey:"-"`
 		// Debug activates the debug mode, it logs every request, the configuration (except the `Password`)
 		// and its raw response before decoded but after gzip reading.
 		//
@@ -98,6 +108,18 @@ type (
 		//
 		// Defaults to false.
 		Debug bool `json:"debug,omitempty" yaml:"Debug,omitempty" survey:"debug"`
+
+		// Protected marks the context of a critical environment, i.e production,
+		// the CLI refuses to run mutating commands against it unless explicitly allowed.
+		//
+		// Defaults to false.
+		Protected bool `json:"protected,omitempty" yaml:"Protected,omitempty" survey:"-"`
+
+		// WebsocketTokenHeader sends the token of the live queries as that header of the websocket handshake,
+		// "X-Kafka-Lenses-Token" or "Authorization", instead of inside the query message.
+		//
+		// Defaults to empty, the token is sent inside the query message.
+		WebsocketTokenHeader string `json:"websocketTokenHeader,omitempty" yaml:"WebsocketTokenHeader,omitempty" survey:"-"`
 	}
 )
 
@@ -261,6 +283,10 @@ func (c *ClientConfig) Fill(other ClientConfig) bool {
 		c.Timeout = v
 	}
 
+	if v := other.BasePath; v != "" && v != c.BasePath {
+		c.BasePath = v
+	}
+
 	// set only when true.
 	if v := other.Debug; v {
 		c.Debug = v
@@ -270,9 +296,36 @@ func (c *ClientConfig) Fill(other ClientConfig) bool {
 		c.Insecure = v
 	}
 
+	if v := other.CACert; v != "" && v != c.CACert {
+		c.CACert = v
+	}
+
+	if v := other.ClientCert; v != "" && v != c.ClientCert {
+		c.ClientCert = v
+	}
+
+	if v := other.ClientKey; v != "" && v != c.ClientKey {
+		c.ClientKey = v
+	}
+
 	return c.IsValid()
 }
 
+// FormatBasePath returns the "basePath" with a leading and without a trailing slash, i.e "/lenses", empty for the root.
+func FormatBasePath(basePath string) string {
+	basePath = strings.Trim(strings.TrimSpace(basePath), "/")
+	if basePath == "" {
+		return ""
+	}
+
+	return "/" + basePath
+}
+
+// URL returns the full URL of the "path" of the Lenses API, the `Host` and the `BasePath` followed by the "path".
+func (c *ClientConfig) URL(path string) string {
+	return c.Host + FormatBasePath(c.BasePath) + "/" + strings.TrimPrefix(path, "/")
+}
+
 // FormatHost will try to make sure that the schema:host:port pattern is followed on the `Host` field.
 func (c *ClientConfig) FormatHost() {
 	if len(c.Host) == 0 {
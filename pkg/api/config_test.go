@@ -229,3 +229,21 @@ Contexts:
 		t.Fatalf("expected result yaml to be written as:\n'%s'\nbut:\n'%s'", expected, got)
 	}
 }
+
+func TestClientConfigURL(t *testing.T) {
+	tests := []struct {
+		basePath, path, expected string
+	}{
+		{"", "api/topics", "https://lenses:9991/api/topics"},
+		{"/lenses", "api/topics", "https://lenses:9991/lenses/api/topics"},
+		{"lenses/", "/api/topics", "https://lenses:9991/lenses/api/topics"},
+		{" / ", "api/topics", "https://lenses:9991/api/topics"},
+	}
+
+	for _, tt := range tests {
+		cfg := ClientConfig{Host: "https://lenses:9991", BasePath: tt.basePath}
+		if got := cfg.URL(tt.path); got != tt.expected {
+			t.Fatalf("expected [%s] but got [%s]", tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetConnectorPluginConfig(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/api/proxy-connect/dev/connector-plugins/FileStreamSink/config/validate", r.URL.Path)
+
+		var cfg ConnectorConfig
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&cfg))
+		assert.Equal(t, "FileStreamSink", cfg["connector.class"])
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name":"FileStreamSink","error_count":1,"groups":["Common"],"configs":[
+			{"definition":{"name":"file","type":"STRING","required":false,"importance":"HIGH","documentation":"Destination filename.","group":"Common"},
+			 "value":{"name":"file","value":null,"errors":[],"visible":true}},
+			{"definition":{"name":"topics","type":"LIST","required":true,"importance":"HIGH","documentation":"List of topics.","group":"Common"},
+			 "value":{"name":"topics","value":null,"errors":["Must configure one of topics or topics.regex"],"visible":true}}]}`))
+	}))
+	defer srv.Close()
+
+	client, err := OpenConnection(ClientConfig{Host: srv.URL}, UsingToken("token"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	definitions, err := client.GetConnectorPluginConfig("dev", "FileStreamSink")
+	assert.NoError(t, err)
+	assert.Len(t, definitions, 2)
+	assert.Equal(t, "topics", definitions[1].Name)
+	assert.True(t, definitions[1].Required)
+
+	v, err := client.ValidateConnectorConfig("dev", "FileStreamSink", ConnectorConfig{"connector.class": "FileStreamSink"})
+	assert.NoError(t, err)
+	assert.Equal(t, []ConnectorConfigValue{{Name: "topics", Errors: []string{"Must configure one of topics or topics.regex"}, Visible: true}}, v.Errors())
+}
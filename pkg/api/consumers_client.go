@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 
 	"github.com/lensesio/lenses-go/pkg"
 )
@@ -56,3 +57,48 @@ func (c *Client) UpdateMultipleTopicsOffset(groupID, offsetType, target string,
 
 	return nil
 }
+
+// ConsumerGroupPartition is a topic partition assigned to a member of a consumer group and its offsets.
+type ConsumerGroupPartition struct {
+	Topic     string `json:"topic" yaml:"topic"`
+	Partition int    `json:"partition" yaml:"partition"`
+	// CurrentOffset is the committed offset of the group on the partition.
+	CurrentOffset int64 `json:"currentOffset" yaml:"currentOffset"`
+	// LogEndOffset is the end offset of the partition.
+	LogEndOffset int64 `json:"logEndOffset" yaml:"logEndOffset"`
+	Lag          int64 `json:"lag" yaml:"lag"`
+}
+
+// ConsumerGroupMember is a member of a consumer group and its assignment.
+type ConsumerGroupMember struct {
+	ConsumerID string                   `json:"consumerId" yaml:"consumerId"`
+	ClientID   string                   `json:"clientId" yaml:"clientId"`
+	Host       string                   `json:"host" yaml:"host"`
+	Partitions []ConsumerGroupPartition `json:"topicPartitions" yaml:"topicPartitions"`
+}
+
+// ConsumerGroup describes a consumer group, its members and their assigned partitions, see `GetConsumerGroup`.
+type ConsumerGroup struct {
+	ID          string                `json:"id" yaml:"id"`
+	State       ConsumerGroupState    `json:"state" yaml:"state"`
+	Coordinator ConsumerCoordinator   `json:"coordinator" yaml:"coordinator"`
+	Members     []ConsumerGroupMember `json:"consumers" yaml:"consumers"`
+}
+
+// GetConsumerGroup returns the members of the consumer group of the "groupID", their client IDs, hosts,
+// assigned partitions and the group's offsets on them.
+func (c *Client) GetConsumerGroup(groupID string) (group ConsumerGroup, err error) {
+	if groupID == "" {
+		err = errRequired("groupID")
+		return
+	}
+
+	path := fmt.Sprintf("%s/%s", pkg.ConsumersGroupPath, url.PathEscape(groupID))
+	resp, err := c.Do(http.MethodGet, path, "", nil)
+	if err != nil {
+		return
+	}
+
+	err = c.ReadJSON(resp, &group)
+	return
+}
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Redacted replaces the tokens and the passwords of the debug output, see `UsingDebugOut`.
+const Redacted = "[REDACTED]"
+
+// sensitiveHeaders are the headers which are redacted by `RedactHeader`.
+var sensitiveHeaders = []string{
+	xKafkaLensesTokenHeaderKey,
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// RedactHeader returns a copy of the "header" with the values of its token, authorization and cookie headers redacted.
+func RedactHeader(header http.Header) http.Header {
+	redacted := header.Clone()
+	for _, key := range sensitiveHeaders {
+		if values := redacted.Values(key); len(values) > 0 {
+			redacted[http.CanonicalHeaderKey(key)] = []string{Redacted}
+		}
+	}
+
+	return redacted
+}
+
+// sensitiveFields matches the string values of the JSON fields which hold a token, a password or a secret.
+var sensitiveFields = regexp.MustCompile(`(?i)("[a-z_.-]*(?:token|password|passwd|secret)[a-z_.-]*"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// RedactJSON returns the "body" with the string values of its token, password and secret fields redacted.
+// The "body" does not have to be a valid JSON document.
+func RedactJSON(body []byte) []byte {
+	return sensitiveFields.ReplaceAll(body, []byte(`${1}"`+Redacted+`"`))
+}
+
+// UsingDebugOut writes a dump of each HTTP request and its response to "w", with the tokens and the passwords redacted,
+// for troubleshooting API incompatibilities. The response of the login, the token, is redacted as a whole.
+func UsingDebugOut(w io.Writer) ConnectionOption {
+	return func(c *Client) {
+		c.debugOut = w
+	}
+}
+
+// debugTransport is the `http.RoundTripper` of the `UsingDebugOut`.
+type debugTransport struct {
+	next http.RoundTripper
+	out  io.Writer
+	mu   sync.Mutex // serializes the dumps of concurrent requests.
+}
+
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var dump bytes.Buffer
+
+	t.dumpRequest(&dump, req)
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		fmt.Fprintf(&dump, "<<< %s %s failed after %s: %v\n\n", req.Method, req.URL, time.Since(start).Round(time.Millisecond), err)
+		t.write(dump.Bytes())
+		return nil, err
+	}
+
+	fmt.Fprintf(&dump, "<<< %s %s (%s)\n", req.Method, req.URL, time.Since(start).Round(time.Millisecond))
+	resp, err = t.dumpResponse(&dump, req, resp)
+	t.write(dump.Bytes())
+	return resp, err
+}
+
+func (t *debugTransport) write(b []byte) {
+	t.mu.Lock()
+	t.out.Write(b)
+	t.mu.Unlock()
+}
+
+func (t *debugTransport) dumpRequest(dump *bytes.Buffer, req *http.Request) {
+	fmt.Fprintf(dump, ">>> %s %s\n", req.Method, req.URL)
+
+	r := req.Clone(req.Context())
+	r.Header = RedactHeader(req.Header)
+	if head, err := httputil.DumpRequestOut(r, false); err == nil {
+		dump.Write(bytes.TrimRight(head, "\r\n"))
+		dump.WriteString("\n")
+	}
+
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			b, _ := ioutil.ReadAll(body)
+			body.Close()
+			writeBody(dump, RedactJSON(b))
+		}
+	}
+	dump.WriteString("\n")
+}
+
+func (t *debugTransport) dumpResponse(dump *bytes.Buffer, req *http.Request, resp *http.Response) (*http.Response, error) {
+	r := *resp
+	r.Header = RedactHeader(resp.Header)
+	if head, err := httputil.DumpResponse(&r, false); err == nil {
+		dump.Write(bytes.TrimRight(head, "\r\n"))
+		dump.WriteString("\n")
+	}
+
+	if resp.Body == nil {
+		dump.WriteString("\n")
+		return resp, nil
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasSuffix(req.URL.Path, "/api/login") {
+		// the body of the login is the token.
+		writeBody(dump, []byte(Redacted))
+	} else {
+		writeBody(dump, RedactJSON(decompress(resp.Header, b)))
+	}
+	dump.WriteString("\n")
+
+	return resp, nil
+}
+
+// decompress returns the uncompressed "b" if the response is gzipped, which is requested by the client itself.
+func decompress(header http.Header, b []byte) []byte {
+	if header.Get(contentEncodingHeaderKey) != gzipEncodingHeaderValue {
+		return b
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return b
+	}
+	defer r.Close()
+
+	uncompressed, err := ioutil.ReadAll(r)
+	if err != nil {
+		return b
+	}
+
+	return uncompressed
+}
+
+func writeBody(dump *bytes.Buffer, b []byte) {
+	if len(b) == 0 {
+		return
+	}
+
+	dump.WriteString("\n")
+	dump.Write(b)
+	if b[len(b)-1] != '\n' {
+		dump.WriteString("\n")
+	}
+}
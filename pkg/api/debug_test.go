@@ -0,0 +1,72 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactJSON(t *testing.T) {
+	tests := []struct {
+		body, expected string
+	}{
+		{`{"user":"admin","password":"s3cr\"et"}`, `{"user":"admin","password":"[REDACTED]"}`},
+		{`{"token": "t0ken", "sql": "SELECT 1"}`, `{"token": "[REDACTED]", "sql": "SELECT 1"}`},
+		{`{"config":{"connection.password":"p","aws.secret.key":"k","tasks.max":"1"}}`, `{"config":{"connection.password":"[REDACTED]","aws.secret.key":"[REDACTED]","tasks.max":"1"}}`},
+		{`not a json, "token"`, `not a json, "token"`},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, string(RedactJSON([]byte(tt.body))))
+	}
+}
+
+func TestRedactHeader(t *testing.T) {
+	header := http.Header{}
+	header.Set(xKafkaLensesTokenHeaderKey, "t0ken")
+	header.Set("Authorization", "Bearer t0ken")
+	header.Set("Accept", "application/json")
+
+	redacted := RedactHeader(header)
+	assert.Equal(t, Redacted, redacted.Get(xKafkaLensesTokenHeaderKey))
+	assert.Equal(t, Redacted, redacted.Get("Authorization"))
+	assert.Equal(t, "application/json", redacted.Get("Accept"))
+	assert.Equal(t, "t0ken", header.Get(xKafkaLensesTokenHeaderKey), "the original is not modified")
+}
+
+func TestUsingDebugOut(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/login":
+			w.Write([]byte("s3ssion-t0ken"))
+		case "/api/auth":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"user":"admin","token":"s3ssion-t0ken"}`))
+		case "/api/topics":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[{"topicName":"orders","configs":[{"name":"sasl.password","value":"x"}]}]`))
+		}
+	}))
+	defer srv.Close()
+
+	var out bytes.Buffer
+	client, err := OpenConnection(ClientConfig{Host: srv.URL, Authentication: BasicAuthentication{Username: "admin", Password: "p4ss"}}, UsingDebugOut(&out))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.GetTopicsNames()
+	assert.NoError(t, err)
+
+	dump := out.String()
+	assert.Contains(t, dump, ">>> POST "+srv.URL+"/api/login")
+	assert.Contains(t, dump, `"password": "[REDACTED]"`)
+	assert.Contains(t, dump, ">>> GET "+srv.URL+"/api/topics")
+	assert.Contains(t, dump, "X-Kafka-Lenses-Token: [REDACTED]")
+	assert.Contains(t, dump, `"topicName":"orders"`)
+	assert.NotContains(t, dump, "p4ss")
+	assert.NotContains(t, dump, "s3ssion-t0ken")
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrAuthFailed is the cause of the errors returned when the authentication against Lenses failed,
+// use `errors.Is(err, ErrAuthFailed)` to check for it.
+//
+// See `OpenConnection` too.
+var ErrAuthFailed = fmt.Errorf("auth failure")
+
+// AuthError is the error of an authentication that Lenses rejected with a 401 or 403 status code,
+// it is an `ErrAuthFailed` and it wraps the "Err" cause of the rejection.
+type AuthError struct {
+	Err error
+}
+
+// Error returns the `ErrAuthFailed` and its cause.
+func (err AuthError) Error() string {
+	return fmt.Sprintf("client: %s: [%v]", ErrAuthFailed, err.Err)
+}
+
+// Is reports whether the "target" is the `ErrAuthFailed`.
+func (err AuthError) Is(target error) bool {
+	return target == ErrAuthFailed
+}
+
+// Unwrap returns the cause of the rejection.
+func (err AuthError) Unwrap() error {
+	return err.Err
+}
+
+// isAuthRejected reports whether the "err" is a 401 or 403 response.
+func isAuthRejected(err error) bool {
+	return errors.Is(err, ErrCredentialsMissing) ||
+		errors.Is(err, ResourceError{StatusCode: http.StatusUnauthorized}) ||
+		errors.Is(err, ResourceError{StatusCode: http.StatusForbidden})
+}
+
+// APIError is the error returned from all API calls when an error status code is received.
+// It is the same type as the `ResourceError`, so `errors.As(err, &apiErr)` can be used
+// to read its `StatusCode` and `Body`.
+type APIError = ResourceError
+
+// Is reports whether the "target" is a `ResourceError` with the same status code,
+// so callers can check for a specific status code, i.e
+// `errors.Is(err, api.ResourceError{StatusCode: http.StatusNotFound})`.
+func (err ResourceError) Is(target error) bool {
+	t, ok := target.(ResourceError)
+	return ok && t.StatusCode == err.StatusCode
+}
+
+// ErrInvalidSQL is the cause of the `InvalidSQLError`,
+// use `errors.Is(err, ErrInvalidSQL)` to check if a query failed to validate.
+var ErrInvalidSQL = fmt.Errorf("invalid sql")
+
+// InvalidSQLError describes a Lenses SQL validation error and its position,
+// the `Line` and `Col` are 1-based.
+//
+// See `SQLValidationResponse.Err` too.
+type InvalidSQLError struct {
+	Line    int    `json:"line" header:"Line"`
+	Col     int    `json:"column" header:"Column"`
+	Message string `json:"message" header:"Message"`
+}
+
+// Error returns the position and the message of the validation error.
+func (err InvalidSQLError) Error() string {
+	return fmt.Sprintf("%s at line %d, column %d: %s", ErrInvalidSQL, err.Line, err.Col, err.Message)
+}
+
+// Unwrap returns the `ErrInvalidSQL`.
+func (err InvalidSQLError) Unwrap() error {
+	return ErrInvalidSQL
+}
+
+// position returns the 1-based line and column of the "offset" inside the "input".
+func position(input string, offset int) (line, col int) {
+	if offset > len(input) {
+		offset = len(input)
+	}
+
+	if offset < 0 {
+		offset = 0
+	}
+
+	before := input[:offset]
+	line = strings.Count(before, "\n") + 1
+	col = offset - strings.LastIndex(before, "\n")
+	return
+}
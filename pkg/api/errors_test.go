@@ -0,0 +1,80 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLValidationResponseErr(t *testing.T) {
+	validation := SQLValidationResponse{
+		Input: "SELECT *\nFROM topic\nLIIT 3",
+		Lints: []ValidationLints{
+			{Start: 0, End: 6, Text: "a warning", Type: "warning"},
+			{Start: 20, End: 24, Text: "Invalid syntax", Type: "error"},
+		},
+	}
+
+	err := validation.Err()
+	assert.True(t, errors.Is(err, ErrInvalidSQL))
+
+	var sqlErr InvalidSQLError
+	assert.True(t, errors.As(err, &sqlErr))
+	assert.Equal(t, InvalidSQLError{Line: 3, Col: 1, Message: "Invalid syntax"}, sqlErr)
+
+	assert.Nil(t, SQLValidationResponse{Input: "SELECT 1"}.Err())
+}
+
+func TestResourceErrorIs(t *testing.T) {
+	var err error = NewResourceError(http.StatusNotFound, "api/topics/x", http.MethodGet, "not found")
+
+	assert.True(t, errors.Is(err, ResourceError{StatusCode: http.StatusNotFound}))
+	assert.False(t, errors.Is(err, ResourceError{StatusCode: http.StatusForbidden}))
+
+	var apiErr APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, "not found", apiErr.Body)
+}
+
+func TestOpenConnectionAuthFailed(t *testing.T) {
+	tests := []struct {
+		status     int
+		authFailed bool
+	}{
+		{http.StatusUnauthorized, true},
+		{http.StatusForbidden, true},
+		{http.StatusInternalServerError, false},
+	}
+
+	for _, tt := range tests {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+			w.Write([]byte("rejected"))
+		}))
+
+		_, err := OpenConnection(ClientConfig{Host: srv.URL, Authentication: BasicAuthentication{Username: "user", Password: "pass"}})
+		srv.Close()
+
+		assert.Equal(t, tt.authFailed, errors.Is(err, ErrAuthFailed), tt.status)
+		if tt.status != http.StatusUnauthorized {
+			// the cause is kept.
+			assert.True(t, errors.Is(err, ResourceError{StatusCode: tt.status}), tt.status)
+		}
+	}
+
+	// a 401 is the `ErrCredentialsMissing` of the client.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	_, err := OpenConnection(ClientConfig{Host: srv.URL, Authentication: BasicAuthentication{Username: "user", Password: "pass"}})
+	assert.True(t, errors.Is(err, ErrCredentialsMissing))
+
+	// not rejected by Lenses.
+	_, err = OpenConnection(ClientConfig{Host: srv.URL, Authentication: BasicAuthentication{Username: "user"}})
+	assert.False(t, errors.Is(err, ErrAuthFailed))
+}
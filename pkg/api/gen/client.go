@@ -0,0 +1,29 @@
+// Package gen contains the typed client of the endpoints of the Lenses OpenAPI definition, openapi.json,
+// its structs and methods are generated by the lenses-openapi-gen command into zz_generated.go.
+// A new endpoint of the server is added to the definition, then `go generate ./pkg/api/gen` regenerates the client.
+// The requests are sent through an `api.Client`, so they share its configuration, authentication and error handling.
+package gen
+
+//go:generate go run ../../../cmd/lenses-openapi-gen -spec openapi.json -package gen -out zz_generated.go
+
+import (
+	"net/http"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+)
+
+// Doer sends the requests of the generated methods and reads their responses, it's implemented by the `api.Client`.
+type Doer interface {
+	Do(method, path, contentType string, send []byte, options ...api.RequestOption) (*http.Response, error)
+	ReadJSON(resp *http.Response, valuePtr interface{}) error
+}
+
+// Client is the typed client of the endpoints of the Lenses OpenAPI definition.
+type Client struct {
+	Doer
+}
+
+// New returns a typed client which sends its requests through the "doer", i.e an `*api.Client`.
+func New(doer Doer) *Client {
+	return &Client{Doer: doer}
+}
@@ -0,0 +1,57 @@
+package gen
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type request struct {
+	method, path, contentType string
+	send                      []byte
+}
+
+// fakeDoer records the requests and responds with the "body".
+type fakeDoer struct {
+	requests []request
+	body     string
+}
+
+func (d *fakeDoer) Do(method, path, contentType string, send []byte, options ...api.RequestOption) (*http.Response, error) {
+	d.requests = append(d.requests, request{method, path, contentType, send})
+	return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(bytes.NewBufferString(d.body))}, nil
+}
+
+func (d *fakeDoer) ReadJSON(resp *http.Response, valuePtr interface{}) error {
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(valuePtr)
+}
+
+func TestClient(t *testing.T) {
+	doer := &fakeDoer{body: `{"name":"admins","namespaces":[{"wildcards":["*"],"permissions":["ShowTopic"],"system":"Kafka","instance":"Dev"}]}`}
+	client := New(doer)
+
+	group, err := client.GetGroup("admins/eu")
+	require.NoError(t, err)
+	assert.Equal(t, "admins", group.Name)
+	assert.Equal(t, []Namespace{{Wildcards: []string{"*"}, Permissions: []string{"ShowTopic"}, System: "Kafka", Instance: "Dev"}}, group.Namespaces)
+
+	require.NoError(t, client.UpdateGroup("admins", group))
+	require.NoError(t, client.CloneGroup("admins", "admins2"))
+
+	require.Len(t, doer.requests, 3)
+	assert.Equal(t, request{http.MethodGet, "api/v1/group/admins%2Feu", "application/json", nil}, doer.requests[0])
+
+	assert.Equal(t, http.MethodPut, doer.requests[1].method)
+	assert.Equal(t, "api/v1/group/admins", doer.requests[1].path)
+	assert.JSONEq(t, doer.body, string(doer.requests[1].send))
+
+	assert.Equal(t, http.MethodPost, doer.requests[2].method)
+	assert.Equal(t, "api/v1/group/admins/clone/admins2", doer.requests[2].path)
+}
@@ -0,0 +1,228 @@
+// Code generated by lenses-openapi-gen. DO NOT EDIT.
+
+package gen
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+)
+
+// Broker is a broker of the Kafka cluster.
+type Broker struct {
+	Host string `json:"host" yaml:"host"`
+	ID   int    `json:"id" yaml:"id"`
+	Port int    `json:"port" yaml:"port"`
+	Rack string `json:"rack,omitempty" yaml:"rack,omitempty"`
+}
+
+// Group is a group of users and service accounts and their permissions.
+type Group struct {
+	AdminPermissions           []string    `json:"adminPermissions,omitempty" yaml:"adminPermissions,omitempty"`
+	ConnectClustersPermissions []string    `json:"connectClustersPermissions,omitempty" yaml:"connectClustersPermissions,omitempty"`
+	Description                string      `json:"description,omitempty" yaml:"description,omitempty"`
+	Name                       string      `json:"name" yaml:"name"`
+	Namespaces                 []Namespace `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+	ScopedPermissions          []string    `json:"scopedPermissions,omitempty" yaml:"scopedPermissions,omitempty"`
+	ServiceAccounts            int         `json:"serviceAccounts,omitempty" yaml:"serviceAccounts,omitempty"`
+	UserAccounts               int         `json:"userAccounts,omitempty" yaml:"userAccounts,omitempty"`
+}
+
+// KafkaCluster describes the brokers of the Kafka cluster and its controller.
+type KafkaCluster struct {
+	Brokers []Broker `json:"brokers" yaml:"brokers"`
+	// The broker ID of the controller, -1 if there is no active controller.
+	Controller int `json:"controller" yaml:"controller"`
+}
+
+// Namespace is a data namespace of a group, the permissions of the datasets matched by its wildcards.
+type Namespace struct {
+	Instance    string   `json:"instance" yaml:"instance"`
+	Permissions []string `json:"permissions" yaml:"permissions"`
+	System      string   `json:"system" yaml:"system"`
+	Wildcards   []string `json:"wildcards" yaml:"wildcards"`
+}
+
+// ServiceAccount is a service account, the identity of an application.
+type ServiceAccount struct {
+	Groups []string `json:"groups" yaml:"groups"`
+	Name   string   `json:"name" yaml:"name"`
+	Owner  string   `json:"owner,omitempty" yaml:"owner,omitempty"`
+	Token  string   `json:"token,omitempty" yaml:"token,omitempty"`
+}
+
+// ServiceAccountToken is the token of a service account.
+type ServiceAccountToken struct {
+	Token string `json:"token,omitempty" yaml:"token,omitempty"`
+}
+
+// GetGroups returns the groups.
+func (c *Client) GetGroups() (result []Group, err error) {
+	path := "api/v1/group"
+
+	resp, err := c.Do(http.MethodGet, path, "application/json", nil)
+	if err != nil {
+		return
+	}
+
+	err = c.ReadJSON(resp, &result)
+	return
+}
+
+// CreateGroup creates a group.
+func (c *Client) CreateGroup(body Group) error {
+	path := "api/v1/group"
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.Do(http.MethodPost, path, "application/json", payload)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// GetGroup returns a group by its name.
+func (c *Client) GetGroup(name string) (result Group, err error) {
+	path := "api/v1/group/" + url.PathEscape(name)
+
+	resp, err := c.Do(http.MethodGet, path, "application/json", nil)
+	if err != nil {
+		return
+	}
+
+	err = c.ReadJSON(resp, &result)
+	return
+}
+
+// UpdateGroup updates a group.
+func (c *Client) UpdateGroup(name string, body Group) error {
+	path := "api/v1/group/" + url.PathEscape(name)
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.Do(http.MethodPut, path, "application/json", payload)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// DeleteGroup deletes a group.
+func (c *Client) DeleteGroup(name string) error {
+	path := "api/v1/group/" + url.PathEscape(name)
+
+	resp, err := c.Do(http.MethodDelete, path, "application/json", nil)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// CloneGroup clones a group to a new one.
+func (c *Client) CloneGroup(name string, cloneName string) error {
+	path := "api/v1/group/" + url.PathEscape(name) + "/clone/" + url.PathEscape(cloneName)
+
+	resp, err := c.Do(http.MethodPost, path, "application/json", nil)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// GetKafkaCluster returns the live brokers of the Kafka cluster and its controller.
+func (c *Client) GetKafkaCluster() (result KafkaCluster, err error) {
+	path := "api/v1/kafka/cluster"
+
+	resp, err := c.Do(http.MethodGet, path, "application/json", nil)
+	if err != nil {
+		return
+	}
+
+	err = c.ReadJSON(resp, &result)
+	return
+}
+
+// GetServiceAccounts returns the service accounts.
+func (c *Client) GetServiceAccounts() (result []ServiceAccount, err error) {
+	path := "api/v1/serviceaccount"
+
+	resp, err := c.Do(http.MethodGet, path, "application/json", nil)
+	if err != nil {
+		return
+	}
+
+	err = c.ReadJSON(resp, &result)
+	return
+}
+
+// CreateServiceAccount creates a service account and returns its token.
+func (c *Client) CreateServiceAccount(body ServiceAccount) (result ServiceAccountToken, err error) {
+	path := "api/v1/serviceaccount"
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return
+	}
+
+	resp, err := c.Do(http.MethodPost, path, "application/json", payload)
+	if err != nil {
+		return
+	}
+
+	err = c.ReadJSON(resp, &result)
+	return
+}
+
+// GetServiceAccount returns a service account by its name.
+func (c *Client) GetServiceAccount(name string) (result ServiceAccount, err error) {
+	path := "api/v1/serviceaccount/" + url.PathEscape(name)
+
+	resp, err := c.Do(http.MethodGet, path, "application/json", nil)
+	if err != nil {
+		return
+	}
+
+	err = c.ReadJSON(resp, &result)
+	return
+}
+
+// DeleteServiceAccount deletes a service account.
+func (c *Client) DeleteServiceAccount(name string) error {
+	path := "api/v1/serviceaccount/" + url.PathEscape(name)
+
+	resp, err := c.Do(http.MethodDelete, path, "application/json", nil)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// RevokeServiceAccountToken revokes the token of a service account and returns the new one, the given one or a random one if it's empty.
+func (c *Client) RevokeServiceAccountToken(name string, body ServiceAccountToken) (result ServiceAccountToken, err error) {
+	path := "api/v1/serviceaccount/" + url.PathEscape(name) + "/revoke"
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return
+	}
+
+	resp, err := c.Do(http.MethodPut, path, "application/json", payload)
+	if err != nil {
+		return
+	}
+
+	err = c.ReadJSON(resp, &result)
+	return
+}
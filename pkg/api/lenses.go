@@ -26,7 +26,7 @@ func getTimeout(httpClient *http.Client, timeoutStr string) time.Duration {
 	return httpClient.Timeout
 }
 
-func getTransportLayer(httpClient *http.Client, timeout time.Duration, insecure bool) (t http.RoundTripper) {
+func getTransportLayer(httpClient *http.Client, timeout time.Duration, tlsConfig *tls.Config) (t http.RoundTripper) {
 	if t := httpClient.Transport; t != nil {
 		return t
 	}
@@ -36,8 +36,8 @@ func getTransportLayer(httpClient *http.Client, timeout time.Duration, insecure
 		TLSNextProto: make(map[string]func(authority string, c *tls.Conn) http.RoundTripper),
 	}
 
-	if insecure {
-		httpTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	if tlsConfig != nil {
+		httpTransport.TLSClientConfig = tlsConfig
 	}
 
 	if timeout > 0 {
@@ -59,7 +59,9 @@ func UsingClient(httpClient *http.Client) ConnectionOption {
 		// config's timeout has priority if the httpClient passed has smaller or not-seted timeout.
 		timeout := getTimeout(httpClient, c.Config.Timeout)
 
-		transport := getTransportLayer(httpClient, timeout, c.Config.Insecure)
+		// an invalid TLS configuration fails the `OpenConnection`.
+		tlsConfig, _ := c.Config.TLSConfig()
+		transport := getTransportLayer(httpClient, timeout, tlsConfig)
 		httpClient.Transport = transport
 
 		c.client = httpClient
@@ -78,6 +80,24 @@ func UsingToken(tok string) ConnectionOption {
 	}
 }
 
+// UsingCachedToken sets the token of a previous session, so `OpenConnection` does not authenticate again.
+// When the token is expired, the client renews it once through the `ClientConfig#Authentication`,
+// calls the "onRefresh" with the new token and retries the request.
+func UsingCachedToken(tok string, onRefresh func(token string)) ConnectionOption {
+	return func(c *Client) {
+		c.onTokenRefresh = onRefresh
+		UsingToken(tok)(c)
+	}
+}
+
+// UsingUserAgent sends the "userAgent" as the User-Agent header of all the requests of the client,
+// i.e the name and version of an application built on the client, instead of the default `UserAgent`.
+func UsingUserAgent(userAgent string) ConnectionOption {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
 // WithContext sets the current context, the environment to load configuration from.
 //
 // See the `Config` structure and the `OpenConnection` function for more.
@@ -124,6 +144,10 @@ func OpenConnection(cfg ClientConfig, options ...ConnectionOption) (*Client, err
 		return nil, fmt.Errorf("invalid configuration: Token or Authentication missing")
 	}
 
+	if _, err := clientConfig.TLSConfig(); err != nil {
+		return nil, err
+	}
+
 	// if client is not set-ed by any option, set it to a new one,
 	// a good idea could be to use the `http.DefaultClient`
 	// but this has some limitations so we start with a new, to be clear and simple.
@@ -132,6 +156,20 @@ func OpenConnection(cfg ClientConfig, options ...ConnectionOption) (*Client, err
 		UsingClient(httpClient)(c)
 	}
 
+	if c.transportOptions != nil {
+		c.transportOptions.apply(c.client)
+	}
+
+	if c.debugOut != nil {
+		// dump what goes on the wire, the cached responses are not.
+		c.client.Transport = &debugTransport{next: c.client.Transport, out: c.debugOut}
+	}
+
+	if c.cache != nil {
+		c.cache.next = c.client.Transport
+		c.client.Transport = c.cache
+	}
+
 	if clientConfig.Debug {
 		golog.SetLevel("debug")
 	}
@@ -144,11 +182,14 @@ func OpenConnection(cfg ClientConfig, options ...ConnectionOption) (*Client, err
 	}
 
 	if clientConfig.Authentication == nil {
-		return nil, fmt.Errorf("client: auth failure: authenticator missing")
+		return nil, fmt.Errorf("client: %w: authenticator missing", ErrAuthFailed)
 	}
 
 	if err := clientConfig.Authentication.Auth(c); err != nil {
-		return nil, fmt.Errorf("client: auth failure: [%v]", err)
+		if isAuthRejected(err) {
+			return nil, AuthError{Err: err}
+		}
+		return nil, fmt.Errorf("client: %w", err)
 	}
 
 	if c.User.Token == "" { // this should never happen.
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsingCachedTokenRefresh(t *testing.T) {
+	var logins int
+	issued, valid := "fresh", "fresh"
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/login", func(w http.ResponseWriter, r *http.Request) {
+		logins++
+		w.Write([]byte(issued))
+	})
+	mux.HandleFunc("/api/auth", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(User{Name: "admin", Token: r.Header.Get(xKafkaLensesTokenHeaderKey)})
+	})
+	mux.HandleFunc("/api/echo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(xKafkaLensesTokenHeaderKey) != valid {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		b, _ := ioutil.ReadAll(r.Body)
+		w.Write(b)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	var refreshed []string
+	cfg := ClientConfig{Host: srv.URL, Authentication: BasicAuthentication{Username: "admin", Password: "admin"}}
+	client, err := OpenConnection(cfg, UsingCachedToken("expired", func(token string) {
+		refreshed = append(refreshed, token)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 0, logins, "the cached token should be used without a login")
+
+	resp, err := client.Do(http.MethodPost, "api/echo", contentTypeJSON, []byte(`{"a":1}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := client.ReadResponseBody(resp)
+	resp.Body.Close()
+
+	assert.Equal(t, `{"a":1}`, string(b))
+	assert.Equal(t, 1, logins)
+	assert.Equal(t, []string{"fresh"}, refreshed)
+	assert.Equal(t, "fresh", client.Config.Token)
+
+	// the renewed token is rejected too, no more retries.
+	issued, valid = "stale", "other"
+	_, err = client.Do(http.MethodGet, "api/echo", "", nil)
+	assert.Equal(t, ErrCredentialsMissing, err)
+	assert.Equal(t, 2, logins)
+}
+
+func TestUsingCachedTokenRefreshConcurrent(t *testing.T) {
+	var logins int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/login", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&logins, 1)
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte("fresh"))
+	})
+	mux.HandleFunc("/api/auth", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(User{Name: "admin", Token: r.Header.Get(xKafkaLensesTokenHeaderKey)})
+	})
+	mux.HandleFunc("/api/echo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(xKafkaLensesTokenHeaderKey) != "fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	cfg := ClientConfig{Host: srv.URL, Authentication: BasicAuthentication{Username: "admin", Password: "admin"}}
+	client, err := OpenConnection(cfg, UsingCachedToken("expired", func(token string) {}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the requests read the token while one of them renews it.
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			time.Sleep(time.Duration(i) * 5 * time.Millisecond)
+			if _, err := client.Do(http.MethodGet, "api/echo", "", nil); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&logins))
+	assert.Equal(t, "fresh", client.GetAccessToken())
+}
+
+func TestOpenConnectionWithoutCachedTokenRefresh(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	client, err := OpenConnection(ClientConfig{Host: srv.URL, Token: "expired"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.Do(http.MethodGet, "api/echo", "", nil)
+	assert.Equal(t, ErrCredentialsMissing, err)
+}
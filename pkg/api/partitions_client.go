@@ -0,0 +1,97 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// The `TopicPartition.Leader` of the partitions without a leader or of an unknown one.
+const (
+	// LeaderNone is the leader of an offline partition, as Kafka reports it.
+	LeaderNone = -1
+	// LeaderUnknown is the leader of a partition when the server does not report the partitions' replicas.
+	LeaderUnknown = -2
+)
+
+const topicPartitionsPath = topicPath + "/partitions"
+
+// TopicPartition describes a partition of a topic, its replicas, its offsets and its size, see `GetTopicPartitions`.
+type TopicPartition struct {
+	Partition      int   `json:"partition" yaml:"partition"`
+	Leader         int   `json:"leader" yaml:"leader"`
+	Replicas       []int `json:"replicas" yaml:"replicas"`
+	InSyncReplicas []int `json:"isr" yaml:"isr"`
+	Begin          int64 `json:"begin" yaml:"begin"`
+	End            int64 `json:"end" yaml:"end"`
+	Messages       int64 `json:"messages" yaml:"messages"`
+	// Bytes is the size on disk of the partition's leader replica, zero if unknown.
+	Bytes int64 `json:"bytes" yaml:"bytes"`
+}
+
+// Offline reports whether the partition has no leader.
+func (p TopicPartition) Offline() bool {
+	return p.Leader == LeaderNone
+}
+
+// UnderReplicated reports whether some of the replicas of the partition are not in sync with its leader,
+// it's always false if the replicas are unknown.
+func (p TopicPartition) UnderReplicated() bool {
+	return len(p.Replicas) > 0 && len(p.InSyncReplicas) < len(p.Replicas)
+}
+
+// OutOfSyncReplicas returns the replicas of the partition which are not in sync with its leader.
+func (p TopicPartition) OutOfSyncReplicas() []int {
+	inSync := make(map[int]bool, len(p.InSyncReplicas))
+	for _, id := range p.InSyncReplicas {
+		inSync[id] = true
+	}
+
+	var outOfSync []int
+	for _, id := range p.Replicas {
+		if !inSync[id] {
+			outOfSync = append(outOfSync, id)
+		}
+	}
+
+	return outOfSync
+}
+
+// GetTopicPartitions returns the partitions of the topic, their leader, replicas, offsets and size.
+// The servers which do not report the partitions' replicas return only their offsets and messages,
+// with a `LeaderUnknown` leader.
+func (c *Client) GetTopicPartitions(topicName string) ([]TopicPartition, error) {
+	if topicName == "" {
+		return nil, errRequired("topicName")
+	}
+
+	resp, err := c.Do(http.MethodGet, fmt.Sprintf(topicPartitionsPath, topicName), "", nil)
+	if err == nil {
+		var partitions []TopicPartition
+		err = c.ReadJSON(resp, &partitions)
+		return partitions, err
+	}
+
+	var resErr ResourceError
+	if !errors.As(err, &resErr) || resErr.StatusCode != http.StatusNotFound {
+		return nil, err
+	}
+
+	topic, err := c.GetTopic(topicName)
+	if err != nil {
+		return nil, err
+	}
+
+	partitions := make([]TopicPartition, 0, len(topic.MessagesPerPartition))
+	for _, p := range topic.MessagesPerPartition {
+		partitions = append(partitions, TopicPartition{
+			Partition: p.Partition,
+			Leader:    LeaderUnknown,
+			Begin:     p.Begin,
+			End:       p.End,
+			Messages:  p.Messages,
+		})
+	}
+
+	return partitions, nil
+}
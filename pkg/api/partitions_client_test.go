@@ -0,0 +1,50 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTopicPartitions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/topics/payments/partitions":
+			w.Write([]byte(`[
+				{"partition":0,"leader":1,"replicas":[1,2,3],"isr":[1,2,3],"begin":0,"end":100,"messages":100,"bytes":2048},
+				{"partition":1,"leader":2,"replicas":[2,3,1],"isr":[2],"begin":10,"end":50,"messages":40,"bytes":1024},
+				{"partition":2,"leader":-1,"replicas":[3,1,2],"isr":[],"begin":0,"end":0,"messages":0}
+			]`))
+		case "/api/topics/legacy":
+			w.Write([]byte(`{"topicName":"legacy","messagesPerPartition":[{"partition":0,"messages":5,"begin":1,"end":6}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := OpenConnection(ClientConfig{Host: srv.URL, Token: "t0ken"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	partitions, err := client.GetTopicPartitions("payments")
+	assert.NoError(t, err)
+	assert.Len(t, partitions, 3)
+	assert.False(t, partitions[0].UnderReplicated())
+	assert.True(t, partitions[1].UnderReplicated())
+	assert.Equal(t, []int{3, 1}, partitions[1].OutOfSyncReplicas())
+	assert.True(t, partitions[2].Offline())
+	assert.False(t, partitions[1].Offline())
+
+	partitions, err = client.GetTopicPartitions("legacy")
+	assert.NoError(t, err)
+	assert.Equal(t, []TopicPartition{{Partition: 0, Leader: LeaderUnknown, Begin: 1, End: 6, Messages: 5}}, partitions)
+	assert.False(t, partitions[0].UnderReplicated())
+	assert.False(t, partitions[0].Offline())
+
+	_, err = client.GetTopicPartitions("missing")
+	assert.Error(t, err)
+}
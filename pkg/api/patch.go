@@ -0,0 +1,322 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MergePatch applies the "patch" to the "target" with the JSON merge patch semantics of the RFC 7386,
+// a null value removes the key, an object is merged to the object of the same key and any other value replaces it.
+// The "target" is not modified, a new map is returned.
+func MergePatch(target, patch map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(target)+len(patch))
+	for k, v := range target {
+		out[k] = v
+	}
+
+	for k, v := range patch {
+		if v == nil {
+			delete(out, k)
+			continue
+		}
+
+		if p, ok := v.(map[string]interface{}); ok {
+			t, _ := out[k].(map[string]interface{})
+			out[k] = MergePatch(t, p)
+			continue
+		}
+
+		out[k] = v
+	}
+
+	return out
+}
+
+// configValue returns the string form of a config value of a patch,
+// numbers are written in full, i.e 86400000 and not 8.64e+07.
+func configValue(v interface{}) string {
+	switch value := v.(type) {
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// PatchTopicConfig changes only the configs of the "patch" of a topic, the rest of its overrides are kept.
+// A null value resets the config to its default.
+//
+// It reads the current overrides of the topic, merges the "patch" and sends them back, see `MergePatch`,
+// and returns the overrides after the change.
+func (c *Client) PatchTopicConfig(topicName string, patch KV) (KV, error) {
+	if topicName == "" {
+		return nil, errRequired("topicName")
+	}
+
+	topic, err := c.GetTopic(topicName)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(KV)
+	defaults := make(KV)
+	for _, kv := range topic.Configs {
+		name, _ := kv["name"].(string)
+		if name == "" {
+			continue
+		}
+
+		if v, ok := kv["defaultValue"]; ok && v != nil {
+			defaults[name] = v
+		}
+
+		if isDefault, _ := kv["isDefault"].(bool); !isDefault {
+			v, ok := kv["originalValue"]
+			if !ok {
+				v = kv["value"]
+			}
+			current[name] = v
+		}
+	}
+
+	merged := KV(MergePatch(current, patch))
+
+	update := make(KV, len(merged))
+	for k, v := range merged {
+		update[k] = configValue(v)
+	}
+
+	var unknown []string
+	for k, v := range patch {
+		if _, overridden := current[k]; v != nil || !overridden {
+			continue
+		}
+
+		def, ok := defaults[k]
+		if !ok {
+			unknown = append(unknown, k)
+			continue
+		}
+		update[k] = configValue(def)
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unable to reset topic configs [%s], their default values are unknown", strings.Join(unknown, ", "))
+	}
+
+	if err = c.UpdateTopicConfig(topicName, []KV{update}); err != nil {
+		return nil, err
+	}
+
+	return merged, nil
+}
+
+// PatchConnectorConfig changes only the configs of the "patch" of a connector, the rest of its config is kept.
+// A null value removes the config.
+//
+// It reads the current config of the connector, merges the "patch" and sends it back, see `MergePatch`,
+// and returns the config after the change.
+func (c *Client) PatchConnectorConfig(clusterName, name string, patch ConnectorConfig) (ConnectorConfig, error) {
+	connector, err := c.GetConnector(clusterName, name)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := ConnectorConfig(MergePatch(connector.Config, patch))
+	if _, err = c.UpdateConnector(clusterName, name, merged); err != nil {
+		return nil, err
+	}
+
+	return merged, nil
+}
+
+// quotaConfigKeys maps the keys of a quota patch, the config names of Kafka or the fields of the manifests,
+// to the fields of the `QuotaConfig`.
+var quotaConfigKeys = map[string]string{
+	"producer_byte_rate": "producer_byte_rate",
+	"producerByteRate":   "producer_byte_rate",
+	"consumer_byte_rate": "consumer_byte_rate",
+	"consumerByteRate":   "consumer_byte_rate",
+	"request_percentage": "request_percentage",
+	"requestPercentage":  "request_percentage",
+}
+
+func (cfg QuotaConfig) get(key string) string {
+	switch key {
+	case "producer_byte_rate":
+		return cfg.ProducerByteRate
+	case "consumer_byte_rate":
+		return cfg.ConsumerByteRate
+	default:
+		return cfg.RequestPercentage
+	}
+}
+
+func (cfg *QuotaConfig) set(key, value string) {
+	switch key {
+	case "producer_byte_rate":
+		cfg.ProducerByteRate = value
+	case "consumer_byte_rate":
+		cfg.ConsumerByteRate = value
+	default:
+		cfg.RequestPercentage = value
+	}
+}
+
+// patchQuota merges the "patch" to the "current" quota config,
+// it returns the config after the change and the properties to remove.
+func patchQuota(current QuotaConfig, patch KV) (QuotaConfig, []string, error) {
+	var (
+		unknown []string
+		remove  []string
+	)
+
+	for k, v := range patch {
+		key, ok := quotaConfigKeys[k]
+		if !ok {
+			unknown = append(unknown, k)
+			continue
+		}
+
+		if v != nil {
+			current.set(key, configValue(v))
+			continue
+		}
+
+		if current.get(key) != "" {
+			current.set(key, "")
+			remove = append(remove, key)
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return current, nil, fmt.Errorf("unknown quota configs [%s], expected producer_byte_rate, consumer_byte_rate or request_percentage", strings.Join(unknown, ", "))
+	}
+
+	sort.Strings(remove)
+	return current, remove, nil
+}
+
+func isAllQuota(s string) bool {
+	return s == "*" || s == "all"
+}
+
+// findQuota returns the config of the quota with the "user" and the "clientID" of the `Quota#GetQuotaAsRequest`.
+func (c *Client) findQuota(user, clientID string) (QuotaConfig, error) {
+	quotas, err := c.GetQuotas()
+	if err != nil {
+		return QuotaConfig{}, err
+	}
+
+	normalize := func(s string) string {
+		if isAllQuota(s) {
+			return "*"
+		}
+		return s
+	}
+
+	for _, q := range quotas {
+		req := q.GetQuotaAsRequest()
+		if normalize(req.User) == normalize(user) && normalize(req.ClientID) == normalize(clientID) {
+			return q.Properties, nil
+		}
+	}
+
+	return QuotaConfig{}, nil
+}
+
+// PatchUsersQuota changes only the configs of the "patch" of a users quota, the rest of its configs are kept.
+// A null value removes the config.
+//
+// An empty "user" means all users, a "clientID" of "*" or "all" means all the clients of the "user".
+// It returns the config after the change.
+func (c *Client) PatchUsersQuota(user, clientID string, patch KV) (QuotaConfig, error) {
+	if user == "" {
+		user = "*"
+	}
+
+	if isAllQuota(user) && clientID != "" {
+		return QuotaConfig{}, fmt.Errorf("a quota for all users can't have a client, use a user instead")
+	}
+
+	current, err := c.findQuota(user, clientID)
+	if err != nil {
+		return QuotaConfig{}, err
+	}
+
+	cfg, remove, err := patchQuota(current, patch)
+	if err != nil {
+		return cfg, err
+	}
+
+	var (
+		update func(QuotaConfig) error
+		del    func(...string) error
+	)
+
+	switch {
+	case isAllQuota(user):
+		update, del = c.CreateOrUpdateQuotaForAllUsers, c.DeleteQuotaForAllUsers
+	case clientID == "":
+		update = func(cfg QuotaConfig) error { return c.CreateOrUpdateQuotaForUser(user, cfg) }
+		del = func(props ...string) error { return c.DeleteQuotaForUser(user, props...) }
+	case isAllQuota(clientID):
+		update = func(cfg QuotaConfig) error { return c.CreateOrUpdateQuotaForUserAllClients(user, cfg) }
+		del = func(props ...string) error { return c.DeleteQuotaForUserAllClients(user, props...) }
+	default:
+		update = func(cfg QuotaConfig) error { return c.CreateOrUpdateQuotaForUserClient(user, clientID, cfg) }
+		del = func(props ...string) error { return c.DeleteQuotaForUserClient(user, clientID, props...) }
+	}
+
+	return cfg, applyQuotaPatch(cfg, remove, update, del)
+}
+
+// PatchClientsQuota changes only the configs of the "patch" of a clients quota, the rest of its configs are kept.
+// A null value removes the config.
+//
+// An empty "clientID", "*" or "all" means all clients.
+// It returns the config after the change.
+func (c *Client) PatchClientsQuota(clientID string, patch KV) (QuotaConfig, error) {
+	if clientID == "" {
+		clientID = "*"
+	}
+
+	current, err := c.findQuota("", clientID)
+	if err != nil {
+		return QuotaConfig{}, err
+	}
+
+	cfg, remove, err := patchQuota(current, patch)
+	if err != nil {
+		return cfg, err
+	}
+
+	if isAllQuota(clientID) {
+		return cfg, applyQuotaPatch(cfg, remove, c.CreateOrUpdateQuotaForAllClients, c.DeleteQuotaForAllClients)
+	}
+
+	return cfg, applyQuotaPatch(cfg, remove,
+		func(cfg QuotaConfig) error { return c.CreateOrUpdateQuotaForClient(clientID, cfg) },
+		func(props ...string) error { return c.DeleteQuotaForClient(clientID, props...) })
+}
+
+// applyQuotaPatch removes the "remove" properties first and then sets the rest of the "cfg", if any.
+func applyQuotaPatch(cfg QuotaConfig, remove []string, update func(QuotaConfig) error, del func(...string) error) error {
+	if len(remove) > 0 {
+		if err := del(remove...); err != nil {
+			return err
+		}
+	}
+
+	if cfg == (QuotaConfig{}) {
+		return nil
+	}
+
+	return update(cfg)
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergePatch(t *testing.T) {
+	target := map[string]interface{}{
+		"a": "1",
+		"b": "2",
+		"c": map[string]interface{}{"d": "3", "e": "4"},
+	}
+
+	got := MergePatch(target, map[string]interface{}{
+		"a": "10",
+		"b": nil,
+		"c": map[string]interface{}{"e": nil, "f": "5"},
+		"g": []interface{}{"6"},
+	})
+
+	assert.Equal(t, map[string]interface{}{
+		"a": "10",
+		"c": map[string]interface{}{"d": "3", "f": "5"},
+		"g": []interface{}{"6"},
+	}, got)
+	assert.Equal(t, "1", target["a"], "the target is not modified")
+}
+
+func TestPatchTopicConfig(t *testing.T) {
+	var sent UpdateConfigs
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			assert.Equal(t, "/api/topics/orders", r.URL.Path)
+			w.Write([]byte(`{"topicName":"orders","config":[
+				{"name":"cleanup.policy","originalValue":"compact","defaultValue":"delete","isDefault":false},
+				{"name":"retention.ms","originalValue":"604800000","defaultValue":"604800000","isDefault":true},
+				{"name":"segment.bytes","originalValue":"1000","isDefault":false}
+			]}`))
+		case http.MethodPut:
+			assert.Equal(t, "/api/configs/topics/orders", r.URL.Path)
+			b, _ := ioutil.ReadAll(r.Body)
+			assert.NoError(t, json.Unmarshal(b, &sent))
+		}
+	}))
+	defer srv.Close()
+
+	client, err := OpenConnection(ClientConfig{Host: srv.URL, Token: "token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := client.PatchTopicConfig("orders", KV{"retention.ms": float64(86400000), "cleanup.policy": nil})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, KV{"retention.ms": float64(86400000), "segment.bytes": "1000"}, got)
+	assert.ElementsMatch(t, []KeyVal{
+		{Key: "retention.ms", Value: "86400000"},
+		{Key: "segment.bytes", Value: "1000"},
+		{Key: "cleanup.policy", Value: "delete"},
+	}, sent.Configs)
+
+	_, err = client.PatchTopicConfig("orders", KV{"segment.bytes": nil})
+	assert.EqualError(t, err, "unable to reset topic configs [segment.bytes], their default values are unknown")
+}
+
+func TestPatchUsersQuota(t *testing.T) {
+	var (
+		deleted []string
+		updated QuotaConfig
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/quotas":
+			w.Write([]byte(`[{"entityName":"bob","entityType":"USER","properties":{"producer_byte_rate":"100","request_percentage":"75"}}]`))
+		case r.Method == http.MethodDelete:
+			assert.Equal(t, "/api/quotas/users/bob", r.URL.Path)
+			b, _ := ioutil.ReadAll(r.Body)
+			assert.NoError(t, json.Unmarshal(b, &deleted))
+		case r.Method == http.MethodPut:
+			assert.Equal(t, "/api/quotas/users/bob", r.URL.Path)
+			b, _ := ioutil.ReadAll(r.Body)
+			assert.NoError(t, json.Unmarshal(b, &updated))
+		default:
+			t.Errorf("unexpected request [%s %s]", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := OpenConnection(ClientConfig{Host: srv.URL, Token: "token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := client.PatchUsersQuota("bob", "", KV{"consumerByteRate": float64(200), "request_percentage": nil})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	want := QuotaConfig{ProducerByteRate: "100", ConsumerByteRate: "200"}
+	assert.Equal(t, want, got)
+	assert.Equal(t, want, updated)
+	assert.Equal(t, []string{"request_percentage"}, deleted)
+
+	_, err = client.PatchUsersQuota("bob", "", KV{"byte_rate": "1"})
+	assert.EqualError(t, err, "unknown quota configs [byte_rate], expected producer_byte_rate, consumer_byte_rate or request_percentage")
+}
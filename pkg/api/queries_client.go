@@ -0,0 +1,67 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const savedQueriesPath = "api/v1/sql/queries"
+
+// SavedQuery is a named Lenses SQL query, shared by the users of a Lenses server or stored locally per context.
+type SavedQuery struct {
+	Name        string `json:"name" yaml:"name" header:"Name"`
+	SQL         string `json:"sql" yaml:"sql" header:"SQL"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty" header:"Description"`
+}
+
+// GetSavedQueries returns the saved queries of the Lenses server.
+func (c *Client) GetSavedQueries() (queries []SavedQuery, err error) {
+	resp, err := c.Do(http.MethodGet, savedQueriesPath, "", nil)
+	if err != nil {
+		return
+	}
+
+	err = c.ReadJSON(resp, &queries)
+	return
+}
+
+// SaveQuery creates or replaces the saved query of the same name on the Lenses server.
+func (c *Client) SaveQuery(query SavedQuery) error {
+	if query.Name == "" {
+		return errRequired("Name")
+	}
+
+	if query.SQL == "" {
+		return errRequired("SQL")
+	}
+
+	send, err := json.Marshal(query)
+	if err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("%s/%s", savedQueriesPath, url.PathEscape(query.Name))
+	resp, err := c.Do(http.MethodPut, path, contentTypeJSON, send)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// DeleteSavedQuery deletes the saved query of the "name" from the Lenses server.
+func (c *Client) DeleteSavedQuery(name string) error {
+	if name == "" {
+		return errRequired("name")
+	}
+
+	path := fmt.Sprintf("%s/%s", savedQueriesPath, url.PathEscape(name))
+	resp, err := c.Do(http.MethodDelete, path, "", nil)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSavedQueries(t *testing.T) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.EscapedPath()+" "+string(body))
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`[{"name":"orders missing payment","sql":"SELECT * FROM orders"}]`))
+		}
+	}))
+	defer srv.Close()
+
+	client, err := OpenConnection(ClientConfig{Host: srv.URL, Token: "t0ken"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query := SavedQuery{Name: "orders missing payment", SQL: "SELECT * FROM orders"}
+	assert.NoError(t, client.SaveQuery(query))
+	queries, err := client.GetSavedQueries()
+	assert.NoError(t, err)
+	assert.Equal(t, []SavedQuery{query}, queries)
+	assert.NoError(t, client.DeleteSavedQuery(query.Name))
+
+	assert.Equal(t, []string{
+		`PUT /api/v1/sql/queries/orders%20missing%20payment {"name":"orders missing payment","sql":"SELECT * FROM orders"}`,
+		`GET /api/v1/sql/queries `,
+		`DELETE /api/v1/sql/queries/orders%20missing%20payment `,
+	}, requests)
+
+	assert.EqualError(t, client.SaveQuery(SavedQuery{Name: "empty"}), "client: [SQL] is required")
+}
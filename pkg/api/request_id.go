@@ -0,0 +1,32 @@
+package api
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// RequestIDHeader is the header which carries the correlation id of a request,
+// so a failure can be cross-referenced with the Lenses server logs.
+const RequestIDHeader = "X-Request-Id"
+
+// NewRequestID returns a new, random, correlation id in the UUID (version 4) form.
+func NewRequestID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		// should never happen, the id is just a correlation hint anyway.
+		return ""
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4.
+	b[8] = (b[8] & 0x3f) | 0x80 // variant 10.
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// UsingRequestID sends the "id" as the `RequestIDHeader` of all the requests of the client,
+// i.e the id of a CLI invocation, instead of a new one per request.
+func UsingRequestID(id string) ConnectionOption {
+	return func(c *Client) {
+		c.requestID = id
+	}
+}
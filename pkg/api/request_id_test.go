@@ -0,0 +1,53 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRequestID(t *testing.T) {
+	id := NewRequestID()
+	assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), id)
+	assert.NotEqual(t, id, NewRequestID())
+}
+
+func TestRequestID(t *testing.T) {
+	var ids []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ids = append(ids, r.Header.Get(RequestIDHeader))
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("Boom"))
+	}))
+	defer srv.Close()
+
+	// a new one per request.
+	client, err := OpenConnection(ClientConfig{Host: srv.URL, Token: "t0ken"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.GetTopics()
+	var resErr ResourceError
+	if assert.True(t, errors.As(err, &resErr)) {
+		assert.Equal(t, ids[0], resErr.RequestID)
+		assert.Contains(t, resErr.String(), "request id ["+ids[0]+"]")
+	}
+	client.GetTopics()
+	assert.NotEmpty(t, ids[0])
+	assert.NotEqual(t, ids[0], ids[1])
+
+	// the same for all of them.
+	ids = nil
+	client, err = OpenConnection(ClientConfig{Host: srv.URL, Token: "t0ken"}, UsingRequestID("invocation-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.GetTopics()
+	client.GetTopics()
+	assert.Equal(t, []string{"invocation-1", "invocation-1"}, ids)
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/pkg/errors"
 )
@@ -244,3 +246,239 @@ func (c *Client) RemoveSchema(name string) (err error) {
 
 	return
 }
+
+//SchemaCompatibilityReq Struct
+type SchemaCompatibilityReq struct {
+	Schema     string `json:"schema"`
+	SchemaType string `json:"schemaType,omitempty"`
+}
+
+//SchemaCompatibilityRes Struct
+type SchemaCompatibilityRes struct {
+	IsCompatible bool     `json:"is_compatible"`
+	Messages     []string `json:"messages,omitempty"`
+}
+
+//CheckSchemaCompatibility tests the schema of the "request" against the latest version of the subject of the "name",
+//through the Schema Registry's compatibility endpoint, without registering it
+func (c *Client) CheckSchemaCompatibility(name string, request WriteSchemaReq) (response SchemaCompatibilityRes, err error) {
+	const basePath = "api/proxy-sr/compatibility/subjects"
+	path := fmt.Sprintf("%s/%s/versions/latest?verbose=true", basePath, url.PathEscape(name))
+
+	if name == "" {
+		err = fmt.Errorf("name is required")
+		return
+	}
+
+	if request.Schema == "" {
+		err = fmt.Errorf("schema is required")
+		return
+	}
+
+	payload := SchemaCompatibilityReq{Schema: request.Schema}
+	// the registry defaults to AVRO and older versions reject the schema type.
+	if format := strings.ToUpper(request.Format); format != "" && format != "AVRO" {
+		payload.SchemaType = format
+	}
+
+	send, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+
+	resp, err := c.Do(http.MethodPost, path, contentTypeJSON, send)
+	if err != nil {
+		return
+	}
+
+	err = c.ReadJSON(resp, &response)
+	return
+}
+
+// The modes of the Schema Registry, see `SetSchemaRegistryMode`.
+const (
+	SchemaRegistryModeImport    = "IMPORT"
+	SchemaRegistryModeReadOnly  = "READONLY"
+	SchemaRegistryModeReadWrite = "READWRITE"
+)
+
+const schemaRegistryProxyPath = "api/proxy-sr"
+
+//SchemaRegistryMode Struct
+type SchemaRegistryMode struct {
+	Mode string `json:"mode" yaml:"mode" header:"Mode"`
+}
+
+//schemaRegistryPath returns the proxied registry path of the "resource", i.e "mode", of the subject of the "name", or the global one if empty
+func schemaRegistryPath(resource, name string) string {
+	if name == "" {
+		return fmt.Sprintf("%s/%s", schemaRegistryProxyPath, resource)
+	}
+
+	return fmt.Sprintf("%s/%s/%s", schemaRegistryProxyPath, resource, url.PathEscape(name))
+}
+
+//GetSchemaRegistryMode returns the mode of the subject of the "name", or the global mode of the registry if empty
+func (c *Client) GetSchemaRegistryMode(name string) (mode SchemaRegistryMode, err error) {
+	resp, err := c.Do(http.MethodGet, schemaRegistryPath("mode", name), "", nil)
+	if err != nil {
+		return
+	}
+
+	err = c.ReadJSON(resp, &mode)
+	return
+}
+
+//SetSchemaRegistryMode sets the mode, IMPORT, READONLY or READWRITE, of the subject of the "name", or the global mode of the registry if empty
+func (c *Client) SetSchemaRegistryMode(name string, mode string) (err error) {
+	mode = strings.ToUpper(mode)
+	switch mode {
+	case SchemaRegistryModeImport, SchemaRegistryModeReadOnly, SchemaRegistryModeReadWrite:
+	case "":
+		return fmt.Errorf("mode is required")
+	default:
+		return fmt.Errorf("invalid mode [%s], expected one of [%s, %s, %s]", mode,
+			SchemaRegistryModeImport, SchemaRegistryModeReadOnly, SchemaRegistryModeReadWrite)
+	}
+
+	payload, err := json.Marshal(SchemaRegistryMode{Mode: mode})
+	if err != nil {
+		return
+	}
+
+	resp, err := c.Do(http.MethodPut, schemaRegistryPath("mode", name), contentTypeJSON, payload)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+//SchemaCompatibilityLevel Struct
+type SchemaCompatibilityLevel struct {
+	Compatibility string `json:"compatibilityLevel" yaml:"compatibility" header:"Compatibility"`
+}
+
+//GetGlobalCompatibility returns the default compatibility of the schema registry
+func (c *Client) GetGlobalCompatibility() (level SchemaCompatibilityLevel, err error) {
+	resp, err := c.Do(http.MethodGet, schemaRegistryPath("config", ""), "", nil)
+	if err != nil {
+		return
+	}
+
+	err = c.ReadJSON(resp, &level)
+	return
+}
+
+//HardDeleteSchema permanently removes the schema and all its versions, the registry requires them to be soft removed first,
+//so it removes them, see `RemoveSchema`, if they are not
+func (c *Client) HardDeleteSchema(name string) (err error) {
+	if name == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	path := fmt.Sprintf("%s/subjects/%s", schemaRegistryProxyPath, url.PathEscape(name))
+
+	// the soft removal fails if the subject is already soft removed, which is fine.
+	if resp, err := c.Do(http.MethodDelete, path, "", nil); err == nil {
+		resp.Body.Close()
+	}
+
+	resp, err := c.Do(http.MethodDelete, path+"?permanent=true", "", nil)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+//SchemaReference Struct
+type SchemaReference struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+//SchemaVersion Struct is a version of a subject as the Schema Registry returns it
+type SchemaVersion struct {
+	Subject    string            `json:"subject,omitempty"`
+	Version    int               `json:"version,omitempty"`
+	ID         int               `json:"id,omitempty"`
+	SchemaType string            `json:"schemaType,omitempty"`
+	Schema     string            `json:"schema"`
+	References []SchemaReference `json:"references,omitempty"`
+}
+
+//Format returns the format of the schema, the registry omits the "AVRO" one
+func (v SchemaVersion) Format() string {
+	if v.SchemaType == "" {
+		return "AVRO"
+	}
+
+	return v.SchemaType
+}
+
+//GetSchemaVersions returns the version numbers of the subject of the "name", in ascending order
+func (c *Client) GetSchemaVersions(name string) (versions []int, err error) {
+	if name == "" {
+		err = fmt.Errorf("name is required")
+		return
+	}
+
+	path := fmt.Sprintf("%s/subjects/%s/versions", schemaRegistryProxyPath, url.PathEscape(name))
+	resp, err := c.Do(http.MethodGet, path, "", nil)
+	if err != nil {
+		return
+	}
+
+	err = c.ReadJSON(resp, &versions)
+	return
+}
+
+//GetSchemaVersion returns the "version" of the subject of the "name", with its references
+func (c *Client) GetSchemaVersion(name string, version int) (schema SchemaVersion, err error) {
+	if name == "" {
+		err = fmt.Errorf("name is required")
+		return
+	}
+
+	path := fmt.Sprintf("%s/subjects/%s/versions/%d", schemaRegistryProxyPath, url.PathEscape(name), version)
+	resp, err := c.Do(http.MethodGet, path, "", nil)
+	if err != nil {
+		return
+	}
+
+	err = c.ReadJSON(resp, &schema)
+	return
+}
+
+//RegisterSchemaVersion registers the schema, its type and references, as a new version of the subject of the "name",
+//unless it is registered already, and returns its id
+func (c *Client) RegisterSchemaVersion(name string, schema SchemaVersion) (id int, err error) {
+	if name == "" {
+		err = fmt.Errorf("name is required")
+		return
+	}
+
+	if schema.Schema == "" {
+		err = fmt.Errorf("schema is required")
+		return
+	}
+
+	payload, err := json.Marshal(SchemaVersion{SchemaType: schema.SchemaType, Schema: schema.Schema, References: schema.References})
+	if err != nil {
+		return
+	}
+
+	path := fmt.Sprintf("%s/subjects/%s/versions", schemaRegistryProxyPath, url.PathEscape(name))
+	resp, err := c.Do(http.MethodPost, path, contentTypeJSON, payload)
+	if err != nil {
+		return
+	}
+
+	var registered struct {
+		ID int `json:"id"`
+	}
+	err = c.ReadJSON(resp, &registered)
+	return registered.ID, err
+}
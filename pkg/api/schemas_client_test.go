@@ -0,0 +1,98 @@
+package api
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaRegistryMode(t *testing.T) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.RequestURI()+" "+string(body))
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/proxy-sr/config":
+			w.Write([]byte(`{"compatibilityLevel":"FULL"}`))
+		case r.Method == http.MethodGet:
+			w.Write([]byte(`{"mode":"READONLY"}`))
+		}
+	}))
+	defer srv.Close()
+
+	client, err := OpenConnection(ClientConfig{Host: srv.URL, Token: "t0ken"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mode, err := client.GetSchemaRegistryMode("")
+	assert.NoError(t, err)
+	assert.Equal(t, SchemaRegistryModeReadOnly, mode.Mode)
+	_, err = client.GetSchemaRegistryMode("orders-value")
+	assert.NoError(t, err)
+	assert.NoError(t, client.SetSchemaRegistryMode("", "import"))
+	assert.NoError(t, client.SetSchemaRegistryMode("orders-value", SchemaRegistryModeReadWrite))
+	assert.EqualError(t, client.SetSchemaRegistryMode("", "frozen"), "invalid mode [FROZEN], expected one of [IMPORT, READONLY, READWRITE]")
+
+	level, err := client.GetGlobalCompatibility()
+	assert.NoError(t, err)
+	assert.Equal(t, "FULL", level.Compatibility)
+
+	assert.NoError(t, client.HardDeleteSchema("orders-value"))
+
+	assert.Equal(t, []string{
+		`GET /api/proxy-sr/mode `,
+		`GET /api/proxy-sr/mode/orders-value `,
+		`PUT /api/proxy-sr/mode {"mode":"IMPORT"}`,
+		`PUT /api/proxy-sr/mode/orders-value {"mode":"READWRITE"}`,
+		`GET /api/proxy-sr/config `,
+		`DELETE /api/proxy-sr/subjects/orders-value `,
+		`DELETE /api/proxy-sr/subjects/orders-value?permanent=true `,
+	}, requests)
+}
+
+func TestSchemaVersions(t *testing.T) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.RequestURI()+" "+string(body))
+		switch {
+		case r.Method == http.MethodPost:
+			w.Write([]byte(`{"id":42}`))
+		case r.URL.Path == "/api/proxy-sr/subjects/orders-value/versions":
+			w.Write([]byte(`[1,2]`))
+		default:
+			w.Write([]byte(`{"subject":"orders-value","version":2,"id":7,"schemaType":"PROTOBUF","schema":"syntax = \"proto3\";",` +
+				`"references":[{"name":"common.proto","subject":"common","version":1}]}`))
+		}
+	}))
+	defer srv.Close()
+
+	client, err := OpenConnection(ClientConfig{Host: srv.URL, Token: "t0ken"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	versions, err := client.GetSchemaVersions("orders-value")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, versions)
+
+	version, err := client.GetSchemaVersion("orders-value", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "PROTOBUF", version.Format())
+	assert.Equal(t, []SchemaReference{{Name: "common.proto", Subject: "common", Version: 1}}, version.References)
+	assert.Equal(t, "AVRO", SchemaVersion{}.Format())
+
+	id, err := client.RegisterSchemaVersion("orders-value", version)
+	assert.NoError(t, err)
+	assert.Equal(t, 42, id)
+
+	assert.Equal(t, []string{
+		`GET /api/proxy-sr/subjects/orders-value/versions `,
+		`GET /api/proxy-sr/subjects/orders-value/versions/2 `,
+		`POST /api/proxy-sr/subjects/orders-value/versions {"schemaType":"PROTOBUF","schema":"syntax = \"proto3\";","references":[{"name":"common.proto","subject":"common","version":1}]}`,
+	}, requests)
+}
@@ -0,0 +1,268 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/lensesio/lenses-go/pkg"
+)
+
+// The types of the search results, see `Search`.
+const (
+	SearchTopic     = "topic"
+	SearchIndex     = "index"
+	SearchField     = "field"
+	SearchConnector = "connector"
+	SearchProcessor = "processor"
+)
+
+// SearchTypes are the types of the search results, in the order of their rank on equal scores.
+var SearchTypes = []string{SearchTopic, SearchIndex, SearchField, SearchConnector, SearchProcessor}
+
+// The scores of the search results, by where the term matched.
+const (
+	searchScoreExact       = 100
+	searchScorePrefix      = 75
+	searchScoreContains    = 50
+	searchScoreField       = 40
+	searchScoreDescription = 20
+)
+
+// SearchResult is a resource of the Lenses catalog matching a search term, see `Search`.
+type SearchResult struct {
+	Type     string `json:"type" yaml:"type" header:"Type"`
+	Name     string `json:"name" yaml:"name" header:"Name"`
+	Location string `json:"location" yaml:"location" header:"Location"`
+	Match    string `json:"match" yaml:"match" header:"Match"`
+	Score    int    `json:"score" yaml:"score" header:"Score"`
+}
+
+// SearchOptions are the options of `Search`.
+type SearchOptions struct {
+	// Types limits the results to the given types, see `SearchTypes`, all of them if empty.
+	Types []string
+	// Limit is the maximum results, all of them if zero.
+	Limit int
+}
+
+func (opts SearchOptions) includes(typ string) bool {
+	if len(opts.Types) == 0 {
+		return true
+	}
+
+	for _, t := range opts.Types {
+		if t == typ {
+			return true
+		}
+	}
+
+	return false
+}
+
+// catalogField is a field of a dataset's key or value schema.
+type catalogField struct {
+	Name   string         `json:"name"`
+	Fields []catalogField `json:"fields,omitempty"`
+}
+
+// catalogDataset is a dataset of the datasets' search response.
+type catalogDataset struct {
+	Name           string       `json:"name"`
+	ConnectionName string       `json:"connectionName"`
+	SourceType     string       `json:"sourceType"`
+	Description    string       `json:"description"`
+	Tags           []DatasetTag `json:"tags"`
+	Fields         struct {
+		Key   []catalogField `json:"key"`
+		Value []catalogField `json:"value"`
+	} `json:"fields"`
+}
+
+type catalogDatasets struct {
+	Datasets struct {
+		Values []catalogDataset `json:"values"`
+	} `json:"datasets"`
+}
+
+const searchPageSize = 1000
+
+// searchDatasets returns the datasets the server's catalog matches with the "term".
+func (c *Client) searchDatasets(term string) ([]catalogDataset, error) {
+	path := fmt.Sprintf("api/%s?query=%s&pageSize=%d", pkg.DatasetsAPIPath, url.QueryEscape(term), searchPageSize)
+	resp, err := c.Do(http.MethodGet, path, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var res catalogDatasets
+	if err = c.ReadJSON(resp, &res); err != nil {
+		return nil, err
+	}
+
+	return res.Datasets.Values, nil
+}
+
+// searchScore returns the score of the "name" for the lower case "term", zero if it does not match.
+func searchScore(name, term string) int {
+	name = strings.ToLower(name)
+	switch {
+	case name == term:
+		return searchScoreExact
+	case strings.HasPrefix(name, term):
+		return searchScorePrefix
+	case strings.Contains(name, term):
+		return searchScoreContains
+	default:
+		return 0
+	}
+}
+
+// searchFields appends the fields, and their nested ones, which match the lower case "term".
+func searchFields(results []SearchResult, fields []catalogField, parent, location, term string) []SearchResult {
+	for _, field := range fields {
+		path := parent + "." + field.Name
+		if score := searchScore(field.Name, term); score > 0 {
+			results = append(results, SearchResult{
+				Type:     SearchField,
+				Name:     path,
+				Location: location,
+				Match:    "field",
+				Score:    score * searchScoreField / searchScoreExact,
+			})
+		}
+		results = searchFields(results, field.Fields, path, location, term)
+	}
+
+	return results
+}
+
+// searchDataset returns the results of the "dataset" for the lower case "term".
+func searchDataset(dataset catalogDataset, term string, opts SearchOptions) (results []SearchResult) {
+	typ := SearchTopic
+	if dataset.ConnectionName != KafkaConnection {
+		typ = SearchIndex
+	}
+	location := dataset.ConnectionName + "/" + dataset.Name
+
+	if opts.includes(typ) {
+		result := SearchResult{Type: typ, Name: dataset.Name, Location: location, Match: "name", Score: searchScore(dataset.Name, term)}
+		if result.Score == 0 {
+			for _, tag := range dataset.Tags {
+				if searchScore(tag.Name, term) > 0 {
+					result.Match, result.Score = "tag", searchScoreDescription
+					break
+				}
+			}
+		}
+		if result.Score == 0 && strings.Contains(strings.ToLower(dataset.Description), term) {
+			result.Match, result.Score = "description", searchScoreDescription
+		}
+		if result.Score > 0 {
+			results = append(results, result)
+		}
+	}
+
+	if opts.includes(SearchField) {
+		results = searchFields(results, dataset.Fields.Key, "_key", location, term)
+		results = searchFields(results, dataset.Fields.Value, "_value", location, term)
+	}
+
+	return
+}
+
+// Search searches the Lenses catalog for the topics, the Elasticsearch indexes, the fields of their schemas,
+// the connectors and the processors which match the "term", case-insensitive,
+// and returns them ranked by where the term matched, the exact names first.
+//
+// The datasets are searched by the server, the connectors and the processors by their names.
+func (c *Client) Search(term string, opts SearchOptions) ([]SearchResult, error) {
+	term = strings.ToLower(strings.TrimSpace(term))
+	if term == "" {
+		return nil, errRequired("term")
+	}
+
+	var results []SearchResult
+
+	if opts.includes(SearchTopic) || opts.includes(SearchIndex) || opts.includes(SearchField) {
+		datasets, err := c.searchDatasets(term)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, dataset := range datasets {
+			results = append(results, searchDataset(dataset, term, opts)...)
+		}
+	}
+
+	if opts.includes(SearchConnector) {
+		clusters, err := c.GetConnectClusters()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, cluster := range clusters {
+			names, err := c.GetConnectors(cluster)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, name := range names {
+				if score := searchScore(name, term); score > 0 {
+					results = append(results, SearchResult{Type: SearchConnector, Name: name, Location: "connect/" + cluster, Match: "name", Score: score})
+				}
+			}
+		}
+	}
+
+	if opts.includes(SearchProcessor) {
+		processors, err := c.GetProcessors()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, processor := range processors.Streams {
+			if score := searchScore(processor.Name, term); score > 0 {
+				location := "processors"
+				if processor.ClusterName != "" {
+					location += "/" + processor.ClusterName
+				}
+				if processor.Namespace != "" {
+					location += "/" + processor.Namespace
+				}
+				results = append(results, SearchResult{Type: SearchProcessor, Name: processor.Name, Location: location, Match: "name", Score: score})
+			}
+		}
+	}
+
+	rankSearchResults(results)
+	if opts.Limit > 0 && len(results) > opts.Limit {
+		results = results[:opts.Limit]
+	}
+
+	return results, nil
+}
+
+// rankSearchResults sorts the "results" by their score, their type and their name.
+func rankSearchResults(results []SearchResult) {
+	rank := make(map[string]int, len(SearchTypes))
+	for i, typ := range SearchTypes {
+		rank[typ] = i
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if rank[a.Type] != rank[b.Type] {
+			return rank[a.Type] < rank[b.Type]
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Location < b.Location
+	})
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearch(t *testing.T) {
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/datasets":
+			queries = append(queries, r.URL.Query().Get("query"))
+			w.Write([]byte(`{"datasets":{"values":[
+				{"name":"payments","connectionName":"kafka","fields":{"key":[],"value":[{"name":"amount"},{"name":"card","fields":[{"name":"payment_id"}]}]}},
+				{"name":"card-payments-v2","connectionName":"kafka"},
+				{"name":"orders","connectionName":"kafka","description":"Orders waiting for their payment"},
+				{"name":"payments-idx","connectionName":"es-prod"}
+			]}}`))
+		case "/api/v1/connection/connections":
+			w.Write([]byte(`[{"name":"connect-dev","templateName":"KafkaConnect"},{"name":"kafka","templateName":"Kafka"}]`))
+		case "/api/proxy-connect/connect-dev/connectors":
+			w.Write([]byte(`["payments-s3-sink","orders-jdbc-source"]`))
+		case "/api/v1/streams":
+			w.Write([]byte(`{"streams":[{"name":"payments","clusterName":"k8s","namespace":"prod"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := OpenConnection(ClientConfig{Host: srv.URL, Token: "t0ken"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := client.Search(" Payment", SearchOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"payment"}, queries)
+	assert.Equal(t, []SearchResult{
+		{Type: SearchTopic, Name: "payments", Location: "kafka/payments", Match: "name", Score: 75},
+		{Type: SearchIndex, Name: "payments-idx", Location: "es-prod/payments-idx", Match: "name", Score: 75},
+		{Type: SearchConnector, Name: "payments-s3-sink", Location: "connect/connect-dev", Match: "name", Score: 75},
+		{Type: SearchProcessor, Name: "payments", Location: "processors/k8s/prod", Match: "name", Score: 75},
+		{Type: SearchTopic, Name: "card-payments-v2", Location: "kafka/card-payments-v2", Match: "name", Score: 50},
+		{Type: SearchField, Name: "_value.card.payment_id", Location: "kafka/payments", Match: "field", Score: 30},
+		{Type: SearchTopic, Name: "orders", Location: "kafka/orders", Match: "description", Score: 20},
+	}, results)
+
+	results, err = client.Search("payment", SearchOptions{Types: []string{SearchField, SearchConnector}, Limit: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []SearchResult{
+		{Type: SearchConnector, Name: "payments-s3-sink", Location: "connect/connect-dev", Match: "name", Score: 75},
+	}, results)
+
+	_, err = client.Search(" ", SearchOptions{})
+	assert.EqualError(t, err, "client: [term] is required")
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The features of the Lenses API that are not available on every version, see `Features`.
+const (
+	FeatureAlertChannels       = "alert-channels"
+	FeatureAuditChannels       = "audit-channels"
+	FeatureConnections         = "connections"
+	FeatureConnectionTemplates = "connection-templates"
+	FeatureDatasets            = "datasets"
+	FeatureElasticsearch       = "elasticsearch"
+	FeatureSavedQueries        = "saved-queries"
+	FeatureServiceAccounts     = "service-accounts"
+	FeatureSQLExplain          = "sql-explain"
+	FeatureTopicSettings       = "topic-settings"
+)
+
+// Features are the minimum Lenses versions of the features of the API.
+var Features = map[string]string{
+	FeatureAlertChannels:       "4.0",
+	FeatureAuditChannels:       "4.0",
+	FeatureConnections:         "4.1",
+	FeatureConnectionTemplates: "4.1",
+	FeatureDatasets:            "4.0",
+	FeatureElasticsearch:       "4.1",
+	FeatureSavedQueries:        "5.0",
+	FeatureServiceAccounts:     "3.2",
+	FeatureSQLExplain:          "5.0",
+	FeatureTopicSettings:       "4.1",
+}
+
+// ServerInfo describes the Lenses server of a client, see `Client#ServerInfo`.
+type ServerInfo struct {
+	Version  string   `json:"version" yaml:"version" header:"Version"`
+	Features []string `json:"features" yaml:"features" header:"Features"`
+}
+
+// Supports reports whether the server provides the "feature", see `Features`.
+// An unknown server version supports everything, the server itself has the final word.
+func (info ServerInfo) Supports(feature string) bool {
+	min, ok := Features[feature]
+	if !ok || info.Version == "" {
+		return true
+	}
+
+	return CompareVersions(info.Version, min) >= 0
+}
+
+// Require returns an `UnsupportedError` if the server does not provide the "feature".
+func (info ServerInfo) Require(feature string) error {
+	if info.Supports(feature) {
+		return nil
+	}
+
+	return UnsupportedError{Feature: feature, MinVersion: Features[feature], Version: info.Version}
+}
+
+// UnsupportedError is returned when a feature requires a newer Lenses server, see `ServerInfo#Require`.
+type UnsupportedError struct {
+	Feature    string
+	MinVersion string
+	Version    string
+}
+
+func (err UnsupportedError) Error() string {
+	return fmt.Sprintf("%s requires Lenses >= %s, the server is [%s]", err.Feature, err.MinVersion, err.Version)
+}
+
+// ServerInfo returns the version of the Lenses server and the features it supports,
+// it is fetched once and shared by the calls of the client.
+func (c *Client) ServerInfo() (ServerInfo, error) {
+	c.serverInfoMu.Lock()
+	defer c.serverInfoMu.Unlock()
+
+	if c.serverInfo != nil {
+		return *c.serverInfo, nil
+	}
+
+	var cfg struct {
+		Version string `json:"lenses.version"`
+	}
+	if err := c.getBoxConfig(&cfg); err != nil {
+		return ServerInfo{}, err
+	}
+
+	info := ServerInfo{Version: cfg.Version}
+	for feature := range Features {
+		if info.Supports(feature) {
+			info.Features = append(info.Features, feature)
+		}
+	}
+	sort.Strings(info.Features)
+
+	c.serverInfo = &info
+	return info, nil
+}
+
+// CompareVersions compares the numeric parts of two versions, i.e "4.1.2" and "4.1-SNAPSHOT",
+// it returns -1 if "a" is older than "b", 1 if it's newer and 0 if they are the same.
+func CompareVersions(a, b string) int {
+	pa, pb := versionParts(a), versionParts(b)
+	for len(pa) < len(pb) {
+		pa = append(pa, 0)
+	}
+	for len(pb) < len(pa) {
+		pb = append(pb, 0)
+	}
+
+	for i := range pa {
+		if pa[i] < pb[i] {
+			return -1
+		}
+		if pa[i] > pb[i] {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+func versionParts(version string) []int {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(version, "-+ "); i >= 0 {
+		version = version[:i]
+	}
+
+	var parts []int
+	for _, s := range strings.Split(version, ".") {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			break
+		}
+		parts = append(parts, n)
+	}
+
+	return parts
+}
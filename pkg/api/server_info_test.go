@@ -0,0 +1,24 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareVersions(t *testing.T) {
+	assert.Equal(t, 0, CompareVersions("4.1", "4.1.0"))
+	assert.Equal(t, 1, CompareVersions("4.1.2", "4.1"))
+	assert.Equal(t, -1, CompareVersions("4.0.9-SNAPSHOT", "4.1"))
+	assert.Equal(t, 1, CompareVersions("v5.0.0+build.1", "4.3.10"))
+}
+
+func TestServerInfoSupports(t *testing.T) {
+	info := ServerInfo{Version: "4.0.2"}
+	assert.True(t, info.Supports(FeatureDatasets))
+	assert.False(t, info.Supports(FeatureConnections))
+	assert.EqualError(t, info.Require(FeatureTopicSettings), "topic-settings requires Lenses >= 4.1, the server is [4.0.2]")
+
+	assert.True(t, ServerInfo{}.Supports(FeatureConnections), "an unknown version supports everything")
+	assert.True(t, info.Supports("unknown"))
+}
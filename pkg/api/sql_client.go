@@ -0,0 +1,53 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+const sqlExplainPath = "api/v1/sql/explain"
+
+// SQLPlanTopic is a topic that a query scans, see `SQLExecutionPlan`.
+type SQLPlanTopic struct {
+	Name string `json:"name" yaml:"name" header:"Topic"`
+	// Scan is how the topic is read, i.e "full", "partitions" or "offsets".
+	Scan       string `json:"scan" yaml:"scan" header:"Scan"`
+	Partitions []int  `json:"partitions,omitempty" yaml:"partitions,omitempty" header:"Partitions"`
+	// EstimatedRecords is the estimated number of the records read from the topic.
+	EstimatedRecords int64 `json:"estimatedRecords" yaml:"estimatedRecords" header:"Est. Records"`
+	EstimatedBytes   int64 `json:"estimatedBytes" yaml:"estimatedBytes" header:"Est. Bytes"`
+}
+
+// SQLPlanFilter is a filter of a query, see `SQLExecutionPlan`.
+type SQLPlanFilter struct {
+	Expression string `json:"expression" yaml:"expression" header:"Filter"`
+	// PushedDown reports whether the filter narrows the records read from Kafka, i.e by partition, offset or timestamp,
+	// instead of being applied to each read record.
+	PushedDown bool `json:"pushedDown" yaml:"pushedDown" header:"Pushed Down"`
+}
+
+// SQLExecutionPlan is the execution plan of a Lenses SQL browse query, see `Client#ExplainSQL`.
+type SQLExecutionPlan struct {
+	SQL     string          `json:"sql" yaml:"sql"`
+	Topics  []SQLPlanTopic  `json:"topics" yaml:"topics"`
+	Filters []SQLPlanFilter `json:"filters" yaml:"filters"`
+	// EstimatedCost is the relative cost of the query, comparable between the plans of the same server.
+	EstimatedCost float64 `json:"estimatedCost" yaml:"estimatedCost"`
+}
+
+// ExplainSQL returns the execution plan of the "sql" browse query, the topics it scans, its filters and whether
+// they are pushed down and its estimated cost, without running it.
+func (c *Client) ExplainSQL(sql string) (plan SQLExecutionPlan, err error) {
+	send, err := json.Marshal(map[string]string{"sql": sql})
+	if err != nil {
+		return
+	}
+
+	resp, err := c.Do(http.MethodPost, sqlExplainPath, contentTypeJSON, send)
+	if err != nil {
+		return
+	}
+
+	err = c.ReadJSON(resp, &plan)
+	return
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// decodePEM returns the PEM of the base64 encoded "value", or the "value" itself if it's already a PEM,
+// i.e read from a mounted file.
+func decodePEM(name, value string) ([]byte, error) {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "-----BEGIN") {
+		return []byte(value), nil
+	}
+
+	b, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s, expected a base64 encoded PEM: [%v]", name, err)
+	}
+
+	return b, nil
+}
+
+// TLSConfig returns the TLS configuration of the `Insecure`, `CACert`, `ClientCert` and `ClientKey`,
+// nil if they are not set so the defaults are used.
+func (c *ClientConfig) TLSConfig() (*tls.Config, error) {
+	if !c.Insecure && c.CACert == "" && c.ClientCert == "" && c.ClientKey == "" {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: c.Insecure}
+
+	if c.CACert != "" {
+		b, err := decodePEM("CA certificate", c.CACert)
+		if err != nil {
+			return nil, err
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, fmt.Errorf("invalid CA certificate, no certificate in its PEM")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if c.ClientCert != "" || c.ClientKey != "" {
+		if c.ClientCert == "" || c.ClientKey == "" {
+			return nil, fmt.Errorf("the client certificate and its key are required together")
+		}
+
+		certPEM, err := decodePEM("client certificate", c.ClientCert)
+		if err != nil {
+			return nil, err
+		}
+
+		keyPEM, err := decodePEM("client key", c.ClientKey)
+		if err != nil {
+			return nil, err
+		}
+
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: [%v]", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLSConfig(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("[]"))
+	}))
+	defer srv.Close()
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+
+	// the certificate of the test server is not trusted by the system.
+	client, err := OpenConnection(ClientConfig{Host: srv.URL, Token: "t0ken"})
+	require.NoError(t, err)
+	_, err = client.GetTopics()
+	assert.Error(t, err)
+
+	for _, ca := range []string{base64.StdEncoding.EncodeToString(caPEM), string(caPEM)} {
+		client, err = OpenConnection(ClientConfig{Host: srv.URL, Token: "t0ken", CACert: ca})
+		require.NoError(t, err)
+		_, err = client.GetTopics()
+		assert.NoError(t, err)
+	}
+
+	tlsConfig, err := (&ClientConfig{}).TLSConfig()
+	assert.NoError(t, err)
+	assert.Nil(t, tlsConfig)
+
+	_, err = OpenConnection(ClientConfig{Host: srv.URL, Token: "t0ken", CACert: "not base64"})
+	assert.EqualError(t, err, "invalid CA certificate, expected a base64 encoded PEM: [illegal base64 data at input byte 3]")
+
+	_, err = OpenConnection(ClientConfig{Host: srv.URL, Token: "t0ken", ClientCert: base64.StdEncoding.EncodeToString(caPEM)})
+	assert.EqualError(t, err, "the client certificate and its key are required together")
+}
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// KafkaConnection is the connection of the Kafka topics' datasets.
+const KafkaConnection = "kafka"
+
+// TopicOwnerTagPrefix is the prefix of the tags of a topic's owners, i.e "owner=payments-team".
+const TopicOwnerTagPrefix = "owner="
+
+// TopicAnnotation is the change of a topic's description, tags and owners, see `AnnotateTopic`.
+type TopicAnnotation struct {
+	// Description, if not nil, replaces the topic's description, an empty one removes it.
+	Description *string
+	// Tags are added to the topic's tags, i.e "team=payments" or "pii".
+	Tags []string
+	// RemoveTags are removed from the topic's tags.
+	RemoveTags []string
+	// Owners are added to the topic's tags as `TopicOwnerTagPrefix` tags.
+	Owners []string
+	// RemoveOwners are removed from the topic's owners.
+	RemoveOwners []string
+}
+
+// TopicTags returns the names of the "topic"'s tags.
+func TopicTags(topic Topic) []string {
+	tags := make([]string, 0, len(topic.Tags))
+	for _, tag := range topic.Tags {
+		tags = append(tags, tag.Name)
+	}
+
+	return tags
+}
+
+// TopicOwners returns the owners of the "topic", the tags with the `TopicOwnerTagPrefix`.
+func TopicOwners(topic Topic) []string {
+	var owners []string
+	for _, tag := range topic.Tags {
+		if strings.HasPrefix(tag.Name, TopicOwnerTagPrefix) {
+			owners = append(owners, strings.TrimPrefix(tag.Name, TopicOwnerTagPrefix))
+		}
+	}
+
+	return owners
+}
+
+// HasTopicTags reports whether the "topic" has all the "tags".
+func HasTopicTags(topic Topic, tags ...string) bool {
+	has := make(map[string]bool, len(topic.Tags))
+	for _, tag := range topic.Tags {
+		has[tag.Name] = true
+	}
+
+	for _, tag := range tags {
+		if !has[tag] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// annotatedTags returns the "tags" with the ones of the "annotation" added and removed, sorted.
+func annotatedTags(tags []string, annotation TopicAnnotation) []string {
+	set := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		set[tag] = true
+	}
+
+	for _, tag := range annotation.Tags {
+		set[tag] = true
+	}
+	for _, owner := range annotation.Owners {
+		set[TopicOwnerTagPrefix+owner] = true
+	}
+
+	for _, tag := range annotation.RemoveTags {
+		delete(set, tag)
+	}
+	for _, owner := range annotation.RemoveOwners {
+		delete(set, TopicOwnerTagPrefix+owner)
+	}
+
+	result := make([]string, 0, len(set))
+	for tag := range set {
+		result = append(result, tag)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// AnnotateTopic changes the description, the tags and the owners of the topic, through its dataset,
+// and returns the annotated topic.
+func (c *Client) AnnotateTopic(topicName string, annotation TopicAnnotation) (Topic, error) {
+	if topicName == "" {
+		return Topic{}, errRequired("topicName")
+	}
+
+	topic, err := c.GetTopic(topicName)
+	if err != nil {
+		return Topic{}, err
+	}
+
+	if annotation.Description != nil {
+		if err = c.UpdateDatasetDescription(KafkaConnection, topicName, *annotation.Description); err != nil {
+			return Topic{}, err
+		}
+		topic.Description = *annotation.Description
+	}
+
+	if len(annotation.Tags)+len(annotation.RemoveTags)+len(annotation.Owners)+len(annotation.RemoveOwners) == 0 {
+		if annotation.Description == nil {
+			return Topic{}, errors.New("nothing to annotate, a description, tags or owners are required")
+		}
+		return topic, nil
+	}
+
+	tags := annotatedTags(TopicTags(topic), annotation)
+	if err = c.UpdateDatasetTags(KafkaConnection, topicName, tags); err != nil {
+		return Topic{}, err
+	}
+
+	topic.Tags = make([]DatasetTag, 0, len(tags))
+	for _, tag := range tags {
+		topic.Tags = append(topic.Tags, DatasetTag{Name: tag})
+	}
+
+	return topic, nil
+}
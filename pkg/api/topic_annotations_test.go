@@ -0,0 +1,51 @@
+package api
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnnotateTopic(t *testing.T) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body))
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"topicName":"payments","tags":[{"name":"pii"},{"name":"owner=alice"}]}`))
+		}
+	}))
+	defer srv.Close()
+
+	client, err := OpenConnection(ClientConfig{Host: srv.URL, Token: "t0ken"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	description := "Card payments"
+	topic, err := client.AnnotateTopic("payments", TopicAnnotation{
+		Description:  &description,
+		Tags:         []string{"team=payments"},
+		RemoveTags:   []string{"pii"},
+		Owners:       []string{"payments-team"},
+		RemoveOwners: []string{"alice"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Card payments", topic.Description)
+	assert.Equal(t, []string{"owner=payments-team", "team=payments"}, TopicTags(topic))
+	assert.Equal(t, []string{"payments-team"}, TopicOwners(topic))
+	assert.True(t, HasTopicTags(topic, "team=payments", "owner=payments-team"))
+	assert.False(t, HasTopicTags(topic, "pii"))
+
+	assert.Equal(t, []string{
+		`GET /api/topics/payments `,
+		`PUT /api/v1/datasets/kafka/payments/description {"description":"Card payments"}`,
+		`PUT /api/v1/datasets/kafka/payments/tags {"tags":[{"name":"owner=payments-team"},{"name":"team=payments"}]}`,
+	}, requests)
+
+	_, err = client.AnnotateTopic("payments", TopicAnnotation{})
+	assert.EqualError(t, err, "nothing to annotate, a description, tags or owners are required")
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+)
+
+// TransportOptions tunes the connection reuse of the client's HTTP transport, see `UsingTransport`.
+// The zero value of a field keeps the default.
+type TransportOptions struct {
+	// MaxIdleConns is the maximum number of idle (keep-alive) connections across all hosts.
+	MaxIdleConns int
+	// MaxIdleConnsPerHost is the maximum number of idle (keep-alive) connections to the Lenses host,
+	// the default is 2 which is low for the concurrent calls of an automation.
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout is how long an idle connection is kept open.
+	IdleConnTimeout time.Duration
+	// DisableKeepAlives opens a new connection per request.
+	DisableKeepAlives bool
+	// TLSSessionCacheSize enables the TLS session resumption with a cache of that many sessions,
+	// so new connections skip the full TLS handshake.
+	TLSSessionCacheSize int
+	// EnableHTTP2 negotiates HTTP/2 with the server, it's disabled by default.
+	EnableHTTP2 bool
+	// RequestTimeout is the overall time limit of a request, including the read of its response body.
+	RequestTimeout time.Duration
+}
+
+// UsingTransport applies the "opts" to the client's HTTP transport, the default one or the `*http.Transport`
+// of a client given by `UsingClient`. A custom `http.RoundTripper` is left as it is, except for the `RequestTimeout`.
+func UsingTransport(opts TransportOptions) ConnectionOption {
+	return func(c *Client) {
+		c.transportOptions = &opts
+	}
+}
+
+func (opts TransportOptions) apply(client *http.Client) {
+	if opts.RequestTimeout > 0 {
+		client.Timeout = opts.RequestTimeout
+	}
+
+	t, ok := client.Transport.(*http.Transport)
+	if !ok {
+		return
+	}
+
+	if opts.MaxIdleConns > 0 {
+		t.MaxIdleConns = opts.MaxIdleConns
+	}
+
+	if opts.MaxIdleConnsPerHost > 0 {
+		t.MaxIdleConnsPerHost = opts.MaxIdleConnsPerHost
+	}
+
+	if opts.IdleConnTimeout > 0 {
+		t.IdleConnTimeout = opts.IdleConnTimeout
+	}
+
+	t.DisableKeepAlives = t.DisableKeepAlives || opts.DisableKeepAlives
+
+	if opts.TLSSessionCacheSize > 0 {
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{}
+		} else {
+			t.TLSClientConfig = t.TLSClientConfig.Clone()
+		}
+		t.TLSClientConfig.ClientSessionCache = tls.NewLRUClientSessionCache(opts.TLSSessionCacheSize)
+	}
+
+	if opts.EnableHTTP2 {
+		// the default transport disables it with an empty, non-nil, TLSNextProto.
+		t.TLSNextProto = nil
+		t.ForceAttemptHTTP2 = true
+	}
+}
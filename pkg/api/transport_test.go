@@ -0,0 +1,59 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsingTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"topicName":"orders"}]`))
+	}))
+	defer srv.Close()
+
+	client, err := OpenConnection(ClientConfig{Host: srv.URL, Token: "t0ken"}, UsingTransport(TransportOptions{
+		MaxIdleConns:        50,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     30 * time.Second,
+		TLSSessionCacheSize: 8,
+		EnableHTTP2:         true,
+		RequestTimeout:      5 * time.Second,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 5*time.Second, client.client.Timeout)
+
+	transport, ok := client.client.Transport.(*http.Transport)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, 50, transport.MaxIdleConns)
+	assert.Equal(t, 10, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, 30*time.Second, transport.IdleConnTimeout)
+	assert.False(t, transport.DisableKeepAlives)
+	assert.NotNil(t, transport.TLSClientConfig.ClientSessionCache)
+	assert.True(t, transport.ForceAttemptHTTP2)
+	assert.Nil(t, transport.TLSNextProto)
+
+	names, err := client.GetTopicsNames()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"orders"}, names)
+}
+
+func TestUsingTransportDefaults(t *testing.T) {
+	client, err := OpenConnection(ClientConfig{Host: "http://localhost:3030", Token: "t0ken"}, UsingTransport(TransportOptions{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transport := client.client.Transport.(*http.Transport)
+	assert.False(t, transport.ForceAttemptHTTP2)
+	assert.NotNil(t, transport.TLSNextProto, "HTTP/2 stays disabled")
+	assert.Equal(t, time.Duration(0), client.client.Timeout)
+}
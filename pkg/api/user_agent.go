@@ -0,0 +1,22 @@
+package api
+
+import (
+	"runtime"
+	"strings"
+
+	"github.com/lensesio/lenses-go/pkg/buildinfo"
+)
+
+// UserAgent returns the default User-Agent of the REST requests and of the websocket handshakes,
+// "lenses-go/<version>; cli/<version>; <os>/<arch>", so the audit logs of the server tell the automation from the UI.
+// The cli part is the `BuildVersion`, it's omitted when empty, i.e the client of an application.
+// It's overridden by `UsingUserAgent`.
+func UserAgent() string {
+	parts := []string{"lenses-go/" + buildinfo.LibraryVersion()}
+	if BuildVersion != "" {
+		parts = append(parts, "cli/"+BuildVersion)
+	}
+	parts = append(parts, runtime.GOOS+"/"+runtime.GOARCH)
+
+	return strings.Join(parts, "; ")
+}
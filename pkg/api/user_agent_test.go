@@ -0,0 +1,43 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserAgent(t *testing.T) {
+	defer func(version string) { BuildVersion = version }(BuildVersion)
+
+	platform := runtime.GOOS + "/" + runtime.GOARCH
+
+	BuildVersion = ""
+	assert.Equal(t, "lenses-go/devel; "+platform, UserAgent())
+
+	BuildVersion = "4.0.1"
+	assert.Equal(t, "lenses-go/devel; cli/4.0.1; "+platform, UserAgent())
+
+	var userAgents []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgents = append(userAgents, r.UserAgent())
+		w.Write([]byte("[]"))
+	}))
+	defer srv.Close()
+
+	client, err := OpenConnection(ClientConfig{Host: srv.URL, Token: "t0ken"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.GetTopics()
+
+	client, err = OpenConnection(ClientConfig{Host: srv.URL, Token: "t0ken"}, UsingUserAgent("nightly-sync/1.2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.GetTopics()
+
+	assert.Equal(t, []string{UserAgent(), "nightly-sync/1.2"}, userAgents)
+}
@@ -1,9 +1,12 @@
 package api
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 )
 
 const usersPath = "api/v1/user"
@@ -123,3 +126,88 @@ func (c *Client) UpdateUserPassword(username, password string) error {
 	}
 	return nil
 }
+
+const currentUserPath = "api/auth"
+
+// CurrentUser is the user authenticated by the client's token, see `GetCurrentUser`.
+type CurrentUser struct {
+	Name                 string   `json:"user"`
+	Roles                []string `json:"roles,omitempty"`
+	Permissions          []string `json:"permissions"`
+	SchemaRegistryDelete bool     `json:"schemaRegistryDelete"`
+	// TokenExpiry is the expiry of the token, if known by the server's response or by the token itself when it's a JWT.
+	TokenExpiry *time.Time `json:"tokenExpiry,omitempty"`
+}
+
+// MissingPermissions returns the "required" permissions that the user has not, the names are compared case-insensitively.
+func (u CurrentUser) MissingPermissions(required ...string) (missing []string) {
+	for _, permission := range required {
+		found := false
+		for _, p := range u.Permissions {
+			if strings.EqualFold(p, permission) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			missing = append(missing, permission)
+		}
+	}
+
+	return
+}
+
+// GetCurrentUser returns the authenticated user of the client's token, its roles, permissions and the token's expiry.
+func (c *Client) GetCurrentUser() (user CurrentUser, err error) {
+	resp, err := c.Do(http.MethodGet, currentUserPath, "", nil)
+	if err != nil {
+		return
+	}
+
+	var payload struct {
+		CurrentUser
+		Groups    []string `json:"groups"`
+		ExpiresAt int64    `json:"expiresAt"` // unix milliseconds.
+	}
+
+	if err = c.ReadJSON(resp, &payload); err != nil {
+		return
+	}
+
+	user = payload.CurrentUser
+	if len(user.Roles) == 0 {
+		user.Roles = payload.Groups
+	}
+
+	if payload.ExpiresAt > 0 {
+		expiry := time.Unix(0, payload.ExpiresAt*int64(time.Millisecond))
+		user.TokenExpiry = &expiry
+	} else if expiry, ok := jwtExpiry(c.token()); ok {
+		user.TokenExpiry = &expiry
+	}
+
+	return
+}
+
+// jwtExpiry returns the "exp" claim of the "token", if it's a JWT.
+func jwtExpiry(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err = json.Unmarshal(b, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}, false
+	}
+
+	return time.Unix(claims.Exp, 0), true
+}
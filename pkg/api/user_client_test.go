@@ -0,0 +1,71 @@
+package api
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCurrentUser(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    string
+		response string
+		roles    []string
+		expiry   time.Time
+	}{
+		{
+			name:     "roles and expiry from the response",
+			token:    "opaque",
+			response: `{"user":"admin","roles":["Admin"],"permissions":["ViewTopics","CreateTopic"],"expiresAt":1600000000000}`,
+			roles:    []string{"Admin"},
+			expiry:   time.Unix(1600000000, 0),
+		},
+		{
+			name:     "groups as roles and expiry from the JWT",
+			token:    "e30." + base64.RawURLEncoding.EncodeToString([]byte(`{"exp":1700000000}`)) + ".sig",
+			response: `{"user":"admin","groups":["dev"],"permissions":["ViewTopics","CreateTopic"]}`,
+			roles:    []string{"dev"},
+			expiry:   time.Unix(1700000000, 0),
+		},
+		{
+			name:     "unknown expiry",
+			token:    "opaque",
+			response: `{"user":"admin","permissions":["ViewTopics","CreateTopic"]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/auth", r.URL.Path)
+			w.Write([]byte(tt.response))
+		}))
+
+		client, err := OpenConnection(ClientConfig{Host: srv.URL, Token: tt.token})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		user, err := client.GetCurrentUser()
+		srv.Close()
+		if err != nil {
+			t.Fatalf("[%s] %v", tt.name, err)
+		}
+
+		assert.Equal(t, "admin", user.Name, tt.name)
+		assert.Equal(t, tt.roles, user.Roles, tt.name)
+		assert.Equal(t, []string{"ViewTopics", "CreateTopic"}, user.Permissions, tt.name)
+		if tt.expiry.IsZero() {
+			assert.Nil(t, user.TokenExpiry, tt.name)
+		} else if assert.NotNil(t, user.TokenExpiry, tt.name) {
+			assert.True(t, tt.expiry.Equal(*user.TokenExpiry), tt.name)
+		}
+
+		assert.Empty(t, user.MissingPermissions("createtopic"), tt.name)
+		assert.Equal(t, []string{"DeleteTopic"}, user.MissingPermissions("ViewTopics", "DeleteTopic"), tt.name)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/lensesio/lenses-go/pkg"
 	"github.com/lensesio/lenses-go/pkg/api"
 	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/printer"
 	"github.com/spf13/cobra"
 )
 
@@ -38,14 +39,14 @@ func NewGetAuditChannelsCommand() *cobra.Command {
 				if err != nil {
 					return fmt.Errorf("failed to retrieve audits' channels. Error: [%s]", err.Error())
 				}
-				return bite.PrintObject(cmd, auditchannelsWithDetails.Values)
+				return printer.PrintObject(cmd, auditchannelsWithDetails.Values)
 			}
 
 			auditchannels, err := config.Client.GetChannels(auditChannelsPath, page, pageSize, sortField, sortOrder, templateName, channelName)
 			if err != nil {
 				return fmt.Errorf("failed to retrieve audits' channels. Error: [%s]", err.Error())
 			}
-			return bite.PrintObject(cmd, auditchannels.Values)
+			return printer.PrintObject(cmd, auditchannels.Values)
 		},
 	}
 
@@ -59,6 +60,7 @@ func NewGetAuditChannelsCommand() *cobra.Command {
 
 	bite.CanBeSilent(cmd)
 	bite.CanPrintJSON(cmd)
+	printer.CanSelect(cmd)
 
 	cmd.AddCommand(NewDeleteAuditChannelCommand())
 	cmd.AddCommand(NewCreateAuditChannelCommand())
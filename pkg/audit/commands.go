@@ -7,6 +7,7 @@ import (
 	"github.com/lensesio/bite"
 	"github.com/lensesio/lenses-go/pkg/api"
 	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/utils"
 	"github.com/lensesio/tableprinter"
 	"github.com/spf13/cobra"
 )
@@ -21,7 +22,7 @@ func NewGetAuditEntriesCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:              "audits",
 		Short:            "List the last buffered audit entries",
-		Example:          `audits [--live] [--with-content]`,
+		Example:          `audits [--live] [--with-content] [--output=ndjson]`,
 		SilenceErrors:    true,
 		TraverseChildren: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -29,6 +30,10 @@ func NewGetAuditEntriesCommand() *cobra.Command {
 			withoutContentColumn := strings.ToUpper(bite.GetOutPutFlag(cmd)) == "TABLE" && !tableOnlyWithContent
 			if sse {
 				handler := func(entry api.AuditEntry) error {
+					if utils.IsNDJSON(cmd) {
+						return utils.PrintNDJSON(cmd, entry)
+					}
+
 					if withoutContentColumn {
 						// entry.Content = nil, no need.
 						newEntry := tableprinter.RemoveStructHeader(entry, "Content")
@@ -50,6 +55,16 @@ func NewGetAuditEntriesCommand() *cobra.Command {
 				return nil
 			}
 
+			if utils.IsNDJSON(cmd) {
+				for _, entry := range entries {
+					if err = utils.PrintNDJSON(cmd, entry); err != nil {
+						return err
+					}
+				}
+
+				return nil
+			}
+
 			if withoutContentColumn {
 				// print each one without content,
 				// bite is smart enough to see that it's the same type and it will append a row instead of a creating a new table,
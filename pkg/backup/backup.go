@@ -0,0 +1,231 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	"gopkg.in/yaml.v2"
+)
+
+// FormatVersion is the version of the layout of the backup archives, a newer archive can not be restored.
+const FormatVersion = 1
+
+// manifestFile is the file of the `Manifest` at the root of a backup archive.
+const manifestFile = "backup.yml"
+
+// Kind is a kind of resources of a backup, the `export` and `import` subcommands of its name back it up and restore it.
+type Kind struct {
+	Name string
+	// Feature is the feature of the API the kind requires, if any, see `api.Features`.
+	Feature string
+}
+
+// Kinds are the kinds of resources of a backup, in restore order: the connections and the settings before the topics,
+// the topics and the schemas before the resources that reference them, the connectors and processors last.
+var Kinds = []Kind{
+	{Name: "connections", Feature: api.FeatureConnections},
+	{Name: "topic-settings", Feature: api.FeatureTopicSettings},
+	{Name: "topics"},
+	{Name: "schemas"},
+	{Name: "acls"},
+	{Name: "quotas"},
+	{Name: "policies"},
+	{Name: "groups"},
+	{Name: "serviceaccounts", Feature: api.FeatureServiceAccounts},
+	{Name: "alert-channels", Feature: api.FeatureAlertChannels},
+	{Name: "audit-channels", Feature: api.FeatureAuditChannels},
+	{Name: "alert-settings"},
+	{Name: "connectors"},
+	{Name: "processors"},
+}
+
+// Manifest describes a backup archive.
+type Manifest struct {
+	FormatVersion int       `json:"formatVersion" yaml:"formatVersion" header:"Format Version"`
+	Created       time.Time `json:"created" yaml:"created" header:"Created"`
+	Context       string    `json:"context" yaml:"context" header:"Context"`
+	Host          string    `json:"host" yaml:"host" header:"Host"`
+	ServerVersion string    `json:"serverVersion,omitempty" yaml:"serverVersion,omitempty" header:"Server Version"`
+	CLIVersion    string    `json:"cliVersion,omitempty" yaml:"cliVersion,omitempty" header:"CLI Version"`
+	Kinds         []string  `json:"kinds" yaml:"kinds" header:"Kinds"`
+}
+
+// Has reports whether the backup contains the resources of the "kind".
+func (m Manifest) Has(kind string) bool {
+	for _, k := range m.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SelectKinds returns the `Kinds` of the comma separated "only" names, in restore order, all of them if empty.
+func SelectKinds(only string) ([]Kind, error) {
+	if strings.TrimSpace(only) == "" {
+		return Kinds, nil
+	}
+
+	names := make(map[string]bool)
+	for _, name := range strings.Split(only, ",") {
+		names[strings.TrimSpace(name)] = true
+	}
+
+	var kinds []Kind
+	for _, kind := range Kinds {
+		if names[kind.Name] {
+			kinds = append(kinds, kind)
+			delete(names, kind.Name)
+		}
+	}
+
+	for name := range names {
+		return nil, fmt.Errorf("unknown kind [%s], expected one of [%s]", name, strings.Join(kindNames(Kinds), ", "))
+	}
+
+	return kinds, nil
+}
+
+func kindNames(kinds []Kind) []string {
+	names := make([]string, len(kinds))
+	for i, kind := range kinds {
+		names[i] = kind.Name
+	}
+
+	return names
+}
+
+// WriteManifest writes the "manifest" to the root of the backup "dir".
+func WriteManifest(dir string, manifest Manifest) error {
+	b, err := yaml.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filepath.Join(dir, manifestFile), b, os.FileMode(0600))
+}
+
+// ReadManifest reads the manifest of the backup "dir" and checks that its format can be restored.
+func ReadManifest(dir string) (Manifest, error) {
+	var manifest Manifest
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, manifestFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return manifest, fmt.Errorf("not a backup, the [%s] is missing", manifestFile)
+		}
+		return manifest, err
+	}
+
+	if err = yaml.Unmarshal(b, &manifest); err != nil {
+		return manifest, fmt.Errorf("unable to read the backup manifest: [%v]", err)
+	}
+
+	if manifest.FormatVersion > FormatVersion {
+		return manifest, fmt.Errorf("the backup format version [%d] is newer than the supported [%d], upgrade the CLI", manifest.FormatVersion, FormatVersion)
+	}
+
+	return manifest, nil
+}
+
+// Archive writes the files of the "dir" to "w" as a gzip compressed tar.
+func Archive(w io.Writer, dir string) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || path == dir {
+			return err
+		}
+
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(name)
+
+		if err = tw.WriteHeader(header); err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tw.Close(); err != nil {
+		return err
+	}
+
+	return gw.Close()
+}
+
+// Extract writes the files of the gzip compressed tar "r" to the "dir".
+func Extract(r io.Reader, dir string) error {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("not a gzip compressed backup: [%v]", err)
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		path := filepath.Join(dir, filepath.FromSlash(header.Name))
+		// the entries can't escape the "dir", i.e by "../".
+		if rel, err := filepath.Rel(dir, path); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("invalid backup entry [%s]", header.Name)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err = os.MkdirAll(path, os.FileMode(0750)); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err = os.MkdirAll(filepath.Dir(path), os.FileMode(0750)); err != nil {
+				return err
+			}
+
+			f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, os.FileMode(0600))
+			if err != nil {
+				return err
+			}
+
+			_, err = io.Copy(f, tr)
+			f.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectKinds(t *testing.T) {
+	kinds, err := SelectKinds("")
+	assert.NoError(t, err)
+	assert.Equal(t, Kinds, kinds)
+
+	// restore order, not the given one.
+	kinds, err = SelectKinds("processors, schemas,topics")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"topics", "schemas", "processors"}, kindNames(kinds))
+
+	_, err = SelectKinds("topics,brokers")
+	assert.Error(t, err)
+}
+
+func TestArchive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lenses-backup-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	src, dst := filepath.Join(dir, "src"), filepath.Join(dir, "dst")
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "kafka", "topics"), 0750))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(src, "kafka", "topics", "topic-orders.yaml"), []byte("name: orders\n"), 0600))
+
+	manifest := Manifest{FormatVersion: FormatVersion, Created: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+		Context: "prod", Host: "https://lenses", ServerVersion: "5.0.1", Kinds: []string{"topics"}}
+	require.NoError(t, WriteManifest(src, manifest))
+
+	var buf bytes.Buffer
+	require.NoError(t, Archive(&buf, src))
+	require.NoError(t, Extract(&buf, dst))
+
+	b, err := ioutil.ReadFile(filepath.Join(dst, "kafka", "topics", "topic-orders.yaml"))
+	assert.NoError(t, err)
+	assert.Equal(t, "name: orders\n", string(b))
+
+	read, err := ReadManifest(dst)
+	assert.NoError(t, err)
+	assert.Equal(t, manifest, read)
+	assert.True(t, read.Has("topics"))
+	assert.False(t, read.Has("schemas"))
+
+	manifest.FormatVersion = FormatVersion + 1
+	require.NoError(t, WriteManifest(dst, manifest))
+	_, err = ReadManifest(dst)
+	assert.Error(t, err)
+
+	_, err = ReadManifest(src + "-missing")
+	assert.Error(t, err)
+}
+
+func TestExtractOutsideDir(t *testing.T) {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "../escape.yml", Typeflag: tar.TypeReg, Mode: 0600, Size: 1}))
+	tw.Write([]byte("x"))
+	tw.Close()
+	gw.Close()
+
+	dir, err := ioutil.TempDir("", "lenses-backup-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.EqualError(t, Extract(&buf, filepath.Join(dir, "dst")), "invalid backup entry [../escape.yml]")
+}
+
+func TestBackupCommandsMutating(t *testing.T) {
+	group := NewBackupGroupCommand()
+	create, _, err := group.Find([]string{"create"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	restore, _, err := group.Find([]string{"restore"})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// a backup of a protected context only reads it, a restore requires --allow-protected.
+	assert.False(t, config.IsMutating(create))
+	assert.True(t, config.IsMutating(restore))
+}
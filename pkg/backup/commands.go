@@ -0,0 +1,213 @@
+package backup
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kataras/golog"
+	"github.com/lensesio/bite"
+	"github.com/lensesio/lenses-go/pkg/api"
+	"github.com/lensesio/lenses-go/pkg/batch"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/spf13/cobra"
+)
+
+//NewBackupGroupCommand creates the `backup` command
+func NewBackupGroupCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Back up the resources of an environment to an archive and restore them",
+		Example: `backup create --out backup.tar.gz
+backup restore -f backup.tar.gz --only topics,schemas`,
+		SilenceErrors:    true,
+		TraverseChildren: true,
+	}
+
+	cmd.AddCommand(NewBackupCreateCommand())
+	cmd.AddCommand(config.WithLock(NewBackupRestoreCommand()))
+
+	return cmd
+}
+
+// run runs the `export` or `import`, the "action", of each of the "kinds" with the "dir" through the "root" command,
+// like the steps of a `run` script.
+func run(root *cobra.Command, action, dir string, kinds []Kind) error {
+	script := &batch.Script{}
+	for _, kind := range kinds {
+		script.Steps = append(script.Steps, batch.Step{
+			Name: fmt.Sprintf("%s %s", action, kind.Name),
+			Args: []string{action, kind.Name, "--dir", dir},
+		})
+	}
+
+	runner := &batch.Runner{Root: root, Err: root.ErrOrStderr()}
+	_, err := runner.Run(script)
+	return err
+}
+
+//NewBackupCreateCommand creates the `backup create` command
+func NewBackupCreateCommand() *cobra.Command {
+	var out, only string
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Export the resources of the current context to a backup archive",
+		Long: `Export the resources of the current context, by the export command of each kind, to a gzip compressed tar archive
+with a manifest of the context, the server and CLI versions and the kinds it contains.
+The kinds the server does not support are skipped. Kinds: ` + strings.Join(kindNames(Kinds), ", ") + `.`,
+		Example: `backup create --out backup.tar.gz
+backup create --out topics.tar.gz --only topics,schemas,acls`,
+		// it only reads the current context, despite its name.
+		Annotations:      map[string]string{config.AnnotationMutating: "false"},
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := bite.CheckRequiredFlags(cmd, bite.FlagPair{"out": out}); err != nil {
+				return err
+			}
+
+			selected, err := SelectKinds(only)
+			if err != nil {
+				return err
+			}
+
+			// an unknown version supports every kind.
+			info, err := config.Client.ServerInfo()
+			if err != nil {
+				golog.Debugf("unable to retrieve the server version: [%v]", err)
+			}
+
+			manifest := Manifest{
+				FormatVersion: FormatVersion,
+				Created:       time.Now().UTC(),
+				Context:       config.Manager.Config.CurrentContext,
+				Host:          config.Client.Config.Host,
+				ServerVersion: info.Version,
+				CLIVersion:    api.BuildVersion,
+			}
+
+			var kinds []Kind
+			for _, kind := range selected {
+				if kind.Feature != "" && !info.Supports(kind.Feature) {
+					golog.Infof("Skipping [%s], the server [%s] does not support it", kind.Name, info.Version)
+					continue
+				}
+				kinds = append(kinds, kind)
+				manifest.Kinds = append(manifest.Kinds, kind.Name)
+			}
+
+			dir, err := ioutil.TempDir("", "lenses-backup")
+			if err != nil {
+				return err
+			}
+			defer os.RemoveAll(dir)
+
+			if err = run(cmd.Root(), "export", dir, kinds); err != nil {
+				return err
+			}
+
+			if err = WriteManifest(dir, manifest); err != nil {
+				return err
+			}
+
+			f, err := os.OpenFile(out, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, os.FileMode(0600))
+			if err != nil {
+				return fmt.Errorf("unable to create the backup [%s]: [%v]", out, err)
+			}
+
+			if err = Archive(f, dir); err != nil {
+				f.Close()
+				return fmt.Errorf("unable to write the backup [%s]: [%v]", out, err)
+			}
+
+			if err = f.Close(); err != nil {
+				return err
+			}
+
+			return bite.PrintInfo(cmd, "Backup [%s] of context [%s] created with [%s]", out, manifest.Context, strings.Join(manifest.Kinds, ", "))
+		},
+	}
+
+	cmd.Flags().StringVar(&out, "out", "", "The backup archive file, i.e backup.tar.gz")
+	cmd.Flags().StringVar(&only, "only", "", "The comma separated kinds to back up, all if empty, i.e topics,schemas")
+
+	bite.CanBeSilent(cmd)
+
+	return cmd
+}
+
+//NewBackupRestoreCommand creates the `backup restore` command
+func NewBackupRestoreCommand() *cobra.Command {
+	var file, only string
+
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Import the resources of a backup archive to the current context",
+		Long: `Import the resources of a backup archive, by the import command of each kind, to the current context,
+the kinds in dependency order, i.e the topics and schemas before the connectors and processors.
+The imports create the missing resources and update the changed ones, so a restore can run again.`,
+		Example: `backup restore -f backup.tar.gz
+backup restore -f backup.tar.gz --only topics,schemas`,
+		Annotations:      map[string]string{config.AnnotationMutating: "true"},
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := bite.CheckRequiredFlags(cmd, bite.FlagPair{"file": file}); err != nil {
+				return err
+			}
+
+			selected, err := SelectKinds(only)
+			if err != nil {
+				return err
+			}
+
+			f, err := os.Open(file)
+			if err != nil {
+				return fmt.Errorf("unable to read the backup [%s]: [%v]", file, err)
+			}
+			defer f.Close()
+
+			dir, err := ioutil.TempDir("", "lenses-restore")
+			if err != nil {
+				return err
+			}
+			defer os.RemoveAll(dir)
+
+			if err = Extract(f, dir); err != nil {
+				return fmt.Errorf("unable to read the backup [%s]: [%v]", file, err)
+			}
+
+			manifest, err := ReadManifest(dir)
+			if err != nil {
+				return err
+			}
+
+			var kinds []Kind
+			for _, kind := range selected {
+				if manifest.Has(kind.Name) {
+					kinds = append(kinds, kind)
+				} else if only != "" {
+					return fmt.Errorf("the backup [%s] does not contain [%s]", file, kind.Name)
+				}
+			}
+
+			golog.Infof("Restoring the backup of context [%s] of [%s], server [%s]", manifest.Context, manifest.Created.Format(time.RFC3339), manifest.ServerVersion)
+
+			if err = run(cmd.Root(), "import", dir, kinds); err != nil {
+				return err
+			}
+
+			return bite.PrintInfo(cmd, "Backup [%s] restored to context [%s] with [%s]", file, config.Manager.Config.CurrentContext, strings.Join(kindNames(kinds), ", "))
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "The backup archive file")
+	cmd.Flags().StringVar(&only, "only", "", "The comma separated kinds to restore, all of the backup if empty, i.e topics,schemas")
+
+	bite.CanBeSilent(cmd)
+
+	return cmd
+}
@@ -0,0 +1,318 @@
+package batch
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/lensesio/lenses-go/pkg/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v2"
+)
+
+// Script is the file of the `run` command, a sequence of CLI commands.
+//
+//	vars:
+//	  topic: orders
+//	steps:
+//	  - name: create the topic
+//	    run: topics create --name ${topic} --partitions 3
+//	    continueOnError: true
+//	  - args: [topics, metadata, set, --name, "${topic}", --key-type, STRING]
+type Script struct {
+	Vars  map[string]string `yaml:"vars"`
+	Steps []Step            `yaml:"steps"`
+}
+
+// Step is a single command of a `Script`, the command line without the `lenses-cli` is
+// either a string, `Run`, or a list of arguments, `Args`.
+type Step struct {
+	Name            string            `yaml:"name"`
+	Run             string            `yaml:"run"`
+	Args            []string          `yaml:"args"`
+	Vars            map[string]string `yaml:"vars"`
+	ContinueOnError bool              `yaml:"continueOnError"`
+}
+
+// Running reports whether the steps of a script are executed,
+// the steps share the client that was set up for the `run` command.
+var Running bool
+
+// ParseScript reads and validates a script.
+func ParseScript(b []byte) (*Script, error) {
+	var script Script
+	if err := yaml.UnmarshalStrict(b, &script); err != nil {
+		return nil, fmt.Errorf("unable to read the script: [%v]", err)
+	}
+
+	if len(script.Steps) == 0 {
+		return nil, errors.New("script has no steps")
+	}
+
+	for i, step := range script.Steps {
+		if (step.Run == "") == (len(step.Args) == 0) {
+			return nil, fmt.Errorf("step [%s]: one of 'run' or 'args' is required", step.title(i))
+		}
+	}
+
+	return &script, nil
+}
+
+func (s Step) title(i int) string {
+	if s.Name != "" {
+		return s.Name
+	}
+
+	return fmt.Sprintf("#%d", i+1)
+}
+
+// expand replaces the `${name}` of "s" with the variable of "vars" or, if missing, the environment variable.
+func expand(s string, vars map[string]string) (string, error) {
+	return utils.ExpandVars(s, func(name string) (string, bool) {
+		if v, ok := vars[name]; ok {
+			return v, true
+		}
+		return os.LookupEnv(name)
+	})
+}
+
+// splitArgs splits a command line to arguments the way a shell does, without the expansions.
+func splitArgs(line string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		inArg   bool
+		quote   rune
+		escaped bool
+	)
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inArg = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 || escaped {
+		return nil, fmt.Errorf("unterminated quote or escape in [%s]", line)
+	}
+
+	if inArg {
+		args = append(args, current.String())
+	}
+
+	return args, nil
+}
+
+// args returns the arguments of the step, with the variables expanded.
+func (s Step) args(vars map[string]string) ([]string, error) {
+	if len(s.Vars) > 0 {
+		merged := make(map[string]string, len(vars)+len(s.Vars))
+		for k, v := range vars {
+			merged[k] = v
+		}
+		for k, v := range s.Vars {
+			merged[k] = v
+		}
+		vars = merged
+	}
+
+	if s.Run != "" {
+		line, err := expand(s.Run, vars)
+		if err != nil {
+			return nil, err
+		}
+		return splitArgs(line)
+	}
+
+	args := make([]string, len(s.Args))
+	for i, arg := range s.Args {
+		expanded, err := expand(arg, vars)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = expanded
+	}
+
+	return args, nil
+}
+
+// StepResult is the outcome of a step.
+type StepResult struct {
+	Step   string `json:"step" header:"Step"`
+	Status string `json:"status" header:"Status"`
+	Error  string `json:"error,omitempty" header:"Error"`
+}
+
+const (
+	statusOK      = "ok"
+	statusFailed  = "failed"
+	statusSkipped = "skipped"
+)
+
+// flagState is the value of a flag to restore before each step.
+type flagState struct {
+	flag    *pflag.Flag
+	value   []string
+	changed bool
+}
+
+func flagValue(f *pflag.Flag) []string {
+	if s, ok := f.Value.(pflag.SliceValue); ok {
+		return s.GetSlice()
+	}
+
+	return []string{f.Value.String()}
+}
+
+func setFlag(f *pflag.Flag, value []string, changed bool) {
+	if s, ok := f.Value.(pflag.SliceValue); ok {
+		s.Replace(value)
+	} else if len(value) > 0 {
+		f.Value.Set(value[0])
+	}
+
+	f.Changed = changed
+}
+
+// defaultValue returns the default of the "f", the slices' defaults are printed as "[a,b]".
+func defaultValue(f *pflag.Flag) []string {
+	if _, ok := f.Value.(pflag.SliceValue); ok {
+		def := strings.TrimSuffix(strings.TrimPrefix(f.DefValue, "["), "]")
+		if def == "" {
+			return nil
+		}
+		return strings.Split(def, ",")
+	}
+
+	return []string{f.DefValue}
+}
+
+// resetFlags sets the flags of the "cmd" and its parents up to the root back to their defaults,
+// the commands keep their parsed flags, so a step would see the flags of the previous step otherwise.
+func resetFlags(root, cmd *cobra.Command) {
+	for c := cmd; c != nil && c != root; c = c.Parent() {
+		c.LocalFlags().VisitAll(func(f *pflag.Flag) {
+			setFlag(f, defaultValue(f), false)
+		})
+	}
+}
+
+// Runner executes the steps of a script through the "Root" command.
+type Runner struct {
+	Root *cobra.Command
+	// Self is the `run` command, a step can't run a script itself.
+	Self *cobra.Command
+	Vars map[string]string
+	Err  io.Writer
+}
+
+// Run executes the steps in order, it stops on the first failed step, unless it's allowed to continue on error.
+func (r *Runner) Run(script *Script) ([]StepResult, error) {
+	vars := make(map[string]string, len(script.Vars)+len(r.Vars))
+	for k, v := range script.Vars {
+		vars[k] = v
+	}
+	for k, v := range r.Vars {
+		vars[k] = v
+	}
+
+	// the global flags of the `run` command, i.e --output, apply to every step unless the step sets them.
+	var global []flagState
+	r.Root.PersistentFlags().VisitAll(func(f *pflag.Flag) {
+		global = append(global, flagState{flag: f, value: flagValue(f), changed: f.Changed})
+	})
+
+	Running = true
+	defer func() {
+		Running = false
+		for _, s := range global {
+			setFlag(s.flag, s.value, s.changed)
+		}
+	}()
+
+	results := make([]StepResult, 0, len(script.Steps))
+	var failed error
+
+	for i, step := range script.Steps {
+		title := step.title(i)
+		if failed != nil {
+			results = append(results, StepResult{Step: title, Status: statusSkipped})
+			continue
+		}
+
+		fmt.Fprintf(r.Err, "==> [%d/%d] %s\n", i+1, len(script.Steps), title)
+
+		err := r.runStep(step, vars, global)
+		if err == nil {
+			results = append(results, StepResult{Step: title, Status: statusOK})
+			continue
+		}
+
+		results = append(results, StepResult{Step: title, Status: statusFailed, Error: err.Error()})
+		if step.ContinueOnError {
+			fmt.Fprintf(r.Err, "step [%s] failed, continue: [%v]\n", title, err)
+			continue
+		}
+
+		failed = fmt.Errorf("step [%s] failed: %w", title, err)
+	}
+
+	return results, failed
+}
+
+func (r *Runner) runStep(step Step, vars map[string]string, global []flagState) error {
+	args, err := step.args(vars)
+	if err != nil {
+		return err
+	}
+
+	if len(args) > 0 && args[0] == "lenses-cli" {
+		args = args[1:]
+	}
+
+	cmd, _, err := r.Root.Find(args)
+	if err != nil {
+		return err
+	}
+
+	if cmd == r.Root {
+		return fmt.Errorf("unknown command [%s]", strings.Join(args, " "))
+	}
+
+	if r.Self != nil && cmd == r.Self {
+		return errors.New("a step can't run a script")
+	}
+
+	for _, s := range global {
+		setFlag(s.flag, s.value, s.changed)
+	}
+	resetFlags(r.Root, cmd)
+
+	r.Root.SetArgs(args)
+	_, err = r.Root.ExecuteC()
+	return err
+}
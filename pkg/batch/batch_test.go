@@ -0,0 +1,141 @@
+package batch
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected []string
+	}{
+		{"topics create --name orders", []string{"topics", "create", "--name", "orders"}},
+		{`  sql "SELECT * FROM orders"  `, []string{"sql", "SELECT * FROM orders"}},
+		{`a 'b "c"' d\ e ""`, []string{"a", `b "c"`, "d e", ""}},
+	}
+
+	for _, tt := range tests {
+		args, err := splitArgs(tt.line)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expected, args, tt.line)
+	}
+
+	_, err := splitArgs(`sql "SELECT`)
+	assert.Error(t, err)
+}
+
+func TestExpand(t *testing.T) {
+	os.Setenv("LENSES_BATCH_TEST", "from-env")
+	defer os.Unsetenv("LENSES_BATCH_TEST")
+
+	out, err := expand("${topic}-${LENSES_BATCH_TEST} $HOME", map[string]string{"topic": "orders"})
+	assert.NoError(t, err)
+	assert.Equal(t, "orders-from-env $HOME", out)
+
+	_, err = expand("${missing}", nil)
+	assert.EqualError(t, err, "undefined variable [missing]")
+}
+
+func TestParseScript(t *testing.T) {
+	_, err := ParseScript([]byte(`steps: []`))
+	assert.Error(t, err)
+
+	_, err = ParseScript([]byte("steps:\n  - name: both\n    run: a\n    args: [a]\n"))
+	assert.EqualError(t, err, "step [both]: one of 'run' or 'args' is required")
+
+	_, err = ParseScript([]byte("steps:\n  - rn: a\n"))
+	assert.Error(t, err)
+
+	script, err := ParseScript([]byte(`{"vars": {"a": "1"}, "steps": [{"run": "topics"}]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "1", script.Vars["a"])
+}
+
+// newRoot returns a command tree which records the calls of the `topic create` command.
+func newRoot(calls *[]string) (*cobra.Command, *cobra.Command) {
+	root := &cobra.Command{Use: "lenses-cli", SilenceErrors: true, SilenceUsage: true, TraverseChildren: true}
+	root.PersistentFlags().String("output", "table", "")
+
+	var (
+		name       string
+		partitions int
+		configs    []string
+	)
+	create := &cobra.Command{
+		Use: "create",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output, _ := cmd.Flags().GetString("output")
+			*calls = append(*calls, name+"/"+output+"/"+cmd.Flag("partitions").Value.String()+"/"+cmd.Flag("configs").Value.String())
+			if name == "fail" {
+				return errors.New("failed")
+			}
+			return nil
+		},
+	}
+	create.Flags().StringVar(&name, "name", "", "")
+	create.Flags().IntVar(&partitions, "partitions", 1, "")
+	create.Flags().StringArrayVar(&configs, "configs", nil, "")
+
+	topic := &cobra.Command{Use: "topic"}
+	topic.AddCommand(create)
+
+	run := &cobra.Command{Use: "run", RunE: func(*cobra.Command, []string) error { return nil }}
+	root.AddCommand(topic, run)
+	return root, run
+}
+
+func TestRunner(t *testing.T) {
+	var calls []string
+	root, run := newRoot(&calls)
+	root.PersistentFlags().Set("output", "json")
+
+	script, err := ParseScript([]byte(`
+vars:
+  topic: orders
+steps:
+  - run: topic create --name ${topic} --partitions 3 --configs a=1 --configs b=2
+  - name: second
+    args: [lenses-cli, topic, create, --name, "${topic}-${suffix}", --output, yaml]
+    vars:
+      suffix: dlq
+  - run: topic create --name fail
+    continueOnError: true
+  - run: topic create --name ${topic}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runner := &Runner{Root: root, Self: run, Vars: map[string]string{"topic": "payments"}, Err: ioutil.Discard}
+	results, err := runner.Run(script)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"payments/json/3/[a=1,b=2]",
+		"payments-dlq/yaml/1/[]",
+		"fail/json/1/[]",
+		"payments/json/1/[]",
+	}, calls)
+	assert.Equal(t, "failed", results[2].Status)
+	assert.False(t, Running)
+
+	calls = nil
+	script, _ = ParseScript([]byte(`
+steps:
+  - run: topic create --name fail
+  - run: topic create --name orders
+`))
+	results, err = runner.Run(script)
+	assert.EqualError(t, err, "step [#1] failed: failed")
+	assert.Equal(t, []string{"fail/json/1/[]"}, calls)
+	assert.Equal(t, "skipped", results[1].Status)
+
+	script, _ = ParseScript([]byte(`steps: [{run: run -f other.yml}]`))
+	_, err = runner.Run(script)
+	assert.EqualError(t, err, "step [#1] failed: a step can't run a script")
+}
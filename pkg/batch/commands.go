@@ -0,0 +1,83 @@
+package batch
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/lensesio/bite"
+	"github.com/spf13/cobra"
+)
+
+//NewRunCommand creates the `run` command
+func NewRunCommand() *cobra.Command {
+	var (
+		file string
+		vars []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Run a sequence of commands from a script file, with a single authenticated client",
+		Long: `Run a sequence of commands from a script file, with a single authenticated client.
+Each step is a command line without the 'lenses-cli', the steps run in order and the run stops on the first failed step,
+unless the step sets 'continueOnError'. The '${name}' of the steps are replaced by the script's 'vars', the '--var' flags
+or the environment variables.
+The steps share the connection of the 'run' command, the connection flags of a step are ignored.`,
+		Example: `run -f playbook.yml --var topic=orders
+
+# playbook.yml
+vars:
+  topic: payments
+steps:
+  - name: create the topic
+    run: topics create --name ${topic} --partitions 3 --replication 1
+    continueOnError: true
+  - name: set the metadata
+    args: [topics, metadata, set, --name, "${topic}", --key-type, STRING, --value-type, JSON]`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := bite.CheckRequiredFlags(cmd, bite.FlagPair{"file": file}); err != nil {
+				return err
+			}
+
+			b, err := ioutil.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("unable to read the script [%s]: [%v]", file, err)
+			}
+
+			script, err := ParseScript(b)
+			if err != nil {
+				return err
+			}
+
+			overrides := make(map[string]string, len(vars))
+			for _, v := range vars {
+				kv := strings.SplitN(v, "=", 2)
+				if len(kv) != 2 || kv[0] == "" {
+					return fmt.Errorf("invalid variable [%s], use --var name=value", v)
+				}
+				overrides[kv[0]] = kv[1]
+			}
+
+			runner := &Runner{Root: cmd.Root(), Self: cmd, Vars: overrides, Err: cmd.ErrOrStderr()}
+			results, err := runner.Run(script)
+
+			// the steps' output goes to the same writer, print the summary to the error output.
+			fmt.Fprintln(cmd.ErrOrStderr())
+			for _, r := range results {
+				if r.Error != "" {
+					fmt.Fprintf(cmd.ErrOrStderr(), "%-8s %s: %s\n", r.Status, r.Step, r.Error)
+				} else {
+					fmt.Fprintf(cmd.ErrOrStderr(), "%-8s %s\n", r.Status, r.Step)
+				}
+			}
+
+			return err
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "The script file, YAML or JSON")
+	cmd.Flags().StringArrayVar(&vars, "var", nil, "A variable of the script, name=value, overrides the script's vars, repeatable")
+
+	return cmd
+}
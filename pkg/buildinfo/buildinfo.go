@@ -0,0 +1,101 @@
+// Package buildinfo provides the build metadata of the CLI and of the library, the version, the git commit,
+// the build date, the Go version and the range of the Lenses API they support.
+//
+// The `Version`, `Commit` and `Date` are set at build time, i.e
+// -ldflags "-X github.com/lensesio/lenses-go/pkg/buildinfo.Version=4.1.0 -X github.com/lensesio/lenses-go/pkg/buildinfo.Commit=$(git rev-parse HEAD) -X github.com/lensesio/lenses-go/pkg/buildinfo.Date=$(date +%s)".
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// Version is the version of the CLI, empty for the development builds and the applications of the library.
+	Version = ""
+	// Commit is the git commit of the build.
+	Commit = ""
+	// Date is the build unix time, in seconds since 1970-01-01 00:00:00 UTC.
+	//
+	// Note that it's a string because it's provided at build time.
+	Date = ""
+)
+
+const (
+	// MinLensesVersion is the oldest version of Lenses supported by the client.
+	MinLensesVersion = "4.0"
+	// MaxLensesVersion is the first version of Lenses not supported by the client, exclusive.
+	MaxLensesVersion = "6.0"
+)
+
+// modulePath is the path of the module of the client.
+const modulePath = "github.com/lensesio/lenses-go"
+
+// LibraryVersion returns the version of the lenses-go module of the binary, the main module or a dependency,
+// "devel" if it's not a released one, i.e a local build.
+func LibraryVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "devel"
+	}
+
+	version := ""
+	if info.Main.Path == modulePath {
+		version = info.Main.Version
+	} else {
+		for _, dep := range info.Deps {
+			if dep.Path == modulePath {
+				version = dep.Version
+				if dep.Replace != nil && dep.Replace.Version != "" {
+					version = dep.Replace.Version
+				}
+				break
+			}
+		}
+	}
+
+	if version == "" || version == "(devel)" {
+		return "devel"
+	}
+
+	return strings.TrimPrefix(version, "v")
+}
+
+// Info is the build metadata, see `Get`.
+type Info struct {
+	Version   string `json:"version" yaml:"version" header:"Version"`
+	Library   string `json:"library" yaml:"library" header:"Library"`
+	Commit    string `json:"commit" yaml:"commit" header:"Commit"`
+	Date      string `json:"date" yaml:"date" header:"Date"`
+	GoVersion string `json:"goVersion" yaml:"goVersion" header:"Go"`
+	Platform  string `json:"platform" yaml:"platform" header:"Platform"`
+	// LensesAPI is the supported range of the Lenses versions, ">=4.0 <6.0".
+	LensesAPI string `json:"lensesAPI" yaml:"lensesAPI" header:"Lenses API"`
+}
+
+// Get returns the build metadata of the running binary.
+// The `Date` is formatted as RFC3339, in UTC, and the missing values are empty.
+func Get() Info {
+	return Info{
+		Version:   Version,
+		Library:   LibraryVersion(),
+		Commit:    Commit,
+		Date:      formatDate(Date),
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+		LensesAPI: ">=" + MinLensesVersion + " <" + MaxLensesVersion,
+	}
+}
+
+// formatDate returns the unix seconds "date" as RFC3339, or as it is if it's not a number.
+func formatDate(date string) string {
+	secs, err := strconv.ParseInt(date, 10, 64)
+	if err != nil {
+		return date
+	}
+
+	return time.Unix(secs, 0).UTC().Format(time.RFC3339)
+}
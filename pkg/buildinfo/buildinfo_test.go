@@ -0,0 +1,43 @@
+package buildinfo
+
+import (
+	"bytes"
+	"encoding/json"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet(t *testing.T) {
+	defer func(version, commit, date string) { Version, Commit, Date = version, commit, date }(Version, Commit, Date)
+
+	Version, Commit, Date = "4.1.0", "0a1b2c3", "1600000000"
+	info := Get()
+	assert.Equal(t, "4.1.0", info.Version)
+	assert.Equal(t, "devel", info.Library)
+	assert.Equal(t, "0a1b2c3", info.Commit)
+	assert.Equal(t, "2020-09-13T12:26:40Z", info.Date)
+	assert.Equal(t, runtime.Version(), info.GoVersion)
+	assert.Equal(t, runtime.GOOS+"/"+runtime.GOARCH, info.Platform)
+	assert.Equal(t, ">=4.0 <6.0", info.LensesAPI)
+
+	Date = "yesterday"
+	assert.Equal(t, "yesterday", Get().Date)
+}
+
+func TestVersionCommand(t *testing.T) {
+	defer func(version, commit, date string) { Version, Commit, Date = version, commit, date }(Version, Commit, Date)
+	Version, Commit, Date = "4.1.0", "0a1b2c3", ""
+
+	var out bytes.Buffer
+	cmd := NewVersionCommand()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--output", "json"})
+	require.NoError(t, cmd.Execute())
+
+	var info Info
+	require.NoError(t, json.Unmarshal(out.Bytes(), &info))
+	assert.Equal(t, Get(), info)
+}
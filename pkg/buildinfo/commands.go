@@ -0,0 +1,30 @@
+package buildinfo
+
+import (
+	"github.com/lensesio/bite"
+	"github.com/spf13/cobra"
+)
+
+//NewVersionCommand creates the `version` command
+func NewVersionCommand() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Print the version and the build information of the CLI",
+		Long: `Print the version of the CLI, its git commit, build date, Go version and platform,
+the version of the lenses-go library and the range of the Lenses versions it supports.`,
+		Example: `version
+version --output json`,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return bite.PrintObject(cmd, Get())
+		},
+	}
+
+	// the global output flags are not registered for the `version`, it does not need a configuration.
+	bite.RegisterOutPutFlag(cmd, &output)
+	bite.CanPrintJSON(cmd)
+
+	return cmd
+}
@@ -0,0 +1,192 @@
+// Package bulk runs the API calls of the commands over many resources, i.e the import and the export,
+// with a bounded number of concurrent workers, and reports the failures of all of them at the end.
+package bulk
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lensesio/lenses-go/pkg/progress"
+	"github.com/lensesio/lenses-go/pkg/throttle"
+	"github.com/spf13/cobra"
+)
+
+// DefaultConcurrency is the default value of the `--concurrency` flag.
+const DefaultConcurrency = 4
+
+// The statuses of a `Result`.
+const (
+	StatusOK     = "ok"
+	StatusFailed = "failed"
+)
+
+// Task is the work of a single resource.
+type Task struct {
+	// Name is the resource of the task, i.e "topic [orders]".
+	Name string
+	Run  func() error
+}
+
+// Result is the outcome of a `Task`.
+type Result struct {
+	Name     string
+	Status   string
+	Error    string
+	Duration time.Duration
+}
+
+// Run executes the "tasks" with at most "concurrency" of them at the same time, all the tasks run even if some fail.
+// The results are in the order of the tasks.
+func Run(concurrency int, tasks []Task) []Result {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]Result, len(tasks))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency && w < len(tasks); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = run(tasks[i])
+			}
+		}()
+	}
+
+	for i := range tasks {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
+
+func run(task Task) (result Result) {
+	result = Result{Name: task.Name, Status: StatusOK}
+	start := time.Now()
+
+	defer func() {
+		result.Duration = time.Since(start)
+		if r := recover(); r != nil {
+			result.Status, result.Error = StatusFailed, fmt.Sprint(r)
+		}
+	}()
+
+	if err := task.Run(); err != nil {
+		result.Status, result.Error = StatusFailed, err.Error()
+	}
+
+	return
+}
+
+// Error is the aggregated error of the failed tasks of a `Run`.
+type Error struct {
+	Failed []Result
+	Total  int
+}
+
+func (err *Error) Error() string {
+	msgs := make([]string, len(err.Failed))
+	for i, r := range err.Failed {
+		msgs[i] = r.Name + ": " + r.Error
+	}
+
+	return fmt.Sprintf("%d of %d failed: [%s]", len(err.Failed), err.Total, strings.Join(msgs, "; "))
+}
+
+// Err returns an `*Error` with the failed "results", if any.
+func Err(results []Result) error {
+	var failed []Result
+	for _, r := range results {
+		if r.Status == StatusFailed {
+			failed = append(failed, r)
+		}
+	}
+
+	if len(failed) == 0 {
+		return nil
+	}
+
+	return &Error{Failed: failed, Total: len(results)}
+}
+
+// PrintSummary writes a line per result to "w" and the totals.
+func PrintSummary(w io.Writer, results []Result) {
+	if len(results) == 0 {
+		return
+	}
+
+	var failed int
+	fmt.Fprintln(w)
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+			fmt.Fprintf(w, "%-8s %s (%s): %s\n", r.Status, r.Name, r.Duration.Round(time.Millisecond), r.Error)
+		} else {
+			fmt.Fprintf(w, "%-8s %s (%s)\n", r.Status, r.Name, r.Duration.Round(time.Millisecond))
+		}
+	}
+	fmt.Fprintf(w, "%d succeeded, %d failed\n", len(results)-failed, failed)
+}
+
+// RunAndReport is the `Run` of the "tasks" with the `--concurrency` and the `--rate` of the "cmd", it shows their progress,
+// prints the summary to the error output of the "cmd" and returns the `Err` of the results.
+func RunAndReport(cmd *cobra.Command, tasks []Task) error {
+	bar := progress.New(cmd, cmd.Name(), int64(len(tasks)))
+	limiter := Limiter(cmd)
+	tracked := make([]Task, len(tasks))
+	for i, task := range tasks {
+		task := task
+		tracked[i] = Task{Name: task.Name, Run: func() error {
+			defer bar.Increment()
+			limiter.Wait(1, 0, nil)
+			return task.Run()
+		}}
+	}
+
+	results := Run(Concurrency(cmd), tracked)
+	bar.Done()
+	PrintSummary(cmd.ErrOrStderr(), results)
+	return Err(results)
+}
+
+// AddConcurrencyFlag adds the `--concurrency` flag to the "cmd" and its sub commands.
+func AddConcurrencyFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().Int("concurrency", DefaultConcurrency, "The number of resources to process at the same time")
+}
+
+// Concurrency returns the `--concurrency` flag of the "cmd", or the `DefaultConcurrency` if it has not the flag.
+func Concurrency(cmd *cobra.Command) int {
+	if f := cmd.Flag("concurrency"); f != nil {
+		var n int
+		if _, err := fmt.Sscan(f.Value.String(), &n); err == nil && n > 0 {
+			return n
+		}
+	}
+
+	return DefaultConcurrency
+}
+
+// AddRateFlag adds the `--rate` flag, the maximum resources processed per second, to the "cmd" and its sub commands.
+func AddRateFlag(cmd *cobra.Command) {
+	var rate throttle.Rate
+	cmd.PersistentFlags().Var(&rate, "rate", "The maximum resources to process per second, i.e 10/s, 0 is unlimited")
+}
+
+// Limiter returns the limiter of the `--rate` flag of the "cmd", nil if it has not the flag or it's unlimited.
+func Limiter(cmd *cobra.Command) *throttle.Limiter {
+	if f := cmd.Flag("rate"); f != nil {
+		if rate, ok := f.Value.(*throttle.Rate); ok {
+			return throttle.New(*rate, 0)
+		}
+	}
+
+	return nil
+}
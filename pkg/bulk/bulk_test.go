@@ -0,0 +1,86 @@
+package bulk
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	var running, maxRunning int32
+
+	tasks := make([]Task, 10)
+	for i := range tasks {
+		i := i
+		tasks[i] = Task{
+			Name: fmt.Sprintf("topic [%d]", i),
+			Run: func() error {
+				n := atomic.AddInt32(&running, 1)
+				defer atomic.AddInt32(&running, -1)
+				for {
+					max := atomic.LoadInt32(&maxRunning)
+					if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+						break
+					}
+				}
+
+				time.Sleep(5 * time.Millisecond)
+				if i%4 == 0 {
+					return errors.New("boom")
+				}
+				return nil
+			},
+		}
+	}
+
+	results := Run(3, tasks)
+	assert.LessOrEqual(t, maxRunning, int32(3))
+	if assert.Len(t, results, 10) {
+		assert.Equal(t, "topic [0]", results[0].Name)
+		assert.Equal(t, StatusFailed, results[0].Status)
+		assert.Equal(t, StatusOK, results[1].Status)
+	}
+
+	err := Err(results)
+	assert.EqualError(t, err, "3 of 10 failed: [topic [0]: boom; topic [4]: boom; topic [8]: boom]")
+	assert.NoError(t, Err(results[1:4]))
+}
+
+func TestRunPanic(t *testing.T) {
+	results := Run(2, []Task{{Name: "a", Run: func() error { panic("oops") }}})
+	assert.Equal(t, StatusFailed, results[0].Status)
+	assert.Equal(t, "oops", results[0].Error)
+}
+
+func TestRunAndReport(t *testing.T) {
+	root := &cobra.Command{Use: "import"}
+	AddConcurrencyFlag(root)
+	AddRateFlag(root)
+	child := &cobra.Command{Use: "topics"}
+	root.AddCommand(child)
+
+	assert.Equal(t, DefaultConcurrency, Concurrency(child))
+	root.PersistentFlags().Set("concurrency", "8")
+	assert.Equal(t, 8, Concurrency(child))
+
+	assert.Nil(t, Limiter(child), "unlimited by default")
+	root.PersistentFlags().Set("rate", "100/s")
+	assert.NotNil(t, Limiter(child))
+
+	var buf bytes.Buffer
+	child.SetErr(&buf)
+	err := RunAndReport(child, []Task{
+		{Name: "topic [a]", Run: func() error { return nil }},
+		{Name: "topic [b]", Run: func() error { return errors.New("exists") }},
+	})
+
+	assert.EqualError(t, err, "1 of 2 failed: [topic [b]: exists]")
+	assert.Contains(t, buf.String(), "failed   topic [b] (")
+	assert.Contains(t, buf.String(), "1 succeeded, 1 failed\n")
+}
@@ -0,0 +1,82 @@
+package cluster
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lensesio/bite"
+	"github.com/lensesio/lenses-go/pkg/bulk"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/spf13/cobra"
+)
+
+//NewClusterGroupCommand creates `cluster` command
+func NewClusterGroupCommand() *cobra.Command {
+	root := &cobra.Command{
+		Use:              "cluster",
+		Short:            "Inspect the Kafka cluster",
+		SilenceErrors:    true,
+		TraverseChildren: true,
+	}
+
+	root.AddCommand(NewClusterHealthCommand())
+
+	return root
+}
+
+//NewClusterHealthCommand creates `cluster health` command
+func NewClusterHealthCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "health",
+		Short: "Report the controller, the offline and the under-replicated partitions of the Kafka cluster",
+		Long: `Report the controller status, the offline and the under-replicated partitions and the out-of-sync replicas of the Kafka cluster.
+It fails if there is no active controller or a partition is offline or under-replicated, so it can run as a cron based monitoring.`,
+		Example: `cluster health
+cluster health --output json --concurrency 16`,
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			report, err := Health(config.Client, bulk.Concurrency(cmd))
+			if err != nil {
+				return err
+			}
+
+			if strings.ToUpper(bite.GetOutPutFlag(cmd)) != "TABLE" {
+				if err = bite.PrintObject(cmd, report); err != nil {
+					return err
+				}
+			} else if err = printReport(cmd, report); err != nil {
+				return err
+			}
+
+			if !report.Healthy() {
+				return fmt.Errorf("cluster is not healthy, controller active [%t], [%d] offline and [%d] under-replicated partition(s)",
+					report.ControllerActive, report.Offline, report.UnderReplicated)
+			}
+
+			return nil
+		},
+	}
+
+	bulk.AddConcurrencyFlag(cmd)
+	bite.CanPrintJSON(cmd)
+
+	return cmd
+}
+
+// printReport prints the problems of the "report" as a table, followed by its totals.
+func printReport(cmd *cobra.Command, report HealthReport) error {
+	if len(report.Problems) > 0 {
+		if err := bite.PrintObject(cmd, report.Problems); err != nil {
+			return err
+		}
+	}
+
+	controller := fmt.Sprintf("broker %d", report.Controller)
+	if !report.ControllerActive {
+		controller = "none active"
+	}
+
+	return bite.PrintInfo(cmd, "Controller: %s, brokers: %d, topics: %d, partitions: %d, offline: %d, under-replicated: %d, out-of-sync replicas: %d",
+		controller, report.Brokers, report.Topics, report.Partitions, report.Offline, report.UnderReplicated, report.OutOfSyncReplicas)
+}
@@ -0,0 +1,127 @@
+// Package cluster provides the commands about the Kafka cluster as a whole, i.e its health.
+package cluster
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	"github.com/lensesio/lenses-go/pkg/bulk"
+)
+
+// The problems of a partition, see `PartitionProblem`.
+const (
+	ProblemOffline         = "offline"
+	ProblemUnderReplicated = "under-replicated"
+)
+
+// PartitionProblem is an offline or under-replicated partition of a `HealthReport`.
+type PartitionProblem struct {
+	Problem   string `json:"problem" yaml:"problem" header:"Problem"`
+	Topic     string `json:"topic" yaml:"topic" header:"Topic"`
+	Partition int    `json:"partition" yaml:"partition" header:"Partition"`
+	Leader    int    `json:"leader" yaml:"leader" header:"Leader"`
+	Replicas  []int  `json:"replicas" yaml:"replicas" header:"Replicas"`
+	ISR       []int  `json:"isr" yaml:"isr" header:"ISR"`
+	OutOfSync []int  `json:"outOfSync" yaml:"outOfSync" header:"Out Of Sync"`
+}
+
+// HealthReport is the health of the Kafka cluster, see `Health`.
+type HealthReport struct {
+	Controller        int                `json:"controller" yaml:"controller"`
+	ControllerActive  bool               `json:"controllerActive" yaml:"controllerActive"`
+	Brokers           int                `json:"brokers" yaml:"brokers"`
+	Topics            int                `json:"topics" yaml:"topics"`
+	Partitions        int                `json:"partitions" yaml:"partitions"`
+	UnderReplicated   int                `json:"underReplicated" yaml:"underReplicated"`
+	Offline           int                `json:"offline" yaml:"offline"`
+	OutOfSyncReplicas int                `json:"outOfSyncReplicas" yaml:"outOfSyncReplicas"`
+	Problems          []PartitionProblem `json:"problems" yaml:"problems"`
+}
+
+// Healthy reports whether the cluster has an active controller and no offline or under-replicated partitions.
+func (r HealthReport) Healthy() bool {
+	return r.ControllerActive && r.Offline == 0 && r.UnderReplicated == 0
+}
+
+// add adds the partitions of the "topic" to the report.
+func (r *HealthReport) add(topic string, partitions []api.TopicPartition) error {
+	r.Topics++
+	for _, p := range partitions {
+		if p.Leader == api.LeaderUnknown {
+			return fmt.Errorf("the server does not report the replicas of the partitions of topic [%s]", topic)
+		}
+
+		r.Partitions++
+		problem := PartitionProblem{Topic: topic, Partition: p.Partition, Leader: p.Leader, Replicas: p.Replicas,
+			ISR: p.InSyncReplicas, OutOfSync: p.OutOfSyncReplicas()}
+		r.OutOfSyncReplicas += len(problem.OutOfSync)
+
+		switch {
+		case p.Offline():
+			r.Offline++
+			problem.Problem = ProblemOffline
+		case p.UnderReplicated():
+			r.UnderReplicated++
+			problem.Problem = ProblemUnderReplicated
+		default:
+			continue
+		}
+
+		r.Problems = append(r.Problems, problem)
+	}
+
+	return nil
+}
+
+// Health returns the health report of the Kafka cluster, it reads the partitions of "concurrency" topics at the same time.
+func Health(client *api.Client, concurrency int) (HealthReport, error) {
+	var report HealthReport
+
+	cluster, err := client.GetKafkaCluster()
+	if err != nil {
+		return report, err
+	}
+
+	controller, active := cluster.Controller()
+	report.Controller, report.ControllerActive, report.Brokers = controller.ID, active, len(cluster.Brokers)
+
+	topics, err := client.GetTopicsNames()
+	if err != nil {
+		return report, err
+	}
+
+	var mu sync.Mutex
+	tasks := make([]bulk.Task, len(topics))
+	for i, topic := range topics {
+		topic := topic
+		tasks[i] = bulk.Task{Name: fmt.Sprintf("topic [%s]", topic), Run: func() error {
+			partitions, err := client.GetTopicPartitions(topic)
+			if err != nil {
+				return err
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			return report.add(topic, partitions)
+		}}
+	}
+
+	if err = bulk.Err(bulk.Run(concurrency, tasks)); err != nil {
+		return report, err
+	}
+
+	sort.SliceStable(report.Problems, func(i, j int) bool {
+		a, b := report.Problems[i], report.Problems[j]
+		if a.Problem != b.Problem {
+			return a.Problem < b.Problem // offline first.
+		}
+		if a.Topic != b.Topic {
+			return a.Topic < b.Topic
+		}
+		return a.Partition < b.Partition
+	})
+
+	return report, nil
+}
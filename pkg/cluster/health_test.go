@@ -0,0 +1,83 @@
+package cluster
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestClient(t *testing.T, controller string, partitions map[string]string) (*api.Client, func()) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/kafka/cluster":
+			w.Write([]byte(`{"controller":` + controller + `,"brokers":[{"id":1,"host":"b1","port":9092},{"id":2,"host":"b2","port":9092},{"id":3,"host":"b3","port":9092}]}`))
+		case "/api/topics":
+			w.Write([]byte(`[{"topicName":"orders"},{"topicName":"payments"}]`))
+		case "/api/topics/orders/partitions":
+			w.Write([]byte(partitions["orders"]))
+		case "/api/topics/payments/partitions":
+			w.Write([]byte(partitions["payments"]))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	client, err := api.OpenConnection(api.ClientConfig{Host: srv.URL, Token: "t0ken"})
+	if err != nil {
+		srv.Close()
+		t.Fatal(err)
+	}
+
+	return client, srv.Close
+}
+
+func TestHealth(t *testing.T) {
+	client, teardown := newTestClient(t, "2", map[string]string{
+		"orders":   `[{"partition":0,"leader":1,"replicas":[1,2,3],"isr":[1,2,3]},{"partition":1,"leader":2,"replicas":[2,3,1],"isr":[2]}]`,
+		"payments": `[{"partition":0,"leader":-1,"replicas":[3],"isr":[]},{"partition":1,"leader":1,"replicas":[1,2],"isr":[1,2]}]`,
+	})
+	defer teardown()
+
+	report, err := Health(client, 2)
+	assert.NoError(t, err)
+	assert.False(t, report.Healthy())
+	assert.Equal(t, HealthReport{
+		Controller:        2,
+		ControllerActive:  true,
+		Brokers:           3,
+		Topics:            2,
+		Partitions:        4,
+		UnderReplicated:   1,
+		Offline:           1,
+		OutOfSyncReplicas: 3,
+		Problems: []PartitionProblem{
+			{Problem: ProblemOffline, Topic: "payments", Partition: 0, Leader: -1, Replicas: []int{3}, ISR: []int{}, OutOfSync: []int{3}},
+			{Problem: ProblemUnderReplicated, Topic: "orders", Partition: 1, Leader: 2, Replicas: []int{2, 3, 1}, ISR: []int{2}, OutOfSync: []int{3, 1}},
+		},
+	}, report)
+}
+
+func TestHealthy(t *testing.T) {
+	partitions := map[string]string{
+		"orders":   `[{"partition":0,"leader":1,"replicas":[1,2],"isr":[2,1]}]`,
+		"payments": `[]`,
+	}
+
+	client, teardown := newTestClient(t, "3", partitions)
+	defer teardown()
+
+	report, err := Health(client, 1)
+	assert.NoError(t, err)
+	assert.True(t, report.Healthy())
+	assert.Empty(t, report.Problems)
+
+	client, teardown = newTestClient(t, "-1", partitions)
+	defer teardown()
+
+	report, err = Health(client, 1)
+	assert.NoError(t, err)
+	assert.False(t, report.ControllerActive)
+	assert.False(t, report.Healthy())
+}
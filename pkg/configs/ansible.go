@@ -0,0 +1,107 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/lensesio/bite"
+	"github.com/lensesio/lenses-go/pkg/exitcode"
+	"github.com/spf13/cobra"
+)
+
+// AnsibleResult is the output of a mutating command with the --ansible flag, the result of an Ansible module,
+// so the CLI can back a module with no parsing of its own.
+type AnsibleResult struct {
+	Changed bool         `json:"changed"`
+	Failed  bool         `json:"failed,omitempty"`
+	Msg     string       `json:"msg"`
+	Diff    *AnsibleDiff `json:"diff,omitempty"`
+	// Code and Kind are the exit code of a failure and its name, see the `exitcode` package.
+	Code int    `json:"code,omitempty"`
+	Kind string `json:"kind,omitempty"`
+}
+
+// AnsibleDiff is the state of the resource before and after the change, as shown by `ansible-playbook --diff`.
+type AnsibleDiff struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// ansibleRun is the output of the running command with the --ansible flag, see `StartAnsible`.
+type ansibleRun struct {
+	out      io.Writer
+	captured bytes.Buffer
+	// reported is set by `ReportChange`, changed is its result.
+	reported, changed bool
+}
+
+var ansible *ansibleRun
+
+// Ansible reports whether the output is the `AnsibleResult`, the --ansible flag is set.
+// The commands check the current state before they change it, so a run with nothing to do is not a change.
+func Ansible() bool {
+	return Manager != nil && Manager.Ansible
+}
+
+// StartAnsible captures the output of the mutating "cmd" if the --ansible flag is set, it's printed as the message
+// of its result by `FinishAnsible`, unless the command reports its result with `ReportChange`.
+func StartAnsible(cmd *cobra.Command) {
+	if !Ansible() || !IsMutating(cmd) || ansible != nil {
+		return
+	}
+
+	ansible = &ansibleRun{out: cmd.OutOrStdout()}
+	cmd.SetOut(&ansible.captured)
+}
+
+// FinishAnsible prints the result of the command that succeeded if it didn't report it itself, a change,
+// with its output as the message.
+func FinishAnsible(cmd *cobra.Command) error {
+	if ansible == nil {
+		return nil
+	}
+
+	cmd.SetOut(ansible.out)
+	if ansible.reported {
+		return nil
+	}
+
+	ansible.reported, ansible.changed = true, true
+	return writeAnsible(ansible.out, AnsibleResult{Changed: true, Msg: strings.TrimSpace(ansible.captured.String())})
+}
+
+// PrintAnsibleError writes the failed result of the "err" to "w" and returns its exit code.
+func PrintAnsibleError(w io.Writer, err error) int {
+	e := exitcode.New(err)
+	writeAnsible(w, AnsibleResult{Failed: true, Msg: err.Error(), Code: e.Code, Kind: e.Kind})
+	return e.Code
+}
+
+// ReportChange prints the result of a mutating command, whether it "changed" the resource, its message and the state
+// of the resource "before" and "after", either of them may be nil. Without the --ansible flag only the "msg" is printed.
+func ReportChange(cmd *cobra.Command, changed bool, msg string, before, after interface{}) error {
+	if ansible == nil {
+		return bite.PrintInfo(cmd, "%s", msg)
+	}
+
+	ansible.reported, ansible.changed = true, changed
+
+	result := AnsibleResult{Changed: changed, Msg: msg}
+	if changed && (before != nil || after != nil) {
+		result.Diff = &AnsibleDiff{Before: before, After: after}
+	}
+
+	return writeAnsible(ansible.out, result)
+}
+
+func writeAnsible(w io.Writer, result AnsibleResult) error {
+	b, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
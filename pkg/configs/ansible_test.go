@@ -0,0 +1,81 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/lensesio/bite"
+	"github.com/lensesio/lenses-go/pkg/exitcode"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnsible(t *testing.T) {
+	manager := Manager
+	Manager = NewEmptyConfigManager()
+	defer func() { Manager, ansible = manager, nil }()
+
+	var out bytes.Buffer
+	root := &cobra.Command{Use: "lenses-cli"}
+	root.SetOut(&out)
+	var output string
+	bite.RegisterOutPutFlagTo(root.PersistentFlags(), &output)
+
+	var changed bool
+	topic := &cobra.Command{Use: "topic"}
+	create := &cobra.Command{Use: "create", RunE: func(cmd *cobra.Command, _ []string) error {
+		return ReportChange(cmd, changed, "Topic [payments] created", nil, map[string]int{"partitions": 3})
+	}}
+	update := &cobra.Command{Use: "update", RunE: func(cmd *cobra.Command, _ []string) error {
+		return bite.PrintInfo(cmd, "Topic [payments] updated")
+	}}
+	topic.AddCommand(create, update)
+	root.AddCommand(topic)
+
+	run := func(args ...string) AnsibleResult {
+		out.Reset()
+		ansible = nil
+		root.SetArgs(args)
+		cmd, err := root.ExecuteC()
+		require.NoError(t, err)
+		require.NoError(t, FinishAnsible(cmd))
+
+		var result AnsibleResult
+		if Ansible() {
+			require.NoError(t, json.Unmarshal(out.Bytes(), &result), out.String())
+		}
+		return result
+	}
+
+	// without the flag only the message is printed.
+	root.PersistentPreRun = func(cmd *cobra.Command, _ []string) { StartAnsible(cmd) }
+	run("topic", "create")
+	assert.Equal(t, "Topic [payments] created\n", out.String())
+
+	Manager.Ansible = true
+	changed = true
+	result := run("topic", "create")
+	assert.True(t, result.Changed)
+	assert.Equal(t, "Topic [payments] created", result.Msg)
+	require.NotNil(t, result.Diff)
+	assert.Nil(t, result.Diff.Before)
+	assert.Equal(t, map[string]interface{}{"partitions": float64(3)}, result.Diff.After)
+
+	changed = false
+	result = run("topic", "create")
+	assert.False(t, result.Changed)
+	assert.Nil(t, result.Diff, "no diff when nothing changed")
+
+	// the commands that do not report their result are a change, their output is the message.
+	result = run("topic", "update")
+	assert.True(t, result.Changed)
+	assert.Equal(t, "Topic [payments] updated", result.Msg)
+
+	out.Reset()
+	code := PrintAnsibleError(&out, exitcode.WithCode(exitcode.NotFound, errors.New("topic [payments] not found")))
+	assert.Equal(t, exitcode.NotFound, code)
+	assert.JSONEq(t, `{"changed":false,"failed":true,"msg":"topic [payments] not found","code":2,"kind":"not_found"}`, out.String())
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultLastAppliedFilepath is the file of the keys of the fields last applied from the manifests, per context and resource,
+// the three-way merge of an apply removes only the fields that were applied before and are not declared anymore.
+var DefaultLastAppliedFilepath = filepath.Join(api.DefaultConfigurationHomeDir, "lenses-cli-last-applied.yml")
+
+// AppliedKey returns the key of the last applied fields of the "kind" resource of the "name".
+func AppliedKey(kind, name string) string {
+	return fmt.Sprintf("%s/%s", kind, name)
+}
+
+// readLastApplied reads the applied keys of the last applied file, per context and resource.
+func readLastApplied() (map[string]map[string][]string, error) {
+	applied := make(map[string]map[string][]string)
+
+	b, err := ioutil.ReadFile(DefaultLastAppliedFilepath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return applied, nil
+		}
+		return nil, err
+	}
+
+	if err = yaml.Unmarshal(b, &applied); err != nil {
+		return nil, fmt.Errorf("unable to read the last applied file [%s]: [%v]", DefaultLastAppliedFilepath, err)
+	}
+
+	if applied == nil {
+		// an empty file.
+		applied = make(map[string]map[string][]string)
+	}
+
+	return applied, nil
+}
+
+func writeLastApplied(applied map[string]map[string][]string) error {
+	b, err := yaml.Marshal(applied)
+	if err != nil {
+		return err
+	}
+
+	os.MkdirAll(filepath.Dir(DefaultLastAppliedFilepath), os.FileMode(0750))
+	return ioutil.WriteFile(DefaultLastAppliedFilepath, b, os.FileMode(0600))
+}
+
+// LastApplied returns the sorted keys of the fields last applied to the resource of the "key" of the "context", see `AppliedKey`.
+func LastApplied(context, key string) ([]string, error) {
+	applied, err := readLastApplied()
+	if err != nil {
+		return nil, err
+	}
+
+	return applied[context][key], nil
+}
+
+// SaveLastApplied saves the keys of the "fields" applied to the resource of the "key" of the "context",
+// no fields remove its entry. Their values are never stored, as they may hold secrets.
+func SaveLastApplied(context, key string, fields map[string]interface{}) error {
+	applied, err := readLastApplied()
+	if err != nil {
+		return err
+	}
+
+	if len(fields) == 0 {
+		delete(applied[context], key)
+		if len(applied[context]) == 0 {
+			delete(applied, context)
+		}
+	} else {
+		keys := make([]string, 0, len(fields))
+		for k := range fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		if applied[context] == nil {
+			applied[context] = make(map[string][]string)
+		}
+		applied[context][key] = keys
+	}
+
+	return writeLastApplied(applied)
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveLastApplied(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lenses-cli-last-applied")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	defaultLastAppliedFilepath := DefaultLastAppliedFilepath
+	DefaultLastAppliedFilepath = filepath.Join(dir, "last-applied.yml")
+	defer func() { DefaultLastAppliedFilepath = defaultLastAppliedFilepath }()
+
+	key := AppliedKey("Connector", "dev:jdbc")
+	fields := map[string]interface{}{"tasks.max": "2", "connection.user": "admin", "connection.password": "s3cr3t"}
+	assert.NoError(t, SaveLastApplied("prod", key, fields))
+
+	b, err := ioutil.ReadFile(DefaultLastAppliedFilepath)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(b), "s3cr3t")
+	assert.NotContains(t, string(b), "admin")
+
+	keys, err := LastApplied("prod", key)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"connection.password", "connection.user", "tasks.max"}, keys)
+
+	assert.NoError(t, SaveLastApplied("prod", key, nil))
+	keys, err = LastApplied("prod", key)
+	assert.NoError(t, err)
+	assert.Empty(t, keys)
+}
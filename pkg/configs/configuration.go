@@ -11,6 +11,7 @@ import (
 	"github.com/joho/godotenv"
 	"github.com/kataras/golog"
 	"github.com/lensesio/lenses-go/pkg/api"
+	"github.com/lensesio/lenses-go/pkg/exitcode"
 	"github.com/lensesio/lenses-go/pkg/utils"
 	"github.com/spf13/pflag"
 )
@@ -25,10 +26,44 @@ var Manager *ConfigurationManager
 type ConfigurationManager struct {
 	Config *api.Config
 	// flags below.
-	CurrentContext, host, timeout, token, user, pass, kerberosConf, kerberosRealm, kerberosKeytab, kerberosCCache string
-	insecure, debug, WaitForLenses                                                                                bool
+	CurrentContext, host, basePath, timeout, token, user, pass, kerberosConf, kerberosRealm, kerberosKeytab, kerberosCCache string
+	insecure, debug, WaitForLenses, Preflight, Yes, AllowProtected                                                          bool
 
 	Filepath string
+	// ErrorFormat is the format of the command's error output, "text" or "json", see the `exitcode` package.
+	ErrorFormat string
+	// invocationTimeout is the --invocation-timeout flag, see `InvocationTimeout`.
+	invocationTimeout string
+	// Vars are the `--set name=value` variables of the manifest files, see `ReadManifest`.
+	Vars []string
+	// CacheTTL is the time the slowly changing lookups are cached for, see `api.UsingCache`.
+	CacheTTL time.Duration
+	// Quiet disables the progress bars and the spinners of the long-running commands, see the `progress` package.
+	Quiet bool
+	// DebugHTTP dumps the HTTP requests, the responses and the websocket frames to the standard error, see `api.UsingDebugOut`.
+	DebugHTTP bool
+	// RequestID is the correlation id of the invocation, sent with all of its requests, see `api.UsingRequestID`.
+	// If empty, a new one is generated per invocation.
+	RequestID string
+	// UserAgent is the User-Agent of the requests and the websocket handshakes, see `api.UsingUserAgent`.
+	// If empty, it's the `api.UserAgent`.
+	UserAgent string
+	// Lock makes the apply and import commands hold the lock of the current context, see `WithLock`.
+	// LockFile is the lock file instead of the one per context in the `DefaultLocksDir`, it implies Lock.
+	Lock     bool
+	LockFile string
+	// ForceUnlock removes the lock of the current context before acquiring it.
+	ForceUnlock bool
+	// Reason is the note of the change of a mutating command, recorded in the changelog of the context, see `RecordChange`.
+	Reason string
+	// Ansible prints the result of a mutating command as the one of an Ansible module, see `ReportChange`.
+	Ansible bool
+
+	// CredentialStore is the store of the tokens and the passwords on save, see `StoreKeyring` and `StorePassphrase`.
+	// If empty, it's the store of the loaded configuration file, the `EnvCredentialStore` or the `StoreLegacy`.
+	CredentialStore string
+	passphrase      string
+	opened          map[string]bool // the contexts with opened secrets.
 }
 
 /*
@@ -60,6 +95,7 @@ func NewConfigurationManager(set *pflag.FlagSet) *ConfigurationManager {
 	set.StringVar(&m.CurrentContext, "context", "", "Load specific environment, embedded configuration based on the configuration's 'Contexts'")
 
 	set.StringVar(&m.host, "host", "", "Lenses host")
+	set.StringVar(&m.basePath, "base-path", "", "The path prefix of a Lenses behind a sub-path proxy, i.e /lenses")
 	// basic auth.
 
 	// if --kerberos-conf set and not other kerberos-* flag set,
@@ -82,6 +118,22 @@ func NewConfigurationManager(set *pflag.FlagSet) *ConfigurationManager {
 
 	set.StringVar(&m.Filepath, "config", "", "Load or save the host, user, pass and debug fields from or to a configuration file (yaml or json)")
 	set.BoolVar(&m.WaitForLenses, "wait-for-lenses", false, "when set will wait for Lenses server to respond")
+	set.BoolVar(&m.Preflight, "preflight", false, "Check the current user's permissions before destructive operations and fail fast if any is missing")
+	set.BoolVar(&m.Yes, "yes", false, "Skip the confirmation of destructive operations")
+	set.BoolVar(&m.AllowProtected, "allow-protected", false, "Allow mutating commands against a protected context")
+	set.StringVar(&m.invocationTimeout, "invocation-timeout", "", "Timeout of the whole invocation, i.e 5m, it exits with code 7 when it elapses, none if empty")
+	set.StringVar(&m.ErrorFormat, "error-format", exitcode.FormatText, "The format of the error output, 'text' or 'json' with the error's code and kind")
+	set.StringArrayVar(&m.Vars, "set", nil, "A variable of the manifest files, name=value, repeat it for more")
+	set.DurationVar(&m.CacheTTL, "cache-ttl", 0, "Cache the topics, the schema subjects and the connector plugins for this long, i.e 30s, the cache is cleared by any change")
+	set.BoolVar(&m.Quiet, "quiet", false, "Do not print the progress bars and the spinners of the long-running commands")
+	set.StringVar(&m.RequestID, "request-id", "", "The X-Request-Id of the requests of this invocation, to look them up in the Lenses logs, a new one is generated if empty")
+	set.StringVar(&m.UserAgent, "user-agent", "", "The User-Agent of the requests, to tell this automation apart in the Lenses audit logs, 'lenses-go/<version>; cli/<version>; <os>/<arch>' if empty")
+	set.BoolVar(&m.Lock, "lock", false, "Hold the lock of the current context while an apply or import runs, so concurrent runs fail fast")
+	set.StringVar(&m.LockFile, "lock-file", "", "The lock file of --lock, i.e on a volume shared by the CI runners, it implies --lock")
+	set.BoolVar(&m.ForceUnlock, "force-unlock", false, "Remove the lock of the current context, left by a run that's gone, before acquiring it")
+	set.StringVar(&m.Reason, "reason", "", `A note of why a mutating command runs, i.e "JIRA-123 increase retention", printed by the history command`)
+	set.BoolVar(&m.Ansible, "ansible", false, `Print the result of a mutating command as the JSON of an Ansible module, {"changed": ..., "msg": ..., "diff": ...}, a command with nothing to do is not a change`)
+	set.BoolVar(&m.DebugHTTP, "debug-http", false, "Dump the HTTP requests and responses and the websocket frames to the standard error, with the tokens and the passwords redacted")
 	return m
 }
 
@@ -124,35 +176,52 @@ func (m *ConfigurationManager) Load() (bool, error) {
 
 	c.SetCurrent(currentContext)
 
-	// authentication flags passed, override or set the particular authentication method.
-	authFromFlags, authLoadedFromFlags := makeAuthFromFlags(m.user, m.pass, m.kerberosConf, m.kerberosRealm, m.kerberosKeytab, m.kerberosCCache)
-	if authLoadedFromFlags {
-		c.GetCurrent().Authentication = authFromFlags
+	m.opened = nil
+	if found {
+		if err := m.openSecrets(c.CurrentContext, c.GetCurrent()); err != nil {
+			return false, err
+		}
+	}
+
+	// the settings' priority is: flags, environment variables, configuration file and the mounted secret,
+	// so the CLI can run inside pods and CI runners without a configuration file.
+	godotenv.Load()
+	flags := credentials{
+		host: m.host, basePath: m.basePath, token: m.token, timeout: m.timeout, insecure: m.insecure, debug: m.debug,
+		user: m.user, pass: m.pass, kerberosConf: m.kerberosConf, kerberosRealm: m.kerberosRealm,
+		kerberosKeytab: m.kerberosKeytab, kerberosCCache: m.kerberosCCache,
+	}
+	env := credentialsFromEnv()
+
+	// the secret fills only what the configuration file is missing.
+	authLoaded := fillMissing(c.GetCurrent(), credentialsFromSecret(secretsDir()))
+
+	// authentication flags or environment variables passed, override or set the particular authentication method.
+	for _, cr := range []credentials{env, flags} {
+		if auth, ok := cr.auth(); ok {
+			c.GetCurrent().Authentication = auth
+			authLoaded = true
+		}
 	}
 
 	// flags have always priority, so transfer any non-empty client configuration flag to the current,
 	// so far we don't care about the configuration file found or not.
-	c.GetCurrent().Fill(api.ClientConfig{
-		Host:     m.host,
-		Token:    m.token,
-		Timeout:  m.timeout,
-		Insecure: m.insecure,
-		Debug:    m.debug,
-	})
+	c.GetCurrent().Fill(env.clientConfig())
+	c.GetCurrent().Fill(flags.clientConfig())
 
 	if found {
 
 		if currentContextChanged {
 			// save the config, the current context changed.
-			for _, v := range c.Contexts {
-				DecryptPassword(v)
+			if err := m.openAllSecrets(); err != nil {
+				return false, err
 			}
 			if err := m.Save(); err != nil {
 				return false, err
 			}
 		} else {
-			// check if loaded from flags, if so and we proceed then the password field goes empty.
-			if !authLoadedFromFlags {
+			// check if loaded from flags, env or secret, if so and we proceed then the password field goes empty.
+			if !authLoaded {
 				// try to set the current context from *.env file or from system 's env variables,
 				// if not empty, the env value has a priority over the configurated `CurrentContext`
 				// but --context flag has a priority over all (look above).
@@ -162,8 +231,8 @@ func (m *ConfigurationManager) Load() (bool, error) {
 				if envContext := strings.TrimSpace(os.Getenv(currentContextEnvKey)); envContext != "" {
 					c.CurrentContext = envContext
 				}
-				for _, v := range c.Contexts {
-					DecryptPassword(v)
+				if err := m.openAllSecrets(); err != nil {
+					return false, err
 				}
 			}
 		}
@@ -180,11 +249,11 @@ func (m *ConfigurationManager) Load() (bool, error) {
 func (m *ConfigurationManager) Save() error {
 	c := m.Config.Clone() // copy the configuration so all changes here will not be present after the save().
 
-	// we encrypt every password (main and contexts) because
+	// we encrypt every secret (main and contexts) because
 	// they are decrypted on load, even if user didn't select to update a specific context.
-	for _, v := range c.Contexts {
+	for name, v := range c.Contexts {
 		v.FormatHost()
-		if err := EncryptPassword(v); err != nil {
+		if err := m.sealSecrets(name, v); err != nil {
 			return err
 		}
 	}
@@ -271,7 +340,7 @@ func SetupClient() (err error) {
 		host := Manager.Config.GetCurrent().Host
 		for {
 			golog.Infof("waiting for host '%s' to respond...", host)
-			Client, err = api.OpenConnection(*Manager.Config.GetCurrent())
+			Client, err = api.OpenConnection(*Manager.Config.GetCurrent(), Manager.clientOptions()...)
 			if err == nil {
 				golog.Infof("connection to '%s' succeeded!", host)
 				break
@@ -281,10 +350,57 @@ func SetupClient() (err error) {
 			time.Sleep(5 * time.Second)
 		}
 	}
-	Client, err = api.OpenConnection(*Manager.Config.GetCurrent())
+	Client, err = api.OpenConnection(*Manager.Config.GetCurrent(), Manager.clientOptions()...)
 	return
 }
 
+// ContextClient returns a new client of the "context" of the configuration file, i.e to read from an environment
+// and write to another one, the current context's client is the `Client`.
+func (m *ConfigurationManager) ContextClient(context string) (*api.Client, error) {
+	if context == m.Config.CurrentContext && Client != nil {
+		return Client, nil
+	}
+
+	cfg, ok := m.Config.Contexts[context]
+	if !ok {
+		return nil, fmt.Errorf("unknown context [%s]", context)
+	}
+
+	if err := m.openSecrets(context, cfg); err != nil {
+		return nil, err
+	}
+
+	return api.OpenConnection(*cfg, m.contextClientOptions(context, cfg)...)
+}
+
+// clientOptions returns the connection options of the flags, the cached session, the response cache, the debug dump,
+// the request id of the invocation and the user agent.
+func (m *ConfigurationManager) clientOptions() []api.ConnectionOption {
+	return m.contextClientOptions(m.Config.CurrentContext, m.Config.GetCurrent())
+}
+
+// contextClientOptions returns the connection options of the "context" whose configuration is "cfg", see `clientOptions`.
+func (m *ConfigurationManager) contextClientOptions(context string, cfg *api.ClientConfig) []api.ConnectionOption {
+	if m.RequestID == "" {
+		m.RequestID = api.NewRequestID()
+	}
+
+	options := []api.ConnectionOption{m.contextSessionOption(context, cfg), api.UsingRequestID(m.RequestID)}
+	if m.CacheTTL > 0 {
+		options = append(options, api.UsingCache(m.CacheTTL))
+	}
+
+	if m.DebugHTTP {
+		options = append(options, api.UsingDebugOut(os.Stderr))
+	}
+
+	if m.UserAgent != "" {
+		options = append(options, api.UsingUserAgent(m.UserAgent))
+	}
+
+	return options
+}
+
 func makeAuthFromFlags(user, pass, kerberosConf, kerberosRealm, kerberosKeytab, kerberosCCache string) (api.Authentication, bool) {
 	if kerberosConf != "" {
 		auth := api.KerberosAuthentication{
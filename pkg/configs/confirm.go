@@ -0,0 +1,53 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/lensesio/lenses-go/pkg/exitcode"
+	"golang.org/x/term"
+)
+
+// EnvConfirm set to "always" requires the confirmation of the destructive commands even when they don't run in a terminal,
+// so they fail unless the --yes flag is passed. It's meant to be set organization-wide, i.e on CI runners of production.
+const EnvConfirm = "LENSES_CONFIRM"
+
+// isTerminal reports whether the standard input is a terminal, it can be replaced for tests.
+var isTerminal = func() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// promptConfirm asks for the "name" to confirm, it can be replaced for tests.
+var promptConfirm = func(message string) (string, error) {
+	var answer string
+	err := survey.AskOne(&survey.Input{Message: message}, &answer)
+	return answer, err
+}
+
+// Confirm asks the user to type the "name" of the "resource", i.e "topic", before a destructive command deletes it.
+// The --yes flag skips the confirmation. Without a terminal the command proceeds, unless the `EnvConfirm` is "always".
+func Confirm(resource, name string) error {
+	if Manager != nil && Manager.Yes {
+		return nil
+	}
+
+	if !isTerminal() {
+		if strings.EqualFold(strings.TrimSpace(os.Getenv(EnvConfirm)), "always") {
+			return exitcode.WithCode(exitcode.Validation, fmt.Errorf("confirmation required to delete the %s [%s], pass the --yes flag", resource, name))
+		}
+		return nil
+	}
+
+	answer, err := promptConfirm(fmt.Sprintf("Type [%s] to confirm the deletion of the %s", name, resource))
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(answer) != name {
+		return exitcode.WithCode(exitcode.Validation, fmt.Errorf("[%s] does not match the %s [%s], aborted", strings.TrimSpace(answer), resource, name))
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfirm(t *testing.T) {
+	defaultManager, defaultIsTerminal, defaultPromptConfirm := Manager, isTerminal, promptConfirm
+	defer func() { Manager, isTerminal, promptConfirm = defaultManager, defaultIsTerminal, defaultPromptConfirm }()
+
+	var (
+		terminal bool
+		answer   string
+		prompts  int
+	)
+	isTerminal = func() bool { return terminal }
+	promptConfirm = func(string) (string, error) {
+		prompts++
+		return answer, nil
+	}
+
+	tests := []struct {
+		name     string
+		yes      bool
+		terminal bool
+		always   bool
+		answer   string
+		prompted bool
+		ok       bool
+	}{
+		{name: "terminal, matching answer", terminal: true, answer: "topic1\n", prompted: true, ok: true},
+		{name: "terminal, wrong answer", terminal: true, answer: "topic2", prompted: true},
+		{name: "terminal, --yes", terminal: true, yes: true, ok: true},
+		{name: "no terminal", ok: true},
+		{name: "no terminal, always", always: true},
+		{name: "no terminal, always, --yes", always: true, yes: true, ok: true},
+	}
+
+	for _, tt := range tests {
+		Manager = NewEmptyConfigManager()
+		Manager.Yes = tt.yes
+		terminal, answer, prompts = tt.terminal, tt.answer, 0
+		if tt.always {
+			os.Setenv(EnvConfirm, "always")
+		}
+
+		err := Confirm("topic", "topic1")
+		os.Unsetenv(EnvConfirm)
+
+		assert.Equal(t, tt.ok, err == nil, tt.name)
+		assert.Equal(t, tt.prompted, prompts == 1, tt.name)
+	}
+}
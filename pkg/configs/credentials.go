@@ -0,0 +1,156 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+)
+
+// The environment variables of the connection settings, they have priority over the configuration file
+// but not over the flags. See `Load` for the whole order.
+const (
+	EnvHost           = "LENSES_HOST"
+	EnvBasePath       = "LENSES_BASE_PATH"
+	EnvToken          = "LENSES_TOKEN"
+	EnvUser           = "LENSES_USER"
+	EnvPassword       = "LENSES_PASSWORD"
+	EnvTimeout        = "LENSES_TIMEOUT"
+	EnvInsecure       = "LENSES_INSECURE"
+	EnvDebug          = "LENSES_DEBUG"
+	EnvKerberosConf   = "LENSES_KERBEROS_CONF"
+	EnvKerberosRealm  = "LENSES_KERBEROS_REALM"
+	EnvKerberosKeytab = "LENSES_KERBEROS_KEYTAB"
+	EnvKerberosCCache = "LENSES_KERBEROS_CCACHE"
+	// EnvCACert, EnvClientCert and EnvClientKey are the TLS material, base64 encoded PEM, see `api.ClientConfig.TLSConfig`.
+	EnvCACert     = "LENSES_CA_CERT"
+	EnvClientCert = "LENSES_CLIENT_CERT"
+	EnvClientKey  = "LENSES_CLIENT_KEY"
+	// EnvSecretsDir sets the directory of the mounted secret, see `DefaultSecretsDir`.
+	EnvSecretsDir = "LENSES_SECRETS_DIR"
+)
+
+// DefaultSecretsDir is the directory where a Kubernetes secret (or ConfigMap) with the connection settings
+// is mounted by default. Each setting is a file named after its environment variable without the "LENSES_" prefix,
+// in lower case, i.e "host", "token", "user" and "password".
+// The secret has the lowest priority, it fills only the settings missing from the configuration file.
+var DefaultSecretsDir = "/var/run/secrets/lenses"
+
+// credentials are the connection settings of a single source: the flags, the environment or a mounted secret.
+type credentials struct {
+	host, basePath, token, timeout, user, pass, kerberosConf, kerberosRealm, kerberosKeytab, kerberosCCache string
+	caCert, clientCert, clientKey                                                                           string
+	insecure, debug                                                                                         bool
+}
+
+func (cr credentials) auth() (api.Authentication, bool) {
+	return makeAuthFromFlags(cr.user, cr.pass, cr.kerberosConf, cr.kerberosRealm, cr.kerberosKeytab, cr.kerberosCCache)
+}
+
+func (cr credentials) clientConfig() api.ClientConfig {
+	return api.ClientConfig{
+		Host:       cr.host,
+		BasePath:   cr.basePath,
+		Token:      cr.token,
+		Timeout:    cr.timeout,
+		Insecure:   cr.insecure,
+		Debug:      cr.debug,
+		CACert:     cr.caCert,
+		ClientCert: cr.clientCert,
+		ClientKey:  cr.clientKey,
+	}
+}
+
+// credentialsFrom reads the settings by their environment variable's name, through the "get" function.
+func credentialsFrom(get func(key string) string) credentials {
+	flag := func(key string) bool {
+		v, _ := strconv.ParseBool(get(key))
+		return v
+	}
+
+	return credentials{
+		host:           get(EnvHost),
+		basePath:       get(EnvBasePath),
+		token:          get(EnvToken),
+		timeout:        get(EnvTimeout),
+		user:           get(EnvUser),
+		pass:           get(EnvPassword),
+		kerberosConf:   get(EnvKerberosConf),
+		kerberosRealm:  get(EnvKerberosRealm),
+		kerberosKeytab: get(EnvKerberosKeytab),
+		kerberosCCache: get(EnvKerberosCCache),
+		caCert:         get(EnvCACert),
+		clientCert:     get(EnvClientCert),
+		clientKey:      get(EnvClientKey),
+		insecure:       flag(EnvInsecure),
+		debug:          flag(EnvDebug),
+	}
+}
+
+func credentialsFromEnv() credentials {
+	return credentialsFrom(func(key string) string {
+		return strings.TrimSpace(os.Getenv(key))
+	})
+}
+
+// credentialsFromSecret reads the settings from the files of the mounted secret's "dir", if it exists.
+func credentialsFromSecret(dir string) credentials {
+	return credentialsFrom(func(key string) string {
+		name := strings.ToLower(strings.TrimPrefix(key, "LENSES_"))
+		b, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(b))
+	})
+}
+
+func secretsDir() string {
+	if dir := strings.TrimSpace(os.Getenv(EnvSecretsDir)); dir != "" {
+		return dir
+	}
+
+	return DefaultSecretsDir
+}
+
+// fillMissing sets the settings of the "cr" that are missing from the "cfg".
+// It reports whether the authentication was set.
+func fillMissing(cfg *api.ClientConfig, cr credentials) bool {
+	if cfg.Host == "" {
+		cfg.Host = cr.host
+	}
+
+	if cfg.BasePath == "" {
+		cfg.BasePath = cr.basePath
+	}
+
+	if cfg.Timeout == "" {
+		cfg.Timeout = cr.timeout
+	}
+
+	if cfg.CACert == "" {
+		cfg.CACert = cr.caCert
+	}
+
+	if cfg.ClientCert == "" && cfg.ClientKey == "" {
+		cfg.ClientCert, cfg.ClientKey = cr.clientCert, cr.clientKey
+	}
+
+	cfg.Insecure = cfg.Insecure || cr.insecure
+	cfg.Debug = cfg.Debug || cr.debug
+
+	if cfg.Token != "" || cfg.Authentication != nil {
+		return false
+	}
+
+	cfg.Token = cr.token
+	if auth, ok := cr.auth(); ok && cr.token == "" {
+		cfg.Authentication = auth
+		return true
+	}
+
+	return false
+}
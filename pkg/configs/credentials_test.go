@@ -0,0 +1,142 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeSecret(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "lenses-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, value := range files {
+		if err = ioutil.WriteFile(filepath.Join(dir, name), []byte(value+"\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func setEnv(t *testing.T, env map[string]string) func() {
+	for k, v := range env {
+		os.Setenv(k, v)
+	}
+
+	return func() {
+		for k := range env {
+			os.Unsetenv(k)
+		}
+	}
+}
+
+func TestLoadCredentialsPriority(t *testing.T) {
+	secret := writeSecret(t, map[string]string{"host": "http://secret:9991", "user": "secret-user", "password": "secret-pass", "timeout": "5s"})
+	defer os.RemoveAll(secret)
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		flags    func(m *ConfigurationManager)
+		host     string
+		auth     api.Authentication
+		token    string
+		insecure bool
+	}{
+		{
+			name: "secret only",
+			env:  map[string]string{EnvSecretsDir: secret},
+			host: "http://secret:9991",
+			auth: api.BasicAuthentication{Username: "secret-user", Password: "secret-pass"},
+		},
+		{
+			name:     "env overrides secret",
+			env:      map[string]string{EnvSecretsDir: secret, EnvHost: "http://env:9991", EnvUser: "env-user", EnvPassword: "env-pass", EnvInsecure: "true"},
+			host:     "http://env:9991",
+			auth:     api.BasicAuthentication{Username: "env-user", Password: "env-pass"},
+			insecure: true,
+		},
+		{
+			name:  "flags override env",
+			env:   map[string]string{EnvSecretsDir: secret, EnvHost: "http://env:9991", EnvToken: "env-token"},
+			flags: func(m *ConfigurationManager) { m.host = "http://flag:9991"; m.token = "flag-token" },
+			host:  "http://flag:9991",
+			auth:  api.BasicAuthentication{Username: "secret-user", Password: "secret-pass"},
+			token: "flag-token",
+		},
+	}
+
+	for _, tt := range tests {
+		restore := setEnv(t, tt.env)
+
+		m := NewConfigurationManager(pflag.NewFlagSet("test", pflag.ContinueOnError))
+		if tt.flags != nil {
+			tt.flags(m)
+		}
+
+		ok, err := m.Load()
+		restore()
+
+		if err != nil {
+			t.Fatalf("[%s] %v", tt.name, err)
+		}
+
+		current := m.Config.GetCurrent()
+		assert.True(t, ok, tt.name)
+		assert.Equal(t, tt.host, current.Host, tt.name)
+		assert.Equal(t, tt.auth, current.Authentication, tt.name)
+		assert.Equal(t, tt.token, current.Token, tt.name)
+		assert.Equal(t, "5s", current.Timeout, tt.name)
+		assert.Equal(t, tt.insecure, current.Insecure, tt.name)
+	}
+}
+
+func TestLoadEnvOnly(t *testing.T) {
+	empty, err := ioutil.TempDir("", "lenses-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(empty)
+
+	restore := setEnv(t, map[string]string{EnvSecretsDir: empty, EnvHost: "https://env:9991", EnvToken: "env-token",
+		EnvCACert: "Y2E=", EnvClientCert: "Y2VydA==", EnvClientKey: "a2V5", EnvTimeout: "30s", EnvInvocationTimeout: "2m"})
+	defer restore()
+
+	m := NewConfigurationManager(pflag.NewFlagSet("test", pflag.ContinueOnError))
+	ok, err := m.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	current := m.Config.GetCurrent()
+	assert.True(t, ok)
+	assert.Equal(t, "https://env:9991", current.Host)
+	assert.Equal(t, "env-token", current.Token)
+	assert.Equal(t, "Y2E=", current.CACert)
+	assert.Equal(t, "Y2VydA==", current.ClientCert)
+	assert.Equal(t, "a2V5", current.ClientKey)
+
+	// the --timeout is the one of the connection only.
+	assert.Equal(t, "30s", current.Timeout)
+	timeout, err := m.InvocationTimeout()
+	assert.NoError(t, err)
+	assert.Equal(t, 2*time.Minute, timeout)
+
+	m.invocationTimeout = "10m"
+	timeout, err = m.InvocationTimeout()
+	assert.NoError(t, err)
+	assert.Equal(t, 10*time.Minute, timeout)
+
+	m.invocationTimeout = "soon"
+	_, err = m.InvocationTimeout()
+	assert.EqualError(t, err, "invalid invocation timeout [soon], expected a positive duration, i.e 5m")
+}
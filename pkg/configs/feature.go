@@ -0,0 +1,47 @@
+package config
+
+import (
+	"github.com/kataras/golog"
+	"github.com/spf13/cobra"
+)
+
+// AnnotationFeature is the command annotation with the feature of the API, see `api.Features`,
+// that the command and its sub commands require, see `RequireFeature`.
+const AnnotationFeature = "feature"
+
+// RequireFeature marks the "cmd", and its sub commands, as requiring the "feature" of the API and returns it,
+// so they fail with a "requires Lenses >= X" error against older servers, see `CheckFeature`.
+func RequireFeature(feature string, cmd *cobra.Command) *cobra.Command {
+	if cmd.Annotations == nil {
+		cmd.Annotations = make(map[string]string)
+	}
+	cmd.Annotations[AnnotationFeature] = feature
+
+	return cmd
+}
+
+// CheckFeature returns an `api.UnsupportedError` if the "cmd", or one of its parents,
+// requires a feature that the Lenses server does not support. The server version is fetched only for these commands.
+func CheckFeature(cmd *cobra.Command) error {
+	for c := cmd; c != nil; c = c.Parent() {
+		feature, ok := c.Annotations[AnnotationFeature]
+		if !ok {
+			continue
+		}
+
+		if Client == nil {
+			return nil
+		}
+
+		info, err := Client.ServerInfo()
+		if err != nil {
+			// the server has the final word.
+			golog.Debugf("unable to retrieve the server version: [%v]", err)
+			return nil
+		}
+
+		return info.Require(feature)
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	"github.com/lensesio/lenses-go/pkg/exitcode"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckFeature(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/api/config", r.URL.Path)
+		w.Write([]byte(`{"lenses.version":"4.0.5"}`))
+	}))
+	defer srv.Close()
+
+	client, err := api.OpenConnection(api.ClientConfig{Host: srv.URL, Token: "t0ken"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defaultClient := Client
+	defer func() { Client = defaultClient }()
+	Client = client
+
+	root := &cobra.Command{Use: "lenses-cli"}
+	topics := &cobra.Command{Use: "topics"}
+	connections := RequireFeature(api.FeatureConnections, &cobra.Command{Use: "connections"})
+	list := &cobra.Command{Use: "list"}
+	connections.AddCommand(list)
+	datasets := RequireFeature(api.FeatureDatasets, &cobra.Command{Use: "datasets"})
+	root.AddCommand(topics, connections, datasets)
+
+	assert.NoError(t, CheckFeature(topics))
+	assert.Equal(t, 0, requests, "no server version for the commands without a feature")
+
+	err = CheckFeature(list)
+	if assert.Error(t, err) {
+		assert.Equal(t, "connections requires Lenses >= 4.1, the server is [4.0.5]", err.Error())
+		assert.Equal(t, exitcode.Unsupported, exitcode.Of(err))
+	}
+
+	assert.NoError(t, CheckFeature(datasets))
+	assert.Equal(t, 1, requests, "the server version is fetched once")
+}
@@ -0,0 +1,168 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lensesio/bite"
+	"github.com/lensesio/lenses-go/pkg/api"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultHistoryFilepath is the changelog file of the mutating commands, per context, see `RecordChange`.
+var DefaultHistoryFilepath = filepath.Join(api.DefaultConfigurationHomeDir, "lenses-cli-history.yml")
+
+// maxHistory is the number of the changes kept per context, the oldest ones are dropped.
+const maxHistory = 1000
+
+// Change is an entry of the changelog of a context, a mutating command that succeeded.
+type Change struct {
+	Time    time.Time `json:"time" yaml:"time" header:"Time"`
+	User    string    `json:"user" yaml:"user" header:"User"`
+	Command string    `json:"command" yaml:"command" header:"Command"`
+	Reason  string    `json:"reason,omitempty" yaml:"reason,omitempty" header:"Reason"`
+}
+
+func readHistory() (map[string][]Change, error) {
+	history := make(map[string][]Change)
+
+	b, err := ioutil.ReadFile(DefaultHistoryFilepath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return history, nil
+		}
+		return nil, err
+	}
+
+	if err = yaml.Unmarshal(b, &history); err != nil {
+		return nil, fmt.Errorf("unable to read the history file [%s]: [%v]", DefaultHistoryFilepath, err)
+	}
+
+	return history, nil
+}
+
+func writeHistory(history map[string][]Change) error {
+	b, err := yaml.Marshal(history)
+	if err != nil {
+		return err
+	}
+
+	os.MkdirAll(filepath.Dir(DefaultHistoryFilepath), os.FileMode(0750))
+	return ioutil.WriteFile(DefaultHistoryFilepath, b, os.FileMode(0600))
+}
+
+// History returns the last "limit" changes of the "context", the most recent first, zero is all of them.
+func History(context string, limit int) ([]Change, error) {
+	history, err := readHistory()
+	if err != nil {
+		return nil, err
+	}
+
+	changes := history[context]
+	if limit > 0 && len(changes) > limit {
+		changes = changes[len(changes)-limit:]
+	}
+
+	recent := make([]Change, len(changes))
+	for i, c := range changes {
+		recent[len(changes)-1-i] = c
+	}
+
+	return recent, nil
+}
+
+// AddChange appends the "change" to the changelog of the "context".
+func AddChange(context string, change Change) error {
+	history, err := readHistory()
+	if err != nil {
+		return err
+	}
+
+	changes := append(history[context], change)
+	if len(changes) > maxHistory {
+		changes = changes[len(changes)-maxHistory:]
+	}
+
+	history[context] = changes
+	return writeHistory(history)
+}
+
+// commandLine returns the command line of the "cmd", with the values of the credential flags redacted.
+func commandLine(cmd *cobra.Command, args []string) string {
+	line := []string{cmd.CommandPath()}
+	line = append(line, args...)
+
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if f.Name == "reason" {
+			return
+		}
+
+		value := f.Value.String()
+		if name := strings.ToLower(f.Name); strings.Contains(name, "pass") || strings.Contains(name, "token") || strings.Contains(name, "secret") {
+			value = "***"
+		}
+
+		line = append(line, fmt.Sprintf("--%s=%s", f.Name, value))
+	})
+
+	return strings.Join(line, " ")
+}
+
+// RecordChange adds the "cmd" to the changelog of the current context with the --reason flag, if it's mutating.
+// It's called after each command that succeeded.
+func RecordChange(cmd *cobra.Command, args []string) error {
+	if Manager == nil || Manager.Config == nil || Manager.Config.CurrentContext == "" || !IsMutating(cmd) {
+		return nil
+	}
+
+	// nothing to do, see `ReportChange`.
+	if ansible != nil && ansible.reported && !ansible.changed {
+		return nil
+	}
+
+	change := Change{Time: time.Now().UTC(), Command: commandLine(cmd, args), Reason: Manager.Reason}
+	if u, err := user.Current(); err == nil {
+		change.User = u.Username
+	}
+
+	return AddChange(Manager.Config.CurrentContext, change)
+}
+
+//NewHistoryCommand creates the `history` command
+func NewHistoryCommand() *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Print the recent changes made by the mutating commands of the CLI to the current context, with their --reason",
+		Example: `history
+history --limit 50 --output json
+topic update --name payments --configs '{"key": "retention.ms", "value": "86400000"}' --reason "JIRA-123 increase retention"`,
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			changes, err := History(Manager.Config.CurrentContext, limit)
+			if err != nil {
+				return err
+			}
+
+			if len(changes) == 0 {
+				return bite.PrintInfo(cmd, "No changes recorded for context [%s]", Manager.Config.CurrentContext)
+			}
+
+			return bite.PrintObject(cmd, changes)
+		},
+	}
+
+	cmd.Flags().IntVar(&limit, "limit", 20, "The number of the most recent changes to print, 0 prints all of them")
+	bite.CanPrintJSON(cmd)
+
+	return cmd
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordChange(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lenses-cli-history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	defaultHistoryFilepath, manager := DefaultHistoryFilepath, Manager
+	DefaultHistoryFilepath = filepath.Join(dir, "history.yml")
+	Manager = NewEmptyConfigManager()
+	defer func() { DefaultHistoryFilepath, Manager = defaultHistoryFilepath, manager }()
+
+	Manager.Config.CurrentContext = "prod"
+
+	root := &cobra.Command{Use: "lenses-cli"}
+	root.PersistentFlags().String("pass", "", "")
+	root.PersistentFlags().StringVar(&Manager.Reason, "reason", "", "")
+
+	topic := &cobra.Command{Use: "topic"}
+	update := &cobra.Command{Use: "update", Run: func(*cobra.Command, []string) {}}
+	update.Flags().String("name", "", "")
+	topic.AddCommand(update)
+
+	imports := &cobra.Command{Use: "import"}
+	topics := &cobra.Command{Use: "topics", Run: func(*cobra.Command, []string) {}}
+	imports.AddCommand(topics)
+
+	get := &cobra.Command{Use: "topics", Run: func(*cobra.Command, []string) {}}
+	root.AddCommand(topic, imports, get)
+
+	run := func(args ...string) {
+		root.SetArgs(args)
+		cmd, err := root.ExecuteC()
+		require.NoError(t, err)
+		require.NoError(t, RecordChange(cmd, cmd.Flags().Args()))
+	}
+
+	run("topic", "update", "--name", "payments", "--pass", "s3cret", "--reason", "JIRA-123 increase retention")
+	run("topics")
+	Manager.Reason = ""
+	run("import", "topics")
+
+	changes, err := History("prod", 0)
+	require.NoError(t, err)
+	require.Len(t, changes, 2, "the commands that change nothing are not recorded")
+
+	assert.Equal(t, "lenses-cli import topics", changes[0].Command, "most recent first")
+	assert.Empty(t, changes[0].Reason)
+	assert.Equal(t, "lenses-cli topic update --name=payments --pass=***", changes[1].Command)
+	assert.Equal(t, "JIRA-123 increase retention", changes[1].Reason)
+
+	changes, _ = History("prod", 1)
+	assert.Len(t, changes, 1)
+
+	changes, _ = History("dev", 0)
+	assert.Empty(t, changes)
+}
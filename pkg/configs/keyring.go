@@ -0,0 +1,127 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// keyringService is the service name of the CLI's entries in the OS keychain.
+const keyringService = "lenses-cli"
+
+// errSecretNotFound is returned by a `secretStore` when there is no entry for an account.
+var errSecretNotFound = errors.New("secret not found")
+
+// secretStore stores secrets by account name outside of the configuration file.
+type secretStore interface {
+	Get(account string) (string, error)
+	Set(account, secret string) error
+	Delete(account string) error
+}
+
+// keyring is the OS keychain, the macOS Keychain through `security` or the Secret Service (GNOME Keyring, KWallet) through `secret-tool`.
+var keyring secretStore = osKeyring{}
+
+// keyringAvailable reports whether the OS keychain's tool is installed.
+var keyringAvailable = func() bool {
+	_, err := exec.LookPath(keyringTool())
+	return err == nil
+}
+
+func keyringTool() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "security"
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return "secret-tool"
+	default:
+		return ""
+	}
+}
+
+type osKeyring struct{}
+
+func (osKeyring) run(stdin string, args ...string) (string, error) {
+	tool := keyringTool()
+	if tool == "" {
+		return "", fmt.Errorf("keyring is not supported on [%s], use the passphrase store instead", runtime.GOOS)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(tool, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			// both tools exit with a non-zero code when the entry does not exist.
+			if msg := strings.TrimSpace(stderr.String()); msg != "" && !strings.Contains(msg, "could not be found") {
+				return "", fmt.Errorf("keyring: [%s]", msg)
+			}
+			return "", errSecretNotFound
+		}
+		return "", fmt.Errorf("keyring: [%v]", err)
+	}
+
+	return stdout.String(), nil
+}
+
+// quote quotes "s" for the `security -i` command line.
+func quote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+func (k osKeyring) Get(account string) (string, error) {
+	var (
+		out string
+		err error
+	)
+
+	if runtime.GOOS == "darwin" {
+		out, err = k.run("", "find-generic-password", "-s", keyringService, "-a", account, "-w")
+	} else {
+		out, err = k.run("", "lookup", "service", keyringService, "account", account)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	out = strings.TrimRight(out, "\r\n")
+	if out == "" {
+		return "", errSecretNotFound
+	}
+
+	return out, nil
+}
+
+// Set adds or updates the entry, the secret is passed through the standard input, so it's not visible in the process list.
+func (k osKeyring) Set(account, secret string) error {
+	var err error
+	if runtime.GOOS == "darwin" {
+		_, err = k.run(fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n", quote(keyringService), quote(account), quote(secret)), "-i")
+	} else {
+		_, err = k.run(secret, "store", "--label="+keyringService+" "+account, "service", keyringService, "account", account)
+	}
+
+	return err
+}
+
+func (k osKeyring) Delete(account string) error {
+	var err error
+	if runtime.GOOS == "darwin" {
+		_, err = k.run("", "delete-generic-password", "-s", keyringService, "-a", account)
+	} else {
+		_, err = k.run("", "clear", "service", keyringService, "account", account)
+	}
+
+	if err == errSecretNotFound {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,216 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/user"
+	"path/filepath"
+	"time"
+
+	"github.com/kataras/golog"
+	"github.com/lensesio/bite"
+	"github.com/lensesio/lenses-go/pkg/api"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultLocksDir is the directory of the lock files of the contexts, see `LockFilepath`.
+var DefaultLocksDir = filepath.Join(api.DefaultConfigurationHomeDir, "locks")
+
+// Lock is the metadata of the lock of a context, held by an apply or import run, see `WithLock`.
+type Lock struct {
+	Context  string    `json:"context" yaml:"context" header:"Context"`
+	User     string    `json:"user" yaml:"user" header:"User"`
+	Host     string    `json:"host" yaml:"host" header:"Host"`
+	PID      int       `json:"pid" yaml:"pid" header:"PID"`
+	Command  string    `json:"command" yaml:"command" header:"Command"`
+	Acquired time.Time `json:"acquired" yaml:"acquired" header:"Acquired"`
+}
+
+func (l Lock) String() string {
+	return fmt.Sprintf("[%s@%s] (pid %d) running [%s] since [%s]", l.User, l.Host, l.PID, l.Command, l.Acquired.Format(time.RFC3339))
+}
+
+// LockFilepath returns the lock file of the "context", the --lock-file flag or one per context in the `DefaultLocksDir`.
+// A lock file on a volume shared by the CI runners locks the context across them.
+func LockFilepath(context string) string {
+	if Manager != nil && Manager.LockFile != "" {
+		return Manager.LockFile
+	}
+
+	return filepath.Join(DefaultLocksDir, context+".lock")
+}
+
+// ReadLock returns the lock of the "path" and false if the context is not locked.
+func ReadLock(path string) (Lock, bool, error) {
+	var lock Lock
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return lock, false, nil
+		}
+		return lock, false, err
+	}
+
+	if err = yaml.Unmarshal(b, &lock); err != nil {
+		return lock, true, fmt.Errorf("unable to read the lock file [%s]: [%v]", path, err)
+	}
+
+	return lock, true, nil
+}
+
+// AcquireLock creates the lock file of the "path" with the "lock" metadata,
+// it fails with the metadata of the existing lock if the context is already locked.
+func AcquireLock(path string, lock Lock) error {
+	b, err := yaml.Marshal(lock)
+	if err != nil {
+		return err
+	}
+
+	os.MkdirAll(filepath.Dir(path), os.FileMode(0750))
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, os.FileMode(0600))
+	if err != nil {
+		if !os.IsExist(err) {
+			return err
+		}
+
+		held, _, readErr := ReadLock(path)
+		if readErr != nil {
+			return fmt.Errorf("context [%s] is locked by [%s], pass the --force-unlock flag to remove it", lock.Context, path)
+		}
+
+		return fmt.Errorf("context [%s] is locked by %s, pass the --force-unlock flag to remove it if the run is gone", held.Context, held)
+	}
+
+	if _, err = f.Write(b); err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+
+	return f.Close()
+}
+
+// ReleaseLock removes the lock file of the "path", a missing lock is not an error.
+func ReleaseLock(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// AnnotationLockContext is the command annotation which names the flag of the context that `WithLock` locks instead of the current one,
+// i.e the --to context of a sync, which is the one it writes to.
+const AnnotationLockContext = "lockContext"
+
+// lockContext returns the context that the "cmd" locks, the value of its `AnnotationLockContext` flag or the current context.
+func lockContext(cmd *cobra.Command) string {
+	if name, ok := cmd.Annotations[AnnotationLockContext]; ok {
+		if f := cmd.Flags().Lookup(name); f != nil && f.Value.String() != "" {
+			return f.Value.String()
+		}
+	}
+
+	return Manager.Config.CurrentContext
+}
+
+// heldLock is the lock file held by this process, so the commands it runs, i.e the steps of an `import all`, do not wait for it.
+var heldLock string
+
+// newLock returns the lock metadata of the "cmd" running against the "context".
+func newLock(cmd *cobra.Command, context string) Lock {
+	lock := Lock{Context: context, PID: os.Getpid(), Command: cmd.CommandPath(), Acquired: time.Now().UTC()}
+	if u, err := user.Current(); err == nil {
+		lock.User = u.Username
+	}
+	lock.Host, _ = os.Hostname()
+
+	return lock
+}
+
+// WithLock makes the "cmd", and its sub commands, hold the lock of the current context, or of its `AnnotationLockContext`, while they run,
+// if the --lock or --lock-file flag is passed, so concurrent apply or import runs against the same environment fail fast.
+// The --force-unlock flag removes a stale lock, of a run that's gone, before acquiring it.
+func WithLock(cmd *cobra.Command) *cobra.Command {
+	for _, sub := range cmd.Commands() {
+		WithLock(sub)
+	}
+
+	run := cmd.RunE
+	if run == nil {
+		return cmd
+	}
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if Manager == nil || (!Manager.Lock && Manager.LockFile == "") || heldLock != "" {
+			return run(cmd, args)
+		}
+
+		context := lockContext(cmd)
+		path := LockFilepath(context)
+		if Manager.ForceUnlock {
+			if err := ReleaseLock(path); err != nil {
+				return err
+			}
+		}
+
+		if err := AcquireLock(path, newLock(cmd, context)); err != nil {
+			return err
+		}
+
+		heldLock = path
+		defer func() {
+			heldLock = ""
+			if err := ReleaseLock(path); err != nil {
+				golog.Errorf("Failed to release the lock of context [%s]. [%s]", context, err.Error())
+			}
+		}()
+
+		return run(cmd, args)
+	}
+
+	return cmd
+}
+
+//NewLockCommand creates the `lock` command
+func NewLockCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lock",
+		Short: "Print the lock of the current context, held by an apply or import run, or remove it with --force-unlock",
+		Example: `lock
+lock --force-unlock
+processor apply -f processor.yml --lock`,
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		Annotations:      map[string]string{AnnotationMutating: "false"}, // local lock file only.
+		RunE: func(cmd *cobra.Command, args []string) error {
+			context := Manager.Config.CurrentContext
+			path := LockFilepath(context)
+
+			lock, locked, err := ReadLock(path)
+			if err != nil && !Manager.ForceUnlock {
+				return err
+			}
+
+			if !locked {
+				return bite.PrintInfo(cmd, "Context [%s] is not locked", context)
+			}
+
+			if Manager.ForceUnlock {
+				if err := ReleaseLock(path); err != nil {
+					return err
+				}
+
+				return bite.PrintInfo(cmd, "Lock of context [%s] removed", context)
+			}
+
+			return bite.PrintObject(cmd, lock)
+		},
+	}
+
+	bite.CanPrintJSON(cmd)
+
+	return cmd
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithLock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lenses-cli-locks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	defaultLocksDir, manager := DefaultLocksDir, Manager
+	DefaultLocksDir = dir
+	Manager = NewEmptyConfigManager()
+	defer func() { DefaultLocksDir, Manager = defaultLocksDir, manager }()
+
+	Manager.Config.CurrentContext = "prod"
+	path := filepath.Join(dir, "prod.lock")
+
+	var held Lock
+	nested := WithLock(&cobra.Command{Use: "topics", RunE: func(cmd *cobra.Command, args []string) error {
+		lock, _, err := ReadLock(path)
+		held = lock
+		return err
+	}})
+	apply := WithLock(&cobra.Command{Use: "apply", RunE: func(cmd *cobra.Command, args []string) error {
+		// the commands run by a locked one do not wait for its lock.
+		return nested.RunE(nested, nil)
+	}})
+
+	// no lock without --lock.
+	assert.NoError(t, apply.RunE(apply, nil))
+	assert.Empty(t, held.Context)
+
+	Manager.Lock = true
+	assert.NoError(t, apply.RunE(apply, nil))
+	assert.Equal(t, "prod", held.Context)
+	assert.Equal(t, "apply", held.Command)
+	assert.Equal(t, os.Getpid(), held.PID)
+
+	_, locked, err := ReadLock(path)
+	assert.NoError(t, err)
+	assert.False(t, locked, "released after the run")
+
+	// held by another run.
+	other := Lock{Context: "prod", User: "ci", Host: "runner-1", PID: 42, Command: "lenses-cli import topics", Acquired: time.Now().UTC()}
+	require.NoError(t, AcquireLock(path, other))
+	err = apply.RunE(apply, nil)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "[ci@runner-1] (pid 42) running [lenses-cli import topics]")
+	}
+
+	Manager.ForceUnlock = true
+	assert.NoError(t, apply.RunE(apply, nil))
+	_, locked, _ = ReadLock(path)
+	assert.False(t, locked)
+}
+
+func TestWithLockContext(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lenses-cli-locks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	defaultLocksDir, manager := DefaultLocksDir, Manager
+	DefaultLocksDir = dir
+	Manager = NewEmptyConfigManager()
+	defer func() { DefaultLocksDir, Manager = defaultLocksDir, manager }()
+
+	Manager.Config.CurrentContext = "dev"
+	Manager.Lock = true
+
+	var held Lock
+	var to string
+	sync := WithLock(&cobra.Command{Use: "sync", Annotations: map[string]string{AnnotationLockContext: "to"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			lock, _, err := ReadLock(filepath.Join(dir, "prod.lock"))
+			held = lock
+			return err
+		}})
+	sync.Flags().StringVar(&to, "to", "", "")
+
+	require.NoError(t, sync.Flags().Set("to", "prod"))
+	assert.NoError(t, sync.RunE(sync, nil))
+	assert.Equal(t, "prod", held.Context, "the --to context is locked")
+
+	// locked by another run.
+	require.NoError(t, AcquireLock(filepath.Join(dir, "prod.lock"), Lock{Context: "prod", User: "ci", Host: "runner-1", PID: 42}))
+	assert.Error(t, sync.RunE(sync, nil))
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/lensesio/bite"
+	"github.com/lensesio/lenses-go/pkg/exitcode"
+	"github.com/lensesio/lenses-go/pkg/manifest"
+	"github.com/lensesio/lenses-go/pkg/utils"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// ManifestVars returns the variables of the manifest files, the environment variables overridden by the `--set` flags.
+func ManifestVars() (map[string]string, error) {
+	vars := make(map[string]string)
+	for _, kv := range os.Environ() {
+		if i := strings.IndexByte(kv, '='); i > 0 {
+			vars[kv[:i]] = kv[i+1:]
+		}
+	}
+
+	if Manager == nil {
+		return vars, nil
+	}
+
+	for _, v := range Manager.Vars {
+		kv := strings.SplitN(v, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("invalid variable [%s], use --set name=value", v)
+		}
+		vars[kv[0]] = kv[1]
+	}
+
+	return vars, nil
+}
+
+// RenderManifest executes the manifest as a Go template, i.e `{{ .TOPIC }}` or `{{ env "TOPIC" }}`,
+// and replaces its `${TOPIC}` with the "vars". A missing variable is an error, so a manifest is never applied half-filled.
+func RenderManifest(b []byte, vars map[string]string) ([]byte, error) {
+	if bytes.Contains(b, []byte("{{")) {
+		tmpl, err := template.New("manifest").Option("missingkey=error").Funcs(template.FuncMap{
+			"env": os.Getenv,
+		}).Parse(string(b))
+		if err != nil {
+			return nil, err
+		}
+
+		var buf bytes.Buffer
+		if err = tmpl.Execute(&buf, vars); err != nil {
+			return nil, err
+		}
+		b = buf.Bytes()
+	}
+
+	out, err := utils.ExpandVars(string(b), func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(out), nil
+}
+
+// ReadManifest reads the resource file of the "path", the same as the `bite.TryReadFile`,
+// with the variables of the `ManifestVars` rendered before it's decoded to the "outPtr".
+// The file is validated against the schema of the "kind", if not empty, see the `manifest` package.
+func ReadManifest(path, kind string, outPtr interface{}) error {
+	b, err := bite.TryReadFileContents(path)
+	if err != nil {
+		return err
+	}
+
+	vars, err := ManifestVars()
+	if err != nil {
+		return err
+	}
+
+	if b, err = RenderManifest(b, vars); err != nil {
+		return fmt.Errorf("render [%s]: %v", path, err)
+	}
+
+	ext := filepath.Ext(path)
+	isYAML := ext == ".yml" || ext == ".yaml"
+
+	var invalid error
+	if kind != "" {
+		if invalid = manifest.Validate(kind, path, b, isYAML); invalid != nil {
+			invalid = exitcode.WithCode(exitcode.Validation, invalid)
+		}
+	}
+
+	// decode even an invalid file, so the caller can report what it got.
+	if isYAML {
+		err = yaml.Unmarshal(b, outPtr)
+	} else {
+		err = json.Unmarshal(b, outPtr)
+	}
+
+	if invalid != nil {
+		return invalid
+	}
+
+	return err
+}
+
+// LoadManifest is the `bite.LoadFile` with the variables and the validation of the `ReadManifest`.
+func LoadManifest(cmd *cobra.Command, path, kind string, outPtr interface{}) error {
+	if err := bite.PrintInfo(cmd, "Loading from file '%s'", path); err != nil {
+		return err
+	}
+
+	return ReadManifest(path, kind, outPtr)
+}
+
+// ManifestBind is the `bite.FileBind` with the variables and the validation of the `ReadManifest`,
+// it loads the file of the first argument, if any, to the "outPtr" before the command runs,
+// otherwise it calls the "orElse", if any.
+func ManifestBind(kind string, outPtr interface{}, orElse ...func() error) bite.CobraRunner {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 || args[0] == "" {
+			for _, fn := range orElse {
+				if err := fn(); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		return LoadManifest(cmd, args[0], kind, outPtr)
+	}
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lensesio/lenses-go/pkg/exitcode"
+	"github.com/lensesio/lenses-go/pkg/manifest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderManifest(t *testing.T) {
+	vars := map[string]string{"ENV": "prod", "PARTITIONS": "6"}
+
+	out, err := RenderManifest([]byte(`name: orders-${ENV}
+partitions: {{ .PARTITIONS }}
+sql: SELECT '$${ENV}'`), vars)
+	assert.NoError(t, err)
+	assert.Equal(t, "name: orders-prod\npartitions: 6\nsql: SELECT '${ENV}'", string(out))
+
+	_, err = RenderManifest([]byte(`name: ${TOPIC}`), vars)
+	assert.EqualError(t, err, "undefined variable [TOPIC]")
+
+	_, err = RenderManifest([]byte(`name: {{ .TOPIC }}`), vars)
+	assert.Error(t, err)
+}
+
+func TestReadManifest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lenses-manifest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "processor.yml")
+	if err = ioutil.WriteFile(path, []byte("name: ${NAME}\nsql: SELECT * FROM {{ .TOPIC }}\nrunnerCount: ${RUNNERS}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Setenv("RUNNERS", "2")
+	defer os.Unsetenv("RUNNERS")
+
+	defaultManager := Manager
+	defer func() { Manager = defaultManager }()
+	Manager = NewEmptyConfigManager()
+	Manager.Vars = []string{"NAME=orders-dedup", "TOPIC=orders", "RUNNERS=3"}
+
+	var processor struct {
+		Name    string `yaml:"name"`
+		SQL     string `yaml:"sql"`
+		Runners int    `yaml:"runnerCount"`
+	}
+	if assert.NoError(t, ReadManifest(path, manifest.KindProcessor, &processor)) {
+		assert.Equal(t, "orders-dedup", processor.Name)
+		assert.Equal(t, "SELECT * FROM orders", processor.SQL)
+		assert.Equal(t, 3, processor.Runners, "--set overrides the environment")
+	}
+
+	Manager.Vars = []string{"NAME"}
+	assert.EqualError(t, ReadManifest(path, manifest.KindProcessor, &processor), "invalid variable [NAME], use --set name=value")
+
+	Manager.Vars = []string{"NAME=orders-dedup", "TOPIC=orders", "RUNNERS=0"}
+	err = ReadManifest(path, manifest.KindProcessor, &processor)
+	if assert.Error(t, err) {
+		assert.Equal(t, "invalid processor manifest ["+path+"]:\n  line 3: runnerCount: must be at least 1, got 0", err.Error())
+		assert.Equal(t, exitcode.Validation, exitcode.Of(err))
+	}
+}
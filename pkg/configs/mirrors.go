@@ -0,0 +1,75 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultMirrorsFilepath is the file of the checkpoints of the `topic mirror` command, per mirror.
+var DefaultMirrorsFilepath = filepath.Join(api.DefaultConfigurationHomeDir, "lenses-cli-mirrors.yml")
+
+// MirrorKey returns the key of the checkpoint of the mirror of the "source" context's "topic"
+// to the "target" context's "targetTopic".
+func MirrorKey(source, topic, target, targetTopic string) string {
+	return fmt.Sprintf("%s/%s->%s/%s", source, topic, target, targetTopic)
+}
+
+func readMirrors() (map[string]map[int]int, error) {
+	mirrors := make(map[string]map[int]int)
+
+	b, err := ioutil.ReadFile(DefaultMirrorsFilepath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return mirrors, nil
+		}
+		return nil, err
+	}
+
+	if err = yaml.Unmarshal(b, &mirrors); err != nil {
+		return nil, fmt.Errorf("unable to read the mirrors file [%s]: [%v]", DefaultMirrorsFilepath, err)
+	}
+
+	return mirrors, nil
+}
+
+func writeMirrors(mirrors map[string]map[int]int) error {
+	b, err := yaml.Marshal(mirrors)
+	if err != nil {
+		return err
+	}
+
+	os.MkdirAll(filepath.Dir(DefaultMirrorsFilepath), os.FileMode(0750))
+	return ioutil.WriteFile(DefaultMirrorsFilepath, b, os.FileMode(0600))
+}
+
+// MirrorOffsets returns the last mirrored offset of each partition of the mirror of the "key", see `MirrorKey`.
+func MirrorOffsets(key string) (map[int]int, error) {
+	mirrors, err := readMirrors()
+	if err != nil {
+		return nil, err
+	}
+
+	return mirrors[key], nil
+}
+
+// SaveMirrorOffsets saves the last mirrored offset of each partition of the mirror of the "key",
+// no offsets remove its checkpoint.
+func SaveMirrorOffsets(key string, offsets map[int]int) error {
+	mirrors, err := readMirrors()
+	if err != nil {
+		return err
+	}
+
+	if len(offsets) == 0 {
+		delete(mirrors, key)
+	} else {
+		mirrors[key] = offsets
+	}
+
+	return writeMirrors(mirrors)
+}
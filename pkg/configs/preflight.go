@@ -0,0 +1,35 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lensesio/lenses-go/pkg/exitcode"
+)
+
+// The permissions required by the destructive commands, see `Preflight`.
+const (
+	PermissionDropTopic           = "DropTopic"
+	PermissionDeleteData          = "DeleteData"
+	PermissionManageKafkaSettings = "ManageKafkaSettings" // ACLs and quotas.
+)
+
+// Preflight checks, if the --preflight flag is set, that the current user has the "permissions" to "action",
+// so a destructive command fails fast, before any change, with the missing permissions.
+func Preflight(action string, permissions ...string) error {
+	if Manager == nil || !Manager.Preflight || len(permissions) == 0 {
+		return nil
+	}
+
+	user, err := Client.GetCurrentUser()
+	if err != nil {
+		return fmt.Errorf("preflight: unable to retrieve the current user: [%v]", err)
+	}
+
+	if missing := user.MissingPermissions(permissions...); len(missing) > 0 {
+		return exitcode.WithCode(exitcode.Forbidden,
+			fmt.Errorf("preflight: user [%s] is not allowed to %s, missing permissions [%s]", user.Name, action, strings.Join(missing, ", ")))
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreflight(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"user":"dev","permissions":["DropTopic","ViewTopics"]}`))
+	}))
+	defer srv.Close()
+
+	client, err := api.OpenConnection(api.ClientConfig{Host: srv.URL, Token: "t0ken"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defaultManager, defaultClient := Manager, Client
+	defer func() { Manager, Client = defaultManager, defaultClient }()
+	Manager, Client = NewEmptyConfigManager(), client
+
+	assert.NoError(t, Preflight("delete ACL", PermissionManageKafkaSettings))
+	assert.Equal(t, 0, requests, "preflight is disabled by default")
+
+	Manager.Preflight = true
+	assert.NoError(t, Preflight("delete topic [a]", PermissionDropTopic))
+
+	err = Preflight("delete ACL", PermissionManageKafkaSettings)
+	if assert.Error(t, err) {
+		assert.Equal(t, "preflight: user [dev] is not allowed to delete ACL, missing permissions [ManageKafkaSettings]", err.Error())
+	}
+	assert.Equal(t, 2, requests)
+}
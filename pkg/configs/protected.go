@@ -0,0 +1,82 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/lensesio/lenses-go/pkg/exitcode"
+	"github.com/spf13/cobra"
+)
+
+// AnnotationMutating is the command annotation which overrides, with "true" or "false", whether the command changes
+// the state of Lenses or Kafka, by default it's decided by the command's name, see `IsMutating`.
+const AnnotationMutating = "mutating"
+
+// mutatingCommands are the names (and the name prefixes, followed by "-") of the commands that change the state of Lenses or Kafka.
+var mutatingCommands = map[string]bool{
+	"create": true, "set": true, "update": true, "delete": true, "remove": true, "rm": true, "del": true,
+	"add": true, "insert": true, "write": true, "import": true, "apply": true, "provision": true, "clone": true,
+	"pause": true, "resume": true, "restart": true, "start": true, "stop": true, "revoke": true, "password": true,
+	"bench": true,
+}
+
+// IsMutating reports whether the "cmd", or the group it belongs to, i.e `import topics`, changes the state of Lenses or Kafka.
+// The closest annotation of the command and its parents decides, otherwise the first of their names that is mutating.
+func IsMutating(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if v, ok := c.Annotations[AnnotationMutating]; ok {
+			return v == "true"
+		}
+
+		if isMutatingName(c.Name()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isMutatingName(name string) bool {
+	if i := strings.IndexByte(name, '-'); i > 0 {
+		name = name[:i]
+	}
+
+	return mutatingCommands[name]
+}
+
+// CheckProtected returns an error if the current context is protected and the "cmd" is mutating,
+// unless the --allow-protected flag is passed.
+func CheckProtected(cmd *cobra.Command) error {
+	if Manager == nil {
+		return nil
+	}
+
+	return CheckProtectedContext(Manager.Config.CurrentContext, cmd)
+}
+
+// CheckProtectedContext returns an error if the "context" of the configuration file is protected and the "cmd" is mutating,
+// unless the --allow-protected flag is passed, i.e for the target context of the commands which write to another context.
+func CheckProtectedContext(context string, cmd *cobra.Command) error {
+	if Manager == nil || Manager.AllowProtected || !IsMutating(cmd) {
+		return nil
+	}
+
+	if cfg, ok := Manager.Config.Contexts[context]; !ok || !cfg.Protected {
+		return nil
+	}
+
+	return exitcode.WithCode(exitcode.Forbidden, fmt.Errorf("context [%s] is protected, pass the --allow-protected flag to run [%s] against it",
+		context, cmd.CommandPath()))
+}
+
+// PrintContextHeader prints the name of the current context in red to "w", if it's protected,
+// so the output of every command shows clearly that it runs against a protected environment.
+func PrintContextHeader(w io.Writer) {
+	if Manager == nil || !Manager.Config.GetCurrent().Protected {
+		return
+	}
+
+	color.New(color.FgRed, color.Bold).Fprintf(w, "[%s] (protected)\n", Manager.Config.CurrentContext)
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsMutating(t *testing.T) {
+	tests := []struct {
+		cmd      *cobra.Command
+		mutating bool
+	}{
+		{&cobra.Command{Use: "delete"}, true},
+		{&cobra.Command{Use: "update-tags"}, true},
+		{&cobra.Command{Use: "topics"}, false},
+		{&cobra.Command{Use: "get"}, false},
+		{&cobra.Command{Use: "delete", Annotations: map[string]string{AnnotationMutating: "false"}}, false},
+		{&cobra.Command{Use: "query", Annotations: map[string]string{AnnotationMutating: "true"}}, true},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.mutating, IsMutating(tt.cmd), tt.cmd.Use)
+	}
+}
+
+func TestCheckProtected(t *testing.T) {
+	defaultManager := Manager
+	defer func() { Manager = defaultManager }()
+
+	Manager = NewEmptyConfigManager()
+	Manager.Config.CurrentContext = "prod"
+	Manager.Config.Contexts["prod"] = &api.ClientConfig{Host: "http://lenses:9991", Protected: true}
+
+	root := &cobra.Command{Use: "lenses-cli"}
+	del := &cobra.Command{Use: "delete"}
+	get := &cobra.Command{Use: "topics"}
+	imports := &cobra.Command{Use: "import"}
+	importTopics := &cobra.Command{Use: "topics"}
+	imports.AddCommand(importTopics)
+	root.AddCommand(del, get, imports)
+
+	err := CheckProtected(del)
+	if assert.Error(t, err) {
+		assert.Equal(t, "context [prod] is protected, pass the --allow-protected flag to run [lenses-cli delete] against it", err.Error())
+	}
+	assert.NoError(t, CheckProtected(get))
+
+	// the subcommands of a mutating group are named after the kind.
+	err = CheckProtected(importTopics)
+	if assert.Error(t, err) {
+		assert.Equal(t, "context [prod] is protected, pass the --allow-protected flag to run [lenses-cli import topics] against it", err.Error())
+	}
+
+	Manager.AllowProtected = true
+	assert.NoError(t, CheckProtected(del))
+
+	var buf bytes.Buffer
+	PrintContextHeader(&buf)
+	assert.Contains(t, buf.String(), "[prod] (protected)")
+
+	Manager.Config.Contexts["prod"].Protected = false
+	Manager.AllowProtected = false
+	assert.NoError(t, CheckProtected(del))
+
+	buf.Reset()
+	PrintContextHeader(&buf)
+	assert.Empty(t, buf.String())
+}
+
+func TestCheckProtectedContext(t *testing.T) {
+	defaultManager := Manager
+	defer func() { Manager = defaultManager }()
+
+	Manager = NewEmptyConfigManager()
+	Manager.Config.CurrentContext = "dev"
+	Manager.Config.Contexts["dev"] = &api.ClientConfig{Host: "http://lenses-dev:9991"}
+	Manager.Config.Contexts["prod"] = &api.ClientConfig{Host: "http://lenses:9991", Protected: true}
+
+	root := &cobra.Command{Use: "lenses-cli"}
+	mirror := &cobra.Command{Use: "mirror", Annotations: map[string]string{AnnotationMutating: "true"}}
+	get := &cobra.Command{Use: "topics"}
+	root.AddCommand(mirror, get)
+
+	assert.NoError(t, CheckProtected(mirror))
+	assert.NoError(t, CheckProtectedContext("dev", mirror))
+	assert.NoError(t, CheckProtectedContext("prod", get))
+	assert.NoError(t, CheckProtectedContext("unknown", mirror))
+
+	err := CheckProtectedContext("prod", mirror)
+	if assert.Error(t, err) {
+		assert.Equal(t, "context [prod] is protected, pass the --allow-protected flag to run [lenses-cli mirror] against it", err.Error())
+	}
+
+	Manager.AllowProtected = true
+	assert.NoError(t, CheckProtectedContext("prod", mirror))
+}
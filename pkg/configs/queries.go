@@ -0,0 +1,105 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultQueriesFilepath is the file of the saved queries of the `queries` command, per context.
+var DefaultQueriesFilepath = filepath.Join(api.DefaultConfigurationHomeDir, "lenses-cli-queries.yml")
+
+func readQueries() (map[string][]api.SavedQuery, error) {
+	queries := make(map[string][]api.SavedQuery)
+
+	b, err := ioutil.ReadFile(DefaultQueriesFilepath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return queries, nil
+		}
+		return nil, err
+	}
+
+	if err = yaml.Unmarshal(b, &queries); err != nil {
+		return nil, fmt.Errorf("unable to read the queries file [%s]: [%v]", DefaultQueriesFilepath, err)
+	}
+
+	return queries, nil
+}
+
+func writeQueries(queries map[string][]api.SavedQuery) error {
+	b, err := yaml.Marshal(queries)
+	if err != nil {
+		return err
+	}
+
+	os.MkdirAll(filepath.Dir(DefaultQueriesFilepath), os.FileMode(0750))
+	return ioutil.WriteFile(DefaultQueriesFilepath, b, os.FileMode(0600))
+}
+
+// SavedQueries returns the queries saved locally for the "context", sorted by name.
+func SavedQueries(context string) ([]api.SavedQuery, error) {
+	queries, err := readQueries()
+	if err != nil {
+		return nil, err
+	}
+
+	return queries[context], nil
+}
+
+// SaveQuery saves the "query" locally for the "context", it replaces the query of the same name.
+func SaveQuery(context string, query api.SavedQuery) error {
+	queries, err := readQueries()
+	if err != nil {
+		return err
+	}
+
+	saved := queries[context]
+	replaced := false
+	for i := range saved {
+		if saved[i].Name == query.Name {
+			saved[i], replaced = query, true
+			break
+		}
+	}
+
+	if !replaced {
+		saved = append(saved, query)
+		sort.Slice(saved, func(i, j int) bool {
+			return saved[i].Name < saved[j].Name
+		})
+	}
+
+	queries[context] = saved
+	return writeQueries(queries)
+}
+
+// RemoveSavedQuery removes the query of the "name" saved locally for the "context",
+// it reports whether the query was found.
+func RemoveSavedQuery(context, name string) (bool, error) {
+	queries, err := readQueries()
+	if err != nil {
+		return false, err
+	}
+
+	saved := queries[context]
+	for i := range saved {
+		if saved[i].Name != name {
+			continue
+		}
+
+		queries[context] = append(saved[:i], saved[i+1:]...)
+		if len(queries[context]) == 0 {
+			delete(queries, context)
+		}
+
+		return true, writeQueries(queries)
+	}
+
+	return false, nil
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSavedQueries(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lenses-cli-queries")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	defaultQueriesFilepath := DefaultQueriesFilepath
+	DefaultQueriesFilepath = filepath.Join(dir, "queries.yml")
+	defer func() { DefaultQueriesFilepath = defaultQueriesFilepath }()
+
+	orders := api.SavedQuery{Name: "orders", SQL: "SELECT * FROM orders"}
+	payments := api.SavedQuery{Name: "payments", SQL: "SELECT * FROM payments", Description: "all the payments"}
+
+	assert.NoError(t, SaveQuery("prod", payments))
+	assert.NoError(t, SaveQuery("prod", orders))
+	assert.NoError(t, SaveQuery("dev", orders))
+
+	queries, err := SavedQueries("prod")
+	assert.NoError(t, err)
+	assert.Equal(t, []api.SavedQuery{orders, payments}, queries, "sorted by name")
+
+	// replaced.
+	orders.SQL = "SELECT * FROM orders WHERE amount > 100"
+	assert.NoError(t, SaveQuery("prod", orders))
+	queries, _ = SavedQueries("prod")
+	assert.Equal(t, []api.SavedQuery{orders, payments}, queries)
+
+	found, err := RemoveSavedQuery("prod", "payments")
+	assert.NoError(t, err)
+	assert.True(t, found)
+
+	found, err = RemoveSavedQuery("prod", "payments")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	queries, _ = SavedQueries("dev")
+	assert.Len(t, queries, 1, "per context")
+}
@@ -0,0 +1,349 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/lensesio/lenses-go/pkg/api"
+	"github.com/lensesio/lenses-go/pkg/utils"
+	"golang.org/x/crypto/scrypt"
+)
+
+// The credential stores, they keep the tokens and the passwords of the configuration file encrypted at rest.
+const (
+	// StoreKeyring keeps the secrets in the OS keychain, the configuration file holds only a reference to them.
+	StoreKeyring = "keyring"
+	// StorePassphrase encrypts the secrets in the configuration file with an AES key derived from a passphrase,
+	// read from the `EnvPassphrase` environment variable or asked on the terminal.
+	StorePassphrase = "passphrase"
+	// StoreLegacy is the old behavior: the passwords are obfuscated by the host and the tokens are stored in plain text.
+	StoreLegacy = "legacy"
+)
+
+const (
+	// EnvCredentialStore sets the credential store of the saved configuration, see `StoreKeyring` and `StorePassphrase`.
+	EnvCredentialStore = "LENSES_CREDENTIAL_STORE"
+	// EnvPassphrase is the passphrase of the `StorePassphrase`.
+	EnvPassphrase = "LENSES_PASSPHRASE"
+)
+
+// The prefixes of the stored secrets' values in the configuration file.
+const (
+	keyringPrefix   = "keyring:"
+	encryptedPrefix = "encrypted:"
+)
+
+// scrypt parameters of the passphrase key derivation.
+const (
+	scryptN       = 1 << 15
+	scryptR       = 8
+	scryptP       = 1
+	scryptKeyLen  = 32
+	scryptSaltLen = 16
+)
+
+// ValidateCredentialStore returns an error if the "store" is not a known credential store.
+func ValidateCredentialStore(store string) error {
+	switch store {
+	case StoreKeyring, StorePassphrase, StoreLegacy:
+		return nil
+	default:
+		return fmt.Errorf("unknown credential store [%s], use one of [%s, %s, %s]", store, StoreKeyring, StorePassphrase, StoreLegacy)
+	}
+}
+
+// DefaultCredentialStore returns the keyring if it's available on this system, otherwise the passphrase store.
+func DefaultCredentialStore() string {
+	if keyringAvailable() {
+		return StoreKeyring
+	}
+
+	return StorePassphrase
+}
+
+// promptPassphrase asks for the passphrase on the terminal, it can be replaced for tests.
+var promptPassphrase = func() (string, error) {
+	var passphrase string
+	err := survey.AskOne(&survey.Password{
+		Message: "Passphrase of the stored credentials",
+		Help:    "The passphrase that encrypts the credentials of the configuration file, set the " + EnvPassphrase + " environment variable to skip this question.",
+	}, &passphrase, survey.WithValidator(survey.Required))
+	return passphrase, err
+}
+
+func (m *ConfigurationManager) getPassphrase() (string, error) {
+	if m.passphrase != "" {
+		return m.passphrase, nil
+	}
+
+	passphrase := os.Getenv(EnvPassphrase)
+	if passphrase == "" {
+		var err error
+		if passphrase, err = promptPassphrase(); err != nil {
+			return "", err
+		}
+	}
+
+	m.passphrase = passphrase
+	return passphrase, nil
+}
+
+// encryptWithPassphrase encrypts the "plain" with AES-256-GCM, the key is derived from the "passphrase" and a random salt.
+func encryptWithPassphrase(plain, passphrase string) (string, error) {
+	salt := make([]byte, scryptSaltLen)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return "", err
+	}
+
+	gcm, err := passphraseCipher(passphrase, salt)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	out := append(salt, nonce...)
+	out = gcm.Seal(out, nonce, []byte(plain), nil)
+	return base64.RawURLEncoding.EncodeToString(out), nil
+}
+
+func decryptWithPassphrase(encrypted, passphrase string) (string, error) {
+	b, err := base64.RawURLEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", err
+	}
+
+	if len(b) < scryptSaltLen {
+		return "", errors.New("encrypted value is too short")
+	}
+
+	gcm, err := passphraseCipher(passphrase, b[:scryptSaltLen])
+	if err != nil {
+		return "", err
+	}
+
+	b = b[scryptSaltLen:]
+	if len(b) < gcm.NonceSize() {
+		return "", errors.New("encrypted value is too short")
+	}
+
+	plain, err := gcm.Open(nil, b[:gcm.NonceSize()], b[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", errors.New("wrong passphrase or corrupted value")
+	}
+
+	return string(plain), nil
+}
+
+func passphraseCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, scryptKeyLen)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// secretAccount returns the keyring account of the "context"'s "field", i.e "master/token".
+func secretAccount(context, field string) string {
+	return context + "/" + field
+}
+
+// credentialStore returns the store the secrets are saved with.
+func (m *ConfigurationManager) credentialStore() string {
+	if m.CredentialStore != "" {
+		return m.CredentialStore
+	}
+
+	if store := strings.TrimSpace(os.Getenv(EnvCredentialStore)); store != "" {
+		return store
+	}
+
+	return StoreLegacy
+}
+
+func isSealed(value string) bool {
+	return strings.HasPrefix(value, keyringPrefix) || strings.HasPrefix(value, encryptedPrefix)
+}
+
+// sealSecret returns the value of the "secret" to write to the configuration file.
+func (m *ConfigurationManager) sealSecret(context, field, secret string) (string, error) {
+	if secret == "" || isSealed(secret) {
+		// not opened on load, keep it.
+		return secret, nil
+	}
+
+	switch store := m.credentialStore(); store {
+	case StoreKeyring:
+		account := secretAccount(context, field)
+		if err := keyring.Set(account, secret); err != nil {
+			return "", err
+		}
+		return keyringPrefix + account, nil
+	case StorePassphrase:
+		passphrase, err := m.getPassphrase()
+		if err != nil {
+			return "", err
+		}
+
+		encrypted, err := encryptWithPassphrase(secret, passphrase)
+		if err != nil {
+			return "", err
+		}
+		return encryptedPrefix + encrypted, nil
+	default:
+		return "", ValidateCredentialStore(store)
+	}
+}
+
+// openSecret returns the secret of a "value" read from the configuration file,
+// values without a store's prefix are returned as they are.
+// The store of the first sealed value found becomes the store of the next save.
+func (m *ConfigurationManager) openSecret(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, keyringPrefix):
+		if m.CredentialStore == "" {
+			m.CredentialStore = StoreKeyring
+		}
+
+		account := strings.TrimPrefix(value, keyringPrefix)
+		secret, err := keyring.Get(account)
+		if err != nil {
+			return "", fmt.Errorf("unable to read [%s] from the keyring: [%v]", account, err)
+		}
+		return secret, nil
+	case strings.HasPrefix(value, encryptedPrefix):
+		if m.CredentialStore == "" {
+			m.CredentialStore = StorePassphrase
+		}
+
+		passphrase, err := m.getPassphrase()
+		if err != nil {
+			return "", err
+		}
+		return decryptWithPassphrase(strings.TrimPrefix(value, encryptedPrefix), passphrase)
+	default:
+		return value, nil
+	}
+}
+
+// mapPassword replaces the password of the basic or the kerberos with password authentication of the "cfg".
+func mapPassword(cfg *api.ClientConfig, fn func(password string) (string, error)) error {
+	if auth, ok := cfg.IsBasicAuth(); ok && auth.Password != "" {
+		p, err := fn(auth.Password)
+		if err != nil {
+			return err
+		}
+
+		auth.Password = p
+		cfg.Authentication = auth
+	} else if auth, ok := cfg.IsKerberosAuth(); ok {
+		if withPass, ok := auth.WithPassword(); ok && withPass.Password != "" {
+			p, err := fn(withPass.Password)
+			if err != nil {
+				return err
+			}
+
+			withPass.Password = p
+			auth.Method = withPass
+			cfg.Authentication = auth
+		}
+	}
+
+	return nil
+}
+
+// sealSecrets replaces the token and the password of the "context"'s "cfg" with their stored values.
+func (m *ConfigurationManager) sealSecrets(context string, cfg *api.ClientConfig) error {
+	if m.credentialStore() == StoreLegacy {
+		return EncryptPassword(cfg)
+	}
+
+	token, err := m.sealSecret(context, "token", cfg.Token)
+	if err != nil {
+		return err
+	}
+	cfg.Token = token
+
+	return mapPassword(cfg, func(password string) (string, error) {
+		return m.sealSecret(context, "password", password)
+	})
+}
+
+// openSecrets replaces the stored token and password of the "context"'s "cfg" with the secrets, once per load.
+func (m *ConfigurationManager) openSecrets(context string, cfg *api.ClientConfig) error {
+	if m.opened[context] {
+		return nil
+	}
+
+	token, err := m.openSecret(cfg.Token)
+	if err != nil {
+		return fmt.Errorf("context [%s]: %v", context, err)
+	}
+	cfg.Token = token
+
+	err = mapPassword(cfg, func(password string) (string, error) {
+		if !isSealed(password) {
+			// legacy.
+			p, _ := utils.DecryptString(password, cfg.Host)
+			return p, nil
+		}
+
+		return m.openSecret(password)
+	})
+	if err != nil {
+		return fmt.Errorf("context [%s]: %v", context, err)
+	}
+
+	if m.opened == nil {
+		m.opened = make(map[string]bool)
+	}
+	m.opened[context] = true
+	return nil
+}
+
+func (m *ConfigurationManager) openAllSecrets() error {
+	for name, v := range m.Config.Contexts {
+		if err := m.openSecrets(name, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RemoveSecrets removes the token and the password of the "context" from the configuration and from the keyring.
+func (m *ConfigurationManager) RemoveSecrets(context string) error {
+	if cfg, ok := m.Config.Contexts[context]; ok {
+		cfg.Token = ""
+		mapPassword(cfg, func(string) (string, error) { return "", nil })
+	}
+
+	if m.credentialStore() != StoreKeyring {
+		return nil
+	}
+
+	for _, field := range []string{"token", "password"} {
+		if err := keyring.Delete(secretAccount(context, field)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
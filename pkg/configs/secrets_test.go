@@ -0,0 +1,129 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeKeyring map[string]string
+
+func (k fakeKeyring) Get(account string) (string, error) {
+	secret, ok := k[account]
+	if !ok {
+		return "", errSecretNotFound
+	}
+	return secret, nil
+}
+
+func (k fakeKeyring) Set(account, secret string) error {
+	k[account] = secret
+	return nil
+}
+
+func (k fakeKeyring) Delete(account string) error {
+	delete(k, account)
+	return nil
+}
+
+func TestEncryptWithPassphrase(t *testing.T) {
+	encrypted, err := encryptWithPassphrase("s3cr3t", "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotContains(t, encrypted, "s3cr3t")
+
+	plain, err := decryptWithPassphrase(encrypted, "passphrase")
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cr3t", plain)
+
+	_, err = decryptWithPassphrase(encrypted, "wrong")
+	assert.Error(t, err)
+}
+
+// saveAndLoad saves a configuration with the "store" and loads it with a new manager.
+func saveAndLoad(t *testing.T, store string) (string, *ConfigurationManager) {
+	dir, err := ioutil.TempDir("", "lenses-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	m := NewEmptyConfigManager()
+	m.Filepath = filepath.Join(dir, "lenses-cli.yml")
+	m.CredentialStore = store
+	m.Config.CurrentContext = "prod"
+	m.Config.Contexts["prod"] = &api.ClientConfig{
+		Host:           "http://lenses:9991",
+		Token:          "t0ken",
+		Authentication: api.BasicAuthentication{Username: "admin", Password: "s3cr3t"},
+	}
+
+	if err = m.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := ioutil.ReadFile(m.Filepath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := NewEmptyConfigManager()
+	loaded.Filepath = m.Filepath
+	if _, err = loaded.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	return string(b), loaded
+}
+
+func TestSaveLoadKeyring(t *testing.T) {
+	fake := fakeKeyring{}
+	keyring = fake
+	defer func() { keyring = osKeyring{} }()
+
+	file, loaded := saveAndLoad(t, StoreKeyring)
+	assert.NotContains(t, file, "t0ken")
+	assert.NotContains(t, file, "s3cr3t")
+	assert.Contains(t, file, "keyring:prod/token")
+	assert.Equal(t, fakeKeyring{"prod/token": "t0ken", "prod/password": "s3cr3t"}, fake)
+
+	current := loaded.Config.GetCurrent()
+	assert.Equal(t, StoreKeyring, loaded.CredentialStore)
+	assert.Equal(t, "t0ken", current.Token)
+	assert.Equal(t, api.BasicAuthentication{Username: "admin", Password: "s3cr3t"}, current.Authentication)
+
+	assert.NoError(t, loaded.RemoveSecrets("prod"))
+	assert.Empty(t, fake)
+	assert.Empty(t, loaded.Config.GetCurrent().Token)
+}
+
+func TestSaveLoadPassphrase(t *testing.T) {
+	os.Setenv(EnvPassphrase, "passphrase")
+	defer os.Unsetenv(EnvPassphrase)
+
+	file, loaded := saveAndLoad(t, StorePassphrase)
+	assert.NotContains(t, file, "t0ken")
+	assert.NotContains(t, file, "s3cr3t")
+	assert.Equal(t, 2, strings.Count(file, encryptedPrefix))
+
+	current := loaded.Config.GetCurrent()
+	assert.Equal(t, StorePassphrase, loaded.CredentialStore)
+	assert.Equal(t, "t0ken", current.Token)
+	assert.Equal(t, api.BasicAuthentication{Username: "admin", Password: "s3cr3t"}, current.Authentication)
+}
+
+func TestSaveLoadLegacy(t *testing.T) {
+	file, loaded := saveAndLoad(t, "")
+	assert.NotContains(t, file, "s3cr3t")
+	assert.Contains(t, file, "t0ken")
+
+	current := loaded.Config.GetCurrent()
+	assert.Equal(t, "", loaded.CredentialStore)
+	assert.Equal(t, api.BasicAuthentication{Username: "admin", Password: "s3cr3t"}, current.Authentication)
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/kataras/golog"
+	"github.com/lensesio/lenses-go/pkg/api"
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultSessionsFilepath is the file of the session tokens cached by the `login` command, per context.
+// The tokens are stored with the configuration's credential store, see `StoreKeyring` and `StorePassphrase`.
+var DefaultSessionsFilepath = filepath.Join(api.DefaultConfigurationHomeDir, "lenses-cli-sessions.yml")
+
+func readSessions() (map[string]string, error) {
+	sessions := make(map[string]string)
+
+	b, err := ioutil.ReadFile(DefaultSessionsFilepath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return sessions, nil
+		}
+		return nil, err
+	}
+
+	if err = yaml.Unmarshal(b, &sessions); err != nil {
+		return nil, fmt.Errorf("unable to read the sessions file [%s]: [%v]", DefaultSessionsFilepath, err)
+	}
+
+	return sessions, nil
+}
+
+func writeSessions(sessions map[string]string) error {
+	b, err := yaml.Marshal(sessions)
+	if err != nil {
+		return err
+	}
+
+	os.MkdirAll(filepath.Dir(DefaultSessionsFilepath), os.FileMode(0750))
+	return ioutil.WriteFile(DefaultSessionsFilepath, b, os.FileMode(0600))
+}
+
+// CachedToken returns the session token of the "context" cached by `CacheToken`, if any.
+func (m *ConfigurationManager) CachedToken(context string) (string, error) {
+	sessions, err := readSessions()
+	if err != nil {
+		return "", err
+	}
+
+	return m.openSecret(sessions[context])
+}
+
+// CacheToken caches the session "token" of the "context", so the next commands don't have to authenticate.
+func (m *ConfigurationManager) CacheToken(context, token string) error {
+	sessions, err := readSessions()
+	if err != nil {
+		return err
+	}
+
+	if m.credentialStore() != StoreLegacy {
+		if token, err = m.sealSecret(context, "session", token); err != nil {
+			return err
+		}
+	}
+
+	sessions[context] = token
+	return writeSessions(sessions)
+}
+
+// RemoveCachedToken removes the session token of the "context".
+func (m *ConfigurationManager) RemoveCachedToken(context string) error {
+	sessions, err := readSessions()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := sessions[context]; !ok {
+		return nil
+	}
+
+	delete(sessions, context)
+	if m.credentialStore() == StoreKeyring {
+		if err = keyring.Delete(secretAccount(context, "session")); err != nil {
+			return err
+		}
+	}
+
+	return writeSessions(sessions)
+}
+
+// contextSessionOption returns the connection option that uses the cached session token of the "context",
+// whose configuration is "current", if it's not authenticated by a token already. A renewed token replaces the cached one.
+func (m *ConfigurationManager) contextSessionOption(context string, current *api.ClientConfig) api.ConnectionOption {
+	if current.Token != "" || current.Authentication == nil {
+		return func(*api.Client) {}
+	}
+
+	token, err := m.CachedToken(context)
+	if err != nil {
+		golog.Debugf("unable to read the cached session of the context [%s]: [%v]", context, err)
+		return func(*api.Client) {}
+	}
+
+	if token == "" {
+		return func(*api.Client) {}
+	}
+
+	return api.UsingCachedToken(token, func(token string) {
+		if err := m.CacheToken(context, token); err != nil {
+			golog.Debugf("unable to cache the session of the context [%s]: [%v]", context, err)
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheToken(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lenses-sessions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	defaultSessionsFilepath := DefaultSessionsFilepath
+	DefaultSessionsFilepath = filepath.Join(dir, "sessions.yml")
+	defer func() { DefaultSessionsFilepath = defaultSessionsFilepath }()
+
+	fake := fakeKeyring{}
+	keyring = fake
+	defer func() { keyring = osKeyring{} }()
+
+	m := NewEmptyConfigManager()
+	m.CredentialStore = StoreKeyring
+
+	token, err := m.CachedToken("prod")
+	assert.NoError(t, err)
+	assert.Empty(t, token)
+
+	assert.NoError(t, m.CacheToken("prod", "s3ss10n"))
+	assert.Equal(t, fakeKeyring{"prod/session": "s3ss10n"}, fake)
+
+	b, err := ioutil.ReadFile(DefaultSessionsFilepath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotContains(t, string(b), "s3ss10n")
+
+	token, err = m.CachedToken("prod")
+	assert.NoError(t, err)
+	assert.Equal(t, "s3ss10n", token)
+
+	assert.NoError(t, m.RemoveCachedToken("prod"))
+	assert.Empty(t, fake)
+
+	token, err = m.CachedToken("prod")
+	assert.NoError(t, err)
+	assert.Empty(t, token)
+}
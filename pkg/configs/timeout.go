@@ -0,0 +1,31 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// EnvInvocationTimeout is the environment variable of the --invocation-timeout flag, see `InvocationTimeout`.
+const EnvInvocationTimeout = "LENSES_INVOCATION_TIMEOUT"
+
+// InvocationTimeout returns the time limit of the whole invocation, the --invocation-timeout flag or the `EnvInvocationTimeout`,
+// zero if none is set. It's not the --timeout, which is the timeout of the connection establishment only.
+func (m *ConfigurationManager) InvocationTimeout() (time.Duration, error) {
+	value := m.invocationTimeout
+	if value == "" {
+		value = strings.TrimSpace(os.Getenv(EnvInvocationTimeout))
+	}
+
+	if value == "" {
+		return 0, nil
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout < 0 {
+		return 0, fmt.Errorf("invalid invocation timeout [%s], expected a positive duration, i.e 5m", value)
+	}
+
+	return timeout, nil
+}
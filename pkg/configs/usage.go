@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultUsageFilepath is the file of the topics' size snapshots of the `topics usage` command, per context.
+var DefaultUsageFilepath = filepath.Join(api.DefaultConfigurationHomeDir, "lenses-cli-usage.yml")
+
+// UsageSnapshot is the size of the topics at a point in time, see `SaveUsageSnapshot`.
+type UsageSnapshot struct {
+	Time time.Time `yaml:"time"`
+	// Topics are the sizes of the topics' leader replicas in bytes, by topic name.
+	Topics map[string]int64 `yaml:"topics"`
+}
+
+func readUsageSnapshots() (map[string][]UsageSnapshot, error) {
+	snapshots := make(map[string][]UsageSnapshot)
+
+	b, err := ioutil.ReadFile(DefaultUsageFilepath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return snapshots, nil
+		}
+		return nil, err
+	}
+
+	if err = yaml.Unmarshal(b, &snapshots); err != nil {
+		return nil, fmt.Errorf("unable to read the usage file [%s]: [%v]", DefaultUsageFilepath, err)
+	}
+
+	return snapshots, nil
+}
+
+func writeUsageSnapshots(snapshots map[string][]UsageSnapshot) error {
+	b, err := yaml.Marshal(snapshots)
+	if err != nil {
+		return err
+	}
+
+	os.MkdirAll(filepath.Dir(DefaultUsageFilepath), os.FileMode(0750))
+	return ioutil.WriteFile(DefaultUsageFilepath, b, os.FileMode(0600))
+}
+
+// UsageSnapshots returns the snapshots saved locally for the "context", the oldest first.
+func UsageSnapshots(context string) ([]UsageSnapshot, error) {
+	snapshots, err := readUsageSnapshots()
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshots[context], nil
+}
+
+// SaveUsageSnapshot saves the "snapshot" locally for the "context",
+// it keeps only the "keep" latest snapshots, all of them if "keep" is not positive.
+func SaveUsageSnapshot(context string, snapshot UsageSnapshot, keep int) error {
+	snapshots, err := readUsageSnapshots()
+	if err != nil {
+		return err
+	}
+
+	saved := append(snapshots[context], snapshot)
+	if keep > 0 && len(saved) > keep {
+		saved = saved[len(saved)-keep:]
+	}
+
+	snapshots[context] = saved
+	return writeUsageSnapshots(snapshots)
+}
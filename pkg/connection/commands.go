@@ -7,6 +7,7 @@ import (
 	"github.com/lensesio/bite"
 	"github.com/lensesio/lenses-go/pkg/api"
 	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/printer"
 	cobra "github.com/spf13/cobra"
 )
 
@@ -33,7 +34,7 @@ connections
 				bite.PrintInfo(cmd, "Info: use JSON or YAML output to get the complete object\n\n")
 			}
 
-			return bite.PrintObject(cmd, connections)
+			return printer.PrintObject(cmd, connections)
 		},
 	}
 
@@ -43,6 +44,7 @@ connections
 	cmd.AddCommand(NewConnectionUpdateCommand())
 
 	bite.CanPrintJSON(cmd)
+	printer.CanSelect(cmd)
 
 	return cmd
 }
@@ -171,6 +173,10 @@ connections delete --name connection-name
 		SilenceErrors:    true,
 		TraverseChildren: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := config.Confirm("connection", name); err != nil {
+				return err
+			}
+
 			if err := config.Client.DeleteConnection(name); err != nil {
 				golog.Errorf("Failed to delete connection. [%s]", err.Error())
 				return err
@@ -2,15 +2,22 @@ package connector
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/kataras/golog"
 	"github.com/lensesio/bite"
 	"github.com/lensesio/lenses-go/pkg"
 	"github.com/lensesio/lenses-go/pkg/api"
 	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/diff"
+	"github.com/lensesio/lenses-go/pkg/manifest"
+	"github.com/lensesio/lenses-go/pkg/printer"
+	"github.com/lensesio/lenses-go/pkg/provider"
+	"github.com/lensesio/lenses-go/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -60,7 +67,7 @@ func NewConnectorsCommand() *cobra.Command {
 					return bite.PrintObject(cmd, bite.OutlineStringResults(cmd, "name", names))
 				}
 
-				return bite.PrintObject(cmd, connectorsInfo)
+				return printer.PrintObject(cmd, connectorsInfo)
 			}
 
 			connectorNames := make(map[string][]string) // clusterName:[] connectors names.
@@ -126,7 +133,7 @@ func NewConnectorsCommand() *cobra.Command {
 				}
 			}
 
-			return bite.PrintObject(cmd, connectors)
+			return printer.PrintObject(cmd, connectors)
 		},
 	}
 
@@ -136,6 +143,7 @@ func NewConnectorsCommand() *cobra.Command {
 	root.Flags().BoolVar(&showSupportedOnly, "supported", false, "List all the supported Kafka Connectors instead of the currently deployed")
 
 	bite.CanPrintJSON(root)
+	printer.CanSelect(root)
 
 	// plugins subcommand.
 	root.AddCommand(NewGetConnectorsPluginsCommand())
@@ -152,34 +160,13 @@ func NewGetConnectorsPluginsCommand() *cobra.Command {
 
 	cmd := &cobra.Command{
 		Use:           "plugins",
-		Short:         "List of available connectors' plugins",
-		Example:       `connectors plugins --cluster-name="cluster_name"`,
+		Short:         "List of available connectors' plugins, of a Connect cluster or of all of them",
+		Example:       `connectors plugins --cluster-name="cluster_name" or connectors plugins`,
 		SilenceErrors: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			var plugins []api.ConnectorPlugin
-
-			if clusterName == "*" {
-				// if * then no clusterName given, fetch the plugins from all known clusters and print them.
-				clusters, err := config.Client.GetConnectClusters()
-				if err != nil {
-					golog.Errorf("Failed to connect clusters. [%s]", err.Error())
-					return err
-				}
-
-				for _, clusterName := range clusters {
-					clusterPlugins, err := config.Client.GetConnectorPlugins(clusterName)
-					if err != nil {
-						golog.Errorf("Failed to find connector pugins. [%s]", err.Error())
-						return err
-					}
-					plugins = append(plugins, clusterPlugins...)
-				}
-			} else {
-				var err error
-				plugins, err = config.Client.GetConnectorPlugins(clusterName)
-				if err != nil {
-					return err
-				}
+			plugins, err := getConnectorPlugins(clusterName)
+			if err != nil {
+				return err
 			}
 
 			for i, p := range plugins {
@@ -188,17 +175,52 @@ func NewGetConnectorsPluginsCommand() *cobra.Command {
 				}
 			}
 
-			return bite.PrintObject(cmd, plugins)
+			return printer.PrintObject(cmd, plugins)
 		},
 	}
 
-	cmd.Flags().StringVar(&clusterName, "cluster-name", "", `Connect cluster name`)
+	cmd.Flags().StringVar(&clusterName, "cluster-name", "", `Connect cluster name, defaults to all clusters`)
 
 	bite.CanPrintJSON(cmd)
+	printer.CanSelect(cmd)
 
 	return cmd
 }
 
+// getConnectorPlugins returns the plugins of the "clusterName", or of all the Connect clusters if it's empty or "*",
+// sorted by their cluster and class.
+func getConnectorPlugins(clusterName string) ([]api.ConnectorPlugin, error) {
+	clusters := []string{clusterName}
+	if clusterName == "" || clusterName == "*" {
+		var err error
+		if clusters, err = config.Client.GetConnectClusters(); err != nil {
+			golog.Errorf("Failed to read connect clusters. [%s]", err.Error())
+			return nil, err
+		}
+	}
+
+	var plugins []api.ConnectorPlugin
+	for _, clusterName := range clusters {
+		clusterPlugins, err := config.Client.GetConnectorPlugins(clusterName)
+		if err != nil {
+			golog.Errorf("Failed to find connector plugins in cluster [%s]. [%s]", clusterName, err.Error())
+			return nil, err
+		}
+
+		plugins = append(plugins, clusterPlugins...)
+	}
+
+	sort.SliceStable(plugins, func(i, j int) bool {
+		if plugins[i].ClusterName != plugins[j].ClusterName {
+			return plugins[i].ClusterName < plugins[j].ClusterName
+		}
+
+		return plugins[i].Class < plugins[j].Class
+	})
+
+	return plugins, nil
+}
+
 //NewGetConnectorsClustersCommand creates the `connectors plugins` command
 func NewGetConnectorsClustersCommand() *cobra.Command {
 	var (
@@ -283,6 +305,11 @@ func NewConnectorGroupCommand() *cobra.Command {
 	// subcommands.
 	root.AddCommand(NewConnectorCreateCommand())
 	root.AddCommand(NewConnectorUpdateCommand())
+	root.AddCommand(NewConnectorUpdateConfigCommand())
+	root.AddCommand(NewConnectorDiffCommand())
+	root.AddCommand(NewConnectorPromoteCommand())
+	root.AddCommand(NewGetConnectorsPluginsCommand())
+	root.AddCommand(NewConnectorPluginGroupCommand())
 	root.AddCommand(NewConnectorGetConfigCommand())
 	root.AddCommand(NewConnectorGetStatusCommand())
 	root.AddCommand(NewConnectorPauseCommand())
@@ -341,7 +368,7 @@ func NewConnectorCreateCommand() *cobra.Command {
 	cmd.Flags().StringVar(&configRaw, "configs", "", `Connector config .e.g."{\"key\": \"value\"}"`) // --config conflicts with the global flag.
 	bite.CanBeSilent(cmd)
 
-	bite.ShouldTryLoadFile(cmd, &connector)
+	bite.Prepend(cmd, config.ManifestBind(manifest.KindConnector, &connector))
 
 	return cmd
 }
@@ -351,15 +378,38 @@ func NewConnectorUpdateCommand() *cobra.Command {
 	var (
 		configRaw string
 		connector = api.CreateUpdateConnectorPayload{Config: make(api.ConnectorConfig)}
+
+		file           string
+		skipValidation bool
+		wait           bool
+		waitTimeout    time.Duration
 	)
 
 	cmd := &cobra.Command{
 		Use:              "update",
 		Short:            "Update a connector's configuration",
-		Example:          `connector update --cluster-name="cluster_name" --name="connector_name" --configs="{\"key\": \"value\"}" or connector update ./connector.yml`,
+		Example:          `connector update --cluster-name="cluster_name" --name="connector_name" --configs="{\"key\": \"value\"}" or connector update ./connector.yml or connector update -f ./connector.yml --wait --wait-timeout=5m`,
 		SilenceErrors:    true,
 		TraverseChildren: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if file != "" {
+				fromFile := api.CreateUpdateConnectorPayload{Config: connector.Config}
+				if err := config.ReadManifest(file, manifest.KindConnector, &fromFile); err != nil {
+					return fmt.Errorf("unable to read the connector file [%s]: [%v]", file, err)
+				}
+
+				// flags have priority over the file's values.
+				if connector.ClusterName == "" {
+					connector.ClusterName = fromFile.ClusterName
+				}
+
+				if connector.Name == "" {
+					connector.Name = fromFile.Name
+				}
+
+				connector.Config = fromFile.Config
+			}
+
 			if err := connector.ApplyAndValidateName(); err != nil {
 				return err
 			}
@@ -390,12 +440,58 @@ func NewConnectorUpdateCommand() *cobra.Command {
 				}
 			}
 
+			// a file is applied by a three-way merge with the deployed config, so the keys set on the server
+			// but never declared in the file are kept, only the ones removed from the file since its last update are removed.
+			var (
+				desired    = connector.Config
+				appliedKey = config.AppliedKey(manifest.KindConnector, connector.ClusterName+":"+connector.Name)
+				context    = config.Manager.Config.CurrentContext
+			)
+			if file != "" {
+				lastApplied, err := config.LastApplied(context, appliedKey)
+				if err != nil {
+					return err
+				}
+
+				connector.Config = diff.Merge(lastApplied, existingConnector.Config, desired)
+			}
+
+			changes := diffConnectorConfig(existingConnector.Config, connector.Config)
+			if len(changes) == 0 {
+				if file != "" {
+					if err := config.SaveLastApplied(context, appliedKey, desired); err != nil {
+						return err
+					}
+				}
+
+				return bite.PrintInfo(cmd, "Connector [%s:%s] unchanged", connector.ClusterName, connector.Name)
+			}
+
+			if !skipValidation {
+				if err := validateConnectorConfig(cmd, config.Client, connector.ClusterName, connector.Config); err != nil {
+					return err
+				}
+			}
+
 			updatedConnector, err := config.Client.UpdateConnector(connector.ClusterName, connector.Name, connector.Config)
 			if err != nil {
 				// bite.FriendlyError(cmd, errResourceNotAccessibleMessage, "unable to update connector [%s:%s], the action requires 'Write' permissions", connector.ClusterName, connector.Name)
 				return err
 			}
 
+			if file != "" {
+				if err := config.SaveLastApplied(context, appliedKey, desired); err != nil {
+					golog.Errorf("Failed to save the last applied config of connector [%s:%s]. [%s]", connector.ClusterName, connector.Name, err.Error())
+				}
+			}
+
+			if wait {
+				if err := waitConnectorRunning(config.Client, connector.ClusterName, connector.Name, waitTimeout, connectorStatusPollInterval); err != nil {
+					golog.Errorf("Connector [%s:%s] updated but its tasks are not running. [%s]", connector.ClusterName, connector.Name, err.Error())
+					return err
+				}
+			}
+
 			//  why we print it back based on the --silent? Because of the connector.Tasks.
 			if !bite.ExpectsFeedback(cmd) {
 				bite.PrintInfo(cmd, "Connector [%s] updated\n\n", connector.Name)
@@ -409,11 +505,63 @@ func NewConnectorUpdateCommand() *cobra.Command {
 	cmd.Flags().StringVar(&connector.ClusterName, "cluster-name", "", `Connect cluster name`)
 	cmd.Flags().StringVar(&connector.Name, "name", "", `Connector name`)
 	cmd.Flags().StringVar(&configRaw, "configs", "", `Connector configs .e.g. "{\"key\": \"value\"}"`)
+	cmd.Flags().StringVarP(&file, "file", "f", "", "The connector file (yaml or json) with the clusterName, name and config fields")
+	cmd.Flags().BoolVar(&skipValidation, "skip-validation", false, "Do not validate the config against the connector plugin before applying it")
+	cmd.Flags().BoolVar(&wait, "wait", false, "Wait for the connector tasks to be running after the update, see --wait-timeout")
+	cmd.Flags().DurationVar(&waitTimeout, "wait-timeout", 2*time.Minute, "How long to wait for the connector tasks to be running")
 
 	bite.CanBeSilent(cmd)
 	bite.CanPrintJSON(cmd)
 
-	bite.ShouldTryLoadFile(cmd, &connector)
+	bite.Prepend(cmd, config.ManifestBind(manifest.KindConnector, &connector))
+
+	return cmd
+}
+
+//NewConnectorUpdateConfigCommand creates the `connector update-config` command
+func NewConnectorUpdateConfigCommand() *cobra.Command {
+	var clusterName, name, patchRaw string
+
+	cmd := &cobra.Command{
+		Use:              "update-config",
+		Short:            "Change only some of the configs of a connector with a JSON merge patch, a null value removes the config",
+		Example:          `connector update-config --cluster-name="cluster_name" --name="connector_name" --patch='{"tasks.max": "4"}' or connector update-config --cluster-name="cluster_name" --name="connector_name" --patch=./patch.json`,
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := bite.CheckRequiredFlags(cmd, bite.FlagPair{"cluster-name": clusterName, "name": name, "patch": patchRaw}); err != nil {
+				return err
+			}
+
+			patch, err := utils.ParsePatch(patchRaw)
+			if err != nil {
+				return err
+			}
+
+			if v, ok := patch["name"]; ok && v != name {
+				return fmt.Errorf(`Connector config["name"] [%v] does not match with the existing one [%s]`, v, name)
+			}
+
+			cfg, err := config.Client.PatchConnectorConfig(clusterName, name, patch)
+			if err != nil {
+				return fmt.Errorf("failed to update connector [%s:%s]. [%s]", clusterName, name, err.Error())
+			}
+
+			if !bite.ExpectsFeedback(cmd) {
+				bite.PrintInfo(cmd, "Connector [%s] updated\n\n", name)
+				return bite.PrintObject(cmd, cfg)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&clusterName, "cluster-name", "", `Connect cluster name`)
+	cmd.Flags().StringVar(&name, "name", "", `Connector name`)
+	cmd.Flags().StringVar(&patchRaw, "patch", "", `The configs to change, a JSON object or a file that contains it, .e.g. "{\"tasks.max\": \"4\"}"`)
+
+	bite.CanBeSilent(cmd)
+	bite.CanPrintJSON(cmd)
 
 	return cmd
 }
@@ -717,12 +865,28 @@ func NewConnectorDeleteCommand() *cobra.Command {
 				return err
 			}
 
+			var before interface{}
+			if config.Ansible() {
+				current, err := provider.ReadConnector(config.Client, provider.Connector{ClusterName: clusterName, Name: name}.ID())
+				if err != nil {
+					if errors.Is(err, provider.ErrNotFound) {
+						return config.ReportChange(cmd, false, fmt.Sprintf("Connector [%s:%s] does not exist", clusterName, name), nil, nil)
+					}
+					return err
+				}
+				before = current
+			}
+
+			if err := config.Confirm("connector", name); err != nil {
+				return err
+			}
+
 			if err := config.Client.DeleteConnector(clusterName, name); err != nil {
 				golog.Errorf("Failed to delete connector [%s] in cluster [%s]. [%s]", name, clusterName, err.Error())
 				return err
 			}
 
-			return bite.PrintInfo(cmd, "Connector [%s:%s] deleted", clusterName, name)
+			return config.ReportChange(cmd, true, fmt.Sprintf("Connector [%s:%s] deleted", clusterName, name), before, nil)
 		},
 	}
 
@@ -0,0 +1,223 @@
+package connector
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kataras/golog"
+	"github.com/lensesio/bite"
+	"github.com/lensesio/lenses-go/pkg"
+	"github.com/lensesio/lenses-go/pkg/api"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/diff"
+	"github.com/lensesio/lenses-go/pkg/manifest"
+	"github.com/spf13/cobra"
+)
+
+// connectorStatusPollInterval is the interval between the connector status requests
+// while waiting for its tasks to be running.
+const connectorStatusPollInterval = 2 * time.Second
+
+const (
+	configKeyAdded   = "added"
+	configKeyRemoved = "removed"
+	configKeyChanged = "changed"
+)
+
+// configChange describes a single config key that differs between the deployed and the desired connector config.
+type configChange struct {
+	Key      string `json:"key" yaml:"key" header:"Key"`
+	Change   string `json:"change" yaml:"change" header:"Change"`
+	Deployed string `json:"deployed,omitempty" yaml:"deployed,omitempty" header:"Deployed"`
+	Desired  string `json:"desired,omitempty" yaml:"desired,omitempty" header:"Desired"`
+}
+
+// diffConnectorConfig returns the config keys that differ between "deployed" and "desired", sorted by key.
+// Values are compared by their string form, as Kafka Connect stores every value as a string.
+// The "name" is ignored, Kafka Connect adds it to the deployed config and a connector file may not declare it.
+func diffConnectorConfig(deployed, desired api.ConnectorConfig) (changes []configChange) {
+	for key, value := range desired {
+		if key == "name" {
+			continue
+		}
+
+		newValue := fmt.Sprint(value)
+		oldValue, found := deployed[key]
+		if !found {
+			changes = append(changes, configChange{Key: key, Change: configKeyAdded, Desired: newValue})
+			continue
+		}
+
+		if oldValue := fmt.Sprint(oldValue); oldValue != newValue {
+			changes = append(changes, configChange{Key: key, Change: configKeyChanged, Deployed: oldValue, Desired: newValue})
+		}
+	}
+
+	for key, value := range deployed {
+		if _, found := desired[key]; !found && key != "name" {
+			changes = append(changes, configChange{Key: key, Change: configKeyRemoved, Deployed: fmt.Sprint(value)})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+
+	return
+}
+
+// stringConfig returns the string form of each value of the "cfg", as Kafka Connect stores them.
+func stringConfig(cfg api.ConnectorConfig) map[string]string {
+	out := make(map[string]string, len(cfg))
+	for key, value := range cfg {
+		out[key] = fmt.Sprint(value)
+	}
+
+	return out
+}
+
+// validateConnectorConfig validates "cfg" against the config definition of its "connector.class" plugin
+// on the server of the "client" and prints the keys that failed to validate.
+func validateConnectorConfig(cmd *cobra.Command, client *api.Client, clusterName string, cfg api.ConnectorConfig) error {
+	class, ok := cfg["connector.class"].(string)
+	if !ok || class == "" {
+		return fmt.Errorf(`config["connector.class"] is required`)
+	}
+
+	validation, err := client.ValidateConnectorConfig(clusterName, class, cfg)
+	if err != nil {
+		golog.Errorf("Failed to validate the config of connector plugin [%s] in cluster [%s]. [%s]", class, clusterName, err.Error())
+		return err
+	}
+
+	if errs := validation.Errors(); len(errs) > 0 {
+		if err := bite.PrintObject(cmd, errs); err != nil {
+			return err
+		}
+
+		return fmt.Errorf("connector config validation failed with [%d] error(s)", validation.ErrorCount)
+	}
+
+	return nil
+}
+
+// connectorRunning reports whether the connector and all of its tasks are RUNNING.
+// It returns an error if the connector or any of its tasks has FAILED.
+func connectorRunning(cs api.ConnectorStatus) (bool, error) {
+	if cs.Connector.State == string(api.FAILED) {
+		return false, fmt.Errorf("connector [%s] failed on worker [%s]", cs.Name, cs.Connector.WorkerID)
+	}
+
+	for _, task := range cs.Tasks {
+		if task.State == string(api.FAILED) {
+			return false, fmt.Errorf("connector [%s] task [%d] failed on worker [%s]: %s", cs.Name, task.ID, task.WorkerID, task.Trace)
+		}
+	}
+
+	if cs.Connector.State != string(api.RUNNING) || len(cs.Tasks) == 0 {
+		return false, nil
+	}
+
+	for _, task := range cs.Tasks {
+		if task.State != string(api.RUNNING) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// waitConnectorRunning polls the connector status every "interval" until the connector and its tasks are RUNNING.
+// The first poll happens after "interval", so the tasks have time to be restarted with the new config.
+func waitConnectorRunning(client *api.Client, clusterName, name string, timeout, interval time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		time.Sleep(interval)
+
+		cs, err := client.GetConnectorStatus(clusterName, name)
+		if err != nil {
+			return err
+		}
+
+		running, err := connectorRunning(cs)
+		if err != nil || running {
+			return err
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out after [%s] waiting for connector [%s:%s] tasks to be running", timeout, clusterName, name)
+		}
+	}
+}
+
+//NewConnectorDiffCommand creates the `connector diff` command
+func NewConnectorDiffCommand() *cobra.Command {
+	var (
+		file              string
+		clusterName, name string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Show the config keys that differ between a connector file and the deployed connector",
+		Example: `connector diff -f ./connector.yml
+connector diff -f ./connector.yml --unified --no-color`,
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := bite.CheckRequiredFlags(cmd, bite.FlagPair{"file": file}); err != nil {
+				return err
+			}
+
+			connector := api.CreateUpdateConnectorPayload{Config: make(api.ConnectorConfig)}
+			if err := config.ReadManifest(file, manifest.KindConnector, &connector); err != nil {
+				return fmt.Errorf("unable to read the connector file [%s]: [%v]", file, err)
+			}
+
+			if clusterName != "" {
+				connector.ClusterName = clusterName
+			}
+
+			if name != "" {
+				connector.Name = name
+			}
+
+			if err := connector.ApplyAndValidateName(); err != nil {
+				return err
+			}
+
+			if err := bite.CheckRequiredFlags(cmd, bite.FlagPair{"cluster-name": connector.ClusterName, "name": connector.Name}); err != nil {
+				return err
+			}
+
+			existingConnector, err := config.Client.GetConnector(connector.ClusterName, connector.Name)
+			if err != nil {
+				bite.FriendlyError(cmd, pkg.ErrResourceNotFoundMessage, "connector [%s:%s] does not exist", connector.ClusterName, connector.Name)
+				return err
+			}
+
+			changes := diffConnectorConfig(existingConnector.Config, connector.Config)
+			if len(changes) == 0 {
+				return bite.PrintInfo(cmd, "Connector [%s:%s] is up to date", connector.ClusterName, connector.Name)
+			}
+
+			if strings.ToUpper(bite.GetOutPutFlag(cmd)) != "TABLE" {
+				return bite.PrintObject(cmd, changes)
+			}
+
+			deployed := fmt.Sprintf("%s:%s", connector.ClusterName, connector.Name)
+			return diff.Write(cmd.OutOrStdout(), deployed, file, stringConfig(existingConnector.Config), stringConfig(connector.Config), diff.OptionsOf(cmd))
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "The connector file (yaml or json) with the clusterName, name and config fields")
+	cmd.Flags().StringVar(&clusterName, "cluster-name", "", `Connect cluster name, overrides the file's one`)
+	cmd.Flags().StringVar(&name, "name", "", `Connector name, overrides the file's one`)
+	diff.AddFlags(cmd)
+
+	bite.CanPrintJSON(cmd)
+
+	return cmd
+}
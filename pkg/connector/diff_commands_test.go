@@ -0,0 +1,122 @@
+package connector
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	test "github.com/lensesio/lenses-go/test"
+)
+
+func TestDiffConnectorConfig(t *testing.T) {
+	deployed := api.ConnectorConfig{
+		"name":            "sink",
+		"connector.class": "FileStreamSink",
+		"tasks.max":       "1",
+		"file":            "/tmp/out",
+	}
+
+	desired := api.ConnectorConfig{
+		"name":            "sink",
+		"connector.class": "FileStreamSink",
+		"tasks.max":       2,
+		"topics":          "orders",
+	}
+
+	expected := []configChange{
+		{Key: "file", Change: configKeyRemoved, Deployed: "/tmp/out"},
+		{Key: "tasks.max", Change: configKeyChanged, Deployed: "1", Desired: "2"},
+		{Key: "topics", Change: configKeyAdded, Desired: "orders"},
+	}
+
+	if got := diffConnectorConfig(deployed, desired); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected changes:\n%#+v\nbut got:\n%#+v", expected, got)
+	}
+
+	if got := diffConnectorConfig(deployed, deployed); len(got) != 0 {
+		t.Fatalf("expected no changes but got: %#+v", got)
+	}
+
+	// the name that Kafka Connect adds to the deployed config is not a removed key.
+	delete(desired, "name")
+	if got := diffConnectorConfig(deployed, desired); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected changes:\n%#+v\nbut got:\n%#+v", expected, got)
+	}
+}
+
+func TestConnectorRunning(t *testing.T) {
+	running := api.ConnectorStatusConnectorField{State: string(api.RUNNING)}
+
+	tests := []struct {
+		name    string
+		status  api.ConnectorStatus
+		running bool
+		err     bool
+	}{
+		{"no tasks", api.ConnectorStatus{Connector: running}, false, false},
+		{"all running", api.ConnectorStatus{Connector: running, Tasks: []api.ConnectorStatusTask{{State: "RUNNING"}, {State: "RUNNING"}}}, true, false},
+		{"task unassigned", api.ConnectorStatus{Connector: running, Tasks: []api.ConnectorStatusTask{{State: "RUNNING"}, {State: "UNASSIGNED"}}}, false, false},
+		{"task failed", api.ConnectorStatus{Connector: running, Tasks: []api.ConnectorStatusTask{{State: "FAILED"}}}, false, true},
+		{"connector failed", api.ConnectorStatus{Connector: api.ConnectorStatusConnectorField{State: "FAILED"}}, false, true},
+	}
+
+	for _, tt := range tests {
+		got, err := connectorRunning(tt.status)
+		if got != tt.running {
+			t.Errorf("[%s] expected running [%v] but got [%v]", tt.name, tt.running, got)
+		}
+
+		if (err != nil) != tt.err {
+			t.Errorf("[%s] expected error [%v] but got [%v]", tt.name, tt.err, err)
+		}
+	}
+}
+
+func TestWaitConnectorRunning(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []string
+		polls    int
+		err      string
+	}{
+		{"running after a restart", []string{"UNASSIGNED", "UNASSIGNED", "RUNNING"}, 3, ""},
+		{"task failed", []string{"UNASSIGNED", "FAILED"}, 2, "connector [sink] task [0] failed on worker [fakehost:8083]: boom"},
+		{"timeout", []string{"UNASSIGNED"}, 0, "timed out after [20ms] waiting for connector [dev:sink] tasks to be running"},
+	}
+
+	for _, tt := range tests {
+		var polls int
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			state := tt.statuses[len(tt.statuses)-1]
+			if polls < len(tt.statuses) {
+				state = tt.statuses[polls]
+			}
+			polls++
+
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"name":"sink","connector":{"state":"RUNNING"},"tasks":[{"id":0,"state":%q,"worker_id":"fakehost:8083","trace":"boom"}]}`, state)
+		})
+		httpClient, teardown := test.TestingHTTPClient(h)
+
+		client, err := api.OpenConnection(test.ClientConfig, api.UsingClient(httpClient))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = waitConnectorRunning(client, "dev", "sink", 20*time.Millisecond, time.Millisecond)
+		teardown()
+
+		if tt.err == "" && err != nil {
+			t.Errorf("[%s] unexpected error: %v", tt.name, err)
+		} else if tt.err != "" && (err == nil || err.Error() != tt.err) {
+			t.Errorf("[%s] expected error [%s] but got [%v]", tt.name, tt.err, err)
+		}
+
+		if tt.polls > 0 && polls != tt.polls {
+			t.Errorf("[%s] expected [%d] status polls but got [%d]", tt.name, tt.polls, polls)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package connector
+
+import (
+	"sort"
+
+	"github.com/kataras/golog"
+	"github.com/lensesio/bite"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/spf13/cobra"
+)
+
+//NewConnectorPluginGroupCommand creates the `connector plugin` command
+func NewConnectorPluginGroupCommand() *cobra.Command {
+	root := &cobra.Command{
+		Use:              "plugin",
+		Short:            "Inspect a connector plugin, see connector plugin --help for details",
+		Example:          `connector plugin describe org.apache.kafka.connect.file.FileStreamSinkConnector --cluster="cluster_name"`,
+		SilenceErrors:    true,
+		TraverseChildren: true,
+	}
+
+	root.AddCommand(NewConnectorPluginDescribeCommand())
+
+	return root
+}
+
+//NewConnectorPluginDescribeCommand creates the `connector plugin describe` command
+func NewConnectorPluginDescribeCommand() *cobra.Command {
+	var clusterName string
+
+	cmd := &cobra.Command{
+		Use:              "describe <class>",
+		Short:            "Print the documentation of the config keys that a connector plugin accepts",
+		Example:          `connector plugin describe FileStreamSink --cluster="cluster_name" --output=json`,
+		Args:             cobra.ExactArgs(1),
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := bite.CheckRequiredFlags(cmd, bite.FlagPair{"cluster": clusterName}); err != nil {
+				return err
+			}
+
+			class := args[0]
+			definitions, err := config.Client.GetConnectorPluginConfig(clusterName, class)
+			if err != nil {
+				golog.Errorf("Failed to describe connector plugin [%s] in cluster [%s]. [%s]", class, clusterName, err.Error())
+				return err
+			}
+
+			sort.SliceStable(definitions, func(i, j int) bool {
+				if definitions[i].Group != definitions[j].Group {
+					return definitions[i].Group < definitions[j].Group
+				}
+
+				return definitions[i].Name < definitions[j].Name
+			})
+
+			return bite.PrintObject(cmd, definitions)
+		},
+	}
+
+	cmd.Flags().StringVar(&clusterName, "cluster", "", `Connect cluster name`)
+
+	bite.CanPrintJSON(cmd)
+
+	return cmd
+}
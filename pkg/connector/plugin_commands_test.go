@@ -0,0 +1,45 @@
+package connector
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	test "github.com/lensesio/lenses-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetConnectorPlugins(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(r.URL.Path, "/prod/connector-plugins"):
+			w.Write([]byte(`[{"class":"FileStreamSource","type":"source","version":"2.5"}]`))
+		case strings.Contains(r.URL.Path, "/dev/connector-plugins"):
+			w.Write([]byte(`[{"class":"FileStreamSink","type":"sink","version":"2.5"},{"class":"FileStreamSource","type":"source","version":"2.5"}]`))
+		default:
+			w.Write([]byte(`[{"name":"prod","templateName":"KafkaConnect"},{"name":"kafka","templateName":"Kafka"},{"name":"dev","templateName":"KafkaConnect"}]`))
+		}
+	})
+	httpClient, teardown := test.TestingHTTPClient(h)
+	defer teardown()
+
+	client, err := api.OpenConnection(test.ClientConfig, api.UsingClient(httpClient))
+	assert.Nil(t, err)
+	config.Client = client
+	defer func() { config.Client = nil }()
+
+	plugins, err := getConnectorPlugins("")
+	assert.Nil(t, err)
+	assert.Equal(t, []api.ConnectorPlugin{
+		{ClusterName: "dev", Class: "FileStreamSink", Type: "sink", Version: "2.5"},
+		{ClusterName: "dev", Class: "FileStreamSource", Type: "source", Version: "2.5"},
+		{ClusterName: "prod", Class: "FileStreamSource", Type: "source", Version: "2.5"},
+	}, plugins)
+
+	plugins, err = getConnectorPlugins("prod")
+	assert.Nil(t, err)
+	assert.Equal(t, []api.ConnectorPlugin{{ClusterName: "prod", Class: "FileStreamSource", Type: "source", Version: "2.5"}}, plugins)
+}
@@ -0,0 +1,184 @@
+package connector
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kataras/golog"
+	"github.com/lensesio/bite"
+	"github.com/lensesio/lenses-go/pkg/api"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// parseConfigOverrides returns the config keys and values of the "key=value" overrides.
+func parseConfigOverrides(overrides []string) (map[string]string, error) {
+	values := make(map[string]string, len(overrides))
+	for _, override := range overrides {
+		idx := strings.IndexByte(override, '=')
+		if idx <= 0 {
+			return nil, fmt.Errorf("invalid override [%s], expected key=value", override)
+		}
+
+		values[strings.TrimSpace(override[:idx])] = override[idx+1:]
+	}
+
+	return values, nil
+}
+
+// promotedConfig returns a copy of the "cfg" with the "overrides" set, the "unset" keys removed and its name set to the "name".
+func promotedConfig(cfg api.ConnectorConfig, name string, overrides map[string]string, unset []string) api.ConnectorConfig {
+	promoted := make(api.ConnectorConfig, len(cfg)+len(overrides))
+	for key, value := range cfg {
+		promoted[key] = value
+	}
+
+	for _, key := range unset {
+		delete(promoted, key)
+	}
+
+	for key, value := range overrides {
+		promoted[key] = value
+	}
+
+	promoted["name"] = name
+	return promoted
+}
+
+//NewConnectorPromoteCommand creates the `connector promote` command
+func NewConnectorPromoteCommand() *cobra.Command {
+	var (
+		from, to                      string
+		clusterName, name             string
+		targetClusterName, targetName string
+		overrides, unset              []string
+		dryRun, skipValidation, wait  bool
+		waitTimeout                   time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "promote",
+		Short: "Deploy a connector of a context to another context, with overrides of its config",
+		Long: `Read the config of a connector of a context of the configuration file, apply the --set and --unset overrides,
+validate it against the Connect cluster of the --to context and create or update the connector there.
+--dry-run prints the config changes of the target connector without deploying it.`,
+		Example: `connector promote --from staging --to prod --cluster-name connect --name orders-sink --set topics=prod_orders --dry-run
+connector promote --from staging --to prod --cluster-name connect --name orders-sink --set topics=prod_orders --set tasks.max=4`,
+		// it deploys to the --to context, which is checked by `config.CheckProtectedContext` too.
+		Annotations:      map[string]string{config.AnnotationMutating: "true"},
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := bite.CheckRequiredFlags(cmd, bite.FlagPair{"from": from, "to": to, "cluster-name": clusterName, "name": name}); err != nil {
+				return err
+			}
+
+			if !dryRun {
+				if err := config.CheckProtectedContext(to, cmd); err != nil {
+					return err
+				}
+			}
+
+			if targetClusterName == "" {
+				targetClusterName = clusterName
+			}
+
+			if targetName == "" {
+				targetName = name
+			}
+
+			values, err := parseConfigOverrides(overrides)
+			if err != nil {
+				return err
+			}
+
+			source, err := config.Manager.ContextClient(from)
+			if err != nil {
+				return err
+			}
+
+			target, err := config.Manager.ContextClient(to)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := source.GetConnectorConfig(clusterName, name)
+			if err != nil {
+				golog.Errorf("Failed to retrieve the config of connector [%s:%s] of context [%s]. [%s]", clusterName, name, from, err.Error())
+				return err
+			}
+
+			promoted := promotedConfig(cfg, targetName, values, unset)
+
+			// the connector is created if it does not exist on the target.
+			var deployed api.ConnectorConfig
+			existingConnector, err := target.GetConnector(targetClusterName, targetName)
+			if err == nil {
+				deployed = existingConnector.Config
+			} else {
+				var resErr api.ResourceError
+				if !errors.As(err, &resErr) || resErr.StatusCode != http.StatusNotFound {
+					return err
+				}
+			}
+
+			changes := diffConnectorConfig(deployed, promoted)
+			if len(changes) == 0 {
+				return bite.PrintInfo(cmd, "Connector [%s:%s] of context [%s] is up to date", targetClusterName, targetName, to)
+			}
+
+			if dryRun {
+				return bite.PrintObject(cmd, changes)
+			}
+
+			if !skipValidation {
+				if err := validateConnectorConfig(cmd, target, targetClusterName, promoted); err != nil {
+					return err
+				}
+			}
+
+			action := "updated"
+			if deployed == nil {
+				action = "created"
+				_, err = target.CreateConnector(targetClusterName, targetName, promoted)
+			} else {
+				_, err = target.UpdateConnector(targetClusterName, targetName, promoted)
+			}
+
+			if err != nil {
+				golog.Errorf("Failed to deploy connector [%s:%s] to context [%s]. [%s]", targetClusterName, targetName, to, err.Error())
+				return err
+			}
+
+			if wait {
+				if err := waitConnectorRunning(target, targetClusterName, targetName, waitTimeout, connectorStatusPollInterval); err != nil {
+					golog.Errorf("Connector [%s:%s] %s but its tasks are not running. [%s]", targetClusterName, targetName, action, err.Error())
+					return err
+				}
+			}
+
+			return bite.PrintInfo(cmd, "Connector [%s:%s] of context [%s] %s", targetClusterName, targetName, to, action)
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "The context of the configuration file to read the connector from")
+	cmd.Flags().StringVar(&to, "to", "", "The context of the configuration file to deploy the connector to")
+	cmd.Flags().StringVar(&clusterName, "cluster-name", "", `Connect cluster name`)
+	cmd.Flags().StringVar(&name, "name", "", `Connector name`)
+	cmd.Flags().StringVar(&targetClusterName, "target-cluster-name", "", "The Connect cluster name of the target context, defaults to the --cluster-name")
+	cmd.Flags().StringVar(&targetName, "target-name", "", "The connector name of the target context, defaults to the --name")
+	cmd.Flags().StringArrayVar(&overrides, "set", nil, "A config override, key=value, repeatable")
+	cmd.Flags().StringArrayVar(&unset, "unset", nil, "A config key to remove, repeatable")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the config changes of the target connector without deploying it")
+	cmd.Flags().BoolVar(&skipValidation, "skip-validation", false, "Do not validate the config against the connector plugin of the target before deploying it")
+	cmd.Flags().BoolVar(&wait, "wait", true, "Wait for the connector tasks to be running after the deployment")
+	cmd.Flags().DurationVar(&waitTimeout, "wait-timeout", 2*time.Minute, "How long to wait for the connector tasks to be running")
+
+	bite.CanBeSilent(cmd)
+	bite.CanPrintJSON(cmd)
+
+	return cmd
+}
@@ -0,0 +1,47 @@
+package connector
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+)
+
+func TestParseConfigOverrides(t *testing.T) {
+	got, err := parseConfigOverrides([]string{"topics=prod_orders", "transforms.route.regex=(.*)=$1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{"topics": "prod_orders", "transforms.route.regex": "(.*)=$1"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected overrides:\n%#+v\nbut got:\n%#+v", expected, got)
+	}
+
+	if _, err = parseConfigOverrides([]string{"=value"}); err == nil {
+		t.Fatal("expected an error for an override without a key")
+	}
+}
+
+func TestPromotedConfig(t *testing.T) {
+	cfg := api.ConnectorConfig{
+		"name":            "orders-sink",
+		"connector.class": "FileStreamSink",
+		"topics":          "staging_orders",
+		"file":            "/tmp/out",
+	}
+
+	got := promotedConfig(cfg, "orders-sink-prod", map[string]string{"topics": "prod_orders"}, []string{"file"})
+	expected := api.ConnectorConfig{
+		"name":            "orders-sink-prod",
+		"connector.class": "FileStreamSink",
+		"topics":          "prod_orders",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected config:\n%#+v\nbut got:\n%#+v", expected, got)
+	}
+
+	if cfg["topics"] != "staging_orders" || cfg["file"] != "/tmp/out" {
+		t.Fatalf("expected the source config to be unchanged but got: %#+v", cfg)
+	}
+}
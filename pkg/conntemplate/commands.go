@@ -6,6 +6,7 @@ import (
 	"github.com/kataras/golog"
 	"github.com/lensesio/bite"
 	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/printer"
 	cobra "github.com/spf13/cobra"
 )
 
@@ -32,11 +33,12 @@ connection-templates
 				bite.PrintInfo(cmd, "Info: use JSON or YAML output to get the complete object\n\n")
 			}
 
-			return bite.PrintObject(cmd, connectionTemplates)
+			return printer.PrintObject(cmd, connectionTemplates)
 		},
 	}
 
 	bite.CanPrintJSON(cmd)
+	printer.CanSelect(cmd)
 
 	return cmd
 }
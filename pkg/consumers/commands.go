@@ -58,6 +58,7 @@ func NewRootCommand() *cobra.Command {
 	}
 
 	cmd.AddCommand(newOffsetsCommand())
+	cmd.AddCommand(newDescribeCommand())
 
 	return cmd
 }
@@ -0,0 +1,133 @@
+package consumers
+
+import (
+	"sort"
+
+	"github.com/lensesio/bite"
+	"github.com/lensesio/lenses-go/pkg/api"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/spf13/cobra"
+)
+
+const (
+	describeCmdDescLong string = "Describes the members of a consumer group, their client IDs, hosts, assigned partitions and offsets."
+	describeCmdExample  string = `
+  # The assigned partitions of each member and the group's offsets on them
+  lenses-cli consumers describe --group <group_name>
+
+  # The assigned partitions and the total lag per member, to spot the unbalanced or stuck members
+  lenses-cli consumers describe --group <group_name> --summary`
+)
+
+// memberPartitionView is a row of `consumers describe`, a partition assigned to a member.
+type memberPartitionView struct {
+	ClientID        string `json:"clientId" header:"Client ID"`
+	ConsumerID      string `json:"consumerId" header:"Consumer ID"`
+	Host            string `json:"host" header:"Host"`
+	Topic           string `json:"topic" header:"Topic"`
+	Partition       int    `json:"partition" header:"Partition"`
+	CommittedOffset int64  `json:"committedOffset" header:"Committed Offset"`
+	EndOffset       int64  `json:"endOffset" header:"End Offset"`
+	Lag             int64  `json:"lag" header:"Lag"`
+}
+
+// memberSummaryView is a row of `consumers describe --summary`, a member and the totals of its assignment.
+type memberSummaryView struct {
+	ClientID   string `json:"clientId" header:"Client ID"`
+	ConsumerID string `json:"consumerId" header:"Consumer ID"`
+	Host       string `json:"host" header:"Host"`
+	Partitions int    `json:"partitions" header:"Partitions"`
+	Lag        int64  `json:"lag" header:"Lag"`
+}
+
+// memberPartitions returns a row per assigned partition of the "group", the members without partitions have an empty one,
+// sorted by client ID, topic and partition.
+func memberPartitions(group api.ConsumerGroup) []memberPartitionView {
+	var rows []memberPartitionView
+	for _, member := range group.Members {
+		row := memberPartitionView{ClientID: member.ClientID, ConsumerID: member.ConsumerID, Host: member.Host}
+		if len(member.Partitions) == 0 {
+			rows = append(rows, row)
+			continue
+		}
+
+		for _, p := range member.Partitions {
+			row.Topic, row.Partition = p.Topic, p.Partition
+			row.CommittedOffset, row.EndOffset, row.Lag = p.CurrentOffset, p.LogEndOffset, p.Lag
+			rows = append(rows, row)
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if a.ClientID != b.ClientID {
+			return a.ClientID < b.ClientID
+		}
+		if a.ConsumerID != b.ConsumerID {
+			return a.ConsumerID < b.ConsumerID
+		}
+		if a.Topic != b.Topic {
+			return a.Topic < b.Topic
+		}
+		return a.Partition < b.Partition
+	})
+
+	return rows
+}
+
+// memberSummaries returns a row per member of the "group", sorted by their lag, the largest first.
+func memberSummaries(group api.ConsumerGroup) []memberSummaryView {
+	rows := make([]memberSummaryView, 0, len(group.Members))
+	for _, member := range group.Members {
+		row := memberSummaryView{ClientID: member.ClientID, ConsumerID: member.ConsumerID, Host: member.Host, Partitions: len(member.Partitions)}
+		for _, p := range member.Partitions {
+			row.Lag += p.Lag
+		}
+		rows = append(rows, row)
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].Lag != rows[j].Lag {
+			return rows[i].Lag > rows[j].Lag
+		}
+		return rows[i].ClientID < rows[j].ClientID
+	})
+
+	return rows
+}
+
+func newDescribeCommand() *cobra.Command {
+	var (
+		group   string
+		summary bool
+	)
+
+	cmd := &cobra.Command{
+		Use:              "describe",
+		Short:            describeCmdDescLong,
+		Long:             describeCmdDescLong,
+		Example:          describeCmdExample,
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			consumerGroup, err := config.Client.GetConsumerGroup(group)
+			if err != nil {
+				return err
+			}
+
+			if summary {
+				return bite.PrintObject(cmd, memberSummaries(consumerGroup))
+			}
+
+			return bite.PrintObject(cmd, memberPartitions(consumerGroup))
+		},
+	}
+
+	cmd.Flags().StringVarP(&group, "group", "g", "", "Consumer Group ID")
+	cmd.Flags().BoolVar(&summary, "summary", false, "Print a row per member, with the number of its partitions and its total lag")
+	cmd.MarkFlagRequired("group")
+
+	bite.CanPrintJSON(cmd)
+
+	return cmd
+}
@@ -0,0 +1,67 @@
+package consumers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const consumerGroupJSON = `{
+	"id": "payments-app",
+	"state": "Stable",
+	"coordinator": {"id": 1, "host": "broker-1", "port": 9092},
+	"consumers": [
+		{"consumerId": "c-2-uuid", "clientId": "c-2", "host": "/10.0.0.2", "topicPartitions": [
+			{"topic": "payments", "partition": 1, "currentOffset": 90, "logEndOffset": 100, "lag": 10},
+			{"topic": "payments", "partition": 0, "currentOffset": 50, "logEndOffset": 150, "lag": 100}
+		]},
+		{"consumerId": "c-1-uuid", "clientId": "c-1", "host": "/10.0.0.1", "topicPartitions": []}
+	]
+}`
+
+func TestDescribeConsumerGroup(t *testing.T) {
+	var path string
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Write([]byte(consumerGroupJSON))
+	})
+	httpClient, teardown := test.TestingHTTPClient(h)
+	defer teardown()
+	client, err := api.OpenConnection(test.ClientConfig, api.UsingClient(httpClient))
+	assert.Nil(t, err)
+	config.Client = client
+
+	var outputValue string
+	cmd := NewRootCommand()
+	cmd.PersistentFlags().StringVar(&outputValue, "output", "json", "")
+
+	out, err := test.ExecuteCommand(cmd, "describe", "--group", "payments-app")
+	assert.NoError(t, err)
+	assert.Equal(t, "/api/consumers/payments-app", path)
+
+	var rows []memberPartitionView
+	assert.NoError(t, json.Unmarshal([]byte(out), &rows))
+	assert.Equal(t, []memberPartitionView{
+		{ClientID: "c-1", ConsumerID: "c-1-uuid", Host: "/10.0.0.1"},
+		{ClientID: "c-2", ConsumerID: "c-2-uuid", Host: "/10.0.0.2", Topic: "payments", Partition: 0, CommittedOffset: 50, EndOffset: 150, Lag: 100},
+		{ClientID: "c-2", ConsumerID: "c-2-uuid", Host: "/10.0.0.2", Topic: "payments", Partition: 1, CommittedOffset: 90, EndOffset: 100, Lag: 10},
+	}, rows)
+
+	cmd = NewRootCommand()
+	cmd.PersistentFlags().StringVar(&outputValue, "output", "json", "")
+
+	out, err = test.ExecuteCommand(cmd, "describe", "--group", "payments-app", "--summary")
+	assert.NoError(t, err)
+
+	var summaries []memberSummaryView
+	assert.NoError(t, json.Unmarshal([]byte(out), &summaries))
+	assert.Equal(t, []memberSummaryView{
+		{ClientID: "c-2", ConsumerID: "c-2-uuid", Host: "/10.0.0.2", Partitions: 2, Lag: 110},
+		{ClientID: "c-1", ConsumerID: "c-1-uuid", Host: "/10.0.0.1"},
+	}, summaries)
+}
@@ -0,0 +1,159 @@
+// Package dedupe suppresses the duplicate records of a stream on the client side, the ones whose key, value
+// or field was already seen within a time window, with a bounded LRU store of the seen ones.
+package dedupe
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lensesio/lenses-go/pkg/sink"
+	"github.com/lensesio/lenses-go/pkg/transform"
+	"github.com/spf13/pflag"
+)
+
+// DefaultMaxEntries is the default number of the records that a `Filter` remembers.
+const DefaultMaxEntries = 100000
+
+// The identities of the records, the `Options#By`, the rest are the dot separated paths of a field.
+const (
+	ByKey   = "key"
+	ByValue = "value"
+)
+
+// Options are the dedupe flags of the commands which print or export records.
+type Options struct {
+	// By is "key", "value" or the dot separated path of a field of the value, `transform.KeyPrefix` for a field of the key.
+	By string
+	// Window is the time, of the records' timestamps, a record is a duplicate of an equal one for, zero is forever.
+	Window time.Duration
+	// MaxEntries is the number of the most recent records which are remembered, defaults to `DefaultMaxEntries`.
+	MaxEntries int
+}
+
+// AddFlags registers the "--dedupe-by", "--window" and "--dedupe-max-entries" flags.
+func (opts *Options) AddFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&opts.By, "dedupe-by", "", "Skip the records whose key, value or field, i.e customer.id or _key.id, was already seen within the --window")
+	flags.DurationVar(&opts.Window, "window", 0, "The time, of the records' timestamps, a record is a duplicate of an equal one for, 0 is forever")
+	flags.IntVar(&opts.MaxEntries, "dedupe-max-entries", DefaultMaxEntries, "The number of the most recent records --dedupe-by remembers")
+}
+
+// Filter returns the filter of the options, nil if "By" is empty.
+func (opts Options) Filter() (*Filter, error) {
+	if opts.By == "" {
+		return nil, nil
+	}
+
+	if opts.Window < 0 {
+		return nil, fmt.Errorf("invalid dedupe window [%s]", opts.Window)
+	}
+
+	max := opts.MaxEntries
+	if max <= 0 {
+		max = DefaultMaxEntries
+	}
+
+	return &Filter{by: opts.By, window: opts.Window, max: max, order: list.New(), seen: make(map[[sha256.Size]byte]*list.Element)}, nil
+}
+
+type entry struct {
+	id   [sha256.Size]byte
+	time time.Time
+}
+
+// Filter reports the duplicate records, it's not safe for concurrent use.
+type Filter struct {
+	by     string
+	window time.Duration
+	max    int
+
+	// order is the seen records, the most recent first.
+	order *list.List
+	seen  map[[sha256.Size]byte]*list.Element
+
+	skipped int64
+}
+
+// Duplicate reports whether an equal "record" was seen within the window, otherwise it remembers it.
+// A record without the field of the filter is never a duplicate.
+func (f *Filter) Duplicate(record sink.Record) bool {
+	data, ok := f.identity(record)
+	if !ok {
+		return false
+	}
+
+	id := sha256.Sum256(data)
+	t := record.Time()
+
+	if el, ok := f.seen[id]; ok {
+		e := el.Value.(*entry)
+		f.order.MoveToFront(el)
+		if f.window == 0 || absDuration(t.Sub(e.time)) <= f.window {
+			f.skipped++
+			return true
+		}
+
+		// out of the window, it's the first of the next one.
+		e.time = t
+		return false
+	}
+
+	f.seen[id] = f.order.PushFront(&entry{id: id, time: t})
+	if f.order.Len() > f.max {
+		oldest := f.order.Back()
+		f.order.Remove(oldest)
+		delete(f.seen, oldest.Value.(*entry).id)
+	}
+
+	return false
+}
+
+// Skipped returns the number of the duplicate records.
+func (f *Filter) Skipped() int64 {
+	return f.skipped
+}
+
+// identity returns the data which identifies the "record", false if it has not the field of the filter.
+func (f *Filter) identity(record sink.Record) (json.RawMessage, bool) {
+	switch f.by {
+	case ByKey:
+		return record.Key, len(record.Key) > 0
+	case ByValue:
+		return record.Value, len(record.Value) > 0
+	}
+
+	data, path := record.Value, f.by
+	if strings.HasPrefix(path, transform.KeyPrefix) {
+		data, path = record.Key, strings.TrimPrefix(path, transform.KeyPrefix)
+	}
+
+	var v interface{}
+	if json.Unmarshal(data, &v) != nil {
+		return nil, false
+	}
+
+	for _, name := range strings.Split(path, ".") {
+		fields, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		if v, ok = fields[name]; !ok {
+			return nil, false
+		}
+	}
+
+	b, err := json.Marshal(v)
+	return b, err == nil
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+
+	return d
+}
@@ -0,0 +1,75 @@
+package dedupe
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/lensesio/lenses-go/pkg/sink"
+	"github.com/lensesio/lenses-go/pkg/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// record returns a record of the "key" and "value" with a timestamp of "minute" minutes.
+func record(key, value string, minute int) sink.Record {
+	return sink.Record{
+		Key:      json.RawMessage(key),
+		Value:    json.RawMessage(value),
+		Metadata: websocket.MetaData{Timestamp: float64(time.Duration(minute) * time.Minute / time.Millisecond)},
+	}
+}
+
+func TestFilterByKey(t *testing.T) {
+	f, err := Options{By: ByKey, Window: 10 * time.Minute}.Filter()
+	require.NoError(t, err)
+
+	assert.False(t, f.Duplicate(record(`"a"`, `{"v":1}`, 1)))
+	assert.False(t, f.Duplicate(record(`"b"`, `{"v":1}`, 2)))
+	assert.True(t, f.Duplicate(record(`"a"`, `{"v":2}`, 5)))
+	// out of the window of the first one.
+	assert.False(t, f.Duplicate(record(`"a"`, `{"v":3}`, 12)))
+	assert.True(t, f.Duplicate(record(`"a"`, `{"v":4}`, 20)))
+	// no key.
+	assert.False(t, f.Duplicate(record(``, `{"v":1}`, 20)))
+	assert.False(t, f.Duplicate(record(``, `{"v":1}`, 20)))
+
+	assert.Equal(t, int64(2), f.Skipped())
+}
+
+func TestFilterByField(t *testing.T) {
+	f, err := Options{By: "customer.id"}.Filter()
+	require.NoError(t, err)
+
+	assert.False(t, f.Duplicate(record(`"a"`, `{"customer":{"id":1},"amount":1}`, 1)))
+	assert.True(t, f.Duplicate(record(`"b"`, `{"customer":{"id":1},"amount":2}`, 600)), "forever without a window")
+	assert.False(t, f.Duplicate(record(`"c"`, `{"customer":{"id":2}}`, 2)))
+	assert.False(t, f.Duplicate(record(`"d"`, `{"amount":3}`, 3)))
+
+	f, err = Options{By: "_key.id"}.Filter()
+	require.NoError(t, err)
+	assert.False(t, f.Duplicate(record(`{"id":"x","v":1}`, `1`, 1)))
+	assert.True(t, f.Duplicate(record(`{"id":"x","v":2}`, `2`, 1)))
+}
+
+func TestFilterMaxEntries(t *testing.T) {
+	f, err := Options{By: ByValue, MaxEntries: 2}.Filter()
+	require.NoError(t, err)
+
+	assert.False(t, f.Duplicate(record(``, `1`, 1)))
+	assert.False(t, f.Duplicate(record(``, `2`, 1)))
+	assert.True(t, f.Duplicate(record(``, `1`, 1)))
+	// evicts the least recently seen, 2.
+	assert.False(t, f.Duplicate(record(``, `3`, 1)))
+	assert.False(t, f.Duplicate(record(``, `2`, 1)))
+	assert.True(t, f.Duplicate(record(``, `3`, 1)))
+}
+
+func TestOptionsFilter(t *testing.T) {
+	f, err := Options{}.Filter()
+	assert.NoError(t, err)
+	assert.Nil(t, f)
+
+	_, err = Options{By: ByKey, Window: -time.Minute}.Filter()
+	assert.EqualError(t, err, "invalid dedupe window [-1m0s]")
+}
@@ -0,0 +1,449 @@
+// Package diff compares the deployed and the desired state of a resource and renders the differences,
+// either as the changed fields of their JSON form or as a unified diff of their lines, colored on a terminal.
+// It's used by the commands that preview a change, i.e `connector diff` and `processor apply --dry-run`.
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mgutz/ansi"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"gopkg.in/yaml.v2"
+)
+
+// The operations of a `Change`.
+const (
+	Added   = "added"
+	Removed = "removed"
+	Changed = "changed"
+)
+
+// Change is a field that differs between two values, see `Values`.
+type Change struct {
+	// Path is the dot separated path of the field, i.e "config.tasks.max" or "fields[2].name".
+	Path string      `json:"path" yaml:"path" header:"Path"`
+	Op   string      `json:"change" yaml:"change" header:"Change"`
+	From interface{} `json:"from,omitempty" yaml:"from,omitempty" header:"From"`
+	To   interface{} `json:"to,omitempty" yaml:"to,omitempty" header:"To"`
+}
+
+// Values returns the fields that differ between "from" and "to", sorted by their path.
+// The values are compared by their JSON form, so a struct and a map with the same fields are equal.
+func Values(from, to interface{}) ([]Change, error) {
+	a, err := normalize(from)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := normalize(to)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []Change
+	compare("", a, b, &changes)
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	return changes, nil
+}
+
+func normalize(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var out interface{}
+	err = json.Unmarshal(b, &out)
+	return out, err
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func compare(path string, a, b interface{}, changes *[]Change) {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		if bv, ok := b.(map[string]interface{}); ok {
+			for k, v := range av {
+				if w, found := bv[k]; found {
+					compare(join(path, k), v, w, changes)
+				} else {
+					*changes = append(*changes, Change{Path: join(path, k), Op: Removed, From: v})
+				}
+			}
+
+			for k, w := range bv {
+				if _, found := av[k]; !found {
+					*changes = append(*changes, Change{Path: join(path, k), Op: Added, To: w})
+				}
+			}
+			return
+		}
+	case []interface{}:
+		if bv, ok := b.([]interface{}); ok {
+			for i := 0; i < len(av) || i < len(bv); i++ {
+				p := path + "[" + strconv.Itoa(i) + "]"
+				switch {
+				case i >= len(bv):
+					*changes = append(*changes, Change{Path: p, Op: Removed, From: av[i]})
+				case i >= len(av):
+					*changes = append(*changes, Change{Path: p, Op: Added, To: bv[i]})
+				default:
+					compare(p, av[i], bv[i], changes)
+				}
+			}
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		*changes = append(*changes, Change{Path: path, Op: Changed, From: a, To: b})
+	}
+}
+
+// Merge returns the three-way merge of the "desired" fields of a manifest into the "live" fields of the server,
+// like `kubectl apply`: the desired fields are set, the "lastApplied" fields of the manifest which are not desired anymore
+// are removed and the rest of the live fields, set on the server but never declared in a manifest, are kept.
+func Merge(lastApplied []string, live, desired map[string]interface{}) map[string]interface{} {
+	applied := make(map[string]bool, len(lastApplied))
+	for _, k := range lastApplied {
+		applied[k] = true
+	}
+
+	merged := make(map[string]interface{}, len(live)+len(desired))
+	for k, v := range live {
+		if applied[k] {
+			if _, found := desired[k]; !found {
+				continue
+			}
+		}
+		merged[k] = v
+	}
+
+	for k, v := range desired {
+		merged[k] = v
+	}
+
+	return merged
+}
+
+// Line is a line of a `Lines` diff, the "Op" is ' ' for an unchanged line, '-' for a removed one and '+' for an added one.
+type Line struct {
+	Op   byte
+	Text string
+}
+
+// Lines returns the line by line diff of "from" and "to", based on their longest common subsequence.
+func Lines(from, to string) []Line {
+	a, b := splitLines(from), splitLines(to)
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	lines := make([]Line, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, Line{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, Line{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, Line{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, Line{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, Line{'+', b[j]})
+	}
+
+	return lines
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// DefaultContext is the number of unchanged lines around the changes of a unified diff.
+const DefaultContext = 3
+
+// Options controls the rendering of a diff, see `OptionsOf`.
+type Options struct {
+	// Color colors the removed lines red, the added green and the changed yellow.
+	Color bool
+	// Unified renders a unified diff of the YAML forms instead of the changed fields.
+	Unified bool
+	// Context is the number of the unchanged lines around the changes of a unified diff.
+	Context int
+}
+
+// isTerminal reports whether the standard output is a terminal, it can be replaced for tests.
+var isTerminal = func() bool {
+	return term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// AddFlags adds the `--no-color` and `--unified` flags of a command that prints a diff.
+func AddFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("no-color", false, "Do not color the diff, it's not colored when the output is not a terminal either")
+	cmd.Flags().Bool("unified", false, "Print a unified diff of the YAML forms instead of the changed fields")
+}
+
+// OptionsOf returns the diff options of the "cmd", see `AddFlags`.
+// The color is disabled by the `--no-color` flag, the NO_COLOR environment variable or a non terminal output.
+func OptionsOf(cmd *cobra.Command) Options {
+	noColor, _ := cmd.Flags().GetBool("no-color")
+	unified, _ := cmd.Flags().GetBool("unified")
+
+	_, noColorEnv := os.LookupEnv("NO_COLOR")
+
+	return Options{
+		Color:   !noColor && !noColorEnv && isTerminal(),
+		Unified: unified,
+		Context: DefaultContext,
+	}
+}
+
+var (
+	red    = ansi.ColorFunc("red")
+	green  = ansi.ColorFunc("green")
+	yellow = ansi.ColorFunc("yellow")
+	cyan   = ansi.ColorFunc("cyan")
+)
+
+func paint(color func(string) string, s string, enabled bool) string {
+	if !enabled {
+		return s
+	}
+	return color(s)
+}
+
+// Write writes the differences of "from" and "to" to "w", the changed fields or, if `Options.Unified`,
+// the unified diff of their YAML forms named "fromName" and "toName".
+func Write(w io.Writer, fromName, toName string, from, to interface{}, opts Options) error {
+	if opts.Unified {
+		a, err := toYAML(from)
+		if err != nil {
+			return err
+		}
+
+		b, err := toYAML(to)
+		if err != nil {
+			return err
+		}
+
+		return WriteUnified(w, fromName, toName, a, b, opts)
+	}
+
+	changes, err := Values(from, to)
+	if err != nil {
+		return err
+	}
+
+	return WriteChanges(w, changes, opts)
+}
+
+func toYAML(v interface{}) (string, error) {
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+
+	v, err := normalize(v)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := yaml.Marshal(v)
+	return string(b), err
+}
+
+// WriteChanges writes a line per change, "+ path: value", "- path: value" or "~ path: from => to".
+func WriteChanges(w io.Writer, changes []Change, opts Options) error {
+	for _, c := range changes {
+		var err error
+		switch c.Op {
+		case Added:
+			_, err = fmt.Fprintln(w, paint(green, fmt.Sprintf("+ %s: %s", c.Path, format(c.To)), opts.Color))
+		case Removed:
+			_, err = fmt.Fprintln(w, paint(red, fmt.Sprintf("- %s: %s", c.Path, format(c.From)), opts.Color))
+		default:
+			_, err = fmt.Fprintln(w, paint(yellow, fmt.Sprintf("~ %s: %s => %s", c.Path, format(c.From), format(c.To)), opts.Color))
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func format(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return strconv.Quote(value)
+	case map[string]interface{}, []interface{}:
+		b, _ := json.Marshal(value)
+		return string(b)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// WriteUnified writes the unified diff of the "from" and "to" texts, nothing if they are equal.
+func WriteUnified(w io.Writer, fromName, toName, from, to string, opts Options) error {
+	lines := Lines(from, to)
+
+	changed := false
+	for _, l := range lines {
+		if l.Op != ' ' {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	context := opts.Context
+	if context < 0 {
+		context = 0
+	}
+
+	var b strings.Builder
+	b.WriteString(paint(red, "--- "+fromName, opts.Color) + "\n")
+	b.WriteString(paint(green, "+++ "+toName, opts.Color) + "\n")
+
+	for _, h := range hunks(lines, context) {
+		b.WriteString(paint(cyan, fmt.Sprintf("@@ -%d,%d +%d,%d @@", h.fromLine, h.fromCount, h.toLine, h.toCount), opts.Color) + "\n")
+		for _, l := range lines[h.start:h.end] {
+			text := string(l.Op) + l.Text
+			switch l.Op {
+			case '-':
+				text = paint(red, text, opts.Color)
+			case '+':
+				text = paint(green, text, opts.Color)
+			}
+			b.WriteString(text + "\n")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type hunk struct {
+	start, end                           int // the range of the lines.
+	fromLine, fromCount, toLine, toCount int
+}
+
+// hunks groups the changed lines with their "context", the hunks that their contexts overlap are merged.
+func hunks(lines []Line, context int) (hs []hunk) {
+	for i := 0; i < len(lines); i++ {
+		if lines[i].Op == ' ' {
+			continue
+		}
+
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+
+		end := i
+		for j := i; j < len(lines); j++ {
+			if lines[j].Op != ' ' {
+				end = j
+				continue
+			}
+			if j-end > 2*context {
+				break
+			}
+		}
+		end += context + 1
+		if end > len(lines) {
+			end = len(lines)
+		}
+
+		if n := len(hs); n > 0 && start <= hs[n-1].end {
+			start = hs[n-1].start
+			hs = hs[:n-1]
+		}
+
+		hs = append(hs, newHunk(lines, start, end))
+		i = end - 1
+	}
+
+	return
+}
+
+func newHunk(lines []Line, start, end int) hunk {
+	h := hunk{start: start, end: end, fromLine: 1, toLine: 1}
+
+	for _, l := range lines[:start] {
+		if l.Op != '+' {
+			h.fromLine++
+		}
+		if l.Op != '-' {
+			h.toLine++
+		}
+	}
+
+	for _, l := range lines[start:end] {
+		if l.Op != '+' {
+			h.fromCount++
+		}
+		if l.Op != '-' {
+			h.toCount++
+		}
+	}
+
+	// an empty side starts at the line before, like the diff tool does.
+	if h.fromCount == 0 {
+		h.fromLine--
+	}
+	if h.toCount == 0 {
+		h.toLine--
+	}
+
+	return h
+}
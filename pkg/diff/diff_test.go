@@ -0,0 +1,100 @@
+package diff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValues(t *testing.T) {
+	type connector struct {
+		Name   string            `json:"name"`
+		Config map[string]string `json:"config"`
+		Tags   []string          `json:"tags"`
+	}
+
+	deployed := connector{Name: "sink", Config: map[string]string{"tasks.max": "1", "topics": "orders"}, Tags: []string{"a", "b"}}
+	desired := map[string]interface{}{
+		"name":   "sink",
+		"config": map[string]interface{}{"tasks.max": "2", "file": "/tmp/out"},
+		"tags":   []string{"a"},
+	}
+
+	changes, err := Values(deployed, desired)
+	assert.NoError(t, err)
+	assert.Equal(t, []Change{
+		{Path: "config.file", Op: Added, To: "/tmp/out"},
+		{Path: "config.tasks.max", Op: Changed, From: "1", To: "2"},
+		{Path: "config.topics", Op: Removed, From: "orders"},
+		{Path: "tags[1]", Op: Removed, From: "b"},
+	}, changes)
+
+	changes, err = Values(deployed, deployed)
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, "deployed", "desired", deployed, desired, Options{}))
+	assert.Equal(t, `+ config.file: "/tmp/out"
+~ config.tasks.max: "1" => "2"
+- config.topics: "orders"
+- tags[1]: "b"
+`, buf.String())
+}
+
+func TestWriteUnified(t *testing.T) {
+	from := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n"
+	to := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\n"
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteUnified(&buf, "deployed", "desired", from, to, Options{Context: 1}))
+	assert.Equal(t, `--- deployed
++++ desired
+@@ -1,3 +1,3 @@
+ a
+-b
++B
+ c
+@@ -10,1 +10,2 @@
+ j
++k
+`, buf.String())
+
+	buf.Reset()
+	assert.NoError(t, WriteUnified(&buf, "deployed", "desired", from, from, Options{Context: 3}))
+	assert.Empty(t, buf.String())
+
+	buf.Reset()
+	assert.NoError(t, WriteUnified(&buf, "deployed", "desired", "", "a\n", Options{Context: 3}))
+	assert.Equal(t, "--- deployed\n+++ desired\n@@ -0,0 +1,1 @@\n+a\n", buf.String())
+}
+
+func TestWriteUnifiedColor(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, "deployed", "desired", map[string]int{"runners": 1}, map[string]int{"runners": 2}, Options{Unified: true, Color: true, Context: 3}))
+	assert.Contains(t, buf.String(), red("-runners: 1"))
+	assert.Contains(t, buf.String(), green("+runners: 2"))
+}
+
+func TestMerge(t *testing.T) {
+	lastApplied := []string{"file", "tasks.max", "topics"}
+	live := map[string]interface{}{"tasks.max": "1", "topics": "orders", "file": "/tmp/out", "errors.tolerance": "all"}
+	desired := map[string]interface{}{"tasks.max": "2", "topics": "orders", "batch.size": "100"}
+
+	assert.Equal(t, map[string]interface{}{
+		"tasks.max":        "2",
+		"topics":           "orders",
+		"batch.size":       "100",
+		"errors.tolerance": "all",
+	}, Merge(lastApplied, live, desired))
+
+	// without a last applied manifest nothing is removed.
+	assert.Equal(t, map[string]interface{}{
+		"tasks.max":        "2",
+		"topics":           "orders",
+		"batch.size":       "100",
+		"file":             "/tmp/out",
+		"errors.tolerance": "all",
+	}, Merge(nil, live, desired))
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/lensesio/bite"
 	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/printer"
 	"github.com/spf13/cobra"
 )
 
@@ -26,7 +27,7 @@ func IndexesCommand() *cobra.Command {
 				return fmt.Errorf("Failed to retrieve indexes. Error: [%s]", err.Error())
 			}
 
-			return bite.PrintObject(cmd, indexes)
+			return printer.PrintObject(cmd, indexes)
 		},
 	}
 
@@ -34,6 +35,7 @@ func IndexesCommand() *cobra.Command {
 	cmd.Flags().BoolVar(&includeSystemIndexes, "include-system-indexes", false, "Show system indexes")
 
 	bite.CanPrintJSON(cmd)
+	printer.CanSelect(cmd)
 	return cmd
 }
 
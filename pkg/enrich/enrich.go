@@ -0,0 +1,241 @@
+// Package enrich joins the records of a stream with a static lookup table of a local CSV or JSON file,
+// the values of the records get the fields of the row whose column matches their key or field,
+// for the reference data which is not in Kafka.
+package enrich
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lensesio/lenses-go/pkg/transform"
+	"github.com/spf13/pflag"
+)
+
+// ByKey is the `Options#On` field of the whole key of the records.
+const ByKey = "key"
+
+// Options are the enrich flags of the commands which print records.
+type Options struct {
+	// From is the lookup file, a CSV with a header, a JSON array of objects or JSON lines, by its extension.
+	From string
+	// On is the join, "<field>=<column>", the field is "key" or the dot separated path of a field of the value,
+	// `transform.KeyPrefix` for a field of the key.
+	On string
+}
+
+// AddFlags registers the "--enrich-from" and "--on" flags.
+func (opts *Options) AddFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&opts.From, "enrich-from", "", "Add the fields of the matching row of a local .csv, .json or .jsonl lookup file to the values of the records")
+	flags.StringVar(&opts.On, "on", "", "The join of --enrich-from, <field>=<column>, the field is key, a field of the value or _key.<field>, i.e key=id or customer.id=id")
+}
+
+// Enricher returns the enricher of the options, nil if "From" is empty.
+func (opts Options) Enricher() (*Enricher, error) {
+	if opts.From == "" {
+		return nil, nil
+	}
+
+	idx := strings.IndexByte(opts.On, '=')
+	if idx <= 0 || idx == len(opts.On)-1 {
+		return nil, fmt.Errorf("invalid join [%s], expected <field>=<column>, i.e key=id", opts.On)
+	}
+
+	return Load(opts.From, opts.On[:idx], opts.On[idx+1:])
+}
+
+// Enricher adds the fields of the rows of its lookup table to the values of the records.
+type Enricher struct {
+	field  string
+	column string
+	rows   map[string]map[string]interface{}
+}
+
+// Load reads the lookup file of the "path", see `Options#From`, and returns its `Enricher`
+// which joins the "field" of the records with the "column" of the rows.
+func Load(path, field, column string) (*Enricher, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".csv" && ext != ".json" && ext != ".jsonl" && ext != ".ndjson" {
+		return nil, fmt.Errorf("unknown lookup file [%s], expected a .csv, .json or .jsonl file", path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var rows []map[string]interface{}
+	switch ext {
+	case ".csv":
+		rows, err = readCSV(f)
+	case ".json":
+		dec := json.NewDecoder(f)
+		dec.UseNumber()
+		err = dec.Decode(&rows)
+	default:
+		rows, err = readJSONLines(f)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the lookup file [%s]: [%v]", path, err)
+	}
+
+	e := &Enricher{field: field, column: column, rows: make(map[string]map[string]interface{}, len(rows))}
+	for _, row := range rows {
+		v, ok := row[column]
+		if !ok {
+			continue
+		}
+
+		// the first row of a value wins.
+		if id := lookupKey(v); id != "" {
+			if _, exists := e.rows[id]; !exists {
+				e.rows[id] = row
+			}
+		}
+	}
+
+	if len(e.rows) == 0 {
+		return nil, fmt.Errorf("the lookup file [%s] has no rows with the column [%s]", path, column)
+	}
+
+	return e, nil
+}
+
+func readCSV(r io.Reader) ([]map[string]interface{}, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil || len(records) == 0 {
+		return nil, err
+	}
+
+	header := records[0]
+	rows := make([]map[string]interface{}, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(map[string]interface{}, len(header))
+		for i, name := range header {
+			if i < len(record) {
+				row[name] = record[i]
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func readJSONLines(r io.Reader) ([]map[string]interface{}, error) {
+	var rows []map[string]interface{}
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 16<<20)
+	for line := 1; sc.Scan(); line++ {
+		b := bytes.TrimSpace(sc.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+
+		var row map[string]interface{}
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+		if err := dec.Decode(&row); err != nil {
+			return nil, fmt.Errorf("line [%d]: [%v]", line, err)
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, sc.Err()
+}
+
+// lookupKey returns the text of a value of the join, a string as it is and the rest as their JSON.
+func lookupKey(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+
+	if v == nil {
+		return ""
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+
+	return string(b)
+}
+
+// Apply returns the "value" with the fields of the matching row added, except the column of the join,
+// the fields of the value are kept. The values which are not JSON objects or have no matching row are returned as they are.
+func (e *Enricher) Apply(key, value json.RawMessage) (json.RawMessage, error) {
+	row, ok := e.rows[e.recordKey(key, value)]
+	if !ok {
+		return value, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(value, &fields) != nil || fields == nil {
+		return value, nil
+	}
+
+	for name, v := range row {
+		if name == e.column {
+			continue
+		}
+
+		if _, exists := fields[name]; exists {
+			continue
+		}
+
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		fields[name] = b
+	}
+
+	return json.Marshal(fields)
+}
+
+// recordKey returns the text of the field of the join of a record, empty if it has not the field.
+func (e *Enricher) recordKey(key, value json.RawMessage) string {
+	data, path := value, e.field
+	switch {
+	case path == ByKey:
+		data, path = key, ""
+	case strings.HasPrefix(path, transform.KeyPrefix):
+		data, path = key, strings.TrimPrefix(path, transform.KeyPrefix)
+	}
+
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if dec.Decode(&v) != nil {
+		// a key of the STRING format.
+		if path == "" {
+			return string(data)
+		}
+		return ""
+	}
+
+	if path != "" {
+		for _, name := range strings.Split(path, ".") {
+			fields, ok := v.(map[string]interface{})
+			if !ok {
+				return ""
+			}
+
+			if v, ok = fields[name]; !ok {
+				return ""
+			}
+		}
+	}
+
+	return lookupKey(v)
+}
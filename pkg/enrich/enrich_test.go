@@ -0,0 +1,82 @@
+package enrich
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeLookup(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestEnricher(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lenses-cli-enrich")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	csvPath := writeLookup(t, dir, "merchants.csv", "id,name,country\nm1,Acme,GR\nm2,Globex,UK\nm1,Duplicate,US\n")
+
+	e, err := Options{From: csvPath, On: "merchant.id=id"}.Enricher()
+	require.NoError(t, err)
+
+	value, err := e.Apply(nil, json.RawMessage(`{"merchant":{"id":"m1"},"amount":10,"country":"FR"}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"merchant":{"id":"m1"},"amount":10,"country":"FR","name":"Acme"}`, string(value), "the fields of the value are kept")
+
+	value, err = e.Apply(nil, json.RawMessage(`{"merchant":{"id":"m9"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, `{"merchant":{"id":"m9"}}`, string(value), "no matching row")
+
+	// by the key, a string one or of the STRING format, from JSON lines.
+	jsonlPath := writeLookup(t, dir, "customers.jsonl", `{"id":"c1","tier":"gold","limits":{"daily":100}}`+"\n\n"+`{"id":2,"tier":"silver"}`+"\n")
+	e, err = Options{From: jsonlPath, On: "key=id"}.Enricher()
+	require.NoError(t, err)
+
+	value, err = e.Apply(json.RawMessage(`"c1"`), json.RawMessage(`{"amount":1}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"amount":1,"tier":"gold","limits":{"daily":100}}`, string(value))
+
+	value, err = e.Apply(json.RawMessage(`c1`), json.RawMessage(`{"amount":1}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"amount":1,"tier":"gold","limits":{"daily":100}}`, string(value))
+
+	value, err = e.Apply(json.RawMessage(`2`), json.RawMessage(`{"amount":2}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"amount":2,"tier":"silver"}`, string(value))
+
+	// by a field of the key, from a JSON array.
+	jsonPath := writeLookup(t, dir, "regions.json", `[{"code":"eu","name":"Europe"}]`)
+	e, err = Options{From: jsonPath, On: "_key.region=code"}.Enricher()
+	require.NoError(t, err)
+
+	value, err = e.Apply(json.RawMessage(`{"region":"eu"}`), json.RawMessage(`{}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"Europe"}`, string(value))
+}
+
+func TestOptionsEnricher(t *testing.T) {
+	e, err := Options{}.Enricher()
+	assert.NoError(t, err)
+	assert.Nil(t, e)
+
+	_, err = Options{From: "merchants.csv", On: "id"}.Enricher()
+	assert.EqualError(t, err, "invalid join [id], expected <field>=<column>, i.e key=id")
+
+	_, err = Options{From: "merchants.xml", On: "key=id"}.Enricher()
+	assert.EqualError(t, err, "unknown lookup file [merchants.xml], expected a .csv, .json or .jsonl file")
+
+	dir, err := ioutil.TempDir("", "lenses-cli-enrich")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = Options{From: writeLookup(t, dir, "empty.csv", "name\nAcme\n"), On: "key=id"}.Enricher()
+	assert.Contains(t, err.Error(), "has no rows with the column [id]")
+}
@@ -0,0 +1,188 @@
+// Package exitcode classifies the errors of the CLI commands into exit codes,
+// so scripts and CI pipelines can branch on the type of a failure.
+package exitcode
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+)
+
+// The exit codes of the CLI.
+const (
+	OK           = 0
+	General      = 1
+	NotFound     = 2
+	Forbidden    = 3
+	Validation   = 4
+	Connectivity = 5
+	Unsupported  = 6
+	Timeout      = 7
+)
+
+// Kinds are the names of the exit codes, as printed by the JSON error format.
+var Kinds = map[int]string{
+	General:      "general",
+	NotFound:     "not_found",
+	Forbidden:    "forbidden",
+	Validation:   "validation",
+	Connectivity: "connectivity",
+	Unsupported:  "unsupported",
+	Timeout:      "timeout",
+}
+
+// The error formats of `Print`.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// codedError is an error with an explicit exit code, see `WithCode`.
+type codedError struct {
+	code int
+	err  error
+}
+
+func (e codedError) Error() string { return e.err.Error() }
+func (e codedError) Unwrap() error { return e.err }
+
+// WithCode returns the "err" with the exit "code", for the errors that can not be classified by their type,
+// i.e `WithCode(Forbidden, err)`.
+func WithCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return codedError{code: code, err: err}
+}
+
+// Of returns the exit code of the "err", by its type, or its status code if it's an `api.ResourceError`,
+// and, as a fallback, by its message.
+func Of(err error) int {
+	if err == nil {
+		return OK
+	}
+
+	var coded codedError
+	if errors.As(err, &coded) {
+		return coded.code
+	}
+
+	var resErr api.ResourceError
+	if errors.As(err, &resErr) {
+		return ofStatusCode(resErr.StatusCode)
+	}
+
+	if errors.Is(err, api.ErrCredentialsMissing) || errors.Is(err, api.ErrAuthFailed) {
+		return Forbidden
+	}
+
+	if errors.Is(err, api.ErrInvalidSQL) {
+		return Validation
+	}
+
+	var unsupported api.UnsupportedError
+	if errors.As(err, &unsupported) {
+		return Unsupported
+	}
+
+	var (
+		netErr net.Error
+		urlErr *url.Error
+	)
+	if errors.As(err, &netErr) || errors.As(err, &urlErr) {
+		return Connectivity
+	}
+
+	return ofMessage(err.Error())
+}
+
+func ofStatusCode(statusCode int) int {
+	switch statusCode {
+	case http.StatusNotFound:
+		return NotFound
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return Forbidden
+	case http.StatusBadRequest, http.StatusConflict, http.StatusUnprocessableEntity:
+		return Validation
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return Connectivity
+	default:
+		return General
+	}
+}
+
+// messages are the fallback classification of the errors which lost their type, i.e by `fmt.Errorf("... [%s]", err.Error())`.
+var messages = []struct {
+	code     int
+	contains []string
+}{
+	{Connectivity, []string{"connection refused", "no such host", "i/o timeout", "network is unreachable", "connection reset", "tls: "}},
+	{Forbidden, []string{api.ErrCredentialsMissing.Error(), api.ErrAuthFailed.Error(), "forbidden", "unauthorized", "not allowed", "is protected"}},
+	{NotFound, []string{"not found", "does not exist", "unknown context"}},
+	{Unsupported, []string{"requires lenses >="}},
+	{Validation, []string{"required flag", "unknown flag", "unknown command", "unknown shorthand flag", "invalid argument", "is required", "are required", "invalid", "does not match"}},
+}
+
+func ofMessage(msg string) int {
+	msg = strings.ToLower(msg)
+	for _, m := range messages {
+		for _, s := range m.contains {
+			if strings.Contains(msg, s) {
+				return m.code
+			}
+		}
+	}
+
+	return General
+}
+
+// Error is the JSON error format of `Print`.
+type Error struct {
+	Code       int    `json:"code"`
+	Kind       string `json:"kind"`
+	Message    string `json:"message"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	RequestID  string `json:"requestId,omitempty"`
+}
+
+// New returns the `Error` of the "err".
+func New(err error) Error {
+	code := Of(err)
+	e := Error{Code: code, Kind: Kinds[code], Message: err.Error()}
+
+	var resErr api.ResourceError
+	if errors.As(err, &resErr) {
+		e.StatusCode = resErr.StatusCode
+		e.RequestID = resErr.RequestID
+	}
+
+	return e
+}
+
+// Print writes the "err" to "w" in the "format", `FormatText` or `FormatJSON`, and returns its exit code.
+// The request id of a failed API call is printed too, so it can be looked up in the Lenses server logs.
+func Print(w io.Writer, format string, err error) int {
+	e := New(err)
+
+	if strings.EqualFold(format, FormatJSON) {
+		b, _ := json.Marshal(e)
+		fmt.Fprintln(w, string(b))
+		return e.Code
+	}
+
+	if e.RequestID != "" {
+		fmt.Fprintf(w, "%v (request id: %s)\n", err, e.RequestID)
+		return e.Code
+	}
+
+	fmt.Fprintln(w, err)
+	return e.Code
+}
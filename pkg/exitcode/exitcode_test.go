@@ -0,0 +1,67 @@
+package exitcode
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"nil", nil, OK},
+		{"not found status", api.NewResourceError(http.StatusNotFound, "/api/topics/a", http.MethodGet, "topic not there"), NotFound},
+		{"forbidden status", fmt.Errorf("wrapped: %w", api.NewResourceError(http.StatusForbidden, "/", http.MethodGet, "")), Forbidden},
+		{"bad request status", api.NewResourceError(http.StatusBadRequest, "/", http.MethodPost, "bad"), Validation},
+		{"unavailable status", api.NewResourceError(http.StatusServiceUnavailable, "/", http.MethodGet, ""), Connectivity},
+		{"internal status", api.NewResourceError(http.StatusInternalServerError, "/", http.MethodGet, ""), General},
+		{"credentials", api.ErrCredentialsMissing, Forbidden},
+		{"auth failed", fmt.Errorf("client: %w: [bad]", api.ErrAuthFailed), Forbidden},
+		{"invalid sql", api.InvalidSQLError{Line: 1, Col: 2, Message: "oops"}, Validation},
+		{"net", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, Connectivity},
+		{"unsupported", fmt.Errorf("connections: %w", api.UnsupportedError{Feature: "connections", MinVersion: "4.1", Version: "4.0.2"}), Unsupported},
+		{"with code", WithCode(Forbidden, errors.New("protected")), Forbidden},
+		{"lost type, connectivity", errors.New("failed: [dial tcp: connection refused]"), Connectivity},
+		{"lost type, not found", errors.New("Failed to delete user [a]. [user does not exist]"), NotFound},
+		{"required flag", errors.New(`required flag(s) "name" not set`), Validation},
+		{"other", errors.New("boom"), General},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.code, Of(tt.err), tt.name)
+	}
+}
+
+func TestPrint(t *testing.T) {
+	var buf bytes.Buffer
+	err := api.NewResourceError(http.StatusNotFound, "/api/topics/a", http.MethodGet, "Topic a not found")
+
+	assert.Equal(t, NotFound, Print(&buf, FormatJSON, err))
+	assert.JSONEq(t, `{"code":2,"kind":"not_found","message":"topic a not found","statusCode":404}`, buf.String())
+
+	buf.Reset()
+	assert.Equal(t, General, Print(&buf, FormatText, errors.New("boom")))
+	assert.Equal(t, "boom\n", buf.String())
+}
+
+func TestPrintRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	err := api.NewResourceError(http.StatusInternalServerError, "/api/topics", http.MethodGet, "Boom")
+	err.RequestID = "6f1d2c3a-0b4e-4c5d-9e8f-7a6b5c4d3e2f"
+
+	assert.Equal(t, General, Print(&buf, FormatJSON, fmt.Errorf("wrapped: %w", err)))
+	assert.JSONEq(t, `{"code":1,"kind":"general","message":"wrapped: boom","statusCode":500,"requestId":"6f1d2c3a-0b4e-4c5d-9e8f-7a6b5c4d3e2f"}`, buf.String())
+
+	buf.Reset()
+	Print(&buf, FormatText, err)
+	assert.Equal(t, "boom (request id: 6f1d2c3a-0b4e-4c5d-9e8f-7a6b5c4d3e2f)\n", buf.String())
+}
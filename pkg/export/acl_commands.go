@@ -7,9 +7,9 @@ import (
 	"github.com/kataras/golog"
 	"github.com/lensesio/bite"
 	"github.com/lensesio/lenses-go/pkg"
-	"github.com/lensesio/lenses-go/pkg/api"
 	config "github.com/lensesio/lenses-go/pkg/configs"
 	"github.com/lensesio/lenses-go/pkg/utils"
+	v1 "github.com/lensesio/lenses-go/v1"
 	"github.com/spf13/cobra"
 )
 
@@ -40,7 +40,7 @@ func NewExportAclsCommand() *cobra.Command {
 	return cmd
 }
 
-func writeACLs(cmd *cobra.Command, client *api.Client) error {
+func writeACLs(cmd *cobra.Command, client v1.Client) error {
 
 	output := strings.ToUpper(bite.GetOutPutFlag(cmd))
 	fileName := fmt.Sprintf("acls.%s", strings.ToLower(output))
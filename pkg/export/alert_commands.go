@@ -11,6 +11,7 @@ import (
 	"github.com/lensesio/lenses-go/pkg/api"
 	config "github.com/lensesio/lenses-go/pkg/configs"
 	"github.com/lensesio/lenses-go/pkg/utils"
+	v1 "github.com/lensesio/lenses-go/v1"
 	"github.com/spf13/cobra"
 )
 
@@ -39,7 +40,7 @@ func NewExportAlertsCommand() *cobra.Command {
 	return cmd
 }
 
-func writeAlertSetting(cmd *cobra.Command, client *api.Client) error {
+func writeAlertSetting(cmd *cobra.Command, client v1.Client) error {
 
 	producerSettings, err := getProducerAlertSettings(client)
 	if err != nil {
@@ -93,7 +94,7 @@ func writeAlertSettingsAsRequest(cmd *cobra.Command, settings alert.SettingCondi
 	return nil
 }
 
-func getAlertSettings(cmd *cobra.Command, client *api.Client, topics []string) (alert.SettingConditionPayloads, error) {
+func getAlertSettings(cmd *cobra.Command, client v1.Client, topics []string) (alert.SettingConditionPayloads, error) {
 	var alertSettings alert.SettingConditionPayloads
 	var conditions []string
 
@@ -134,7 +135,7 @@ func getAlertSettings(cmd *cobra.Command, client *api.Client, topics []string) (
 	return alert.SettingConditionPayloads{AlertID: 2000, Conditions: conditions}, nil
 }
 
-func getConsumerAlertSettings(client *api.Client) (api.ConsumerAlertSettings, error) {
+func getConsumerAlertSettings(client v1.Client) (api.ConsumerAlertSettings, error) {
 	var consumerAlertSettings api.ConsumerAlertSettings
 
 	settings, err := client.GetAlertSetting(2000)
@@ -163,7 +164,7 @@ func getConsumerAlertSettings(client *api.Client) (api.ConsumerAlertSettings, er
 	return consumerAlertSettings, nil
 }
 
-func getProducerAlertSettings(client *api.Client) (api.ProducerAlertSettings, error) {
+func getProducerAlertSettings(client v1.Client) (api.ProducerAlertSettings, error) {
 	var producerAlertSettings api.ProducerAlertSettings
 
 	settings, err := client.GetAlertSetting(5000)
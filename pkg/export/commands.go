@@ -8,7 +8,9 @@ import (
 	"github.com/lensesio/bite"
 	"github.com/lensesio/lenses-go/pkg"
 	"github.com/lensesio/lenses-go/pkg/api"
+	"github.com/lensesio/lenses-go/pkg/bulk"
 	"github.com/lensesio/lenses-go/pkg/utils"
+	v1 "github.com/lensesio/lenses-go/v1"
 
 	"github.com/kataras/golog"
 	"github.com/spf13/cobra"
@@ -64,12 +66,15 @@ export connections --dir my-dir
 export connections --dir my-dir --connection-id 1
 export groups --dir groups
 export topic-settings --dir topic-settings
-export serviceaccounts --dir serviceaccounts`,
+export serviceaccounts --dir serviceaccounts
+export schemas --dir my-dir --concurrency 8`,
 		SilenceErrors:    true,
 		TraverseChildren: true,
 	}
 
 	cmd.MarkPersistentFlagRequired("dir")
+	bulk.AddConcurrencyFlag(cmd)
+
 	cmd.AddCommand(NewExportAclsCommand())
 	cmd.AddCommand(NewExportAlertsCommand())
 	cmd.AddCommand(NewExportConnectorsCommand())
@@ -88,7 +93,7 @@ export serviceaccounts --dir serviceaccounts`,
 	return cmd
 }
 
-func setExecutionMode(client *api.Client) error {
+func setExecutionMode(client v1.Client) error {
 	execMode, err := getExecutionMode(client)
 
 	if err != nil {
@@ -99,7 +104,7 @@ func setExecutionMode(client *api.Client) error {
 	return nil
 }
 
-func getExecutionMode(client *api.Client) (api.ExecutionMode, error) {
+func getExecutionMode(client v1.Client) (api.ExecutionMode, error) {
 	mode, err := client.GetExecutionMode()
 	if err != nil {
 		return mode, err
@@ -108,7 +113,7 @@ func getExecutionMode(client *api.Client) (api.ExecutionMode, error) {
 	return mode, nil
 }
 
-func getAttachedTopics(client *api.Client, id string) ([]api.CreateTopicPayload, error) {
+func getAttachedTopics(client v1.Client, id string) ([]api.CreateTopicPayload, error) {
 	var topics []api.CreateTopicPayload
 
 	if dependents {
@@ -172,7 +177,7 @@ func createBranch(cmd *cobra.Command, branchName string) error {
 	return nil
 }
 
-func handleDependents(cmd *cobra.Command, client *api.Client, id string) error {
+func handleDependents(cmd *cobra.Command, client v1.Client, id string) error {
 
 	//get topics
 	topics, err := getAttachedTopics(client, id)
@@ -0,0 +1,43 @@
+package export
+
+import (
+	"testing"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	v1 "github.com/lensesio/lenses-go/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeAPI implements the calls of the attached topics export, any other call panics.
+type fakeAPI struct {
+	v1.Client
+	extracts []api.TopicExtract
+	topics   map[string]api.Topic
+}
+
+func (f fakeAPI) GetTopicExtract(id string) ([]api.TopicExtract, error) { return f.extracts, nil }
+func (f fakeAPI) GetTopic(name string) (api.Topic, error)               { return f.topics[name], nil }
+
+func TestGetAttachedTopics(t *testing.T) {
+	client := fakeAPI{
+		extracts: []api.TopicExtract{{Parents: []string{"TOPIC-payments"}, Descendants: []string{"PROCESSOR-p1", "TOPIC-payments_eu"}}},
+		topics: map[string]api.Topic{
+			"payments":    {TopicName: "payments", Partitions: 3, Replication: 1},
+			"payments_eu": {TopicName: "payments_eu", Partitions: 1, Replication: 1},
+		},
+	}
+
+	dependents = true
+	defer func() { dependents = false }()
+
+	topics, err := getAttachedTopics(client, "p1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if assert.Len(t, topics, 2) {
+		assert.Equal(t, "payments_eu", topics[0].TopicName)
+		assert.Equal(t, "payments", topics[1].TopicName)
+		assert.Equal(t, 3, topics[1].Partitions)
+	}
+}
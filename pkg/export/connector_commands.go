@@ -7,9 +7,10 @@ import (
 	"github.com/kataras/golog"
 	"github.com/lensesio/bite"
 	"github.com/lensesio/lenses-go/pkg"
-	"github.com/lensesio/lenses-go/pkg/api"
+	"github.com/lensesio/lenses-go/pkg/bulk"
 	config "github.com/lensesio/lenses-go/pkg/configs"
 	"github.com/lensesio/lenses-go/pkg/utils"
+	v1 "github.com/lensesio/lenses-go/v1"
 	"github.com/spf13/cobra"
 )
 
@@ -48,13 +49,14 @@ func NewExportConnectorsCommand() *cobra.Command {
 // writeConnectors writes the connectors to files as yaml
 // If a clusterName is provided the connectors are filtered by clusterName
 // If a name is provided the connectors are filtered by connector name
-func writeConnectors(cmd *cobra.Command, client *api.Client, clusterName string, name string) error {
+func writeConnectors(cmd *cobra.Command, client v1.Client, clusterName string, name string) error {
 	clusters, err := client.GetConnectClusters()
 
 	if err != nil {
 		return err
 	}
 
+	var tasks []bulk.Task
 	for _, cluster := range clusters {
 
 		connectorNames, err := client.GetConnectors(cluster)
@@ -77,33 +79,44 @@ func writeConnectors(cmd *cobra.Command, client *api.Client, clusterName string,
 				continue
 			}
 
-			connector, err := client.GetConnector(cluster, connectorName)
-			if err != nil {
-				return err
-			}
+			cluster, connectorName := cluster, connectorName
+			tasks = append(tasks, bulk.Task{
+				Name: fmt.Sprintf("connector [%s:%s]", cluster, connectorName),
+				Run:  func() error { return writeConnector(cmd, client, cluster, connectorName) },
+			})
+		}
+	}
 
-			if connector.Config[connectorClassKey] == sqlConnectorClass {
-				continue
-			}
+	return bulk.RunAndReport(cmd, tasks)
+}
+
+func writeConnector(cmd *cobra.Command, client v1.Client, cluster, connectorName string) error {
+	connector, err := client.GetConnector(cluster, connectorName)
+	if err != nil {
+		return err
+	}
 
-			request := connector.ConnectorAsRequest()
+	if connector.Config[connectorClassKey] == sqlConnectorClass {
+		return nil
+	}
 
-			output := strings.ToUpper(bite.GetOutPutFlag(cmd))
-			fileName := fmt.Sprintf("connector-%s-%s.%s", strings.ToLower(cluster), strings.ToLower(connectorName), strings.ToLower(output))
+	request := connector.ConnectorAsRequest()
 
-			if output == "TABLE" {
-				output = "YAML"
-			}
+	output := strings.ToUpper(bite.GetOutPutFlag(cmd))
+	fileName := fmt.Sprintf("connector-%s-%s.%s", strings.ToLower(cluster), strings.ToLower(connectorName), strings.ToLower(output))
 
-			golog.Debugf("Exporting connector [%s.%s] to [%s%s]", cluster, connectorName, landscapeDir, fileName)
-			if err := utils.WriteFile(landscapeDir, pkg.ConnectorsPath, fileName, output, request); err != nil {
-				return err
-			}
+	if output == "TABLE" {
+		output = "YAML"
+	}
 
-			if dependents {
-				handleDependents(cmd, client, fmt.Sprintf("%s:%s", connector.ClusterName, connector.Name))
-			}
-		}
+	golog.Debugf("Exporting connector [%s.%s] to [%s%s]", cluster, connectorName, landscapeDir, fileName)
+	if err := utils.WriteFile(landscapeDir, pkg.ConnectorsPath, fileName, output, request); err != nil {
+		return err
 	}
+
+	if dependents {
+		handleDependents(cmd, client, fmt.Sprintf("%s:%s", connector.ClusterName, connector.Name))
+	}
+
 	return nil
 }
@@ -7,9 +7,9 @@ import (
 	"github.com/kataras/golog"
 	"github.com/lensesio/bite"
 	"github.com/lensesio/lenses-go/pkg"
-	"github.com/lensesio/lenses-go/pkg/api"
 	config "github.com/lensesio/lenses-go/pkg/configs"
 	"github.com/lensesio/lenses-go/pkg/utils"
+	v1 "github.com/lensesio/lenses-go/v1"
 	"github.com/spf13/cobra"
 )
 
@@ -45,7 +45,7 @@ func NewExportPoliciesCommand() *cobra.Command {
 	return cmd
 }
 
-func writePolicies(cmd *cobra.Command, client *api.Client, name string, ID string) error {
+func writePolicies(cmd *cobra.Command, client v1.Client, name string, ID string) error {
 	golog.Infof("Writing policies to [%s]", landscapeDir)
 	output := strings.ToUpper(bite.GetOutPutFlag(cmd))
 
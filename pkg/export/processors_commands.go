@@ -10,6 +10,7 @@ import (
 	"github.com/lensesio/lenses-go/pkg/api"
 	config "github.com/lensesio/lenses-go/pkg/configs"
 	"github.com/lensesio/lenses-go/pkg/utils"
+	v1 "github.com/lensesio/lenses-go/v1"
 	"github.com/spf13/cobra"
 )
 
@@ -49,7 +50,7 @@ func NewExportProcessorsCommand() *cobra.Command {
 	return cmd
 }
 
-func writeProcessors(cmd *cobra.Command, client *api.Client, id, cluster, namespace, name string) error {
+func writeProcessors(cmd *cobra.Command, client v1.Client, id, cluster, namespace, name string) error {
 
 	if mode == api.ExecutionModeInProcess {
 		cluster = "IN-PROC"
@@ -10,6 +10,7 @@ import (
 	"github.com/lensesio/lenses-go/pkg/api"
 	config "github.com/lensesio/lenses-go/pkg/configs"
 	"github.com/lensesio/lenses-go/pkg/utils"
+	v1 "github.com/lensesio/lenses-go/v1"
 	"github.com/spf13/cobra"
 )
 
@@ -40,7 +41,7 @@ func NewExportQuotasCommand() *cobra.Command {
 	return cmd
 }
 
-func writeQuotas(cmd *cobra.Command, client *api.Client) error {
+func writeQuotas(cmd *cobra.Command, client v1.Client) error {
 
 	quotas, err := client.GetQuotas()
 
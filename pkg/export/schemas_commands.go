@@ -9,9 +9,10 @@ import (
 	"github.com/kataras/golog"
 	"github.com/lensesio/bite"
 	"github.com/lensesio/lenses-go/pkg"
-	"github.com/lensesio/lenses-go/pkg/api"
+	"github.com/lensesio/lenses-go/pkg/bulk"
 	config "github.com/lensesio/lenses-go/pkg/configs"
 	"github.com/lensesio/lenses-go/pkg/utils"
+	v1 "github.com/lensesio/lenses-go/v1"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
@@ -52,7 +53,7 @@ func NewExportSchemasCmd() *cobra.Command {
 }
 
 // WriteSchemas to a file
-func WriteSchemas(cmd *cobra.Command, client *api.Client, name string) error {
+func WriteSchemas(cmd *cobra.Command, client v1.Client, name string) error {
 	output := strings.ToUpper(bite.GetOutPutFlag(cmd))
 	if name != "" {
 		return writeSchema(output, client, name)
@@ -62,15 +63,19 @@ func WriteSchemas(cmd *cobra.Command, client *api.Client, name string) error {
 	if err != nil {
 		return err
 	}
-	for _, sub := range subjects {
-		if err := writeSchema(output, client, sub.Name); err != nil {
-			return err
+	tasks := make([]bulk.Task, len(subjects))
+	for i, sub := range subjects {
+		name := sub.Name
+		tasks[i] = bulk.Task{
+			Name: fmt.Sprintf("schema [%s]", name),
+			Run:  func() error { return writeSchema(output, client, name) },
 		}
 	}
-	return nil
+
+	return bulk.RunAndReport(cmd, tasks)
 }
 
-func writeSchema(outputFormat string, client *api.Client, name string) error {
+func writeSchema(outputFormat string, client v1.Client, name string) error {
 
 	schema, err := client.GetSchema(name)
 	if err != nil {
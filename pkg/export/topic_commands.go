@@ -10,6 +10,7 @@ import (
 	"github.com/lensesio/lenses-go/pkg/api"
 	config "github.com/lensesio/lenses-go/pkg/configs"
 	"github.com/lensesio/lenses-go/pkg/utils"
+	v1 "github.com/lensesio/lenses-go/v1"
 	"github.com/spf13/cobra"
 )
 
@@ -42,7 +43,7 @@ func NewExportTopicsCommand() *cobra.Command {
 	return cmd
 }
 
-func writeTopics(cmd *cobra.Command, client *api.Client, topicName string) error {
+func writeTopics(cmd *cobra.Command, client v1.Client, topicName string) error {
 	var requests []api.CreateTopicPayload
 
 	raw, err := client.GetTopics()
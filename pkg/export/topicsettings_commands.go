@@ -8,9 +8,9 @@ import (
 	"github.com/kataras/golog"
 	"github.com/lensesio/bite"
 	"github.com/lensesio/lenses-go/pkg"
-	"github.com/lensesio/lenses-go/pkg/api"
 	config "github.com/lensesio/lenses-go/pkg/configs"
 	"github.com/lensesio/lenses-go/pkg/utils"
+	v1 "github.com/lensesio/lenses-go/v1"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
@@ -49,7 +49,7 @@ func NewExportTopicSettingsCmd() *cobra.Command {
 }
 
 // WriteTopicSettings to a file
-func WriteTopicSettings(cmd *cobra.Command, client *api.Client) error {
+func WriteTopicSettings(cmd *cobra.Command, client v1.Client) error {
 	golog.Infof("Writing topic-settings to [%s]", landscapeDir)
 
 	settings, err := client.GetTopicSettings()
@@ -0,0 +1,256 @@
+package healthcheck
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/lensesio/bite"
+	"github.com/lensesio/lenses-go/pkg/api"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/exitcode"
+	"github.com/lensesio/lenses-go/pkg/websocket"
+	"github.com/spf13/cobra"
+)
+
+// The checks, in the order they run. A failed check exits with the code of its kind of failure, see `ExitCodes`.
+const (
+	CheckConfig    = "config"
+	CheckREST      = "rest"
+	CheckAuth      = "auth"
+	CheckWebsocket = "websocket"
+	CheckSQL       = "sql"
+)
+
+// ExitCodes are the exit codes of the `healthcheck` command per failed check, of the `exitcode` package.
+var ExitCodes = map[string]int{
+	CheckConfig:    exitcode.Validation,
+	CheckREST:      exitcode.Connectivity,
+	CheckAuth:      exitcode.Forbidden,
+	CheckWebsocket: exitcode.Connectivity,
+	CheckSQL:       exitcode.General,
+}
+
+const (
+	statusOK      = "ok"
+	statusFailed  = "failed"
+	statusSkipped = "skipped"
+)
+
+// CheckResult is the result of a single check.
+type CheckResult struct {
+	Check    string `json:"check" header:"Check"`
+	Status   string `json:"status" header:"Status"`
+	Duration int64  `json:"durationMs" header:"Duration (ms)"`
+	Error    string `json:"error,omitempty" header:"Error"`
+}
+
+// probe runs the checks against the Lenses of the "config".
+type probe struct {
+	config  *api.ClientConfig
+	timeout time.Duration
+	sql     string
+
+	client *api.Client // set by the auth check.
+}
+
+// run runs the checks in order, the checks after a failed one are skipped.
+// It returns the name of the failed check, if any.
+func (p *probe) run() (results []CheckResult, failed string) {
+	checks := []struct {
+		name string
+		run  func() error
+	}{
+		{CheckConfig, p.checkConfig},
+		{CheckREST, p.checkREST},
+		{CheckAuth, p.checkAuth},
+		{CheckWebsocket, p.checkWebsocket},
+		{CheckSQL, p.checkSQL},
+	}
+
+	for _, check := range checks {
+		result := CheckResult{Check: check.name}
+
+		switch {
+		case failed != "":
+			result.Status = statusSkipped
+		case check.name == CheckSQL && p.sql == "":
+			result.Status = statusSkipped
+		default:
+			start := time.Now()
+			err := check.run()
+			result.Duration = time.Since(start).Milliseconds()
+			if err != nil {
+				result.Status = statusFailed
+				result.Error = err.Error()
+				failed = check.name
+			} else {
+				result.Status = statusOK
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return
+}
+
+// checkError returns the error of the "failed" check of the "results", with its exit code, nil if none failed.
+func checkError(results []CheckResult, failed string) error {
+	if failed == "" {
+		return nil
+	}
+
+	for _, r := range results {
+		if r.Check == failed {
+			return exitcode.WithCode(ExitCodes[failed], fmt.Errorf("healthcheck [%s] failed: [%s]", failed, r.Error))
+		}
+	}
+
+	return exitcode.WithCode(ExitCodes[failed], fmt.Errorf("healthcheck [%s] failed", failed))
+}
+
+func (p *probe) checkConfig() error {
+	if p.config == nil || p.config.Host == "" {
+		return errors.New("host is missing")
+	}
+
+	if !p.config.IsValid() {
+		return errors.New("token or authentication is missing")
+	}
+
+	return nil
+}
+
+// checkREST checks that the server responds, any response but a server error is fine, the request is not authenticated.
+func (p *probe) checkREST() error {
+	transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
+	if p.config.Insecure {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	client := &http.Client{Timeout: p.timeout, Transport: transport}
+	resp, err := client.Get(strings.TrimSuffix(p.config.Host, "/") + "/")
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("server responded with [%s]", resp.Status)
+	}
+
+	return nil
+}
+
+// checkAuth logs in, unless a token is used, and makes an authenticated request.
+func (p *probe) checkAuth() error {
+	client, err := api.OpenConnection(*p.config, api.UsingClient(&http.Client{Timeout: p.timeout}))
+	if err != nil {
+		return err
+	}
+
+	if _, err = client.GetUserProfile(); err != nil {
+		return err
+	}
+
+	p.client = client
+	return nil
+}
+
+func (p *probe) liveConfiguration(sql string) websocket.LiveConfiguration {
+	return websocket.LiveConfiguration{
+		Host:             p.config.Host,
+		BasePath:         p.config.BasePath,
+		Debug:            p.config.Debug,
+		HandshakeTimeout: p.timeout,
+		Message: websocket.Message{
+			Token: p.client.Config.Token,
+			SQL:   sql,
+		},
+	}
+}
+
+func (p *probe) checkWebsocket() error {
+	return websocket.Handshake(p.liveConfiguration(""))
+}
+
+// checkSQL runs the query and waits for its end.
+func (p *probe) checkSQL() error {
+	conn, err := websocket.OpenLiveConnection(p.liveConfiguration(p.sql))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	done := make(chan error, 1)
+	reporter := func(resp websocket.LiveResponse) error {
+		var errStr string
+		json.Unmarshal(resp.Data.Value, &errStr)
+		select {
+		case done <- fmt.Errorf("[%s]: [%s]", resp.Type, errStr):
+		default:
+		}
+		return nil
+	}
+
+	conn.OnError(reporter)
+	conn.OnInvalidRequest(reporter)
+	conn.OnEnd(func(websocket.LiveResponse) error {
+		select {
+		case done <- nil:
+		default:
+		}
+		return nil
+	})
+
+	select {
+	case err = <-done:
+		return err
+	case err = <-conn.Err():
+		return err
+	case <-time.After(p.timeout):
+		return fmt.Errorf("query did not complete in [%s]", p.timeout)
+	}
+}
+
+//NewHealthcheckCommand creates `healthcheck` command
+func NewHealthcheckCommand() *cobra.Command {
+	var (
+		timeout time.Duration
+		sql     string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "healthcheck",
+		Short: "Check that Lenses is reachable, the credentials are valid and live queries work",
+		Long: `Check that Lenses is reachable, the credentials are valid and live queries work.
+It exits with the code of the first failed check: 4 (validation) for the configuration, 5 (connectivity) for the REST API reachability
+and the websocket handshake, 3 (forbidden) for the authentication and 1 (general) for the query.`,
+		Example: `healthcheck
+healthcheck --sql="SELECT * FROM healthcheck_topic LIMIT 1" --check-timeout=5s --output=json`,
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p := &probe{config: config.Manager.Config.GetCurrent(), timeout: timeout, sql: sql}
+
+			results, failed := p.run()
+			if err := bite.PrintObject(cmd, results); err != nil {
+				return err
+			}
+
+			return checkError(results, failed)
+		},
+	}
+
+	cmd.Flags().DurationVar(&timeout, "check-timeout", 10*time.Second, "Timeout of each check")
+	cmd.Flags().StringVar(&sql, "sql", "", "A query to run as the last check, it should end by itself, i.e with a LIMIT")
+
+	bite.CanPrintJSON(cmd)
+
+	return cmd
+}
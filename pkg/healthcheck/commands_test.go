@@ -0,0 +1,140 @@
+package healthcheck
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/lensesio/lenses-go/pkg/api"
+	"github.com/lensesio/lenses-go/pkg/exitcode"
+	test "github.com/lensesio/lenses-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// newLensesServer starts a server which accepts the "token" and answers the queries with an "END".
+func newLensesServer(t *testing.T, token string) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/api/user/profile", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Kafka-Lenses-Token") != token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	})
+	mux.HandleFunc("/api/ws/v2/sql/execute", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+
+		var msg struct {
+			Token string `json:"token"`
+		}
+		if err = conn.ReadJSON(&msg); err != nil {
+			return
+		}
+
+		if msg.Token != token {
+			conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"ERROR","data":{"value":"unauthorized"}}`))
+		} else {
+			conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"END"}`))
+		}
+		conn.ReadMessage()
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func statuses(results []CheckResult) map[string]string {
+	m := make(map[string]string, len(results))
+	for _, r := range results {
+		m[r.Check] = r.Status
+	}
+	return m
+}
+
+func TestProbe(t *testing.T) {
+	test.SetupMasterContext()
+
+	srv := newLensesServer(t, "secret")
+	defer srv.Close()
+
+	tests := []struct {
+		name     string
+		config   *api.ClientConfig
+		sql      string
+		failed   string
+		statuses map[string]string
+	}{
+		{
+			name:   "healthy",
+			config: &api.ClientConfig{Host: srv.URL, Token: "secret"},
+			sql:    "SELECT * FROM healthcheck LIMIT 1",
+			statuses: map[string]string{
+				CheckConfig: statusOK, CheckREST: statusOK, CheckAuth: statusOK, CheckWebsocket: statusOK, CheckSQL: statusOK,
+			},
+		},
+		{
+			name:   "healthy without query",
+			config: &api.ClientConfig{Host: srv.URL, Token: "secret"},
+			statuses: map[string]string{
+				CheckConfig: statusOK, CheckREST: statusOK, CheckAuth: statusOK, CheckWebsocket: statusOK, CheckSQL: statusSkipped,
+			},
+		},
+		{
+			name:   "invalid token",
+			config: &api.ClientConfig{Host: srv.URL, Token: "invalid"},
+			sql:    "SELECT * FROM healthcheck LIMIT 1",
+			failed: CheckAuth,
+			statuses: map[string]string{
+				CheckConfig: statusOK, CheckREST: statusOK, CheckAuth: statusFailed, CheckWebsocket: statusSkipped, CheckSQL: statusSkipped,
+			},
+		},
+		{
+			name:   "unreachable",
+			config: &api.ClientConfig{Host: "http://127.0.0.1:1", Token: "secret"},
+			failed: CheckREST,
+			statuses: map[string]string{
+				CheckConfig: statusOK, CheckREST: statusFailed, CheckAuth: statusSkipped, CheckWebsocket: statusSkipped, CheckSQL: statusSkipped,
+			},
+		},
+		{
+			name:   "no host",
+			config: &api.ClientConfig{Token: "secret"},
+			failed: CheckConfig,
+			statuses: map[string]string{
+				CheckConfig: statusFailed, CheckREST: statusSkipped, CheckAuth: statusSkipped, CheckWebsocket: statusSkipped, CheckSQL: statusSkipped,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		p := &probe{config: tt.config, timeout: 5 * time.Second, sql: tt.sql}
+		results, failed := p.run()
+
+		assert.Equal(t, tt.failed, failed, tt.name)
+		assert.Equal(t, tt.statuses, statuses(results), tt.name)
+
+		err := checkError(results, failed)
+		if tt.failed == "" {
+			assert.NoError(t, err, tt.name)
+			continue
+		}
+		assert.Equal(t, ExitCodes[tt.failed], exitcode.Of(err), tt.name)
+	}
+
+	results := []CheckResult{{Check: CheckAuth, Status: statusFailed, Error: "invalid token"}}
+	err := checkError(results, CheckAuth)
+	assert.EqualError(t, err, "healthcheck [auth] failed: [invalid token]")
+	assert.Equal(t, exitcode.Forbidden, exitcode.Of(err))
+}
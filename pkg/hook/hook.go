@@ -0,0 +1,269 @@
+// Package hook runs external programs on the events of a live query, its records and its errors,
+// with the JSON of the event as their input, so the records can be processed by shell scripts.
+package hook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kataras/golog"
+	"github.com/lensesio/lenses-go/pkg/sink"
+	"github.com/lensesio/lenses-go/pkg/transform"
+	"github.com/lensesio/lenses-go/pkg/websocket"
+)
+
+// The defaults of the `Options`.
+const (
+	DefaultConcurrency = 4
+	DefaultTimeout     = 30 * time.Second
+)
+
+// Options are the programs of the events and their limits.
+type Options struct {
+	// OnRecord is the command run for each record, with the JSON of the `sink.Record` as its input.
+	OnRecord string
+	// OnError is the command run for each error, with the JSON of the `ErrorEvent` as its input.
+	OnError string
+	// Concurrency is the maximum programs that run at the same time, a record waits for a free one.
+	// Defaults to `DefaultConcurrency`.
+	Concurrency int
+	// Timeout is the time a program may run before it's killed, defaults to `DefaultTimeout`.
+	Timeout time.Duration
+	// Transform, if not nil, anonymizes the records before they are passed to the program.
+	Transform *transform.Transformer
+}
+
+// ErrorEvent is the input of the `Options#OnError` program.
+type ErrorEvent struct {
+	// Type is the type of the message, i.e ERROR or INVALIDREQUEST, or CONNECTION for a connection error.
+	Type  string `json:"type"`
+	Topic string `json:"topic,omitempty"`
+	Error string `json:"error"`
+}
+
+// Runner runs a command through the shell for each event, with the JSON of the event as its input
+// and its output written to the standard output and error.
+type Runner struct {
+	Command string
+	Timeout time.Duration
+	Stdout  io.Writer
+	Stderr  io.Writer
+
+	slots chan struct{}
+	wg    sync.WaitGroup
+
+	mu          sync.Mutex
+	ran, failed int64
+}
+
+// NewRunner returns a `Runner` of the "command", which runs at most "concurrency" programs at the same time
+// and kills the ones that run longer than the "timeout".
+func NewRunner(command string, concurrency int, timeout time.Duration) *Runner {
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	return &Runner{
+		Command: command,
+		Timeout: timeout,
+		Stdout:  os.Stdout,
+		Stderr:  os.Stderr,
+		slots:   make(chan struct{}, concurrency),
+	}
+}
+
+// Run runs the command with the "event" and waits for it, the "env" are added to its environment.
+func (r *Runner) Run(event interface{}, env ...string) error {
+	r.slots <- struct{}{}
+	defer func() { <-r.slots }()
+
+	return r.run(event, env)
+}
+
+// Go runs the command with the "event" in the background, it blocks while all the programs are running.
+// A failure is logged, see `Stats`.
+func (r *Runner) Go(event interface{}, env ...string) {
+	r.slots <- struct{}{}
+	r.wg.Add(1)
+
+	go func() {
+		defer func() {
+			<-r.slots
+			r.wg.Done()
+		}()
+
+		if err := r.run(event, env); err != nil {
+			golog.Errorf("hook: %v", err)
+		}
+	}()
+}
+
+func (r *Runner) run(event interface{}, env []string) error {
+	err := Exec(r.Command, event, r.Timeout, r.Stdout, r.Stderr, env...)
+
+	r.mu.Lock()
+	r.ran++
+	if err != nil {
+		r.failed++
+	}
+	r.mu.Unlock()
+
+	return err
+}
+
+// Exec runs the "command" through the shell, `sh -c` or `cmd /c` on windows, with the JSON of the "input" as its input
+// and the "env" added to its environment, and waits for it. It's killed if it runs longer than the "timeout".
+func Exec(command string, input interface{}, timeout time.Duration, stdout, stderr io.Writer, env ...string) error {
+	b, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/c", command)
+	}
+
+	cmd.Stdin = bytes.NewReader(b)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.Env = append(os.Environ(), env...)
+
+	err = cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("exec [%s]: killed after [%s]", command, timeout)
+	}
+
+	if err != nil {
+		return fmt.Errorf("exec [%s]: [%v]", command, err)
+	}
+
+	return nil
+}
+
+// Wait waits for the programs started by `Go`.
+func (r *Runner) Wait() {
+	r.wg.Wait()
+}
+
+// Stats returns the programs that ran and the ones that failed or were killed.
+func (r *Runner) Stats() (ran, failed int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.ran, r.failed
+}
+
+// Hooks runs the programs of the `Options` on the events of the live streams it's attached to.
+type Hooks struct {
+	opts   Options
+	record *Runner
+	err    *Runner
+}
+
+// New returns the `Hooks` of the "opts", or nil if they have no program.
+// The programs of all the streams it's attached to share the same limits.
+func New(opts Options) *Hooks {
+	if opts.OnRecord == "" && opts.OnError == "" {
+		return nil
+	}
+
+	h := &Hooks{opts: opts}
+	if opts.OnRecord != "" {
+		h.record = NewRunner(opts.OnRecord, opts.Concurrency, opts.Timeout)
+	}
+
+	if opts.OnError != "" {
+		h.err = NewRunner(opts.OnError, opts.Concurrency, opts.Timeout)
+	}
+
+	return h
+}
+
+// Attach runs the programs on the records and the errors of the "stream" of the "topic", which may be empty.
+// It should be attached before the listeners that exit on an error, the error's program runs before they are called.
+func (h *Hooks) Attach(stream websocket.LiveStream, topic string) {
+	if h == nil {
+		return
+	}
+
+	if h.record != nil {
+		stream.OnRecordMessage(func(resp websocket.LiveResponse) error {
+			record := sink.NewRecord(resp.Data)
+			if h.opts.Transform != nil {
+				key, value, err := h.opts.Transform.Apply(record.Key, record.Value)
+				if err != nil {
+					return err
+				}
+				record.Key, record.Value = key, value
+			}
+
+			h.record.Go(record, recordEnv(topic, record)...)
+			return nil
+		})
+	}
+
+	if h.err != nil {
+		onError := func(resp websocket.LiveResponse) error {
+			var msg string
+			if err := json.Unmarshal(resp.Data.Value, &msg); err != nil {
+				msg = string(resp.Data.Value)
+			}
+
+			h.Error(ErrorEvent{Type: string(resp.Type), Topic: topic, Error: msg})
+			return nil
+		}
+
+		stream.OnError(onError)
+		stream.OnInvalidRequest(onError)
+	}
+}
+
+// Error runs the error's program with the "event" and waits for it, it's a no-op if there is none.
+func (h *Hooks) Error(event ErrorEvent) {
+	if h == nil || h.err == nil {
+		return
+	}
+
+	if err := h.err.Run(event, "LENSES_EVENT=error", "LENSES_TOPIC="+event.Topic); err != nil {
+		golog.Errorf("hook: %v", err)
+	}
+}
+
+// Close waits for the running programs.
+func (h *Hooks) Close() {
+	if h == nil {
+		return
+	}
+
+	if h.record != nil {
+		h.record.Wait()
+		ran, failed := h.record.Stats()
+		golog.Debugf("hook: [%d] record programs ran, [%d] failed", ran, failed)
+	}
+}
+
+func recordEnv(topic string, record sink.Record) []string {
+	return []string{
+		"LENSES_EVENT=record",
+		"LENSES_TOPIC=" + topic,
+		"LENSES_PARTITION=" + strconv.Itoa(record.Metadata.Partition),
+		"LENSES_OFFSET=" + strconv.Itoa(record.Metadata.Offset),
+	}
+}
@@ -0,0 +1,94 @@
+package hook
+
+import (
+	"bytes"
+	"encoding/json"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lensesio/lenses-go/pkg/sink"
+	"github.com/lensesio/lenses-go/pkg/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncBuffer is the output of the programs that run at the same time.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestRunner(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the commands are shell scripts")
+	}
+
+	var out syncBuffer
+	r := NewRunner(`cat; echo " $LENSES_TOPIC"`, 2, time.Second)
+	r.Stdout = &out
+
+	for i := 0; i < 5; i++ {
+		record := sink.Record{Value: json.RawMessage(`{"amount":1}`), Metadata: websocket.MetaData{Offset: i}}
+		r.Go(record, recordEnv("payments", record)...)
+	}
+	r.Wait()
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 5)
+	assert.Contains(t, lines[0], `"value":{"amount":1}`)
+	assert.True(t, strings.HasSuffix(lines[0], " payments"), lines[0])
+
+	ran, failed := r.Stats()
+	assert.Equal(t, int64(5), ran)
+	assert.Equal(t, int64(0), failed)
+
+	assert.EqualError(t, NewRunner("exit 3", 1, time.Second).Run(ErrorEvent{}), "exec [exit 3]: [exit status 3]")
+	assert.EqualError(t, NewRunner("exec sleep 5", 1, 50*time.Millisecond).Run(ErrorEvent{}), "exec [exec sleep 5]: killed after [50ms]")
+}
+
+func TestRunnerConcurrency(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the commands are shell scripts")
+	}
+
+	r := NewRunner("sleep 0.2", 2, time.Second)
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		r.Go(ErrorEvent{})
+	}
+	r.Wait()
+
+	// two rounds of two programs.
+	assert.True(t, time.Since(start) >= 400*time.Millisecond, time.Since(start).String())
+}
+
+func TestHooksError(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the commands are shell scripts")
+	}
+
+	assert.Nil(t, New(Options{}), "no programs")
+
+	var out syncBuffer
+	h := New(Options{OnError: "cat"})
+	h.err.Stdout = &out
+	h.Error(ErrorEvent{Type: "CONNECTION", Topic: "payments", Error: "connection reset"})
+	h.Close()
+
+	assert.JSONEq(t, `{"type":"CONNECTION","topic":"payments","error":"connection reset"}`, out.String())
+}
@@ -8,8 +8,11 @@ import (
 	"github.com/lensesio/bite"
 	"github.com/lensesio/lenses-go/pkg"
 	"github.com/lensesio/lenses-go/pkg/api"
+	"github.com/lensesio/lenses-go/pkg/bulk"
 	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/manifest"
 	"github.com/lensesio/lenses-go/pkg/utils"
+	v1 "github.com/lensesio/lenses-go/v1"
 	"github.com/spf13/cobra"
 )
 
@@ -42,7 +45,7 @@ func NewImportAclsCommand() *cobra.Command {
 	return cmd
 }
 
-func loadAcls(client *api.Client, cmd *cobra.Command, loadpath string) error {
+func loadAcls(client v1.Client, cmd *cobra.Command, loadpath string) error {
 	golog.Infof("Loading acls from [%s]", loadpath)
 	files, err := utils.FindFiles(loadpath)
 	if err != nil {
@@ -55,9 +58,10 @@ func loadAcls(client *api.Client, cmd *cobra.Command, loadpath string) error {
 		return err
 	}
 
+	var tasks []bulk.Task
 	for _, file := range files {
 		var candidateACLs []api.ACL
-		if err := bite.LoadFile(cmd, fmt.Sprintf("%s/%s", loadpath, file.Name()), &candidateACLs); err != nil {
+		if err := config.LoadManifest(cmd, fmt.Sprintf("%s/%s", loadpath, file.Name()), manifest.KindACLs, &candidateACLs); err != nil {
 			golog.Errorf("Error loading file [%s]", loadpath)
 			return err
 		}
@@ -72,10 +76,17 @@ func loadAcls(client *api.Client, cmd *cobra.Command, loadpath string) error {
 				}
 			}
 
-			if err := client.CreateOrUpdateACL(candidateACL); err != nil {
-				return fmt.Errorf("error creating/updating acl from [%s] [%s]", loadpath, err.Error())
-			}
-			fmt.Fprintf(cmd.OutOrStdout(), "imported ACL [%s] successfully\n", candidateACL)
+			acl := candidateACL
+			tasks = append(tasks, bulk.Task{
+				Name: fmt.Sprintf("ACL [%s]", acl),
+				Run: func() error {
+					if err := client.CreateOrUpdateACL(acl); err != nil {
+						return fmt.Errorf("error creating/updating acl from [%s] [%s]", loadpath, err.Error())
+					}
+					fmt.Fprintf(cmd.OutOrStdout(), "imported ACL [%s] successfully\n", acl)
+					return nil
+				},
+			})
 
 			imported = true
 		}
@@ -85,5 +96,6 @@ func loadAcls(client *api.Client, cmd *cobra.Command, loadpath string) error {
 			fmt.Fprintf(cmd.OutOrStdout(), "no new ACLs have been found for import from %s\n", importFilePath)
 		}
 	}
-	return nil
+
+	return bulk.RunAndReport(cmd, tasks)
 }
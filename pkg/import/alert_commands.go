@@ -10,6 +10,7 @@ import (
 	"github.com/lensesio/lenses-go/pkg"
 	"github.com/lensesio/lenses-go/pkg/api"
 	config "github.com/lensesio/lenses-go/pkg/configs"
+	v1 "github.com/lensesio/lenses-go/v1"
 	"github.com/spf13/cobra"
 )
 
@@ -47,7 +48,7 @@ func NewImportAlertSettingsCommand() *cobra.Command {
 	return cmd
 }
 
-func loadConsumerAlertSettings(client *api.Client, cmd *cobra.Command, loadpath string) error {
+func loadConsumerAlertSettings(client v1.Client, cmd *cobra.Command, loadpath string) error {
 	settings, err := client.GetAlertSetting(2000)
 	if err != nil {
 		return err
@@ -76,7 +77,7 @@ func loadConsumerAlertSettings(client *api.Client, cmd *cobra.Command, loadpath
 		return err
 	}
 
-	if err := bite.LoadFile(cmd, fmt.Sprintf("%s/%s", loadpath, "alert-setting-consumer.yaml"), &targetConsumerAlertSettings); err != nil {
+	if err := config.LoadManifest(cmd, fmt.Sprintf("%s/%s", loadpath, "alert-setting-consumer.yaml"), "", &targetConsumerAlertSettings); err != nil {
 		return fmt.Errorf("error loading file [%s]", loadpath)
 	}
 	fmt.Fprintf(cmd.OutOrStdout(), "loading alert conditions from alert-setting-consumer.yaml\n")
@@ -122,7 +123,7 @@ func loadConsumerAlertSettings(client *api.Client, cmd *cobra.Command, loadpath
 	return nil
 }
 
-func loadProducerAlertSettings(client *api.Client, cmd *cobra.Command, loadpath string) error {
+func loadProducerAlertSettings(client v1.Client, cmd *cobra.Command, loadpath string) error {
 	settings, err := client.GetAlertSetting(5000)
 	if err != nil {
 		return err
@@ -151,7 +152,7 @@ func loadProducerAlertSettings(client *api.Client, cmd *cobra.Command, loadpath
 		return err
 	}
 
-	if err := bite.LoadFile(cmd, fmt.Sprintf("%s/%s", loadpath, "alert-setting-producer.yaml"), &targetProducerAlertSettings); err != nil {
+	if err := config.LoadManifest(cmd, fmt.Sprintf("%s/%s", loadpath, "alert-setting-producer.yaml"), "", &targetProducerAlertSettings); err != nil {
 		return fmt.Errorf("error loading file [%s]", loadpath)
 	}
 	fmt.Fprintf(cmd.OutOrStdout(), "loading alert conditions from alert-setting-producer.yaml\n")
@@ -0,0 +1,78 @@
+package imports
+
+import (
+	"github.com/lensesio/bite"
+	"github.com/lensesio/lenses-go/pkg/batch"
+	"github.com/spf13/cobra"
+)
+
+//NewImportAllCommand creates `import all` command
+func NewImportAllCommand() *cobra.Command {
+	var (
+		path, only, skip string
+		dryRun           bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "all",
+		Short: "Import every kind of a landscape directory, in dependency order",
+		Long: `Import every kind of resources of a landscape directory by its import command, in dependency order:
+the schemas before the topics of their subjects, the topics before the connectors and processors which read them,
+the processors before the ones which read the topics they write and the ACLs last. A dependency cycle fails the import
+before anything is imported. --dry-run prints the order without importing.`,
+		Example: `import all --dir my-landscape --dry-run
+import all --dir my-landscape --skip acls,quotas
+import all --dir my-landscape --only topics,schemas,processors`,
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kinds, err := selectKinds(only, skip)
+			if err != nil {
+				return err
+			}
+
+			var resources []resource
+			for _, kind := range kinds {
+				r, err := loadResources(path, kind)
+				if err != nil {
+					return err
+				}
+				resources = append(resources, r...)
+			}
+
+			steps, err := resolveKinds(resources)
+			if err != nil {
+				return err
+			}
+
+			if len(steps) == 0 {
+				return bite.PrintInfo(cmd, "No resources to import in [%s]", path)
+			}
+
+			if dryRun {
+				return bite.PrintObject(cmd, steps)
+			}
+
+			script := &batch.Script{}
+			for _, step := range steps {
+				script.Steps = append(script.Steps, batch.Step{
+					Name: "import " + step.Kind,
+					Args: []string{"import", step.Kind, "--dir", path},
+				})
+			}
+
+			runner := &batch.Runner{Root: cmd.Root(), Self: cmd, Err: cmd.ErrOrStderr()}
+			_, err = runner.Run(script)
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&path, "dir", ".", "Base directory to import")
+	cmd.Flags().StringVar(&only, "only", "", "The comma separated kinds to import, all if empty, i.e topics,schemas")
+	cmd.Flags().StringVar(&skip, "skip", "", "The comma separated kinds not to import, i.e acls,quotas")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the kinds in the order they would be imported, without importing them")
+
+	bite.CanPrintJSON(cmd)
+
+	return cmd
+}
@@ -5,13 +5,14 @@ import (
 	"fmt"
 	"reflect"
 
-	"github.com/lensesio/bite"
 	"github.com/lensesio/lenses-go/pkg/api"
+	config "github.com/lensesio/lenses-go/pkg/configs"
 	"github.com/lensesio/lenses-go/pkg/utils"
+	v1 "github.com/lensesio/lenses-go/v1"
 	"github.com/spf13/cobra"
 )
 
-func importChannels(client *api.Client, cmd *cobra.Command, loadpath, channelType, channelsPath string) error {
+func importChannels(client v1.Client, cmd *cobra.Command, loadpath, channelType, channelsPath string) error {
 	fmt.Fprintf(cmd.OutOrStdout(), "loading %s channels from [%s] directory\n", channelType, loadpath)
 
 	var targetChannels []api.ChannelPayload
@@ -22,7 +23,7 @@ func importChannels(client *api.Client, cmd *cobra.Command, loadpath, channelTyp
 
 	for _, file := range files {
 		var targetChannel api.ChannelPayload
-		if err := bite.LoadFile(cmd, fmt.Sprintf("%s/%s", loadpath, file.Name()), &targetChannel); err != nil {
+		if err := config.LoadManifest(cmd, fmt.Sprintf("%s/%s", loadpath, file.Name()), "", &targetChannel); err != nil {
 			return fmt.Errorf("error loading file [%s]", loadpath)
 		}
 		targetChannels = append(targetChannels, targetChannel)
@@ -9,6 +9,7 @@ import (
 	"github.com/lensesio/lenses-go/pkg/api"
 	config "github.com/lensesio/lenses-go/pkg/configs"
 	"github.com/lensesio/lenses-go/pkg/utils"
+	v1 "github.com/lensesio/lenses-go/v1"
 	"github.com/spf13/cobra"
 )
 
@@ -40,7 +41,7 @@ func NewImportConnectionsCommand() *cobra.Command {
 	return cmd
 }
 
-func loadConnections(client *api.Client, cmd *cobra.Command, loadpath string) error {
+func loadConnections(client v1.Client, cmd *cobra.Command, loadpath string) error {
 	golog.Infof("Loading connections from [%s]", loadpath)
 
 	currentConnections, err := client.GetConnections()
@@ -60,7 +61,7 @@ func loadConnections(client *api.Client, cmd *cobra.Command, loadpath string) er
 
 	for _, file := range files {
 		var connection api.Connection
-		if err := bite.LoadFile(cmd, fmt.Sprintf("%s/%s", loadpath, file.Name()), &connection); err != nil {
+		if err := config.LoadManifest(cmd, fmt.Sprintf("%s/%s", loadpath, file.Name()), "", &connection); err != nil {
 			golog.Errorf("Error loading file [%s]", loadpath)
 			return err
 		}
@@ -10,7 +10,9 @@ import (
 	"github.com/lensesio/lenses-go/pkg"
 	"github.com/lensesio/lenses-go/pkg/api"
 	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/manifest"
 	"github.com/lensesio/lenses-go/pkg/utils"
+	v1 "github.com/lensesio/lenses-go/v1"
 	"github.com/matryer/try"
 	"github.com/spf13/cobra"
 )
@@ -47,7 +49,7 @@ func NewImportConnectorsCommand() *cobra.Command {
 	return cmd
 }
 
-func loadConnectors(client *api.Client, cmd *cobra.Command, loadpath, interval string, retries int) error {
+func loadConnectors(client v1.Client, cmd *cobra.Command, loadpath, interval string, retries int) error {
 	intervalDuration, err := time.ParseDuration(interval)
 	if err != nil {
 		return err
@@ -61,7 +63,7 @@ func loadConnectors(client *api.Client, cmd *cobra.Command, loadpath, interval s
 
 	for _, file := range files {
 		var connector api.CreateUpdateConnectorPayload
-		if err := load(cmd, fmt.Sprintf("%s/%s", loadpath, file.Name()), &connector); err != nil {
+		if err := load(cmd, fmt.Sprintf("%s/%s", loadpath, file.Name()), manifest.KindConnector, &connector); err != nil {
 			return err
 		}
 
@@ -0,0 +1,366 @@
+package imports
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/lensesio/lenses-go/pkg"
+	"github.com/lensesio/lenses-go/pkg/api"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/manifest"
+	"github.com/lensesio/lenses-go/pkg/utils"
+)
+
+// importKind is a kind of resources of a landscape directory, the `import` subcommand of its name imports
+// the files of its subdirectory.
+type importKind struct {
+	Name, Dir string
+}
+
+// importKinds are the kinds of a landscape directory, in the order they are imported when they do not depend on each other.
+// The ACLs are imported last, as they may refer to any resource.
+var importKinds = []importKind{
+	{Name: "connections", Dir: pkg.ConnectionsFilePath},
+	{Name: "topic-settings", Dir: pkg.TopicSettingsPath},
+	{Name: "groups", Dir: pkg.GroupsPath},
+	{Name: "serviceaccounts", Dir: pkg.ServiceAccountsPath},
+	{Name: "schemas", Dir: pkg.SchemasPath},
+	{Name: "topics", Dir: pkg.TopicsPath},
+	{Name: "quotas", Dir: pkg.QuotasPath},
+	{Name: "policies", Dir: pkg.PoliciesPath},
+	{Name: "connectors", Dir: pkg.ConnectorsPath},
+	{Name: "processors", Dir: pkg.SQLPath},
+	{Name: "alert-channels", Dir: "alert-channels"},
+	{Name: "audit-channels", Dir: "audit-channels"},
+	{Name: "alert-settings", Dir: pkg.AlertSettingsPath},
+	{Name: "acls", Dir: pkg.AclsPath},
+}
+
+const aclsKind = "acls"
+
+// resource is a resource of a landscape directory, a node of its dependency graph.
+type resource struct {
+	Kind, Name string
+	// Reads and Writes are the topics the resource consumes and produces, Requires the ids of the other resources it needs.
+	Reads, Writes []string
+	Requires      []string
+}
+
+func resourceID(kind, name string) string {
+	return kind + "/" + name
+}
+
+func (r resource) ID() string {
+	return resourceID(r.Kind, r.Name)
+}
+
+var (
+	sqlWrites = regexp.MustCompile("(?i)\\bINSERT\\s+INTO\\s+(`[^`]+`|[\\w.\\-]+)")
+	sqlReads  = regexp.MustCompile("(?i)\\b(?:FROM|JOIN)\\s+(`[^`]+`|[\\w.\\-]+)")
+)
+
+// sqlTopics returns the topics the processor "sql" reads from and writes to, by its `FROM`, `JOIN` and `INSERT INTO` clauses.
+func sqlTopics(sql string) (reads, writes []string) {
+	for _, m := range sqlWrites.FindAllStringSubmatch(sql, -1) {
+		writes = append(writes, strings.Trim(m[1], "`"))
+	}
+
+	for _, m := range sqlReads.FindAllStringSubmatch(sql, -1) {
+		reads = append(reads, strings.Trim(m[1], "`"))
+	}
+
+	return
+}
+
+// connectorTopics are the config keys of the connectors which name the topics they read or write.
+var connectorTopics = []string{"topics", "topic", "kafka.topic"}
+
+// loadResources reads the resources of the "kind" from the landscape "dir", the ones whose references are known:
+// the topics, the schemas, the connectors, the processors and the ACLs. The rest are resources without dependencies.
+func loadResources(dir string, kind importKind) ([]resource, error) {
+	path := filepath.Join(dir, kind.Dir)
+	files, err := utils.FindFiles(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var resources []resource
+	for _, file := range files {
+		filePath := filepath.Join(path, file.Name())
+		name := strings.TrimSuffix(file.Name(), filepath.Ext(file.Name()))
+		r := resource{Kind: kind.Name, Name: name}
+
+		switch kind.Name {
+		case "topics":
+			var topic api.CreateTopicPayload
+			if err := config.ReadManifest(filePath, manifest.KindTopic, &topic); err != nil {
+				return nil, err
+			}
+			r.Name = topic.TopicName
+		case "connectors":
+			connector := api.CreateUpdateConnectorPayload{Config: make(api.ConnectorConfig)}
+			if err := config.ReadManifest(filePath, manifest.KindConnector, &connector); err != nil {
+				return nil, err
+			}
+			r.Name = connector.Name
+			for _, key := range connectorTopics {
+				if value, ok := connector.Config[key].(string); ok {
+					for _, topic := range strings.Split(value, ",") {
+						if topic = strings.TrimSpace(topic); topic != "" {
+							r.Reads = append(r.Reads, topic)
+						}
+					}
+				}
+			}
+		case "processors":
+			var processor api.CreateProcessorFilePayload
+			if err := config.ReadManifest(filePath, manifest.KindProcessor, &processor); err != nil {
+				return nil, err
+			}
+			r.Name = processor.Name
+			r.Reads, r.Writes = sqlTopics(processor.SQL)
+		case aclsKind:
+			var acls []api.ACL
+			if err := config.ReadManifest(filePath, manifest.KindACLs, &acls); err != nil {
+				return nil, err
+			}
+			for _, acl := range acls {
+				if strings.EqualFold(string(acl.ResourceType), string(api.ACLResourceTopic)) {
+					r.Reads = append(r.Reads, acl.ResourceName)
+				}
+			}
+		}
+
+		resources = append(resources, r)
+	}
+
+	return resources, nil
+}
+
+// link sets the `Requires` of the "resources": a topic requires the key and value schemas of its subject,
+// a resource which reads a topic requires the topic, or the processors which write it if it is not a resource itself.
+func link(resources []resource) {
+	schemas := make(map[string]bool)
+	topics := make(map[string]bool)
+	writers := make(map[string][]string)
+	for _, r := range resources {
+		switch r.Kind {
+		case "schemas":
+			schemas[r.Name] = true
+		case "topics":
+			topics[r.Name] = true
+		}
+		for _, topic := range r.Writes {
+			writers[topic] = append(writers[topic], r.ID())
+		}
+	}
+
+	for i, r := range resources {
+		var requires []string
+		if r.Kind == "topics" {
+			for _, subject := range []string{r.Name + "-key", r.Name + "-value"} {
+				if schemas[subject] {
+					requires = append(requires, resourceID("schemas", subject))
+				}
+			}
+		}
+
+		for _, topic := range r.Reads {
+			if topics[topic] {
+				requires = append(requires, resourceID("topics", topic))
+				continue
+			}
+			for _, writer := range writers[topic] {
+				if writer != r.ID() {
+					requires = append(requires, writer)
+				}
+			}
+		}
+
+		resources[i].Requires = requires
+	}
+}
+
+// findCycle returns the ids of a dependency cycle of the "resources", the first one again at the end, or none.
+func findCycle(resources []resource) []string {
+	byID := make(map[string]resource, len(resources))
+	for _, r := range resources {
+		byID[r.ID()] = r
+	}
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int, len(resources))
+
+	var (
+		path  []string
+		cycle []string
+		visit func(id string) bool
+	)
+	visit = func(id string) bool {
+		switch state[id] {
+		case visited:
+			return false
+		case visiting:
+			for i, p := range path {
+				if p == id {
+					cycle = append(append(cycle, path[i:]...), id)
+					break
+				}
+			}
+			return true
+		}
+
+		state[id] = visiting
+		path = append(path, id)
+		for _, dep := range byID[id].Requires {
+			if visit(dep) {
+				return true
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = visited
+		return false
+	}
+
+	for _, r := range resources {
+		if visit(r.ID()) {
+			return cycle
+		}
+	}
+
+	return nil
+}
+
+// importStep is a kind of the import plan, see `resolveKinds`.
+type importStep struct {
+	Kind      string `json:"kind" yaml:"kind" header:"Kind"`
+	Resources int    `json:"resources" yaml:"resources" header:"Resources"`
+	// After are the kinds of the resources the kind's resources require.
+	After []string `json:"after,omitempty" yaml:"after,omitempty" header:"After"`
+}
+
+// resolveKinds returns the kinds of the "resources" in the order they can be imported, each kind after the kinds of
+// the resources its resources require and the ACLs last, otherwise in the `importKinds` order.
+// It fails on a dependency cycle of the resources.
+func resolveKinds(resources []resource) ([]importStep, error) {
+	link(resources)
+	if cycle := findCycle(resources); len(cycle) > 0 {
+		return nil, fmt.Errorf("dependency cycle [%s]", strings.Join(cycle, " -> "))
+	}
+
+	counts := make(map[string]int)
+	after := make(map[string]map[string]bool)
+	for _, r := range resources {
+		counts[r.Kind]++
+		if after[r.Kind] == nil {
+			after[r.Kind] = make(map[string]bool)
+		}
+		for _, dep := range r.Requires {
+			if kind := dep[:strings.IndexByte(dep, '/')]; kind != r.Kind {
+				after[r.Kind][kind] = true
+			}
+		}
+	}
+
+	if counts[aclsKind] > 0 {
+		for kind := range counts {
+			if kind != aclsKind {
+				after[aclsKind][kind] = true
+			}
+		}
+	}
+
+	var steps []importStep
+	done := make(map[string]bool)
+	for len(steps) < len(counts) {
+		progress := false
+		for _, kind := range importKinds {
+			if counts[kind.Name] == 0 || done[kind.Name] {
+				continue
+			}
+
+			ready := true
+			for dep := range after[kind.Name] {
+				ready = ready && done[dep]
+			}
+			if !ready {
+				continue
+			}
+
+			step := importStep{Kind: kind.Name, Resources: counts[kind.Name]}
+			for dep := range after[kind.Name] {
+				step.After = append(step.After, dep)
+			}
+			sort.Strings(step.After)
+
+			steps = append(steps, step)
+			done[kind.Name] = true
+			progress = true
+			break
+		}
+
+		if !progress {
+			var pending []string
+			for kind := range counts {
+				if !done[kind] {
+					pending = append(pending, kind)
+				}
+			}
+			sort.Strings(pending)
+			return nil, fmt.Errorf("dependency cycle between the kinds [%s]", strings.Join(pending, ", "))
+		}
+	}
+
+	return steps, nil
+}
+
+// selectKinds returns the `importKinds` of the comma separated "only" kinds, all if empty, without the "skip" ones.
+func selectKinds(only, skip string) ([]importKind, error) {
+	known := make(map[string]bool, len(importKinds))
+	for _, kind := range importKinds {
+		known[kind.Name] = true
+	}
+
+	parse := func(s string) (map[string]bool, error) {
+		names := make(map[string]bool)
+		for _, name := range strings.Split(s, ",") {
+			if name = strings.TrimSpace(name); name == "" {
+				continue
+			}
+			if !known[name] {
+				return nil, fmt.Errorf("unknown kind [%s]", name)
+			}
+			names[name] = true
+		}
+		return names, nil
+	}
+
+	onlyKinds, err := parse(only)
+	if err != nil {
+		return nil, err
+	}
+
+	skipKinds, err := parse(skip)
+	if err != nil {
+		return nil, err
+	}
+
+	var kinds []importKind
+	for _, kind := range importKinds {
+		if (len(onlyKinds) == 0 || onlyKinds[kind.Name]) && !skipKinds[kind.Name] {
+			kinds = append(kinds, kind)
+		}
+	}
+
+	return kinds, nil
+}
@@ -0,0 +1,88 @@
+package imports
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lensesio/lenses-go/pkg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLTopics(t *testing.T) {
+	reads, writes := sqlTopics("SET defaults.topic.autocreate=true;\nINSERT INTO `orders-enriched` SELECT STREAM * FROM orders o INNER JOIN `customers` c ON o.customerId = c._key")
+	assert.Equal(t, []string{"orders", "customers"}, reads)
+	assert.Equal(t, []string{"orders-enriched"}, writes)
+}
+
+func TestResolveKinds(t *testing.T) {
+	resources := []resource{
+		{Kind: "acls", Name: "acls-all", Reads: []string{"orders"}},
+		{Kind: "processors", Name: "report", Reads: []string{"orders-enriched"}, Writes: []string{"report"}},
+		{Kind: "processors", Name: "enrich", Reads: []string{"orders"}, Writes: []string{"orders-enriched"}},
+		{Kind: "connectors", Name: "report-sink", Reads: []string{"report"}},
+		{Kind: "topics", Name: "orders"},
+		{Kind: "schemas", Name: "orders-value"},
+		{Kind: "quotas", Name: "quotas"},
+	}
+
+	steps, err := resolveKinds(resources)
+	require.NoError(t, err)
+	assert.Equal(t, []importStep{
+		{Kind: "schemas", Resources: 1},
+		{Kind: "topics", Resources: 1, After: []string{"schemas"}},
+		{Kind: "quotas", Resources: 1},
+		{Kind: "processors", Resources: 2, After: []string{"topics"}},
+		{Kind: "connectors", Resources: 1, After: []string{"processors"}},
+		{Kind: "acls", Resources: 1, After: []string{"connectors", "processors", "quotas", "schemas", "topics"}},
+	}, steps)
+	assert.Equal(t, []string{"processors/enrich"}, resources[1].Requires)
+
+	_, err = resolveKinds([]resource{
+		{Kind: "processors", Name: "a", Reads: []string{"x"}, Writes: []string{"y"}},
+		{Kind: "processors", Name: "b", Reads: []string{"y"}, Writes: []string{"x"}},
+	})
+	assert.EqualError(t, err, "dependency cycle [processors/a -> processors/b -> processors/a]")
+}
+
+func TestSelectKinds(t *testing.T) {
+	kinds, err := selectKinds("acls,topics,schemas", "acls")
+	require.NoError(t, err)
+	assert.Equal(t, []importKind{{Name: "schemas", Dir: pkg.SchemasPath}, {Name: "topics", Dir: pkg.TopicsPath}}, kinds)
+
+	kinds, err = selectKinds("", "")
+	require.NoError(t, err)
+	assert.Equal(t, importKinds, kinds)
+
+	_, err = selectKinds("", "brokers")
+	assert.EqualError(t, err, "unknown kind [brokers]")
+}
+
+func TestLoadResources(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lenses-import-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	write := func(path, contents string) {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(path)), 0750))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, path), []byte(contents), 0600))
+	}
+
+	write("apps/connectors/connector-sink.yaml", "clusterName: dev\nname: sink\nconfig:\n  connector.class: FileStreamSink\n  topics: orders, payments\n")
+	write("kafka/topics/topic-orders.yaml", "name: orders\nreplication: 1\npartitions: 3\n")
+
+	connectors, err := loadResources(dir, importKind{Name: "connectors", Dir: pkg.ConnectorsPath})
+	require.NoError(t, err)
+	assert.Equal(t, []resource{{Kind: "connectors", Name: "sink", Reads: []string{"orders", "payments"}}}, connectors)
+
+	topics, err := loadResources(dir, importKind{Name: "topics", Dir: pkg.TopicsPath})
+	require.NoError(t, err)
+	assert.Equal(t, []resource{{Kind: "topics", Name: "orders"}}, topics)
+
+	// a missing kind has no resources.
+	processors, err := loadResources(dir, importKind{Name: "processors", Dir: pkg.SQLPath})
+	assert.NoError(t, err)
+	assert.Empty(t, processors)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/lensesio/lenses-go/pkg/api"
 	config "github.com/lensesio/lenses-go/pkg/configs"
 	"github.com/lensesio/lenses-go/pkg/utils"
+	v1 "github.com/lensesio/lenses-go/v1"
 	"github.com/spf13/cobra"
 )
 
@@ -41,7 +42,7 @@ func NewImportGroupsCommand() *cobra.Command {
 	return cmd
 }
 
-func loadGroups(client *api.Client, cmd *cobra.Command, loadpath string) error {
+func loadGroups(client v1.Client, cmd *cobra.Command, loadpath string) error {
 	golog.Infof("Loading user groups from [%s]", loadpath)
 	files, err := utils.FindFiles(loadpath)
 	if err != nil {
@@ -56,7 +57,7 @@ func loadGroups(client *api.Client, cmd *cobra.Command, loadpath string) error {
 	for _, file := range files {
 
 		var group api.Group
-		if err := bite.LoadFile(cmd, fmt.Sprintf("%s/%s", loadpath, file.Name()), &group); err != nil {
+		if err := config.LoadManifest(cmd, fmt.Sprintf("%s/%s", loadpath, file.Name()), "", &group); err != nil {
 			golog.Errorf("Error loading file [%s]", loadpath)
 			return err
 		}
@@ -1,7 +1,8 @@
 package imports
 
 import (
-	"github.com/lensesio/bite"
+	"github.com/lensesio/lenses-go/pkg/bulk"
+	config "github.com/lensesio/lenses-go/pkg/configs"
 	"github.com/spf13/cobra"
 )
 
@@ -22,11 +23,17 @@ import topics --landscape my-acls-dir
 import policies --landscape my-acls-dir
 import groups --dir groups
 import topic-settings --dir topic-settings
-import serviceaccounts --dir serviceaccounts`,
+import serviceaccounts --dir serviceaccounts
+import topics --dir my-dir --concurrency 8
+import topics --dir my-dir --rate 5/s
+import all --dir my-dir --skip acls`,
 		SilenceErrors:    true,
 		TraverseChildren: true,
 	}
 
+	bulk.AddConcurrencyFlag(cmd)
+	bulk.AddRateFlag(cmd)
+
 	cmd.AddCommand(NewImportAclsCommand())
 	cmd.AddCommand(NewImportAlertSettingsCommand())
 	cmd.AddCommand(NewImportConnectionsCommand())
@@ -41,10 +48,11 @@ import serviceaccounts --dir serviceaccounts`,
 	cmd.AddCommand(ImportTopicSettingsCmd())
 	cmd.AddCommand(NewImportAuditChannelsCommand())
 	cmd.AddCommand(NewImportSchemasCmd())
+	cmd.AddCommand(NewImportAllCommand())
 
 	return cmd
 }
 
-func load(cmd *cobra.Command, path string, data interface{}) error {
-	return bite.TryReadFile(path, data)
+func load(cmd *cobra.Command, path, kind string, data interface{}) error {
+	return config.ReadManifest(path, kind, data)
 }
@@ -7,8 +7,11 @@ import (
 	"github.com/lensesio/bite"
 	"github.com/lensesio/lenses-go/pkg"
 	"github.com/lensesio/lenses-go/pkg/api"
+	"github.com/lensesio/lenses-go/pkg/bulk"
 	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/manifest"
 	"github.com/lensesio/lenses-go/pkg/utils"
+	v1 "github.com/lensesio/lenses-go/v1"
 	"github.com/spf13/cobra"
 )
 
@@ -41,7 +44,7 @@ func NewImportPoliciesCommand() *cobra.Command {
 	return cmd
 }
 
-func loadPolicies(client *api.Client, cmd *cobra.Command, loadpath string) error {
+func loadPolicies(client v1.Client, cmd *cobra.Command, loadpath string) error {
 	golog.Infof("Loading data policies from [%s]", loadpath)
 	files, err := utils.FindFiles(loadpath)
 	if err != nil {
@@ -54,10 +57,11 @@ func loadPolicies(client *api.Client, cmd *cobra.Command, loadpath string) error
 		return err
 	}
 
+	var tasks []bulk.Task
 	for _, file := range files {
 
 		var policy api.DataPolicyRequest
-		if err := bite.LoadFile(cmd, fmt.Sprintf("%s/%s", loadpath, file.Name()), &policy); err != nil {
+		if err := config.LoadManifest(cmd, fmt.Sprintf("%s/%s", loadpath, file.Name()), manifest.KindPolicy, &policy); err != nil {
 			return err
 		}
 
@@ -76,22 +80,34 @@ func loadPolicies(client *api.Client, cmd *cobra.Command, loadpath string) error
 					Fields:      p.Fields,
 				}
 
-				if err := client.UpdatePolicy(payload); err != nil {
-					golog.Errorf("Error updating data policy [%s]. [%s]", p.Name, err.Error())
-					return err
-				}
-				golog.Infof("Updated policy [%s]", p.Name)
+				tasks = append(tasks, bulk.Task{
+					Name: fmt.Sprintf("policy [%s]", p.Name),
+					Run: func() error {
+						if err := client.UpdatePolicy(payload); err != nil {
+							golog.Errorf("Error updating data policy [%s]. [%s]", payload.Name, err.Error())
+							return err
+						}
+						golog.Infof("Updated policy [%s]", payload.Name)
+						return nil
+					},
+				})
 			}
 		}
 
 		if !found {
-			if err := client.CreatePolicy(policy); err != nil {
-				golog.Errorf("Error creating data policy [%s]. [%s]", policy.Name, err.Error())
-				return err
-			}
-			golog.Infof("Created data policy [%s]", policy.Name)
+			tasks = append(tasks, bulk.Task{
+				Name: fmt.Sprintf("policy [%s]", policy.Name),
+				Run: func() error {
+					if err := client.CreatePolicy(policy); err != nil {
+						golog.Errorf("Error creating data policy [%s]. [%s]", policy.Name, err.Error())
+						return err
+					}
+					golog.Infof("Created data policy [%s]", policy.Name)
+					return nil
+				},
+			})
 		}
 	}
 
-	return nil
+	return bulk.RunAndReport(cmd, tasks)
 }
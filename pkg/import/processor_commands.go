@@ -6,8 +6,11 @@ import (
 	"github.com/lensesio/bite"
 	"github.com/lensesio/lenses-go/pkg"
 	"github.com/lensesio/lenses-go/pkg/api"
+	"github.com/lensesio/lenses-go/pkg/bulk"
 	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/manifest"
 	"github.com/lensesio/lenses-go/pkg/utils"
+	v1 "github.com/lensesio/lenses-go/v1"
 
 	"github.com/kataras/golog"
 	"github.com/spf13/cobra"
@@ -42,7 +45,7 @@ func NewImportProcessorsCommand() *cobra.Command {
 	return cmd
 }
 
-func loadProcessors(client *api.Client, cmd *cobra.Command, loadpath string) error {
+func loadProcessors(client v1.Client, cmd *cobra.Command, loadpath string) error {
 	golog.Infof("Loading processors from [%s]", loadpath)
 	files, err := utils.FindFiles(loadpath)
 	if err != nil {
@@ -55,15 +58,18 @@ func loadProcessors(client *api.Client, cmd *cobra.Command, loadpath string) err
 		golog.Errorf("Failed to retrieve processors. [%s]", err.Error())
 	}
 
+	var tasks []bulk.Task
+
 IterateImportFiles:
 	for _, file := range files {
 
 		var processor api.CreateProcessorFilePayload
 
-		if err := load(cmd, fmt.Sprintf("%s/%s", loadpath, file.Name()), &processor); err != nil {
+		if err := load(cmd, fmt.Sprintf("%s/%s", loadpath, file.Name()), manifest.KindProcessor, &processor); err != nil {
 			return err
 		}
 
+		fileName := file.Name()
 		for _, p := range processors.Streams {
 			if processor.Name != p.Name ||
 				processor.ClusterName != p.ClusterName ||
@@ -72,35 +78,48 @@ IterateImportFiles:
 			}
 
 			if processor.Runners == p.Runners {
-				golog.Warnf("Processor [%s] from file [%s/%s] already exists", p.ID, loadpath, file.Name())
+				golog.Warnf("Processor [%s] from file [%s/%s] already exists", p.ID, loadpath, fileName)
 				// Iterate next file from 'files'
 				continue IterateImportFiles
 			}
-			//scale
-			if err := client.UpdateProcessorRunners(p.ID, processor.Runners); err != nil {
-				golog.Errorf("Error scaling processor [%s] from file [%s/%s]. [%s]", p.ID, loadpath, file.Name(), err.Error())
-				return err
-			}
-			golog.Infof("Scaled processor [%s] from file [%s/%s] from [%d] to [%d]", p.ID, loadpath, file.Name(), p.Runners, processor.Runners)
-			return nil
 
+			existing := p
+			tasks = append(tasks, bulk.Task{
+				Name: fmt.Sprintf("processor [%s]", processor.Name),
+				Run: func() error {
+					//scale
+					if err := client.UpdateProcessorRunners(existing.ID, processor.Runners); err != nil {
+						golog.Errorf("Error scaling processor [%s] from file [%s/%s]. [%s]", existing.ID, loadpath, fileName, err.Error())
+						return err
+					}
+					golog.Infof("Scaled processor [%s] from file [%s/%s] from [%d] to [%d]", existing.ID, loadpath, fileName, existing.Runners, processor.Runners)
+					return nil
+				},
+			})
+			continue IterateImportFiles
 		}
 
-		if err := client.CreateProcessor(
-			processor.Name,
-			processor.SQL,
-			processor.Runners,
-			processor.ClusterName,
-			processor.Namespace,
-			processor.Pipeline,
-			processor.ProcessorID); err != nil {
-
-			golog.Errorf("Error creating processor from file [%s/%s]. [%s]", loadpath, file.Name(), err.Error())
-			return err
-		}
-
-		golog.Infof("Created processor from [%s/%s]", loadpath, file.Name())
+		tasks = append(tasks, bulk.Task{
+			Name: fmt.Sprintf("processor [%s]", processor.Name),
+			Run: func() error {
+				if err := client.CreateProcessor(
+					processor.Name,
+					processor.SQL,
+					processor.Runners,
+					processor.ClusterName,
+					processor.Namespace,
+					processor.Pipeline,
+					processor.ProcessorID); err != nil {
+
+					golog.Errorf("Error creating processor from file [%s/%s]. [%s]", loadpath, fileName, err.Error())
+					return err
+				}
+
+				golog.Infof("Created processor from [%s/%s]", loadpath, fileName)
+				return nil
+			},
+		})
 	}
 
-	return nil
+	return bulk.RunAndReport(cmd, tasks)
 }
@@ -7,9 +7,12 @@ import (
 	"github.com/lensesio/bite"
 	"github.com/lensesio/lenses-go/pkg"
 	"github.com/lensesio/lenses-go/pkg/api"
+	"github.com/lensesio/lenses-go/pkg/bulk"
 	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/manifest"
 	quotapkg "github.com/lensesio/lenses-go/pkg/quota"
 	"github.com/lensesio/lenses-go/pkg/utils"
+	v1 "github.com/lensesio/lenses-go/v1"
 	"github.com/spf13/cobra"
 )
 
@@ -42,7 +45,7 @@ func NewImportQuotasCommand() *cobra.Command {
 	return cmd
 }
 
-func loadQuotas(client *api.Client, cmd *cobra.Command, loadpath string) error {
+func loadQuotas(client v1.Client, cmd *cobra.Command, loadpath string) error {
 	golog.Infof("Loading quotas from [%s]", loadpath)
 	files, err := utils.FindFiles(loadpath)
 	if err != nil {
@@ -60,9 +63,10 @@ func loadQuotas(client *api.Client, cmd *cobra.Command, loadpath string) error {
 		lensesReq = append(lensesReq, lq.GetQuotaAsRequest())
 	}
 
+	var tasks []bulk.Task
 	for _, file := range files {
 		var quotas []api.CreateQuotaPayload
-		if err := bite.LoadFile(cmd, fmt.Sprintf("%s/%s", loadpath, file.Name()), &quotas); err != nil {
+		if err := config.LoadManifest(cmd, fmt.Sprintf("%s/%s", loadpath, file.Name()), manifest.KindQuotas, &quotas); err != nil {
 			golog.Errorf("Error loading file [%s]", loadpath)
 			return err
 		}
@@ -85,30 +89,30 @@ func loadQuotas(client *api.Client, cmd *cobra.Command, loadpath string) error {
 				continue
 			}
 
-			if quota.QuotaType == string(api.QuotaEntityClient) ||
-				quota.QuotaType == string(api.QuotaEntityClients) ||
-				quota.QuotaType == string(api.QuotaEntityClientsDefault) {
-				if err := quotapkg.CreateQuotaForClients(cmd, client, quota); err != nil {
-					golog.Errorf("Error creating/updating quota type [%s], client [%s], user [%s] from [%s]. [%s]",
-						quota.QuotaType, quota.ClientID, quota.User, loadpath, err.Error())
-					return err
-				}
-
-				golog.Infof("Created/updated quota type [%s], client [%s], user [%s] from [%s]",
-					quota.QuotaType, quota.ClientID, quota.User, loadpath)
-				continue
-
-			}
-
-			if err := quotapkg.CreateQuotaForUsers(cmd, client, quota); err != nil {
-				golog.Errorf("Error creating/updating quota type [%s], client [%s], user [%s] from [%s]. [%s]",
-					quota.QuotaType, quota.ClientID, quota.User, loadpath, err.Error())
-				return err
-			}
-
-			golog.Infof("Created/updated quota type [%s], client [%s], user [%s] from [%s]",
-				quota.QuotaType, quota.ClientID, quota.User, loadpath)
+			quota := quota
+			tasks = append(tasks, bulk.Task{
+				Name: fmt.Sprintf("quota type [%s], client [%s], user [%s]", quota.QuotaType, quota.ClientID, quota.User),
+				Run: func() error {
+					create := quotapkg.CreateQuotaForUsers
+					if quota.QuotaType == string(api.QuotaEntityClient) ||
+						quota.QuotaType == string(api.QuotaEntityClients) ||
+						quota.QuotaType == string(api.QuotaEntityClientsDefault) {
+						create = quotapkg.CreateQuotaForClients
+					}
+
+					if err := create(cmd, client, quota); err != nil {
+						golog.Errorf("Error creating/updating quota type [%s], client [%s], user [%s] from [%s]. [%s]",
+							quota.QuotaType, quota.ClientID, quota.User, loadpath, err.Error())
+						return err
+					}
+
+					golog.Infof("Created/updated quota type [%s], client [%s], user [%s] from [%s]",
+						quota.QuotaType, quota.ClientID, quota.User, loadpath)
+					return nil
+				},
+			})
 		}
 	}
-	return nil
+
+	return bulk.RunAndReport(cmd, tasks)
 }
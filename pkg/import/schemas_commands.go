@@ -8,8 +8,10 @@ import (
 	"github.com/kataras/golog"
 	"github.com/lensesio/lenses-go/pkg"
 	"github.com/lensesio/lenses-go/pkg/api"
+	"github.com/lensesio/lenses-go/pkg/bulk"
 	config "github.com/lensesio/lenses-go/pkg/configs"
 	"github.com/lensesio/lenses-go/pkg/utils"
+	v1 "github.com/lensesio/lenses-go/v1"
 
 	"github.com/pkg/errors"
 
@@ -53,27 +55,34 @@ func NewImportSchemasCmd() *cobra.Command {
 }
 
 //ReadSchemas to read the files and import one by one
-func ReadSchemas(client *api.Client, cmd *cobra.Command, filePath string) error {
+func ReadSchemas(client v1.Client, cmd *cobra.Command, filePath string) error {
 	files, err := utils.FindFiles(filePath)
 	if err != nil {
 		return err
 	}
 
+	var tasks []bulk.Task
 	for _, file := range files {
 		var schema api.WriteSchemaReq
 		var fileName = file.Name()
 
-		if err := bite.LoadFile(cmd, fmt.Sprintf("%s/%s", filePath, file.Name()), &schema); err != nil {
+		if err := config.LoadManifest(cmd, fmt.Sprintf("%s/%s", filePath, file.Name()), "", &schema); err != nil {
 			return errors.Wrapf(err, "Could not load file [%s]", fileName)
 		}
 
 		schemaName := strings.TrimSuffix(fileName, filepath.Ext(fileName))
 
-		if err := client.WriteSchema(schemaName, schema); err != nil {
-			return errors.Wrapf(err, "Could not import Schemas [%s]", fileName)
-		}
-		golog.Infof("imported schema from file '%s'", filePath)
+		tasks = append(tasks, bulk.Task{
+			Name: fmt.Sprintf("schema [%s]", schemaName),
+			Run: func() error {
+				if err := client.WriteSchema(schemaName, schema); err != nil {
+					return errors.Wrapf(err, "Could not import Schemas [%s]", fileName)
+				}
+				golog.Infof("imported schema from file '%s'", fileName)
+				return nil
+			},
+		})
 	}
 
-	return nil
+	return bulk.RunAndReport(cmd, tasks)
 }
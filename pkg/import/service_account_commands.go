@@ -9,6 +9,7 @@ import (
 	"github.com/lensesio/lenses-go/pkg/api"
 	config "github.com/lensesio/lenses-go/pkg/configs"
 	"github.com/lensesio/lenses-go/pkg/utils"
+	v1 "github.com/lensesio/lenses-go/v1"
 	"github.com/spf13/cobra"
 )
 
@@ -39,7 +40,7 @@ func NewImportServiceAccountsCommand() *cobra.Command {
 	return cmd
 }
 
-func loadServiceAccounts(client *api.Client, cmd *cobra.Command, loadpath string) error {
+func loadServiceAccounts(client v1.Client, cmd *cobra.Command, loadpath string) error {
 	golog.Infof("Loading service accounts from [%s]", loadpath)
 	files, err := utils.FindFiles(loadpath)
 	if err != nil {
@@ -55,7 +56,7 @@ func loadServiceAccounts(client *api.Client, cmd *cobra.Command, loadpath string
 	for _, file := range files {
 
 		var svcacc api.ServiceAccount
-		if err := bite.LoadFile(cmd, fmt.Sprintf("%s/%s", loadpath, file.Name()), &svcacc); err != nil {
+		if err := config.LoadManifest(cmd, fmt.Sprintf("%s/%s", loadpath, file.Name()), "", &svcacc); err != nil {
 			golog.Errorf("Error loading file [%s]", loadpath)
 			return err
 		}
@@ -7,8 +7,11 @@ import (
 	"github.com/lensesio/bite"
 	"github.com/lensesio/lenses-go/pkg"
 	"github.com/lensesio/lenses-go/pkg/api"
+	"github.com/lensesio/lenses-go/pkg/bulk"
 	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/manifest"
 	"github.com/lensesio/lenses-go/pkg/utils"
+	v1 "github.com/lensesio/lenses-go/v1"
 	"github.com/spf13/cobra"
 )
 
@@ -40,7 +43,7 @@ func NewImportTopicsCommand() *cobra.Command {
 	return cmd
 }
 
-func loadTopics(client *api.Client, cmd *cobra.Command, loadpath string) error {
+func loadTopics(client v1.Client, cmd *cobra.Command, loadpath string) error {
 	golog.Infof("Loading topics from [%s]", loadpath)
 
 	remoteTopics, err := client.GetTopics()
@@ -74,47 +77,57 @@ func loadTopics(client *api.Client, cmd *cobra.Command, loadpath string) error {
 	if err != nil {
 		return err
 	}
+
+	var tasks []bulk.Task
 	for _, file := range files {
 		var topicFromFile api.CreateTopicPayload
-		if err := bite.LoadFile(cmd, fmt.Sprintf("%s/%s", loadpath, file.Name()), &topicFromFile); err != nil {
+		if err := config.LoadManifest(cmd, fmt.Sprintf("%s/%s", loadpath, file.Name()), manifest.KindTopic, &topicFromFile); err != nil {
 			return err
 		}
 
-		// if target imported topic exists then compare it with the instance found on the server
-		if topicValue, ok := simplyfiedRemoteTopics[topicFromFile.TopicName]; ok {
+		topicValue, exists := simplyfiedRemoteTopics[topicFromFile.TopicName]
+		tasks = append(tasks, bulk.Task{
+			Name: fmt.Sprintf("topic [%s]", topicFromFile.TopicName),
+			Run: func() error {
+				// If topic doesn't exist on the remote server then import it as new
+				if !exists {
+					if err := client.CreateTopic(topicFromFile.TopicName, topicFromFile.Replication, topicFromFile.Partitions, topicFromFile.Configs); err != nil {
+						return err
+					}
 
-			// compare the partition values
-			if topicValue.partitions != topicFromFile.Partitions {
-				if err := client.UpdateTopicPartitions(topicFromFile.TopicName, topicFromFile.Partitions); err != nil {
-					return err
+					golog.Infof("Created topic [%s]", topicFromFile.TopicName)
+					return nil
 				}
 
-				golog.Infof("Updated topic '%s' partitions with new value '%v'", topicFromFile.TopicName, topicFromFile.Partitions)
-			}
+				// if target imported topic exists then compare it with the instance found on the server
 
-			// compare the config from imported file with the config on the remote server
-			for k, v := range topicFromFile.Configs {
-
-				// If at least one config value is different then perform a single PUT on all config
-				if v != topicValue.configs[k] {
-					if err := client.UpdateTopicConfig(topicFromFile.TopicName, []api.KV{topicFromFile.Configs}); err != nil {
+				// compare the partition values
+				if topicValue.partitions != topicFromFile.Partitions {
+					if err := client.UpdateTopicPartitions(topicFromFile.TopicName, topicFromFile.Partitions); err != nil {
 						return err
 					}
 
-					golog.Infof("Updated topic '%s' config", topicFromFile.TopicName)
-					break
+					golog.Infof("Updated topic '%s' partitions with new value '%v'", topicFromFile.TopicName, topicFromFile.Partitions)
 				}
 
-			}
-		} else {
-			// If topic doesn't exist on the remote server then import it as new
-			if err := client.CreateTopic(topicFromFile.TopicName, topicFromFile.Replication, topicFromFile.Partitions, topicFromFile.Configs); err != nil {
-				return err
-			}
+				// compare the config from imported file with the config on the remote server
+				for k, v := range topicFromFile.Configs {
 
-			golog.Infof("Created topic [%s]", topicFromFile.TopicName)
-		}
+					// If at least one config value is different then perform a single PUT on all config
+					if v != topicValue.configs[k] {
+						if err := client.UpdateTopicConfig(topicFromFile.TopicName, []api.KV{topicFromFile.Configs}); err != nil {
+							return err
+						}
+
+						golog.Infof("Updated topic '%s' config", topicFromFile.TopicName)
+						break
+					}
+				}
+
+				return nil
+			},
+		})
 	}
 
-	return nil
+	return bulk.RunAndReport(cmd, tasks)
 }
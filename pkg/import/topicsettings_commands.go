@@ -9,6 +9,7 @@ import (
 	"github.com/lensesio/lenses-go/pkg/api"
 	config "github.com/lensesio/lenses-go/pkg/configs"
 	"github.com/lensesio/lenses-go/pkg/utils"
+	v1 "github.com/lensesio/lenses-go/v1"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
@@ -46,7 +47,7 @@ func ImportTopicSettingsCmd() *cobra.Command {
 }
 
 // ReadTopicSettings to read for each file and pass the topic-settings
-func ReadTopicSettings(client *api.Client, cmd *cobra.Command, filePath string) error {
+func ReadTopicSettings(client v1.Client, cmd *cobra.Command, filePath string) error {
 	files, err := utils.FindFiles(filePath)
 	if err != nil {
 		return err
@@ -55,7 +56,7 @@ func ReadTopicSettings(client *api.Client, cmd *cobra.Command, filePath string)
 	for _, file := range files {
 		var settings api.TopicSettingsRequest
 		var fileName = file.Name()
-		if err := bite.LoadFile(cmd, fmt.Sprintf("%s/%s", filePath, file.Name()), &settings); err != nil {
+		if err := config.LoadManifest(cmd, fmt.Sprintf("%s/%s", filePath, file.Name()), "", &settings); err != nil {
 			return errors.Wrapf(err, utils.RED("Could not load file [%s]"), fileName)
 		}
 
@@ -8,6 +8,7 @@ import (
 	"github.com/lensesio/bite"
 	"github.com/lensesio/lenses-go/pkg/api"
 	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/printer"
 	"github.com/spf13/cobra"
 )
 
@@ -25,10 +26,12 @@ func NewGroupsCommand() *cobra.Command {
 				golog.Errorf("Failed to find groups. [%s]", err.Error())
 				return err
 			}
-			return bite.PrintObject(cmd, groups)
+			return printer.PrintObject(cmd, groups)
 		},
 	}
 
+	printer.CanSelect(root)
+
 	root.AddCommand(NewGetGroupCommand())
 	root.AddCommand(NewCreateGroupCommand())
 	root.AddCommand(NewDeleteGroupCommand())
@@ -155,6 +158,10 @@ func NewDeleteGroupCommand() *cobra.Command {
 				return err
 			}
 
+			if err := config.Confirm("group", name); err != nil {
+				return err
+			}
+
 			if err := config.Client.DeleteGroup(name); err != nil {
 				return fmt.Errorf("Failed to delete group [%s]. [%s]", name, err.Error())
 			}
@@ -7,6 +7,7 @@ import (
 	"github.com/lensesio/bite"
 	"github.com/lensesio/lenses-go/pkg/api"
 	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/printer"
 	"github.com/spf13/cobra"
 )
 
@@ -24,10 +25,12 @@ func NewServiceAccountsCommand() *cobra.Command {
 				golog.Errorf("Failed to find groups. [%s]", err.Error())
 				return err
 			}
-			return bite.PrintObject(cmd, svcaccs)
+			return printer.PrintObject(cmd, svcaccs)
 		},
 	}
 
+	printer.CanSelect(root)
+
 	root.AddCommand(NewGetServiceAccountCommand())
 	root.AddCommand(NewCreateServiceAccountCommand())
 	root.AddCommand(NewUpdateServiceAccountCommand())
@@ -138,6 +141,10 @@ func NewDeleteServiceAccountCommand() *cobra.Command {
 				return err
 			}
 
+			if err := config.Confirm("service account", name); err != nil {
+				return err
+			}
+
 			if err := config.Client.DeleteServiceAccount(name); err != nil {
 				return fmt.Errorf("Failed to delete service account [%s]. [%s]", name, err.Error())
 			}
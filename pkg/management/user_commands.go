@@ -7,6 +7,7 @@ import (
 	"github.com/lensesio/bite"
 	"github.com/lensesio/lenses-go/pkg/api"
 	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/printer"
 	"github.com/lensesio/lenses-go/pkg/utils"
 	"github.com/spf13/cobra"
 )
@@ -43,13 +44,14 @@ users --groups Group1 --groups Group2
 						filteredUsers = append(filteredUsers, filteredUser)
 					}
 				}
-				return bite.PrintObject(cmd, filteredUsers)
+				return printer.PrintObject(cmd, filteredUsers)
 			}
-			return bite.PrintObject(cmd, users)
+			return printer.PrintObject(cmd, users)
 		},
 	}
 
 	root.Flags().StringArrayVar(&groupNames, "groups", []string{}, `Group name`)
+	printer.CanSelect(root)
 
 	root.AddCommand(NewGetUserCommand())
 	root.AddCommand(NewCreateUserCommand())
@@ -182,6 +184,10 @@ func NewDeleteUserCommand() *cobra.Command {
 				return err
 			}
 
+			if err := config.Confirm("user", username); err != nil {
+				return err
+			}
+
 			if err := config.Client.DeleteUser(username); err != nil {
 				return fmt.Errorf("Failed to delete user [%s]. [%s]", username, err.Error())
 			}
@@ -0,0 +1,183 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// jsonLines returns the line of each key and array item of a JSON document, by their path.
+func jsonLines(b []byte) map[string]int {
+	lines := map[string]int{"": 1}
+	dec := json.NewDecoder(bytes.NewReader(b))
+
+	// lineAt returns the line of the next token after the "offset".
+	lineAt := func(offset int64) int {
+		i := int(offset)
+		for i < len(b) && strings.IndexByte(" \t\r\n,:", b[i]) >= 0 {
+			i++
+		}
+		return bytes.Count(b[:i], []byte("\n")) + 1
+	}
+
+	var walk func(path string) bool
+	walk = func(path string) bool {
+		tok, err := dec.Token()
+		if err != nil {
+			return false
+		}
+
+		switch tok {
+		case json.Delim('{'):
+			for dec.More() {
+				line := lineAt(dec.InputOffset())
+				key, err := dec.Token()
+				if err != nil {
+					return false
+				}
+
+				child := childPath(path, key.(string))
+				lines[child] = line
+				if !walk(child) {
+					return false
+				}
+			}
+			_, err = dec.Token()
+		case json.Delim('['):
+			for i := 0; dec.More(); i++ {
+				child := indexPath(path, i)
+				lines[child] = lineAt(dec.InputOffset())
+				if !walk(child) {
+					return false
+				}
+			}
+			_, err = dec.Token()
+		}
+
+		return err == nil
+	}
+
+	walk("")
+	return lines
+}
+
+// yamlFrame is an open mapping key or sequence item of the `yamlLines`.
+type yamlFrame struct {
+	indent int
+	path   string
+	item   bool // a sequence item.
+	open   bool // a key without an inline value, its children may follow.
+}
+
+// yamlLines returns the line of each key and sequence item of a YAML document, by their path.
+// It reads the block style the manifests are written in, the values of the flow style, i.e `[a, b]`,
+// share the line of their key.
+func yamlLines(b []byte) map[string]int {
+	var (
+		lines       = map[string]int{"": 1}
+		stack       = []yamlFrame{{indent: -1, open: true}}
+		counters    = make(map[string]int)
+		blockIndent = -1 // the indent of the key of a literal or folded block, its lines are skipped.
+	)
+
+	for n, raw := range strings.Split(string(b), "\n") {
+		line := strings.TrimRight(raw, " \t\r")
+		rest := strings.TrimLeft(line, " ")
+		if rest == "" || rest[0] == '#' || rest == "---" || rest == "..." {
+			continue
+		}
+
+		pos := len(line) - len(rest)
+		if blockIndent >= 0 {
+			if pos > blockIndent {
+				continue
+			}
+			blockIndent = -1
+		}
+
+		for {
+			isItem := rest == "-" || strings.HasPrefix(rest, "- ")
+			for len(stack) > 1 {
+				top := stack[len(stack)-1]
+				if top.indent < pos || (isItem && top.indent == pos && top.open && !top.item) {
+					// an indentless sequence is the value of the key of the same indent.
+					break
+				}
+				stack = stack[:len(stack)-1]
+			}
+
+			if !isItem {
+				break
+			}
+
+			parent := stack[len(stack)-1].path
+			path := indexPath(parent, counters[parent])
+			counters[parent]++
+			lines[path] = n + 1
+			stack = append(stack, yamlFrame{indent: pos, path: path, item: true, open: true})
+
+			skip := 1
+			for skip < len(rest) && rest[skip] == ' ' {
+				skip++
+			}
+			pos += skip
+			rest = rest[skip:]
+			if rest == "" {
+				break
+			}
+		}
+
+		key, value, ok := yamlKey(rest)
+		if !ok {
+			continue
+		}
+
+		path := childPath(stack[len(stack)-1].path, key)
+		lines[path] = n + 1
+		value = strings.TrimSpace(value)
+		if i := strings.Index(value, " #"); i >= 0 {
+			value = strings.TrimSpace(value[:i])
+		}
+		stack = append(stack, yamlFrame{indent: pos, path: path, open: value == ""})
+
+		if strings.HasPrefix(value, "|") || strings.HasPrefix(value, ">") {
+			blockIndent = pos
+		}
+	}
+
+	return lines
+}
+
+// yamlKey splits a `key: value` line.
+func yamlKey(s string) (key, value string, ok bool) {
+	if s == "" {
+		return
+	}
+
+	if q := s[0]; q == '"' || q == '\'' {
+		end := strings.IndexByte(s[1:], q)
+		if end < 0 {
+			return
+		}
+
+		key, s = s[1:end+1], s[end+2:]
+		if !strings.HasPrefix(s, ":") {
+			return "", "", false
+		}
+		return key, s[1:], true
+	}
+
+	if strings.HasPrefix(s, "{") || strings.HasPrefix(s, "[") {
+		return
+	}
+
+	i := strings.Index(s, ": ")
+	if i < 0 {
+		if !strings.HasSuffix(s, ":") {
+			return
+		}
+		i = len(s) - 1
+	}
+
+	return strings.TrimSpace(s[:i]), s[i+1:], true
+}
@@ -0,0 +1,393 @@
+// Package manifest validates the resource files against the JSON schema of their kind,
+// so the mistakes are reported with their line before any API call is made.
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Violation is a field of a manifest that does not match the schema.
+type Violation struct {
+	// Path is the field's path, i.e `configs.retention` or `[2].principal`, empty for the document itself.
+	Path string
+	// Line is the field's line in the file, 0 if unknown.
+	Line    int
+	Message string
+}
+
+func (v Violation) String() string {
+	path := v.Path
+	if path == "" {
+		path = "(root)"
+	}
+
+	if v.Line > 0 {
+		return fmt.Sprintf("line %d: %s: %s", v.Line, path, v.Message)
+	}
+
+	return fmt.Sprintf("%s: %s", path, v.Message)
+}
+
+// ValidationError is the error of an invalid manifest.
+type ValidationError struct {
+	File       string
+	Kind       string
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid %s manifest [%s]:", e.Kind, e.File)
+	for _, v := range e.Violations {
+		b.WriteString("\n  ")
+		b.WriteString(v.String())
+	}
+
+	return b.String()
+}
+
+// Kinds returns the kinds with a schema.
+func Kinds() []string {
+	kinds := make([]string, 0, len(schemas))
+	for kind := range schemas {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// Validate validates the contents "b" of the "file" against the schema of the "kind",
+// the file is decoded as YAML if "isYAML", otherwise as JSON.
+// It returns a `*ValidationError` with all the violations, or the decode error.
+func Validate(kind, file string, b []byte, isYAML bool) error {
+	raw, ok := schemas[kind]
+	if !ok {
+		return fmt.Errorf("unknown manifest kind [%s]", kind)
+	}
+
+	var s schema
+	if err := json.Unmarshal([]byte(raw), &s); err != nil {
+		return fmt.Errorf("schema of [%s]: %v", kind, err)
+	}
+
+	var (
+		doc   interface{}
+		lines map[string]int
+	)
+
+	if isYAML {
+		if err := yaml.Unmarshal(b, &doc); err != nil {
+			return err
+		}
+		doc = normalize(doc)
+		lines = yamlLines(b)
+	} else {
+		if err := json.Unmarshal(b, &doc); err != nil {
+			return err
+		}
+		lines = jsonLines(b)
+	}
+
+	v := &validator{caseInsensitive: !isYAML}
+	s.validate(v, doc, "")
+	if len(v.violations) == 0 {
+		return nil
+	}
+
+	for i := range v.violations {
+		v.violations[i].Line = lineOf(lines, v.violations[i].Path)
+	}
+
+	sort.SliceStable(v.violations, func(i, j int) bool {
+		return v.violations[i].Line < v.violations[j].Line
+	})
+
+	return &ValidationError{File: file, Kind: kind, Violations: v.violations}
+}
+
+// normalize converts the maps decoded by the yaml package to maps with string keys, as the JSON ones.
+func normalize(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, value := range t {
+			m[fmt.Sprint(k)] = normalize(value)
+		}
+		return m
+	case []interface{}:
+		for i := range t {
+			t[i] = normalize(t[i])
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+func childPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
+
+func indexPath(path string, i int) string {
+	return fmt.Sprintf("%s[%d]", path, i)
+}
+
+// lineOf returns the line of the "path" or of its closest parent.
+func lineOf(lines map[string]int, path string) int {
+	for {
+		if line, ok := lines[path]; ok {
+			return line
+		}
+
+		i := strings.LastIndexAny(path, ".[")
+		if i <= 0 {
+			return lines[""]
+		}
+		path = path[:i]
+	}
+}
+
+// typeList is the `type` of a schema, a single type or a list of them.
+type typeList []string
+
+func (t *typeList) UnmarshalJSON(b []byte) error {
+	var single string
+	if err := json.Unmarshal(b, &single); err == nil {
+		*t = typeList{single}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(b, &list); err != nil {
+		return err
+	}
+
+	*t = list
+	return nil
+}
+
+// schema is the subset of the JSON Schema the manifests need.
+type schema struct {
+	Type                 typeList           `json:"type"`
+	Properties           map[string]*schema `json:"properties"`
+	Required             []string           `json:"required"`
+	AdditionalProperties *bool              `json:"additionalProperties"`
+	Items                *schema            `json:"items"`
+	Minimum              *float64           `json:"minimum"`
+	MinLength            *int               `json:"minLength"`
+	Aliases              map[string]string  `json:"x-aliases"`
+}
+
+type validator struct {
+	caseInsensitive bool
+	violations      []Violation
+}
+
+func (v *validator) addf(path, format string, args ...interface{}) {
+	v.violations = append(v.violations, Violation{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func typeOf(value interface{}) string {
+	switch t := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case int, int64, uint64:
+		return "integer"
+	case float64:
+		if t == math.Trunc(t) && !math.IsInf(t, 0) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func (s *schema) allows(typ string) bool {
+	if len(s.Type) == 0 {
+		return true
+	}
+
+	for _, t := range s.Type {
+		if t == typ || (t == "number" && typ == "integer") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func toFloat(value interface{}) float64 {
+	switch t := value.(type) {
+	case int:
+		return float64(t)
+	case int64:
+		return float64(t)
+	case uint64:
+		return float64(t)
+	case float64:
+		return t
+	default:
+		return 0
+	}
+}
+
+func (s *schema) validate(v *validator, value interface{}, path string) {
+	// an empty value is the same as a missing one, the required check reports it.
+	if value == nil {
+		return
+	}
+
+	typ := typeOf(value)
+	if !s.allows(typ) {
+		v.addf(path, "must be %s, got %s", strings.Join(s.Type, " or "), typ)
+		return
+	}
+
+	switch typ {
+	case "string":
+		if s.MinLength != nil && len(value.(string)) < *s.MinLength {
+			v.addf(path, "must not be empty")
+		}
+	case "integer", "number":
+		if s.Minimum != nil && toFloat(value) < *s.Minimum {
+			v.addf(path, "must be at least %v, got %v", *s.Minimum, value)
+		}
+	case "array":
+		if s.Items != nil {
+			for i, item := range value.([]interface{}) {
+				s.Items.validate(v, item, indexPath(path, i))
+			}
+		}
+	case "object":
+		s.validateObject(v, value.(map[string]interface{}), path)
+	}
+}
+
+// property returns the name of the "key" in the schema's properties, resolving the aliases.
+func (s *schema) property(key string, caseInsensitive bool) (string, bool) {
+	if alias, ok := s.Aliases[key]; ok {
+		key = alias
+	}
+
+	if _, ok := s.Properties[key]; ok {
+		return key, true
+	}
+
+	if caseInsensitive {
+		for name := range s.Properties {
+			if strings.EqualFold(name, key) {
+				return name, true
+			}
+		}
+
+		for alias, name := range s.Aliases {
+			if strings.EqualFold(alias, key) {
+				return name, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+func (s *schema) validateObject(v *validator, obj map[string]interface{}, path string) {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	present := make(map[string]bool, len(obj))
+	for _, key := range keys {
+		name, ok := s.property(key, v.caseInsensitive)
+		if !ok {
+			if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				msg := "unknown field"
+				if suggestion := s.suggest(key); suggestion != "" {
+					msg += fmt.Sprintf(", did you mean [%s]?", suggestion)
+				}
+				v.addf(childPath(path, key), msg)
+			}
+			continue
+		}
+
+		if obj[key] != nil {
+			present[name] = true
+		}
+		s.Properties[name].validate(v, obj[key], childPath(path, key))
+	}
+
+	for _, name := range s.Required {
+		if !present[name] {
+			v.addf(path, "missing required field [%s]", name)
+		}
+	}
+}
+
+// suggest returns the property closest to the unknown "key", if any is close enough.
+func (s *schema) suggest(key string) string {
+	var (
+		best     string
+		bestDist = 3
+	)
+
+	for name := range s.Properties {
+		if d := distance(strings.ToLower(key), strings.ToLower(name)); d < bestDist || (d == bestDist && name < best && best != "") {
+			best, bestDist = name, d
+		}
+	}
+
+	return best
+}
+
+// distance is the Levenshtein distance of "a" and "b".
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
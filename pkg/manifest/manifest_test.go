@@ -0,0 +1,142 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func violations(t *testing.T, err error) []string {
+	t.Helper()
+
+	if err == nil {
+		return nil
+	}
+
+	verr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("expected a validation error, got [%v]", err)
+	}
+
+	out := make([]string, len(verr.Violations))
+	for i, v := range verr.Violations {
+		out[i] = v.String()
+	}
+	return out
+}
+
+func TestValidateYAML(t *testing.T) {
+	topic := `# orders
+name: orders
+partitions: three
+replicaton: 3
+configs:
+  retention.ms: "86400000"
+description: |
+  partitions: 1
+`
+	err := Validate(KindTopic, "topic.yml", []byte(topic), true)
+	assert.Equal(t, []string{
+		"line 3: partitions: must be integer, got string",
+		"line 4: replicaton: unknown field, did you mean [replication]?",
+	}, violations(t, err))
+
+	assert.NoError(t, Validate(KindTopic, "topic.yml", []byte("name: orders\npartitions: 3\n"), true))
+
+	err = Validate(KindTopic, "topic.yml", []byte("partitions: 0\n"), true)
+	assert.Equal(t, []string{
+		"line 1: partitions: must be at least 1, got 0",
+		"line 1: (root): missing required field [name]",
+	}, violations(t, err))
+}
+
+func TestValidateYAMLSequences(t *testing.T) {
+	acls := `- permissionType: Allow
+  principal: User:bob
+  operation: READ
+  resourceType: TOPIC
+  patternType: LITERAL
+  resourceName: orders
+  host: "*"
+- permissionType: Allow
+  principal: User:alice
+  operation: READ
+  resourceType: TOPIC
+  resourceName: payments
+  hosts: "*"
+`
+	err := Validate(KindACLs, "acls.yml", []byte(acls), true)
+	assert.Equal(t, []string{
+		"line 8: [1]: missing required field [patternType]",
+		"line 13: [1].hosts: unknown field, did you mean [host]?",
+	}, violations(t, err))
+
+	quotas := `
+- type: USERS
+  config:
+    producerByteRate: 100000
+    consumerbyterate: 100000
+`
+	err = Validate(KindQuotas, "quotas.yml", []byte(quotas), true)
+	assert.Equal(t, []string{
+		"line 5: [0].config.consumerbyterate: unknown field, did you mean [consumerByteRate]?",
+	}, violations(t, err))
+
+	policy := `name: pii
+fields:
+- email
+- 42
+`
+	err = Validate(KindPolicy, "policy.yml", []byte(policy), true)
+	assert.Equal(t, []string{"line 4: fields[1]: must be string, got integer"}, violations(t, err))
+}
+
+func TestValidateJSON(t *testing.T) {
+	topic := `{
+  "topicName": "orders",
+  "partitions": 1.5,
+  "Replication": 3
+}`
+	err := Validate(KindTopic, "topic.json", []byte(topic), false)
+	assert.Equal(t, []string{"line 3: partitions: must be integer, got number"}, violations(t, err))
+
+	connector := `{"ClusterName": "dev", "Name": "sink", "Config": {"tasks.max": 1}}`
+	assert.NoError(t, Validate(KindConnector, "connector.json", []byte(connector), false))
+
+	processor := `{
+  "name": "dedup",
+  "sql": ""
+}`
+	err = Validate(KindProcessor, "processor.json", []byte(processor), false)
+	assert.Equal(t, []string{"line 3: sql: must not be empty"}, violations(t, err))
+
+	err = Validate(KindProcessor, "processor.json", []byte(`{"name": `), false)
+	if assert.Error(t, err) {
+		_, ok := err.(*ValidationError)
+		assert.False(t, ok)
+	}
+}
+
+func TestYAMLLines(t *testing.T) {
+	lines := yamlLines([]byte(`steps:
+- name: a
+  args:
+    - x
+    - y
+- name: b
+"quoted key": 1
+`))
+
+	assert.Equal(t, map[string]int{
+		"":                 1,
+		"steps":            1,
+		"steps[0]":         2,
+		"steps[0].name":    2,
+		"steps[0].args":    3,
+		"steps[0].args[0]": 4,
+		"steps[0].args[1]": 5,
+		"steps[1]":         6,
+		"steps[1].name":    6,
+		"quoted key":       7,
+	}, lines)
+}
@@ -0,0 +1,126 @@
+package manifest
+
+// The kinds of the resource manifests with a schema.
+const (
+	KindTopic     = "topic"
+	KindQuotas    = "quotas"
+	KindConnector = "connector"
+	KindProcessor = "processor"
+	KindACL       = "acl"
+	KindACLs      = "acls"
+	KindPolicy    = "policy"
+)
+
+// The schemas are JSON Schema documents of the YAML form of the manifests, the `x-aliases` of an object
+// are the keys of its JSON form that differ, i.e the `topicName` of a topic's `name`.
+// The JSON files are matched case-insensitively, as the `encoding/json` does.
+
+const topicSchema = `{
+  "type": "object",
+  "additionalProperties": false,
+  "x-aliases": {"topicName": "name"},
+  "required": ["name"],
+  "properties": {
+    "name": {"type": "string", "minLength": 1},
+    "replication": {"type": "integer", "minimum": 1},
+    "partitions": {"type": "integer", "minimum": 1},
+    "description": {"type": "string"},
+    "configs": {"type": "object"}
+  }
+}`
+
+const quotaSchema = `{
+  "type": "object",
+  "additionalProperties": false,
+  "required": ["type", "config"],
+  "properties": {
+    "type": {"type": "string", "minLength": 1},
+    "user": {"type": "string"},
+    "client": {"type": "string"},
+    "config": {
+      "type": "object",
+      "additionalProperties": false,
+      "x-aliases": {
+        "producer_byte_rate": "producerByteRate",
+        "consumer_byte_rate": "consumerByteRate",
+        "request_percentage": "requestPercentage"
+      },
+      "properties": {
+        "producerByteRate": {"type": ["string", "integer"]},
+        "consumerByteRate": {"type": ["string", "integer"]},
+        "requestPercentage": {"type": ["string", "number"]}
+      }
+    }
+  }
+}`
+
+const connectorSchema = `{
+  "type": "object",
+  "additionalProperties": false,
+  "required": ["name", "config"],
+  "properties": {
+    "clusterName": {"type": "string"},
+    "name": {"type": "string", "minLength": 1},
+    "config": {"type": "object"}
+  }
+}`
+
+const processorSchema = `{
+  "type": "object",
+  "additionalProperties": false,
+  "required": ["name", "sql"],
+  "properties": {
+    "name": {"type": "string", "minLength": 1},
+    "sql": {"type": "string", "minLength": 1},
+    "runnerCount": {"type": "integer", "minimum": 1},
+    "cluster": {"type": "string"},
+    "namespace": {"type": "string"},
+    "pipeline": {"type": "string"},
+    "processorId": {"type": "string"}
+  }
+}`
+
+const aclSchema = `{
+  "type": "object",
+  "additionalProperties": false,
+  "required": ["permissionType", "principal", "operation", "resourceType", "patternType", "resourceName"],
+  "properties": {
+    "permissionType": {"type": "string", "minLength": 1},
+    "principal": {"type": "string", "minLength": 1},
+    "operation": {"type": "string", "minLength": 1},
+    "resourceType": {"type": "string", "minLength": 1},
+    "patternType": {"type": "string", "minLength": 1},
+    "resourceName": {"type": "string", "minLength": 1},
+    "host": {"type": "string"}
+  }
+}`
+
+// policySchema accepts the read-only fields of the exported policies as well.
+const policySchema = `{
+  "type": "object",
+  "additionalProperties": false,
+  "required": ["name"],
+  "properties": {
+    "id": {"type": "string"},
+    "name": {"type": "string", "minLength": 1},
+    "category": {"type": "string"},
+    "impactType": {"type": "string"},
+    "obfuscation": {"type": "string"},
+    "datasets": {"type": "array", "items": {"type": "string"}},
+    "fields": {"type": "array", "items": {"type": "string"}},
+    "impact": {"type": "object"},
+    "lastUpdated": {"type": "string"},
+    "lastUpdatedUser": {"type": "string"},
+    "versions": {"type": "integer"}
+  }
+}`
+
+var schemas = map[string]string{
+	KindTopic:     topicSchema,
+	KindQuotas:    `{"type": "array", "items": ` + quotaSchema + `}`,
+	KindConnector: connectorSchema,
+	KindProcessor: processorSchema,
+	KindACL:       aclSchema,
+	KindACLs:      `{"type": "array", "items": ` + aclSchema + `}`,
+	KindPolicy:    policySchema,
+}
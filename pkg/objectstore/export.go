@@ -0,0 +1,266 @@
+package objectstore
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lensesio/lenses-go/pkg/sink"
+)
+
+// The formats of the exported objects.
+const (
+	FormatJSON = "json" // a JSON record per line.
+	FormatCSV  = "csv"  // a header and a line per record, of its partition, offset, timestamp, key, value and headers.
+	// FormatParquet is a Parquet file of the columns of the CSV format, its records are buffered as JSON lines
+	// and written as a single row group on upload.
+	FormatParquet = "parquet"
+)
+
+// The ways to partition the exported objects.
+const (
+	PartitionByNone      = ""
+	PartitionByDate      = "date"      // by the records' date, the "date=2006-01-02/" prefix.
+	PartitionByPartition = "partition" // by the records' partition, the "partition=N/" prefix.
+)
+
+// CheckpointKey is the key of the `Checkpoint` of an export, next to its objects.
+const CheckpointKey = "_checkpoint.json"
+
+// DefaultFlushSize is the buffered bytes which trigger the upload of the objects of an `Exporter`.
+const DefaultFlushSize = 64 << 20
+
+// Checkpoint is the progress of an export, it's written after each upload so a failed export can be resumed.
+type Checkpoint struct {
+	// Offsets are the last exported offset of each partition.
+	Offsets map[int]int `json:"offsets"`
+	// Objects is the number of the exported objects, the sequence of the next one.
+	Objects int   `json:"objects"`
+	Records int64 `json:"records"`
+}
+
+// Filter returns the Lenses SQL condition of the records which are not exported yet, it's empty if none is.
+func (c Checkpoint) Filter() string {
+	if len(c.Offsets) == 0 {
+		return ""
+	}
+
+	partitions := make([]int, 0, len(c.Offsets))
+	for p := range c.Offsets {
+		partitions = append(partitions, p)
+	}
+	sort.Ints(partitions)
+
+	conditions := make([]string, 0, len(partitions)+1)
+	in := make([]string, 0, len(partitions))
+	for _, p := range partitions {
+		conditions = append(conditions, fmt.Sprintf("(_meta.partition = %d AND _meta.offset > %d)", p, c.Offsets[p]))
+		in = append(in, strconv.Itoa(p))
+	}
+	conditions = append(conditions, fmt.Sprintf("_meta.partition NOT IN (%s)", strings.Join(in, ", ")))
+
+	return strings.Join(conditions, " OR ")
+}
+
+// Exporter writes records to objects of a `Store`, in the "Format" and partitioned by the "PartitionBy".
+// The records are buffered and uploaded, along with the checkpoint, once the buffered bytes reach the "FlushSize".
+type Exporter struct {
+	Store       Store
+	Format      string
+	PartitionBy string
+	FlushSize   int
+
+	checkpoint Checkpoint
+	offsets    map[int]int // the offsets of the buffered records, the checkpoint's once uploaded.
+	buckets    map[string]*bytes.Buffer
+	buffered   int
+	pending    int64 // the buffered records.
+}
+
+// NewExporter returns an `Exporter` which resumes from the checkpoint of the "store", if there is one.
+func NewExporter(store Store, format, partitionBy string) (*Exporter, error) {
+	switch format {
+	case FormatJSON, FormatCSV, FormatParquet:
+	default:
+		return nil, fmt.Errorf("unknown format [%s], expected json, csv or parquet", format)
+	}
+
+	switch partitionBy {
+	case PartitionByNone, PartitionByDate, PartitionByPartition:
+	default:
+		return nil, fmt.Errorf("unknown partition by [%s], expected date or partition", partitionBy)
+	}
+
+	e := &Exporter{
+		Store:       store,
+		Format:      format,
+		PartitionBy: partitionBy,
+		FlushSize:   DefaultFlushSize,
+		offsets:     make(map[int]int),
+		buckets:     make(map[string]*bytes.Buffer),
+	}
+
+	b, err := store.Get(CheckpointKey)
+	if err != nil && err != ErrNotExist {
+		return nil, fmt.Errorf("unable to read the checkpoint: [%v]", err)
+	}
+
+	if err == nil {
+		if err = json.Unmarshal(b, &e.checkpoint); err != nil {
+			return nil, fmt.Errorf("invalid checkpoint: [%v]", err)
+		}
+	}
+
+	for p, offset := range e.checkpoint.Offsets {
+		e.offsets[p] = offset
+	}
+
+	return e, nil
+}
+
+// Checkpoint returns the checkpoint of the uploaded records.
+func (e *Exporter) Checkpoint() Checkpoint {
+	return e.checkpoint
+}
+
+func (e *Exporter) bucket(r sink.Record) string {
+	switch e.PartitionBy {
+	case PartitionByDate:
+		return "date=" + r.Time().UTC().Format("2006-01-02")
+	case PartitionByPartition:
+		return "partition=" + strconv.Itoa(r.Metadata.Partition)
+	default:
+		return ""
+	}
+}
+
+// Add buffers the "record" and uploads the buffered objects if they reached the "FlushSize".
+func (e *Exporter) Add(r sink.Record) error {
+	name := e.bucket(r)
+	buf, ok := e.buckets[name]
+	if !ok {
+		buf = new(bytes.Buffer)
+		e.buckets[name] = buf
+	}
+
+	n := buf.Len()
+	if err := e.encode(buf, r, !ok); err != nil {
+		return err
+	}
+
+	e.buffered += buf.Len() - n
+	e.offsets[r.Metadata.Partition] = r.Metadata.Offset
+	e.pending++
+
+	if e.buffered < e.FlushSize {
+		return nil
+	}
+
+	return e.Flush()
+}
+
+func (e *Exporter) encode(buf *bytes.Buffer, r sink.Record, first bool) error {
+	if e.Format == FormatJSON || e.Format == FormatParquet {
+		return json.NewEncoder(buf).Encode(r)
+	}
+
+	w := csv.NewWriter(buf)
+	if first {
+		w.Write([]string{"partition", "offset", "timestamp", "key", "value", "headers"})
+	}
+
+	var headers string
+	if len(r.Headers) > 0 {
+		b, err := json.Marshal(r.Headers)
+		if err != nil {
+			return err
+		}
+		headers = string(b)
+	}
+
+	w.Write([]string{
+		strconv.Itoa(r.Metadata.Partition),
+		strconv.Itoa(r.Metadata.Offset),
+		strconv.FormatInt(r.Time().UnixNano()/1e6, 10),
+		csvField(r.Key),
+		csvField(r.Value),
+		headers,
+	})
+	w.Flush()
+	return w.Error()
+}
+
+// csvField returns the JSON "v" as text, a JSON string is unquoted.
+func csvField(v json.RawMessage) string {
+	var s string
+	if json.Unmarshal(v, &s) == nil {
+		return s
+	}
+
+	if string(v) == "null" {
+		return ""
+	}
+
+	return string(v)
+}
+
+// Flush uploads the buffered objects and then the checkpoint.
+func (e *Exporter) Flush() error {
+	if len(e.buckets) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(e.buckets))
+	for name := range e.buckets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		key := fmt.Sprintf("part-%05d.%s", e.checkpoint.Objects, e.Format)
+		if name != "" {
+			key = name + "/" + key
+		}
+
+		object := e.buckets[name].Bytes()
+		if e.Format == FormatParquet {
+			b, err := toParquet(object)
+			if err != nil {
+				return fmt.Errorf("unable to write the parquet object [%s]: [%v]", key, err)
+			}
+			object = b
+		}
+
+		if err := e.Store.Put(key, object); err != nil {
+			return fmt.Errorf("upload of [%s] failed: [%v]", key, err)
+		}
+
+		// uploaded, a retry of a failed flush neither uploads it again nor overwrites it.
+		delete(e.buckets, name)
+		e.checkpoint.Objects++
+	}
+
+	checkpoint := e.checkpoint
+	checkpoint.Records += e.pending
+	checkpoint.Offsets = make(map[int]int, len(e.offsets))
+	for p, offset := range e.offsets {
+		checkpoint.Offsets[p] = offset
+	}
+
+	b, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	if err = e.Store.Put(CheckpointKey, b); err != nil {
+		return fmt.Errorf("upload of the checkpoint failed: [%v]", err)
+	}
+
+	e.checkpoint = checkpoint
+	e.buffered, e.pending = 0, 0
+	return nil
+}
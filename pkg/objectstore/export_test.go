@@ -0,0 +1,136 @@
+package objectstore
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lensesio/lenses-go/pkg/sink"
+	"github.com/lensesio/lenses-go/pkg/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+func tempDir(t *testing.T) (Dir, func()) {
+	dir, err := ioutil.TempDir("", "lenses-export")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return Dir(dir), func() { os.RemoveAll(dir) }
+}
+
+func exportRecord(partition, offset int, ts float64, value string) sink.Record {
+	return sink.Record{
+		Key:      json.RawMessage(`"k"`),
+		Value:    json.RawMessage(value),
+		Metadata: websocket.MetaData{Partition: partition, Offset: offset, Timestamp: ts},
+	}
+}
+
+func TestCheckpointFilter(t *testing.T) {
+	assert.Equal(t, "", Checkpoint{}.Filter())
+	assert.Equal(t, "(_meta.partition = 0 AND _meta.offset > 10) OR (_meta.partition = 2 AND _meta.offset > 4) OR _meta.partition NOT IN (0, 2)",
+		Checkpoint{Offsets: map[int]int{2: 4, 0: 10}}.Filter())
+}
+
+func TestExporter(t *testing.T) {
+	dir, teardown := tempDir(t)
+	defer teardown()
+
+	e, err := NewExporter(dir, FormatCSV, PartitionByDate)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// 2020-09-13 and 2020-09-14.
+	assert.NoError(t, e.Add(exportRecord(0, 1, 1600000000000, `{"id":1}`)))
+	withHeaders := exportRecord(1, 7, 1600086400000, `"two"`)
+	withHeaders.Headers = websocket.Headers{"source": []byte("web")}
+	assert.NoError(t, e.Add(withHeaders))
+	assert.NoError(t, e.Flush())
+
+	b, err := dir.Get("date=2020-09-13/part-00000.csv")
+	assert.NoError(t, err)
+	assert.Equal(t, "partition,offset,timestamp,key,value,headers\n0,1,1600000000000,k,\"{\"\"id\"\":1}\",\n", string(b))
+
+	b, err = dir.Get("date=2020-09-14/part-00001.csv")
+	assert.NoError(t, err)
+	assert.Equal(t, "partition,offset,timestamp,key,value,headers\n1,7,1600086400000,k,two,\"{\"\"source\"\":\"\"web\"\"}\"\n", string(b))
+
+	// resumes from the checkpoint.
+	e, err = NewExporter(dir, FormatCSV, PartitionByDate)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, Checkpoint{Offsets: map[int]int{0: 1, 1: 7}, Objects: 2, Records: 2}, e.Checkpoint())
+
+	e.FlushSize = 1
+	assert.NoError(t, e.Add(exportRecord(0, 2, 1600000000000, `3`)))
+	_, err = os.Stat(filepath.Join(string(dir), "date=2020-09-13", "part-00002.csv"))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, e.Checkpoint().Objects)
+	assert.Equal(t, 2, e.Checkpoint().Offsets[0])
+}
+
+func TestNewExporterFormat(t *testing.T) {
+	dir, teardown := tempDir(t)
+	defer teardown()
+
+	_, err := NewExporter(dir, "avro", PartitionByNone)
+	assert.EqualError(t, err, "unknown format [avro], expected json, csv or parquet")
+
+	_, err = NewExporter(dir, FormatJSON, "hour")
+	assert.EqualError(t, err, "unknown partition by [hour], expected date or partition")
+}
+
+func TestExporterParquet(t *testing.T) {
+	dir, teardown := tempDir(t)
+	defer teardown()
+
+	e, err := NewExporter(dir, FormatParquet, PartitionByNone)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	withHeaders := exportRecord(1, 7, 1600086400000, `"two"`)
+	withHeaders.Headers = websocket.Headers{"source": []byte("web")}
+	assert.NoError(t, e.Add(exportRecord(0, 1, 1600000000000, `{"id":1}`)))
+	assert.NoError(t, e.Add(withHeaders))
+	assert.NoError(t, e.Flush())
+
+	b, err := dir.Get("part-00000.parquet")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	f, err := buffer.NewBufferFile(b)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	r, err := reader.NewParquetReader(f, new(parquetRecord), 1)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer r.ReadStop()
+
+	rows := make([]parquetRecord, r.GetNumRows())
+	if !assert.NoError(t, r.Read(&rows)) || !assert.Len(t, rows, 2) {
+		return
+	}
+
+	assert.Equal(t, int32(0), rows[0].Partition)
+	assert.Equal(t, int64(1), rows[0].Offset)
+	assert.Equal(t, int64(1600000000000), rows[0].Timestamp)
+	assert.Equal(t, "k", *rows[0].Key)
+	assert.Equal(t, `{"id":1}`, *rows[0].Value)
+	assert.Nil(t, rows[0].Headers)
+
+	assert.Equal(t, int64(7), rows[1].Offset)
+	assert.Equal(t, "two", *rows[1].Value)
+	assert.Equal(t, `{"source":"web"}`, *rows[1].Headers)
+}
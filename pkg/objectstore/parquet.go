@@ -0,0 +1,78 @@
+package objectstore
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+
+	"github.com/lensesio/lenses-go/pkg/sink"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// parquetRecord is a row of a Parquet object, of the columns of the CSV format.
+// The key, value and headers are their JSON text, a JSON string is unquoted.
+type parquetRecord struct {
+	Partition int32   `parquet:"name=partition, type=INT32"`
+	Offset    int64   `parquet:"name=offset, type=INT64"`
+	Timestamp int64   `parquet:"name=timestamp, type=TIMESTAMP_MILLIS"`
+	Key       *string `parquet:"name=key, type=UTF8, repetitiontype=OPTIONAL"`
+	Value     *string `parquet:"name=value, type=UTF8, repetitiontype=OPTIONAL"`
+	Headers   *string `parquet:"name=headers, type=UTF8, repetitiontype=OPTIONAL"`
+}
+
+func optionalField(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return &s
+}
+
+// toParquet returns the Parquet object of the JSON lines "records" of a bucket,
+// they are buffered as JSON and written as a single row group on upload.
+func toParquet(records []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := writer.NewParquetWriterFromWriter(&buf, new(parquetRecord), 1)
+	if err != nil {
+		return nil, err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(records))
+	scanner.Buffer(nil, len(records)+1)
+	for scanner.Scan() {
+		var r sink.Record
+		if err = json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return nil, err
+		}
+
+		row := parquetRecord{
+			Partition: int32(r.Metadata.Partition),
+			Offset:    int64(r.Metadata.Offset),
+			Timestamp: r.Time().UnixNano() / 1e6,
+			Key:       optionalField(csvField(r.Key)),
+			Value:     optionalField(csvField(r.Value)),
+		}
+
+		if len(r.Headers) > 0 {
+			b, err := json.Marshal(r.Headers)
+			if err != nil {
+				return nil, err
+			}
+			row.Headers = optionalField(string(b))
+		}
+
+		if err = w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if err = w.WriteStop(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
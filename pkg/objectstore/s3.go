@@ -0,0 +1,296 @@
+package objectstore
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kataras/golog"
+)
+
+// The defaults of the `S3` store.
+const (
+	DefaultPartSize     int64 = 16 << 20
+	DefaultMaxRetries         = 3
+	DefaultRetryBackoff       = time.Second
+)
+
+// minPartSize is the minimum size of a multipart upload's part, except the last one.
+const minPartSize int64 = 5 << 20
+
+// S3 is a store of the objects of an Amazon S3 bucket, or of any service compatible with its REST API,
+// i.e MinIO or the XML API of Google Cloud Storage. Its requests are path-style.
+type S3 struct {
+	// Endpoint is the base URL of the service, i.e https://s3.eu-west-1.amazonaws.com.
+	Endpoint string
+	Region   string
+	Bucket   string
+	Prefix   string
+	// Credentials sign the requests with the Signature Version 4.
+	Credentials Credentials
+	// Token, if set, authorizes the requests with an OAuth 2.0 bearer token instead of the "Credentials".
+	Token string
+	// PartSize is the size of a multipart upload's parts, `DefaultPartSize` by default.
+	// The objects which are not larger are uploaded with a single request.
+	PartSize int64
+	// MaxRetries is the retries of a failed request, `DefaultMaxRetries` by default, negative does not retry.
+	MaxRetries int
+	// Client defaults to a client with a 5 minutes timeout.
+	Client *http.Client
+
+	backoff time.Duration
+	now     func() time.Time
+}
+
+// NewS3 returns the `S3` store of the "bucket" with the configuration of the AWS environment variables:
+// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN, AWS_REGION (or AWS_DEFAULT_REGION, us-east-1 by default)
+// and AWS_ENDPOINT_URL for an S3 compatible service.
+func NewS3(bucket, prefix string) *S3 {
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	endpoint := os.Getenv("AWS_ENDPOINT_URL")
+	if endpoint == "" {
+		endpoint = "https://s3." + region + ".amazonaws.com"
+	}
+
+	return &S3{
+		Endpoint: endpoint,
+		Region:   region,
+		Bucket:   bucket,
+		Prefix:   prefix,
+		Credentials: Credentials{
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		},
+	}
+}
+
+// GCSEndpoint is the endpoint of the XML API of Google Cloud Storage.
+const GCSEndpoint = "https://storage.googleapis.com"
+
+// NewGCS returns the store of the Google Cloud Storage "bucket", through its XML API which is compatible with S3's,
+// authorized by the GOOGLE_OAUTH_ACCESS_TOKEN environment variable, i.e `gcloud auth print-access-token`,
+// or by the GCS_HMAC_ACCESS_KEY_ID and GCS_HMAC_SECRET HMAC keys.
+func NewGCS(bucket, prefix string) (*S3, error) {
+	s := &S3{Endpoint: GCSEndpoint, Region: "auto", Bucket: bucket, Prefix: prefix}
+	if s.Token = os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"); s.Token != "" {
+		return s, nil
+	}
+
+	s.Credentials = Credentials{AccessKeyID: os.Getenv("GCS_HMAC_ACCESS_KEY_ID"), SecretAccessKey: os.Getenv("GCS_HMAC_SECRET")}
+	if s.Credentials.AccessKeyID == "" || s.Credentials.SecretAccessKey == "" {
+		return nil, errors.New("google cloud storage credentials are missing, set GOOGLE_OAUTH_ACCESS_TOKEN or GCS_HMAC_ACCESS_KEY_ID and GCS_HMAC_SECRET")
+	}
+
+	return s, nil
+}
+
+// S3Error is the error response of a request.
+type S3Error struct {
+	StatusCode int    `xml:"-"`
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+}
+
+func (e S3Error) Error() string {
+	return fmt.Sprintf("object store request failed with status code [%d]: [%s: %s]", e.StatusCode, e.Code, e.Message)
+}
+
+func (e S3Error) retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+}
+
+func (s *S3) objectURL(key string, query url.Values) string {
+	key = strings.TrimPrefix(key, "/")
+	if s.Prefix != "" {
+		key = strings.Trim(s.Prefix, "/") + "/" + key
+	}
+
+	u := strings.TrimSuffix(s.Endpoint, "/") + "/" + s.Bucket + "/" + uriEncode(key, true)
+	if len(query) > 0 {
+		u += "?" + canonicalQuery(query)
+	}
+
+	return u
+}
+
+// do sends the request, with retries, and returns its response's header and body.
+func (s *S3) do(method, key string, query url.Values, body []byte) (http.Header, []byte, error) {
+	maxRetries := s.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = DefaultMaxRetries
+	}
+
+	backoff := s.backoff
+	if backoff <= 0 {
+		backoff = DefaultRetryBackoff
+	}
+
+	for retry := 0; ; retry++ {
+		header, respBody, err := s.send(method, key, query, body)
+		if err == nil {
+			return header, respBody, nil
+		}
+
+		var s3Err S3Error
+		if (errors.As(err, &s3Err) && !s3Err.retryable()) || retry >= maxRetries {
+			return nil, nil, err
+		}
+
+		golog.Debugf("objectstore: %s [%s] failed, retrying in [%s]: [%v]", method, key, backoff, err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (s *S3) send(method, key string, query url.Values, body []byte) (http.Header, []byte, error) {
+	req, err := http.NewRequest(method, s.objectURL(key, query), bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if s.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.Token)
+	} else {
+		payloadHash := sha256Hex(body)
+		req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+		now := time.Now
+		if s.now != nil {
+			now = s.now
+		}
+		signV4(req, payloadHash, s.Credentials, s.Region, "s3", now())
+	}
+
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Minute}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		s3Err := S3Error{StatusCode: resp.StatusCode}
+		xml.Unmarshal(respBody, &s3Err)
+		return nil, nil, s3Err
+	}
+
+	return resp.Header, respBody, nil
+}
+
+// Get reads the object of the "key".
+func (s *S3) Get(key string) ([]byte, error) {
+	_, b, err := s.do(http.MethodGet, key, nil, nil)
+	var s3Err S3Error
+	if errors.As(err, &s3Err) && s3Err.StatusCode == http.StatusNotFound {
+		return nil, ErrNotExist
+	}
+
+	return b, err
+}
+
+// Put uploads the object of the "key", with a multipart upload if it's larger than the "PartSize".
+func (s *S3) Put(key string, data []byte) error {
+	partSize := s.PartSize
+	if partSize <= 0 {
+		partSize = DefaultPartSize
+	}
+	if partSize < minPartSize {
+		partSize = minPartSize
+	}
+
+	if int64(len(data)) <= partSize {
+		_, _, err := s.do(http.MethodPut, key, nil, data)
+		return err
+	}
+
+	return s.putMultipart(key, data, partSize)
+}
+
+type initiateMultipartUploadResult struct {
+	UploadID string `xml:"UploadId"`
+}
+
+type completedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+type completeMultipartUpload struct {
+	XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+	Parts   []completedPart `xml:"Part"`
+}
+
+func (s *S3) putMultipart(key string, data []byte, partSize int64) error {
+	_, b, err := s.do(http.MethodPost, key, url.Values{"uploads": {""}}, nil)
+	if err != nil {
+		return err
+	}
+
+	var initiated initiateMultipartUploadResult
+	if err = xml.Unmarshal(b, &initiated); err != nil || initiated.UploadID == "" {
+		return fmt.Errorf("multipart upload of [%s] was not initiated: [%v]", key, err)
+	}
+
+	uploadID := initiated.UploadID
+	complete := completeMultipartUpload{}
+	for n, offset := 1, int64(0); offset < int64(len(data)); n, offset = n+1, offset+partSize {
+		end := offset + partSize
+		if end > int64(len(data)) {
+			end = int64(len(data))
+		}
+
+		query := url.Values{"partNumber": {strconv.Itoa(n)}, "uploadId": {uploadID}}
+		header, _, err := s.do(http.MethodPut, key, query, data[offset:end])
+		if err != nil {
+			s.abort(key, uploadID)
+			return fmt.Errorf("part [%d] of [%s]: %w", n, key, err)
+		}
+
+		complete.Parts = append(complete.Parts, completedPart{PartNumber: n, ETag: header.Get("ETag")})
+	}
+
+	body, err := xml.Marshal(complete)
+	if err != nil {
+		s.abort(key, uploadID)
+		return err
+	}
+
+	if _, _, err = s.do(http.MethodPost, key, url.Values{"uploadId": {uploadID}}, body); err != nil {
+		s.abort(key, uploadID)
+		return err
+	}
+
+	return nil
+}
+
+// abort aborts the multipart upload so its parts are not stored, and charged, forever.
+func (s *S3) abort(key, uploadID string) {
+	if _, _, err := s.do(http.MethodDelete, key, url.Values{"uploadId": {uploadID}}, nil); err != nil {
+		golog.Errorf("objectstore: abort of the multipart upload [%s] of [%s] failed: [%v]", uploadID, key, err)
+	}
+}
@@ -0,0 +1,125 @@
+package objectstore
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeS3 is an in-memory S3 of a single bucket, with multipart uploads.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	parts   map[string][][]byte
+	fail    int // the next requests to fail with a 503.
+	auth    []string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.auth = append(f.auth, r.Header.Get("Authorization"))
+	if f.fail > 0 {
+		f.fail--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`<Error><Code>SlowDown</Code><Message>Reduce your request rate</Message></Error>`))
+		return
+	}
+
+	key := r.URL.Path
+	body, _ := ioutil.ReadAll(r.Body)
+	q := r.URL.Query()
+
+	switch {
+	case r.Method == http.MethodPost && q.Get("uploadId") == "":
+		f.parts[key] = nil
+		fmt.Fprint(w, `<InitiateMultipartUploadResult><UploadId>u1</UploadId></InitiateMultipartUploadResult>`)
+	case r.Method == http.MethodPut && q.Get("uploadId") != "":
+		f.parts[key] = append(f.parts[key], body)
+		w.Header().Set("ETag", fmt.Sprintf(`"etag-%s"`, q.Get("partNumber")))
+	case r.Method == http.MethodPost:
+		var complete completeMultipartUpload
+		xml.Unmarshal(body, &complete)
+		if len(complete.Parts) != len(f.parts[key]) || complete.Parts[1].ETag != `"etag-2"` {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var object []byte
+		for _, p := range f.parts[key] {
+			object = append(object, p...)
+		}
+		f.objects[key] = object
+	case r.Method == http.MethodPut:
+		f.objects[key] = body
+	case r.Method == http.MethodGet:
+		b, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`))
+			return
+		}
+		w.Write(b)
+	}
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *S3, func()) {
+	fake := &fakeS3{objects: make(map[string][]byte), parts: make(map[string][][]byte)}
+	srv := httptest.NewServer(fake)
+
+	s := &S3{
+		Endpoint:    srv.URL,
+		Region:      "us-east-1",
+		Bucket:      "bucket",
+		Prefix:      "exports/",
+		Credentials: Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"},
+		backoff:     time.Millisecond,
+	}
+
+	return fake, s, srv.Close
+}
+
+func TestS3PutGet(t *testing.T) {
+	fake, s, teardown := newFakeS3(t)
+	defer teardown()
+
+	fake.fail = 2
+	assert.NoError(t, s.Put("date=2020-09-13/part-00000.json", []byte(`{"value":1}`)))
+	assert.Equal(t, `{"value":1}`, string(fake.objects["/bucket/exports/date=2020-09-13/part-00000.json"]))
+	assert.True(t, strings.HasPrefix(fake.auth[0], "AWS4-HMAC-SHA256 Credential=AKID/"))
+
+	b, err := s.Get("date=2020-09-13/part-00000.json")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"value":1}`, string(b))
+
+	_, err = s.Get("missing")
+	assert.Equal(t, ErrNotExist, err)
+
+	fake.fail = 10
+	assert.EqualError(t, s.Put("k", nil), "object store request failed with status code [503]: [SlowDown: Reduce your request rate]")
+}
+
+func TestS3PutMultipart(t *testing.T) {
+	fake, s, teardown := newFakeS3(t)
+	defer teardown()
+
+	data := make([]byte, minPartSize+10)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	s.Token = "t0ken"
+	s.PartSize = minPartSize
+	assert.NoError(t, s.Put("big", data))
+	assert.Len(t, fake.parts["/bucket/exports/big"], 2)
+	assert.Equal(t, data, fake.objects["/bucket/exports/big"])
+	assert.Equal(t, "Bearer t0ken", fake.auth[0])
+}
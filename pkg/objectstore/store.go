@@ -0,0 +1,83 @@
+// Package objectstore writes objects to a local directory, to Amazon S3 or to Google Cloud Storage,
+// the destinations of the `topic export` command.
+package objectstore
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotExist is returned by the `Store#Get` when the object does not exist.
+var ErrNotExist = errors.New("objectstore: object does not exist")
+
+// Store writes and reads objects by their key, a slash separated path relative to the store's prefix.
+type Store interface {
+	Put(key string, data []byte) error
+	Get(key string) ([]byte, error)
+}
+
+// Open returns the store of the "rawURL":
+// s3://bucket/prefix for an `S3` store with the credentials of the AWS_* environment variables,
+// gs://bucket/prefix for a Google Cloud Storage one, see `NewGCS`,
+// and file:///path or a path for a `Dir`.
+func Open(rawURL string) (Store, error) {
+	if !strings.Contains(rawURL, "://") {
+		return Dir(rawURL), nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid store url [%s]: [%v]", rawURL, err)
+	}
+
+	prefix := strings.Trim(u.Path, "/")
+	switch u.Scheme {
+	case "file":
+		return Dir(filepath.FromSlash(u.Host + u.Path)), nil
+	case "s3":
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid store url [%s]: the bucket is missing", rawURL)
+		}
+		return NewS3(u.Host, prefix), nil
+	case "gs":
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid store url [%s]: the bucket is missing", rawURL)
+		}
+		return NewGCS(u.Host, prefix)
+	default:
+		return nil, fmt.Errorf("unknown store url scheme [%s], expected s3, gs or file", u.Scheme)
+	}
+}
+
+// Dir is a store of the files of a local directory.
+type Dir string
+
+// Put writes the object to its file, through a temporary file so a failed write does not leave half an object.
+func (d Dir) Put(key string, data []byte) error {
+	path := filepath.Join(string(d), filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// Get reads the object's file.
+func (d Dir) Get(key string) ([]byte, error) {
+	b, err := ioutil.ReadFile(filepath.Join(string(d), filepath.FromSlash(key)))
+	if os.IsNotExist(err) {
+		return nil, ErrNotExist
+	}
+
+	return b, err
+}
@@ -0,0 +1,97 @@
+package plugin
+
+import (
+	"os"
+	"os/exec"
+
+	"github.com/lensesio/bite"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/exitcode"
+	"github.com/spf13/cobra"
+)
+
+// Command returns the command of the plugin "p", it runs its executable with the arguments, the standard streams
+// and the environment of the CLI plus the connection of the current context, see `Env`. It exits with the plugin's exit code.
+func Command(p Plugin) *cobra.Command {
+	return &cobra.Command{
+		Use:                p.Name,
+		Short:              "Plugin " + p.Path,
+		DisableFlagParsing: true,
+		SilenceErrors:      true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			context := ""
+			if config.Manager != nil && config.Manager.Config != nil {
+				context = config.Manager.Config.CurrentContext
+			}
+
+			c := exec.Command(p.Path, args...)
+			c.Stdin, c.Stdout, c.Stderr = os.Stdin, cmd.OutOrStdout(), cmd.ErrOrStderr()
+			c.Env = append(os.Environ(), Env(config.Client, context)...)
+
+			if err := c.Run(); err != nil {
+				if exitErr, ok := err.(*exec.ExitError); ok {
+					return exitcode.WithCode(exitErr.ExitCode(), err)
+				}
+				return err
+			}
+
+			return nil
+		},
+	}
+}
+
+// AddCommands adds the commands of the plugins found on the "pathList" to the "root",
+// except the ones named after a command of the CLI, which can not be overridden.
+func AddCommands(root *cobra.Command, pathList string) {
+	builtin := make(map[string]bool)
+	for _, cmd := range root.Commands() {
+		builtin[cmd.Name()] = true
+		for _, alias := range cmd.Aliases {
+			builtin[alias] = true
+		}
+	}
+
+	for _, p := range Discover(pathList) {
+		if !builtin[p.Name] {
+			root.AddCommand(Command(p))
+		}
+	}
+}
+
+//NewPluginGroupCommand creates the `plugin` command
+func NewPluginGroupCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:              "plugin",
+		Short:            "Manage the plugins, the lenses-cli-<name> executables on the PATH run as lenses-cli <name>",
+		Example:          `plugin list`,
+		SilenceErrors:    true,
+		TraverseChildren: true,
+	}
+
+	cmd.AddCommand(NewPluginListCommand())
+
+	return cmd
+}
+
+//NewPluginListCommand creates the `plugin list` command
+func NewPluginListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:              "list",
+		Short:            "List the plugins found on the PATH",
+		Example:          `plugin list`,
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			plugins := Discover(os.Getenv("PATH"))
+			if len(plugins) == 0 {
+				return bite.PrintInfo(cmd, "No plugins found on the PATH, a plugin is an executable named %s<name>", Prefix)
+			}
+
+			return bite.PrintObject(cmd, plugins)
+		},
+	}
+
+	bite.CanPrintJSON(cmd)
+
+	return cmd
+}
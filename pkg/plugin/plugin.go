@@ -0,0 +1,116 @@
+// Package plugin adds custom commands to the CLI, without forking it: the `lenses-cli-<name>` executables on the PATH,
+// run as `lenses-cli <name>` with the connection of the current context in their environment, like the kubectl plugins,
+// and the Go commands registered by a build of the CLI that imports this package, see `Register`.
+package plugin
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/spf13/cobra"
+)
+
+// Prefix is the prefix of the name of a plugin executable, the rest is the name of its command.
+const Prefix = "lenses-cli-"
+
+// EnvCLI is the environment variable of a plugin with the path of the CLI, so it can run its commands.
+const EnvCLI = "LENSES_CLI"
+
+// Plugin is an executable plugin, found on the PATH.
+type Plugin struct {
+	Name string `json:"name" header:"Name"`
+	Path string `json:"path" header:"Path"`
+}
+
+// Discover returns the plugins of the directories of the "pathList", i.e the PATH, sorted by name.
+// A plugin found in more than one directory is the first one, like the command that the shell would run.
+func Discover(pathList string) []Plugin {
+	var (
+		plugins []Plugin
+		found   = make(map[string]bool)
+	)
+
+	for _, dir := range filepath.SplitList(pathList) {
+		entries, err := ioutil.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasPrefix(entry.Name(), Prefix) {
+				continue
+			}
+
+			name := strings.TrimSuffix(strings.TrimPrefix(entry.Name(), Prefix), ".exe")
+			if name == "" || found[name] {
+				continue
+			}
+
+			path := filepath.Join(dir, entry.Name())
+			if info, err := os.Stat(path); err != nil || info.Mode()&0111 == 0 {
+				continue
+			}
+
+			found[name] = true
+			plugins = append(plugins, Plugin{Name: name, Path: path})
+		}
+	}
+
+	sort.Slice(plugins, func(i, j int) bool {
+		return plugins[i].Name < plugins[j].Name
+	})
+
+	return plugins
+}
+
+// Env returns the environment variables of the connection to the server of the "client" of the "context",
+// the ones the CLI reads, so a plugin, or the CLI run by a plugin, uses the same server and token.
+func Env(client *api.Client, context string) []string {
+	var env []string
+	if context != "" {
+		env = append(env, "LENSES_CLI_CONTEXT="+context)
+	}
+
+	if exe, err := os.Executable(); err == nil {
+		env = append(env, EnvCLI+"="+exe)
+	}
+
+	if client == nil {
+		return env
+	}
+
+	return append(env,
+		config.EnvHost+"="+client.Config.Host,
+		config.EnvBasePath+"="+client.Config.BasePath,
+		config.EnvToken+"="+client.Config.Token,
+		config.EnvInsecure+"="+strconv.FormatBool(client.Config.Insecure),
+	)
+}
+
+var (
+	registered []*cobra.Command
+	mu         sync.Mutex
+)
+
+// Register adds the "cmd" to the commands of the CLI, it's called by the init function of the package of a custom command,
+// imported by a build of the CLI.
+func Register(cmd *cobra.Command) {
+	mu.Lock()
+	registered = append(registered, cmd)
+	mu.Unlock()
+}
+
+// Registered returns the commands added by `Register`.
+func Registered() []*cobra.Command {
+	mu.Lock()
+	defer mu.Unlock()
+
+	return append([]*cobra.Command(nil), registered...)
+}
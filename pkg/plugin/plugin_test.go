@@ -0,0 +1,51 @@
+package plugin
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscover(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lenses-cli-plugins")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	first, second := filepath.Join(dir, "first"), filepath.Join(dir, "second")
+	files := map[string]os.FileMode{
+		filepath.Join(first, "lenses-cli-report"):  0755,
+		filepath.Join(first, "lenses-cli-notes"):   0644, // not executable.
+		filepath.Join(first, "kubectl-report"):     0755,
+		filepath.Join(second, "lenses-cli-report"): 0755, // shadowed by the first.
+		filepath.Join(second, "lenses-cli-topics"): 0755,
+	}
+	for path, mode := range files {
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := ioutil.WriteFile(path, []byte("#!/bin/sh\n"), mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pathList := first + string(os.PathListSeparator) + filepath.Join(dir, "missing") + string(os.PathListSeparator) + second
+	plugins := Discover(pathList)
+	assert.Equal(t, []Plugin{
+		{Name: "report", Path: filepath.Join(first, "lenses-cli-report")},
+		{Name: "topics", Path: filepath.Join(second, "lenses-cli-topics")},
+	}, plugins)
+
+	root := &cobra.Command{Use: "lenses-cli"}
+	root.AddCommand(&cobra.Command{Use: "topic", Aliases: []string{"topics"}})
+	AddCommands(root, pathList)
+
+	var names []string
+	for _, cmd := range root.Commands() {
+		names = append(names, cmd.Name())
+	}
+	assert.Equal(t, []string{"report", "topic"}, names, "the commands of the CLI are not overridden")
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/lensesio/bite"
 	"github.com/lensesio/lenses-go/pkg/api"
 	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/manifest"
+	"github.com/lensesio/lenses-go/pkg/printer"
 	"github.com/spf13/cobra"
 )
 
@@ -39,7 +41,7 @@ func NewGetPoliciesCommand() *cobra.Command {
 				err = errors.New("cannot be found in policies")
 				return fmt.Errorf("failed to retrieve policy [%s]. [%s]", name, err.Error())
 			}
-			return bite.PrintObject(cmd, result)
+			return printer.PrintObject(cmd, result)
 		},
 	}
 
@@ -47,6 +49,7 @@ func NewGetPoliciesCommand() *cobra.Command {
 	cmd.AddCommand(NewGetPoliciesObfuscationCommand())
 	cmd.AddCommand(NewGetPoliciesImpactTypesCommand())
 	bite.CanPrintJSON(cmd)
+	printer.CanSelect(cmd)
 	return cmd
 }
 
@@ -212,7 +215,7 @@ func NewCreatePolicyCommand() *cobra.Command {
 	cmd.Flags().StringVar(&datasets, "datasets", "*", "(Optional - Comma separated) Specify Datasets")
 	cmd.Flags().StringVar(&fields, "fields", "", "Schema fields, comma separated")
 
-	bite.Prepend(cmd, bite.FileBind(&policy))
+	bite.Prepend(cmd, config.ManifestBind(manifest.KindPolicy, &policy))
 	bite.CanPrintJSON(cmd)
 	bite.CanBeSilent(cmd)
 
@@ -274,7 +277,7 @@ func NewUpdatePolicyCommand() *cobra.Command {
 	cmd.Flags().StringVar(&datasets, "datasets", "*", "(Optional - Comma separated) Specify Datasets")
 	cmd.Flags().StringVar(&fields, "fields", "", "Schema fields, comma separated")
 
-	bite.Prepend(cmd, bite.FileBind(&policy))
+	bite.Prepend(cmd, config.ManifestBind(manifest.KindPolicy, &policy))
 	bite.CanPrintJSON(cmd)
 	bite.CanBeSilent(cmd)
 
@@ -297,6 +300,10 @@ func NewDeletePolicyCommand() *cobra.Command {
 				return err
 			}
 
+			if err := config.Confirm("policy", id); err != nil {
+				return err
+			}
+
 			if err := config.Client.DeletePolicy(id); err != nil {
 				golog.Errorf("Failed to delete policy [%s]. [%s]", id, err.Error())
 				return err
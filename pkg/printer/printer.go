@@ -0,0 +1,451 @@
+// Package printer prints the results of the list commands like the `bite.PrintObject` does,
+// with their `--columns`, `--sort-by` and `--filter` flags applied, see `CanSelect`.
+package printer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lensesio/bite"
+	"github.com/lensesio/tableprinter"
+	"github.com/spf13/cobra"
+)
+
+// CanSelect adds the `--columns`, `--sort-by` and `--filter` flags to a list command, see `PrintObject`
+func CanSelect(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("columns", nil, "Print only these fields, in that order, i.e --columns=name,partitions,retention.ms")
+	cmd.Flags().String("sort-by", "", "Sort by a field, prefix it with '-' for descending order, i.e --sort-by=-partitions")
+	cmd.Flags().StringArray("filter", nil, "Print only the entries that match, field=value, field!=value, field~=regex, field!~=regex or field>number, <, >=, <=, repeat it for more")
+}
+
+// Selection is the parsed `--columns`, `--sort-by` and `--filter` flags of a command.
+type Selection struct {
+	Columns    []string
+	SortBy     string
+	Descending bool
+	Filters    []Filter
+}
+
+// IsEmpty reports whether none of the flags is set.
+func (s Selection) IsEmpty() bool {
+	return len(s.Columns) == 0 && s.SortBy == "" && len(s.Filters) == 0
+}
+
+// Filter is a `--filter` condition.
+type Filter struct {
+	Field    string
+	Operator string
+	Value    string
+	regex    *regexp.Regexp
+}
+
+var filterOperators = []string{"!~=", "~=", "!=", ">=", "<=", "=", ">", "<"}
+
+// ParseFilter parses a `--filter` condition, i.e "name~=^orders".
+func ParseFilter(s string) (Filter, error) {
+	var (
+		idx = -1
+		op  string
+	)
+
+	for _, o := range filterOperators {
+		if i := strings.Index(s, o); i > 0 && (idx == -1 || i < idx) {
+			idx, op = i, o
+		}
+	}
+
+	if idx == -1 {
+		return Filter{}, fmt.Errorf("invalid filter [%s], expected field=value, field!=value, field~=regex, field!~=regex, field>number, <, >= or <=", s)
+	}
+
+	f := Filter{Field: strings.TrimSpace(s[:idx]), Operator: op, Value: strings.TrimSpace(s[idx+len(op):])}
+
+	switch op {
+	case "~=", "!~=":
+		regex, err := regexp.Compile(f.Value)
+		if err != nil {
+			return Filter{}, fmt.Errorf("invalid filter [%s]: %v", s, err)
+		}
+		f.regex = regex
+	case ">", "<", ">=", "<=":
+		if _, err := strconv.ParseFloat(f.Value, 64); err != nil {
+			return Filter{}, fmt.Errorf("invalid filter [%s], [%s] is not a number", s, f.Value)
+		}
+	}
+
+	return f, nil
+}
+
+// Match reports whether the "value" of the filter's field matches, a missing field matches only the negative conditions.
+func (f Filter) Match(value interface{}, found bool) bool {
+	if !found {
+		return f.Operator == "!=" || f.Operator == "!~="
+	}
+
+	s := valueString(value)
+
+	switch f.Operator {
+	case "=":
+		return s == f.Value
+	case "!=":
+		return s != f.Value
+	case "~=":
+		return f.regex.MatchString(s)
+	case "!~=":
+		return !f.regex.MatchString(s)
+	}
+
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return false
+	}
+	expected, _ := strconv.ParseFloat(f.Value, 64)
+
+	switch f.Operator {
+	case ">":
+		return n > expected
+	case "<":
+		return n < expected
+	case ">=":
+		return n >= expected
+	default:
+		return n <= expected
+	}
+}
+
+// SelectionOf returns the parsed selection flags of the "cmd", empty if it can't select.
+func SelectionOf(cmd *cobra.Command) (s Selection, err error) {
+	if cmd.Flags().Lookup("columns") == nil {
+		return
+	}
+
+	columns, _ := cmd.Flags().GetStringSlice("columns")
+	for _, c := range columns {
+		if c = strings.TrimSpace(c); c != "" {
+			s.Columns = append(s.Columns, c)
+		}
+	}
+
+	s.SortBy, _ = cmd.Flags().GetString("sort-by")
+	if strings.HasPrefix(s.SortBy, "-") {
+		s.SortBy, s.Descending = s.SortBy[1:], true
+	}
+
+	filters, _ := cmd.Flags().GetStringArray("filter")
+	for _, raw := range filters {
+		f, err := ParseFilter(raw)
+		if err != nil {
+			return s, err
+		}
+		s.Filters = append(s.Filters, f)
+	}
+
+	return
+}
+
+// PrintObject prints "v" like the `bite.PrintObject`, a list with the selection flags of the "cmd" applied:
+// the entries are filtered and sorted and, if `--columns` is set, only those fields are printed
+func PrintObject(cmd *cobra.Command, v interface{}, tableOnlyFilters ...interface{}) error {
+	s, err := SelectionOf(cmd)
+	if err != nil {
+		return err
+	}
+
+	list := reflect.Indirect(reflect.ValueOf(v))
+	if s.IsEmpty() || list.Kind() != reflect.Slice {
+		return bite.PrintObject(cmd, v, tableOnlyFilters...)
+	}
+
+	docs, err := documents(list)
+	if err != nil {
+		return err
+	}
+	aliases := headerAliases(list.Type().Elem())
+
+	indexes, err := s.apply(docs, aliases)
+	if err != nil {
+		return err
+	}
+
+	isTable := strings.ToUpper(bite.GetOutPutFlag(cmd)) == "TABLE"
+	if isTable {
+		// the table-only filters of the command, i.e the topics command hides the control topics.
+		kept := indexes[:0]
+		for _, i := range indexes {
+			if keep(list.Index(i), tableOnlyFilters) {
+				kept = append(kept, i)
+			}
+		}
+		indexes = kept
+	}
+
+	if len(s.Columns) == 0 {
+		selected := reflect.MakeSlice(list.Type(), 0, len(indexes))
+		for _, i := range indexes {
+			selected = reflect.Append(selected, list.Index(i))
+		}
+		return bite.PrintObject(cmd, selected.Interface(), tableOnlyFilters...)
+	}
+
+	for _, c := range s.Columns {
+		if !resolvedByAny(docs, aliases, c) {
+			return fmt.Errorf("unknown column [%s]", c)
+		}
+	}
+
+	if !isTable {
+		rows := make([]map[string]interface{}, len(indexes))
+		for r, i := range indexes {
+			rows[r] = make(map[string]interface{}, len(s.Columns))
+			for _, c := range s.Columns {
+				rows[r][c], _ = resolve(docs[i], aliases, c)
+			}
+		}
+		return bite.PrintObject(cmd, rows)
+	}
+
+	headers := make([]string, len(s.Columns))
+	for i, c := range s.Columns {
+		headers[i] = strings.ToUpper(c)
+	}
+
+	rows := make([][]string, len(indexes))
+	for r, i := range indexes {
+		rows[r] = make([]string, len(s.Columns))
+		for j, c := range s.Columns {
+			if value, found := resolve(docs[i], aliases, c); found {
+				rows[r][j] = valueString(value)
+			}
+		}
+	}
+
+	tableprinter.New(cmd.OutOrStdout()).Render(headers, rows, nil, true)
+	return nil
+}
+
+// apply returns the indexes of the "docs" that match the filters, in the sort order.
+func (s Selection) apply(docs []interface{}, aliases map[string]string) ([]int, error) {
+	var indexes []int
+	for i, doc := range docs {
+		matches := true
+		for _, f := range s.Filters {
+			value, found := resolve(doc, aliases, f.Field)
+			if !f.Match(value, found) {
+				matches = false
+				break
+			}
+		}
+
+		if matches {
+			indexes = append(indexes, i)
+		}
+	}
+
+	if s.SortBy == "" {
+		return indexes, nil
+	}
+
+	if !resolvedByAny(docs, aliases, s.SortBy) {
+		return nil, fmt.Errorf("unknown sort field [%s]", s.SortBy)
+	}
+
+	sort.SliceStable(indexes, func(i, j int) bool {
+		a, _ := resolve(docs[indexes[i]], aliases, s.SortBy)
+		b, _ := resolve(docs[indexes[j]], aliases, s.SortBy)
+		if s.Descending {
+			return less(b, a)
+		}
+		return less(a, b)
+	})
+
+	return indexes, nil
+}
+
+func less(a, b interface{}) bool {
+	sa, sb := valueString(a), valueString(b)
+
+	na, errA := strconv.ParseFloat(sa, 64)
+	nb, errB := strconv.ParseFloat(sb, 64)
+	if errA == nil && errB == nil {
+		return na < nb
+	}
+
+	return sa < sb
+}
+
+// documents returns the JSON form of each entry of the "list", the fields are resolved against it.
+func documents(list reflect.Value) ([]interface{}, error) {
+	docs := make([]interface{}, list.Len())
+	for i := range docs {
+		b, err := json.Marshal(list.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+		if err = dec.Decode(&docs[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return docs, nil
+}
+
+// headerAliases maps the table headers of the "typ" to their JSON fields, so a `--columns=name` selects the `topicName` of a topic.
+func headerAliases(typ reflect.Type) map[string]string {
+	aliases := make(map[string]string)
+
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return aliases
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.Anonymous {
+			for k, v := range headerAliases(field.Type) {
+				aliases[k] = v
+			}
+			continue
+		}
+
+		jsonName := strings.Split(field.Tag.Get("json"), ",")[0]
+		header := strings.Split(field.Tag.Get("header"), ",")[0]
+		if jsonName == "" || jsonName == "-" || header == "" {
+			continue
+		}
+
+		aliases[normalize(header)] = jsonName
+	}
+
+	return aliases
+}
+
+func normalize(s string) string {
+	return strings.NewReplacer(" ", "", "_", "", "-", "").Replace(strings.ToLower(s))
+}
+
+func resolvedByAny(docs []interface{}, aliases map[string]string, field string) bool {
+	for _, doc := range docs {
+		if _, found := resolve(doc, aliases, field); found {
+			return true
+		}
+	}
+
+	return len(docs) == 0
+}
+
+// resolve returns the value of the "field" of the "doc", a dot separated path, i.e "coordinator.host".
+// The names are case insensitive and a name may contain dots itself, i.e "retention.ms".
+// A list of objects with a "name" is looked up by that name, i.e the configs of a topic,
+// and a field that is not at the top level is looked up one level deeper, so "retention.ms" resolves to "config.retention.ms".
+func resolve(doc interface{}, aliases map[string]string, field string) (interface{}, bool) {
+	parts := strings.Split(field, ".")
+
+	if alias, ok := aliases[normalize(field)]; ok {
+		parts = []string{alias}
+	}
+
+	if value, found := lookup(doc, parts); found {
+		return value, true
+	}
+
+	if m, ok := doc.(map[string]interface{}); ok {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			if value, found := lookup(m[k], parts); found {
+				return value, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+func lookup(doc interface{}, parts []string) (interface{}, bool) {
+	if len(parts) == 0 {
+		return doc, true
+	}
+
+	// the longest name first, so "retention.ms" is a single name.
+	for n := len(parts); n > 0; n-- {
+		name := strings.Join(parts[:n], ".")
+
+		switch d := doc.(type) {
+		case map[string]interface{}:
+			for k, v := range d {
+				if strings.EqualFold(k, name) {
+					if value, found := lookup(v, parts[n:]); found {
+						return value, true
+					}
+				}
+			}
+		case []interface{}:
+			for _, item := range d {
+				entry, ok := item.(map[string]interface{})
+				if !ok || !strings.EqualFold(valueString(entry["name"]), name) {
+					continue
+				}
+
+				if n == len(parts) {
+					if v, ok := entry["value"]; ok {
+						return v, true
+					}
+					return entry, true
+				}
+
+				if value, found := lookup(entry, parts[n:]); found {
+					return value, true
+				}
+			}
+		}
+	}
+
+	return nil, false
+}
+
+func valueString(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case json.Number:
+		return value.String()
+	case map[string]interface{}, []interface{}:
+		b, _ := json.Marshal(value)
+		return string(b)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// keep reports whether the "elem" passes the table-only filters of the `bite.PrintObject`, funcs of the element's type.
+func keep(elem reflect.Value, filters []interface{}) bool {
+	for _, filter := range filters {
+		fn := reflect.ValueOf(filter)
+		if fn.Kind() != reflect.Func || fn.Type().NumIn() != 1 || fn.Type().NumOut() != 1 || fn.Type().In(0) != elem.Type() {
+			continue
+		}
+
+		if !fn.Call([]reflect.Value{elem})[0].Bool() {
+			return false
+		}
+	}
+
+	return true
+}
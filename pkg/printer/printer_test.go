@@ -0,0 +1,84 @@
+package printer
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/lensesio/bite"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+type topic struct {
+	TopicName  string                   `json:"topicName" header:"Name"`
+	Partitions int                      `json:"partitions" header:"Part"`
+	Internal   bool                     `json:"internal"`
+	Configs    []map[string]interface{} `json:"config" header:"Configs,count"`
+}
+
+var topics = []topic{
+	{TopicName: "orders", Partitions: 3, Configs: []map[string]interface{}{{"name": "retention.ms", "value": "1000"}}},
+	{TopicName: "payments", Partitions: 12, Configs: []map[string]interface{}{{"name": "retention.ms", "value": "500"}}},
+	{TopicName: "_schemas", Partitions: 1, Internal: true},
+}
+
+func newListCommand(args ...string) (*cobra.Command, *bytes.Buffer) {
+	var output string
+	cmd := &cobra.Command{Use: "topics"}
+	bite.RegisterOutPutFlag(cmd, &output)
+	bite.CanPrintJSON(cmd)
+	CanSelect(cmd)
+	cmd.Flags().Parse(args)
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	return cmd, &buf
+}
+
+func TestPrintObjectJSON(t *testing.T) {
+	cmd, buf := newListCommand("--output=json", "--columns=name,partitions,retention.ms", "--sort-by=-partitions", "--filter=name~=s$")
+	assert.NoError(t, PrintObject(cmd, topics))
+
+	var rows []map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &rows))
+	assert.Equal(t, []map[string]interface{}{
+		{"name": "payments", "partitions": float64(12), "retention.ms": "500"},
+		{"name": "orders", "partitions": float64(3), "retention.ms": "1000"},
+		{"name": "_schemas", "partitions": float64(1), "retention.ms": nil},
+	}, rows)
+}
+
+func TestPrintObjectTable(t *testing.T) {
+	cmd, buf := newListCommand("--columns=name,retention.ms", "--filter=partitions>=3", "--sort-by=retention.ms")
+	assert.NoError(t, PrintObject(cmd, topics, func(t topic) bool { return !t.Internal }))
+
+	out := buf.String()
+	assert.Contains(t, out, "NAME")
+	assert.Contains(t, out, "RETENTION MS")
+	assert.NotContains(t, out, "_schemas")
+	assert.True(t, bytes.Index(buf.Bytes(), []byte("payments")) < bytes.Index(buf.Bytes(), []byte("orders")), out)
+}
+
+func TestPrintObjectKeepsEntries(t *testing.T) {
+	cmd, buf := newListCommand("--output=json", "--filter=internal=true")
+	assert.NoError(t, PrintObject(cmd, topics))
+
+	var rows []topic
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &rows))
+	assert.Equal(t, []topic{topics[2]}, rows)
+}
+
+func TestPrintObjectErrors(t *testing.T) {
+	cmd, _ := newListCommand("--columns=owner")
+	assert.EqualError(t, PrintObject(cmd, topics), "unknown column [owner]")
+
+	cmd, _ = newListCommand("--sort-by=owner")
+	assert.EqualError(t, PrintObject(cmd, topics), "unknown sort field [owner]")
+
+	cmd, _ = newListCommand("--filter=name")
+	assert.Error(t, PrintObject(cmd, topics))
+
+	_, err := ParseFilter("partitions>many")
+	assert.EqualError(t, err, "invalid filter [partitions>many], [many] is not a number")
+}
@@ -0,0 +1,299 @@
+package processor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kataras/golog"
+	"github.com/lensesio/bite"
+	"github.com/lensesio/lenses-go/pkg/api"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/diff"
+	manifests "github.com/lensesio/lenses-go/pkg/manifest"
+	"github.com/spf13/cobra"
+)
+
+// applyAction is the change that `processor apply` makes to reach the manifest's definition.
+type applyAction string
+
+const (
+	applyCreate    applyAction = "create"
+	applyScale     applyAction = "scale"
+	applyRedeploy  applyAction = "redeploy"
+	applyUnchanged applyAction = "unchanged"
+)
+
+// planApply compares the manifest against the existing processors
+// and returns the action to apply and the matched processor, if any.
+//
+// A processor matches by its processor id, if the manifest has one, otherwise by name and by the cluster and namespace
+// the manifest declares, a processor of the same name on another cluster or namespace is a different one.
+// A change of the SQL, cluster or namespace requires a redeploy, a change of the runners just a scale.
+func planApply(manifest api.CreateProcessorFilePayload, processors []api.ProcessorStream) (applyAction, *api.ProcessorStream, error) {
+	current, err := findProcessor(manifest, processors)
+	if err != nil || current == nil {
+		return applyCreate, nil, err
+	}
+
+	runners := manifest.Runners
+	if runners <= 0 {
+		runners = 1
+	}
+
+	if normalizeSQL(current.SQL) != normalizeSQL(manifest.SQL) ||
+		(manifest.ClusterName != "" && manifest.ClusterName != current.ClusterName) ||
+		(manifest.Namespace != "" && manifest.Namespace != current.Namespace) {
+		return applyRedeploy, current, nil
+	}
+
+	if runners != current.Runners {
+		return applyScale, current, nil
+	}
+
+	return applyUnchanged, current, nil
+}
+
+// findProcessor returns the existing processor of the manifest, see `planApply`, or nil if there is none.
+func findProcessor(manifest api.CreateProcessorFilePayload, processors []api.ProcessorStream) (*api.ProcessorStream, error) {
+	var candidates []api.ProcessorStream
+	for _, p := range processors {
+		if manifest.ProcessorID != "" {
+			if p.ProcessorID == manifest.ProcessorID {
+				candidates = append(candidates, p)
+			}
+			continue
+		}
+
+		if p.Name == manifest.Name &&
+			(manifest.ClusterName == "" || manifest.ClusterName == p.ClusterName) &&
+			(manifest.Namespace == "" || manifest.Namespace == p.Namespace) {
+			candidates = append(candidates, p)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &candidates[0], nil
+	default:
+		return nil, fmt.Errorf("more than one processors named [%s] found, please set the cluster and namespace or the processorId", manifest.Name)
+	}
+}
+
+// redeployProcessor replaces the "current" processor with the one of the "manifest",
+// as the SQL, cluster and namespace can't be changed on a running processor.
+// The "current" one is created again if the new one fails to be created.
+func redeployProcessor(current *api.ProcessorStream, manifest api.CreateProcessorFilePayload) error {
+	if err := config.Client.DeleteProcessor(current.ID); err != nil {
+		golog.Errorf("Failed to delete processor [%s] for redeploy. [%s]", current.ID, err.Error())
+		return err
+	}
+
+	err := config.Client.CreateProcessor(manifest.Name, manifest.SQL, manifest.Runners, manifest.ClusterName, manifest.Namespace, manifest.Pipeline, manifest.ProcessorID)
+	if err == nil {
+		return nil
+	}
+
+	golog.Errorf("Failed to create processor [%s]. [%s]", manifest.Name, err.Error())
+	if restoreErr := config.Client.CreateProcessor(current.Name, current.SQL, current.Runners, current.ClusterName, current.Namespace, current.Pipeline, current.ProcessorID); restoreErr != nil {
+		return fmt.Errorf("unable to redeploy processor [%s]: [%v], the previous one was deleted and could not be restored: [%v], its sql was: [%s]", manifest.Name, err, restoreErr, current.SQL)
+	}
+
+	return fmt.Errorf("unable to redeploy processor [%s]: [%v], the previous one was restored", manifest.Name, err)
+}
+
+// normalizeSQL collapses the whitespace of a query, so formatting changes do not cause a redeploy.
+func normalizeSQL(sql string) string {
+	return strings.Join(strings.Fields(sql), " ")
+}
+
+// processorDefinition is the part of a processor that the `processor apply` compares, for the diff of the --dry-run.
+type processorDefinition struct {
+	SQL         string `json:"sql"`
+	Runners     int    `json:"runnerCount"`
+	ClusterName string `json:"cluster"`
+	Namespace   string `json:"namespace"`
+}
+
+// fields returns the fields of the definition by their manifest name, the empty ones are not declared.
+func (d processorDefinition) fields() map[string]interface{} {
+	fields := make(map[string]interface{})
+	if d.SQL != "" {
+		fields["sql"] = d.SQL
+	}
+	if d.Runners > 0 {
+		fields["runnerCount"] = d.Runners
+	}
+	if d.ClusterName != "" {
+		fields["cluster"] = d.ClusterName
+	}
+	if d.Namespace != "" {
+		fields["namespace"] = d.Namespace
+	}
+
+	return fields
+}
+
+// mergeApplied returns the "manifest" with the three-way merge of its fields into the "current" processor's ones, see `diff.Merge`.
+// The runners of a processor scaled on the server are kept, unless the manifest declares them
+// or declared them when it was last applied, then they are reset to the default.
+func mergeApplied(manifest api.CreateProcessorFilePayload, current *api.ProcessorStream, lastApplied []string) api.CreateProcessorFilePayload {
+	if current == nil {
+		return manifest
+	}
+
+	desired := processorDefinition{SQL: manifest.SQL, Runners: manifest.Runners, ClusterName: manifest.ClusterName, Namespace: manifest.Namespace}
+	live := processorDefinition{SQL: current.SQL, Runners: current.Runners, ClusterName: current.ClusterName, Namespace: current.Namespace}
+	merged := diff.Merge(lastApplied, live.fields(), desired.fields())
+
+	manifest.Runners, _ = merged["runnerCount"].(int)
+	manifest.ClusterName, _ = merged["cluster"].(string)
+	manifest.Namespace, _ = merged["namespace"].(string)
+	return manifest
+}
+
+//NewProcessorApplyCommand creates `processor apply` command
+func NewProcessorApplyCommand() *cobra.Command {
+	var (
+		file   string
+		dryRun bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Create a processor from a manifest file or update the existing one if its definition changed",
+		Long: `Create a processor from a manifest file or update the existing one if its definition changed.
+The manifest is merged with the deployed processor, the fields it does not declare, i.e the runners of a processor
+scaled on the server, are kept unless they were declared when it was last applied from this machine.`,
+		Example: `processor apply -f processor.yml
+processor apply -f processor.yml --dry-run [--unified]`,
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := bite.CheckRequiredFlags(cmd, bite.FlagPair{"file": file}); err != nil {
+				return err
+			}
+
+			var manifest api.CreateProcessorFilePayload
+			if err := config.ReadManifest(file, manifests.KindProcessor, &manifest); err != nil {
+				return fmt.Errorf("unable to read the processor manifest [%s]: [%v]", file, err)
+			}
+
+			if manifest.Name == "" || manifest.SQL == "" {
+				return fmt.Errorf("processor manifest [%s]: name and sql are required", file)
+			}
+
+			result, err := config.Client.GetProcessors()
+			if err != nil {
+				golog.Errorf("Failed to retrieve processors. [%s]", err.Error())
+				return err
+			}
+
+			current, err := findProcessor(manifest, result.Streams)
+			if err != nil {
+				return err
+			}
+
+			key := manifest.ProcessorID
+			if key == "" {
+				key = manifest.Name
+			}
+
+			var (
+				context    = config.Manager.Config.CurrentContext
+				appliedKey = config.AppliedKey(manifests.KindProcessor, key)
+				applied    = processorDefinition{SQL: manifest.SQL, Runners: manifest.Runners, ClusterName: manifest.ClusterName, Namespace: manifest.Namespace}.fields()
+			)
+
+			lastApplied, err := config.LastApplied(context, appliedKey)
+			if err != nil {
+				return err
+			}
+
+			manifest = mergeApplied(manifest, current, lastApplied)
+			if manifest.Runners <= 0 {
+				manifest.Runners = 1
+			}
+
+			action, current, err := planApply(manifest, result.Streams)
+			if err != nil {
+				return err
+			}
+
+			// the definitions of the diff of the --dry-run and of the --ansible result.
+			var deployed interface{}
+			desired := processorDefinition{SQL: manifest.SQL, Runners: manifest.Runners, ClusterName: manifest.ClusterName, Namespace: manifest.Namespace}
+			if current != nil {
+				definition := processorDefinition{SQL: current.SQL, Runners: current.Runners, ClusterName: current.ClusterName, Namespace: current.Namespace}
+				if desired.ClusterName == "" {
+					desired.ClusterName = definition.ClusterName
+				}
+				if desired.Namespace == "" {
+					desired.Namespace = definition.Namespace
+				}
+				deployed = definition
+			}
+
+			if dryRun {
+				if err := bite.PrintInfo(cmd, "Processor [%s] would be: %s", manifest.Name, action); err != nil || current == nil || action == applyUnchanged {
+					return err
+				}
+
+				return diff.Write(cmd.OutOrStdout(), current.ID, file, deployed, desired, diff.OptionsOf(cmd))
+			}
+
+			saveApplied := func() error {
+				return config.SaveLastApplied(context, appliedKey, applied)
+			}
+
+			switch action {
+			case applyUnchanged:
+				if err := saveApplied(); err != nil {
+					return err
+				}
+
+				return config.ReportChange(cmd, false, fmt.Sprintf("Processor [%s] unchanged", manifest.Name), nil, nil)
+			case applyScale:
+				if err := config.Client.UpdateProcessorRunners(current.ID, manifest.Runners); err != nil {
+					golog.Errorf("Failed to scale processor [%s] to [%d]. [%s]", current.ID, manifest.Runners, err.Error())
+					return err
+				}
+
+				if err := saveApplied(); err != nil {
+					golog.Errorf("Failed to save the last applied definition of processor [%s]. [%s]", manifest.Name, err.Error())
+				}
+
+				return config.ReportChange(cmd, true, fmt.Sprintf("Processor [%s] scaled from [%d] to [%d]", manifest.Name, current.Runners, manifest.Runners), deployed, desired)
+			case applyRedeploy:
+				if err := redeployProcessor(current, manifest); err != nil {
+					return err
+				}
+
+				if err := saveApplied(); err != nil {
+					golog.Errorf("Failed to save the last applied definition of processor [%s]. [%s]", manifest.Name, err.Error())
+				}
+
+				return config.ReportChange(cmd, true, fmt.Sprintf("Processor [%s] redeployed", manifest.Name), deployed, desired)
+			}
+
+			if err := config.Client.CreateProcessor(manifest.Name, manifest.SQL, manifest.Runners, manifest.ClusterName, manifest.Namespace, manifest.Pipeline, manifest.ProcessorID); err != nil {
+				golog.Errorf("Failed to create processor [%s]. [%s]", manifest.Name, err.Error())
+				return err
+			}
+
+			if err := saveApplied(); err != nil {
+				golog.Errorf("Failed to save the last applied definition of processor [%s]. [%s]", manifest.Name, err.Error())
+			}
+
+			return config.ReportChange(cmd, true, fmt.Sprintf("Processor [%s] created", manifest.Name), nil, desired)
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "The processor manifest file (yaml or json) with the name, sql, runnerCount, cluster, namespace, pipeline and processorId fields")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the action that would be applied and the differences without applying it")
+	diff.AddFlags(cmd)
+	bite.CanBeSilent(cmd)
+
+	return cmd
+}
@@ -0,0 +1,115 @@
+package processor
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	test "github.com/lensesio/lenses-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanApply(t *testing.T) {
+	existing := []api.ProcessorStream{
+		{ID: "1", ProcessorID: "lsql_1", Name: "payments", SQL: "INSERT INTO b SELECT STREAM * FROM a", Runners: 1, ClusterName: "dev", Namespace: "ns"},
+		{ID: "2", ProcessorID: "lsql_2", Name: "orders", SQL: "INSERT INTO d SELECT STREAM * FROM c", Runners: 1, ClusterName: "dev", Namespace: "ns"},
+		{ID: "3", ProcessorID: "lsql_3", Name: "orders", SQL: "INSERT INTO d SELECT STREAM * FROM c", Runners: 2, ClusterName: "prod", Namespace: "ns"},
+	}
+
+	tests := []struct {
+		name     string
+		manifest api.CreateProcessorFilePayload
+		action   applyAction
+		id       string
+	}{
+		{"missing processor is created", api.CreateProcessorFilePayload{Name: "new", SQL: "SELECT 1", Runners: 1}, applyCreate, ""},
+		{"same definition with different formatting is unchanged", api.CreateProcessorFilePayload{Name: "payments", SQL: "INSERT INTO b\n  SELECT STREAM *\n  FROM a", Runners: 1, ClusterName: "dev"}, applyUnchanged, "1"},
+		{"different runners are scaled", api.CreateProcessorFilePayload{Name: "payments", SQL: "INSERT INTO b SELECT STREAM * FROM a", Runners: 3}, applyScale, "1"},
+		{"different sql is redeployed", api.CreateProcessorFilePayload{Name: "payments", SQL: "INSERT INTO c SELECT STREAM * FROM a", Runners: 1}, applyRedeploy, "1"},
+		{"different cluster is a new processor", api.CreateProcessorFilePayload{Name: "payments", SQL: "INSERT INTO b SELECT STREAM * FROM a", Runners: 1, ClusterName: "prod"}, applyCreate, ""},
+		{"different cluster of the processor id is redeployed", api.CreateProcessorFilePayload{Name: "payments", ProcessorID: "lsql_1", SQL: "INSERT INTO b SELECT STREAM * FROM a", Runners: 1, ClusterName: "prod"}, applyRedeploy, "1"},
+		{"processor id has priority over name", api.CreateProcessorFilePayload{Name: "renamed", ProcessorID: "lsql_1", SQL: "INSERT INTO b SELECT STREAM * FROM a", Runners: 1}, applyUnchanged, "1"},
+		{"same name matches by cluster and namespace", api.CreateProcessorFilePayload{Name: "orders", SQL: "INSERT INTO d SELECT STREAM * FROM c", Runners: 2, ClusterName: "prod", Namespace: "ns"}, applyUnchanged, "3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action, current, err := planApply(tt.manifest, existing)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.action, action)
+			if tt.id == "" {
+				assert.Nil(t, current)
+			} else {
+				assert.Equal(t, tt.id, current.ID)
+			}
+		})
+	}
+
+	_, _, err := planApply(api.CreateProcessorFilePayload{Name: "orders", SQL: "SELECT 1"}, existing)
+	assert.NotNil(t, err)
+}
+
+func TestMergeApplied(t *testing.T) {
+	current := &api.ProcessorStream{ID: "1", Name: "payments", SQL: "INSERT INTO b SELECT STREAM * FROM a", Runners: 4, ClusterName: "dev", Namespace: "ns"}
+	manifest := api.CreateProcessorFilePayload{Name: "payments", SQL: "INSERT INTO b SELECT STREAM * FROM a"}
+
+	// runners scaled on the server and never declared are kept.
+	merged := mergeApplied(manifest, current, []string{"sql"})
+	assert.Equal(t, 4, merged.Runners)
+	assert.Equal(t, "dev", merged.ClusterName)
+	assert.Equal(t, "ns", merged.Namespace)
+
+	action, _, err := planApply(merged, []api.ProcessorStream{*current})
+	assert.Nil(t, err)
+	assert.Equal(t, applyUnchanged, action)
+
+	// runners removed from the manifest since its last apply are reset.
+	merged = mergeApplied(manifest, current, []string{"runnerCount", "sql"})
+	assert.Equal(t, 0, merged.Runners)
+
+	action, _, err = planApply(merged, []api.ProcessorStream{*current})
+	assert.Nil(t, err)
+	assert.Equal(t, applyScale, action)
+
+	// declared runners win.
+	manifest.Runners = 2
+	assert.Equal(t, 2, mergeApplied(manifest, current, nil).Runners)
+	assert.Equal(t, 2, mergeApplied(manifest, nil, nil).Runners)
+}
+
+func TestRedeployProcessorRestore(t *testing.T) {
+	var created []api.CreateProcessorRequestPayload
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			return
+		}
+
+		var payload api.CreateProcessorRequestPayload
+		json.NewDecoder(r.Body).Decode(&payload)
+		created = append(created, payload)
+		if payload.SQL == "INVALID" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write(processorRegisteredAsJSON)
+	})
+	httpClient, teardown := test.TestingHTTPClient(h)
+	defer teardown()
+
+	client, err := api.OpenConnection(test.ClientConfig, api.UsingClient(httpClient))
+	assert.Nil(t, err)
+	config.Client = client
+	defer func() { config.Client = nil }()
+
+	current := &api.ProcessorStream{ID: "1", ProcessorID: "lsql_1", Name: "payments", SQL: "INSERT INTO b SELECT STREAM * FROM a", Runners: 2, ClusterName: "dev", Namespace: "ns"}
+	err = redeployProcessor(current, api.CreateProcessorFilePayload{Name: "payments", ProcessorID: "lsql_1", SQL: "INVALID", Runners: 1})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "the previous one was restored")
+	}
+
+	if assert.Len(t, created, 2) {
+		assert.Equal(t, api.CreateProcessorRequestPayload{Name: "payments", SQL: "INSERT INTO b SELECT STREAM * FROM a", Runners: 2, ClusterName: "dev", Namespace: "ns", AppID: "lsql_1"}, created[1])
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/lensesio/bite"
 	"github.com/lensesio/lenses-go/pkg/api"
 	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/manifest"
+	"github.com/lensesio/lenses-go/pkg/printer"
 	"github.com/lensesio/lenses-go/pkg/utils"
 	"github.com/spf13/cobra"
 )
@@ -60,7 +62,7 @@ func NewGetProcessorsCommand() *cobra.Command {
 				final = append(final, processor)
 			}
 
-			return bite.PrintObject(cmd, final)
+			return printer.PrintObject(cmd, final)
 		},
 	}
 
@@ -70,6 +72,7 @@ func NewGetProcessorsCommand() *cobra.Command {
 	cmd.Flags().StringVar(&namespace, "namespace", "", "Select by namespace, available only in KUBERNETES mode")
 	// example: lenses-cli processors --query="[?ClusterName == 'IN_PROC'].Name | sort(@) | {Processor_Names_IN_PROC: join(', ', @)}"
 	bite.CanPrintJSON(cmd)
+	printer.CanSelect(cmd)
 
 	cmd.AddCommand(NewProcessorsLogsCommand())
 	cmd.AddCommand(NewListDeploymentTargetsCommand())
@@ -133,6 +136,7 @@ func NewProcessorGroupCommand() *cobra.Command {
 	// subcommands
 	root.AddCommand(NewProcessorViewCommand())
 	root.AddCommand(NewProcessorCreateCommand())
+	root.AddCommand(config.WithLock(NewProcessorApplyCommand()))
 	root.AddCommand(NewProcessorPauseCommand())
 	root.AddCommand(NewProcessorResumeCommand())
 	root.AddCommand(NewProcessorUpdateRunnersCommand())
@@ -206,7 +210,7 @@ func NewProcessorCreateCommand() *cobra.Command {
 	cmd.Flags().StringVar(&processor.Pipeline, "pipeline", "", `A label to apply to kubernetes processors, defaults to processor name`)
 	cmd.Flags().StringVar(&processor.ProcessorID, "id", "", `The processor identifier, it is used as the underlying Kafka consumer group`)
 
-	bite.Prepend(cmd, bite.FileBind(&processor))
+	bite.Prepend(cmd, config.ManifestBind(manifest.KindProcessor, &processor))
 	bite.CanBeSilent(cmd)
 
 	return cmd
@@ -340,6 +344,15 @@ func NewProcessorDeleteCommand() *cobra.Command {
 				return err
 			}
 
+			name := identifier
+			if processorName != "" {
+				name = processorName
+			}
+
+			if err := config.Confirm("processor", name); err != nil {
+				return err
+			}
+
 			// delete the processor based on the identifier, based on the current running mode.
 			if err := config.Client.DeleteProcessor(identifier); err != nil {
 				golog.Errorf("Failed to delete processor [%s]. [%s]", identifier, err.Error())
@@ -0,0 +1,79 @@
+package profile
+
+import (
+	"html/template"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Report is the data of the HTML report of a profile.
+type Report struct {
+	// Title is the title of the report, i.e the name of the topic.
+	Title string
+	// Values is the number of the profiled values.
+	Values int64
+	// Created is the time of the report.
+	Created time.Time
+	Fields  []Field
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"bucket": func(b LengthBucket) string {
+		if b.Max < 0 {
+			return ">" + strconv.Itoa(lengthBounds[len(lengthBounds)-1])
+		}
+		return "≤" + strconv.Itoa(b.Max)
+	},
+	"percent": func(count int64, f Field) int64 {
+		var total int64
+		for _, b := range f.Lengths {
+			total += b.Count
+		}
+		if total == 0 {
+			return 0
+		}
+		return count * 100 / total
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Profile of {{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ddd; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #f4f4f4; }
+td.num { text-align: right; }
+.bar { display: inline-block; height: 10px; background: #4a90d9; }
+.warn { color: #c0392b; }
+</style>
+</head>
+<body>
+<h1>Profile of {{.Title}}</h1>
+<p>{{.Values}} records, {{.Created.Format "02 Jan 2006 15:04 MST"}}</p>
+<table>
+<tr><th>Field</th><th>Count</th><th>Null %</th><th>Distinct</th><th>Min</th><th>Max</th><th>Length</th><th>Length distribution</th></tr>
+{{- range .Fields}}
+{{- $field := .}}
+<tr>
+<td>{{.Path}}</td>
+<td class="num">{{.Count}}</td>
+<td class="num{{if ge .NullRate 50.0}} warn{{end}}">{{.NullRate}}</td>
+<td class="num">{{.Distinct}}</td>
+<td>{{.Min}}</td>
+<td>{{.Max}}</td>
+<td>{{if .Lengths}}{{.MinLength}}..{{.MaxLength}}, avg {{.AvgLength}}{{end}}</td>
+<td>{{range .Lengths}}<div>{{bucket .}} <span class="bar" style="width: {{percent .Count $field}}px"></span> {{.Count}}</div>{{end}}</td>
+</tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+// WriteHTML writes the HTML report of the "report" to "w".
+func WriteHTML(w io.Writer, report Report) error {
+	return reportTemplate.Execute(w, report)
+}
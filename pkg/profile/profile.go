@@ -0,0 +1,256 @@
+// Package profile computes the data quality statistics of the fields of JSON values, i.e of a sample of the records of a topic:
+// how often they are null or missing, the estimated number of their distinct values, their min and max
+// and the distribution of the length of their strings.
+package profile
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lensesio/lenses-go/pkg/sketch"
+)
+
+// lengthBounds are the upper bounds of the buckets of the length distribution, the last bucket has no bound.
+var lengthBounds = []int{0, 1, 2, 4, 8, 16, 32, 64, 128, 256, 512, 1024}
+
+// LengthBucket is a bucket of the length distribution of the strings of a field.
+type LengthBucket struct {
+	// Max is the largest length of the bucket, -1 for the last one which has no bound.
+	Max   int   `json:"max" yaml:"max"`
+	Count int64 `json:"count" yaml:"count"`
+}
+
+// Field is the statistics of a field, the ones of the strings are zero if it has none.
+type Field struct {
+	// Path is the dot separated path of the field, the items of an array are the "[]" of its path.
+	Path string `json:"path" yaml:"path" header:"Field"`
+	// Count is the number of the values with the field, null or not.
+	Count int64 `json:"count" yaml:"count" header:"Count"`
+	// NullRate is the percentage of the values of the parent of the field whose field is null or missing.
+	NullRate float64 `json:"nullRate" yaml:"nullRate" header:"Null %"`
+	// Distinct is the estimated number of the distinct values of the field, except the objects and the arrays.
+	Distinct int64 `json:"distinct" yaml:"distinct" header:"Distinct"`
+	// Min and Max are the smallest and the largest numbers of the field, the strings are compared if it has no numbers.
+	Min string `json:"min" yaml:"min" header:"Min"`
+	Max string `json:"max" yaml:"max" header:"Max"`
+	// MinLength, MaxLength and AvgLength are the lengths of the strings of the field, in characters.
+	MinLength int     `json:"minLength" yaml:"minLength" header:"Min Length"`
+	MaxLength int     `json:"maxLength" yaml:"maxLength" header:"Max Length"`
+	AvgLength float64 `json:"avgLength" yaml:"avgLength" header:"Avg Length"`
+	// Lengths is the length distribution of the strings of the field, the empty buckets are omitted.
+	Lengths []LengthBucket `json:"lengths" yaml:"lengths"`
+}
+
+type fieldStats struct {
+	parent string
+	count  int64
+	nulls  int64
+
+	// objects and items are the number of the objects and of the array items of the field,
+	// the parents of its fields and of its "[]" field.
+	objects, items int64
+	distinct       *sketch.Distinct
+
+	numbers        int64
+	minNum, maxNum float64
+	strs           int64
+	minStr, maxStr string
+	minLen, maxLen int
+	totalLen       int64
+	lengths        []int64 // by the `lengthBounds`, the last one is the unbounded bucket.
+}
+
+// Profiler computes the statistics of the fields of the JSON values added to it, it's not safe for concurrent use.
+type Profiler struct {
+	fields  map[string]*fieldStats
+	values  int64
+	skipped int64
+}
+
+// New returns a `Profiler` without values.
+func New() *Profiler {
+	return &Profiler{fields: make(map[string]*fieldStats)}
+}
+
+// Add adds the JSON "value", a value which is not JSON is skipped and its error is returned.
+func (p *Profiler) Add(value json.RawMessage) error {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(value))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		p.skipped++
+		return err
+	}
+
+	p.values++
+	p.observe("", "", v)
+	return nil
+}
+
+// Values returns the number of the added JSON values.
+func (p *Profiler) Values() int64 {
+	return p.values
+}
+
+// Skipped returns the number of the added values which were not JSON.
+func (p *Profiler) Skipped() int64 {
+	return p.skipped
+}
+
+func (p *Profiler) stats(path, parent string) *fieldStats {
+	s, ok := p.fields[path]
+	if !ok {
+		s = &fieldStats{parent: parent, lengths: make([]int64, len(lengthBounds)+1)}
+		p.fields[path] = s
+	}
+
+	return s
+}
+
+func (p *Profiler) observe(path, parent string, v interface{}) {
+	s := p.stats(path, parent)
+	s.count++
+
+	switch v := v.(type) {
+	case nil:
+		s.nulls++
+	case map[string]interface{}:
+		s.objects++
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			fieldPath := name
+			if path != "" {
+				fieldPath = path + "." + name
+			}
+			p.observe(fieldPath, path, v[name])
+		}
+	case []interface{}:
+		s.items += int64(len(v))
+		for _, item := range v {
+			p.observe(path+"[]", path, item)
+		}
+	case json.Number:
+		s.addDistinct(v.String())
+		if f, err := v.Float64(); err == nil {
+			if s.numbers == 0 || f < s.minNum {
+				s.minNum = f
+			}
+			if s.numbers == 0 || f > s.maxNum {
+				s.maxNum = f
+			}
+			s.numbers++
+		}
+	case string:
+		s.addDistinct(v)
+		s.addString(v)
+	case bool:
+		s.addDistinct(strconv.FormatBool(v))
+	}
+}
+
+func (s *fieldStats) addDistinct(value string) {
+	if s.distinct == nil {
+		s.distinct = sketch.NewDistinct()
+	}
+	s.distinct.AddString(value)
+}
+
+func (s *fieldStats) addString(v string) {
+	if s.strs == 0 || v < s.minStr {
+		s.minStr = v
+	}
+	if s.strs == 0 || v > s.maxStr {
+		s.maxStr = v
+	}
+
+	n := utf8.RuneCountInString(v)
+	if s.strs == 0 || n < s.minLen {
+		s.minLen = n
+	}
+	if n > s.maxLen {
+		s.maxLen = n
+	}
+	s.totalLen += int64(n)
+	s.strs++
+
+	bucket := sort.SearchInts(lengthBounds, n)
+	s.lengths[bucket]++
+}
+
+// Fields returns the statistics of the fields of the values, the nested ones after their parents.
+// A value which is not an object is the field of an empty path.
+func (p *Profiler) Fields() []Field {
+	paths := make([]string, 0, len(p.fields))
+	for path := range p.fields {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	fields := make([]Field, 0, len(paths))
+	for _, path := range paths {
+		s := p.fields[path]
+		if path == "" && s.objects == s.count {
+			// the values are objects, their fields are profiled instead.
+			continue
+		}
+
+		parents := p.values
+		if path != "" {
+			parent := p.fields[s.parent]
+			parents = parent.objects
+			if strings.HasSuffix(path, "[]") {
+				parents = parent.items
+			}
+		}
+		fields = append(fields, s.field(path, parents))
+	}
+
+	return fields
+}
+
+func (s *fieldStats) field(path string, parents int64) Field {
+	f := Field{Path: path, Count: s.count}
+	if parents > 0 {
+		nonNull := s.count - s.nulls
+		f.NullRate = float64((parents-nonNull)*10000/parents) / 100
+	}
+
+	if s.distinct != nil {
+		f.Distinct = s.distinct.Count()
+	}
+
+	switch {
+	case s.numbers > 0:
+		f.Min = strconv.FormatFloat(s.minNum, 'f', -1, 64)
+		f.Max = strconv.FormatFloat(s.maxNum, 'f', -1, 64)
+	case s.strs > 0:
+		f.Min, f.Max = s.minStr, s.maxStr
+	}
+
+	if s.strs > 0 {
+		f.MinLength, f.MaxLength = s.minLen, s.maxLen
+		f.AvgLength = float64(s.totalLen*100/s.strs) / 100
+		for i, count := range s.lengths {
+			if count == 0 {
+				continue
+			}
+
+			max := -1
+			if i < len(lengthBounds) {
+				max = lengthBounds[i]
+			}
+			f.Lengths = append(f.Lengths, LengthBucket{Max: max, Count: count})
+		}
+	}
+
+	return f
+}
@@ -0,0 +1,61 @@
+package profile
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfiler(t *testing.T) {
+	p := New()
+	for _, v := range []string{
+		`{"id":1,"name":"Ann","amount":10.5,"tags":["a","b"],"customer":{"vip":true}}`,
+		`{"id":2,"name":"","amount":-3,"tags":[null],"customer":null}`,
+		`{"id":3,"name":null,"amount":7,"tags":[]}`,
+		`{"id":3,"name":"Bartholomew","amount":7}`,
+	} {
+		require.NoError(t, p.Add(json.RawMessage(v)))
+	}
+	assert.Error(t, p.Add(json.RawMessage(`not json`)))
+	assert.Equal(t, int64(4), p.Values())
+	assert.Equal(t, int64(1), p.Skipped())
+
+	assert.Equal(t, []Field{
+		{Path: "amount", Count: 4, Distinct: 3, Min: "-3", Max: "10.5"},
+		{Path: "customer", Count: 2, NullRate: 75},
+		{Path: "customer.vip", Count: 1, Distinct: 1},
+		{Path: "id", Count: 4, Distinct: 3, Min: "1", Max: "3"},
+		{Path: "name", Count: 4, NullRate: 25, Distinct: 3, Min: "", Max: "Bartholomew", MinLength: 0, MaxLength: 11, AvgLength: 4.66,
+			Lengths: []LengthBucket{{Max: 0, Count: 1}, {Max: 4, Count: 1}, {Max: 16, Count: 1}}},
+		{Path: "tags", Count: 3, NullRate: 25},
+		{Path: "tags[]", Count: 3, NullRate: 33.33, Distinct: 2, Min: "a", Max: "b", MinLength: 1, MaxLength: 1, AvgLength: 1,
+			Lengths: []LengthBucket{{Max: 1, Count: 2}}},
+	}, p.Fields())
+
+	// not objects.
+	p = New()
+	require.NoError(t, p.Add(json.RawMessage(`"abc"`)))
+	require.NoError(t, p.Add(json.RawMessage(`null`)))
+	fields := p.Fields()
+	require.Len(t, fields, 1)
+	assert.Equal(t, "", fields[0].Path)
+	assert.Equal(t, float64(50), fields[0].NullRate)
+}
+
+func TestWriteHTML(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteHTML(&buf, Report{Title: "payments<1>", Values: 2, Created: time.Date(2020, 3, 11, 0, 0, 0, 0, time.UTC),
+		Fields: []Field{{Path: "name", Count: 2, NullRate: 50, Lengths: []LengthBucket{{Max: 4, Count: 1}, {Max: -1, Count: 1}}}}})
+	require.NoError(t, err)
+
+	html := buf.String()
+	assert.Contains(t, html, "<title>Profile of payments&lt;1&gt;</title>")
+	assert.Contains(t, html, "2 records, 11 Mar 2020 00:00 UTC")
+	assert.Contains(t, html, `<td class="num warn">50</td>`)
+	assert.Contains(t, html, `≤4 <span class="bar" style="width: 50px"></span> 1`)
+	assert.Contains(t, html, `&gt;1024 <span class="bar"`)
+}
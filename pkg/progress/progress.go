@@ -0,0 +1,154 @@
+// Package progress reports the progress of the long-running commands, a bar with the rate and the ETA
+// when the total is known, a spinner with the rate otherwise. It's written to the error output and
+// only when the output is a terminal, see `Enabled`.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lensesio/bite"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// isTerminal reports whether the standard output and the error output are terminals, it can be replaced for tests.
+var isTerminal = func() bool {
+	return term.IsTerminal(int(os.Stdout.Fd())) && term.IsTerminal(int(os.Stderr.Fd()))
+}
+
+// Enabled reports whether the "cmd" should report its progress, it's disabled
+// when the output is not a terminal, with the --quiet flag or with the --output=json, ndjson or yaml.
+func Enabled(cmd *cobra.Command) bool {
+	if config.Manager != nil && config.Manager.Quiet {
+		return false
+	}
+
+	if out := strings.ToLower(bite.GetOutPutFlag(cmd)); out == "json" || out == "ndjson" || out == "yaml" {
+		return false
+	}
+
+	return isTerminal()
+}
+
+const (
+	barWidth       = 30
+	redrawInterval = 100 * time.Millisecond
+)
+
+var spinnerFrames = []string{"|", "/", "-", "\\"}
+
+// Bar is the progress of a command, a bar if the total is known, a spinner otherwise.
+// A disabled bar, see `New`, does nothing. It's safe for concurrent use.
+type Bar struct {
+	w       io.Writer
+	label   string
+	total   int64
+	enabled bool
+	now     func() time.Time
+
+	mu       sync.Mutex
+	current  int64
+	start    time.Time
+	lastDraw time.Time
+	frame    int
+	drawn    bool
+}
+
+// New returns the progress of the "cmd", written to its error output if `Enabled`.
+// A "total" less than 1 shows a spinner instead of a bar.
+func New(cmd *cobra.Command, label string, total int64) *Bar {
+	return newBar(cmd.ErrOrStderr(), label, total, Enabled(cmd))
+}
+
+func newBar(w io.Writer, label string, total int64, enabled bool) *Bar {
+	return &Bar{w: w, label: label, total: total, enabled: enabled, now: time.Now, start: time.Now()}
+}
+
+// Add advances the progress by "n" and redraws it, at most every 100ms unless it was cleared.
+func (b *Bar) Add(n int64) {
+	if !b.enabled {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.current += n
+	if now := b.now(); !b.drawn || now.Sub(b.lastDraw) >= redrawInterval || (b.total > 0 && b.current >= b.total) {
+		b.lastDraw = now
+		b.draw(now)
+	}
+}
+
+// Increment advances the progress by one.
+func (b *Bar) Increment() {
+	b.Add(1)
+}
+
+// Clear erases the progress line, so the command can print its own output, the next `Add` draws it again.
+func (b *Bar) Clear() {
+	if !b.enabled {
+		return
+	}
+
+	b.mu.Lock()
+	b.clear()
+	b.mu.Unlock()
+}
+
+// Done erases the progress line.
+func (b *Bar) Done() {
+	b.Clear()
+}
+
+func (b *Bar) clear() {
+	if b.drawn {
+		fmt.Fprint(b.w, "\r\033[K")
+		b.drawn = false
+	}
+}
+
+func (b *Bar) draw(now time.Time) {
+	b.clear()
+	fmt.Fprint(b.w, b.line(now))
+	b.drawn = true
+}
+
+// line returns the progress line, i.e "import [=====>      ] 12/40 30% 4.0/s ETA 7s".
+func (b *Bar) line(now time.Time) string {
+	elapsed := now.Sub(b.start)
+
+	var rate float64
+	if elapsed > 0 {
+		rate = float64(b.current) / elapsed.Seconds()
+	}
+
+	if b.total < 1 {
+		b.frame = (b.frame + 1) % len(spinnerFrames)
+		return fmt.Sprintf("%s %s %d %.1f/s", b.label, spinnerFrames[b.frame], b.current, rate)
+	}
+
+	current := b.current
+	if current > b.total {
+		current = b.total
+	}
+
+	filled := int(int64(barWidth) * current / b.total)
+	bar := strings.Repeat("=", filled)
+	if filled < barWidth {
+		bar += ">" + strings.Repeat(" ", barWidth-filled-1)
+	}
+
+	eta := "?"
+	if rate > 0 {
+		eta = (time.Duration(float64(b.total-current)/rate) * time.Second).Round(time.Second).String()
+	}
+
+	return fmt.Sprintf("%s [%s] %d/%d %d%% %.1f/s ETA %s", b.label, bar, current, b.total, current*100/b.total, rate, eta)
+}
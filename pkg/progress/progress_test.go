@@ -0,0 +1,74 @@
+package progress
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/lensesio/bite"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBar(t *testing.T) {
+	var buf bytes.Buffer
+	b := newBar(&buf, "import", 40, true)
+	now := b.start
+	b.now = func() time.Time { return now }
+
+	now = now.Add(3 * time.Second)
+	b.Add(12)
+	assert.Equal(t, "import [=========>                    ] 12/40 30% 4.0/s ETA 7s", buf.String())
+
+	// throttled.
+	b.Add(1)
+	assert.Equal(t, "import [=========>                    ] 12/40 30% 4.0/s ETA 7s", buf.String())
+
+	buf.Reset()
+	b.Clear()
+	assert.Equal(t, "\r\033[K", buf.String())
+
+	buf.Reset()
+	now = now.Add(time.Second)
+	b.Add(27)
+	assert.Equal(t, "import [==============================] 40/40 100% 10.0/s ETA 0s", buf.String())
+
+	buf.Reset()
+	b.Done()
+	b.Done()
+	assert.Equal(t, "\r\033[K", buf.String())
+}
+
+func TestSpinner(t *testing.T) {
+	var buf bytes.Buffer
+	b := newBar(&buf, "records", 0, true)
+	b.now = func() time.Time { return b.start.Add(2 * time.Second) }
+
+	b.Add(5)
+	assert.Equal(t, "records / 5 2.5/s", buf.String())
+}
+
+func TestDisabled(t *testing.T) {
+	var buf bytes.Buffer
+	b := newBar(&buf, "import", 10, false)
+	b.Increment()
+	b.Done()
+	assert.Empty(t, buf.String())
+}
+
+func TestEnabled(t *testing.T) {
+	defer func(f func() bool) { isTerminal = f }(isTerminal)
+
+	var output string
+	cmd := &cobra.Command{Use: "topics"}
+	bite.RegisterOutPutFlag(cmd, &output)
+
+	isTerminal = func() bool { return false }
+	assert.False(t, Enabled(cmd))
+
+	isTerminal = func() bool { return true }
+	assert.True(t, Enabled(cmd))
+
+	cmd.Flags().Set("output", "json")
+	assert.False(t, Enabled(cmd))
+}
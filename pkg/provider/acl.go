@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"errors"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	v1 "github.com/lensesio/lenses-go/v1"
+)
+
+const aclIDFormat = "resourceType/patternType/resourceName/principal/host/operation/permissionType"
+
+// ACLID returns the ID of the "acl", all of its fields, an ACL is immutable.
+// The enum fields are upper-cased, the server may return them in a different case than they were set.
+func ACLID(acl api.ACL) string {
+	acl.Validate()
+	return joinID(string(acl.ResourceType), acl.PatternType, acl.ResourceName, acl.Principal, acl.Host,
+		string(acl.Operation), string(acl.PermissionType))
+}
+
+// ParseACLID returns the ACL of the "id", see `ACLID`.
+func ParseACLID(id string) (api.ACL, error) {
+	parts, err := splitID("ACL", id, 7, aclIDFormat)
+	if err != nil {
+		return api.ACL{}, err
+	}
+
+	acl := api.ACL{
+		ResourceType:   api.ACLResourceType(parts[0]),
+		PatternType:    parts[1],
+		ResourceName:   parts[2],
+		Principal:      parts[3],
+		Host:           parts[4],
+		Operation:      api.ACLOperation(parts[5]),
+		PermissionType: api.ACLPermissionType(parts[6]),
+	}
+
+	return acl, acl.Validate()
+}
+
+// ReadACL returns the ACL of the "id".
+func ReadACL(client v1.Client, id string) (api.ACL, error) {
+	if _, err := ParseACLID(id); err != nil {
+		return api.ACL{}, err
+	}
+
+	acls, err := client.GetACLs()
+	if err != nil {
+		return api.ACL{}, err
+	}
+
+	for _, acl := range acls {
+		if ACLID(acl) == id {
+			return acl, nil
+		}
+	}
+
+	return api.ACL{}, notFound("ACL", id)
+}
+
+// CreateACL creates the "acl" and returns its ID, it's a no-op if it exists.
+func CreateACL(client v1.Client, acl api.ACL) (string, error) {
+	if err := acl.Validate(); err != nil {
+		return "", err
+	}
+
+	if err := client.CreateOrUpdateACL(acl); err != nil {
+		return "", err
+	}
+
+	return ACLID(acl), nil
+}
+
+// DeleteACL deletes the ACL of the "id", it's a no-op if it does not exist.
+func DeleteACL(client v1.Client, id string) error {
+	acl, err := ReadACL(client, id)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	return client.DeleteACL(acl)
+}
@@ -0,0 +1,87 @@
+package provider
+
+import (
+	"errors"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	v1 "github.com/lensesio/lenses-go/v1"
+)
+
+// Connector is a Kafka Connect connector of a Connect cluster. Its ID is "<cluster>/<name>".
+type Connector struct {
+	ClusterName string `json:"clusterName" yaml:"clusterName"`
+	Name        string `json:"name" yaml:"name"`
+	// Config is the configuration of the connector, without its name.
+	Config api.ConnectorConfig `json:"config" yaml:"config"`
+}
+
+// ID returns the ID of the connector.
+func (c Connector) ID() string {
+	return joinID(c.ClusterName, c.Name)
+}
+
+// ParseConnectorID returns the connector, without its config, of the "id", see `Connector`.
+func ParseConnectorID(id string) (Connector, error) {
+	parts, err := splitID("connector", id, 2, "cluster/name")
+	if err != nil {
+		return Connector{}, err
+	}
+
+	return Connector{ClusterName: parts[0], Name: parts[1]}, nil
+}
+
+// ReadConnector returns the connector of the "id".
+func ReadConnector(client v1.Client, id string) (Connector, error) {
+	connector, err := ParseConnectorID(id)
+	if err != nil {
+		return Connector{}, err
+	}
+
+	config, err := client.GetConnectorConfig(connector.ClusterName, connector.Name)
+	if err != nil {
+		if isStatusNotFound(err) {
+			return Connector{}, notFound("connector", id)
+		}
+		return Connector{}, err
+	}
+
+	// Connect adds the name, it's the one of the ID.
+	delete(config, "name")
+	connector.Config = config
+	return connector, nil
+}
+
+// CreateConnector creates the connector and returns its ID, an existing one is updated to the "connector".
+func CreateConnector(client v1.Client, connector Connector) (string, error) {
+	if _, err := ReadConnector(client, connector.ID()); err == nil {
+		return connector.ID(), UpdateConnector(client, connector)
+	} else if !errors.Is(err, ErrNotFound) {
+		return "", err
+	}
+
+	if _, err := client.CreateConnector(connector.ClusterName, connector.Name, connector.Config); err != nil {
+		return "", err
+	}
+
+	return connector.ID(), nil
+}
+
+// UpdateConnector sets the config of the connector.
+func UpdateConnector(client v1.Client, connector Connector) error {
+	_, err := client.UpdateConnector(connector.ClusterName, connector.Name, connector.Config)
+	return err
+}
+
+// DeleteConnector deletes the connector of the "id", it's a no-op if it does not exist.
+func DeleteConnector(client v1.Client, id string) error {
+	connector, err := ParseConnectorID(id)
+	if err != nil {
+		return err
+	}
+
+	if err = client.DeleteConnector(connector.ClusterName, connector.Name); err != nil && !isStatusNotFound(err) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+
+	v1 "github.com/lensesio/lenses-go/v1"
+)
+
+// Processor is a SQL processor. Its ID is the one of Lenses,
+// it's looked up by the name, the cluster and the namespace of the processor after its creation.
+// Only the Runners can be updated.
+type Processor struct {
+	ID          string `json:"id,omitempty" yaml:"id,omitempty"`
+	Name        string `json:"name" yaml:"name"`
+	SQL         string `json:"sql" yaml:"sql"`
+	Runners     int    `json:"runners" yaml:"runners"`
+	ClusterName string `json:"clusterName,omitempty" yaml:"clusterName,omitempty"`
+	Namespace   string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Pipeline    string `json:"pipeline,omitempty" yaml:"pipeline,omitempty"`
+	// ProcessorID is the optional id of the application of the processor, i.e its consumer group.
+	ProcessorID string `json:"processorId,omitempty" yaml:"processorId,omitempty"`
+}
+
+// ReadProcessor returns the processor of the "id".
+func ReadProcessor(client v1.Client, id string) (Processor, error) {
+	p, err := client.GetProcessor(id)
+	if err != nil {
+		if isStatusNotFound(err) {
+			return Processor{}, notFound("processor", id)
+		}
+		return Processor{}, err
+	}
+
+	if p.ID == "" {
+		return Processor{}, notFound("processor", id)
+	}
+
+	return Processor{
+		ID:          p.ID,
+		Name:        p.Name,
+		SQL:         p.SQL,
+		Runners:     p.Runners,
+		ClusterName: p.ClusterName,
+		Namespace:   p.Namespace,
+		Pipeline:    p.Pipeline,
+		ProcessorID: p.ProcessorID,
+	}, nil
+}
+
+// LookupProcessor returns the ID of the processor of the name, the cluster and the namespace of the "processor", empty if none.
+func LookupProcessor(client v1.Client, processor Processor) (string, error) {
+	result, err := client.GetProcessors()
+	if err != nil {
+		return "", err
+	}
+
+	for _, p := range result.Streams {
+		if p.Name == processor.Name && p.ClusterName == processor.ClusterName && p.Namespace == processor.Namespace {
+			return p.ID, nil
+		}
+	}
+
+	return "", nil
+}
+
+// CreateProcessor creates the processor and returns its ID. An existing one, of the same name, cluster and namespace,
+// is scaled to the runners of the "processor", its SQL is not changed.
+func CreateProcessor(client v1.Client, processor Processor) (string, error) {
+	id, err := LookupProcessor(client, processor)
+	if err != nil {
+		return "", err
+	}
+
+	if id != "" {
+		processor.ID = id
+		return id, UpdateProcessor(client, processor)
+	}
+
+	if err = client.CreateProcessor(processor.Name, processor.SQL, processor.Runners, processor.ClusterName,
+		processor.Namespace, processor.Pipeline, processor.ProcessorID); err != nil {
+		return "", err
+	}
+
+	if id, err = LookupProcessor(client, processor); err != nil {
+		return "", err
+	}
+
+	if id == "" {
+		return "", fmt.Errorf("processor [%s] was created but it's not listed", processor.Name)
+	}
+
+	return id, nil
+}
+
+// UpdateProcessor scales the processor of its `ID` to its runners.
+func UpdateProcessor(client v1.Client, processor Processor) error {
+	current, err := ReadProcessor(client, processor.ID)
+	if err != nil {
+		return err
+	}
+
+	if processor.Runners <= 0 || processor.Runners == current.Runners {
+		return nil
+	}
+
+	return client.UpdateProcessorRunners(processor.ID, processor.Runners)
+}
+
+// DeleteProcessor deletes the processor of the "id", it's a no-op if it does not exist.
+func DeleteProcessor(client v1.Client, id string) error {
+	if _, err := ReadProcessor(client, id); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if err := client.DeleteProcessor(id); err != nil && !isStatusNotFound(err) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+// Package provider is the resource layer of a Terraform provider of Lenses, and of any other declarative tool,
+// on top of the stable `v1.Client`.
+//
+// Each resource, the topics, quotas, ACLs, connectors and processors, has:
+//   - a stable ID, derived from its identity and never from a server-generated value except the processors' one,
+//     which is the import ID of the resource too, i.e `terraform import lenses_topic.orders orders`
+//   - a Read by ID, which returns an error wrapping the `ErrNotFound` if the resource is gone
+//   - an idempotent Create, which converges an existing resource to the desired one instead of failing,
+//     so a retried apply after a timeout succeeds
+//   - an Update of its mutable fields, the others are immutable and their change is a replacement
+//   - an idempotent Delete, which succeeds if the resource is already gone
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+)
+
+// ErrNotFound is the cause of the errors of the Reads of a resource that does not exist (anymore),
+// use `errors.Is(err, ErrNotFound)` to remove the resource from the state.
+var ErrNotFound = errors.New("not found")
+
+func notFound(kind, id string) error {
+	return fmt.Errorf("%s [%s]: %w", kind, id, ErrNotFound)
+}
+
+func isStatusNotFound(err error) bool {
+	return errors.Is(err, api.ResourceError{StatusCode: http.StatusNotFound})
+}
+
+// idEscaper escapes the separator of the parts of an ID, and the escape character, only, so the IDs stay readable.
+var idEscaper = strings.NewReplacer("%", "%25", "/", "%2F")
+
+// joinID returns the ID of the "parts", escaped so a part can contain the separator.
+func joinID(parts ...string) string {
+	for i, part := range parts {
+		parts[i] = idEscaper.Replace(part)
+	}
+
+	return strings.Join(parts, "/")
+}
+
+// splitID returns the "n" parts of the "id" of the "kind", see `joinID`.
+func splitID(kind, id string, n int, format string) ([]string, error) {
+	parts := strings.Split(id, "/")
+	if len(parts) != n {
+		return nil, fmt.Errorf("invalid %s ID [%s], expected [%s]", kind, id, format)
+	}
+
+	for i, part := range parts {
+		unescaped, err := url.PathUnescape(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s ID [%s]: [%v]", kind, id, err)
+		}
+		parts[i] = unescaped
+	}
+
+	return parts, nil
+}
@@ -0,0 +1,243 @@
+package provider
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	v1 "github.com/lensesio/lenses-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClient keeps the topics, ACLs, quotas and connectors in memory, the other methods of the `v1.Client` panic.
+type fakeClient struct {
+	v1.Client
+
+	topics     map[string]api.Topic
+	defaults   map[string]string
+	updates    []api.KV
+	acls       []api.ACL
+	quotas     []api.Quota
+	connectors map[string]api.ConnectorConfig
+	calls      []string
+}
+
+var errNotFound = api.ResourceError{StatusCode: http.StatusNotFound}
+
+func (c *fakeClient) GetTopic(name string) (api.Topic, error) {
+	topic, ok := c.topics[name]
+	if !ok {
+		return api.Topic{}, errNotFound
+	}
+	return topic, nil
+}
+
+func (c *fakeClient) CreateTopic(name string, replication, partitions int, configs api.KV) error {
+	var kvs []api.KV
+	for k, v := range configs {
+		kv := api.KV{"name": k, "originalValue": v, "isDefault": false}
+		if def, ok := c.defaults[k]; ok {
+			kv["defaultValue"] = def
+		}
+		kvs = append(kvs, kv)
+	}
+	c.topics[name] = api.Topic{TopicName: name, Replication: replication, Partitions: partitions, Configs: kvs}
+	c.calls = append(c.calls, "create topic "+name)
+	return nil
+}
+
+func (c *fakeClient) UpdateTopicPartitions(name string, partitions int) error {
+	topic := c.topics[name]
+	topic.Partitions = partitions
+	c.topics[name] = topic
+	c.calls = append(c.calls, "update partitions "+name)
+	return nil
+}
+
+func (c *fakeClient) UpdateTopicConfig(name string, configs []api.KV) error {
+	c.updates = append(c.updates, configs...)
+	return nil
+}
+
+func (c *fakeClient) DeleteTopic(name string) error {
+	delete(c.topics, name)
+	c.calls = append(c.calls, "delete topic "+name)
+	return nil
+}
+
+func (c *fakeClient) GetACLs() ([]api.ACL, error) { return c.acls, nil }
+
+func (c *fakeClient) CreateOrUpdateACL(acl api.ACL) error {
+	c.acls = append(c.acls, acl)
+	return nil
+}
+
+func (c *fakeClient) DeleteACL(acl api.ACL) error {
+	c.calls = append(c.calls, "delete acl "+acl.Principal)
+	return nil
+}
+
+func (c *fakeClient) GetQuotas() ([]api.Quota, error) { return c.quotas, nil }
+
+func (c *fakeClient) CreateOrUpdateQuotaForUserClient(user, clientID string, config api.QuotaConfig) error {
+	c.calls = append(c.calls, "quota "+user+" "+clientID)
+	return nil
+}
+
+func (c *fakeClient) DeleteQuotaForAllClients(propertiesToRemove ...string) error {
+	c.calls = append(c.calls, "delete quota all clients")
+	return nil
+}
+
+func (c *fakeClient) GetConnectorConfig(cluster, name string) (api.ConnectorConfig, error) {
+	config, ok := c.connectors[cluster+"/"+name]
+	if !ok {
+		return nil, errNotFound
+	}
+	return config, nil
+}
+
+func (c *fakeClient) CreateConnector(cluster, name string, config api.ConnectorConfig) (api.Connector, error) {
+	c.connectors[cluster+"/"+name] = config
+	c.calls = append(c.calls, "create connector "+name)
+	return api.Connector{}, nil
+}
+
+func (c *fakeClient) UpdateConnector(cluster, name string, config api.ConnectorConfig) (api.Connector, error) {
+	c.connectors[cluster+"/"+name] = config
+	c.calls = append(c.calls, "update connector "+name)
+	return api.Connector{}, nil
+}
+
+func (c *fakeClient) DeleteConnector(cluster, name string) error {
+	if _, ok := c.connectors[cluster+"/"+name]; !ok {
+		return errNotFound
+	}
+	delete(c.connectors, cluster+"/"+name)
+	return nil
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{topics: make(map[string]api.Topic), defaults: map[string]string{"cleanup.policy": "delete"},
+		connectors: make(map[string]api.ConnectorConfig)}
+}
+
+func TestTopic(t *testing.T) {
+	client := newFakeClient()
+
+	_, err := ReadTopic(client, "orders")
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	topic := Topic{Name: "orders", Partitions: 3, Replication: 1, Configs: map[string]string{"cleanup.policy": "compact"}}
+	id, err := CreateTopic(client, topic)
+	require.NoError(t, err)
+	assert.Equal(t, "orders", id)
+
+	read, err := ReadTopic(client, id)
+	require.NoError(t, err)
+	assert.Equal(t, topic, read)
+
+	// a retried create converges.
+	topic.Partitions = 6
+	topic.Configs = map[string]string{"retention.ms": "1000"}
+	_, err = CreateTopic(client, topic)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"create topic orders", "update partitions orders"}, client.calls)
+	assert.Equal(t, []api.KV{{"retention.ms": "1000", "cleanup.policy": "delete"}}, client.updates)
+
+	// up to date.
+	require.NoError(t, UpdateTopic(client, Topic{Name: "orders", Partitions: 6, Replication: 1, Configs: map[string]string{"cleanup.policy": "compact"}}))
+	assert.Len(t, client.updates, 1)
+
+	// a config without a known default can not be reset.
+	client.topics["orders"] = api.Topic{TopicName: "orders", Replication: 1, Partitions: 6,
+		Configs: []api.KV{{"name": "segment.ms", "originalValue": "1000", "isDefault": false}}}
+	assert.EqualError(t, UpdateTopic(client, Topic{Name: "orders", Partitions: 6, Replication: 1}),
+		"unable to reset the configs [segment.ms] of the topic [orders], their default values are unknown")
+
+	topic.Partitions = 2
+	assert.EqualError(t, UpdateTopic(client, topic),
+		"the partitions of the topic [orders] can not be decreased from [6] to [2], the topic has to be replaced")
+
+	require.NoError(t, DeleteTopic(client, id))
+	require.NoError(t, DeleteTopic(client, id))
+	assert.Equal(t, "delete topic orders", client.calls[len(client.calls)-1])
+	assert.Len(t, client.calls, 3)
+}
+
+func TestACL(t *testing.T) {
+	client := newFakeClient()
+
+	acl := api.ACL{ResourceType: "topic", PatternType: "literal", ResourceName: "orders/eu", Principal: "User:alice",
+		Host: "*", Operation: "read", PermissionType: "allow"}
+	id, err := CreateACL(client, acl)
+	require.NoError(t, err)
+	assert.Equal(t, "TOPIC/LITERAL/orders%2Feu/User:alice/*/READ/ALLOW", id)
+
+	parsed, err := ParseACLID(id)
+	require.NoError(t, err)
+	assert.Equal(t, id, ACLID(parsed))
+
+	read, err := ReadACL(client, id)
+	require.NoError(t, err)
+	assert.Equal(t, "orders/eu", read.ResourceName)
+
+	_, err = ParseACLID("TOPIC/orders")
+	assert.EqualError(t, err, "invalid ACL ID [TOPIC/orders], expected [resourceType/patternType/resourceName/principal/host/operation/permissionType]")
+
+	require.NoError(t, DeleteACL(client, id))
+	client.acls = nil
+	require.NoError(t, DeleteACL(client, id))
+	assert.Equal(t, []string{"delete acl User:alice"}, client.calls)
+}
+
+func TestQuota(t *testing.T) {
+	client := newFakeClient()
+	client.quotas = []api.Quota{
+		{EntityType: api.QuotaEntityUserClient, EntityName: "alice", Child: "billing", Properties: api.QuotaConfig{ProducerByteRate: "1024"}},
+		{EntityType: api.QuotaEntityClientsDefault, EntityName: "<default>"},
+	}
+
+	quota, err := ReadQuota(client, "alice/billing")
+	require.NoError(t, err)
+	assert.Equal(t, Quota{User: "alice", ClientID: "billing", Config: api.QuotaConfig{ProducerByteRate: "1024"}}, quota)
+
+	id, err := CreateQuota(client, quota)
+	require.NoError(t, err)
+	assert.Equal(t, "alice/billing", id)
+
+	_, err = ReadQuota(client, "bob/")
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	_, err = ParseQuotaID("*/billing")
+	assert.EqualError(t, err, "invalid quota, the clients of all the users are not supported")
+
+	require.NoError(t, DeleteQuota(client, "/*"))
+	require.NoError(t, DeleteQuota(client, "bob/"))
+	assert.Equal(t, []string{"quota alice billing", "delete quota all clients"}, client.calls)
+}
+
+func TestConnector(t *testing.T) {
+	client := newFakeClient()
+
+	connector := Connector{ClusterName: "dev", Name: "s3-sink", Config: api.ConnectorConfig{"tasks.max": "1"}}
+	id, err := CreateConnector(client, connector)
+	require.NoError(t, err)
+	assert.Equal(t, "dev/s3-sink", id)
+
+	client.connectors[id]["name"] = "s3-sink"
+	read, err := ReadConnector(client, id)
+	require.NoError(t, err)
+	assert.Equal(t, connector, read)
+
+	_, err = CreateConnector(client, connector)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"create connector s3-sink", "update connector s3-sink"}, client.calls)
+
+	require.NoError(t, DeleteConnector(client, id))
+	require.NoError(t, DeleteConnector(client, id))
+	_, err = ReadConnector(client, id)
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
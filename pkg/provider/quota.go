@@ -0,0 +1,142 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	v1 "github.com/lensesio/lenses-go/v1"
+)
+
+// QuotaDefault is the `User` or the `ClientID` of a `Quota` of all the users or all the clients.
+const QuotaDefault = "*"
+
+// Quota is a Kafka quota of a user, a client, or a client of a user, set by the `User` and the `ClientID`,
+// either can be the `QuotaDefault`. Its ID is "<user>/<client>", with an empty part for the one not set,
+// i.e "alice/", "/billing", "alice/billing" or "*/".
+type Quota struct {
+	User     string
+	ClientID string
+	Config   api.QuotaConfig
+}
+
+// ID returns the ID of the quota.
+func (q Quota) ID() string {
+	return joinID(q.User, q.ClientID)
+}
+
+func (q Quota) validate() error {
+	if q.User == "" && q.ClientID == "" {
+		return fmt.Errorf("invalid quota, the user or the client is required")
+	}
+
+	if q.User == QuotaDefault && q.ClientID != "" {
+		return fmt.Errorf("invalid quota, the clients of all the users are not supported")
+	}
+
+	return nil
+}
+
+// ParseQuotaID returns the quota, without its config, of the "id", see `Quota`.
+func ParseQuotaID(id string) (Quota, error) {
+	parts, err := splitID("quota", id, 2, "user/client")
+	if err != nil {
+		return Quota{}, err
+	}
+
+	q := Quota{User: parts[0], ClientID: parts[1]}
+	return q, q.validate()
+}
+
+// quotaOf returns the user and the client of the quota returned by the server.
+func quotaOf(q api.Quota) Quota {
+	switch q.EntityType {
+	case api.QuotaEntityUser:
+		return Quota{User: q.EntityName, Config: q.Properties}
+	case api.QuotaEntityUsers, api.QuotaEntityUsersDefault:
+		return Quota{User: QuotaDefault, Config: q.Properties}
+	case api.QuotaEntityUserClient:
+		return Quota{User: q.EntityName, ClientID: q.Child, Config: q.Properties}
+	case api.QuotaEntityClient:
+		return Quota{ClientID: q.EntityName, Config: q.Properties}
+	default: // QuotaEntityClients and QuotaEntityClientsDefault.
+		return Quota{ClientID: QuotaDefault, Config: q.Properties}
+	}
+}
+
+// ReadQuota returns the quota of the "id".
+func ReadQuota(client v1.Client, id string) (Quota, error) {
+	if _, err := ParseQuotaID(id); err != nil {
+		return Quota{}, err
+	}
+
+	quotas, err := client.GetQuotas()
+	if err != nil {
+		return Quota{}, err
+	}
+
+	for _, q := range quotas {
+		if quota := quotaOf(q); quota.ID() == id {
+			return quota, nil
+		}
+	}
+
+	return Quota{}, notFound("quota", id)
+}
+
+// CreateQuota sets the quota and returns its ID, an existing one is updated to the "quota".
+func CreateQuota(client v1.Client, quota Quota) (string, error) {
+	if err := UpdateQuota(client, quota); err != nil {
+		return "", err
+	}
+
+	return quota.ID(), nil
+}
+
+// UpdateQuota sets the config of the quota.
+func UpdateQuota(client v1.Client, quota Quota) error {
+	if err := quota.validate(); err != nil {
+		return err
+	}
+
+	switch {
+	case quota.User == QuotaDefault:
+		return client.CreateOrUpdateQuotaForAllUsers(quota.Config)
+	case quota.User != "" && quota.ClientID == QuotaDefault:
+		return client.CreateOrUpdateQuotaForUserAllClients(quota.User, quota.Config)
+	case quota.User != "" && quota.ClientID != "":
+		return client.CreateOrUpdateQuotaForUserClient(quota.User, quota.ClientID, quota.Config)
+	case quota.User != "":
+		return client.CreateOrUpdateQuotaForUser(quota.User, quota.Config)
+	case quota.ClientID == QuotaDefault:
+		return client.CreateOrUpdateQuotaForAllClients(quota.Config)
+	default:
+		return client.CreateOrUpdateQuotaForClient(quota.ClientID, quota.Config)
+	}
+}
+
+// DeleteQuota removes all the constraints of the quota of the "id", it's a no-op if it does not exist.
+func DeleteQuota(client v1.Client, id string) error {
+	quota, err := ReadQuota(client, id)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	switch {
+	case quota.User == QuotaDefault:
+		return client.DeleteQuotaForAllUsers()
+	case quota.User != "" && quota.ClientID == QuotaDefault:
+		return client.DeleteQuotaForUserAllClients(quota.User)
+	case quota.User != "" && quota.ClientID != "":
+		return client.DeleteQuotaForUserClient(quota.User, quota.ClientID)
+	case quota.User != "":
+		return client.DeleteQuotaForUser(quota.User)
+	case quota.ClientID == QuotaDefault:
+		return client.DeleteQuotaForAllClients()
+	default:
+		return client.DeleteQuotaForClient(quota.ClientID)
+	}
+}
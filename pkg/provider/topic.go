@@ -0,0 +1,186 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	v1 "github.com/lensesio/lenses-go/v1"
+)
+
+// Topic is a Kafka topic. Its ID is its name.
+// The Replication is immutable and the Partitions can only be increased.
+type Topic struct {
+	Name        string `json:"name" yaml:"name"`
+	Partitions  int    `json:"partitions" yaml:"partitions"`
+	Replication int    `json:"replication" yaml:"replication"`
+	// Configs are the overridden configs of the topic, the defaults of the broker are not included.
+	Configs map[string]string `json:"configs,omitempty" yaml:"configs,omitempty"`
+}
+
+// ID returns the ID of the topic.
+func (t Topic) ID() string {
+	return t.Name
+}
+
+// ReadTopic returns the topic of the "id".
+func ReadTopic(client v1.Client, id string) (Topic, error) {
+	topic, err := client.GetTopic(id)
+	if err != nil {
+		if isStatusNotFound(err) {
+			return Topic{}, notFound("topic", id)
+		}
+		return Topic{}, err
+	}
+
+	if topic.TopicName == "" || topic.IsMarkedForDeletion {
+		return Topic{}, notFound("topic", id)
+	}
+
+	configs := make(map[string]string)
+	for _, kv := range topic.Configs {
+		name, _ := kv["name"].(string)
+		if name == "" {
+			continue
+		}
+
+		if isDefault, _ := kv["isDefault"].(bool); isDefault {
+			continue
+		}
+
+		v, ok := kv["originalValue"]
+		if !ok {
+			v = kv["value"]
+		}
+		configs[name] = fmt.Sprintf("%v", v)
+	}
+
+	return Topic{Name: topic.TopicName, Partitions: topic.Partitions, Replication: topic.Replication, Configs: configs}, nil
+}
+
+// CreateTopic creates the topic and returns its ID, an existing one is updated to the "topic".
+func CreateTopic(client v1.Client, topic Topic) (string, error) {
+	if _, err := ReadTopic(client, topic.ID()); err == nil {
+		return topic.ID(), UpdateTopic(client, topic)
+	} else if !errors.Is(err, ErrNotFound) {
+		return "", err
+	}
+
+	configs := make(api.KV, len(topic.Configs))
+	for k, v := range topic.Configs {
+		configs[k] = v
+	}
+
+	if err := client.CreateTopic(topic.Name, topic.Replication, topic.Partitions, configs); err != nil {
+		return "", err
+	}
+
+	return topic.ID(), nil
+}
+
+// UpdateTopic increases the partitions of the topic and sets its configs, the ones not in the "topic" are reset to their defaults.
+// It fails if the default value of a config to reset is unknown.
+func UpdateTopic(client v1.Client, topic Topic) error {
+	current, err := ReadTopic(client, topic.ID())
+	if err != nil {
+		return err
+	}
+
+	if topic.Replication != 0 && topic.Replication != current.Replication {
+		return fmt.Errorf("the replication of the topic [%s] can not be changed from [%d] to [%d], the topic has to be replaced",
+			topic.Name, current.Replication, topic.Replication)
+	}
+
+	if topic.Partitions < current.Partitions {
+		return fmt.Errorf("the partitions of the topic [%s] can not be decreased from [%d] to [%d], the topic has to be replaced",
+			topic.Name, current.Partitions, topic.Partitions)
+	}
+
+	if topic.Partitions > current.Partitions {
+		if err = client.UpdateTopicPartitions(topic.Name, topic.Partitions); err != nil {
+			return err
+		}
+	}
+
+	update := make(api.KV, len(topic.Configs))
+	changed := false
+	for k, v := range topic.Configs {
+		update[k] = v
+		if current.Configs[k] != v {
+			changed = true
+		}
+	}
+
+	var reset []string
+	for k := range current.Configs {
+		if _, ok := topic.Configs[k]; !ok {
+			reset = append(reset, k)
+		}
+	}
+
+	if !changed && len(reset) == 0 {
+		return nil
+	}
+
+	if len(reset) > 0 {
+		defaults, err := topicDefaults(client, topic.Name)
+		if err != nil {
+			return err
+		}
+
+		var unknown []string
+		for _, k := range reset {
+			def, ok := defaults[k]
+			if !ok {
+				unknown = append(unknown, k)
+				continue
+			}
+			// the configs which are not in the "topic" are reset to their defaults.
+			update[k] = def
+		}
+
+		if len(unknown) > 0 {
+			sort.Strings(unknown)
+			return fmt.Errorf("unable to reset the configs [%s] of the topic [%s], their default values are unknown",
+				strings.Join(unknown, ", "), topic.Name)
+		}
+	}
+
+	return client.UpdateTopicConfig(topic.Name, []api.KV{update})
+}
+
+// topicDefaults returns the default values of the configs of the topic of the "name".
+func topicDefaults(client v1.Client, name string) (map[string]string, error) {
+	topic, err := client.GetTopic(name)
+	if err != nil {
+		return nil, err
+	}
+
+	defaults := make(map[string]string)
+	for _, kv := range topic.Configs {
+		name, _ := kv["name"].(string)
+		if v, ok := kv["defaultValue"]; ok && v != nil && name != "" {
+			defaults[name] = fmt.Sprintf("%v", v)
+		}
+	}
+
+	return defaults, nil
+}
+
+// DeleteTopic deletes the topic of the "id", it's a no-op if it does not exist.
+func DeleteTopic(client v1.Client, id string) error {
+	if _, err := ReadTopic(client, id); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if err := client.DeleteTopic(id); err != nil && !isStatusNotFound(err) {
+		return err
+	}
+
+	return nil
+}
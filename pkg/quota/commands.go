@@ -9,6 +9,10 @@ import (
 	"github.com/lensesio/lenses-go/pkg"
 	"github.com/lensesio/lenses-go/pkg/api"
 	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/manifest"
+	"github.com/lensesio/lenses-go/pkg/printer"
+	"github.com/lensesio/lenses-go/pkg/utils"
+	v1 "github.com/lensesio/lenses-go/v1"
 	"github.com/spf13/cobra"
 )
 
@@ -25,11 +29,12 @@ func NewGetQuotasCommand() *cobra.Command {
 				return err
 			}
 
-			return bite.PrintObject(cmd, quotas)
+			return printer.PrintObject(cmd, quotas)
 		},
 	}
 
 	bite.CanPrintJSON(cmd)
+	printer.CanSelect(cmd)
 
 	return cmd
 }
@@ -106,7 +111,7 @@ func NewQuotaUsersSubGroupCommand() *cobra.Command {
 	setCommand.Flags().StringVar(&quota.ClientID, "quota-client", "", "Quota client")
 
 	bite.CanBeSilent(setCommand)
-	bite.Prepend(setCommand, bite.FileBind(&quotas, bite.ElseBind(func() error { return bite.TryReadFile(configRaw, &quota.Config) })))
+	bite.Prepend(setCommand, config.ManifestBind(manifest.KindQuotas, &quotas, func() error { return bite.TryReadFile(configRaw, &quota.Config) }))
 
 	rootSub.AddCommand(setCommand)
 
@@ -126,8 +131,22 @@ func NewQuotaUsersSubGroupCommand() *cobra.Command {
 				actionMsg = "update"
 			}
 
+			if err := config.Preflight(actionMsg+" quota", config.PermissionManageKafkaSettings); err != nil {
+				return err
+			}
+
 			// bite.FriendlyError(cmd, errResourceNotAccessibleMessage, "unable to %s quota, user has no rights for this action", actionMsg)
 
+			if name := quota.User; actionMsg == "delete" {
+				if name == "" {
+					name = "default"
+				}
+
+				if err := config.Confirm("user quota", name); err != nil {
+					return err
+				}
+			}
+
 			var user, clientID = quota.User, quota.ClientID
 
 			if user != "" {
@@ -172,6 +191,48 @@ func NewQuotaUsersSubGroupCommand() *cobra.Command {
 
 	rootSub.AddCommand(deleteCommand)
 
+	var patchRaw string
+
+	updateConfigCommand := &cobra.Command{
+		Use:              "update-config",
+		Short:            "Change only some of the configs of the default user quota or a specific user quota (and/or client(s)) with a JSON merge patch, a null value removes the config",
+		Example:          `quota users update-config [--quota-user="user"] [--quota-client=""] --patch="{\"producer_byte_rate\": \"100000\", \"request_percentage\": null}"`,
+		TraverseChildren: true,
+		SilenceErrors:    true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := bite.CheckRequiredFlags(cmd, bite.FlagPair{"patch": patchRaw}); err != nil {
+				return err
+			}
+
+			patch, err := utils.ParsePatch(patchRaw)
+			if err != nil {
+				return err
+			}
+
+			if err = config.Preflight("update quota", config.PermissionManageKafkaSettings); err != nil {
+				return err
+			}
+
+			if _, err = config.Client.PatchUsersQuota(quota.User, quota.ClientID, patch); err != nil {
+				golog.Errorf("Failed to update quota for user [%s], client [%s]. [%s]", quota.User, quota.ClientID, err.Error())
+				return err
+			}
+
+			if quota.User == "" {
+				return bite.PrintInfo(cmd, "Default user quota updated")
+			}
+
+			return bite.PrintInfo(cmd, "Quota for user [%s] updated", quota.User)
+		},
+	}
+
+	updateConfigCommand.Flags().StringVar(&patchRaw, "patch", "", `The configs to change, a JSON object or a file that contains it, .e.g. "{\"producer_byte_rate\": \"100000\"}"`)
+	updateConfigCommand.Flags().StringVar(&quota.User, "quota-user", "", "Quota user")
+	updateConfigCommand.Flags().StringVar(&quota.ClientID, "quota-client", "", "Quota client")
+	bite.CanBeSilent(updateConfigCommand)
+
+	rootSub.AddCommand(updateConfigCommand)
+
 	return rootSub
 }
 
@@ -230,7 +291,7 @@ func NewQuotaClientsSubGroupCommand() *cobra.Command {
 	setCommand.Flags().StringVar(&configRaw, "quota-config", "", `Quota config .e.g. "{\"key\": \"value\"}"`)
 	setCommand.Flags().StringVar(&quota.ClientID, "quota-client", "", "Quota client")
 	bite.CanBeSilent(setCommand)
-	bite.Prepend(setCommand, bite.FileBind(&quotas, bite.ElseBind(func() error { return bite.TryReadFile(configRaw, &quota.Config) })))
+	bite.Prepend(setCommand, config.ManifestBind(manifest.KindQuotas, &quotas, func() error { return bite.TryReadFile(configRaw, &quota.Config) }))
 
 	rootSub.AddCommand(setCommand)
 
@@ -250,8 +311,22 @@ func NewQuotaClientsSubGroupCommand() *cobra.Command {
 				actionMsg = "update"
 			}
 
+			if err := config.Preflight(actionMsg+" quota", config.PermissionManageKafkaSettings); err != nil {
+				return err
+			}
+
 			// bite.FriendlyError(cmd, errResourceNotAccessibleMessage, "unable to %s quota, user has no rights for this action", actionMsg)
 
+			if name := quota.ClientID; actionMsg == "delete" {
+				if name == "" || name == "all" || name == "*" {
+					name = "default"
+				}
+
+				if err := config.Confirm("client quota", name); err != nil {
+					return err
+				}
+			}
+
 			if id := quota.ClientID; id != "" && id != "all" && id != "*" {
 				if err := client.DeleteQuotaForClient(id, args...); err != nil {
 					golog.Errorf("Failed to delete quota for client [%s]. [%s]", quota.ClientID, err.Error())
@@ -275,11 +350,52 @@ func NewQuotaClientsSubGroupCommand() *cobra.Command {
 
 	rootSub.AddCommand(deleteCommand)
 
+	var patchRaw string
+
+	updateConfigCommand := &cobra.Command{
+		Use:              "update-config",
+		Short:            "Change only some of the configs of the default client quota or a specific one with a JSON merge patch, a null value removes the config",
+		Example:          `quota clients update-config [--quota-client=""] --patch="{\"consumer_byte_rate\": \"200000\"}"`,
+		TraverseChildren: true,
+		SilenceErrors:    true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := bite.CheckRequiredFlags(cmd, bite.FlagPair{"patch": patchRaw}); err != nil {
+				return err
+			}
+
+			patch, err := utils.ParsePatch(patchRaw)
+			if err != nil {
+				return err
+			}
+
+			if err = config.Preflight("update quota", config.PermissionManageKafkaSettings); err != nil {
+				return err
+			}
+
+			if _, err = config.Client.PatchClientsQuota(quota.ClientID, patch); err != nil {
+				golog.Errorf("Failed to update quota for client [%s]. [%s]", quota.ClientID, err.Error())
+				return err
+			}
+
+			if id := quota.ClientID; id != "" && id != "all" && id != "*" {
+				return bite.PrintInfo(cmd, "Quota for client [%s] updated", id)
+			}
+
+			return bite.PrintInfo(cmd, "Default client quota updated")
+		},
+	}
+
+	updateConfigCommand.Flags().StringVar(&patchRaw, "patch", "", `The configs to change, a JSON object or a file that contains it, .e.g. "{\"consumer_byte_rate\": \"200000\"}"`)
+	updateConfigCommand.Flags().StringVar(&quota.ClientID, "quota-client", "", "Quota client")
+	bite.CanBeSilent(updateConfigCommand)
+
+	rootSub.AddCommand(updateConfigCommand)
+
 	return rootSub
 }
 
 // CreateQuotaForClients creates quotas for clients
-func CreateQuotaForClients(cmd *cobra.Command, client *api.Client, quota api.CreateQuotaPayload) error {
+func CreateQuotaForClients(cmd *cobra.Command, client v1.Client, quota api.CreateQuotaPayload) error {
 	if id := quota.ClientID; id != "" && id != "all" && id != "*" && strings.HasPrefix(quota.QuotaType, "CLIENT") {
 		if err := client.CreateOrUpdateQuotaForClient(quota.ClientID, quota.Config); err != nil {
 			return err
@@ -293,7 +409,7 @@ func CreateQuotaForClients(cmd *cobra.Command, client *api.Client, quota api.Cre
 }
 
 // CreateQuotaForUsers creates quotas for users
-func CreateQuotaForUsers(cmd *cobra.Command, client *api.Client, quota api.CreateQuotaPayload) error {
+func CreateQuotaForUsers(cmd *cobra.Command, client v1.Client, quota api.CreateQuotaPayload) error {
 	if quota.User != "" && strings.HasPrefix(quota.QuotaType, "USER") {
 		if clientID := quota.ClientID; clientID != "" {
 			if clientID == "all" || clientID == "*" {
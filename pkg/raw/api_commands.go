@@ -0,0 +1,165 @@
+// Package raw contains the pass-through commands, which reach the endpoints of the server that have no command yet
+// through the connection of the current context.
+package raw
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/exitcode"
+	lensesws "github.com/lensesio/lenses-go/pkg/websocket"
+	"github.com/spf13/cobra"
+)
+
+// methods are the HTTP methods of the `api` command.
+var methods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodHead, http.MethodOptions}
+
+// readData returns the body of the "data", the contents of a file if it starts with "@", of the "stdin" if it's "@-",
+// or the "data" itself.
+func readData(data string, stdin io.Reader) ([]byte, error) {
+	switch {
+	case data == "@-":
+		return ioutil.ReadAll(stdin)
+	case strings.HasPrefix(data, "@"):
+		return ioutil.ReadFile(data[1:])
+	default:
+		return []byte(data), nil
+	}
+}
+
+// parseHeaders returns the request option which sets the "headers", of the "name: value" form.
+func parseHeaders(headers []string) (api.RequestOption, error) {
+	h, err := lensesws.ParseHeader(headers)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(r *http.Request) error {
+		for name, values := range h {
+			r.Header[name] = values
+		}
+		return nil
+	}, nil
+}
+
+// writeHeaders writes the status line and the headers of the "resp" to "w", like `curl --include`.
+func writeHeaders(w io.Writer, resp *http.Response) {
+	fmt.Fprintf(w, "%s %s\n", resp.Proto, resp.Status)
+
+	names := make([]string, 0, len(resp.Header))
+	for name := range resp.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, value := range resp.Header[name] {
+			fmt.Fprintf(w, "%s: %s\n", name, value)
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// request sends the "method" request of the "path" with the "body" through the "client" and writes the response body to "w".
+// A failed request returns an `api.ResourceError` with the response body.
+func request(client *api.Client, method, path, contentType string, body []byte, include bool, w io.Writer, options ...api.RequestOption) error {
+	resp, err := client.Do(method, path, contentType, body, options...)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if include {
+		writeHeaders(w, resp)
+	}
+
+	b, err := client.ReadResponseBody(resp)
+	if err != nil {
+		return err
+	}
+
+	if _, err = w.Write(b); err != nil {
+		return err
+	}
+
+	if len(b) > 0 && b[len(b)-1] != '\n' {
+		_, err = fmt.Fprintln(w)
+	}
+
+	return err
+}
+
+//NewAPICommand creates the `api` command
+func NewAPICommand() *cobra.Command {
+	var (
+		data, contentType string
+		headers           []string
+		include           bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "api METHOD PATH",
+		Short: "Send an authenticated request to an endpoint of the server and print its response",
+		Long: `Send an authenticated request to an endpoint of the server, i.e a new one without a command yet, through the connection
+of the current context, with its base path, TLS settings and token, which is refreshed if it expires. The response body is printed as it is.
+--data is the request body, the contents of a file with @path or of the standard input with @-.`,
+		Example: `api GET /api/v1/kafka/topics
+api POST /api/v1/something --data @body.json
+api DELETE "/api/v1/something?force=true" --include
+echo '{"name":"x"}' | api PUT /api/v1/something --data @-`,
+		Args:             cobra.ExactArgs(2),
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			method := strings.ToUpper(args[0])
+			valid := false
+			for _, m := range methods {
+				valid = valid || m == method
+			}
+			if !valid {
+				return exitcode.WithCode(exitcode.Validation, fmt.Errorf("invalid method [%s], expected one of [%s]", args[0], strings.Join(methods, ", ")))
+			}
+
+			// a change through the api is checked and recorded like the one of a mutating command.
+			if method != http.MethodGet && method != http.MethodHead && method != http.MethodOptions {
+				cmd.Annotations = map[string]string{config.AnnotationMutating: "true"}
+				if err := config.CheckProtected(cmd); err != nil {
+					return err
+				}
+			}
+
+			var body []byte
+			if data != "" {
+				b, err := readData(data, cmd.InOrStdin())
+				if err != nil {
+					return fmt.Errorf("unable to read the data [%s]: [%v]", data, err)
+				}
+				body = b
+
+				if contentType == "" {
+					contentType = "application/json"
+				}
+			}
+
+			setHeaders, err := parseHeaders(headers)
+			if err != nil {
+				return exitcode.WithCode(exitcode.Validation, err)
+			}
+
+			return request(config.Client, method, args[1], contentType, body, include, cmd.OutOrStdout(), setHeaders)
+		},
+	}
+
+	cmd.Flags().StringVarP(&data, "data", "d", "", "The request body, @path reads it from a file and @- from the standard input")
+	cmd.Flags().StringVar(&contentType, "content-type", "", "The Content-Type of the request body, application/json by default")
+	cmd.Flags().StringArrayVarP(&headers, "header", "H", nil, `A header of the request, "name: value", repeat it for more`)
+	cmd.Flags().BoolVarP(&include, "include", "i", false, "Print the status and the headers of the response before its body")
+
+	return cmd
+}
@@ -0,0 +1,72 @@
+package raw
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadData(t *testing.T) {
+	b, err := readData(`{"a":1}`, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, string(b))
+
+	b, err = readData("@-", strings.NewReader("from stdin"))
+	assert.NoError(t, err)
+	assert.Equal(t, "from stdin", string(b))
+
+	_, err = readData("@/missing/body.json", nil)
+	assert.Error(t, err)
+}
+
+func TestRequest(t *testing.T) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.RequestURI()+" "+r.Header.Get("Content-Type")+" "+r.Header.Get("X-Team")+" "+string(body))
+		if r.URL.Path == "/lenses/api/v1/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("no such endpoint"))
+			return
+		}
+		w.Header().Set("X-Total", "2")
+		w.Write([]byte(`{"items":2}`))
+	}))
+	defer srv.Close()
+
+	client, err := api.OpenConnection(api.ClientConfig{Host: srv.URL, BasePath: "/lenses", Token: "t0ken"})
+	require.NoError(t, err)
+
+	setHeaders, err := parseHeaders([]string{"X-Team: payments"})
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	assert.NoError(t, request(client, http.MethodPost, "/api/v1/something?dry=true", "application/json", []byte(`{"a":1}`), false, &out, setHeaders))
+	assert.Equal(t, "{\"items\":2}\n", out.String())
+
+	out.Reset()
+	assert.NoError(t, request(client, http.MethodGet, "api/v1/something", "", nil, true, &out))
+	assert.Contains(t, out.String(), "HTTP/1.1 200 OK\n")
+	assert.Contains(t, out.String(), "X-Total: 2\n")
+
+	err = request(client, http.MethodGet, "/api/v1/missing", "", nil, false, &out)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "no such endpoint")
+	}
+
+	assert.Equal(t, []string{
+		`POST /lenses/api/v1/something?dry=true application/json payments {"a":1}`,
+		"GET /lenses/api/v1/something   ",
+		"GET /lenses/api/v1/missing   ",
+	}, requests)
+
+	_, err = parseHeaders([]string{"no-colon"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,209 @@
+package raw
+
+import (
+	"bytes"
+	"crypto/tls"
+	hexdump "encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/lensesio/bite"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	lensesws "github.com/lensesio/lenses-go/pkg/websocket"
+	"github.com/spf13/cobra"
+)
+
+// tokenPlaceholder is replaced by the token of the current context in the frames sent by the `ws` command.
+const tokenPlaceholder = "${token}"
+
+// splitFrames returns the non empty lines of "data", a frame per line, with the `tokenPlaceholder` replaced by the "token".
+func splitFrames(data []byte, token string) [][]byte {
+	var frames [][]byte
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		frames = append(frames, bytes.ReplaceAll(line, []byte(tokenPlaceholder), []byte(token)))
+	}
+
+	return frames
+}
+
+// writeFrame writes a received frame to "w", a text one as it is and a binary one, i.e MessagePack, as a hex dump.
+func writeFrame(w io.Writer, typ int, frame []byte) error {
+	if typ == websocket.BinaryMessage {
+		_, err := fmt.Fprintf(w, "binary message of %d bytes\n%s", len(frame), hexdump.Dump(frame))
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "%s\n", bytes.TrimRight(frame, "\n"))
+	return err
+}
+
+type receivedFrame struct {
+	typ  int
+	data []byte
+	err  error
+}
+
+// wsSession sends the "frames" to the "conn" and writes the frames it receives to "out", see `writeFrame`,
+// until the server closes the connection, "count" frames are received, no frame is received for "idle" or the "stop" is closed.
+// Zero "count" and "idle" mean no limit. It returns the number of the received frames.
+func wsSession(conn *websocket.Conn, frames [][]byte, out io.Writer, count int, idle time.Duration, stop <-chan struct{}) (int, error) {
+	defer conn.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+
+	received := make(chan receivedFrame)
+	go func() {
+		for {
+			typ, data, err := conn.ReadMessage()
+			select {
+			case received <- receivedFrame{typ, data, err}:
+			case <-done:
+				return
+			}
+
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	for _, frame := range frames {
+		if err := conn.WriteMessage(websocket.TextMessage, frame); err != nil {
+			return 0, err
+		}
+	}
+
+	closeConn := func() {
+		conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+	}
+
+	var timeout <-chan time.Time
+	n := 0
+	for {
+		if idle > 0 {
+			timeout = time.After(idle)
+		}
+
+		select {
+		case f := <-received:
+			if f.err != nil {
+				if websocket.IsCloseError(f.err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+					return n, nil
+				}
+				return n, f.err
+			}
+
+			if err := writeFrame(out, f.typ, f.data); err != nil {
+				return n, err
+			}
+
+			if n++; count > 0 && n >= count {
+				closeConn()
+				return n, nil
+			}
+		case <-timeout:
+			closeConn()
+			return n, nil
+		case <-stop:
+			closeConn()
+			return n, nil
+		}
+	}
+}
+
+//NewWSCommand creates the `ws` command
+func NewWSCommand() *cobra.Command {
+	var (
+		endpoint, send string
+		headers        []string
+		count          int
+		idle           time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "ws",
+		Short: "Open an authenticated websocket to an endpoint of the server, send frames and print the ones it receives",
+		Long: `Open an authenticated websocket to an endpoint of the server through the connection of the current context,
+with its base path, TLS settings and its token in the handshake's token header, X-Kafka-Lenses-Token by default.
+The lines of --send are sent as text frames, ` + tokenPlaceholder + ` in them is replaced by the token. The received frames are printed one per line,
+a binary one as a hex dump, until the server closes the connection, --count frames are received, no frame is received for --idle-timeout or Ctrl+C.
+It's for debugging new message types and reproducing protocol issues, --debug-http dumps the handshake too.`,
+		Example: `ws --endpoint /api/ws/v2/sql/execute --send '{"sql":"SELECT * FROM payments LIMIT 3"}'
+ws --endpoint /api/ws/v1/some/endpoint --send @frames.jsonl --idle-timeout 30s
+ws --endpoint /api/ws/v1/some/endpoint --send @frames.jsonl --count 10 > frames.txt`,
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := bite.CheckRequiredFlags(cmd, bite.FlagPair{"endpoint": endpoint}); err != nil {
+				return err
+			}
+
+			client := config.Client
+			var frames [][]byte
+			if send != "" {
+				data, err := readData(send, cmd.InOrStdin())
+				if err != nil {
+					return fmt.Errorf("unable to read the frames [%s]: [%v]", send, err)
+				}
+				frames = splitFrames(data, client.Config.Token)
+			}
+
+			header, err := lensesws.ParseHeader(headers)
+			if err != nil {
+				return err
+			}
+
+			live := lensesws.LiveConfiguration{
+				Host:        client.Config.Host,
+				Debug:       client.Config.Debug,
+				BasePath:    client.Config.BasePath,
+				Message:     lensesws.Message{Token: client.Config.Token},
+				Header:      header,
+				TokenHeader: client.Config.WebsocketTokenHeader,
+			}
+			if live.TokenHeader == "" {
+				live.TokenHeader = lensesws.TokenHeaderLenses
+			}
+			if client.Config.Insecure {
+				live.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+			}
+
+			conn, err := lensesws.OpenRawConnection(live, endpoint)
+			if err != nil {
+				return err
+			}
+
+			stop := make(chan struct{})
+			interrupt := make(chan os.Signal, 1)
+			signal.Notify(interrupt, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
+			defer signal.Stop(interrupt)
+			go func() {
+				if _, ok := <-interrupt; ok {
+					close(stop)
+				}
+			}()
+
+			_, err = wsSession(conn, frames, cmd.OutOrStdout(), count, idle, stop)
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&endpoint, "endpoint", "", "The path of the websocket endpoint, i.e /api/ws/v2/sql/execute")
+	cmd.Flags().StringVar(&send, "send", "", "The frames to send, a line each, @path reads them from a file and @- from the standard input")
+	cmd.Flags().StringArrayVarP(&headers, "header", "H", nil, `A header of the handshake, "name: value", repeat it for more`)
+	cmd.Flags().IntVar(&count, "count", 0, "Close the connection after this many received frames, 0 is no limit")
+	cmd.Flags().DurationVar(&idle, "idle-timeout", 0, "Close the connection when no frame is received for this long, 0 is no limit")
+
+	return cmd
+}
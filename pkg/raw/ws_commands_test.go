@@ -0,0 +1,82 @@
+package raw
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitFrames(t *testing.T) {
+	frames := splitFrames([]byte("{\"token\":\"${token}\",\"sql\":\"SELECT 1\"}\n\n  {\"type\":\"STOP\"}  \n"), "t0ken")
+	assert.Equal(t, [][]byte{
+		[]byte(`{"token":"t0ken","sql":"SELECT 1"}`),
+		[]byte(`{"type":"STOP"}`),
+	}, frames)
+}
+
+func TestWSSession(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		// echoes each frame, then a binary one, then closes if asked to.
+		for {
+			_, frame, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			if string(frame) == "close" {
+				conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+				return
+			}
+
+			conn.WriteMessage(websocket.TextMessage, append([]byte("echo "), frame...))
+			conn.WriteMessage(websocket.BinaryMessage, []byte{0x81, 0xa1, 0x61})
+		}
+	}))
+	defer srv.Close()
+
+	dial := func() *websocket.Conn {
+		conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+		require.NoError(t, err)
+		return conn
+	}
+
+	// until the server closes the connection.
+	var out bytes.Buffer
+	n, err := wsSession(dial(), [][]byte{[]byte(`{"a":1}`), []byte("close")}, &out, 0, 0, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.True(t, strings.HasPrefix(out.String(), "echo {\"a\":1}\nbinary message of 3 bytes\n"), out.String())
+
+	// until the count.
+	out.Reset()
+	n, err = wsSession(dial(), [][]byte{[]byte("one"), []byte("two")}, &out, 3, 0, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	// until it's idle.
+	out.Reset()
+	n, err = wsSession(dial(), [][]byte{[]byte("one")}, &out, 0, 200*time.Millisecond, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	// until it's stopped.
+	stop := make(chan struct{})
+	close(stop)
+	n, err = wsSession(dial(), nil, &out, 0, 0, stop)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
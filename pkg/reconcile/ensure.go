@@ -0,0 +1,133 @@
+package reconcile
+
+import (
+	"errors"
+
+	"github.com/lensesio/lenses-go/pkg/diff"
+	"github.com/lensesio/lenses-go/pkg/provider"
+	v1 "github.com/lensesio/lenses-go/v1"
+)
+
+// EnsureTopic creates the "desired" topic or converges the existing one, its partitions and its configs,
+// the configs missing from the "desired" are reset to their defaults. A zero Replication keeps the current one,
+// a different one is an error, as a decrease of the partitions, the topic has to be replaced.
+func EnsureTopic(client v1.Client, desired provider.Topic) (Result, error) {
+	result := Result{Kind: "topic", ID: desired.ID(), Action: Unchanged}
+
+	current, err := provider.ReadTopic(client, desired.ID())
+	if errors.Is(err, provider.ErrNotFound) {
+		if _, err = provider.CreateTopic(client, desired); err != nil {
+			return result, err
+		}
+		result.Action = Created
+		return result, nil
+	}
+	if err != nil {
+		return result, err
+	}
+
+	if desired.Replication == 0 {
+		desired.Replication = current.Replication
+	}
+
+	if result.Changes, err = diff.Values(current, desired); err != nil || len(result.Changes) == 0 {
+		return result, err
+	}
+
+	if err = provider.UpdateTopic(client, desired); err != nil {
+		return result, err
+	}
+
+	result.Action = Updated
+	return result, nil
+}
+
+// EnsureConnector creates the "desired" connector or sets the config of the existing one if it drifted.
+func EnsureConnector(client v1.Client, desired provider.Connector) (Result, error) {
+	result := Result{Kind: "connector", ID: desired.ID(), Action: Unchanged}
+
+	current, err := provider.ReadConnector(client, desired.ID())
+	if errors.Is(err, provider.ErrNotFound) {
+		if _, err = provider.CreateConnector(client, desired); err != nil {
+			return result, err
+		}
+		result.Action = Created
+		return result, nil
+	}
+	if err != nil {
+		return result, err
+	}
+
+	if result.Changes, err = diff.Values(current, desired); err != nil || len(result.Changes) == 0 {
+		return result, err
+	}
+
+	if err = provider.UpdateConnector(client, desired); err != nil {
+		return result, err
+	}
+
+	result.Action = Updated
+	return result, nil
+}
+
+// EnsureProcessor creates the "desired" processor, by its name, cluster and namespace, or converges the existing one:
+// it's scaled to the runners and it's replaced, deleted and created again, if its SQL drifted.
+// The ID of the `Result` is the one of the processor, a new one if it's replaced.
+func EnsureProcessor(client v1.Client, desired provider.Processor) (Result, error) {
+	result := Result{Kind: "processor", ID: desired.Name, Action: Unchanged}
+
+	id, err := provider.LookupProcessor(client, desired)
+	if err != nil {
+		return result, err
+	}
+
+	if id == "" {
+		if result.ID, err = provider.CreateProcessor(client, desired); err != nil {
+			return result, err
+		}
+		result.Action = Created
+		return result, nil
+	}
+
+	current, err := provider.ReadProcessor(client, id)
+	if err != nil {
+		return result, err
+	}
+
+	result.ID = id
+	desired.ID = id
+	// the unset fields are the server's choice.
+	if desired.Runners <= 0 {
+		desired.Runners = current.Runners
+	}
+	if desired.Pipeline == "" {
+		desired.Pipeline = current.Pipeline
+	}
+	if desired.ProcessorID == "" {
+		desired.ProcessorID = current.ProcessorID
+	}
+
+	if result.Changes, err = diff.Values(current, desired); err != nil || len(result.Changes) == 0 {
+		return result, err
+	}
+
+	if current.SQL == desired.SQL && current.Pipeline == desired.Pipeline && current.ProcessorID == desired.ProcessorID {
+		if err = provider.UpdateProcessor(client, desired); err != nil {
+			return result, err
+		}
+		result.Action = Updated
+		return result, nil
+	}
+
+	if err = provider.DeleteProcessor(client, id); err != nil {
+		return result, err
+	}
+
+	desired.ID = ""
+	if result.ID, err = provider.CreateProcessor(client, desired); err != nil {
+		return result, err
+	}
+
+	result.Action = Replaced
+	return result, nil
+}
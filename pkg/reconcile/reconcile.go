@@ -0,0 +1,48 @@
+// Package reconcile converges the topics, connectors and processors of Lenses to their desired state,
+// for the reconciliation loop of a Kubernetes operator. An "Ensure" function reads the current state,
+// computes the drift from the desired one and applies only what differs, its `Result` summarizes the changes,
+// i.e for the events and the status of the custom resource.
+package reconcile
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/lensesio/lenses-go/pkg/diff"
+)
+
+// The actions of a `Result`.
+const (
+	Unchanged = "unchanged"
+	Created   = "created"
+	Updated   = "updated"
+	// Replaced is a processor deleted and created again, its SQL can not be updated.
+	Replaced = "replaced"
+)
+
+// Result is the outcome of an "Ensure" function.
+type Result struct {
+	Kind   string `json:"kind" yaml:"kind"`
+	ID     string `json:"id" yaml:"id"`
+	Action string `json:"action" yaml:"action"`
+	// Changes are the fields that drifted from the desired state, empty if the resource is created.
+	Changes []diff.Change `json:"changes,omitempty" yaml:"changes,omitempty"`
+}
+
+// Changed reports whether the resource was changed.
+func (r Result) Changed() bool {
+	return r.Action != Unchanged
+}
+
+// String returns the summary of the result, i.e `topic [orders] updated: ~ partitions: 3 => 6; + configs.retention.ms: "1000"`.
+func (r Result) String() string {
+	summary := fmt.Sprintf("%s [%s] %s", r.Kind, r.ID, r.Action)
+	if len(r.Changes) == 0 {
+		return summary
+	}
+
+	var b bytes.Buffer
+	diff.WriteChanges(&b, r.Changes, diff.Options{})
+	return summary + ": " + strings.Join(strings.Split(strings.TrimSpace(b.String()), "\n"), "; ")
+}
@@ -0,0 +1,127 @@
+package reconcile
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	"github.com/lensesio/lenses-go/pkg/provider"
+	v1 "github.com/lensesio/lenses-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClient keeps the topics and the processors in memory, the other methods of the `v1.Client` panic.
+type fakeClient struct {
+	v1.Client
+
+	topics     map[string]api.Topic
+	processors []api.ProcessorStream
+	calls      []string
+}
+
+var errNotFound = api.ResourceError{StatusCode: http.StatusNotFound}
+
+func (c *fakeClient) GetTopic(name string) (api.Topic, error) {
+	topic, ok := c.topics[name]
+	if !ok {
+		return api.Topic{}, errNotFound
+	}
+	return topic, nil
+}
+
+func (c *fakeClient) CreateTopic(name string, replication, partitions int, configs api.KV) error {
+	c.topics[name] = api.Topic{TopicName: name, Replication: replication, Partitions: partitions}
+	c.calls = append(c.calls, "create topic "+name)
+	return nil
+}
+
+func (c *fakeClient) UpdateTopicPartitions(name string, partitions int) error {
+	topic := c.topics[name]
+	topic.Partitions = partitions
+	c.topics[name] = topic
+	c.calls = append(c.calls, fmt.Sprintf("partitions %s %d", name, partitions))
+	return nil
+}
+
+func (c *fakeClient) GetProcessors() (api.ProcessorsResult, error) {
+	return api.ProcessorsResult{Streams: c.processors}, nil
+}
+
+func (c *fakeClient) GetProcessor(id string) (api.ProcessorStream, error) {
+	for _, p := range c.processors {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return api.ProcessorStream{}, errNotFound
+}
+
+func (c *fakeClient) CreateProcessor(name, sql string, runners int, cluster, namespace, pipeline, processorID string) error {
+	id := fmt.Sprintf("p%d", len(c.calls))
+	c.processors = append(c.processors, api.ProcessorStream{ID: id, Name: name, SQL: sql, Runners: runners, ClusterName: cluster, Namespace: namespace})
+	c.calls = append(c.calls, "create processor "+id)
+	return nil
+}
+
+func (c *fakeClient) UpdateProcessorRunners(id string, runners int) error {
+	c.calls = append(c.calls, fmt.Sprintf("scale %s %d", id, runners))
+	return nil
+}
+
+func (c *fakeClient) DeleteProcessor(id string) error {
+	for i, p := range c.processors {
+		if p.ID == id {
+			c.processors = append(c.processors[:i], c.processors[i+1:]...)
+		}
+	}
+	c.calls = append(c.calls, "delete processor "+id)
+	return nil
+}
+
+func TestEnsureTopic(t *testing.T) {
+	client := &fakeClient{topics: make(map[string]api.Topic)}
+	desired := provider.Topic{Name: "orders", Partitions: 3, Replication: 1}
+
+	result, err := EnsureTopic(client, desired)
+	require.NoError(t, err)
+	assert.Equal(t, "topic [orders] created", result.String())
+
+	result, err = EnsureTopic(client, desired)
+	require.NoError(t, err)
+	assert.False(t, result.Changed())
+
+	desired.Partitions, desired.Replication = 6, 0
+	result, err = EnsureTopic(client, desired)
+	require.NoError(t, err)
+	assert.Equal(t, Updated, result.Action)
+	assert.Equal(t, "topic [orders] updated: ~ partitions: 3 => 6", result.String())
+	assert.Equal(t, []string{"create topic orders", "partitions orders 6"}, client.calls)
+}
+
+func TestEnsureProcessor(t *testing.T) {
+	client := &fakeClient{}
+	desired := provider.Processor{Name: "enrich", SQL: "INSERT INTO a SELECT STREAM * FROM b", Runners: 1}
+
+	result, err := EnsureProcessor(client, desired)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Kind: "processor", ID: "p0", Action: Created}, result)
+
+	desired.Runners = 2
+	result, err = EnsureProcessor(client, desired)
+	require.NoError(t, err)
+	assert.Equal(t, "processor [p0] updated: ~ runners: 1 => 2", result.String())
+
+	client.processors[0].Runners = 2
+	result, err = EnsureProcessor(client, desired)
+	require.NoError(t, err)
+	assert.False(t, result.Changed())
+
+	desired.SQL = "INSERT INTO a SELECT STREAM * FROM c"
+	result, err = EnsureProcessor(client, desired)
+	require.NoError(t, err)
+	assert.Equal(t, Replaced, result.Action)
+	assert.Equal(t, "p3", result.ID)
+	assert.Equal(t, []string{"create processor p0", "scale p0 2", "delete processor p0", "create processor p3"}, client.calls)
+}
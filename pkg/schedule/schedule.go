@@ -0,0 +1,191 @@
+// Package schedule runs a Lenses SQL browse query periodically, compares its records with the ones of the previous run
+// and triggers actions, a command or a webhook, when they changed. It's the lightweight alerting of the `sql schedule` command.
+package schedule
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kataras/golog"
+	"github.com/lensesio/lenses-go/pkg/hook"
+	"github.com/lensesio/lenses-go/pkg/sink"
+)
+
+// Change is the outcome of a run whose records differ from the previous run's, it's given to the actions.
+type Change struct {
+	Query string    `json:"query"`
+	Time  time.Time `json:"time"`
+	// Records are all the records of the run.
+	Records []json.RawMessage `json:"records"`
+	// Added are the records which were not returned by the previous run.
+	Added []json.RawMessage `json:"added"`
+	// Removed are the records of the previous run which are not returned anymore.
+	Removed []json.RawMessage `json:"removed"`
+}
+
+// Compare returns the change from the "previous" records to the "current" ones, it reports false if they are the same.
+// The records are compared by their JSON form, so the order does not matter.
+func Compare(previous, current []json.RawMessage) (Change, bool) {
+	counts := make(map[string]int, len(previous))
+	for _, r := range previous {
+		counts[string(r)]++
+	}
+
+	change := Change{Records: current}
+	for _, r := range current {
+		if counts[string(r)] > 0 {
+			counts[string(r)]--
+			continue
+		}
+		change.Added = append(change.Added, r)
+	}
+
+	for _, r := range previous {
+		if counts[string(r)] > 0 {
+			counts[string(r)]--
+			change.Removed = append(change.Removed, r)
+		}
+	}
+
+	return change, len(change.Added) > 0 || len(change.Removed) > 0
+}
+
+// Action is triggered by a `Change`, see `ParseAction`.
+type Action interface {
+	Trigger(change Change) error
+}
+
+// ParseAction parses an action of the `--on-results` flag, "exec <command>" or "webhook <url>".
+func ParseAction(s string) (Action, error) {
+	fields := strings.SplitN(strings.TrimSpace(s), " ", 2)
+	if len(fields) != 2 || strings.TrimSpace(fields[1]) == "" {
+		return nil, fmt.Errorf("invalid action [%s], expected exec <command> or webhook <url>", s)
+	}
+
+	target := strings.TrimSpace(fields[1])
+	switch strings.ToLower(fields[0]) {
+	case "exec":
+		return Exec{Command: target}, nil
+	case "webhook":
+		if !strings.HasPrefix(target, "http://") && !strings.HasPrefix(target, "https://") {
+			return nil, fmt.Errorf("invalid webhook url [%s]", target)
+		}
+		return Webhook{URL: target}, nil
+	default:
+		return nil, fmt.Errorf("unknown action [%s], expected exec <command> or webhook <url>", fields[0])
+	}
+}
+
+// Exec runs the "Command" through the shell, with the JSON `Change` as its input
+// and the LENSES_QUERY, LENSES_RECORDS, LENSES_ADDED and LENSES_REMOVED environment variables.
+type Exec struct {
+	Command string
+	// Timeout is the time the command may run before it's killed, defaults to `hook.DefaultTimeout`.
+	Timeout time.Duration
+}
+
+// Trigger runs the command and waits for it, its output is written to the standard output and error, see `hook.Exec`.
+func (e Exec) Trigger(change Change) error {
+	timeout := e.Timeout
+	if timeout <= 0 {
+		timeout = hook.DefaultTimeout
+	}
+
+	return hook.Exec(e.Command, change, timeout, os.Stdout, os.Stderr,
+		"LENSES_QUERY="+change.Query,
+		"LENSES_RECORDS="+strconv.Itoa(len(change.Records)),
+		"LENSES_ADDED="+strconv.Itoa(len(change.Added)),
+		"LENSES_REMOVED="+strconv.Itoa(len(change.Removed)),
+	)
+}
+
+// Webhook posts the JSON `Change` to the "URL".
+type Webhook struct {
+	URL string
+	// Client defaults to a client with a 30 seconds timeout.
+	Client *http.Client
+}
+
+// Trigger posts the change, a response status code other than 2xx is an error, see `sink.PostJSON`.
+func (w Webhook) Trigger(change Change) error {
+	return sink.PostJSON(w.Client, "webhook", w.URL, nil, change)
+}
+
+// Runner runs the "Query" every "Every" and triggers the "Actions" when its records changed.
+type Runner struct {
+	Query string
+	Every time.Duration
+	// Browse runs the query and returns its records.
+	Browse func(sql string) ([]json.RawMessage, error)
+	// Actions are triggered, in order, on a change. A failed action does not stop the rest or the runner.
+	Actions []Action
+	// Always triggers the actions on every run that returned records, even if they did not change.
+	Always bool
+	// Runs is the number of runs, zero runs until the "stop" of `Start` is closed.
+	Runs int
+
+	previous []json.RawMessage
+	now      func() time.Time
+}
+
+// Run runs the query once and triggers the actions if its records changed since the previous run.
+// It reports whether the actions were triggered.
+func (r *Runner) Run() (bool, error) {
+	records, err := r.Browse(r.Query)
+	if err != nil {
+		return false, err
+	}
+
+	change, changed := Compare(r.previous, records)
+	r.previous = records
+
+	if !changed && !(r.Always && len(records) > 0) {
+		return false, nil
+	}
+
+	change.Query = r.Query
+	change.Time = time.Now()
+	if r.now != nil {
+		change.Time = r.now()
+	}
+
+	for _, action := range r.Actions {
+		if err = action.Trigger(change); err != nil {
+			golog.Errorf("schedule: %v", err)
+		}
+	}
+
+	return true, nil
+}
+
+// Start runs the query right away and then every "Every", until the "stop" is closed or the "Runs" are done.
+// A failed run is logged and the next one runs as scheduled.
+func (r *Runner) Start(stop <-chan struct{}) error {
+	if r.Every <= 0 {
+		return fmt.Errorf("schedule: the interval must be positive, got [%s]", r.Every)
+	}
+
+	ticker := time.NewTicker(r.Every)
+	defer ticker.Stop()
+
+	for runs := 1; ; runs++ {
+		if _, err := r.Run(); err != nil {
+			golog.Errorf("schedule: run [%d] of [%s] failed: [%v]", runs, r.Query, err)
+		}
+
+		if r.Runs > 0 && runs >= r.Runs {
+			return nil
+		}
+
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package schedule
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func records(values ...string) []json.RawMessage {
+	rs := make([]json.RawMessage, 0, len(values))
+	for _, v := range values {
+		rs = append(rs, json.RawMessage(v))
+	}
+	return rs
+}
+
+func TestCompare(t *testing.T) {
+	_, changed := Compare(records(`1`, `2`), records(`2`, `1`))
+	assert.False(t, changed)
+
+	change, changed := Compare(records(`1`, `2`, `2`), records(`2`, `3`))
+	assert.True(t, changed)
+	assert.Equal(t, records(`3`), change.Added)
+	assert.Equal(t, records(`1`, `2`), change.Removed)
+	assert.Equal(t, records(`2`, `3`), change.Records)
+
+	change, changed = Compare(nil, records(`1`))
+	assert.True(t, changed)
+	assert.Equal(t, records(`1`), change.Added)
+	assert.Empty(t, change.Removed)
+}
+
+func TestParseAction(t *testing.T) {
+	action, err := ParseAction("exec ./alert.sh --critical")
+	assert.NoError(t, err)
+	assert.Equal(t, Exec{Command: "./alert.sh --critical"}, action)
+
+	action, err = ParseAction("webhook https://hooks.example.com/lenses")
+	assert.NoError(t, err)
+	assert.Equal(t, Webhook{URL: "https://hooks.example.com/lenses"}, action)
+
+	_, err = ParseAction("webhook hooks.example.com")
+	assert.EqualError(t, err, "invalid webhook url [hooks.example.com]")
+
+	_, err = ParseAction("exec")
+	assert.EqualError(t, err, "invalid action [exec], expected exec <command> or webhook <url>")
+
+	_, err = ParseAction("mail ops@example.com")
+	assert.EqualError(t, err, "unknown action [mail], expected exec <command> or webhook <url>")
+}
+
+type recorder struct{ changes []Change }
+
+func (r *recorder) Trigger(change Change) error {
+	r.changes = append(r.changes, change)
+	return nil
+}
+
+func TestRunner(t *testing.T) {
+	runs := [][]json.RawMessage{records(`1`), records(`1`), records(`1`, `2`), nil}
+	rec := new(recorder)
+	r := &Runner{
+		Query: "SELECT * FROM payments",
+		Every: time.Millisecond,
+		Runs:  len(runs),
+		Browse: func(sql string) ([]json.RawMessage, error) {
+			rs := runs[0]
+			runs = runs[1:]
+			return rs, nil
+		},
+		Actions: []Action{rec},
+	}
+
+	assert.NoError(t, r.Start(make(chan struct{})))
+	if assert.Len(t, rec.changes, 3) {
+		assert.Equal(t, "SELECT * FROM payments", rec.changes[0].Query)
+		assert.Equal(t, records(`1`), rec.changes[0].Added)
+		assert.Equal(t, records(`2`), rec.changes[1].Added)
+		assert.Equal(t, records(`1`, `2`), rec.changes[2].Removed)
+	}
+
+	r.Always, r.previous = true, records(`1`)
+	r.Browse = func(string) ([]json.RawMessage, error) { return records(`1`), nil }
+	triggered, err := r.Run()
+	assert.NoError(t, err)
+	assert.True(t, triggered)
+}
+
+func TestWebhook(t *testing.T) {
+	got := make(chan Change, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		var change Change
+		json.Unmarshal(b, &change)
+		got <- change
+		if change.Query == "fail" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	w := Webhook{URL: srv.URL}
+	assert.NoError(t, w.Trigger(Change{Query: "SELECT 1", Added: records(`{"id":1}`)}))
+	change := <-got
+	assert.Equal(t, "SELECT 1", change.Query)
+	assert.Equal(t, records(`{"id":1}`), change.Added)
+
+	assert.EqualError(t, w.Trigger(Change{Query: "fail"}), "webhook ["+srv.URL+"] failed with status code [502]: []")
+}
+
+func TestExec(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lenses-schedule")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	out := filepath.Join(dir, "change.json")
+	e := Exec{Command: `cat > "` + out + `" && test "$LENSES_ADDED" = 1`}
+	assert.NoError(t, e.Trigger(Change{Query: "SELECT 1", Added: records(`1`)}))
+
+	b, err := ioutil.ReadFile(out)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"added":[1]`)
+
+	assert.Error(t, Exec{Command: "exit 3"}.Trigger(Change{}))
+	assert.EqualError(t, Exec{Command: "exec sleep 5", Timeout: 50 * time.Millisecond}.Trigger(Change{}), "exec [exec sleep 5]: killed after [50ms]")
+}
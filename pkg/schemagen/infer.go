@@ -0,0 +1,372 @@
+package schemagen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/lensesio/lenses-go/pkg/sketch"
+)
+
+// JSONSchemaDraft is the "$schema" of the JSON Schemas of `Inferrer#JSONSchema`.
+const JSONSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// Inferrer infers the schema of JSON values, i.e the values of the records of a topic without a schema,
+// by a sample of them. A field which is missing from or null in some of the values is nullable,
+// the integers are longs unless a decimal number was seen and a field of more types is a union of them.
+// It's not safe for concurrent use.
+type Inferrer struct {
+	root    *node
+	samples int64
+}
+
+// NewInferrer returns an `Inferrer` without samples.
+func NewInferrer() *Inferrer {
+	return &Inferrer{root: newNode()}
+}
+
+// Add adds the JSON "value" to the samples.
+func (in *Inferrer) Add(value json.RawMessage) error {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(value))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return err
+	}
+
+	in.samples++
+	in.root.observe(v)
+	return nil
+}
+
+// Samples returns the number of the added values.
+func (in *Inferrer) Samples() int64 {
+	return in.samples
+}
+
+// node is the types and the statistics of a value, a field or the items of an array, of the samples.
+type node struct {
+	count, nulls                  int64
+	booleans, ints, doubles, strs int64
+	objects, arrays               int64
+	fields                        map[string]*node
+	order                         []string // the field names, by the first time they were seen.
+	items                         *node
+	// distinct is the distinct values which are not objects or arrays, nil if none.
+	distinct *sketch.Distinct
+}
+
+func newNode() *node {
+	return &node{fields: make(map[string]*node)}
+}
+
+func (n *node) observe(v interface{}) {
+	n.count++
+
+	switch v := v.(type) {
+	case nil:
+		n.nulls++
+	case bool:
+		n.booleans++
+		n.addDistinct(fmt.Sprint(v))
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			n.ints++
+		} else {
+			n.doubles++
+		}
+		n.addDistinct(v.String())
+	case string:
+		n.strs++
+		n.addDistinct(v)
+	case map[string]interface{}:
+		n.objects++
+		// in the order of the JSON would be better but the decoded map has none, the names are sorted instead.
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			child, ok := n.fields[name]
+			if !ok {
+				child = newNode()
+				n.fields[name] = child
+				n.order = append(n.order, name)
+			}
+			child.observe(v[name])
+		}
+	case []interface{}:
+		n.arrays++
+		if n.items == nil {
+			n.items = newNode()
+		}
+		for _, item := range v {
+			n.items.observe(item)
+		}
+	}
+}
+
+func (n *node) addDistinct(value string) {
+	if n.distinct == nil {
+		n.distinct = sketch.NewDistinct()
+	}
+	n.distinct.AddString(value)
+}
+
+// optional reports whether the field "n" of an object of "parent" objects is missing from or null in some of them.
+func (n *node) optional(parent int64) bool {
+	return n.nulls > 0 || n.count < parent
+}
+
+// typeNames returns the JSON Schema types of the values of "n", except null.
+func (n *node) typeNames() []string {
+	var names []string
+	if n.objects > 0 {
+		names = append(names, "object")
+	}
+	if n.arrays > 0 {
+		names = append(names, "array")
+	}
+	if n.strs > 0 {
+		names = append(names, "string")
+	}
+	if n.doubles > 0 {
+		names = append(names, "number")
+	} else if n.ints > 0 {
+		names = append(names, "integer")
+	}
+	if n.booleans > 0 {
+		names = append(names, "boolean")
+	}
+
+	return names
+}
+
+// avroGenerator generates the Avro schema of the nodes, the names of its records are unique.
+type avroGenerator struct {
+	names map[string]int
+}
+
+// recordName returns a unique Avro name of the record of a "field".
+func (g *avroGenerator) recordName(field string) string {
+	name := avroName(field)
+	if r := []rune(name); len(r) > 0 {
+		r[0] = unicode.ToUpper(r[0])
+		name = string(r)
+	}
+
+	g.names[name]++
+	if g.names[name] > 1 {
+		name = fmt.Sprintf("%s%d", name, g.names[name])
+	}
+
+	return name
+}
+
+// schema returns the Avro schema of the non null values of "n", a union if they are of more types,
+// "null" if they are all null.
+func (g *avroGenerator) schema(n *node, name string) Schema {
+	var union []Schema
+	for _, typ := range n.typeNames() {
+		switch typ {
+		case "object":
+			union = append(union, g.record(n, g.recordName(name)))
+		case "array":
+			var items Schema = "string"
+			if n.items != nil && len(n.items.typeNames()) > 0 {
+				items = g.nullable(n.items, n.items.nulls > 0, name+"Item")
+			}
+			union = append(union, Array{Type: "array", Items: items})
+		case "string":
+			union = append(union, "string")
+		case "number":
+			union = append(union, "double")
+		case "integer":
+			union = append(union, "long")
+		case "boolean":
+			union = append(union, "boolean")
+		}
+	}
+
+	switch len(union) {
+	case 0:
+		return "null"
+	case 1:
+		return union[0]
+	default:
+		return union
+	}
+}
+
+// nullable returns the schema of "n", as a union with null first if "optional".
+func (g *avroGenerator) nullable(n *node, optional bool, name string) Schema {
+	schema := g.schema(n, name)
+	if !optional || schema == "null" {
+		return schema
+	}
+
+	if union, ok := schema.([]Schema); ok {
+		return append([]Schema{"null"}, union...)
+	}
+
+	return []Schema{"null", schema}
+}
+
+func (g *avroGenerator) record(n *node, name string) Record {
+	record := Record{Type: "record", Name: name, Fields: []Field{}}
+	for _, fieldName := range n.order {
+		child := n.fields[fieldName]
+		field := Field{Name: avroName(fieldName), Type: g.nullable(child, child.optional(n.objects), fieldName)}
+		if union, ok := field.Type.([]Schema); (ok && union[0] == "null") || field.Type == "null" {
+			field.Default = &null
+		}
+		record.Fields = append(record.Fields, field)
+	}
+
+	return record
+}
+
+// avroName returns the "name" with the characters which are not valid in an Avro name replaced by underscores.
+func avroName(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		switch {
+		case r == '_', r < unicode.MaxASCII && unicode.IsLetter(r):
+		case r < unicode.MaxASCII && unicode.IsDigit(r) && i > 0:
+		default:
+			r = '_'
+		}
+		b.WriteRune(r)
+	}
+
+	if b.Len() == 0 {
+		return "_"
+	}
+
+	return b.String()
+}
+
+// Avro returns the inferred Avro schema, a record named by the "opts" if the values are JSON objects.
+func (in *Inferrer) Avro(opts Options) (Schema, error) {
+	if in.samples == 0 {
+		return nil, fmt.Errorf("schemagen: no values to infer the schema of")
+	}
+
+	name := opts.Name
+	if name == "" {
+		name = "Record"
+	}
+
+	g := &avroGenerator{names: map[string]int{avroName(name): 1}}
+	if in.root.objects > 0 && len(in.root.typeNames()) == 1 {
+		record := g.record(in.root, avroName(name))
+		record.Namespace = opts.Namespace
+		return record, nil
+	}
+
+	return g.nullable(in.root, in.root.nulls > 0, name), nil
+}
+
+// JSONSchema returns the inferred JSON Schema, the fields which are present in all the objects are required.
+func (in *Inferrer) JSONSchema() (Schema, error) {
+	if in.samples == 0 {
+		return nil, fmt.Errorf("schemagen: no values to infer the schema of")
+	}
+
+	schema := jsonSchema(in.root, in.root.nulls > 0)
+	schema["$schema"] = JSONSchemaDraft
+	return schema, nil
+}
+
+func jsonSchema(n *node, optional bool) map[string]interface{} {
+	schema := make(map[string]interface{})
+
+	names := n.typeNames()
+	if optional || len(names) == 0 {
+		names = append(names, "null")
+	}
+	if len(names) == 1 {
+		schema["type"] = names[0]
+	} else {
+		schema["type"] = names
+	}
+
+	if n.objects > 0 {
+		properties := make(map[string]interface{}, len(n.fields))
+		required := []string{}
+		for _, name := range n.order {
+			child := n.fields[name]
+			properties[name] = jsonSchema(child, child.nulls > 0)
+			if child.count >= n.objects {
+				required = append(required, name)
+			}
+		}
+		schema["properties"] = properties
+		schema["required"] = required
+	}
+
+	if n.arrays > 0 && n.items != nil && n.items.count > 0 {
+		schema["items"] = jsonSchema(n.items, n.items.nulls > 0)
+	}
+
+	return schema
+}
+
+// InferredField is a field of the inferred schema and its statistics.
+type InferredField struct {
+	// Path is the dot separated path of the field, the items of an array are the "[]" of its path.
+	Path string `json:"path" yaml:"path" header:"Field"`
+	// Type is the JSON Schema types of the field, "|" separated.
+	Type     string `json:"type" yaml:"type" header:"Type"`
+	Nullable bool   `json:"nullable" yaml:"nullable" header:"Nullable"`
+	// Present is the percentage of the objects which have the field, null or not.
+	Present float64 `json:"present" yaml:"present" header:"Present %"`
+	// Distinct is the estimated number of the distinct values of a field which is not an object or an array.
+	Distinct int64 `json:"distinct" yaml:"distinct" header:"Distinct"`
+}
+
+// Fields returns the fields of the inferred schema, the nested ones after their parents.
+func (in *Inferrer) Fields() []InferredField {
+	var fields []InferredField
+	collectFields(&fields, in.root, "")
+	return fields
+}
+
+func collectFields(fields *[]InferredField, n *node, path string) {
+	for _, name := range n.order {
+		child := n.fields[name]
+		fieldPath := name
+		if path != "" {
+			fieldPath = path + "." + name
+		}
+
+		*fields = append(*fields, inferredField(child, fieldPath, n.objects))
+		collectFields(fields, child, fieldPath)
+		if child.items != nil {
+			*fields = append(*fields, inferredField(child.items, fieldPath+"[]", child.items.count))
+			collectFields(fields, child.items, fieldPath+"[]")
+		}
+	}
+}
+
+func inferredField(n *node, path string, parent int64) InferredField {
+	types := n.typeNames()
+	if len(types) == 0 {
+		types = []string{"null"}
+	}
+
+	field := InferredField{Path: path, Type: strings.Join(types, "|"), Nullable: n.optional(parent)}
+	if parent > 0 {
+		field.Present = float64(n.count*10000/parent) / 100
+	}
+	if n.distinct != nil {
+		field.Distinct = n.distinct.Count()
+	}
+
+	return field
+}
@@ -0,0 +1,89 @@
+package schemagen
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func inferrer(t *testing.T, values ...string) *Inferrer {
+	in := NewInferrer()
+	for _, v := range values {
+		require.NoError(t, in.Add(json.RawMessage(v)))
+	}
+	return in
+}
+
+func TestInferAvro(t *testing.T) {
+	in := inferrer(t,
+		`{"id":1,"amount":10,"customer":{"name":"a"},"tags":["x"],"first-name":"a","note":null}`,
+		`{"id":2,"amount":10.5,"customer":{"name":"b","vip":true},"tags":[],"first-name":"b","code":"c"}`,
+		`{"id":3,"amount":7,"customer":{"name":"c"},"tags":["y","z"],"first-name":1,"code":2}`,
+	)
+	assert.Equal(t, int64(3), in.Samples())
+
+	schema, err := in.Avro(Options{Name: "Payment", Namespace: "io.lenses"})
+	require.NoError(t, err)
+
+	s, err := String(schema)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "record", "name": "Payment", "namespace": "io.lenses",
+		"fields": [
+			{"name": "amount", "type": "double"},
+			{"name": "customer", "type": {"type": "record", "name": "Customer", "fields": [
+				{"name": "name", "type": "string"},
+				{"name": "vip", "type": ["null", "boolean"], "default": null}
+			]}},
+			{"name": "first_name", "type": ["string", "long"]},
+			{"name": "id", "type": "long"},
+			{"name": "note", "type": "null", "default": null},
+			{"name": "tags", "type": {"type": "array", "items": "string"}},
+			{"name": "code", "type": ["null", "string", "long"], "default": null}
+		]
+	}`, s)
+
+	// not objects.
+	schema, err = inferrer(t, `"a"`, `null`).Avro(Options{})
+	require.NoError(t, err)
+	assert.Equal(t, []Schema{"null", "string"}, schema)
+
+	_, err = NewInferrer().Avro(Options{})
+	assert.Error(t, err)
+	assert.Error(t, NewInferrer().Add(json.RawMessage(`{`)))
+}
+
+func TestInferJSONSchema(t *testing.T) {
+	in := inferrer(t, `{"id":1,"tags":["x",null],"name":"a"}`, `{"id":2,"tags":[],"name":null}`, `{"id":3,"tags":[]}`)
+
+	schema, err := in.JSONSchema()
+	require.NoError(t, err)
+
+	b, err := json.Marshal(schema)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"type": "object",
+		"properties": {
+			"id": {"type": "integer"},
+			"name": {"type": ["string", "null"]},
+			"tags": {"type": "array", "items": {"type": ["string", "null"]}}
+		},
+		"required": ["id", "tags"]
+	}`, string(b))
+}
+
+func TestInferFields(t *testing.T) {
+	in := inferrer(t, `{"id":1,"customer":{"id":"a"},"tags":["x"]}`, `{"id":1,"customer":{"id":"b"},"tags":["y"]}`,
+		`{"id":2,"customer":null,"tags":["x"]}`, `{"id":3}`)
+
+	assert.Equal(t, []InferredField{
+		{Path: "customer", Type: "object", Nullable: true, Present: 75},
+		{Path: "customer.id", Type: "string", Present: 100, Distinct: 2},
+		{Path: "id", Type: "integer", Present: 100, Distinct: 3},
+		{Path: "tags", Type: "array", Nullable: true, Present: 75},
+		{Path: "tags[]", Type: "string", Present: 100, Distinct: 2},
+	}, in.Fields())
+}
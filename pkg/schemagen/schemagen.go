@@ -0,0 +1,240 @@
+// Package schemagen derives Avro schemas from Go structs, either at runtime through reflection, see `Avro`,
+// or from their source code, see `AvroFromSource`, so producers can register the schema of the records they write.
+//
+// The fields are named by their `avro` tag, or their `json` tag, and a "-" name skips them.
+// A `doc` tag sets the documentation of a field. A pointer is a nullable field, with a null default.
+// The time.Time fields are timestamp-millis longs and the nested structs are records named by their type.
+package schemagen
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Schema is an Avro schema, marshalled as JSON by its `String`.
+type Schema interface{}
+
+// Record is an Avro record schema.
+type Record struct {
+	Type      string  `json:"type"`
+	Name      string  `json:"name"`
+	Namespace string  `json:"namespace,omitempty"`
+	Doc       string  `json:"doc,omitempty"`
+	Fields    []Field `json:"fields"`
+}
+
+// Field is a field of an Avro record.
+type Field struct {
+	Name    string           `json:"name"`
+	Doc     string           `json:"doc,omitempty"`
+	Type    Schema           `json:"type"`
+	Default *json.RawMessage `json:"default,omitempty"`
+}
+
+// Array is an Avro array schema.
+type Array struct {
+	Type  string `json:"type"`
+	Items Schema `json:"items"`
+}
+
+// Map is an Avro map schema, the keys are strings.
+type Map struct {
+	Type   string `json:"type"`
+	Values Schema `json:"values"`
+}
+
+// Logical is an Avro logical type, i.e a timestamp-millis long.
+type Logical struct {
+	Type        string `json:"type"`
+	LogicalType string `json:"logicalType"`
+}
+
+// timestamp is the schema of the time.Time fields.
+var timestamp = Logical{Type: "long", LogicalType: "timestamp-millis"}
+
+var null = json.RawMessage("null")
+
+// primitives are the Avro types of the Go basic types, by their name.
+var primitives = map[string]string{
+	"bool":    "boolean",
+	"int8":    "int",
+	"int16":   "int",
+	"int32":   "int",
+	"uint8":   "int",
+	"uint16":  "int",
+	"int":     "long",
+	"int64":   "long",
+	"uint":    "long",
+	"uint32":  "long",
+	"uint64":  "long",
+	"float32": "float",
+	"float64": "double",
+	"string":  "string",
+}
+
+// Options are the options of `Avro` and `AvroFromSource`.
+type Options struct {
+	// Namespace is the namespace of the top-level record.
+	Namespace string
+	// Name overrides the name of the top-level record, its Go type name by default.
+	Name string
+}
+
+// fieldName returns the Avro name of a struct field by its "tag" and whether it should be skipped.
+func fieldName(goName string, tag reflect.StructTag) (string, bool) {
+	for _, key := range []string{"avro", "json"} {
+		if value, ok := tag.Lookup(key); ok {
+			name := strings.Split(value, ",")[0]
+			if name == "-" {
+				return "", true
+			}
+			if name != "" {
+				return name, false
+			}
+		}
+	}
+
+	return goName, false
+}
+
+// nullable returns the field as a union of null and its type, with a null default.
+func nullable(field Field) Field {
+	field.Type = []Schema{"null", field.Type}
+	field.Default = &null
+	return field
+}
+
+// String returns the JSON form of the "schema".
+func String(schema Schema) (string, error) {
+	b, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// Avro returns the Avro record schema of the struct, or pointer to struct, "v".
+func Avro(v interface{}, opts Options) (Schema, error) {
+	typ := reflect.TypeOf(v)
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("schemagen: [%T] is not a struct", v)
+	}
+
+	g := &reflectGenerator{defined: make(map[reflect.Type]bool)}
+	record, err := g.record(typ)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Name != "" {
+		record.Name = opts.Name
+	}
+	record.Namespace = opts.Namespace
+	return record, nil
+}
+
+type reflectGenerator struct {
+	// defined are the records already defined, which are referenced by their name.
+	defined map[reflect.Type]bool
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (g *reflectGenerator) record(typ reflect.Type) (Record, error) {
+	g.defined[typ] = true
+
+	record := Record{Type: "record", Name: typ.Name(), Fields: []Field{}}
+	if err := g.fields(typ, &record); err != nil {
+		return Record{}, err
+	}
+
+	return record, nil
+}
+
+func (g *reflectGenerator) fields(typ reflect.Type, record *Record) error {
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			if err := g.fields(f.Type, record); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if f.PkgPath != "" { // unexported.
+			continue
+		}
+
+		name, skip := fieldName(f.Name, f.Tag)
+		if skip {
+			continue
+		}
+
+		field := Field{Name: name, Doc: f.Tag.Get("doc")}
+		fieldType := f.Type
+		optional := fieldType.Kind() == reflect.Ptr
+		if optional {
+			fieldType = fieldType.Elem()
+		}
+
+		schema, err := g.schema(fieldType)
+		if err != nil {
+			return fmt.Errorf("schemagen: field [%s.%s]: %v", typ.Name(), f.Name, err)
+		}
+
+		field.Type = schema
+		if optional {
+			field = nullable(field)
+		}
+		record.Fields = append(record.Fields, field)
+	}
+
+	return nil
+}
+
+func (g *reflectGenerator) schema(typ reflect.Type) (Schema, error) {
+	if typ == timeType {
+		return timestamp, nil
+	}
+
+	switch typ.Kind() {
+	case reflect.Struct:
+		if g.defined[typ] {
+			return typ.Name(), nil
+		}
+		return g.record(typ)
+	case reflect.Slice, reflect.Array:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return "bytes", nil
+		}
+		items, err := g.schema(typ.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return Array{Type: "array", Items: items}, nil
+	case reflect.Map:
+		if typ.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("map keys of type [%s] are not supported, only strings", typ.Key())
+		}
+		values, err := g.schema(typ.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return Map{Type: "map", Values: values}, nil
+	case reflect.Ptr:
+		return g.schema(typ.Elem())
+	default:
+		if primitive, ok := primitives[typ.Kind().String()]; ok {
+			return primitive, nil
+		}
+		return nil, fmt.Errorf("type [%s] is not supported", typ)
+	}
+}
@@ -0,0 +1,188 @@
+package schemagen
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Status string
+
+type Address struct {
+	City string `json:"city"`
+}
+
+type Audit struct {
+	CreatedBy string `json:"createdBy"`
+}
+
+type Order struct {
+	Audit
+	ID       int64             `json:"id" doc:"The order id"`
+	Quantity int32             `avro:"qty" json:"quantity"`
+	Price    float64           `json:"price"`
+	Status   Status            `json:"status"`
+	Note     *string           `json:"note,omitempty"`
+	Tags     []string          `json:"tags"`
+	Payload  []byte            `json:"payload"`
+	Attrs    map[string]int    `json:"attrs"`
+	Billing  Address           `json:"billing"`
+	Shipping *Address          `json:"shipping"`
+	Created  time.Time         `json:"created"`
+	Internal string            `json:"-"`
+	secret   string            // unexported.
+	Extra    map[string]string `json:"extra"`
+}
+
+const orderSource = `package model
+
+import "time"
+
+type Status string
+
+type Address struct {
+	City string ` + "`json:\"city\"`" + `
+}
+
+type Audit struct {
+	CreatedBy string ` + "`json:\"createdBy\"`" + `
+}
+
+type Order struct {
+	Audit
+	ID       int64             ` + "`json:\"id\" doc:\"The order id\"`" + `
+	Quantity int32             ` + "`avro:\"qty\" json:\"quantity\"`" + `
+	Price    float64           ` + "`json:\"price\"`" + `
+	Status   Status            ` + "`json:\"status\"`" + `
+	Note     *string           ` + "`json:\"note,omitempty\"`" + `
+	Tags     []string          ` + "`json:\"tags\"`" + `
+	Payload  []byte            ` + "`json:\"payload\"`" + `
+	Attrs    map[string]int    ` + "`json:\"attrs\"`" + `
+	Billing  Address           ` + "`json:\"billing\"`" + `
+	Shipping *Address          ` + "`json:\"shipping\"`" + `
+	Created  time.Time         ` + "`json:\"created\"`" + `
+	Internal string            ` + "`json:\"-\"`" + `
+	secret   string
+	Extra    map[string]string ` + "`json:\"extra\"`" + `
+}
+`
+
+const orderSchema = `{
+  "type": "record",
+  "name": "Order",
+  "namespace": "io.lenses.shop",
+  "fields": [
+    {
+      "name": "createdBy",
+      "type": "string"
+    },
+    {
+      "name": "id",
+      "doc": "The order id",
+      "type": "long"
+    },
+    {
+      "name": "qty",
+      "type": "int"
+    },
+    {
+      "name": "price",
+      "type": "double"
+    },
+    {
+      "name": "status",
+      "type": "string"
+    },
+    {
+      "name": "note",
+      "type": [
+        "null",
+        "string"
+      ],
+      "default": null
+    },
+    {
+      "name": "tags",
+      "type": {
+        "type": "array",
+        "items": "string"
+      }
+    },
+    {
+      "name": "payload",
+      "type": "bytes"
+    },
+    {
+      "name": "attrs",
+      "type": {
+        "type": "map",
+        "values": "long"
+      }
+    },
+    {
+      "name": "billing",
+      "type": {
+        "type": "record",
+        "name": "Address",
+        "fields": [
+          {
+            "name": "city",
+            "type": "string"
+          }
+        ]
+      }
+    },
+    {
+      "name": "shipping",
+      "type": [
+        "null",
+        "Address"
+      ],
+      "default": null
+    },
+    {
+      "name": "created",
+      "type": {
+        "type": "long",
+        "logicalType": "timestamp-millis"
+      }
+    },
+    {
+      "name": "extra",
+      "type": {
+        "type": "map",
+        "values": "string"
+      }
+    }
+  ]
+}`
+
+func TestAvro(t *testing.T) {
+	schema, err := Avro(&Order{}, Options{Namespace: "io.lenses.shop"})
+	assert.NoError(t, err)
+	s, err := String(schema)
+	assert.NoError(t, err)
+	assert.Equal(t, orderSchema, s)
+
+	_, err = Avro(42, Options{})
+	assert.EqualError(t, err, "schemagen: [int] is not a struct")
+
+	_, err = Avro(struct{ C chan int }{}, Options{})
+	assert.EqualError(t, err, "schemagen: field [.C]: type [chan int] is not supported")
+}
+
+func TestAvroFromSource(t *testing.T) {
+	schema, err := AvroFromSource([]byte(orderSource), "Order", Options{Namespace: "io.lenses.shop"})
+	assert.NoError(t, err)
+	s, err := String(schema)
+	assert.NoError(t, err)
+	assert.Equal(t, orderSchema, s)
+
+	schema, err = AvroFromSource([]byte(orderSource), "Address", Options{Name: "ShippingAddress"})
+	assert.NoError(t, err)
+	assert.Equal(t, "ShippingAddress", schema.(Record).Name)
+
+	_, err = AvroFromSource([]byte(orderSource), "Customer", Options{})
+	assert.EqualError(t, err, "schemagen: struct [Customer] is not declared in the source")
+}
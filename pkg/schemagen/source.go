@@ -0,0 +1,177 @@
+package schemagen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strconv"
+)
+
+// AvroFromSource returns the Avro record schema of the struct "typeName" declared in the Go "src" file,
+// the types it references must be declared in the same file, except the time.Time.
+func AvroFromSource(src []byte, typeName string, opts Options) (Schema, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+	if err != nil {
+		return nil, fmt.Errorf("schemagen: %v", err)
+	}
+
+	g := &sourceGenerator{types: make(map[string]ast.Expr), defined: make(map[string]bool)}
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			if ts, ok := spec.(*ast.TypeSpec); ok {
+				g.types[ts.Name.Name] = ts.Type
+			}
+		}
+	}
+
+	st, ok := g.types[typeName].(*ast.StructType)
+	if !ok {
+		return nil, fmt.Errorf("schemagen: struct [%s] is not declared in the source", typeName)
+	}
+
+	record, err := g.record(typeName, st)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Name != "" {
+		record.Name = opts.Name
+	}
+	record.Namespace = opts.Namespace
+	return record, nil
+}
+
+type sourceGenerator struct {
+	// types are the declared types of the file by their name.
+	types map[string]ast.Expr
+	// defined are the records already defined, which are referenced by their name.
+	defined map[string]bool
+}
+
+func (g *sourceGenerator) record(name string, st *ast.StructType) (Record, error) {
+	g.defined[name] = true
+
+	record := Record{Type: "record", Name: name, Fields: []Field{}}
+	if err := g.fields(name, st, &record); err != nil {
+		return Record{}, err
+	}
+
+	return record, nil
+}
+
+func (g *sourceGenerator) fields(typeName string, st *ast.StructType, record *Record) error {
+	for _, f := range st.Fields.List {
+		var tag reflect.StructTag
+		if f.Tag != nil {
+			value, err := strconv.Unquote(f.Tag.Value)
+			if err != nil {
+				return fmt.Errorf("schemagen: invalid tag of [%s]: %v", typeName, err)
+			}
+			tag = reflect.StructTag(value)
+		}
+
+		if len(f.Names) == 0 { // embedded.
+			ident, ok := f.Type.(*ast.Ident)
+			if !ok {
+				return fmt.Errorf("schemagen: embedded field of [%s] is not supported", typeName)
+			}
+			embedded, ok := g.types[ident.Name].(*ast.StructType)
+			if !ok {
+				return fmt.Errorf("schemagen: embedded struct [%s] is not declared in the source", ident.Name)
+			}
+			if err := g.fields(ident.Name, embedded, record); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, ident := range f.Names {
+			if !ident.IsExported() {
+				continue
+			}
+
+			name, skip := fieldName(ident.Name, tag)
+			if skip {
+				continue
+			}
+
+			field := Field{Name: name, Doc: tag.Get("doc")}
+			expr := f.Type
+			star, optional := expr.(*ast.StarExpr)
+			if optional {
+				expr = star.X
+			}
+
+			schema, err := g.schema(expr)
+			if err != nil {
+				return fmt.Errorf("schemagen: field [%s.%s]: %v", typeName, ident.Name, err)
+			}
+
+			field.Type = schema
+			if optional {
+				field = nullable(field)
+			}
+			record.Fields = append(record.Fields, field)
+		}
+	}
+
+	return nil
+}
+
+func (g *sourceGenerator) schema(expr ast.Expr) (Schema, error) {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if primitive, ok := primitives[t.Name]; ok {
+			return primitive, nil
+		}
+		if t.Name == "byte" {
+			return "int", nil
+		}
+
+		declared, ok := g.types[t.Name]
+		if !ok {
+			return nil, fmt.Errorf("type [%s] is not declared in the source", t.Name)
+		}
+		if st, ok := declared.(*ast.StructType); ok {
+			if g.defined[t.Name] {
+				return t.Name, nil
+			}
+			return g.record(t.Name, st)
+		}
+		// a named type, i.e `type Status string`.
+		return g.schema(declared)
+	case *ast.SelectorExpr:
+		if pkg, ok := t.X.(*ast.Ident); ok && pkg.Name == "time" && t.Sel.Name == "Time" {
+			return timestamp, nil
+		}
+		return nil, fmt.Errorf("type [%s.%s] is not supported", t.X, t.Sel.Name)
+	case *ast.StarExpr:
+		return g.schema(t.X)
+	case *ast.ArrayType:
+		if elt, ok := t.Elt.(*ast.Ident); ok && (elt.Name == "byte" || elt.Name == "uint8") {
+			return "bytes", nil
+		}
+		items, err := g.schema(t.Elt)
+		if err != nil {
+			return nil, err
+		}
+		return Array{Type: "array", Items: items}, nil
+	case *ast.MapType:
+		if key, ok := t.Key.(*ast.Ident); !ok || key.Name != "string" {
+			return nil, fmt.Errorf("map keys of type [%v] are not supported, only strings", t.Key)
+		}
+		values, err := g.schema(t.Value)
+		if err != nil {
+			return nil, err
+		}
+		return Map{Type: "map", Values: values}, nil
+	default:
+		return nil, fmt.Errorf("type [%T] is not supported", expr)
+	}
+}
@@ -2,12 +2,20 @@ package schemas
 
 import (
 	"fmt"
+	"io/ioutil"
+	"net/http"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 
 	"github.com/MakeNowJust/heredoc"
 	"github.com/lensesio/bite"
 	"github.com/lensesio/lenses-go/pkg/api"
 	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/exitcode"
+	"github.com/lensesio/lenses-go/pkg/printer"
+	"github.com/lensesio/lenses-go/pkg/schemagen"
 	"github.com/lensesio/lenses-go/pkg/utils"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -26,7 +34,11 @@ func NewSchemasCmd() *cobra.Command {
 			- Create or Update a particular Schema.
 			- Delete a "Schema" or a "Version".
 			- Set the Schema "Compatibility".
-			- Set the Default "Compatibility".
+			- Set or view the Default "Compatibility".
+			- Set or view the global or a Schema's "Mode".
+			- Check the "Compatibility" of a local Schema file.
+			- Generate an "AVRO" Schema from a Go struct.
+			- Sync the Schemas of a context to another one.
 		`),
 		Example: heredoc.Doc(`
 		$ lenses-cli schema-registry
@@ -42,6 +54,12 @@ func NewSchemasCmd() *cobra.Command {
 	rootCmd.AddCommand(SetGlobalCompatibility())
 	rootCmd.AddCommand(RemoveSchemaVersion())
 	rootCmd.AddCommand(RemoveSchema())
+	rootCmd.AddCommand(CheckSchemaCmd())
+	rootCmd.AddCommand(GenSchemaCmd())
+	rootCmd.AddCommand(GetGlobalCompatibility())
+	rootCmd.AddCommand(GetMode())
+	rootCmd.AddCommand(SetMode())
+	rootCmd.AddCommand(config.WithLock(SyncSchemasCmd()))
 
 	return rootCmd
 }
@@ -61,11 +79,12 @@ func ViewSubjectsCmd() *cobra.Command {
 				return err
 			}
 
-			return bite.PrintObject(cmd, subjects)
+			return printer.PrintObject(cmd, subjects)
 		},
 	}
 
 	bite.CanPrintJSON(cmd)
+	printer.CanSelect(cmd)
 	bite.CanBeSilent(cmd)
 
 	return cmd
@@ -270,20 +289,29 @@ func RemoveSchemaVersion() *cobra.Command {
 //RemoveSchema removes a particular schema
 func RemoveSchema() *cobra.Command {
 	var name string
+	var permanent bool
 
 	cmd := &cobra.Command{
 		Use: "remove-schema",
 		Long: heredoc.Doc(`
 		Remove a Schema and all its versions. Note, that this will perform a soft removal
-		of the Schema. Not a permanent one.
+		of the Schema. Not a permanent one, unless "--permanent" is set.
 		`),
 		Example: heredoc.Doc(`
 		$ lenses-cli schema-registry remove-schema --name="<NAME>"
+		$ lenses-cli schema-registry remove-schema --name="<NAME>" --permanent
 		`),
 		SilenceErrors:    true,
 		TraverseChildren: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			client := config.Client
+			if permanent {
+				if err := config.Confirm("schema", name); err != nil {
+					return err
+				}
+				return errors.Wrap(client.HardDeleteSchema(name), "✘ Error")
+			}
+
 			err := client.RemoveSchema(name)
 
 			return errors.Wrap(err, "✘ Error")
@@ -294,8 +322,391 @@ func RemoveSchema() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&name, "name", "", "Schema Name")
+	cmd.Flags().BoolVar(&permanent, "permanent", false, "Remove the Schema permanently, its IDs can not be looked up anymore")
 
 	cmd.MarkFlagRequired("name")
 
 	return cmd
 }
+
+// schemaFormat returns the format of the schema "file" by its extension, AVRO if unknown.
+func schemaFormat(file string) string {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".proto":
+		return "PROTOBUF"
+	case ".json":
+		return "JSON"
+	default:
+		return "AVRO"
+	}
+}
+
+//CheckSchemaCmd checks the compatibility of a local schema file with the latest version of a subject
+func CheckSchemaCmd() *cobra.Command {
+	var file, subject, format string
+
+	cmd := &cobra.Command{
+		Use: "check",
+		Long: heredoc.Doc(`
+		Check the compatibility of a local Schema file with the latest version
+		of a subject, without registering it, i.e on a pre-merge CI check.
+
+		If the Schema is not compatible, the command fails and, for "AVRO",
+		explains the incompatible fields, i.e an added required field or a
+		type change, by the "Compatibility" of the subject.
+		`),
+		Example: heredoc.Doc(`
+		$ lenses-cli schema-registry check -f orders.avsc --subject orders-value
+		$ lenses-cli schema-registry check -f orders.proto --subject orders-value --output json
+		`),
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			b, err := ioutil.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("unable to read the schema file [%s]: [%v]", file, err)
+			}
+
+			if format == "" {
+				format = schemaFormat(file)
+			}
+
+			client := config.Client
+			request := api.WriteSchemaReq{Format: strings.ToUpper(format), Schema: string(b)}
+			result, err := client.CheckSchemaCompatibility(subject, request)
+			if err != nil {
+				var resErr api.ResourceError
+				if errors.As(err, &resErr) && resErr.StatusCode == http.StatusNotFound {
+					return bite.PrintInfo(cmd, "Subject [%s] does not exist, the schema is compatible", subject)
+				}
+				return err
+			}
+
+			if result.IsCompatible {
+				return bite.PrintInfo(cmd, "Schema is compatible with the latest version of subject [%s]", subject)
+			}
+
+			var incompatibilities []Incompatibility
+			if request.Format == "AVRO" {
+				latest, err := client.GetSchema(subject)
+				if err != nil {
+					return err
+				}
+
+				if incompatibilities, err = ExplainAvro(latest.Schema, request.Schema, latest.Compatibility); err != nil {
+					return err
+				}
+			}
+
+			// the registry's own messages explain the rest, i.e of the protobuf schemas.
+			if len(incompatibilities) == 0 {
+				for _, message := range result.Messages {
+					incompatibilities = append(incompatibilities, Incompatibility{Change: "incompatible", Reason: message})
+				}
+			}
+
+			if len(incompatibilities) > 0 {
+				if err := bite.PrintObject(cmd, incompatibilities); err != nil {
+					return err
+				}
+			}
+
+			// fail, so pipelines can stop on incompatible schemas.
+			return exitcode.WithCode(exitcode.Validation, fmt.Errorf("schema is not compatible with the latest version of subject [%s]", subject))
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "The file path of the schema to check")
+	cmd.Flags().StringVar(&subject, "subject", "", "The subject to check the schema against, i.e orders-value")
+	cmd.Flags().StringVar(&format, "format", "", "Schema Format, either one of 'AVRO', 'PROTOBUF', 'JSON', by the file extension if empty")
+
+	cmd.MarkFlagRequired("file")
+	cmd.MarkFlagRequired("subject")
+
+	bite.CanPrintJSON(cmd)
+	bite.CanBeSilent(cmd)
+
+	return cmd
+}
+
+//GenSchemaCmd generates an Avro schema from a Go struct and optionally registers it
+func GenSchemaCmd() *cobra.Command {
+	var file, typeName, subject string
+	var opts schemagen.Options
+
+	cmd := &cobra.Command{
+		Use: "gen",
+		Long: heredoc.Doc(`
+		Generate an "AVRO" Schema from a Go struct of a source file and print it
+		or, with --subject, register it.
+
+		The fields are named by their "avro" or "json" tags, a "doc" tag sets
+		their documentation and the pointers are nullable fields. The structs
+		it references must be declared in the same file.
+
+		Go programs can derive the Schema of a value at runtime with the
+		"schemagen.Avro" function of the "pkg/schemagen" package.
+		`),
+		Example: heredoc.Doc(`
+		$ lenses-cli schema-registry gen -f model/order.go --type Order --namespace io.lenses.shop
+		$ lenses-cli schema-registry gen -f model/order.go --type Order --subject orders-value
+		`),
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// a registration is checked and recorded like the one of a mutating command.
+			if subject != "" {
+				cmd.Annotations = map[string]string{config.AnnotationMutating: "true"}
+				if err := config.CheckProtected(cmd); err != nil {
+					return err
+				}
+			}
+
+			src, err := ioutil.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("unable to read the source file [%s]: [%v]", file, err)
+			}
+
+			schema, err := schemagen.AvroFromSource(src, typeName, opts)
+			if err != nil {
+				return err
+			}
+
+			if subject == "" {
+				return bite.PrintJSON(cmd, schema)
+			}
+
+			s, err := schemagen.String(schema)
+			if err != nil {
+				return err
+			}
+
+			if err = config.Client.WriteSchema(subject, api.WriteSchemaReq{Format: "AVRO", Schema: s}); err != nil {
+				return errors.Wrap(err, "✘ Error")
+			}
+
+			return bite.PrintInfo(cmd, "Schema of [%s] registered to subject [%s]", typeName, subject)
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "The Go source file which declares the struct")
+	cmd.Flags().StringVar(&typeName, "type", "", "The name of the struct")
+	cmd.Flags().StringVar(&opts.Namespace, "namespace", "", "The namespace of the record")
+	cmd.Flags().StringVar(&opts.Name, "record-name", "", "The name of the record, the name of the struct if empty")
+	cmd.Flags().StringVar(&subject, "subject", "", "Register the schema to the subject instead of printing it, i.e orders-value")
+
+	cmd.MarkFlagRequired("file")
+	cmd.MarkFlagRequired("type")
+
+	bite.CanPrintJSON(cmd)
+	bite.CanBeSilent(cmd)
+
+	return cmd
+}
+
+//GetGlobalCompatibility returns the default compatibility
+func GetGlobalCompatibility() *cobra.Command {
+	cmd := &cobra.Command{
+		Use: "get-default",
+		Long: heredoc.Doc(`
+		View the Schema Registry Compatibility, used by the Schemas which
+		have no compatibility set.
+		`),
+		Example: heredoc.Doc(`
+		$ lenses-cli schema-registry get-default
+		`),
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			level, err := config.Client.GetGlobalCompatibility()
+			if err != nil {
+				return errors.Wrap(err, "✘ Error")
+			}
+
+			return bite.PrintObject(cmd, level)
+		},
+	}
+
+	bite.CanPrintJSON(cmd)
+
+	return cmd
+}
+
+//GetMode returns the mode of the schema registry or of a schema
+func GetMode() *cobra.Command {
+	var name string
+
+	cmd := &cobra.Command{
+		Use: "mode",
+		Long: heredoc.Doc(`
+		View the Schema Registry Mode, "IMPORT", "READONLY" or "READWRITE",
+		or the Mode of a Schema if "--name" is set.
+		`),
+		Example: heredoc.Doc(`
+		$ lenses-cli schema-registry mode
+		$ lenses-cli schema-registry mode --name="<NAME>"
+		`),
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mode, err := config.Client.GetSchemaRegistryMode(name)
+			if err != nil {
+				return errors.Wrap(err, "✘ Error")
+			}
+
+			return bite.PrintObject(cmd, mode)
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "Schema Name, the global Mode if empty")
+
+	bite.CanPrintJSON(cmd)
+
+	return cmd
+}
+
+//SetMode sets the mode of the schema registry or of a schema
+func SetMode() *cobra.Command {
+	var name, mode string
+
+	cmd := &cobra.Command{
+		Use: "set-mode",
+		Long: heredoc.Doc(`
+		Set the Schema Registry Mode, or the Mode of a Schema if "--name" is set.
+
+		Options: "IMPORT", "READONLY", "READWRITE"
+
+		The "IMPORT" Mode allows to register Schemas with their IDs, i.e when
+		migrating a registry, and requires the Schemas to be empty.
+		`),
+		Example: heredoc.Doc(`
+		$ lenses-cli schema-registry set-mode --mode="READONLY"
+		$ lenses-cli schema-registry set-mode --name="<NAME>" --mode="READWRITE"
+		`),
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := config.Client.SetSchemaRegistryMode(name, mode)
+
+			return errors.Wrap(err, "✘ Error")
+		},
+		PostRun: func(cmd *cobra.Command, args []string) {
+			fmt.Fprintln(os.Stderr, utils.Green("✓ Request succeeded!"))
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "Schema Name, the global Mode if empty")
+	cmd.Flags().StringVar(&mode, "mode", "", "Schema Registry Mode")
+
+	cmd.MarkFlagRequired("mode")
+
+	return cmd
+}
+
+//SyncSchemasCmd registers the versions of the subjects of a context's registry which are missing from another context's one
+func SyncSchemasCmd() *cobra.Command {
+	var (
+		from, to, pattern string
+		dryRun            bool
+	)
+
+	cmd := &cobra.Command{
+		Use: "sync",
+		Long: heredoc.Doc(`
+		Register the versions of the subjects of the Schema Registry of a context
+		of the configuration file which are missing from the Schema Registry of
+		another context, i.e to promote the Schemas from dev to prod.
+
+		The subjects are synced in dependency order, the subjects their Schemas
+		reference first, and the references point to the versions of the target.
+		A version which is not compatible with the latest version of the target
+		is a conflict, the later versions of its subject are skipped and the
+		command fails. "--dry-run" reports the conflicts without registering.
+		`),
+		Example: heredoc.Doc(`
+		$ lenses-cli schema-registry sync --from dev --to prod --dry-run
+		$ lenses-cli schema-registry sync --from dev --to prod --subjects "^orders-.*"
+		`),
+		// it registers to the --to context, which is checked by `config.CheckProtectedContext` and locked by `config.WithLock`.
+		Annotations:      map[string]string{config.AnnotationMutating: "true", config.AnnotationLockContext: "to"},
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := bite.CheckRequiredFlags(cmd, bite.FlagPair{"from": from, "to": to}); err != nil {
+				return err
+			}
+
+			if from == to {
+				return fmt.Errorf("the --from and --to contexts are the same [%s]", from)
+			}
+
+			if !dryRun {
+				if err := config.CheckProtectedContext(to, cmd); err != nil {
+					return err
+				}
+			}
+
+			filter, err := regexp.Compile(pattern)
+			if err != nil {
+				return exitcode.WithCode(exitcode.Validation, fmt.Errorf("invalid subjects pattern [%s]: [%v]", pattern, err))
+			}
+
+			source, err := config.Manager.ContextClient(from)
+			if err != nil {
+				return err
+			}
+
+			target, err := config.Manager.ContextClient(to)
+			if err != nil {
+				return err
+			}
+
+			all, err := source.GetSubjects()
+			if err != nil {
+				return errors.Wrap(err, "✘ Error")
+			}
+
+			var subjects []string
+			for _, subject := range all {
+				if filter.MatchString(subject.Name) {
+					subjects = append(subjects, subject.Name)
+				}
+			}
+
+			changes, err := syncSchemas(source, target, subjects, dryRun)
+			if err != nil {
+				return err
+			}
+
+			if len(changes) == 0 {
+				return bite.PrintInfo(cmd, "The subjects of context [%s] are in sync with context [%s]", to, from)
+			}
+
+			if err = bite.PrintObject(cmd, changes); err != nil {
+				return err
+			}
+
+			var notSynced int
+			for _, change := range changes {
+				if change.Action != syncRegister {
+					notSynced++
+				}
+			}
+
+			if notSynced > 0 {
+				return exitcode.WithCode(exitcode.Validation, fmt.Errorf("[%d] of [%d] versions can not be synced to context [%s]", notSynced, len(changes), to))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "The context of the configuration file to copy the Schemas from")
+	cmd.Flags().StringVar(&to, "to", "", "The context of the configuration file to register the Schemas to")
+	cmd.Flags().StringVar(&pattern, "subjects", "", "A regular expression of the subjects to sync, all if empty")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Check the compatibility of the missing versions without registering them")
+
+	bite.CanPrintJSON(cmd)
+
+	return cmd
+}
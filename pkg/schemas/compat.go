@@ -0,0 +1,274 @@
+package schemas
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lensesio/lenses-go/pkg/diff"
+)
+
+// The directions of a compatibility mode, see `compatibilityDirections`.
+const (
+	backward = "BACKWARD"
+	forward  = "FORWARD"
+	full     = "FULL"
+	none     = "NONE"
+)
+
+// Incompatibility is a field-level explanation of why a schema is not compatible with the registered one.
+type Incompatibility struct {
+	Path   string `json:"path" yaml:"path" header:"Path"`
+	Change string `json:"change" yaml:"change" header:"Change"`
+	From   string `json:"from,omitempty" yaml:"from,omitempty" header:"From"`
+	To     string `json:"to,omitempty" yaml:"to,omitempty" header:"To"`
+	Reason string `json:"reason" yaml:"reason" header:"Reason"`
+}
+
+// avroField is the shape of an Avro field that matters to the compatibility of a schema.
+type avroField struct {
+	Type       string          `json:"type"`
+	HasDefault bool            `json:"default,omitempty"`
+	Symbols    map[string]bool `json:"symbols,omitempty"`
+}
+
+// avroFields returns the fields of the Avro record "schema", and their nested ones, by their dot separated path.
+func avroFields(schema string) (map[string]avroField, error) {
+	var root interface{}
+	if err := json.Unmarshal([]byte(schema), &root); err != nil {
+		return nil, fmt.Errorf("invalid avro schema: [%v]", err)
+	}
+
+	fields := make(map[string]avroField)
+	collectAvroFields(fields, "", root)
+	return fields, nil
+}
+
+func collectAvroFields(fields map[string]avroField, path string, node interface{}) {
+	switch v := node.(type) {
+	case []interface{}: // union.
+		for _, member := range v {
+			collectAvroFields(fields, path, member)
+		}
+	case map[string]interface{}:
+		switch v["type"] {
+		case "record", "error":
+			list, _ := v["fields"].([]interface{})
+			for _, f := range list {
+				field, ok := f.(map[string]interface{})
+				if !ok {
+					continue
+				}
+
+				name, _ := field["name"].(string)
+				fieldPath := name
+				if path != "" {
+					fieldPath = path + "." + name
+				}
+
+				_, hasDefault := field["default"]
+				fields[fieldPath] = avroField{
+					Type:       avroTypeName(field["type"]),
+					HasDefault: hasDefault,
+					Symbols:    avroSymbols(field["type"]),
+				}
+				collectAvroFields(fields, fieldPath, field["type"])
+			}
+		case "array":
+			collectAvroFields(fields, path+"[]", v["items"])
+		case "map":
+			collectAvroFields(fields, path+"{}", v["values"])
+		}
+	}
+}
+
+// avroTypeName returns the name of an Avro type, i.e "long", "null|string", "array<string>" or "record:Address".
+func avroTypeName(node interface{}) string {
+	switch v := node.(type) {
+	case string:
+		return v
+	case []interface{}:
+		names := make([]string, 0, len(v))
+		for _, member := range v {
+			names = append(names, avroTypeName(member))
+		}
+		return strings.Join(names, "|")
+	case map[string]interface{}:
+		typ, _ := v["type"].(string)
+		switch typ {
+		case "record", "error", "enum", "fixed":
+			name, _ := v["name"].(string)
+			return typ + ":" + name
+		case "array":
+			return "array<" + avroTypeName(v["items"]) + ">"
+		case "map":
+			return "map<" + avroTypeName(v["values"]) + ">"
+		default:
+			if logical, ok := v["logicalType"].(string); ok {
+				return typ + "(" + logical + ")"
+			}
+			return typ
+		}
+	default:
+		return ""
+	}
+}
+
+func avroSymbols(node interface{}) map[string]bool {
+	v, ok := node.(map[string]interface{})
+	if !ok || v["type"] != "enum" {
+		return nil
+	}
+
+	list, _ := v["symbols"].([]interface{})
+	symbols := make(map[string]bool, len(list))
+	for _, s := range list {
+		if name, ok := s.(string); ok {
+			symbols[name] = true
+		}
+	}
+
+	return symbols
+}
+
+// avroPromotions are the types a writer's type can be read as, besides itself.
+var avroPromotions = map[string][]string{
+	"int":    {"long", "float", "double"},
+	"long":   {"float", "double"},
+	"float":  {"double"},
+	"string": {"bytes"},
+	"bytes":  {"string"},
+}
+
+// canRead reports whether the records written with the "writer" type can be read with the "reader" type.
+func canRead(writer, reader string) bool {
+	if writer == reader {
+		return true
+	}
+
+	readers := strings.Split(reader, "|")
+	for _, w := range strings.Split(writer, "|") {
+		readable := false
+		for _, r := range readers {
+			if w == r {
+				readable = true
+				break
+			}
+			for _, p := range avroPromotions[w] {
+				if p == r {
+					readable = true
+				}
+			}
+		}
+		if !readable {
+			return false
+		}
+	}
+
+	return true
+}
+
+// compatibilityDirections returns whether the "compatibility" mode requires the new schema to read the old records,
+// backward, and the old schema to read the new records, forward. The transitive modes are checked against the latest version only.
+func compatibilityDirections(compatibility string) (isBackward, isForward bool) {
+	mode := strings.TrimSuffix(strings.ToUpper(compatibility), "_TRANSITIVE")
+	mode = strings.TrimSuffix(mode, "S") // the "BACKWARDS" and "FORWARDS" spelling of the Lenses docs.
+	switch mode {
+	case none:
+		return false, false
+	case forward:
+		return false, true
+	case full:
+		return true, true
+	default:
+		return true, false
+	}
+}
+
+// hasNewParent reports whether a parent field of the "path" is in the "to" fields but not in the "from" ones.
+func hasNewParent(path string, from, to map[string]avroField) bool {
+	for i := strings.LastIndex(path, "."); i > 0; i = strings.LastIndex(path, ".") {
+		path = strings.TrimRight(path[:i], "[]{}")
+		if _, ok := to[path]; ok {
+			_, existed := from[path]
+			return !existed
+		}
+	}
+
+	return false
+}
+
+// ExplainAvro returns the changes of the "to" Avro schema, compared to the "from" one, which break the "compatibility" mode,
+// i.e a field added without a default or a type changed to one that can not be promoted.
+func ExplainAvro(from, to, compatibility string) ([]Incompatibility, error) {
+	oldFields, err := avroFields(from)
+	if err != nil {
+		return nil, err
+	}
+
+	newFields, err := avroFields(to)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := diff.Values(oldFields, newFields)
+	if err != nil {
+		return nil, err
+	}
+
+	isBackward, isForward := compatibilityDirections(compatibility)
+
+	var result []Incompatibility
+	for _, change := range changes {
+		// a whole field is added or removed, its nested fields are explained by it.
+		if change.Op == diff.Added || change.Op == diff.Removed {
+			if hasNewParent(change.Path, oldFields, newFields) || hasNewParent(change.Path, newFields, oldFields) {
+				continue
+			}
+			if field, ok := newFields[change.Path]; ok && change.Op == diff.Added {
+				if isBackward && !field.HasDefault {
+					result = append(result, Incompatibility{Path: change.Path, Change: "added required field", To: field.Type,
+						Reason: "the field has no default, the new schema can not read the existing records"})
+				}
+				continue
+			}
+			if field, ok := oldFields[change.Path]; ok && change.Op == diff.Removed {
+				if isForward && !field.HasDefault {
+					result = append(result, Incompatibility{Path: change.Path, Change: "removed required field", From: field.Type,
+						Reason: "the field had no default, the consumers of the old schema can not read the new records"})
+				}
+				continue
+			}
+		}
+
+		if path := strings.TrimSuffix(change.Path, ".type"); path != change.Path && change.Op == diff.Changed {
+			fromType, toType := oldFields[path].Type, newFields[path].Type
+			if isBackward && !canRead(fromType, toType) {
+				result = append(result, Incompatibility{Path: path, Change: "type change", From: fromType, To: toType,
+					Reason: "the existing records can not be read as the new type"})
+			} else if isForward && !canRead(toType, fromType) {
+				result = append(result, Incompatibility{Path: path, Change: "type change", From: fromType, To: toType,
+					Reason: "the new records can not be read as the old type by the consumers of the old schema"})
+			}
+			continue
+		}
+
+		if i := strings.LastIndex(change.Path, ".symbols."); i > 0 {
+			path, symbol := change.Path[:i], change.Path[i+len(".symbols."):]
+			if change.Op == diff.Removed && isBackward {
+				result = append(result, Incompatibility{Path: path, Change: "removed enum symbol", From: symbol,
+					Reason: "the existing records with the symbol can not be read by the new schema"})
+			} else if change.Op == diff.Added && isForward {
+				result = append(result, Incompatibility{Path: path, Change: "added enum symbol", To: symbol,
+					Reason: "the new records with the symbol can not be read by the consumers of the old schema"})
+			}
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Path < result[j].Path
+	})
+
+	return result, nil
+}
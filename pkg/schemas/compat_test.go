@@ -0,0 +1,70 @@
+package schemas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const ordersV1 = `{"type":"record","name":"Order","fields":[
+	{"name":"id","type":"string"},
+	{"name":"quantity","type":"int"},
+	{"name":"price","type":"double"},
+	{"name":"note","type":["null","string"],"default":null},
+	{"name":"status","type":{"type":"enum","name":"Status","symbols":["NEW","PAID","SHIPPED"]}},
+	{"name":"customer","type":{"type":"record","name":"Customer","fields":[{"name":"name","type":"string"}]}}
+]}`
+
+const ordersV2 = `{"type":"record","name":"Order","fields":[
+	{"name":"id","type":"long"},
+	{"name":"quantity","type":"long"},
+	{"name":"price","type":"double"},
+	{"name":"currency","type":"string"},
+	{"name":"coupon","type":["null","string"],"default":null},
+	{"name":"status","type":{"type":"enum","name":"Status","symbols":["NEW","PAID","CANCELLED"]}},
+	{"name":"customer","type":{"type":"record","name":"Customer","fields":[{"name":"name","type":"string"},{"name":"email","type":"string"}]}},
+	{"name":"address","type":{"type":"record","name":"Address","fields":[{"name":"city","type":"string"}]},"default":{"city":""}}
+]}`
+
+func TestExplainAvroBackward(t *testing.T) {
+	incompatibilities, err := ExplainAvro(ordersV1, ordersV2, "BACKWARD")
+	assert.NoError(t, err)
+	assert.Equal(t, []Incompatibility{
+		{Path: "currency", Change: "added required field", To: "string", Reason: "the field has no default, the new schema can not read the existing records"},
+		{Path: "customer.email", Change: "added required field", To: "string", Reason: "the field has no default, the new schema can not read the existing records"},
+		{Path: "id", Change: "type change", From: "string", To: "long", Reason: "the existing records can not be read as the new type"},
+		{Path: "status", Change: "removed enum symbol", From: "SHIPPED", Reason: "the existing records with the symbol can not be read by the new schema"},
+	}, incompatibilities)
+}
+
+func TestExplainAvroForward(t *testing.T) {
+	incompatibilities, err := ExplainAvro(ordersV1, ordersV2, "FORWARD_TRANSITIVE")
+	assert.NoError(t, err)
+	assert.Equal(t, []Incompatibility{
+		{Path: "id", Change: "type change", From: "string", To: "long", Reason: "the new records can not be read as the old type by the consumers of the old schema"},
+		{Path: "quantity", Change: "type change", From: "int", To: "long", Reason: "the new records can not be read as the old type by the consumers of the old schema"},
+		{Path: "status", Change: "added enum symbol", To: "CANCELLED", Reason: "the new records with the symbol can not be read by the consumers of the old schema"},
+	}, incompatibilities)
+
+	incompatibilities, err = ExplainAvro(ordersV2, ordersV1, "FORWARD")
+	assert.NoError(t, err)
+	assert.Contains(t, incompatibilities, Incompatibility{Path: "currency", Change: "removed required field", From: "string",
+		Reason: "the field had no default, the consumers of the old schema can not read the new records"})
+	assert.NotContains(t, incompatibilities, Incompatibility{Path: "coupon", Change: "removed required field", From: "null|string",
+		Reason: "the field had no default, the consumers of the old schema can not read the new records"})
+}
+
+func TestExplainAvroNone(t *testing.T) {
+	incompatibilities, err := ExplainAvro(ordersV1, ordersV2, "NONE")
+	assert.NoError(t, err)
+	assert.Empty(t, incompatibilities)
+
+	_, err = ExplainAvro(ordersV1, "{", "BACKWARD")
+	assert.Error(t, err)
+}
+
+func TestSchemaFormat(t *testing.T) {
+	assert.Equal(t, "AVRO", schemaFormat("orders.avsc"))
+	assert.Equal(t, "PROTOBUF", schemaFormat("orders.proto"))
+	assert.Equal(t, "JSON", schemaFormat("orders.JSON"))
+}
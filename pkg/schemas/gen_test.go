@@ -0,0 +1,29 @@
+package schemas
+
+import (
+	"testing"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenSchemaCmdProtected(t *testing.T) {
+	defaultManager := config.Manager
+	defer func() { config.Manager = defaultManager }()
+
+	config.Manager = config.NewEmptyConfigManager()
+	config.Manager.Config.CurrentContext = "prod"
+	config.Manager.Config.Contexts["prod"] = &api.ClientConfig{Host: "http://lenses:9991", Protected: true}
+
+	// the registration to a subject is refused before the source is read.
+	cmd := GenSchemaCmd()
+	cmd.SetArgs([]string{"-f", "order.go", "--type", "Order", "--subject", "orders-value"})
+	err := cmd.Execute()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "context [prod] is protected")
+	}
+	assert.True(t, config.IsMutating(cmd))
+
+	assert.False(t, config.IsMutating(GenSchemaCmd()))
+}
@@ -0,0 +1,217 @@
+package schemas
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	"github.com/pkg/errors"
+)
+
+// The actions of a version of a subject of a `schema-registry sync`.
+const (
+	syncRegister = "register"
+	syncConflict = "conflict"
+	syncSkip     = "skip"
+	syncFailed   = "failed"
+)
+
+// SyncChange is a version of a subject of the source registry which is missing from the target one.
+type SyncChange struct {
+	Subject string `json:"subject" yaml:"subject" header:"Subject"`
+	Version int    `json:"version" yaml:"version" header:"Version"`
+	Format  string `json:"format" yaml:"format" header:"Format"`
+	Action  string `json:"action" yaml:"action" header:"Action"`
+	Reason  string `json:"reason,omitempty" yaml:"reason,omitempty" header:"Reason"`
+}
+
+// registry is the Schema Registry API of a context that the sync uses, see `api.Client`.
+type registry interface {
+	GetSchemaVersions(name string) ([]int, error)
+	GetSchemaVersion(name string, version int) (api.SchemaVersion, error)
+	RegisterSchemaVersion(name string, schema api.SchemaVersion) (int, error)
+	CheckSchemaCompatibility(name string, request api.WriteSchemaReq) (api.SchemaCompatibilityRes, error)
+}
+
+func isStatus(err error, statusCode int) bool {
+	var resErr api.ResourceError
+	return errors.As(err, &resErr) && resErr.StatusCode == statusCode
+}
+
+// subjectVersions returns the versions of the "subject", none if it does not exist.
+func subjectVersions(r registry, subject string) ([]api.SchemaVersion, error) {
+	numbers, err := r.GetSchemaVersions(subject)
+	if err != nil {
+		if isStatus(err, http.StatusNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unable to read the versions of subject [%s]: [%v]", subject, err)
+	}
+
+	versions := make([]api.SchemaVersion, 0, len(numbers))
+	for _, n := range numbers {
+		v, err := r.GetSchemaVersion(subject, n)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read version [%d] of subject [%s]: [%v]", n, subject, err)
+		}
+		v.Subject, v.Version = subject, n
+		versions = append(versions, v)
+	}
+
+	return versions, nil
+}
+
+// dependencyOrder returns the subjects of the "versions" sorted by name, each one after the subjects its versions reference.
+func dependencyOrder(versions map[string][]api.SchemaVersion) []string {
+	names := make([]string, 0, len(versions))
+	for name := range versions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var (
+		order   []string
+		visited = make(map[string]bool, len(names))
+		visit   func(string)
+	)
+	visit = func(name string) {
+		if visited[name] {
+			return
+		}
+		visited[name] = true
+		for _, v := range versions[name] {
+			for _, ref := range v.References {
+				visit(ref.Subject)
+			}
+		}
+		order = append(order, name)
+	}
+
+	for _, name := range names {
+		visit(name)
+	}
+
+	return order
+}
+
+// syncSchemas registers the versions of the "subjects" of the "source" registry, and of the subjects they reference,
+// which are missing from the "target" registry, in dependency order and with their references translated to the target's versions.
+// A version which is not compatible with the target's latest one is a conflict, the later versions of its subject
+// and the versions which reference a version that is not synced are skipped. A dry run checks the compatibility without registering.
+func syncSchemas(source, target registry, subjects []string, dryRun bool) ([]SyncChange, error) {
+	versions := make(map[string][]api.SchemaVersion)
+	for pending := append([]string(nil), subjects...); len(pending) > 0; pending = pending[1:] {
+		subject := pending[0]
+		if _, ok := versions[subject]; ok {
+			continue
+		}
+
+		vs, err := subjectVersions(source, subject)
+		if err != nil {
+			return nil, err
+		}
+
+		versions[subject] = vs
+		for _, v := range vs {
+			for _, ref := range v.References {
+				pending = append(pending, ref.Subject)
+			}
+		}
+	}
+
+	var (
+		changes []SyncChange
+		// synced are the target versions of the source versions of each subject which are, or would be on a dry run, registered.
+		synced = make(map[string]map[int]int)
+	)
+	for _, subject := range dependencyOrder(versions) {
+		existing, err := subjectVersions(target, subject)
+		if err != nil {
+			return nil, err
+		}
+
+		registered := make(map[string]int, len(existing))
+		for _, v := range existing {
+			registered[v.Schema] = v.Version
+		}
+
+		synced[subject] = make(map[int]int)
+		blocked, added := false, false
+		for _, v := range versions[subject] {
+			if version, ok := registered[v.Schema]; ok {
+				synced[subject][v.Version] = version
+				continue
+			}
+
+			change := SyncChange{Subject: subject, Version: v.Version, Format: v.Format(), Action: syncRegister}
+			schema := v
+			schema.References = make([]api.SchemaReference, len(v.References))
+			for i, ref := range v.References {
+				version, ok := synced[ref.Subject][ref.Version]
+				if !ok && change.Action == syncRegister {
+					change.Action, change.Reason = syncSkip, fmt.Sprintf("the referenced version [%d] of subject [%s] is not synced", ref.Version, ref.Subject)
+				}
+				ref.Version = version
+				schema.References[i] = ref
+			}
+
+			if blocked {
+				change.Action, change.Reason = syncSkip, "a previous version is not synced"
+			}
+
+			// the compatibility endpoint does not accept references, the registration checks them.
+			if change.Action == syncRegister && len(v.References) == 0 {
+				result, err := target.CheckSchemaCompatibility(subject, api.WriteSchemaReq{Format: v.Format(), Schema: v.Schema})
+				if err != nil && !isStatus(err, http.StatusNotFound) {
+					change.Action, change.Reason = syncFailed, err.Error()
+				} else if err == nil && !result.IsCompatible {
+					change.Action, change.Reason = syncConflict, "not compatible with the latest version of the target"
+					if len(result.Messages) > 0 {
+						change.Reason = strings.Join(result.Messages, "; ")
+					}
+				}
+			}
+
+			if change.Action == syncRegister && !dryRun {
+				if _, err := target.RegisterSchemaVersion(subject, schema); err != nil {
+					change.Action, change.Reason = syncFailed, err.Error()
+					if isStatus(err, http.StatusConflict) {
+						change.Action = syncConflict
+					}
+				}
+			}
+
+			if change.Action == syncRegister {
+				added = true
+				// the target's version is known once registered, until then the dry run assumes the source's one.
+				synced[subject][v.Version] = v.Version
+			} else {
+				blocked = true
+			}
+
+			changes = append(changes, change)
+		}
+
+		if !added || dryRun {
+			continue
+		}
+
+		if existing, err = subjectVersions(target, subject); err != nil {
+			return nil, err
+		}
+
+		for _, v := range existing {
+			registered[v.Schema] = v.Version
+		}
+
+		for _, v := range versions[subject] {
+			if version, ok := registered[v.Schema]; ok {
+				synced[subject][v.Version] = version
+			}
+		}
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,106 @@
+package schemas
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryRegistry is a registry whose schemas containing "breaking" are not compatible.
+type memoryRegistry map[string][]api.SchemaVersion
+
+func (r memoryRegistry) GetSchemaVersions(name string) ([]int, error) {
+	versions, ok := r[name]
+	if !ok {
+		return nil, api.ResourceError{StatusCode: http.StatusNotFound}
+	}
+
+	numbers := make([]int, len(versions))
+	for i, v := range versions {
+		numbers[i] = v.Version
+	}
+	return numbers, nil
+}
+
+func (r memoryRegistry) GetSchemaVersion(name string, version int) (api.SchemaVersion, error) {
+	for _, v := range r[name] {
+		if v.Version == version {
+			return v, nil
+		}
+	}
+	return api.SchemaVersion{}, api.ResourceError{StatusCode: http.StatusNotFound}
+}
+
+func (r memoryRegistry) RegisterSchemaVersion(name string, schema api.SchemaVersion) (int, error) {
+	schema.Subject, schema.Version, schema.ID = name, len(r[name])+1, 100+len(r[name])
+	r[name] = append(r[name], schema)
+	return schema.ID, nil
+}
+
+func (r memoryRegistry) CheckSchemaCompatibility(name string, request api.WriteSchemaReq) (api.SchemaCompatibilityRes, error) {
+	if len(r[name]) == 0 {
+		return api.SchemaCompatibilityRes{}, api.ResourceError{StatusCode: http.StatusNotFound}
+	}
+	if strings.Contains(request.Schema, "breaking") {
+		return api.SchemaCompatibilityRes{Messages: []string{"READER_FIELD_MISSING_DEFAULT_VALUE"}}, nil
+	}
+	return api.SchemaCompatibilityRes{IsCompatible: true}, nil
+}
+
+func TestSyncSchemas(t *testing.T) {
+	source := memoryRegistry{
+		"common": {{Version: 1, SchemaType: "PROTOBUF", Schema: "common-1"}, {Version: 2, SchemaType: "PROTOBUF", Schema: "common-2"}},
+		"orders-value": {
+			{Version: 1, SchemaType: "PROTOBUF", Schema: "orders-1", References: []api.SchemaReference{{Name: "common.proto", Subject: "common", Version: 2}}},
+		},
+		"payments-value": {{Version: 1, Schema: "payments-1"}, {Version: 2, Schema: "payments-2-breaking"}, {Version: 3, Schema: "payments-3"}},
+		"users-value":    {{Version: 1, Schema: "users-1"}},
+	}
+	target := memoryRegistry{
+		"common":         {{Version: 1, SchemaType: "PROTOBUF", Schema: "common-0"}},
+		"payments-value": {{Version: 1, Schema: "payments-1"}},
+	}
+
+	// the dry run registers nothing, the referenced subject is synced too.
+	changes, err := syncSchemas(source, target, []string{"orders-value", "payments-value"}, true)
+	assert.NoError(t, err)
+	expected := []SyncChange{
+		{Subject: "common", Version: 1, Format: "PROTOBUF", Action: syncRegister},
+		{Subject: "common", Version: 2, Format: "PROTOBUF", Action: syncRegister},
+		{Subject: "orders-value", Version: 1, Format: "PROTOBUF", Action: syncRegister},
+		{Subject: "payments-value", Version: 2, Format: "AVRO", Action: syncConflict, Reason: "READER_FIELD_MISSING_DEFAULT_VALUE"},
+		{Subject: "payments-value", Version: 3, Format: "AVRO", Action: syncSkip, Reason: "a previous version is not synced"},
+	}
+	assert.Equal(t, expected, changes)
+	assert.Len(t, target["common"], 1)
+	assert.NotContains(t, target, "orders-value")
+
+	changes, err = syncSchemas(source, target, []string{"orders-value", "payments-value"}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, changes)
+	assert.Len(t, target["common"], 3)
+	assert.Len(t, target["payments-value"], 1)
+	// the reference points to the target's version of the referenced schema.
+	assert.Equal(t, []api.SchemaReference{{Name: "common.proto", Subject: "common", Version: 3}}, target["orders-value"][0].References)
+
+	changes, err = syncSchemas(source, target, []string{"orders-value"}, false)
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestDependencyOrder(t *testing.T) {
+	ref := func(subject string) []api.SchemaReference {
+		return []api.SchemaReference{{Subject: subject, Version: 1}}
+	}
+
+	order := dependencyOrder(map[string][]api.SchemaVersion{
+		"a": {{References: ref("c")}},
+		"b": nil,
+		"c": {{References: ref("d")}},
+		"d": nil,
+	})
+	assert.Equal(t, []string{"d", "c", "a", "b"}, order)
+}
@@ -0,0 +1,61 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lensesio/bite"
+	"github.com/lensesio/lenses-go/pkg/api"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/spf13/cobra"
+)
+
+//NewSearchCommand creates `search` command
+func NewSearchCommand() *cobra.Command {
+	var opts api.SearchOptions
+
+	cmd := &cobra.Command{
+		Use:   "search <term>",
+		Short: "Search the Lenses catalog for topics, fields, connectors and processors",
+		Long: `Search the Lenses catalog for the topics, the Elasticsearch indexes, the fields of their schemas,
+the connectors and the processors which match the term, case-insensitive.
+The results are ranked by where the term matched, the exact names first.`,
+		Example: `search payments
+search amount --type field --output json
+search orders --type topic --type connector --limit 10`,
+		Args:             cobra.ExactArgs(1),
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, typ := range opts.Types {
+				if !isSearchType(typ) {
+					return fmt.Errorf("invalid type [%s], expected one of [%s]", typ, strings.Join(api.SearchTypes, ", "))
+				}
+			}
+
+			results, err := config.Client.Search(args[0], opts)
+			if err != nil {
+				return err
+			}
+
+			return bite.PrintObject(cmd, results)
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&opts.Types, "type", nil, fmt.Sprintf("Search only the resources of the type, can be repeated, one of [%s]", strings.Join(api.SearchTypes, ", ")))
+	cmd.Flags().IntVar(&opts.Limit, "limit", 0, "The maximum results, all of them if zero")
+
+	bite.CanPrintJSON(cmd)
+
+	return cmd
+}
+
+func isSearchType(typ string) bool {
+	for _, t := range api.SearchTypes {
+		if t == typ {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,113 @@
+package selfupdate
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/lensesio/bite"
+	"github.com/lensesio/lenses-go/pkg/api"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/exitcode"
+	"github.com/spf13/cobra"
+)
+
+//NewUpdateCommand creates the `update` command
+func NewUpdateCommand() *cobra.Command {
+	var (
+		channel                      string
+		check, notice, skipSignature bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update the CLI to the latest release of its channel",
+		Long: `Update the CLI in place to the latest GitHub release of its channel, stable or beta which includes the pre-releases.
+The checksum of the downloaded archive and the signature of the checksum, by the release public key of the build, are verified before the executable is replaced.
+A build without a release public key fails, unless --skip-signature installs it by its checksum only.
+--channel is saved for the next updates and the notice of a new version, which is printed once a day at most and is turned off by --notice=false.`,
+		Example: `update
+update --check
+update --channel beta
+update --notice=false`,
+		// it changes the CLI, not Lenses.
+		Annotations:      map[string]string{config.AnnotationMutating: "false"},
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			state, err := ReadState()
+			if err != nil {
+				return err
+			}
+
+			if cmd.Flags().Changed("channel") {
+				if channel != ChannelStable && channel != ChannelBeta {
+					return exitcode.WithCode(exitcode.Validation, fmt.Errorf("invalid channel [%s], expected [%s] or [%s]", channel, ChannelStable, ChannelBeta))
+				}
+				state.Channel = channel
+			}
+
+			if cmd.Flags().Changed("notice") {
+				state.DisableNotice = !notice
+			}
+
+			if cmd.Flags().Changed("channel") || cmd.Flags().Changed("notice") {
+				if err = SaveState(state); err != nil {
+					return err
+				}
+			}
+
+			if cmd.Flags().Changed("notice") && !cmd.Flags().Changed("channel") && !check {
+				if notice {
+					return bite.PrintInfo(cmd, "The new version notice is on")
+				}
+				return bite.PrintInfo(cmd, "The new version notice is off")
+			}
+
+			client := &http.Client{Timeout: 5 * time.Minute}
+			release, err := Latest(client, state.channel())
+			if err != nil {
+				return err
+			}
+
+			current := api.BuildVersion
+			if !Newer(release.Version, current) {
+				return bite.PrintInfo(cmd, "lenses-cli %s is the latest version of the %s channel", current, state.channel())
+			}
+
+			if check {
+				return bite.PrintInfo(cmd, "A new version of lenses-cli is available, %s (current %s)", release.Version, current)
+			}
+
+			exe, err := os.Executable()
+			if err != nil {
+				return err
+			}
+			if exe, err = filepath.EvalSymlinks(exe); err != nil {
+				return err
+			}
+
+			if err = Install(client, release, exe, runtime.GOOS, runtime.GOARCH, skipSignature); err != nil {
+				if err == ErrNoPublicKey {
+					return exitcode.WithCode(exitcode.Validation, err)
+				}
+				return err
+			}
+
+			state.LastCheck, state.Latest = time.Now(), release.Version
+			SaveState(state)
+
+			return bite.PrintInfo(cmd, "Updated lenses-cli to %s", release.Version)
+		},
+	}
+
+	cmd.Flags().StringVar(&channel, "channel", ChannelStable, "The channel of the releases, 'stable' or 'beta' which includes the pre-releases, it's saved for the next updates")
+	cmd.Flags().BoolVar(&check, "check", false, "Check for a new version without installing it")
+	cmd.Flags().BoolVar(&skipSignature, "skip-signature", false, "Install the release without verifying its signature, only its checksum, which is not verified against the publisher")
+	cmd.Flags().BoolVar(&notice, "notice", true, "Print a notice, once a day at most, when a new version is available, it's saved for the next runs")
+
+	return cmd
+}
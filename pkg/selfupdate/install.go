@@ -0,0 +1,218 @@
+package selfupdate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+)
+
+// PublicKey is the base64 encoded ed25519 public key of the signatures of the release checksums, the "<archive>.sha256.sig" assets.
+// It defaults to the `releasePublicKey` and can be overridden at build time,
+// i.e -ldflags "-X github.com/lensesio/lenses-go/pkg/selfupdate.PublicKey=...".
+// `Install` fails without it, unless the signature is skipped explicitly.
+var PublicKey = releasePublicKey
+
+// ErrNoPublicKey is returned by `Install` when the build has no `PublicKey` and the signature is not skipped.
+var ErrNoPublicKey = errors.New("the build has no release public key to verify the signature of the release, use --skip-signature to install it by its checksum only")
+
+func download(client *http.Client, url string) ([]byte, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to download [%s]: [%s]", url, resp.Status)
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+// verifyChecksum checks the SHA-256 of the "archive" of the "name" against its line in the "sums", of the sha256sum format.
+func verifyChecksum(archive, sums []byte, name string) error {
+	scanner := bufio.NewScanner(bytes.NewReader(sums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != name {
+			continue
+		}
+
+		sum := sha256.Sum256(archive)
+		if !strings.EqualFold(fields[0], hex.EncodeToString(sum[:])) {
+			return fmt.Errorf("checksum mismatch of [%s]: expected [%s] but got [%x]", name, fields[0], sum)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("no checksum of [%s]", name)
+}
+
+// verifySignature checks the base64 encoded ed25519 "signature" of the "sums" by the base64 encoded "publicKey".
+func verifySignature(sums, signature []byte, publicKey string) error {
+	key, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid public key of the releases")
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return fmt.Errorf("invalid signature of the checksums: [%v]", err)
+	}
+
+	if !ed25519.Verify(ed25519.PublicKey(key), sums, sig) {
+		return fmt.Errorf("the signature of the checksums is not valid")
+	}
+
+	return nil
+}
+
+// extractBinary returns the executable of the CLI, "lenses-cli" or "lenses-cli.exe" in any directory of the "archive",
+// a zip file if "isZip" or a gzipped tarball.
+func extractBinary(archive []byte, isZip bool) ([]byte, error) {
+	if isZip {
+		r, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, f := range r.File {
+			if path.Base(f.Name) != "lenses-cli.exe" {
+				continue
+			}
+
+			rc, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			defer rc.Close()
+			return ioutil.ReadAll(rc)
+		}
+
+		return nil, fmt.Errorf("no lenses-cli.exe in the archive")
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("no lenses-cli in the archive")
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if h.Typeflag == tar.TypeReg && path.Base(h.Name) == "lenses-cli" {
+			return ioutil.ReadAll(tr)
+		}
+	}
+}
+
+// replaceExecutable replaces the executable of the "path" by the "binary", with the same permissions.
+// The running one is renamed first, windows does not allow to overwrite it.
+func replaceExecutable(path string, binary []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	newPath, oldPath := path+".new", path+".old"
+	if err = ioutil.WriteFile(newPath, binary, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("unable to write the new executable [%s]: [%v]", newPath, err)
+	}
+
+	os.Remove(oldPath)
+	if err = os.Rename(path, oldPath); err != nil {
+		os.Remove(newPath)
+		return fmt.Errorf("unable to replace the executable [%s]: [%v]", path, err)
+	}
+
+	if err = os.Rename(newPath, path); err != nil {
+		// put the running one back.
+		os.Rename(oldPath, path)
+		os.Remove(newPath)
+		return fmt.Errorf("unable to replace the executable [%s]: [%v]", path, err)
+	}
+
+	// it fails on windows while the old one is running, the next update removes it.
+	os.Remove(oldPath)
+	return nil
+}
+
+// Install downloads the archive of the "release" for the "goos" and "goarch", verifies its checksum
+// and the signature of the checksums by the `PublicKey`, unless "skipSignature", and replaces the executable of the "path" by its one.
+// It returns the `ErrNoPublicKey` if the build has no `PublicKey` and the signature is not skipped.
+func Install(client *http.Client, release Release, path, goos, goarch string, skipSignature bool) error {
+	if PublicKey == "" && !skipSignature {
+		return ErrNoPublicKey
+	}
+
+	name := ArchiveName(release.Version, goos, goarch)
+	archiveAsset, ok := release.asset(name)
+	if !ok {
+		return fmt.Errorf("release [%s] has no archive for [%s/%s], expected [%s]", release.Version, goos, goarch, name)
+	}
+
+	sumsAsset, ok := release.asset(name + ".sha256")
+	if !ok {
+		return fmt.Errorf("release [%s] has no checksum of [%s]", release.Version, name)
+	}
+
+	sums, err := download(client, sumsAsset.URL)
+	if err != nil {
+		return err
+	}
+
+	if !skipSignature {
+		sigAsset, ok := release.asset(sumsAsset.Name + ".sig")
+		if !ok {
+			return fmt.Errorf("release [%s] has no signature of the checksum of [%s]", release.Version, name)
+		}
+
+		sig, err := download(client, sigAsset.URL)
+		if err != nil {
+			return err
+		}
+
+		if err = verifySignature(sums, sig, PublicKey); err != nil {
+			return err
+		}
+	}
+
+	archive, err := download(client, archiveAsset.URL)
+	if err != nil {
+		return err
+	}
+
+	if err = verifyChecksum(archive, sums, name); err != nil {
+		return err
+	}
+
+	binary, err := extractBinary(archive, goos == "windows")
+	if err != nil {
+		return fmt.Errorf("unable to extract the executable of [%s]: [%v]", name, err)
+	}
+
+	return replaceExecutable(path, binary)
+}
@@ -0,0 +1,107 @@
+package selfupdate
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultStateFilepath is the file of the settings of the updates and of the last check for a new version.
+var DefaultStateFilepath = filepath.Join(api.DefaultConfigurationHomeDir, "lenses-cli-update.yml")
+
+// CheckInterval is how often `Notify` checks for a new version.
+const CheckInterval = 24 * time.Hour
+
+// State are the settings of the updates, set by the `update` command, and the result of the last check for a new version.
+type State struct {
+	// Channel is the channel of the updates and of the notice, `ChannelStable` if empty.
+	Channel string `yaml:"channel,omitempty"`
+	// DisableNotice turns off the new version notice.
+	DisableNotice bool `yaml:"disableNotice,omitempty"`
+	// LastCheck is the time of the last check for a new version and Latest is the version it found.
+	LastCheck time.Time `yaml:"lastCheck,omitempty"`
+	Latest    string    `yaml:"latest,omitempty"`
+}
+
+// channel returns the channel of the state, `ChannelStable` by default.
+func (s State) channel() string {
+	if s.Channel == "" {
+		return ChannelStable
+	}
+
+	return s.Channel
+}
+
+// ReadState returns the settings of the updates, the zero one if they are not saved yet.
+func ReadState() (State, error) {
+	var state State
+
+	b, err := ioutil.ReadFile(DefaultStateFilepath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return state, err
+	}
+
+	if err = yaml.Unmarshal(b, &state); err != nil {
+		return state, fmt.Errorf("unable to read the update file [%s]: [%v]", DefaultStateFilepath, err)
+	}
+
+	return state, nil
+}
+
+// SaveState saves the settings of the updates.
+func SaveState(state State) error {
+	b, err := yaml.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	os.MkdirAll(filepath.Dir(DefaultStateFilepath), os.FileMode(0750))
+	return ioutil.WriteFile(DefaultStateFilepath, b, os.FileMode(0600))
+}
+
+// Notify writes a notice to "w" if a newer version than the "current" one is released on the channel of the state.
+// It checks the releases once per `CheckInterval`, with a short timeout, and it's a no-op if the notice is disabled
+// or the "current" is a development build.
+func Notify(w io.Writer, current string) error {
+	if current == "" {
+		return nil
+	}
+
+	state, err := ReadState()
+	if err != nil || state.DisableNotice {
+		return err
+	}
+
+	if time.Since(state.LastCheck) > CheckInterval {
+		// the next check is after the interval even if this one fails, i.e offline.
+		state.LastCheck = time.Now()
+		release, err := Latest(&http.Client{Timeout: 2 * time.Second}, state.channel())
+		if err == nil {
+			state.Latest = release.Version
+		}
+
+		if saveErr := SaveState(state); saveErr != nil {
+			return saveErr
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if Newer(state.Latest, current) {
+		fmt.Fprintf(w, "A new version of lenses-cli is available, %s (current %s), run 'lenses-cli update' to install it\n", state.Latest, current)
+	}
+
+	return nil
+}
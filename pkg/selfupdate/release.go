@@ -0,0 +1,127 @@
+// Package selfupdate updates the CLI in place from its GitHub releases, after it verifies the checksum of the release archive,
+// and the signature of the checksum by the release `PublicKey`, and it notifies of a new version, see `Notify`.
+package selfupdate
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+)
+
+// ReleasesURL is the GitHub API endpoint of the releases of the CLI.
+var ReleasesURL = "https://api.github.com/repos/lensesio/lenses-go/releases"
+
+// The channels of the releases.
+const (
+	// ChannelStable are the releases.
+	ChannelStable = "stable"
+	// ChannelBeta are the releases and the pre-releases.
+	ChannelBeta = "beta"
+)
+
+// Release is a GitHub release of the CLI.
+type Release struct {
+	Version    string  `json:"tag_name" header:"Version"`
+	Prerelease bool    `json:"prerelease" header:"Pre-release"`
+	Draft      bool    `json:"draft" header:"-"`
+	Assets     []Asset `json:"assets" header:"-"`
+}
+
+// Asset is a file of a release, i.e the archive of an OS.
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// asset returns the asset of the "name" of the release.
+func (r Release) asset(name string) (Asset, bool) {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a, true
+		}
+	}
+
+	return Asset{}, false
+}
+
+// ArchiveName returns the name of the release archive of the "version" of the CLI for the "goos" and "goarch",
+// a zip file for windows and a gzipped tarball for the rest.
+func ArchiveName(version, goos, goarch string) string {
+	ext := ".tar.gz"
+	if goos == "windows" {
+		ext = ".zip"
+	}
+
+	return fmt.Sprintf("lenses-cli-%s-%s-%s%s", goos, goarch, strings.TrimPrefix(version, "v"), ext)
+}
+
+// Latest returns the latest release of the "channel", `ChannelStable` or `ChannelBeta`.
+func Latest(client *http.Client, channel string) (Release, error) {
+	if channel != ChannelStable && channel != ChannelBeta {
+		return Release{}, fmt.Errorf("invalid channel [%s], expected [%s] or [%s]", channel, ChannelStable, ChannelBeta)
+	}
+
+	resp, err := client.Get(ReleasesURL)
+	if err != nil {
+		return Release{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Release{}, fmt.Errorf("unable to list the releases [%s]: [%s]", ReleasesURL, resp.Status)
+	}
+
+	var releases []Release
+	if err = json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return Release{}, fmt.Errorf("unable to read the releases [%s]: [%v]", ReleasesURL, err)
+	}
+
+	var latest Release
+	for _, r := range releases {
+		if r.Draft || (r.Prerelease && channel == ChannelStable) {
+			continue
+		}
+
+		if latest.Version == "" || Newer(r.Version, latest.Version) {
+			latest = r
+		}
+	}
+
+	if latest.Version == "" {
+		return Release{}, fmt.Errorf("no release found on the [%s] channel", channel)
+	}
+
+	return latest, nil
+}
+
+// Newer reports whether the "version" is newer than the "current" one, i.e "v4.1.0" than "4.0.2" or "4.1.0" than "4.1.0-rc1".
+// An empty "current" version, a development build, is older than any.
+func Newer(version, current string) bool {
+	if current == "" {
+		return version != ""
+	}
+
+	if c := api.CompareVersions(version, current); c != 0 {
+		return c > 0
+	}
+
+	// the same numbers, a version without a pre-release part is newer than a pre-release of it.
+	versionPre, currentPre := preRelease(version), preRelease(current)
+	if versionPre == "" || currentPre == "" {
+		return versionPre == "" && currentPre != ""
+	}
+
+	return versionPre > currentPre
+}
+
+// preRelease returns the pre-release part of the "version", i.e "rc1" of "4.1.0-rc1".
+func preRelease(version string) string {
+	if i := strings.IndexByte(version, '-'); i >= 0 {
+		return version[i+1:]
+	}
+
+	return ""
+}
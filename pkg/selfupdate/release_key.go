@@ -0,0 +1,7 @@
+package selfupdate
+
+// releasePublicKey is the base64 encoded ed25519 public key of the release signing key,
+// the "<archive>.sha256.sig" assets of the GitHub releases are signed by its private key.
+// It's kept in the source, so the builds by `go install` verify the releases too, see `PublicKey`.
+// A rotated key is replaced here and released before the releases are signed by the new one.
+const releasePublicKey = ""
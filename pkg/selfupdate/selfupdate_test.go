@@ -0,0 +1,213 @@
+package selfupdate
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewer(t *testing.T) {
+	assert.True(t, Newer("v4.1.0", "4.0.2"))
+	assert.True(t, Newer("4.1.0", "4.1.0-rc1"))
+	assert.True(t, Newer("4.1.0-rc2", "4.1.0-rc1"))
+	assert.True(t, Newer("4.10.0", "4.9.9"))
+	assert.True(t, Newer("4.1.0", ""))
+	assert.False(t, Newer("4.1.0", "4.1.0"))
+	assert.False(t, Newer("4.1.0-rc1", "4.1.0"))
+	assert.False(t, Newer("4.0.9", "v4.1"))
+	assert.False(t, Newer("", "4.1.0"))
+}
+
+func tarball(t *testing.T, name string, content []byte) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+	_, err := tw.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+// newReleasesServer serves the releases and the files of their assets, by name.
+func newReleasesServer(t *testing.T, releases []Release, files map[string][]byte) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/releases" {
+			json.NewEncoder(w).Encode(releases)
+			return
+		}
+
+		b, ok := files[filepath.Base(r.URL.Path)]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(b)
+	}))
+
+	ReleasesURL = srv.URL + "/releases"
+	for i := range releases {
+		for j := range releases[i].Assets {
+			releases[i].Assets[j].URL = srv.URL + "/download/" + releases[i].Assets[j].Name
+		}
+	}
+
+	return srv
+}
+
+func TestLatest(t *testing.T) {
+	defer func(url string) { ReleasesURL = url }(ReleasesURL)
+
+	srv := newReleasesServer(t, []Release{
+		{Version: "v4.2.0-rc1", Prerelease: true},
+		{Version: "v5.0.0", Draft: true},
+		{Version: "v4.1.0"},
+		{Version: "v4.0.3"},
+	}, nil)
+	defer srv.Close()
+
+	release, err := Latest(srv.Client(), ChannelStable)
+	require.NoError(t, err)
+	assert.Equal(t, "v4.1.0", release.Version)
+
+	release, err = Latest(srv.Client(), ChannelBeta)
+	require.NoError(t, err)
+	assert.Equal(t, "v4.2.0-rc1", release.Version)
+
+	_, err = Latest(srv.Client(), "nightly")
+	assert.EqualError(t, err, "invalid channel [nightly], expected [stable] or [beta]")
+}
+
+func TestInstall(t *testing.T) {
+	defer func(url, key string) { ReleasesURL, PublicKey = url, key }(ReleasesURL, PublicKey)
+
+	name := ArchiveName("v4.1.0", "linux", "amd64")
+	assert.Equal(t, "lenses-cli-linux-amd64-4.1.0.tar.gz", name)
+
+	archive := tarball(t, "lenses-cli-linux-amd64-4.1.0/lenses-cli", []byte("new binary"))
+	sum := sha256.Sum256(archive)
+	sums := []byte(fmt.Sprintf("%x  %s\n", sum, name))
+
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	sig := []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, sums)))
+
+	files := map[string][]byte{name: archive, name + ".sha256": sums, name + ".sha256.sig": sig}
+	release := Release{Version: "v4.1.0", Assets: []Asset{{Name: name}, {Name: name + ".sha256"}, {Name: name + ".sha256.sig"}}}
+	srv := newReleasesServer(t, []Release{release}, files)
+	defer srv.Close()
+	release, err = Latest(srv.Client(), ChannelStable)
+	require.NoError(t, err)
+
+	dir, err := ioutil.TempDir("", "lenses-cli-update")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	exe := filepath.Join(dir, "lenses-cli")
+	require.NoError(t, ioutil.WriteFile(exe, []byte("old binary"), 0755))
+
+	// a build without a public key.
+	PublicKey = ""
+	assert.Equal(t, ErrNoPublicKey, Install(srv.Client(), release, exe, "linux", "amd64", false))
+	b, err := ioutil.ReadFile(exe)
+	require.NoError(t, err)
+	assert.Equal(t, "old binary", string(b))
+
+	PublicKey = base64.StdEncoding.EncodeToString(publicKey)
+	require.NoError(t, Install(srv.Client(), release, exe, "linux", "amd64", false))
+
+	b, err = ioutil.ReadFile(exe)
+	require.NoError(t, err)
+	assert.Equal(t, "new binary", string(b))
+	info, err := os.Stat(exe)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+	_, err = os.Stat(exe + ".old")
+	assert.True(t, os.IsNotExist(err))
+
+	// a tampered archive.
+	files[name] = tarball(t, "lenses-cli-linux-amd64-4.1.0/lenses-cli", []byte("evil binary"))
+	err = Install(srv.Client(), release, exe, "linux", "amd64", false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "checksum mismatch")
+
+	// tampered checksums.
+	files[name+".sha256"] = []byte(fmt.Sprintf("%x  %s\n", sha256.Sum256(files[name]), name))
+	assert.EqualError(t, Install(srv.Client(), release, exe, "linux", "amd64", false), "the signature of the checksums is not valid")
+
+	b, err = ioutil.ReadFile(exe)
+	require.NoError(t, err)
+	assert.Equal(t, "new binary", string(b))
+
+	// skipped explicitly, by the checksum only.
+	require.NoError(t, Install(srv.Client(), release, exe, "linux", "amd64", true))
+	b, err = ioutil.ReadFile(exe)
+	require.NoError(t, err)
+	assert.Equal(t, "evil binary", string(b))
+
+	assert.EqualError(t, Install(srv.Client(), release, exe, "darwin", "arm64", false),
+		"release [v4.1.0] has no archive for [darwin/arm64], expected [lenses-cli-darwin-arm64-4.1.0.tar.gz]")
+}
+
+func TestNotify(t *testing.T) {
+	defer func(url, path string) { ReleasesURL, DefaultStateFilepath = url, path }(ReleasesURL, DefaultStateFilepath)
+
+	dir, err := ioutil.TempDir("", "lenses-cli-update")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	DefaultStateFilepath = filepath.Join(dir, "lenses-cli-update.yml")
+
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`[{"tag_name":"v4.2.0-rc1","prerelease":true},{"tag_name":"v4.1.0"}]`))
+	}))
+	defer srv.Close()
+	ReleasesURL = srv.URL
+
+	var out bytes.Buffer
+	require.NoError(t, Notify(&out, "4.0.0"))
+	assert.Equal(t, "A new version of lenses-cli is available, v4.1.0 (current 4.0.0), run 'lenses-cli update' to install it\n", out.String())
+
+	// checked once per interval.
+	out.Reset()
+	require.NoError(t, Notify(&out, "4.1.0"))
+	assert.Empty(t, out.String())
+	assert.Equal(t, 1, requests)
+
+	state, err := ReadState()
+	require.NoError(t, err)
+	assert.Equal(t, "v4.1.0", state.Latest)
+	assert.WithinDuration(t, time.Now(), state.LastCheck, time.Minute)
+
+	// the beta channel.
+	state.Channel, state.LastCheck = ChannelBeta, time.Time{}
+	require.NoError(t, SaveState(state))
+	require.NoError(t, Notify(&out, "4.1.0"))
+	assert.Contains(t, out.String(), "v4.2.0-rc1")
+
+	// turned off and the development builds.
+	out.Reset()
+	state.DisableNotice, state.LastCheck = true, time.Time{}
+	require.NoError(t, SaveState(state))
+	require.NoError(t, Notify(&out, "4.0.0"))
+	require.NoError(t, Notify(&out, ""))
+	assert.Empty(t, out.String())
+	assert.Equal(t, 2, requests)
+}
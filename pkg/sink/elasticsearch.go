@@ -0,0 +1,166 @@
+package sink
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultESIndex is the default index pattern of an `ESSink`.
+const DefaultESIndex = "lenses-{topic}-{date}"
+
+// ESSink bulk-indexes the records into Elasticsearch or OpenSearch.
+// A record is indexed with the id "<topic>-<partition>-<offset>", if it has metadata,
+// so a retried batch does not index it twice.
+type ESSink struct {
+	// URL is the base URL of the cluster, its user info, if any, is used for basic authentication.
+	URL string
+	// Index is the pattern of the records' index, `DefaultESIndex` by default.
+	// "{topic}" is replaced with the "Topic", "{date}" with the record's date as 2006.01.02
+	// and "{date:<layout>}" with the record's date in the Go time layout.
+	Index string
+	// Topic is the topic of the records, i.e the topic of the query's FROM.
+	Topic string
+	// Client defaults to a client with a 30 seconds timeout.
+	Client *http.Client
+}
+
+var esDatePattern = regexp.MustCompile(`\{date(?::([^}]+))?\}`)
+
+// index returns the index of a record of the "t" time.
+func (s *ESSink) index(t time.Time) string {
+	pattern := s.Index
+	if pattern == "" {
+		pattern = DefaultESIndex
+	}
+
+	topic := s.Topic
+	if topic == "" {
+		topic = "records"
+	}
+
+	index := strings.Replace(pattern, "{topic}", topic, -1)
+	index = esDatePattern.ReplaceAllStringFunc(index, func(m string) string {
+		layout := "2006.01.02"
+		if sub := esDatePattern.FindStringSubmatch(m); sub[1] != "" {
+			layout = sub[1]
+		}
+		return t.UTC().Format(layout)
+	})
+
+	// index names must be lowercase.
+	return strings.ToLower(index)
+}
+
+type esAction struct {
+	Index esIndex `json:"index"`
+}
+
+type esIndex struct {
+	Index string `json:"_index"`
+	ID    string `json:"_id,omitempty"`
+}
+
+// esBulkResponse is the part of the bulk API response needed to report the failed items.
+type esBulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int `json:"status"`
+		Error  struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+// Write indexes the "records" with a single bulk request, it fails if any of them was not indexed.
+func (s *ESSink) Write(records []Record) error {
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, r := range records {
+		action := esAction{Index: esIndex{Index: s.index(r.Time())}}
+		if r.Metadata.Timestamp != nil && s.Topic != "" {
+			action.Index.ID = fmt.Sprintf("%s-%d-%d", s.Topic, r.Metadata.Partition, r.Metadata.Offset)
+		}
+
+		if err := enc.Encode(action); err != nil {
+			return err
+		}
+
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		return fmt.Errorf("invalid elasticsearch url [%s]: [%v]", s.URL, err)
+	}
+
+	user := u.User
+	u.User = nil
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/_bulk"
+
+	req, err := http.NewRequest(http.MethodPost, u.String(), &body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	if user != nil {
+		password, _ := user.Password()
+		req.SetBasicAuth(user.Username(), password)
+	}
+
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("elasticsearch bulk request failed with status code [%d]: [%s]", resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+
+	var bulk esBulkResponse
+	if err = json.Unmarshal(b, &bulk); err != nil {
+		return fmt.Errorf("elasticsearch bulk response: [%v]", err)
+	}
+
+	if !bulk.Errors {
+		return nil
+	}
+
+	failed, reason := 0, ""
+	for _, item := range bulk.Items {
+		for _, result := range item {
+			if result.Status > 299 {
+				failed++
+				if reason == "" {
+					reason = result.Error.Type + ": " + result.Error.Reason
+				}
+			}
+		}
+	}
+
+	return fmt.Errorf("elasticsearch failed to index [%d] of [%d] records: [%s]", failed, len(records), reason)
+}
+
+// Close does nothing.
+func (s *ESSink) Close() error { return nil }
@@ -0,0 +1,69 @@
+package sink
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lensesio/lenses-go/pkg/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestESSinkIndex(t *testing.T) {
+	ts := time.Date(2020, 9, 13, 12, 26, 40, 0, time.UTC)
+
+	s := &ESSink{Topic: "Payments"}
+	assert.Equal(t, "lenses-payments-2020.09.13", s.index(ts))
+
+	s.Index = "{topic}-{date:2006-01}"
+	assert.Equal(t, "payments-2020-09", s.index(ts))
+
+	s.Topic = ""
+	s.Index = "events"
+	assert.Equal(t, "events", s.index(ts))
+}
+
+func TestESSinkWrite(t *testing.T) {
+	type bulk struct {
+		path, user, password string
+		lines                []string
+	}
+
+	got := make(chan bulk, 1)
+	response := `{"errors":false,"items":[]}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b := bulk{path: r.URL.Path}
+		b.user, b.password, _ = r.BasicAuth()
+		sc := bufio.NewScanner(r.Body)
+		for sc.Scan() {
+			b.lines = append(b.lines, sc.Text())
+		}
+		got <- b
+		w.Write([]byte(response))
+	}))
+	defer srv.Close()
+
+	s := &ESSink{URL: "http://elastic:s3cr3t@" + srv.Listener.Addr().String() + "/", Topic: "payments"}
+	records := []Record{{
+		Value:    json.RawMessage(`{"id":1}`),
+		Metadata: websocket.MetaData{Timestamp: float64(1600000000000), Partition: 1, Offset: 42},
+	}}
+	assert.NoError(t, s.Write(records))
+
+	b := <-got
+	assert.Equal(t, "/_bulk", b.path)
+	assert.Equal(t, "elastic", b.user)
+	assert.Equal(t, "s3cr3t", b.password)
+	if assert.Len(t, b.lines, 2) {
+		assert.Equal(t, `{"index":{"_index":"lenses-payments-2020.09.13","_id":"payments-1-42"}}`, b.lines[0])
+		assert.Contains(t, b.lines[1], `"value":{"id":1}`)
+	}
+
+	response = `{"errors":true,"items":[{"index":{"status":201}},{"index":{"status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse"}}}]}`
+	err := s.Write(append(records, records...))
+	<-got
+	assert.EqualError(t, err, "elasticsearch failed to index [1] of [2] records: [mapper_parsing_exception: failed to parse]")
+}
@@ -0,0 +1,45 @@
+package sink
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+)
+
+// FileSink appends the records, one JSON per line, to a file.
+type FileSink struct {
+	f *os.File
+	w *bufio.Writer
+}
+
+// NewFileSink opens, or creates, the file of the "path" to append the records to.
+func NewFileSink(path string) (*FileSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileSink{f: f, w: bufio.NewWriter(f)}, nil
+}
+
+// Write appends the "records" and flushes them to the file.
+func (s *FileSink) Write(records []Record) error {
+	enc := json.NewEncoder(s.w)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+
+	return s.w.Flush()
+}
+
+// Close closes the file.
+func (s *FileSink) Close() error {
+	if err := s.w.Flush(); err != nil {
+		s.f.Close()
+		return err
+	}
+
+	return s.f.Close()
+}
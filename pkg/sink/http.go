@@ -0,0 +1,63 @@
+package sink
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// HTTPSink posts each batch as a JSON array of records to the "URL".
+type HTTPSink struct {
+	URL    string
+	Header http.Header
+	// Client defaults to a client with a 30 seconds timeout.
+	Client *http.Client
+}
+
+// Write posts the "records", a response status code other than 2xx is an error.
+func (s *HTTPSink) Write(records []Record) error {
+	return PostJSON(s.Client, "sink", s.URL, s.Header, records)
+}
+
+// PostJSON posts the JSON of "v" to the "url" with the "header", a response status code other than 2xx is an error.
+// The "name" prefixes the errors, i.e sink or webhook, and the "client" defaults to a client with a 30 seconds timeout.
+func PostJSON(client *http.Client, name, url string, header http.Header, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s [%s]: [%v]", name, url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("%s [%s] failed with status code [%d]: [%s]", name, url, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
+
+// Close does nothing.
+func (s *HTTPSink) Close() error { return nil }
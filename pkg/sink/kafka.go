@@ -0,0 +1,136 @@
+package sink
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lensesio/lenses-go/pkg/websocket"
+)
+
+// KafkaSink inserts the records into a topic through a Lenses SQL "INSERT" statement,
+// the topic's key and value formats should be STRING or JSON.
+type KafkaSink struct {
+	Topic string
+	// Config is the live connection configuration of the statements, its "SQL" is set by the sink.
+	Config websocket.LiveConfiguration
+	// Timeout is the maximum time to wait for a statement, defaults to 30 seconds.
+	Timeout time.Duration
+}
+
+// Write inserts the "records" with a single statement.
+func (s *KafkaSink) Write(records []Record) error {
+	config := s.Config
+	config.Message.SQL = insertStatement(s.Topic, records)
+	config.Message.Live = false
+	// the options of the query do not apply to the statements.
+	config.Limit, config.SampleRate, config.AckWindow = 0, 0, 0
+
+	conn, err := websocket.OpenLiveConnection(config)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	done := make(chan error, 1)
+	reporter := func(resp websocket.LiveResponse) error {
+		var errStr string
+		json.Unmarshal(resp.Data.Value, &errStr)
+		select {
+		case done <- fmt.Errorf("[%s]: [%s]", resp.Type, errStr):
+		default:
+		}
+		return nil
+	}
+
+	conn.OnError(reporter)
+	conn.OnInvalidRequest(reporter)
+	conn.OnEnd(func(websocket.LiveResponse) error {
+		select {
+		case done <- nil:
+		default:
+		}
+		return nil
+	})
+
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+
+	select {
+	case err = <-done:
+		return err
+	case err = <-conn.Err():
+		return err
+	case <-time.After(timeout):
+		return fmt.Errorf("insert into [%s] did not complete in [%s]", s.Topic, timeout)
+	}
+}
+
+// Close does nothing.
+func (s *KafkaSink) Close() error { return nil }
+
+// insertStatement returns the statement that inserts the "records" into the "topic",
+// their key and value are inserted as their JSON text and their headers as text,
+// the records without one of the headers of the others insert it as null.
+func insertStatement(topic string, records []Record) string {
+	var headers []string
+	seen := make(map[string]bool)
+	for _, r := range records {
+		for name := range r.Headers {
+			if !seen[name] {
+				seen[name] = true
+				headers = append(headers, name)
+			}
+		}
+	}
+	sort.Strings(headers)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "INSERT INTO `%s`(_key, _value", topic)
+	for _, name := range headers {
+		fmt.Fprintf(&b, ", _header.`%s`", name)
+	}
+	b.WriteString(") VALUES")
+
+	for i, r := range records {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, " (%s, %s", sqlString(r.Key), sqlString(r.Value))
+		for _, name := range headers {
+			b.WriteString(", ")
+			b.WriteString(sqlText(r.Headers[name]))
+		}
+		b.WriteByte(')')
+	}
+
+	return b.String()
+}
+
+// sqlText returns the "v" as a Lenses SQL string literal, null if it's nil.
+func sqlText(v []byte) string {
+	if v == nil {
+		return "null"
+	}
+
+	return "'" + strings.Replace(string(v), "'", "''", -1) + "'"
+}
+
+// sqlString returns the JSON "v" as a Lenses SQL string literal, a JSON string is unquoted first.
+func sqlString(v json.RawMessage) string {
+	if len(v) == 0 || string(v) == "null" {
+		return "null"
+	}
+
+	s := string(v)
+	var str string
+	if json.Unmarshal(v, &str) == nil {
+		s = str
+	}
+
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
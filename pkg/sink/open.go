@@ -0,0 +1,40 @@
+package sink
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/lensesio/lenses-go/pkg/websocket"
+)
+
+// Open returns the sink of the "rawURL":
+// http:// or https:// for an `HTTPSink`, file:// for a `FileSink` and kafka://topic for a `KafkaSink`,
+// which runs its statements with the "config" of the live query.
+func Open(rawURL string, config websocket.LiveConfiguration) (Sink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sink url [%s]: [%v]", rawURL, err)
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		return &HTTPSink{URL: rawURL}, nil
+	case "file":
+		path := u.Path
+		if u.Host != "" { // file://relative/path.
+			path = u.Host + path
+		}
+		if path == "" {
+			return nil, fmt.Errorf("invalid sink url [%s]: the file path is missing", rawURL)
+		}
+		return NewFileSink(path)
+	case "kafka":
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid sink url [%s]: the topic is missing", rawURL)
+		}
+		return &KafkaSink{Topic: u.Host, Config: config}, nil
+	default:
+		return nil, fmt.Errorf("unknown sink url scheme [%s], expected http, https, file or kafka", u.Scheme)
+	}
+}
@@ -0,0 +1,284 @@
+// Package sink forwards the records of a live query to a downstream system, an HTTP endpoint, a file or a Kafka topic,
+// in batches and with retries, so the CLI can be used as a simple bridge.
+package sink
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kataras/golog"
+	"github.com/lensesio/lenses-go/pkg/throttle"
+	"github.com/lensesio/lenses-go/pkg/transform"
+	"github.com/lensesio/lenses-go/pkg/websocket"
+)
+
+// Record is a record of a live query as it's written to a `Sink`.
+type Record struct {
+	Key      json.RawMessage    `json:"key,omitempty"`
+	Value    json.RawMessage    `json:"value"`
+	Metadata websocket.MetaData `json:"metadata"`
+	Headers  websocket.Headers  `json:"headers,omitempty"`
+}
+
+// NewRecord returns the record of the "data", it copies its key, value and headers
+// because the connection may reuse their buffers, see `LiveConfiguration#ReuseBuffers`.
+func NewRecord(data websocket.Data) Record {
+	return Record{
+		Key:      append(json.RawMessage(nil), data.Key...),
+		Value:    append(json.RawMessage(nil), data.Value...),
+		Metadata: data.Metadata,
+		Headers:  data.Headers.Clone(),
+	}
+}
+
+// Time returns the time of the record's timestamp, in milliseconds, or the current time if it has none.
+func (r Record) Time() time.Time {
+	var ms int64
+	switch ts := r.Metadata.Timestamp.(type) {
+	case float64:
+		ms = int64(ts)
+	case json.Number:
+		ms, _ = ts.Int64()
+	case string:
+		ms, _ = strconv.ParseInt(ts, 10, 64)
+	}
+
+	if ms <= 0 {
+		return time.Now()
+	}
+
+	return time.Unix(0, ms*int64(time.Millisecond))
+}
+
+// Sink writes batches of records to a downstream system.
+type Sink interface {
+	// Write writes the "records", it may be called again with the same records if it fails.
+	Write(records []Record) error
+	Close() error
+}
+
+// Options are the batching and retry options of a `Batcher`.
+type Options struct {
+	// BatchSize is the maximum records of a batch, defaults to `DefaultBatchSize`.
+	BatchSize int
+	// FlushInterval is the maximum time a record waits for its batch to be full, defaults to `DefaultFlushInterval`.
+	FlushInterval time.Duration
+	// MaxRetries is the number of retries of a failed batch, zero does not retry.
+	MaxRetries int
+	// RetryBackoff is the wait before the first retry, it doubles on every retry, defaults to `DefaultRetryBackoff`.
+	RetryBackoff time.Duration
+	// Skip, if not nil, drops the records of `Attach` it returns true for, i.e the duplicates, as they are received.
+	Skip func(Record) bool
+	// Enrich, if not nil, returns the value of the records of `Attach` with the fields it adds, before they are transformed,
+	// so the added fields can be anonymized too.
+	Enrich func(key, value json.RawMessage) (json.RawMessage, error)
+	// Transform, if not nil, anonymizes the records of `Attach` before they are added.
+	Transform *transform.Transformer
+	// Limiter, if not nil, throttles the records of `Add`, by their number and the bytes of their keys and values.
+	// It can be shared by many batchers.
+	Limiter *throttle.Limiter
+}
+
+// The defaults of the `Options`.
+const (
+	DefaultBatchSize     = 100
+	DefaultFlushInterval = time.Second
+	DefaultRetryBackoff  = 500 * time.Millisecond
+)
+
+// ErrClosed is returned by the `Batcher#Add` after the batcher is closed.
+var ErrClosed = errors.New("sink: closed")
+
+// Batcher buffers the records and writes them to its `Sink` when the batch is full or every flush interval.
+type Batcher struct {
+	sink Sink
+	opts Options
+
+	mu      sync.Mutex
+	batch   []Record
+	err     error // the error of the last background flush, returned by the next `Add` or `Close`.
+	written int64
+	dropped int64
+	closed  bool
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// NewBatcher returns a `Batcher` of the "sink" and starts its flush interval,
+// it must be closed to write the last batch.
+func NewBatcher(sink Sink, opts Options) *Batcher {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = DefaultFlushInterval
+	}
+
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = DefaultRetryBackoff
+	}
+
+	b := &Batcher{
+		sink:    sink,
+		opts:    opts,
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+
+	go b.flushEvery(opts.FlushInterval)
+	return b
+}
+
+// Attach returns a `Batcher` of the "sink" which receives the records of the "stream".
+// The caller should close it once the stream ends.
+func Attach(stream websocket.LiveStream, sink Sink, opts Options) *Batcher {
+	b := NewBatcher(sink, opts)
+	stream.OnRecordMessage(func(resp websocket.LiveResponse) error {
+		record := NewRecord(resp.Data)
+		if opts.Skip != nil && opts.Skip(record) {
+			return nil
+		}
+
+		if opts.Enrich != nil {
+			value, err := opts.Enrich(record.Key, record.Value)
+			if err != nil {
+				return err
+			}
+			record.Value = value
+		}
+
+		if opts.Transform != nil {
+			key, value, err := opts.Transform.Apply(record.Key, record.Value)
+			if err != nil {
+				return err
+			}
+			record.Key, record.Value = key, value
+		}
+
+		return b.Add(record)
+	})
+
+	return b
+}
+
+func (b *Batcher) flushEvery(interval time.Duration) {
+	defer close(b.stopped)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.stop:
+			return
+		case <-ticker.C:
+			b.mu.Lock()
+			if err := b.flush(); err != nil {
+				golog.Errorf("sink: %v", err)
+				b.err = err
+			}
+			b.mu.Unlock()
+		}
+	}
+}
+
+// Add adds the "record" to the batch and writes the batch if it's full.
+// It waits for the `Options#Limiter`, if any.
+func (b *Batcher) Add(record Record) error {
+	b.opts.Limiter.Wait(1, len(record.Key)+len(record.Value), nil)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return ErrClosed
+	}
+
+	if err := b.err; err != nil {
+		b.err = nil
+		return err
+	}
+
+	b.batch = append(b.batch, record)
+	if len(b.batch) < b.opts.BatchSize {
+		return nil
+	}
+
+	return b.flush()
+}
+
+// Flush writes the buffered records.
+func (b *Batcher) Flush() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.flush()
+}
+
+// flush writes the batch, with retries, the batch is dropped if it still fails.
+func (b *Batcher) flush() error {
+	if len(b.batch) == 0 {
+		return nil
+	}
+
+	batch := b.batch
+	b.batch = nil
+
+	backoff := b.opts.RetryBackoff
+	for retry := 0; ; retry++ {
+		err := b.sink.Write(batch)
+		if err == nil {
+			b.written += int64(len(batch))
+			return nil
+		}
+
+		if retry >= b.opts.MaxRetries {
+			b.dropped += int64(len(batch))
+			return fmt.Errorf("[%d] records were not written after [%d] retries: [%v]", len(batch), retry, err)
+		}
+
+		golog.Debugf("sink: write of [%d] records failed, retrying in [%s]: [%v]", len(batch), backoff, err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// Stats returns the records written to the sink and the ones dropped because their batch failed.
+func (b *Batcher) Stats() (written, dropped int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.written, b.dropped
+}
+
+// Close writes the last batch and closes the sink.
+func (b *Batcher) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	b.mu.Unlock()
+
+	close(b.stop)
+	<-b.stopped
+
+	b.mu.Lock()
+	err := b.flush()
+	if err == nil {
+		err = b.err
+	}
+	b.mu.Unlock()
+
+	if closeErr := b.sink.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
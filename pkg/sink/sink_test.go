@@ -0,0 +1,246 @@
+package sink
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lensesio/lenses-go/pkg/websocket"
+	"github.com/lensesio/lenses-go/pkg/websocket/wstest"
+	"github.com/stretchr/testify/assert"
+)
+
+type memorySink struct {
+	mu      sync.Mutex
+	batches [][]Record
+	fail    int
+	closed  bool
+}
+
+func (s *memorySink) Write(records []Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.fail > 0 {
+		s.fail--
+		return errors.New("unavailable")
+	}
+
+	s.batches = append(s.batches, records)
+	return nil
+}
+
+func (s *memorySink) Close() error {
+	s.closed = true
+	return nil
+}
+
+func record(value string) Record {
+	return Record{Value: json.RawMessage(value)}
+}
+
+func TestBatcher(t *testing.T) {
+	s := new(memorySink)
+	b := NewBatcher(s, Options{BatchSize: 2, FlushInterval: time.Hour})
+
+	assert.NoError(t, b.Add(record(`1`)))
+	assert.Empty(t, s.batches)
+	assert.NoError(t, b.Add(record(`2`)))
+	assert.Equal(t, [][]Record{{record(`1`), record(`2`)}}, s.batches)
+
+	assert.NoError(t, b.Add(record(`3`)))
+	assert.NoError(t, b.Close())
+	assert.Equal(t, []Record{record(`3`)}, s.batches[1])
+	assert.True(t, s.closed)
+	assert.Equal(t, ErrClosed, b.Add(record(`4`)))
+}
+
+func TestBatcherFlushInterval(t *testing.T) {
+	s := new(memorySink)
+	b := NewBatcher(s, Options{BatchSize: 100, FlushInterval: 10 * time.Millisecond})
+	defer b.Close()
+
+	assert.NoError(t, b.Add(record(`1`)))
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		s.mu.Lock()
+		n := len(s.batches)
+		s.mu.Unlock()
+		if n == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the batch was not flushed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestBatcherRetry(t *testing.T) {
+	s := &memorySink{fail: 2}
+	b := NewBatcher(s, Options{BatchSize: 1, FlushInterval: time.Hour, MaxRetries: 2, RetryBackoff: time.Millisecond})
+	assert.NoError(t, b.Add(record(`1`)))
+	assert.Len(t, s.batches, 1)
+
+	s.fail = 3
+	assert.EqualError(t, b.Add(record(`2`)), "[1] records were not written after [2] retries: [unavailable]")
+	written, dropped := b.Stats()
+	assert.Equal(t, int64(1), written)
+	assert.Equal(t, int64(1), dropped)
+	assert.NoError(t, b.Close())
+}
+
+// attach returns the records that `Attach` added from the "messages" of a test server with the "opts".
+func attach(t *testing.T, opts Options, messages ...string) []Record {
+	srv := wstest.NewServer(append(messages, wstest.End())...)
+	defer srv.Close()
+
+	conn, err := websocket.OpenLiveConnection(websocket.LiveConfiguration{Host: srv.URL, Message: websocket.Message{SQL: "SELECT * FROM payments"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	s := new(memorySink)
+	opts.BatchSize, opts.FlushInterval = 10, time.Hour
+	b := Attach(conn, s, opts)
+
+	end := make(chan struct{})
+	conn.OnEnd(func(resp websocket.LiveResponse) error {
+		close(end)
+		return nil
+	})
+
+	select {
+	case <-end:
+	case err := <-conn.Err():
+		t.Fatal(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+
+	assert.NoError(t, b.Close())
+	var records []Record
+	for _, batch := range s.batches {
+		records = append(records, batch...)
+	}
+
+	return records
+}
+
+func TestAttachSkip(t *testing.T) {
+	seen := make(map[string]bool)
+	skip := func(r Record) bool {
+		duplicate := seen[string(r.Value)]
+		seen[string(r.Value)] = true
+		return duplicate
+	}
+
+	records := attach(t, Options{Skip: skip}, wstest.Record(0, 1, "", "1"), wstest.Record(0, 2, "", "1"), wstest.Record(0, 3, "", "2"))
+	if assert.Len(t, records, 2) {
+		assert.Equal(t, []int{1, 3}, []int{records[0].Metadata.Offset, records[1].Metadata.Offset})
+	}
+}
+
+func TestAttachEnrich(t *testing.T) {
+	enrich := func(key, value json.RawMessage) (json.RawMessage, error) {
+		return json.RawMessage(`{"id":` + string(value) + `,"country":"GR"}`), nil
+	}
+
+	records := attach(t, Options{Enrich: enrich}, wstest.Record(0, 1, "", "1"))
+	if assert.Len(t, records, 1) {
+		assert.JSONEq(t, `{"id":1,"country":"GR"}`, string(records[0].Value))
+	}
+}
+
+func TestHTTPSink(t *testing.T) {
+	got := make(chan []Record, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var records []Record
+		json.NewDecoder(r.Body).Decode(&records)
+		got <- records
+		if r.Header.Get("X-Fail") != "" {
+			http.Error(w, "bad gateway", http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	s := &HTTPSink{URL: srv.URL}
+	assert.NoError(t, s.Write([]Record{{Key: json.RawMessage(`"k"`), Value: json.RawMessage(`{"id":1}`)}}))
+	records := <-got
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, `"k"`, string(records[0].Key))
+		assert.Equal(t, `{"id":1}`, string(records[0].Value))
+	}
+
+	s.Header = http.Header{"X-Fail": []string{"1"}}
+	assert.EqualError(t, s.Write([]Record{record(`1`)}), "sink ["+srv.URL+"] failed with status code [502]: [bad gateway]")
+}
+
+func TestFileSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lenses-sink")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "records.jsonl")
+	s, err := Open("file://"+path, websocket.LiveConfiguration{})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NoError(t, s.Write([]Record{record(`1`), record(`{"id":2}`)}))
+	assert.NoError(t, s.Close())
+
+	b, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	if assert.Len(t, lines, 2) {
+		var r Record
+		assert.NoError(t, json.Unmarshal([]byte(lines[1]), &r))
+		assert.Equal(t, `{"id":2}`, string(r.Value))
+	}
+}
+
+func TestOpen(t *testing.T) {
+	s, err := Open("https://hooks.example.com/lenses", websocket.LiveConfiguration{})
+	assert.NoError(t, err)
+	assert.Equal(t, &HTTPSink{URL: "https://hooks.example.com/lenses"}, s)
+
+	s, err = Open("kafka://payments-copy", websocket.LiveConfiguration{Host: "lenses"})
+	assert.NoError(t, err)
+	assert.Equal(t, "payments-copy", s.(*KafkaSink).Topic)
+
+	_, err = Open("kafka://", websocket.LiveConfiguration{})
+	assert.EqualError(t, err, "invalid sink url [kafka://]: the topic is missing")
+
+	_, err = Open("ftp://host/file", websocket.LiveConfiguration{})
+	assert.EqualError(t, err, "unknown sink url scheme [ftp], expected http, https, file or kafka")
+}
+
+func TestInsertStatement(t *testing.T) {
+	sql := insertStatement("payments", []Record{
+		{Key: json.RawMessage(`"k1"`), Value: json.RawMessage(`{"name":"o'neil"}`)},
+		{Value: json.RawMessage(`2`)},
+	})
+	assert.Equal(t, "INSERT INTO `payments`(_key, _value) VALUES ('k1', '{\"name\":\"o''neil\"}'), (null, '2')", sql)
+}
+
+func TestInsertStatementHeaders(t *testing.T) {
+	sql := insertStatement("payments", []Record{
+		{Value: json.RawMessage(`1`), Headers: websocket.Headers{"source": []byte("web"), "trace": []byte("a'b")}},
+		{Value: json.RawMessage(`2`), Headers: websocket.Headers{"source": []byte("app")}},
+		{Value: json.RawMessage(`3`)},
+	})
+	assert.Equal(t, "INSERT INTO `payments`(_key, _value, _header.`source`, _header.`trace`) VALUES (null, '1', 'web', 'a''b'), (null, '2', 'app', null), (null, '3', null, null)", sql)
+}
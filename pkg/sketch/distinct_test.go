@@ -0,0 +1,39 @@
+package sketch
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistinct(t *testing.T) {
+	d := NewDistinct()
+	assert.Zero(t, d.Count())
+
+	for i := 0; i < 3; i++ {
+		d.AddString("a")
+		d.AddString("b")
+	}
+	assert.Equal(t, int64(2), d.Count())
+
+	for _, n := range []int{1000, 100000} {
+		d = NewDistinct()
+		for i := 0; i < n; i++ {
+			d.AddString(strconv.Itoa(i))
+			d.AddString(strconv.Itoa(i))
+		}
+		assert.InEpsilon(t, n, d.Count(), 0.05, strconv.Itoa(n))
+	}
+}
+
+func TestDistinctMerge(t *testing.T) {
+	a, b := NewDistinct(), NewDistinct()
+	for i := 0; i < 500; i++ {
+		a.AddString(strconv.Itoa(i))
+		b.AddString(strconv.Itoa(i + 250))
+	}
+
+	a.Merge(b)
+	assert.InEpsilon(t, 750, a.Count(), 0.05)
+}
@@ -0,0 +1,354 @@
+package sql
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/kataras/golog"
+	"github.com/lensesio/bite"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/websocket"
+	"github.com/spf13/cobra"
+)
+
+// benchRecord is the value of the synthetic records produced by the `bench` command.
+type benchRecord struct {
+	Run  string `json:"run"`
+	Seq  int    `json:"seq"`
+	Sent int64  `json:"sent"` // unix milliseconds.
+}
+
+// benchReport is the result of a `bench` run.
+type benchReport struct {
+	Topic      string  `json:"topic" header:"Topic"`
+	Run        string  `json:"run" header:"Run"`
+	Produced   int     `json:"produced" header:"Produced"`
+	Received   int     `json:"received" header:"Received"`
+	Lost       int     `json:"lost" header:"Lost"`
+	Throughput float64 `json:"throughput" header:"Records/sec"`
+	P50        int64   `json:"p50" header:"p50 (ms)"`
+	P95        int64   `json:"p95" header:"p95 (ms)"`
+	P99        int64   `json:"p99" header:"p99 (ms)"`
+	Max        int64   `json:"max" header:"Max (ms)"`
+}
+
+// latencyPercentile returns the "p" (0-100) percentile, by the nearest rank, of the "sorted" latencies.
+func latencyPercentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+
+	return sorted[rank-1]
+}
+
+// benchDue returns how many records should be produced now, "elapsed" after the start,
+// to keep up with the target "rate" per second, at most "maxBatch".
+func benchDue(elapsed time.Duration, rate int, produced int, maxBatch int) int {
+	n := int(elapsed.Seconds()*float64(rate)) - produced
+	if n > maxBatch {
+		n = maxBatch
+	}
+	if n < 0 {
+		n = 0
+	}
+
+	return n
+}
+
+// benchInsertStatement returns the statement that inserts the "records" into the "topic".
+func benchInsertStatement(topic string, records []benchRecord) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "INSERT INTO `%s`(_key, run, seq, sent) VALUES", topic)
+	for i, rec := range records {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, " ('%s-%d', '%s', %d, %d)", rec.Run, rec.Seq, rec.Run, rec.Seq, rec.Sent)
+	}
+
+	return b.String()
+}
+
+// benchCreateStatement returns the statement that creates the "topic" with the synthetic records' schema.
+func benchCreateStatement(topic string) string {
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS `%s`(_key string, run string, seq int, sent long) FORMAT(string, json)", topic)
+}
+
+func benchLiveConfiguration(sql string, live bool) websocket.LiveConfiguration {
+	currentConfig := config.Manager.Config.GetCurrent()
+
+	return websocket.LiveConfiguration{
+		Host:  currentConfig.Host,
+		Debug: currentConfig.Debug,
+		Message: websocket.Message{
+			Token: config.Client.Config.Token,
+			SQL:   sql,
+			Live:  live,
+			Stats: 0,
+		},
+		ReuseBuffers: true,
+	}
+}
+
+// execStatement runs a statement which does not return records, i.e an "INSERT", and waits for its end.
+func execStatement(sql string, timeout time.Duration) error {
+	conn, err := websocket.OpenLiveConnection(benchLiveConfiguration(sql, false))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	done := make(chan error, 1)
+	reporter := func(resp websocket.LiveResponse) error {
+		var errStr string
+		json.Unmarshal(resp.Data.Value, &errStr)
+		select {
+		case done <- fmt.Errorf("[%s]: [%s]", resp.Type, errStr):
+		default:
+		}
+		return nil
+	}
+
+	conn.OnError(reporter)
+	conn.OnInvalidRequest(reporter)
+	conn.OnEnd(func(websocket.LiveResponse) error {
+		select {
+		case done <- nil:
+		default:
+		}
+		return nil
+	})
+
+	select {
+	case err = <-done:
+		return err
+	case err = <-conn.Err():
+		return err
+	case <-time.After(timeout):
+		return fmt.Errorf("statement did not complete in [%s]", timeout)
+	}
+}
+
+// benchOptions are the `bench` command's flags.
+type benchOptions struct {
+	Topic    string
+	Rate     int
+	Duration time.Duration
+	Warmup   time.Duration
+	Drain    time.Duration
+	Batch    int
+	Create   bool
+}
+
+func runBench(cmd *cobra.Command, opts benchOptions) (benchReport, error) {
+	run := strconv.FormatInt(time.Now().UnixNano(), 36)
+	report := benchReport{Topic: opts.Topic, Run: run}
+
+	if opts.Create {
+		if err := execStatement(benchCreateStatement(opts.Topic), 30*time.Second); err != nil {
+			return report, fmt.Errorf("unable to create topic [%s]: %w", opts.Topic, err)
+		}
+	}
+
+	sql := fmt.Sprintf("SELECT run, seq, sent FROM `%s` WHERE run = '%s'", opts.Topic, run)
+	conn, err := websocket.OpenLiveConnection(benchLiveConfiguration(sql, true))
+	if err != nil {
+		return report, fmt.Errorf("unable to subscribe to topic [%s]: %w", opts.Topic, err)
+	}
+	defer conn.Close()
+
+	var (
+		mu        sync.Mutex
+		latencies []time.Duration
+		seen      = make(map[int]struct{})
+		errs      = make(chan error, 1)
+	)
+
+	go func() {
+		for err := range conn.Err() {
+			golog.Debugf("bench: [%v]", err)
+		}
+	}()
+
+	reporter := func(resp websocket.LiveResponse) error {
+		var errStr string
+		json.Unmarshal(resp.Data.Value, &errStr)
+		select {
+		case errs <- fmt.Errorf("[%s]: [%s]", resp.Type, errStr):
+		default:
+		}
+		return nil
+	}
+	conn.OnError(reporter)
+	conn.OnInvalidRequest(reporter)
+
+	conn.OnRecordMessage(func(resp websocket.LiveResponse) error {
+		received := time.Now()
+
+		var rec benchRecord
+		if err := json.Unmarshal(resp.Data.Value, &rec); err != nil || rec.Run != run {
+			return nil
+		}
+
+		mu.Lock()
+		if _, ok := seen[rec.Seq]; !ok {
+			seen[rec.Seq] = struct{}{}
+			latencies = append(latencies, received.Sub(time.Unix(0, rec.Sent*int64(time.Millisecond))))
+		}
+		mu.Unlock()
+		return nil
+	})
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+
+	// give the live query the time to start, records produced before it are not received.
+	select {
+	case <-time.After(opts.Warmup):
+	case err = <-errs:
+		return report, err
+	case <-interrupt:
+		return report, nil
+	}
+
+	fmt.Fprintf(cmd.OutOrStderr(), "producing [%d] records/sec to [%s] for [%s]\n", opts.Rate, opts.Topic, opts.Duration)
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	start := time.Now()
+	deadline := start.Add(opts.Duration)
+
+produce:
+	for {
+		select {
+		case now := <-ticker.C:
+			if !now.Before(deadline) {
+				break produce
+			}
+
+			n := benchDue(now.Sub(start), opts.Rate, report.Produced, opts.Batch)
+			if n == 0 {
+				continue
+			}
+
+			sent := time.Now().UnixNano() / int64(time.Millisecond)
+			records := make([]benchRecord, n)
+			for i := range records {
+				records[i] = benchRecord{Run: run, Seq: report.Produced + i, Sent: sent}
+			}
+
+			if err = execStatement(benchInsertStatement(opts.Topic, records), 30*time.Second); err != nil {
+				return report, fmt.Errorf("unable to produce to topic [%s]: %w", opts.Topic, err)
+			}
+			report.Produced += n
+		case err = <-errs:
+			return report, err
+		case <-interrupt:
+			break produce
+		}
+	}
+
+	elapsed := time.Since(start)
+	if elapsed > 0 {
+		report.Throughput = math.Round(float64(report.Produced)/elapsed.Seconds()*10) / 10
+	}
+
+	// wait for the in-flight records.
+	drain := time.After(opts.Drain)
+wait:
+	for {
+		mu.Lock()
+		received := len(latencies)
+		mu.Unlock()
+		if received >= report.Produced {
+			break
+		}
+
+		select {
+		case <-ticker.C:
+		case <-drain:
+			break wait
+		case <-interrupt:
+			break wait
+		}
+	}
+
+	mu.Lock()
+	sorted := append([]time.Duration(nil), latencies...)
+	mu.Unlock()
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	report.Received = len(sorted)
+	report.Lost = report.Produced - report.Received
+	report.P50 = latencyPercentile(sorted, 50).Milliseconds()
+	report.P95 = latencyPercentile(sorted, 95).Milliseconds()
+	report.P99 = latencyPercentile(sorted, 99).Milliseconds()
+	report.Max = latencyPercentile(sorted, 100).Milliseconds()
+
+	return report, nil
+}
+
+//NewBenchCommand creates `bench` command
+func NewBenchCommand() *cobra.Command {
+	var opts benchOptions
+
+	cmd := &cobra.Command{
+		Use:   "bench",
+		Short: "Produce synthetic records to a topic at a target rate and measure their end-to-end latency through a live query",
+		Long: `Produce synthetic records to a topic at a target rate and measure their end-to-end latency through a live query.
+The records are JSON values with a string key, the topic should exist with these formats, or use --create.
+The latency is the time between producing a record and receiving it from the live query, measured by the client's clock.`,
+		Example: `bench --topic=bench --rate=1000 --duration=1m
+bench --topic=bench --create --rate=100 --output=json`,
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := bite.CheckRequiredFlags(cmd, bite.FlagPair{"topic": opts.Topic}); err != nil {
+				return err
+			}
+
+			if opts.Rate <= 0 || opts.Batch <= 0 {
+				return errors.New("rate and batch must be positive")
+			}
+
+			report, err := runBench(cmd, opts)
+			if err != nil {
+				return err
+			}
+
+			return bite.PrintObject(cmd, report)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Topic, "topic", "", "The topic to produce to and to subscribe to")
+	cmd.Flags().IntVar(&opts.Rate, "rate", 100, "Target records per second")
+	cmd.Flags().DurationVar(&opts.Duration, "duration", 30*time.Second, "How long to produce records")
+	cmd.Flags().DurationVar(&opts.Warmup, "warmup", 2*time.Second, "How long to wait for the live query to start before producing")
+	cmd.Flags().DurationVar(&opts.Drain, "drain", 10*time.Second, "How long to wait for the in-flight records after producing")
+	cmd.Flags().IntVar(&opts.Batch, "batch", 1000, "Maximum records per insert statement")
+	cmd.Flags().BoolVar(&opts.Create, "create", false, "Create the topic, if it does not exist, before producing")
+
+	bite.CanPrintJSON(cmd)
+
+	return cmd
+}
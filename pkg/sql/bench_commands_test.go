@@ -0,0 +1,62 @@
+package sql
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLatencyPercentile(t *testing.T) {
+	var sorted []time.Duration
+	for i := 1; i <= 100; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Millisecond)
+	}
+
+	tests := []struct {
+		p        float64
+		expected time.Duration
+	}{
+		{50, 50 * time.Millisecond},
+		{95, 95 * time.Millisecond},
+		{99, 99 * time.Millisecond},
+		{100, 100 * time.Millisecond},
+		{0, 1 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		if got := latencyPercentile(sorted, tt.p); got != tt.expected {
+			t.Fatalf("expected p%v to be [%s] but got [%s]", tt.p, tt.expected, got)
+		}
+	}
+
+	if got := latencyPercentile(nil, 99); got != 0 {
+		t.Fatalf("expected zero for no latencies but got [%s]", got)
+	}
+}
+
+func TestBenchDue(t *testing.T) {
+	tests := []struct {
+		elapsed  time.Duration
+		produced int
+		expected int
+	}{
+		{100 * time.Millisecond, 0, 10},
+		{time.Second, 50, 50},
+		{time.Second, 100, 0},
+		{time.Second, 120, 0},
+		{time.Minute, 0, 500}, // catches up by at most a batch.
+	}
+
+	for i, tt := range tests {
+		if got := benchDue(tt.elapsed, 100, tt.produced, 500); got != tt.expected {
+			t.Fatalf("[%d] expected [%d] records but got [%d]", i, tt.expected, got)
+		}
+	}
+}
+
+func TestBenchInsertStatement(t *testing.T) {
+	got := benchInsertStatement("bench", []benchRecord{{Run: "r1", Seq: 0, Sent: 1600000000000}, {Run: "r1", Seq: 1, Sent: 1600000000001}})
+	expected := "INSERT INTO `bench`(_key, run, seq, sent) VALUES ('r1-0', 'r1', 0, 1600000000000), ('r1-1', 'r1', 1, 1600000000001)"
+	if got != expected {
+		t.Fatalf("expected:\n%s\nbut got:\n%s", expected, got)
+	}
+}
@@ -4,16 +4,18 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"net"
 	"os"
 	"os/signal"
 	"strconv"
-	"strings"
 	"syscall"
 
 	"github.com/kataras/golog"
 	"github.com/lensesio/bite"
 	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/hook"
+	"github.com/lensesio/lenses-go/pkg/progress"
+	"github.com/lensesio/lenses-go/pkg/sink"
+	"github.com/lensesio/lenses-go/pkg/utils"
 	"github.com/lensesio/lenses-go/pkg/websocket"
 	"github.com/spf13/cobra"
 )
@@ -28,6 +30,7 @@ type (
 		Key      json.RawMessage    `json:"key"`
 		Value    json.RawMessage    `json:"value"`
 		Metadata websocket.MetaData `json:"metadata"`
+		Headers  websocket.Headers  `json:"headers,omitempty"`
 	}
 
 	responseWithKeys struct {
@@ -38,11 +41,13 @@ type (
 	responseWithMeta struct {
 		Value    json.RawMessage    `json:"value"`
 		Metadata websocket.MetaData `json:"metadata"`
+		Headers  websocket.Headers  `json:"headers,omitempty"`
 	}
 
 	responseWithKeysWithMetaOnly struct {
 		Key      json.RawMessage    `json:"key"`
 		Metadata websocket.MetaData `json:"metadata"`
+		Headers  websocket.Headers  `json:"headers,omitempty"`
 	}
 )
 
@@ -77,7 +82,7 @@ func readAndQuoteQueries(args []string) ([]string, error) {
 	return []string{query}, nil
 }
 
-func runSQL(cmd *cobra.Command, sql string, meta bool, keys bool, keysOnly bool, liveStream bool, stats bool) error {
+func runSQL(cmd *cobra.Command, sql string, meta bool, keys bool, keysOnly bool, liveStream bool, stats bool, opts liveOptions) error {
 	currentConfig := config.Manager.Config.GetCurrent()
 
 	message := websocket.Message{
@@ -86,38 +91,101 @@ func runSQL(cmd *cobra.Command, sql string, meta bool, keys bool, keysOnly bool,
 		Live:  liveStream,
 		Stats: 2,
 	}
-	conn, err := websocket.OpenLiveConnection(websocket.LiveConfiguration{
+	liveConfig := websocket.LiveConfiguration{
 		Host:    currentConfig.Host,
 		Debug:   currentConfig.Debug,
 		Message: message,
-	})
+		// keep the metadata's timestamps as they are sent instead of float64s.
+		UseNumber: true,
+	}
+	if err := opts.apply(&liveConfig); err != nil {
+		return err
+	}
+
+	conn, closeRecord, err := opts.open(liveConfig)
+	if err != nil {
+		return err
+	}
+	defer logTransferStats(conn)
+	defer func() {
+		if err := closeRecord(); err != nil {
+			fmt.Fprintf(cmd.OutOrStderr(), "[%s]\n", err)
+		}
+	}()
+
+	// the records received so far and their rate, the records themselves are printed above it.
+	bar := progress.New(cmd, "records", 0)
+	defer bar.Done()
 
+	hooks, err := opts.hooks()
 	if err != nil {
+		conn.Close()
 		return err
 	}
+	defer hooks.Close()
+	// before the error reporter, which exits.
+	hooks.Attach(conn, queryTopic(sql))
 
 	go func() {
 		// print each error on screen, do not exit because
 		// a query may be errorred but another, most important may running for a long time.
 		select {
 		case err := <-conn.Err():
-			// ignore error and don't print that caused by ctrl/cmd+c while trying to read.
-			if errNet, isNetworkClosed := err.(*net.OpError); isNetworkClosed && errNet.Op == "read" {
-				if strings.Contains(errNet.Error(), "use of closed") {
-					return
-				}
-			}
-
 			fmt.Fprintf(cmd.OutOrStderr(), "[%s]\n", err)
+			hooks.Error(hook.ErrorEvent{Type: "CONNECTION", Error: err.Error()})
 		}
 	}()
 
+	if stats {
+		conn.OnStats(func(resp websocket.LiveResponse) error {
+			return printJSON(cmd, resp)
+		})
+	}
+
+	transformer, err := opts.Transform.Transformer()
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	duplicates, err := opts.Dedupe.Filter()
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	enricher, err := opts.Enrich.Enricher()
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	batcher, err := opts.attachSink(conn, liveConfig, duplicates, enricher)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	closeSink := func() {
+		if batcher == nil {
+			return
+		}
+		if err := batcher.Close(); err != nil {
+			fmt.Fprintf(cmd.OutOrStderr(), "[%s]\n", err)
+		}
+	}
+	defer closeSink()
+
 	// we exit on error, the only one place that we directly exit from here.
 	errorReporter := func(resp websocket.LiveResponse) error {
 		// parse it, otherwise it shows it very ugly.
 		var errStr string
 		json.Unmarshal(resp.Data.Value, &errStr)
 		_, err = fmt.Fprintf(cmd.OutOrStderr(), "[%s]: [%s]\n", resp.Type, errStr)
+		// the records buffered by the sink are written before it exits.
+		closeSink()
+		hooks.Close()
+		closeRecord()
 		os.Exit(1)
 		return err
 	}
@@ -126,15 +194,36 @@ func runSQL(cmd *cobra.Command, sql string, meta bool, keys bool, keysOnly bool,
 	conn.OnError(errorReporter)
 	conn.OnInvalidRequest(errorReporter)
 
-	if stats {
-		conn.OnStats(func(resp websocket.LiveResponse) error {
-			err := bite.PrintJSON(cmd, resp)
-			return err
-		})
-	}
-
 	// first subscribe to any incoming kafka messages (as result of the lsql publish).
 	conn.OnRecordMessage(func(resp websocket.LiveResponse) error {
+		if batcher != nil {
+			// forwarded to the sink instead.
+			bar.Increment()
+			return nil
+		}
+
+		key, value := resp.Data.Key, resp.Data.Value
+		// by the received record, before it's anonymized.
+		if duplicates != nil && duplicates.Duplicate(sink.Record{Key: key, Value: value, Metadata: resp.Data.Metadata}) {
+			return nil
+		}
+
+		// before it's anonymized, so the added fields can be too.
+		if enricher != nil {
+			var err error
+			if value, err = enricher.Apply(key, value); err != nil {
+				golog.Error(err)
+				return err
+			}
+		}
+
+		if transformer != nil {
+			var err error
+			if key, value, err = transformer.Apply(key, value); err != nil {
+				golog.Error(err)
+				return err
+			}
+		}
 
 		var data interface{}
 
@@ -142,54 +231,63 @@ func runSQL(cmd *cobra.Command, sql string, meta bool, keys bool, keysOnly bool,
 			// keys and metadata only
 			if meta {
 				data = responseWithKeysWithMetaOnly{
-					Key:      resp.Data.Key,
+					Key:      key,
 					Metadata: resp.Data.Metadata,
+					Headers:  resp.Data.Headers,
 				}
 			} else {
-				data = resp.Data.Key
+				data = key
 			}
 		} else {
 			// data only
 			if !keys && !meta {
-				data = resp.Data.Value
+				data = value
 			}
 
 			// data and metadata
 			if !keys && meta {
 				data = responseWithMeta{
-					Value:    resp.Data.Value,
+					Value:    value,
 					Metadata: resp.Data.Metadata,
+					Headers:  resp.Data.Headers,
 				}
 			}
 
 			// keys and data
 			if keys && !meta {
 				data = responseWithKeys{
-					Key:   resp.Data.Key,
-					Value: resp.Data.Value,
+					Key:   key,
+					Value: value,
 				}
 			}
 
 			// keys, data and metadata
 			if keys && meta {
 				data = responseWithKeysWithMeta{
-					Key:      resp.Data.Key,
-					Value:    resp.Data.Value,
+					Key:      key,
+					Value:    value,
 					Metadata: resp.Data.Metadata,
+					Headers:  resp.Data.Headers,
 				}
 			}
 		}
 
-		if err := bite.PrintJSON(cmd, data); err != nil {
+		bar.Clear()
+		if err := printJSON(cmd, data); err != nil {
 			golog.Error(err)
 			return err
 		}
+		bar.Increment()
 
 		return nil
 	})
 
 	conn.OnEnd(func(resp websocket.LiveResponse) error {
+		bar.Done()
+		closeSink()
+		hooks.Close()
 		if !InteractiveShell && sqlLiveStream {
+			closeRecord()
 			os.Exit(0)
 		} else {
 			p, err := os.FindProcess(os.Getpid())
@@ -218,18 +316,38 @@ func runSQL(cmd *cobra.Command, sql string, meta bool, keys bool, keysOnly bool,
 	return conn.Wait(ch)
 }
 
+// printJSON prints a record or a stats message, a single line of JSON with the `--output=ndjson`.
+func printJSON(cmd *cobra.Command, v interface{}) error {
+	if utils.IsNDJSON(cmd) {
+		return utils.PrintNDJSON(cmd, v)
+	}
+
+	return bite.PrintJSON(cmd, v)
+}
+
 //NewLiveLSQLCommand creates `query` command
 func NewLiveLSQLCommand() *cobra.Command {
 
 	cmd := &cobra.Command{
-		Use:              "query",
-		Short:            "Queries, either browsing for continuous (live-stream)",
-		Example:          `query "SELECT * FROM cc_payments LIMIT 10"`,
+		Use:   "query",
+		Short: "Queries, either browsing for continuous (live-stream)",
+		Example: `query "SELECT * FROM cc_payments LIMIT 10"
+query "SELECT * FROM cc_payments" --live-stream --record=payments.jsonl
+query --replay=payments.jsonl --replay-speed=2
+query "SELECT * FROM cc_payments" --live-stream --sink-url=https://hooks.example.com/payments
+query "SELECT * FROM customers LIMIT 100" --redact email --hash customer.id --drop-field address
+query "SELECT * FROM compacted_customers" --dedupe-by key --window 10m
+query "SELECT * FROM payments" --live-stream --enrich-from merchants.csv --on merchant.id=id`,
 		SilenceErrors:    true,
 		TraverseChildren: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			client := config.Client
 
+			if sqlLiveOptions.Replay != "" {
+				// the recorded session has the query already.
+				return runSQL(cmd, "", sqlMeta, sqlKeys, sqlKeysOnly, sqlLiveStream, sqlStats, sqlLiveOptions)
+			}
+
 			if len(args) < 1 {
 				golog.Errorf(`sql query is missing, the correct form is: query "your query"`)
 				return nil
@@ -258,8 +376,7 @@ func NewLiveLSQLCommand() *cobra.Command {
 			}
 
 			checkValidation(validation)
-			runSQL(cmd, queries[0], sqlMeta, sqlKeys, sqlKeysOnly, sqlLiveStream, sqlStats)
-			return nil
+			return runSQL(cmd, queries[0], sqlMeta, sqlKeys, sqlKeysOnly, sqlLiveStream, sqlStats, sqlLiveOptions)
 
 		},
 	}
@@ -268,7 +385,14 @@ func NewLiveLSQLCommand() *cobra.Command {
 	cmd.Flags().BoolVar(&sqlStats, "stats", false, "Print query stats")
 	cmd.Flags().BoolVar(&sqlKeys, "keys", false, "Print message keys")
 	cmd.Flags().BoolVar(&sqlKeysOnly, "keys-only", false, "Print message keys only")
-	cmd.Flags().BoolVar(&sqlMeta, "meta", false, "Print message metadata")
+	cmd.Flags().BoolVar(&sqlMeta, "meta", false, "Print message metadata and headers")
+	sqlLiveOptions.addFlags(cmd.Flags(), true)
+	sqlLiveOptions.addSessionFlags(cmd.Flags())
+	sqlLiveOptions.addSinkFlags(cmd.Flags())
+	sqlLiveOptions.addHookFlags(cmd.Flags())
+	sqlLiveOptions.Dedupe.AddFlags(cmd.Flags())
+	sqlLiveOptions.Enrich.AddFlags(cmd.Flags())
+	sqlLiveOptions.Transform.AddFlags(cmd.Flags())
 
 	bite.CanPrintJSON(cmd)
 
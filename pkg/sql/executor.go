@@ -7,7 +7,7 @@ import (
 
 	"github.com/kataras/golog"
 	"github.com/lensesio/bite"
-	"github.com/lensesio/lenses-go/pkg/api"
+	v1 "github.com/lensesio/lenses-go/v1"
 	"github.com/spf13/cobra"
 )
 
@@ -27,12 +27,12 @@ var sqlQuery string
 //Executor sturct
 type Executor struct {
 	interactiveCmd *cobra.Command
-	client         *api.Client
+	client         v1.Client
 	sqlHistoryPath string
 }
 
 //NewExecutor creates a new executor
-func NewExecutor(interactiveCmd *cobra.Command, client *api.Client, sqlHistoryPath string) *Executor {
+func NewExecutor(interactiveCmd *cobra.Command, client v1.Client, sqlHistoryPath string) *Executor {
 	return &Executor{
 		interactiveCmd: interactiveCmd,
 		client:         client,
@@ -153,7 +153,7 @@ func (e *Executor) Execute(sql string) {
 				return
 			}
 
-			runSQL(e.interactiveCmd, finalQ, sqlMeta, sqlKeys, sqlKeysOnly, sqlLiveStream, sqlStats)
+			runSQL(e.interactiveCmd, finalQ, sqlMeta, sqlKeys, sqlKeysOnly, sqlLiveStream, sqlStats, sqlLiveOptions)
 
 			file, err := os.Create(e.sqlHistoryPath)
 			if err != nil {
@@ -0,0 +1,64 @@
+package sql
+
+import (
+	"strings"
+
+	"github.com/lensesio/bite"
+	"github.com/lensesio/lenses-go/pkg/api"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/spf13/cobra"
+)
+
+//NewExplainCommand creates the `sql explain` command
+func NewExplainCommand() *cobra.Command {
+	var file string
+
+	cmd := &cobra.Command{
+		Use:   "explain",
+		Short: "Print the execution plan of a Lenses SQL browse query, the topics it scans, its filters and its estimated cost",
+		Example: `sql explain "SELECT * FROM cc_payments WHERE _meta.partition = 1 AND amount > 100"
+sql explain -f query.sql --output json`,
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query, err := readQuery(file, args)
+			if err != nil {
+				return err
+			}
+
+			plan, err := config.Client.ExplainSQL(query)
+			if err != nil {
+				return err
+			}
+
+			if strings.ToUpper(bite.GetOutPutFlag(cmd)) != "TABLE" {
+				return bite.PrintObject(cmd, plan)
+			}
+
+			return printPlan(cmd, plan)
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "The file path of the SQL statement to explain")
+
+	bite.CanPrintJSON(cmd)
+
+	return config.RequireFeature(api.FeatureSQLExplain, cmd)
+}
+
+// printPlan prints the scanned topics and the filters of the "plan" as tables, followed by its estimated cost.
+func printPlan(cmd *cobra.Command, plan api.SQLExecutionPlan) error {
+	if len(plan.Topics) > 0 {
+		if err := bite.PrintObject(cmd, plan.Topics); err != nil {
+			return err
+		}
+	}
+
+	if len(plan.Filters) > 0 {
+		if err := bite.PrintObject(cmd, plan.Filters); err != nil {
+			return err
+		}
+	}
+
+	return bite.PrintInfo(cmd, "Estimated cost: %v", plan.EstimatedCost)
+}
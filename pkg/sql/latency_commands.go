@@ -0,0 +1,425 @@
+package sql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/lensesio/bite"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/exitcode"
+	"github.com/lensesio/lenses-go/pkg/transform"
+	"github.com/lensesio/lenses-go/pkg/websocket"
+	"github.com/spf13/cobra"
+)
+
+// latencySamples is the number of the latencies the percentiles of `latency` are computed by.
+const latencySamples = 100000
+
+// latencyReport is a report of the `latency` command.
+type latencyReport struct {
+	From    string `json:"from" header:"From"`
+	To      string `json:"to" header:"To"`
+	Matched int64  `json:"matched" header:"Matched"`
+	// Unmatched is the records of either topic which were not correlated within the timeout.
+	Unmatched int64 `json:"unmatched" header:"Unmatched"`
+	Pending   int   `json:"pending" header:"Pending"`
+	P50       int64 `json:"p50" header:"p50 (ms)"`
+	P95       int64 `json:"p95" header:"p95 (ms)"`
+	P99       int64 `json:"p99" header:"p99 (ms)"`
+	Max       int64 `json:"max" header:"Max (ms)"`
+}
+
+// correlation is the fields of the records of the two topics which correlate them, see `parseCorrelation`.
+type correlation struct {
+	From, To string
+}
+
+// parseCorrelation parses a "<from field>=<to field>" correlation, a single field is the field of both topics.
+// The fields are "key", the dot separated path of a field of the value or `transform.KeyPrefix` for a field of the key.
+func parseCorrelation(s string) (correlation, error) {
+	from, to := s, s
+	if idx := strings.IndexByte(s, '='); idx >= 0 {
+		from, to = s[:idx], s[idx+1:]
+	}
+
+	if from == "" || to == "" {
+		return correlation{}, fmt.Errorf("invalid correlation [%s], expected <field> or <from field>=<to field>, i.e request_id=id", s)
+	}
+
+	return correlation{From: from, To: to}, nil
+}
+
+// correlationID returns the text of the "field" of a record, empty if it has not the field.
+func correlationID(key, value json.RawMessage, field string) string {
+	data, path := value, field
+	switch {
+	case path == "key":
+		data, path = key, ""
+	case strings.HasPrefix(path, transform.KeyPrefix):
+		data, path = key, strings.TrimPrefix(path, transform.KeyPrefix)
+	}
+
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if dec.Decode(&v) != nil {
+		// a key of the STRING format.
+		if path == "" {
+			return string(data)
+		}
+		return ""
+	}
+
+	if path != "" {
+		for _, name := range strings.Split(path, ".") {
+			fields, ok := v.(map[string]interface{})
+			if !ok {
+				return ""
+			}
+
+			if v, ok = fields[name]; !ok {
+				return ""
+			}
+		}
+	}
+
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+}
+
+// pendingEvent is a record waiting for its correlated record of the other topic.
+type pendingEvent struct {
+	timestamp time.Time
+	received  time.Time
+}
+
+// latencyTracker correlates the records of two topics and keeps a sample of their latencies, it's not safe for concurrent use.
+type latencyTracker struct {
+	timeout    time.Duration
+	maxPending int
+	maxSamples int
+
+	// requests and responses are the records waiting for their correlated one, by their correlation id,
+	// a response may be received before its request.
+	requests, responses map[string]pendingEvent
+
+	// samples is a uniform sample, a reservoir, of the latencies of the matched records.
+	samples   []time.Duration
+	matched   int64
+	unmatched int64
+	max       time.Duration
+	rnd       *rand.Rand
+}
+
+func newLatencyTracker(timeout time.Duration, maxPending, maxSamples int) *latencyTracker {
+	return &latencyTracker{
+		timeout:    timeout,
+		maxPending: maxPending,
+		maxSamples: maxSamples,
+		requests:   make(map[string]pendingEvent),
+		responses:  make(map[string]pendingEvent),
+		rnd:        rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// request adds a record of the "from" topic, of the "ts" timestamp, received at "now".
+func (t *latencyTracker) request(id string, ts, now time.Time) {
+	if resp, ok := t.responses[id]; ok {
+		delete(t.responses, id)
+		t.observe(resp.timestamp.Sub(ts))
+		return
+	}
+
+	t.add(t.requests, id, pendingEvent{timestamp: ts, received: now})
+}
+
+// response adds a record of the "to" topic, of the "ts" timestamp, received at "now".
+func (t *latencyTracker) response(id string, ts, now time.Time) {
+	if req, ok := t.requests[id]; ok {
+		delete(t.requests, id)
+		t.observe(ts.Sub(req.timestamp))
+		return
+	}
+
+	t.add(t.responses, id, pendingEvent{timestamp: ts, received: now})
+}
+
+func (t *latencyTracker) add(pending map[string]pendingEvent, id string, e pendingEvent) {
+	if _, exists := pending[id]; exists {
+		// the first one is the start, or the end, of the event.
+		return
+	}
+
+	if len(t.requests)+len(t.responses) >= t.maxPending {
+		t.unmatched++
+		return
+	}
+
+	pending[id] = e
+}
+
+func (t *latencyTracker) observe(latency time.Duration) {
+	// the clocks of the producers of the two topics may be skewed.
+	if latency < 0 {
+		latency = 0
+	}
+
+	t.matched++
+	if latency > t.max {
+		t.max = latency
+	}
+
+	if len(t.samples) < t.maxSamples {
+		t.samples = append(t.samples, latency)
+		return
+	}
+
+	if i := t.rnd.Int63n(t.matched); i < int64(t.maxSamples) {
+		t.samples[i] = latency
+	}
+}
+
+// expire drops the pending records which were received more than the timeout before "now".
+func (t *latencyTracker) expire(now time.Time) {
+	for _, pending := range []map[string]pendingEvent{t.requests, t.responses} {
+		for id, e := range pending {
+			if now.Sub(e.received) > t.timeout {
+				delete(pending, id)
+				t.unmatched++
+			}
+		}
+	}
+}
+
+// report returns the report of the latencies so far.
+func (t *latencyTracker) report(from, to string) latencyReport {
+	sorted := append([]time.Duration(nil), t.samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	return latencyReport{
+		From:      from,
+		To:        to,
+		Matched:   t.matched,
+		Unmatched: t.unmatched,
+		Pending:   len(t.requests) + len(t.responses),
+		P50:       latencyPercentile(sorted, 50).Milliseconds(),
+		P95:       latencyPercentile(sorted, 95).Milliseconds(),
+		P99:       latencyPercentile(sorted, 99).Milliseconds(),
+		Max:       t.max.Milliseconds(),
+	}
+}
+
+// latencyOptions are the `latency` command's flags.
+type latencyOptions struct {
+	From, To   string
+	Correlate  string
+	Timeout    time.Duration
+	Interval   time.Duration
+	Duration   time.Duration
+	MaxPending int
+	MaxP99     time.Duration
+}
+
+// latencyEvent is a record of one of the two topics.
+type latencyEvent struct {
+	response  bool
+	id        string
+	timestamp time.Time
+}
+
+func runLatency(cmd *cobra.Command, opts latencyOptions) (latencyReport, error) {
+	corr, err := parseCorrelation(opts.Correlate)
+	if err != nil {
+		return latencyReport{}, err
+	}
+
+	currentConfig := config.Manager.Config.GetCurrent()
+
+	var (
+		events = make(chan latencyEvent, 1024)
+		errs   = make(chan error, 2)
+		done   = make(chan struct{})
+	)
+	defer close(done)
+
+	subscribe := func(topic, field string, response bool) error {
+		conn, err := websocket.OpenLiveConnection(websocket.LiveConfiguration{
+			Host:  currentConfig.Host,
+			Debug: currentConfig.Debug,
+			Message: websocket.Message{
+				Token: config.Client.Config.Token,
+				SQL:   fmt.Sprintf("SELECT * FROM `%s`", topic),
+				Live:  true,
+				Stats: 0,
+			},
+			UseNumber: true,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to subscribe to topic [%s]: %w", topic, err)
+		}
+		go func() {
+			<-done
+			conn.Close()
+		}()
+
+		go func() {
+			for err := range conn.Err() {
+				fmt.Fprintf(cmd.OutOrStderr(), "[%s]: [%s]\n", topic, err)
+			}
+		}()
+
+		reporter := func(resp websocket.LiveResponse) error {
+			var errStr string
+			json.Unmarshal(resp.Data.Value, &errStr)
+			select {
+			case errs <- fmt.Errorf("[%s]: [%s]: [%s]", topic, resp.Type, errStr):
+			default:
+			}
+			return nil
+		}
+		conn.OnError(reporter)
+		conn.OnInvalidRequest(reporter)
+
+		conn.OnRecordMessage(func(resp websocket.LiveResponse) error {
+			id := correlationID(resp.Data.Key, resp.Data.Value, field)
+			if id == "" {
+				return nil
+			}
+
+			ts := time.Now()
+			if ms := recordTimestamp(resp.Data.Metadata); ms > 0 {
+				ts = time.Unix(0, ms*int64(time.Millisecond))
+			}
+
+			select {
+			case events <- latencyEvent{response: response, id: id, timestamp: ts}:
+			case <-done:
+			}
+			return nil
+		})
+
+		return nil
+	}
+
+	if err = subscribe(opts.From, corr.From, false); err != nil {
+		return latencyReport{}, err
+	}
+	if err = subscribe(opts.To, corr.To, true); err != nil {
+		return latencyReport{}, err
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+
+	var deadline <-chan time.Time
+	if opts.Duration > 0 {
+		deadline = time.After(opts.Duration)
+	}
+
+	var interval <-chan time.Time
+	if opts.Interval > 0 {
+		ticker := time.NewTicker(opts.Interval)
+		defer ticker.Stop()
+		interval = ticker.C
+	}
+
+	expiry := time.NewTicker(time.Second)
+	defer expiry.Stop()
+
+	tracker := newLatencyTracker(opts.Timeout, opts.MaxPending, latencySamples)
+	for {
+		select {
+		case e := <-events:
+			if e.response {
+				tracker.response(e.id, e.timestamp, time.Now())
+			} else {
+				tracker.request(e.id, e.timestamp, time.Now())
+			}
+		case now := <-expiry.C:
+			tracker.expire(now)
+		case <-interval:
+			if err = bite.PrintObject(cmd, tracker.report(opts.From, opts.To)); err != nil {
+				return latencyReport{}, err
+			}
+		case err = <-errs:
+			return tracker.report(opts.From, opts.To), err
+		case <-deadline:
+			return tracker.report(opts.From, opts.To), nil
+		case <-interrupt:
+			return tracker.report(opts.From, opts.To), nil
+		}
+	}
+}
+
+//NewLatencyCommand creates `latency` command
+func NewLatencyCommand() *cobra.Command {
+	var opts latencyOptions
+
+	cmd := &cobra.Command{
+		Use:   "latency",
+		Short: "Measure the end-to-end latency between the correlated records of two topics through live queries",
+		Long: `Measure the end-to-end latency between the correlated records of two topics, i.e the requests and the responses of a pipeline, through live queries.
+The records are correlated by a field, or by a field of each topic, and the latency is the difference of their timestamps.
+The records which are not correlated within the --match-timeout are counted as unmatched. It runs until interrupted or for the --duration
+and prints the report every --interval and at the end, --max-p99 fails the command if the p99 latency is higher.`,
+		Example: `latency --from requests --to responses --correlate request_id
+latency --from requests --to responses --correlate id=request_id --duration 5m --max-p99 200ms
+latency --from orders --to shipments --correlate _key.orderId=orderId --interval 10s --output json`,
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := bite.CheckRequiredFlags(cmd, bite.FlagPair{"from": opts.From, "to": opts.To, "correlate": opts.Correlate}); err != nil {
+				return err
+			}
+
+			if opts.Timeout <= 0 || opts.MaxPending <= 0 {
+				return fmt.Errorf("timeout and max-pending must be positive")
+			}
+
+			report, err := runLatency(cmd, opts)
+			if err != nil {
+				return err
+			}
+
+			if err = bite.PrintObject(cmd, report); err != nil {
+				return err
+			}
+
+			if opts.MaxP99 > 0 && time.Duration(report.P99)*time.Millisecond > opts.MaxP99 {
+				return exitcode.WithCode(exitcode.Validation,
+					fmt.Errorf("p99 latency [%dms] is higher than [%s]", report.P99, opts.MaxP99))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.From, "from", "", "The topic of the first records, i.e the requests")
+	cmd.Flags().StringVar(&opts.To, "to", "", "The topic of the correlated records, i.e the responses")
+	cmd.Flags().StringVar(&opts.Correlate, "correlate", "", "The field which correlates the records, <field> or <from field>=<to field>, key or _key.<field> for the key")
+	cmd.Flags().DurationVar(&opts.Timeout, "match-timeout", time.Minute, "How long a record waits for its correlated one before it's unmatched")
+	cmd.Flags().DurationVar(&opts.Interval, "interval", 0, "Print the report at this interval too, 0 prints it only at the end")
+	cmd.Flags().DurationVar(&opts.Duration, "duration", 0, "How long to measure, 0 measures until interrupted")
+	cmd.Flags().IntVar(&opts.MaxPending, "max-pending", 100000, "The maximum records waiting for their correlated one, the next ones are unmatched")
+	cmd.Flags().DurationVar(&opts.MaxP99, "max-p99", 0, "Fail if the p99 latency is higher, i.e 200ms")
+
+	bite.CanPrintJSON(cmd)
+
+	return cmd
+}
@@ -0,0 +1,76 @@
+package sql
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCorrelation(t *testing.T) {
+	c, err := parseCorrelation("request_id")
+	require.NoError(t, err)
+	assert.Equal(t, correlation{From: "request_id", To: "request_id"}, c)
+
+	c, err = parseCorrelation("id=request.id")
+	require.NoError(t, err)
+	assert.Equal(t, correlation{From: "id", To: "request.id"}, c)
+
+	_, err = parseCorrelation("id=")
+	assert.EqualError(t, err, "invalid correlation [id=], expected <field> or <from field>=<to field>, i.e request_id=id")
+}
+
+func TestCorrelationID(t *testing.T) {
+	key, value := json.RawMessage(`{"id":7}`), json.RawMessage(`{"request":{"id":"r1"},"n":1.5}`)
+
+	assert.Equal(t, "r1", correlationID(key, value, "request.id"))
+	assert.Equal(t, "1.5", correlationID(key, value, "n"))
+	assert.Equal(t, "7", correlationID(key, value, "_key.id"))
+	assert.Equal(t, `{"id":7}`, correlationID(key, value, "key"))
+	assert.Equal(t, "abc", correlationID(json.RawMessage(`abc`), value, "key"))
+	assert.Empty(t, correlationID(key, value, "missing"))
+	assert.Empty(t, correlationID(key, json.RawMessage(`{"id":null}`), "id"))
+}
+
+func TestLatencyTracker(t *testing.T) {
+	start := time.Unix(1000, 0)
+	ms := func(n int) time.Time { return start.Add(time.Duration(n) * time.Millisecond) }
+
+	tr := newLatencyTracker(time.Minute, 3, 100)
+	tr.request("a", ms(0), ms(0))
+	tr.request("a", ms(5), ms(5)) // a retry of the request, the first one is the start.
+	tr.request("b", ms(10), ms(10))
+	tr.response("a", ms(100), ms(100))
+	// the response before its request.
+	tr.response("c", ms(150), ms(150))
+	tr.request("c", ms(120), ms(160))
+	// skewed clocks.
+	tr.response("b", ms(5), ms(170))
+
+	tr.request("d", ms(200), ms(200))
+	tr.request("e", ms(200), ms(200))
+	tr.request("f", ms(200), ms(200))
+	tr.request("g", ms(200), ms(200)) // more than the max pending.
+
+	report := tr.report("requests", "responses")
+	assert.Equal(t, latencyReport{From: "requests", To: "responses", Matched: 3, Unmatched: 1, Pending: 3,
+		P50: 30, P95: 100, P99: 100, Max: 100}, report)
+
+	tr.expire(ms(200).Add(time.Minute + time.Millisecond))
+	report = tr.report("requests", "responses")
+	assert.Equal(t, int64(4), report.Unmatched)
+	assert.Zero(t, report.Pending)
+
+	// the reservoir keeps the max samples.
+	tr = newLatencyTracker(time.Minute, 10, 10)
+	for i := 0; i < 1000; i++ {
+		id := string(rune('a' + i%26))
+		tr.request(id, ms(0), ms(0))
+		tr.response(id, ms(i), ms(i))
+	}
+	assert.Len(t, tr.samples, 10)
+	assert.Equal(t, int64(1000), tr.report("", "").Matched)
+	assert.Equal(t, int64(999), tr.report("", "").Max)
+}
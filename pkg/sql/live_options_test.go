@@ -0,0 +1,54 @@
+package sql
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lensesio/lenses-go/pkg/websocket"
+	"github.com/lensesio/lenses-go/pkg/websocket/wstest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLiveOptionsOpenRecord(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lenses-record")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	srv := wstest.NewServer(wstest.Record(0, 1, "", `{"id":1}`), wstest.End())
+	defer srv.Close()
+
+	opts := liveOptions{Record: filepath.Join(dir, "session.jsonl")}
+	conn, closeRecord, err := opts.open(websocket.LiveConfiguration{Host: srv.URL, Message: websocket.Message{SQL: "SELECT * FROM payments"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	end := make(chan struct{})
+	conn.OnEnd(func(resp websocket.LiveResponse) error {
+		close(end)
+		return nil
+	})
+
+	select {
+	case <-end:
+	case err := <-conn.Err():
+		t.Fatal(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+
+	assert.NoError(t, closeRecord())
+	// closed once, the command closes it on the exits too.
+	assert.NoError(t, closeRecord())
+
+	b, err := ioutil.ReadFile(opts.Record)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"RECORD"`)
+	assert.Contains(t, string(b), `"END"`)
+}
@@ -0,0 +1,252 @@
+package sql
+
+import (
+	"fmt"
+
+	"github.com/kataras/golog"
+	"github.com/lensesio/bite"
+	"github.com/lensesio/lenses-go/pkg/api"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/spf13/cobra"
+)
+
+// savedQueries reads and writes the saved queries of the Lenses server, if "server" is true,
+// otherwise the ones saved locally for the current context.
+type savedQueries struct {
+	server bool
+}
+
+func (s savedQueries) require() error {
+	if !s.server {
+		return nil
+	}
+
+	info, err := config.Client.ServerInfo()
+	if err != nil {
+		// the server has the final word.
+		golog.Debugf("unable to retrieve the server version: [%v]", err)
+		return nil
+	}
+
+	return info.Require(api.FeatureSavedQueries)
+}
+
+func (s savedQueries) list() ([]api.SavedQuery, error) {
+	if err := s.require(); err != nil {
+		return nil, err
+	}
+
+	if s.server {
+		return config.Client.GetSavedQueries()
+	}
+
+	return config.SavedQueries(config.Manager.Config.CurrentContext)
+}
+
+func (s savedQueries) get(name string) (api.SavedQuery, error) {
+	queries, err := s.list()
+	if err != nil {
+		return api.SavedQuery{}, err
+	}
+
+	for _, q := range queries {
+		if q.Name == name {
+			return q, nil
+		}
+	}
+
+	return api.SavedQuery{}, fmt.Errorf("saved query [%s] not found", name)
+}
+
+func (s savedQueries) save(query api.SavedQuery) error {
+	if err := s.require(); err != nil {
+		return err
+	}
+
+	if s.server {
+		return config.Client.SaveQuery(query)
+	}
+
+	return config.SaveQuery(config.Manager.Config.CurrentContext, query)
+}
+
+func (s savedQueries) delete(name string) error {
+	if err := s.require(); err != nil {
+		return err
+	}
+
+	if s.server {
+		return config.Client.DeleteSavedQuery(name)
+	}
+
+	found, err := config.RemoveSavedQuery(config.Manager.Config.CurrentContext, name)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return fmt.Errorf("saved query [%s] not found", name)
+	}
+
+	return nil
+}
+
+//NewQueriesCommand creates the `queries` command
+func NewQueriesCommand() *cobra.Command {
+	var store savedQueries
+
+	root := &cobra.Command{
+		Use:   "queries",
+		Short: "Manage named SQL queries, saved locally per context or on the Lenses server with --server",
+		Example: `queries save --name orders-missing-payment -f orders-missing-payment.sql
+queries list
+queries run --name orders-missing-payment --keys
+queries delete --name orders-missing-payment`,
+		SilenceErrors:    true,
+		TraverseChildren: true,
+	}
+
+	root.PersistentFlags().BoolVar(&store.server, "server", false, "Use the saved queries of the Lenses server, shared by its users, instead of the local ones of the current context")
+
+	root.AddCommand(newSaveQueryCommand(&store))
+	root.AddCommand(newListQueriesCommand(&store))
+	root.AddCommand(newRunQueryCommand(&store))
+	root.AddCommand(newDeleteQueryCommand(&store))
+
+	return root
+}
+
+func newSaveQueryCommand(store *savedQueries) *cobra.Command {
+	var (
+		query api.SavedQuery
+		file  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "save",
+		Short: "Save a named SQL query, it replaces the query of the same name",
+		Example: `queries save --name orders-missing-payment -f orders-missing-payment.sql --description "Orders without a payment in the last hour"
+queries save --name big-payments "SELECT * FROM payments WHERE amount > 1000" --server`,
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := bite.CheckRequiredFlags(cmd, bite.FlagPair{"name": query.Name}); err != nil {
+				return err
+			}
+
+			sql, err := readQuery(file, args)
+			if err != nil {
+				return err
+			}
+			query.SQL = sql
+
+			if err = store.save(query); err != nil {
+				return fmt.Errorf("failed to save query [%s]. [%v]", query.Name, err)
+			}
+
+			return bite.PrintInfo(cmd, "Query [%s] saved", query.Name)
+		},
+	}
+
+	cmd.Flags().StringVar(&query.Name, "name", "", "The name of the query")
+	cmd.Flags().StringVar(&query.Description, "description", "", "The description of the query")
+	cmd.Flags().StringVarP(&file, "file", "f", "", "The file path of the SQL statement")
+	bite.CanBeSilent(cmd)
+
+	return cmd
+}
+
+func newListQueriesCommand(store *savedQueries) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:              "list",
+		Short:            "List the saved queries",
+		Example:          `queries list --output json`,
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			queries, err := store.list()
+			if err != nil {
+				return err
+			}
+
+			return bite.PrintObject(cmd, queries)
+		},
+	}
+
+	bite.CanPrintJSON(cmd)
+
+	return cmd
+}
+
+func newRunQueryCommand(store *savedQueries) *cobra.Command {
+	var (
+		name                                    string
+		liveStream, stats, keys, keysOnly, meta bool
+		opts                                    liveOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Run a saved query, like the query command does",
+		Example: `queries run --name orders-missing-payment
+queries run --name big-payments --server --live-stream --output ndjson`,
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := bite.CheckRequiredFlags(cmd, bite.FlagPair{"name": name}); err != nil {
+				return err
+			}
+
+			query, err := store.get(name)
+			if err != nil {
+				return err
+			}
+
+			return runSQL(cmd, query.SQL, meta, keys, keysOnly, liveStream, stats, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "The name of the query")
+	cmd.Flags().BoolVar(&liveStream, "live-stream", false, "Run in continuous query mode")
+	cmd.Flags().BoolVar(&stats, "stats", false, "Print query stats")
+	cmd.Flags().BoolVar(&keys, "keys", false, "Print message keys")
+	cmd.Flags().BoolVar(&keysOnly, "keys-only", false, "Print message keys only")
+	cmd.Flags().BoolVar(&meta, "meta", false, "Print message metadata and headers")
+	opts.addFlags(cmd.Flags(), true)
+
+	bite.CanPrintJSON(cmd)
+
+	return cmd
+}
+
+func newDeleteQueryCommand(store *savedQueries) *cobra.Command {
+	var name string
+
+	cmd := &cobra.Command{
+		Use:              "delete",
+		Short:            "Delete a saved query",
+		Example:          `queries delete --name orders-missing-payment`,
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := bite.CheckRequiredFlags(cmd, bite.FlagPair{"name": name}); err != nil {
+				return err
+			}
+
+			if err := config.Confirm("saved query", name); err != nil {
+				return err
+			}
+
+			if err := store.delete(name); err != nil {
+				return fmt.Errorf("failed to delete query [%s]. [%v]", name, err)
+			}
+
+			return bite.PrintInfo(cmd, "Query [%s] deleted", name)
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "The name of the query")
+	bite.CanBeSilent(cmd)
+
+	return cmd
+}
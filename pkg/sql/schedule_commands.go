@@ -0,0 +1,142 @@
+package sql
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/lensesio/bite"
+	"github.com/lensesio/lenses-go/pkg/schedule"
+	"github.com/lensesio/lenses-go/pkg/websocket"
+	"github.com/spf13/cobra"
+)
+
+// scheduledRecord is a record of a scheduled run, its metadata are left out so the same records compare equal between runs.
+type scheduledRecord struct {
+	Key   json.RawMessage `json:"key,omitempty"`
+	Value json.RawMessage `json:"value"`
+}
+
+// browseRecords runs the browse "sql" and returns its records, once it ends.
+func browseRecords(sql string, timeout time.Duration) ([]json.RawMessage, error) {
+	conn, err := websocket.OpenLiveConnection(benchLiveConfiguration(sql, false))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var records []json.RawMessage
+	conn.OnRecordMessage(func(resp websocket.LiveResponse) error {
+		b, err := json.Marshal(scheduledRecord{Key: resp.Data.Key, Value: resp.Data.Value})
+		if err != nil {
+			return err
+		}
+
+		records = append(records, b)
+		return nil
+	})
+
+	done := make(chan error, 1)
+	reporter := func(resp websocket.LiveResponse) error {
+		var errStr string
+		json.Unmarshal(resp.Data.Value, &errStr)
+		select {
+		case done <- fmt.Errorf("[%s]: [%s]", resp.Type, errStr):
+		default:
+		}
+		return nil
+	}
+
+	conn.OnError(reporter)
+	conn.OnInvalidRequest(reporter)
+	conn.OnEnd(func(websocket.LiveResponse) error {
+		select {
+		case done <- nil:
+		default:
+		}
+		return nil
+	})
+
+	select {
+	case err = <-done:
+		return records, err
+	case err = <-conn.Err():
+		return nil, err
+	case <-time.After(timeout):
+		return nil, fmt.Errorf("query did not complete in [%s]", timeout)
+	}
+}
+
+//NewScheduleCommand creates the `sql schedule` command
+func NewScheduleCommand() *cobra.Command {
+	var (
+		file      string
+		onResults []string
+		timeout   time.Duration
+		runner    = schedule.Runner{Every: 5 * time.Minute}
+	)
+
+	cmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "Run a Lenses SQL browse query periodically and run a command or call a webhook when its records change",
+		Long: `Run a Lenses SQL browse query periodically and run a command or call a webhook when its records change.
+The records of each run are compared with the ones of the previous run, the first run is compared with no records.
+An action is "exec <command>", which runs the command through the shell with the change as JSON input
+and the LENSES_QUERY, LENSES_RECORDS, LENSES_ADDED, LENSES_REMOVED environment variables,
+or "webhook <url>", which posts the change as JSON. The change is {"query", "time", "records", "added", "removed"}.`,
+		Example: `sql schedule -f query.sql --every 5m --on-results "exec ./alert.sh"
+sql schedule "SELECT * FROM payments WHERE amount > 10000" --every 1m --on-results "webhook https://hooks.example.com/lenses"
+sql schedule -f query.sql --every 30s --always --runs 10 --on-results "exec cat"`,
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(onResults) == 0 {
+				return errors.New("required flag --on-results is missing")
+			}
+
+			query, err := readQuery(file, args)
+			if err != nil {
+				return err
+			}
+
+			for _, s := range onResults {
+				action, err := schedule.ParseAction(s)
+				if err != nil {
+					return err
+				}
+				runner.Actions = append(runner.Actions, action)
+			}
+
+			runner.Query = query
+			runner.Browse = func(sql string) ([]json.RawMessage, error) {
+				return browseRecords(sql, timeout)
+			}
+
+			stop := make(chan struct{})
+			interrupt := make(chan os.Signal, 1)
+			signal.Notify(interrupt, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
+			defer signal.Stop(interrupt)
+			go func() {
+				<-interrupt
+				close(stop)
+			}()
+
+			return runner.Start(stop)
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "The file path of the SQL browse query to run")
+	cmd.Flags().DurationVar(&runner.Every, "every", runner.Every, "The interval between the runs, i.e 30s, 5m, 1h")
+	cmd.Flags().StringArrayVar(&onResults, "on-results", nil, `The action to trigger on a change, "exec <command>" or "webhook <url>", can be repeated`)
+	cmd.Flags().BoolVar(&runner.Always, "always", false, "Trigger the actions on every run that returned records, even if they did not change")
+	cmd.Flags().IntVar(&runner.Runs, "runs", 0, "The number of runs, 0 runs until interrupted")
+	cmd.Flags().DurationVar(&timeout, "run-timeout", time.Minute, "How long to wait for each run of the query to complete")
+
+	bite.CanBeSilent(cmd)
+
+	return cmd
+}
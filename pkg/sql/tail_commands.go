@@ -0,0 +1,309 @@
+package sql
+
+import (
+	"container/heap"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/lensesio/bite"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/hook"
+	"github.com/lensesio/lenses-go/pkg/websocket"
+	"github.com/spf13/cobra"
+)
+
+// tailRecord is a record received from one of the tailed topics.
+type tailRecord struct {
+	Topic     string          `json:"topic"`
+	Partition int             `json:"partition"`
+	Offset    int             `json:"offset"`
+	Timestamp int64           `json:"timestamp"`
+	Key       json.RawMessage `json:"key,omitempty"`
+	Value     json.RawMessage `json:"value"`
+	// Headers are printed with the JSON output only.
+	Headers websocket.Headers `json:"headers,omitempty"`
+
+	received time.Time
+	seq      uint64 // keeps the arrival order of records with the same timestamp.
+}
+
+// recordTimestamp returns the record's metadata timestamp in milliseconds, zero if missing or unknown.
+func recordTimestamp(md websocket.MetaData) int64 {
+	switch ts := md.Timestamp.(type) {
+	case json.Number:
+		n, _ := ts.Int64()
+		return n
+	case float64:
+		return int64(ts)
+	case string:
+		n, _ := strconv.ParseInt(ts, 10, 64)
+		return n
+	default:
+		return 0
+	}
+}
+
+type tailHeap []tailRecord
+
+func (h tailHeap) Len() int { return len(h) }
+func (h tailHeap) Less(i, j int) bool {
+	if h[i].Timestamp != h[j].Timestamp {
+		return h[i].Timestamp < h[j].Timestamp
+	}
+	return h[i].seq < h[j].seq
+}
+func (h tailHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *tailHeap) Push(x interface{}) { *h = append(*h, x.(tailRecord)) }
+func (h *tailHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// tailMerger merges the records of many topics ordered by their timestamp.
+// Each record is held back for the reordering "window" after it was received,
+// so a record that arrives late, by at most the window, is still printed in order.
+type tailMerger struct {
+	window  time.Duration
+	records tailHeap
+	seq     uint64
+}
+
+func (m *tailMerger) push(rec tailRecord, now time.Time) {
+	m.seq++
+	rec.seq = m.seq
+	rec.received = now
+	heap.Push(&m.records, rec)
+}
+
+// pop returns, in timestamp order, the records that their reordering window has passed at "now".
+func (m *tailMerger) pop(now time.Time) (records []tailRecord) {
+	for m.records.Len() > 0 && now.Sub(m.records[0].received) >= m.window {
+		records = append(records, heap.Pop(&m.records).(tailRecord))
+	}
+
+	return
+}
+
+// flush returns all the buffered records in timestamp order.
+func (m *tailMerger) flush() (records []tailRecord) {
+	for m.records.Len() > 0 {
+		records = append(records, heap.Pop(&m.records).(tailRecord))
+	}
+
+	return
+}
+
+func printTailRecord(cmd *cobra.Command, rec tailRecord, keys bool) error {
+	if out := strings.ToUpper(bite.GetOutPutFlag(cmd)); out == "JSON" || out == "NDJSON" {
+		if !keys {
+			rec.Key = nil
+		}
+		return printJSON(cmd, rec)
+	}
+
+	out := cmd.OutOrStdout()
+	if keys {
+		_, err := fmt.Fprintf(out, "[%s:%d] %s %s\n", rec.Topic, rec.Partition, rec.Key, rec.Value)
+		return err
+	}
+
+	_, err := fmt.Fprintf(out, "[%s:%d] %s\n", rec.Topic, rec.Partition, rec.Value)
+	return err
+}
+
+// reportErrors calls "report" for each error of a connection's "errs" until "done" is closed,
+// the errors channel of a connection is never closed.
+func reportErrors(errs <-chan error, done <-chan struct{}, report func(error)) {
+	for {
+		select {
+		case err := <-errs:
+			report(err)
+		case <-done:
+			return
+		}
+	}
+}
+
+func runTail(cmd *cobra.Command, topics []string, window time.Duration, keys bool, opts liveOptions) error {
+	currentConfig := config.Manager.Config.GetCurrent()
+
+	transformer, err := opts.Transform.Transformer()
+	if err != nil {
+		return err
+	}
+
+	hooks, err := opts.hooks()
+	if err != nil {
+		return err
+	}
+	defer hooks.Close()
+
+	var (
+		records = make(chan tailRecord)
+		errs    = make(chan error, len(topics))
+		done    = make(chan struct{})
+		conns   []*websocket.LiveConnection
+	)
+
+	closeAll := func() {
+		close(done)
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}
+
+	for _, topic := range topics {
+		topic := topic
+		liveConfig := websocket.LiveConfiguration{
+			Host:  currentConfig.Host,
+			Debug: currentConfig.Debug,
+			Message: websocket.Message{
+				Token: config.Client.Config.Token,
+				SQL:   fmt.Sprintf("SELECT * FROM `%s`", topic),
+				Live:  true,
+				Stats: 0,
+			},
+			UseNumber: true,
+		}
+		if err := opts.apply(&liveConfig); err != nil {
+			closeAll()
+			return err
+		}
+
+		conn, err := websocket.OpenLiveConnection(liveConfig)
+		if err != nil {
+			closeAll()
+			return fmt.Errorf("unable to tail topic [%s]: %w", topic, err)
+		}
+		conns = append(conns, conn)
+		defer logTransferStats(conn)
+
+		go reportErrors(conn.Err(), done, func(err error) {
+			fmt.Fprintf(cmd.OutOrStderr(), "[%s]: [%s]\n", topic, err)
+			hooks.Error(hook.ErrorEvent{Type: "CONNECTION", Topic: topic, Error: err.Error()})
+		})
+
+		// before the error reporter, which stops the tail.
+		hooks.Attach(conn, topic)
+
+		errorReporter := func(resp websocket.LiveResponse) error {
+			var errStr string
+			json.Unmarshal(resp.Data.Value, &errStr)
+			select {
+			case errs <- fmt.Errorf("[%s]: [%s]: [%s]", topic, resp.Type, errStr):
+			default:
+			}
+			return nil
+		}
+
+		conn.OnError(errorReporter)
+		conn.OnInvalidRequest(errorReporter)
+
+		conn.OnRecordMessage(func(resp websocket.LiveResponse) error {
+			key, value := resp.Data.Key, resp.Data.Value
+			if transformer != nil {
+				var err error
+				if key, value, err = transformer.Apply(key, value); err != nil {
+					return err
+				}
+			}
+
+			rec := tailRecord{
+				Topic:     topic,
+				Partition: resp.Data.Metadata.Partition,
+				Offset:    resp.Data.Metadata.Offset,
+				Timestamp: recordTimestamp(resp.Data.Metadata),
+				Key:       key,
+				Value:     value,
+				Headers:   resp.Data.Headers,
+			}
+
+			select {
+			case records <- rec:
+			case <-done:
+			}
+			return nil
+		})
+	}
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
+
+	tick := window / 4
+	if tick <= 0 {
+		tick = 10 * time.Millisecond
+	}
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	merger := &tailMerger{window: window}
+	printRecords := func(records []tailRecord) error {
+		for _, rec := range records {
+			if err := printTailRecord(cmd, rec, keys); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for {
+		select {
+		case rec := <-records:
+			merger.push(rec, time.Now())
+		case now := <-ticker.C:
+			if err := printRecords(merger.pop(now)); err != nil {
+				closeAll()
+				return err
+			}
+		case err := <-errs:
+			closeAll()
+			printRecords(merger.flush())
+			return err
+		case <-ch:
+			closeAll()
+			return printRecords(merger.flush())
+		}
+	}
+}
+
+//NewTailCommand creates `tail` command
+func NewTailCommand() *cobra.Command {
+	var (
+		window time.Duration
+		keys   bool
+		opts   liveOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "tail <topic> [<topic>...]",
+		Short: "Follow the new records of one or more topics, merged in timestamp order",
+		Example: `tail topicA topicB topicC
+tail topicA topicB --window=2s --keys --output=json
+tail huge_topic --sample-rate=1%`,
+		Args:             cobra.MinimumNArgs(1),
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTail(cmd, args, window, keys, opts)
+		},
+	}
+
+	cmd.Flags().DurationVar(&window, "window", 500*time.Millisecond, "How long to hold back each record so late records of other topics can be printed before it")
+	cmd.Flags().BoolVar(&keys, "keys", false, "Print record keys")
+	opts.addFlags(cmd.Flags(), false)
+	opts.addHookFlags(cmd.Flags())
+	opts.Transform.AddFlags(cmd.Flags())
+
+	bite.CanPrintJSON(cmd)
+
+	return cmd
+}
@@ -0,0 +1,68 @@
+package sql
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lensesio/lenses-go/pkg/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTailMerger(t *testing.T) {
+	var (
+		start  = time.Now()
+		merger = &tailMerger{window: time.Second}
+	)
+
+	merger.push(tailRecord{Topic: "a", Timestamp: 30}, start)
+	merger.push(tailRecord{Topic: "b", Timestamp: 10}, start.Add(100*time.Millisecond))
+	merger.push(tailRecord{Topic: "c", Timestamp: 20}, start.Add(200*time.Millisecond))
+
+	// records are held back until the window passes for the earliest one.
+	assert.Empty(t, merger.pop(start.Add(500*time.Millisecond)))
+
+	topics := func(records []tailRecord) (names []string) {
+		for _, rec := range records {
+			names = append(names, rec.Topic)
+		}
+		return
+	}
+
+	assert.Equal(t, []string{"b", "c", "a"}, topics(merger.pop(start.Add(1200*time.Millisecond))))
+
+	merger.push(tailRecord{Topic: "a", Timestamp: 40}, start.Add(1300*time.Millisecond))
+	merger.push(tailRecord{Topic: "b", Timestamp: 40}, start.Add(1400*time.Millisecond))
+	assert.Equal(t, []string{"a", "b"}, topics(merger.flush()))
+}
+
+func TestRecordTimestamp(t *testing.T) {
+	assert.Equal(t, int64(1594303200000), recordTimestamp(websocket.MetaData{Timestamp: json.Number("1594303200000")}))
+	assert.Equal(t, int64(1594303200000), recordTimestamp(websocket.MetaData{Timestamp: float64(1594303200000)}))
+	assert.Equal(t, int64(0), recordTimestamp(websocket.MetaData{}))
+}
+
+func TestReportErrors(t *testing.T) {
+	var (
+		errs     = make(chan error)
+		done     = make(chan struct{})
+		reported = make(chan error, 1)
+		returned = make(chan struct{})
+	)
+
+	go func() {
+		reportErrors(errs, done, func(err error) { reported <- err })
+		close(returned)
+	}()
+
+	errs <- errors.New("connection reset")
+	assert.EqualError(t, <-reported, "connection reset")
+
+	close(done)
+	select {
+	case <-returned:
+	case <-time.After(5 * time.Second):
+		t.Fatal("reportErrors did not return after done")
+	}
+}
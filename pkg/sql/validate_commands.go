@@ -0,0 +1,105 @@
+package sql
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/lensesio/bite"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/spf13/cobra"
+)
+
+//NewSQLGroupCommand creates the `sql` command
+func NewSQLGroupCommand() *cobra.Command {
+	root := &cobra.Command{
+		Use:   "sql",
+		Short: "Validate, explain and schedule Lenses SQL statements",
+		Example: `sql validate -f query.sql
+sql explain -f query.sql
+sql schedule -f query.sql --every 5m --on-results "exec ./alert.sh"`,
+		SilenceErrors:    true,
+		TraverseChildren: true,
+	}
+
+	root.AddCommand(NewValidateCommand())
+	root.AddCommand(NewExplainCommand())
+	root.AddCommand(NewScheduleCommand())
+
+	return root
+}
+
+//NewValidateCommand creates the `sql validate` command
+func NewValidateCommand() *cobra.Command {
+	var (
+		file   string
+		strict bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate a Lenses SQL statement and print its errors, if any, with their line and column",
+		Example: `sql validate -f query.sql
+sql validate "SELECT * FROM cc_payments LIIT 10"
+cat query.sql | sql validate --strict`,
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query, err := readQuery(file, args)
+			if err != nil {
+				return err
+			}
+
+			validation, err := config.Client.ValidateSQL(query, len(query))
+			if err != nil {
+				return err
+			}
+
+			errs := validation.Errors(strict)
+			if len(errs) == 0 {
+				return bite.PrintInfo(cmd, "SQL is valid")
+			}
+
+			if err := bite.PrintObject(cmd, errs); err != nil {
+				return err
+			}
+
+			// fail, so pipelines can stop on invalid queries.
+			return fmt.Errorf("sql validation failed with [%d] error(s)", len(errs))
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "The file path of the SQL statement to validate")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Treat warnings as errors")
+
+	bite.CanPrintJSON(cmd)
+	bite.CanBeSilent(cmd)
+
+	return cmd
+}
+
+// readQuery returns the query from the "file" if not empty, otherwise from the first argument or the input pipe.
+func readQuery(file string, args []string) (string, error) {
+	if file != "" {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("unable to read the sql file [%s]: [%v]", file, err)
+		}
+
+		return string(b), nil
+	}
+
+	if len(args) > 0 {
+		return args[0], nil
+	}
+
+	has, b, err := bite.ReadInPipe()
+	if err != nil {
+		return "", fmt.Errorf("io pipe: [%v]", err)
+	}
+
+	if !has || len(b) == 0 {
+		return "", fmt.Errorf("sql argument is missing, use the --file flag or pass the query as argument or through the input pipe")
+	}
+
+	return string(b), nil
+}
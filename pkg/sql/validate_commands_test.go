@@ -0,0 +1,82 @@
+package sql
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	test "github.com/lensesio/lenses-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const invalidSQLValidationResponse = `{"input":"SELECT *\nFROM payments\nLIIT 10","caret":0,"lints":[{"start":23,"end":27,"text":"Invalid syntax","type":"error"}]}`
+
+func TestValidateCommand(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(invalidSQLValidationResponse))
+	})
+	httpClient, teardown := test.TestingHTTPClient(h)
+	defer teardown()
+
+	client, err := api.OpenConnection(test.ClientConfig, api.UsingClient(httpClient))
+	assert.Nil(t, err)
+	config.Client = client
+
+	cmd := NewSQLGroupCommand()
+	var outputValue string
+	cmd.PersistentFlags().StringVar(&outputValue, "output", "json", "")
+	output, err := test.ExecuteCommand(cmd, "validate", "SELECT *\nFROM payments\nLIIT 10")
+
+	assert.EqualError(t, err, "sql validation failed with [1] error(s)")
+	assert.Contains(t, output, `{"line":3,"column":1,"message":"Invalid syntax"}`)
+
+	config.Client = nil
+}
+
+func TestValidateCommandValid(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"input":"SELECT * FROM payments","lints":[]}`))
+	})
+	httpClient, teardown := test.TestingHTTPClient(h)
+	defer teardown()
+
+	client, err := api.OpenConnection(test.ClientConfig, api.UsingClient(httpClient))
+	assert.Nil(t, err)
+	config.Client = client
+
+	cmd := NewSQLGroupCommand()
+	var outputValue string
+	cmd.PersistentFlags().StringVar(&outputValue, "output", "json", "")
+	output, err := test.ExecuteCommand(cmd, "validate", "SELECT * FROM payments")
+
+	assert.Nil(t, err)
+	assert.Contains(t, output, "SQL is valid")
+
+	config.Client = nil
+}
+
+func TestExplainCommand(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/sql/explain", r.URL.Path)
+		w.Write([]byte(`{"sql":"SELECT * FROM payments WHERE _meta.partition = 1","topics":[{"name":"payments","scan":"partitions","partitions":[1],"estimatedRecords":1200,"estimatedBytes":48000}],"filters":[{"expression":"_meta.partition = 1","pushedDown":true}],"estimatedCost":12.5}`))
+	})
+	httpClient, teardown := test.TestingHTTPClient(h)
+	defer teardown()
+
+	client, err := api.OpenConnection(test.ClientConfig, api.UsingClient(httpClient))
+	assert.Nil(t, err)
+	config.Client = client
+
+	cmd := NewSQLGroupCommand()
+	var outputValue string
+	cmd.PersistentFlags().StringVar(&outputValue, "output", "json", "")
+	output, err := test.ExecuteCommand(cmd, "explain", "SELECT * FROM payments WHERE _meta.partition = 1")
+
+	assert.Nil(t, err)
+	assert.Contains(t, output, `"topics":[{"name":"payments","scan":"partitions","partitions":[1],"estimatedRecords":1200,"estimatedBytes":48000}]`)
+	assert.Contains(t, output, `"filters":[{"expression":"_meta.partition = 1","pushedDown":true}]`)
+	assert.Contains(t, output, `"estimatedCost":12.5`)
+
+	config.Client = nil
+}
@@ -0,0 +1,196 @@
+// Package throttle limits the records and the bytes per second that the commands produce, with token buckets,
+// so a backfill does not flood a production cluster by accident.
+package throttle
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// Rate is a number of events per second, zero is unlimited.
+// It's parsed from "<n>/s", "<n>/m", "<n>/h" or a plain "<n>" per second, i.e "1000/s".
+type Rate float64
+
+// ParseRate returns the rate of the "s", see `Rate`.
+func ParseRate(s string) (Rate, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+
+	n, unit := s, "s"
+	if idx := strings.IndexByte(s, '/'); idx >= 0 {
+		n, unit = s[:idx], s[idx+1:]
+	}
+
+	v, err := strconv.ParseFloat(n, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid rate [%s], expected <n>/s, <n>/m or <n>/h, i.e 1000/s", s)
+	}
+
+	switch unit {
+	case "s":
+	case "m":
+		v /= 60
+	case "h":
+		v /= 3600
+	default:
+		return 0, fmt.Errorf("invalid rate [%s], expected <n>/s, <n>/m or <n>/h, i.e 1000/s", s)
+	}
+
+	return Rate(v), nil
+}
+
+func (r Rate) String() string {
+	if r == 0 {
+		return "0"
+	}
+
+	return strconv.FormatFloat(float64(r), 'f', -1, 64) + "/s"
+}
+
+// Set implements the `pflag.Value`.
+func (r *Rate) Set(s string) error {
+	v, err := ParseRate(s)
+	if err != nil {
+		return err
+	}
+
+	*r = v
+	return nil
+}
+
+// Type implements the `pflag.Value`.
+func (r *Rate) Type() string {
+	return "rate"
+}
+
+// Bucket is a token bucket of a rate per second which holds up to a second of tokens.
+// Taking more tokens than it holds is allowed, the next takers wait for the debt to be paid.
+type Bucket struct {
+	rate     float64
+	capacity float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+
+	now func() time.Time
+}
+
+// NewBucket returns a full `Bucket` of the "rate" per second.
+func NewBucket(rate float64) *Bucket {
+	capacity := rate
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	b := &Bucket{rate: rate, capacity: capacity, tokens: capacity, now: time.Now}
+	b.last = b.now()
+	return b
+}
+
+// Reserve takes "n" tokens and returns the time to wait before they are available.
+func (b *Bucket) Reserve(n float64) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	b.last = now
+
+	b.tokens -= n
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// Limiter limits the records and the bytes per second, a nil one is unlimited.
+type Limiter struct {
+	records *Bucket
+	bytes   *Bucket
+}
+
+// New returns a `Limiter` of the "records" and "bytesPerSec" rates, nil if both of them are unlimited.
+func New(records Rate, bytesPerSec int64) *Limiter {
+	if records <= 0 && bytesPerSec <= 0 {
+		return nil
+	}
+
+	l := new(Limiter)
+	if records > 0 {
+		l.records = NewBucket(float64(records))
+	}
+
+	if bytesPerSec > 0 {
+		l.bytes = NewBucket(float64(bytesPerSec))
+	}
+
+	return l
+}
+
+// Reserve takes the tokens of "records" records of "bytes" bytes and returns the time to wait before they can be sent.
+func (l *Limiter) Reserve(records, bytes int) time.Duration {
+	if l == nil {
+		return 0
+	}
+
+	var wait time.Duration
+	if l.records != nil {
+		wait = l.records.Reserve(float64(records))
+	}
+
+	if l.bytes != nil {
+		if w := l.bytes.Reserve(float64(bytes)); w > wait {
+			wait = w
+		}
+	}
+
+	return wait
+}
+
+// Wait waits until "records" records of "bytes" bytes can be sent, or until the "stop" is closed,
+// it reports false if it's stopped. The "stop" may be nil.
+func (l *Limiter) Wait(records, bytes int, stop <-chan struct{}) bool {
+	wait := l.Reserve(records, bytes)
+	if wait <= 0 {
+		return true
+	}
+
+	t := time.NewTimer(wait)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return true
+	case <-stop:
+		return false
+	}
+}
+
+// Options are the rates of a `Limiter`.
+type Options struct {
+	Rate           Rate
+	MaxBytesPerSec int64
+}
+
+// AddFlags registers the "--rate" and "--max-bytes-per-sec" flags.
+func (opts *Options) AddFlags(flags *pflag.FlagSet) {
+	flags.Var(&opts.Rate, "rate", "The maximum records per second, i.e 1000/s or 60000/m, 0 is unlimited")
+	flags.Int64Var(&opts.MaxBytesPerSec, "max-bytes-per-sec", 0, "The maximum bytes of the keys and values per second, 0 is unlimited")
+}
+
+// Limiter returns the limiter of the options, nil if they are unlimited.
+func (opts Options) Limiter() *Limiter {
+	return New(opts.Rate, opts.MaxBytesPerSec)
+}
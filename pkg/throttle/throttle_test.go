@@ -0,0 +1,85 @@
+package throttle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Rate
+	}{
+		{"", 0},
+		{"1000", 1000},
+		{"1000/s", 1000},
+		{"600/m", 10},
+		{"7200/h", 2},
+		{"0.5/s", 0.5},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseRate(tt.in)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+
+	for _, in := range []string{"fast", "10/d", "-1/s"} {
+		_, err := ParseRate(in)
+		assert.EqualError(t, err, "invalid rate ["+in+"], expected <n>/s, <n>/m or <n>/h, i.e 1000/s")
+	}
+}
+
+func TestBucket(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := NewBucket(10)
+	b.now = func() time.Time { return now }
+	b.last = now
+
+	// a second of tokens.
+	assert.Zero(t, b.Reserve(10))
+	assert.Equal(t, 100*time.Millisecond, b.Reserve(1))
+
+	// the debt of a batch larger than the bucket.
+	now = now.Add(100 * time.Millisecond)
+	assert.Equal(t, 2*time.Second, b.Reserve(20))
+
+	// it does not hold more than a second of tokens.
+	now = now.Add(time.Hour)
+	assert.Zero(t, b.Reserve(10))
+	assert.NotZero(t, b.Reserve(1))
+}
+
+func TestLimiter(t *testing.T) {
+	var l *Limiter
+	assert.Zero(t, l.Reserve(1000, 1<<20), "nil is unlimited")
+	assert.True(t, l.Wait(1000, 1<<20, nil))
+	assert.Nil(t, New(0, 0))
+
+	// the slower of the two rates.
+	l = New(1000, 100)
+	assert.Zero(t, l.Reserve(1, 100))
+	wait := l.Reserve(1, 50)
+	assert.True(t, wait > 400*time.Millisecond && wait <= 500*time.Millisecond, wait.String())
+
+	stop := make(chan struct{})
+	close(stop)
+	assert.False(t, l.Wait(1, 100, stop))
+}
+
+func TestOptionsFlags(t *testing.T) {
+	var opts Options
+	flags := pflag.NewFlagSet("pipe", pflag.ContinueOnError)
+	opts.AddFlags(flags)
+
+	require.NoError(t, flags.Parse([]string{"--rate", "60000/m", "--max-bytes-per-sec", "1048576"}))
+	assert.Equal(t, Rate(1000), opts.Rate)
+	assert.Equal(t, "1000/s", flags.Lookup("rate").Value.String())
+	assert.NotNil(t, opts.Limiter())
+
+	assert.Error(t, flags.Parse([]string{"--rate", "lots"}))
+}
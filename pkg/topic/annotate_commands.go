@@ -0,0 +1,72 @@
+package topic
+
+import (
+	"github.com/lensesio/bite"
+	"github.com/lensesio/lenses-go/pkg/api"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/spf13/cobra"
+)
+
+// topicAnnotationView is the description, the tags and the owners of a topic.
+type topicAnnotationView struct {
+	Name        string   `json:"name" yaml:"name" header:"Name"`
+	Description string   `json:"description" yaml:"description" header:"Description"`
+	Tags        []string `json:"tags" yaml:"tags" header:"Tags"`
+	Owners      []string `json:"owners" yaml:"owners" header:"Owners"`
+}
+
+func newTopicAnnotationView(topic api.Topic) topicAnnotationView {
+	return topicAnnotationView{
+		Name:        topic.TopicName,
+		Description: topic.Description,
+		Tags:        api.TopicTags(topic),
+		Owners:      api.TopicOwners(topic),
+	}
+}
+
+//NewTopicAnnotateCommand creates `topic annotate` command
+func NewTopicAnnotateCommand() *cobra.Command {
+	var (
+		topicName, description string
+		annotation             api.TopicAnnotation
+	)
+
+	cmd := &cobra.Command{
+		Use:   "annotate",
+		Short: "Set the description, the tags and the owners of a topic",
+		Long: `Set the description, the tags and the owners of a topic.
+The tags are added to the existing ones, --remove-tag removes them. The owners are kept as "owner=<name>" tags.`,
+		Example: `topic annotate --name payments --tag team=payments --tag pii --description "Card payments, one record per authorization"
+topic annotate --name payments --owner payments-team --remove-owner alice --remove-tag pii`,
+		Annotations:      map[string]string{config.AnnotationMutating: "true"},
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := bite.CheckRequiredFlags(cmd, bite.FlagPair{"name": topicName}); err != nil {
+				return err
+			}
+
+			if cmd.Flags().Changed("description") {
+				annotation.Description = &description
+			}
+
+			topic, err := config.Client.AnnotateTopic(topicName, annotation)
+			if err != nil {
+				return err
+			}
+
+			return bite.PrintObject(cmd, newTopicAnnotationView(topic))
+		},
+	}
+
+	cmd.Flags().StringVar(&topicName, "name", "", "The topic to annotate")
+	cmd.Flags().StringVar(&description, "description", "", "The description of the topic, an empty one removes it")
+	cmd.Flags().StringArrayVar(&annotation.Tags, "tag", nil, "A tag to add, i.e team=payments, can be repeated")
+	cmd.Flags().StringArrayVar(&annotation.RemoveTags, "remove-tag", nil, "A tag to remove, can be repeated")
+	cmd.Flags().StringArrayVar(&annotation.Owners, "owner", nil, "An owner to add, can be repeated")
+	cmd.Flags().StringArrayVar(&annotation.RemoveOwners, "remove-owner", nil, "An owner to remove, can be repeated")
+
+	bite.CanPrintJSON(cmd)
+
+	return config.RequireFeature(api.FeatureDatasets, cmd)
+}
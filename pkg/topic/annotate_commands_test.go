@@ -0,0 +1,13 @@
+package topic
+
+import (
+	"testing"
+
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopicAnnotateCommandMutating(t *testing.T) {
+	// it writes the topic's metadata, a protected context requires --allow-protected.
+	assert.True(t, config.IsMutating(NewTopicAnnotateCommand()))
+}
@@ -0,0 +1,361 @@
+package topic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/lensesio/bite"
+	"github.com/lensesio/lenses-go/pkg/exitcode"
+	"github.com/lensesio/lenses-go/pkg/transform"
+	"github.com/lensesio/lenses-go/pkg/websocket"
+	"github.com/spf13/cobra"
+)
+
+// The groups of `topic audit-sequence --partition-by`, the rest are the dot separated paths of a field.
+const (
+	sequenceByKey       = "key"
+	sequenceByPartition = "partition"
+	sequenceByNone      = "none"
+)
+
+// maxSequenceGaps is the number of the gaps of a group which are printed, the rest are counted only.
+const maxSequenceGaps = 10
+
+// seqRange is a range of missing sequence numbers, both inclusive.
+type seqRange struct {
+	From, To int64
+}
+
+func (r seqRange) String() string {
+	if r.From == r.To {
+		return strconv.FormatInt(r.From, 10)
+	}
+
+	return fmt.Sprintf("%d-%d", r.From, r.To)
+}
+
+// sequenceGroup is the sequence of the records of a group, i.e of a key.
+type sequenceGroup struct {
+	first, last int64
+	records     int64
+	duplicates  int64
+	// late is the records which filled a gap, they were written out of order.
+	late int64
+	// gaps is the missing ranges, in order.
+	gaps []seqRange
+}
+
+func (g *sequenceGroup) add(seq int64) {
+	g.records++
+	if g.records == 1 {
+		g.first, g.last = seq, seq
+		return
+	}
+
+	switch {
+	case seq == g.last+1:
+		g.last = seq
+	case seq > g.last:
+		g.gaps = append(g.gaps, seqRange{From: g.last + 1, To: seq - 1})
+		g.last = seq
+	case seq < g.first:
+		// before the first one, the numbers between them are missing.
+		if seq < g.first-1 {
+			g.gaps = append([]seqRange{{From: seq + 1, To: g.first - 1}}, g.gaps...)
+		}
+		g.first = seq
+		g.late++
+	default:
+		if !g.fill(seq) {
+			g.duplicates++
+		}
+	}
+}
+
+// fill removes the "seq" from the gaps, it reports whether it was missing.
+func (g *sequenceGroup) fill(seq int64) bool {
+	i := sort.Search(len(g.gaps), func(i int) bool { return g.gaps[i].To >= seq })
+	if i == len(g.gaps) || g.gaps[i].From > seq {
+		return false
+	}
+
+	r := g.gaps[i]
+	switch {
+	case r.From == r.To:
+		g.gaps = append(g.gaps[:i], g.gaps[i+1:]...)
+	case seq == r.From:
+		g.gaps[i].From++
+	case seq == r.To:
+		g.gaps[i].To--
+	default:
+		g.gaps = append(g.gaps[:i+1], g.gaps[i:]...)
+		g.gaps[i].To = seq - 1
+		g.gaps[i+1].From = seq + 1
+	}
+
+	g.late++
+	return true
+}
+
+func (g *sequenceGroup) missing() int64 {
+	var n int64
+	for _, r := range g.gaps {
+		n += r.To - r.From + 1
+	}
+
+	return n
+}
+
+// sequenceView is a row of `topic audit-sequence`.
+type sequenceView struct {
+	Group      string `json:"group" yaml:"group" header:"Group"`
+	First      int64  `json:"first" yaml:"first" header:"First"`
+	Last       int64  `json:"last" yaml:"last" header:"Last"`
+	Records    int64  `json:"records" yaml:"records" header:"Records"`
+	Missing    int64  `json:"missing" yaml:"missing" header:"Missing"`
+	Duplicates int64  `json:"duplicates" yaml:"duplicates" header:"Duplicates"`
+	OutOfOrder int64  `json:"outOfOrder" yaml:"outOfOrder" header:"Out Of Order"`
+	// Gaps are the first `maxSequenceGaps` missing ranges.
+	Gaps string `json:"gaps" yaml:"gaps" header:"Gaps"`
+}
+
+// sequenceAuditor reports the missing and the duplicate sequence numbers of the records per group, it's safe for concurrent use.
+type sequenceAuditor struct {
+	field, by string
+
+	mu     sync.Mutex
+	groups map[string]*sequenceGroup
+	// invalid is the records without an integer sequence number.
+	invalid int64
+}
+
+func newSequenceAuditor(field, by string) *sequenceAuditor {
+	return &sequenceAuditor{field: field, by: by, groups: make(map[string]*sequenceGroup)}
+}
+
+// add adds the record, the ones without an integer sequence field are counted as invalid.
+func (a *sequenceAuditor) add(record websocket.Data) {
+	seq, ok := sequenceNumber(fieldValue(record.Key, record.Value, a.field))
+
+	var group string
+	switch a.by {
+	case sequenceByNone:
+	case sequenceByPartition:
+		group = strconv.Itoa(record.Metadata.Partition)
+	default:
+		v, found := fieldValue(record.Key, record.Value, a.by)
+		if !found {
+			ok = false
+		}
+		group = fieldText(v)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !ok {
+		a.invalid++
+		return
+	}
+
+	g, exists := a.groups[group]
+	if !exists {
+		g = new(sequenceGroup)
+		a.groups[group] = g
+	}
+	g.add(seq)
+}
+
+// report returns the groups, all of them or only the ones with missing, duplicate or out of order records, by their name.
+func (a *sequenceAuditor) report(all bool) (views []sequenceView, invalid int64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for name, g := range a.groups {
+		view := sequenceView{Group: name, First: g.first, Last: g.last, Records: g.records,
+			Missing: g.missing(), Duplicates: g.duplicates, OutOfOrder: g.late}
+		if !all && view.Missing == 0 && view.Duplicates == 0 && view.OutOfOrder == 0 {
+			continue
+		}
+
+		gaps := make([]string, 0, maxSequenceGaps)
+		for i, r := range g.gaps {
+			if i == maxSequenceGaps {
+				gaps = append(gaps, fmt.Sprintf("+%d more", len(g.gaps)-maxSequenceGaps))
+				break
+			}
+			gaps = append(gaps, r.String())
+		}
+		view.Gaps = strings.Join(gaps, ", ")
+		views = append(views, view)
+	}
+
+	sort.Slice(views, func(i, j int) bool {
+		return views[i].Group < views[j].Group
+	})
+
+	return views, a.invalid
+}
+
+// fieldValue returns the value of the "field" of a record, "key" is the whole key, `transform.KeyPrefix` a field of the key
+// and the rest the dot separated path of a field of the value. A key which is not JSON is a string.
+func fieldValue(key, value json.RawMessage, field string) (interface{}, bool) {
+	data, path := value, field
+	switch {
+	case path == sequenceByKey:
+		data, path = key, ""
+	case strings.HasPrefix(path, transform.KeyPrefix):
+		data, path = key, strings.TrimPrefix(path, transform.KeyPrefix)
+	}
+
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if dec.Decode(&v) != nil {
+		// a key of the STRING format.
+		return string(data), path == "" && len(data) > 0
+	}
+
+	if path != "" {
+		for _, name := range strings.Split(path, ".") {
+			fields, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+
+			if v, ok = fields[name]; !ok {
+				return nil, false
+			}
+		}
+	}
+
+	return v, true
+}
+
+// fieldText returns the text of a field value, a string as it is and the rest as their JSON.
+func fieldText(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// sequenceNumber returns the integer of a sequence field, a JSON number or a string of one.
+func sequenceNumber(v interface{}, found bool) (int64, bool) {
+	if !found {
+		return 0, false
+	}
+
+	var s string
+	switch v := v.(type) {
+	case json.Number:
+		s = v.String()
+	case string:
+		s = v
+	default:
+		return 0, false
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	return n, err == nil
+}
+
+//NewTopicAuditSequenceCommand creates `topic audit-sequence` command
+func NewTopicAuditSequenceCommand() *cobra.Command {
+	var (
+		topicName, field, by string
+		live, all            bool
+		timeout, duration    time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "audit-sequence",
+		Short: "Report the missing, duplicate and out of order sequence numbers of the records of a topic per key",
+		Long: `Report the missing, duplicate and out of order sequence numbers of the records of a topic, per key, partition or field,
+to verify the exactly-once delivery of a pipeline. It scans the records of the topic by SQL, or follows its new ones with --live
+until interrupted or for the --duration. The sequence of a group starts at its first record, the gaps are the numbers which were
+not seen and a record which fills a gap is out of order. It prints the groups with issues and fails if there are any.`,
+		Example: `topic audit-sequence --name payments --field seq --partition-by key
+topic audit-sequence --name payments --field meta.seq --partition-by _key.accountId --all
+topic audit-sequence --name payments --field seq --partition-by partition --live --duration 10m`,
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := bite.CheckRequiredFlags(cmd, bite.FlagPair{"name": topicName, "field": field}); err != nil {
+				return err
+			}
+
+			auditor := newSequenceAuditor(field, by)
+			stop, finished := make(chan struct{}), make(chan struct{})
+			defer close(finished)
+
+			interrupt := make(chan os.Signal, 1)
+			signal.Notify(interrupt, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
+			defer signal.Stop(interrupt)
+
+			var deadline <-chan time.Time
+			if live && duration > 0 {
+				deadline = time.After(duration)
+			}
+
+			go func() {
+				select {
+				case <-interrupt:
+				case <-deadline:
+				case <-finished:
+					return
+				}
+				close(stop)
+			}()
+
+			scanTimeout := timeout
+			if live {
+				scanTimeout = 0
+			}
+
+			if err := scan(fmt.Sprintf("SELECT * FROM `%s`", topicName), live, scanTimeout, stop, auditor.add); err != nil {
+				return err
+			}
+
+			views, invalid := auditor.report(all)
+			if invalid > 0 {
+				bite.PrintInfo(cmd, "Skipped [%d] records without an integer [%s] or [%s]", invalid, field, by)
+			}
+
+			if err := bite.PrintObject(cmd, views); err != nil {
+				return err
+			}
+
+			for _, v := range views {
+				if v.Missing > 0 || v.Duplicates > 0 || v.OutOfOrder > 0 {
+					return exitcode.WithCode(exitcode.Validation, fmt.Errorf("topic [%s] has missing, duplicate or out of order records", topicName))
+				}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&topicName, "name", "", "The topic name")
+	cmd.Flags().StringVar(&field, "field", "", "The integer sequence field, a dot separated path of the value or _key.<field> of the key")
+	cmd.Flags().StringVar(&by, "partition-by", sequenceByKey, "The groups of the sequences, key, partition, none or a field, i.e _key.accountId")
+	cmd.Flags().BoolVar(&live, "live", false, "Follow the new records of the topic, until interrupted or for the --duration, instead of scanning it")
+	cmd.Flags().DurationVar(&duration, "duration", 0, "How long to follow the topic with --live, 0 follows it until interrupted")
+	cmd.Flags().BoolVar(&all, "all", false, "Print all the groups, not only the ones with issues")
+	cmd.Flags().DurationVar(&timeout, "scan-timeout", 10*time.Minute, "The maximum duration of the scan of the topic")
+
+	bite.CanPrintJSON(cmd)
+
+	return cmd
+}
@@ -0,0 +1,84 @@
+package topic
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/lensesio/lenses-go/pkg/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSequenceGroup(t *testing.T) {
+	var g sequenceGroup
+	for _, seq := range []int64{5, 6, 7, 10, 11, 11, 15, 8, 13, 3, 6} {
+		g.add(seq)
+	}
+
+	assert.Equal(t, int64(3), g.first)
+	assert.Equal(t, int64(15), g.last)
+	assert.Equal(t, int64(11), g.records)
+	assert.Equal(t, int64(2), g.duplicates)
+	// 8, 13 and 3.
+	assert.Equal(t, int64(3), g.late)
+	assert.Equal(t, []seqRange{{4, 4}, {9, 9}, {12, 12}, {14, 14}}, g.gaps)
+	assert.Equal(t, int64(4), g.missing())
+}
+
+func TestSequenceAuditor(t *testing.T) {
+	record := func(partition int, key, value string) websocket.Data {
+		return websocket.Data{Key: json.RawMessage(key), Value: json.RawMessage(value), Metadata: websocket.MetaData{Partition: partition}}
+	}
+
+	records := []websocket.Data{
+		record(0, `a`, `{"seq":1}`),
+		record(0, `a`, `{"seq":2}`),
+		record(1, `b`, `{"seq":"1"}`),
+		record(1, `b`, `{"seq":4}`),
+		record(0, `a`, `{"seq":2}`),
+		record(0, `a`, `{"seq":3}`),
+		record(1, `c`, `{"seq":1.5}`),
+		record(1, `c`, `{}`),
+	}
+
+	a := newSequenceAuditor("seq", sequenceByKey)
+	for _, r := range records {
+		a.add(r)
+	}
+
+	views, invalid := a.report(false)
+	assert.Equal(t, int64(2), invalid)
+	assert.Equal(t, []sequenceView{
+		{Group: "a", First: 1, Last: 3, Records: 4, Duplicates: 1},
+		{Group: "b", First: 1, Last: 4, Records: 2, Missing: 2, Gaps: "2-3"},
+	}, views)
+
+	a = newSequenceAuditor("seq", sequenceByPartition)
+	for _, r := range records {
+		a.add(r)
+	}
+	views, _ = a.report(true)
+	assert.Len(t, views, 2)
+	assert.Equal(t, "0", views[0].Group)
+	assert.Equal(t, int64(1), views[0].Duplicates)
+	assert.Equal(t, int64(2), views[1].Missing)
+
+	// by a field of the key.
+	a = newSequenceAuditor("seq", "_key.id")
+	a.add(record(0, `{"id":7}`, `{"seq":1}`))
+	a.add(record(0, `{"id":7}`, `{"seq":3}`))
+	a.add(record(0, `{"other":7}`, `{"seq":3}`))
+	views, invalid = a.report(false)
+	assert.Equal(t, int64(1), invalid)
+	assert.Equal(t, []sequenceView{{Group: "7", First: 1, Last: 3, Records: 2, Missing: 1, Gaps: "2"}}, views)
+}
+
+func TestSequenceGaps(t *testing.T) {
+	a := newSequenceAuditor("seq", sequenceByNone)
+	for seq := 0; seq < 40; seq += 3 {
+		a.add(websocket.Data{Value: json.RawMessage(`{"seq":` + strconv.Itoa(seq) + `}`)})
+	}
+
+	views, _ := a.report(false)
+	assert.Equal(t, "1-2, 4-5, 7-8, 10-11, 13-14, 16-17, 19-20, 22-23, 25-26, 28-29, +3 more", views[0].Gaps)
+}
@@ -0,0 +1,161 @@
+package topic
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/websocket"
+)
+
+// defaultBrowseTimeout is the maximum duration of the browse queries the `topic` commands run on their own.
+const defaultBrowseTimeout = time.Minute
+
+// liveConfiguration returns the live connection configuration of the "sql" to the server of the "client",
+// which may not be of the current context.
+func liveConfiguration(client *api.Client, sql string) websocket.LiveConfiguration {
+	live := websocket.LiveConfiguration{
+		Host:     client.Config.Host,
+		Debug:    client.Config.Debug,
+		BasePath: client.Config.BasePath,
+		Message: websocket.Message{
+			Token: client.Config.Token,
+			SQL:   sql,
+		},
+		TokenHeader: client.Config.WebsocketTokenHeader,
+		UseNumber:   true,
+	}
+
+	if client.Config.Insecure {
+		live.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	return live
+}
+
+// browse runs the browse "sql" and returns its records once it ends.
+func browse(sql string, timeout time.Duration) ([]websocket.Data, error) {
+	return browseFrom(config.Client, sql, timeout)
+}
+
+// browseFrom runs the browse "sql" on the server of the "client" and returns its records once it ends.
+func browseFrom(client *api.Client, sql string, timeout time.Duration) ([]websocket.Data, error) {
+	conn, err := websocket.OpenLiveConnection(liveConfiguration(client, sql))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var records []websocket.Data
+	conn.OnRecordMessage(func(resp websocket.LiveResponse) error {
+		data := resp.Data
+		data.Key = append(json.RawMessage(nil), data.Key...)
+		data.Value = append(json.RawMessage(nil), data.Value...)
+		records = append(records, data)
+		return nil
+	})
+
+	done := make(chan error, 1)
+	reporter := func(resp websocket.LiveResponse) error {
+		var errStr string
+		json.Unmarshal(resp.Data.Value, &errStr)
+		select {
+		case done <- fmt.Errorf("[%s]: [%s]", resp.Type, errStr):
+		default:
+		}
+		return nil
+	}
+
+	conn.OnError(reporter)
+	conn.OnInvalidRequest(reporter)
+	conn.OnEnd(func(websocket.LiveResponse) error {
+		select {
+		case done <- nil:
+		default:
+		}
+		return nil
+	})
+
+	select {
+	case err = <-done:
+		return records, err
+	case err = <-conn.Err():
+		return nil, err
+	case <-time.After(timeout):
+		return nil, fmt.Errorf("query did not complete in [%s]", timeout)
+	}
+}
+
+// scan runs the "sql", a live query if "live", and calls "fn" with each of its records, in the reader of the connection,
+// until it ends or "stop" is closed. A query which does not end in the "timeout" fails, zero waits until it's stopped.
+func scan(sql string, live bool, timeout time.Duration, stop <-chan struct{}, fn func(websocket.Data)) error {
+	liveConfig := liveConfiguration(config.Client, sql)
+	liveConfig.Message.Live = live
+
+	conn, err := websocket.OpenLiveConnection(liveConfig)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	conn.OnRecordMessage(func(resp websocket.LiveResponse) error {
+		fn(resp.Data)
+		return nil
+	})
+
+	done := make(chan error, 1)
+	reporter := func(resp websocket.LiveResponse) error {
+		var errStr string
+		json.Unmarshal(resp.Data.Value, &errStr)
+		select {
+		case done <- fmt.Errorf("[%s]: [%s]", resp.Type, errStr):
+		default:
+		}
+		return nil
+	}
+
+	conn.OnError(reporter)
+	conn.OnInvalidRequest(reporter)
+	conn.OnEnd(func(websocket.LiveResponse) error {
+		select {
+		case done <- nil:
+		default:
+		}
+		return nil
+	})
+
+	var deadline <-chan time.Time
+	if timeout > 0 {
+		deadline = time.After(timeout)
+	}
+
+	select {
+	case err = <-done:
+		return err
+	case err = <-conn.Err():
+		return err
+	case <-stop:
+		return nil
+	case <-deadline:
+		return fmt.Errorf("query did not complete in [%s]", timeout)
+	}
+}
+
+// recordTimestamp returns the timestamp of the record, in milliseconds, and false if it has none.
+func recordTimestamp(metadata websocket.MetaData) (int64, bool) {
+	var ms int64
+	switch ts := metadata.Timestamp.(type) {
+	case float64:
+		ms = int64(ts)
+	case json.Number:
+		ms, _ = ts.Int64()
+	case string:
+		ms, _ = strconv.ParseInt(ts, 10, 64)
+	}
+
+	return ms, ms > 0
+}
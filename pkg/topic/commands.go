@@ -2,6 +2,7 @@ package topic
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sort"
 
@@ -9,6 +10,10 @@ import (
 	"github.com/lensesio/bite"
 	"github.com/lensesio/lenses-go/pkg/api"
 	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/manifest"
+	"github.com/lensesio/lenses-go/pkg/printer"
+	"github.com/lensesio/lenses-go/pkg/provider"
+	"github.com/lensesio/lenses-go/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -21,12 +26,16 @@ type topicView struct {
 
 //NewTopicsGroupCommand creates `topics` command
 func NewTopicsGroupCommand() *cobra.Command {
-	var namesOnly, unwrap bool
+	var (
+		namesOnly, unwrap bool
+		tags, owners      []string
+	)
 
 	root := &cobra.Command{
-		Use:           "topics",
-		Short:         "List all available topics",
-		Example:       "topics",
+		Use:   "topics",
+		Short: "List all available topics",
+		Example: `topics
+topics --tag team=payments --owner payments-team`,
 		SilenceErrors: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			client := config.Client
@@ -54,6 +63,10 @@ func NewTopicsGroupCommand() *cobra.Command {
 				return err
 			}
 
+			if len(tags) > 0 || len(owners) > 0 {
+				topics = filterTopicsByTags(topics, tags, owners)
+			}
+
 			sort.Slice(topics, func(i, j int) bool {
 				return topics[i].TopicName < topics[j].TopicName
 			})
@@ -64,7 +77,7 @@ func NewTopicsGroupCommand() *cobra.Command {
 			}
 
 			// return printJSON(cmd, topics)
-			return bite.PrintObject(cmd, topicsView, func(t topicView) bool {
+			return printer.PrintObject(cmd, topicsView, func(t topicView) bool {
 				return !t.IsControlTopic // on JSON we print everything.
 			})
 		},
@@ -72,15 +85,35 @@ func NewTopicsGroupCommand() *cobra.Command {
 
 	root.Flags().BoolVar(&namesOnly, "names", false, "Print topic names only")
 	root.Flags().BoolVar(&unwrap, "unwrap", false, "--unwrap")
+	root.Flags().StringArrayVar(&tags, "tag", nil, "List only the topics with the tag, can be repeated, all of them must match")
+	root.Flags().StringArrayVar(&owners, "owner", nil, "List only the topics of the owner, can be repeated, all of them must match")
 
 	bite.CanPrintJSON(root)
+	printer.CanSelect(root)
 
 	root.AddCommand(NewGetAvailableTopicConfigKeysCommand())
 	root.AddCommand(NewTopicsMetadataSubgroupCommand())
+	root.AddCommand(NewTopicsUsageCommand())
 
 	return root
 }
 
+// filterTopicsByTags returns the "topics" which have all the "tags" and all the "owners".
+func filterTopicsByTags(topics []api.Topic, tags, owners []string) []api.Topic {
+	for _, owner := range owners {
+		tags = append(tags, api.TopicOwnerTagPrefix+owner)
+	}
+
+	filtered := topics[:0]
+	for _, topic := range topics {
+		if api.HasTopicTags(topic, tags...) {
+			filtered = append(filtered, topic)
+		}
+	}
+
+	return filtered
+}
+
 //NewGetAvailableTopicConfigKeysCommand creates `topics keys` command
 func NewGetAvailableTopicConfigKeysCommand() *cobra.Command {
 	var unwrap bool
@@ -277,6 +310,18 @@ func NewTopicGroupCommand() *cobra.Command {
 	root.AddCommand(NewTopicCreateCommand())
 	root.AddCommand(NewTopicDeleteCommand())
 	root.AddCommand(NewTopicUpdateCommand())
+	root.AddCommand(NewTopicUpdateConfigCommand())
+	root.AddCommand(NewTopicExportCommand())
+	root.AddCommand(NewTopicPipeCommand())
+	root.AddCommand(NewTopicAnnotateCommand())
+	root.AddCommand(NewTopicPartitionsCommand())
+	root.AddCommand(NewTopicStatsCommand())
+	root.AddCommand(NewTopicGetCommand())
+	root.AddCommand(NewTopicReplayCommand())
+	root.AddCommand(NewTopicMirrorCommand())
+	root.AddCommand(NewTopicInferSchemaCommand())
+	root.AddCommand(NewTopicProfileCommand())
+	root.AddCommand(NewTopicAuditSequenceCommand())
 
 	return root
 }
@@ -313,12 +358,25 @@ func NewTopicCreateCommand() *cobra.Command {
 				}
 			}
 
+			if config.Ansible() {
+				if _, err := provider.ReadTopic(config.Client, topic.TopicName); err == nil {
+					return config.ReportChange(cmd, false, fmt.Sprintf("Topic [%s] exists", topic.TopicName), nil, nil)
+				} else if !errors.Is(err, provider.ErrNotFound) {
+					return err
+				}
+			}
+
 			if err := config.Client.CreateTopic(topic.TopicName, topic.Replication, topic.Partitions, topic.Configs); err != nil {
 				golog.Errorf("Failed to create topic [%s]. [%s]", topic.TopicName, err.Error())
 				return err
 			}
 
-			return bite.PrintInfo(cmd, "Topic [%s] created", topic.TopicName)
+			after := provider.Topic{Name: topic.TopicName, Partitions: topic.Partitions, Replication: topic.Replication, Configs: map[string]string{}}
+			for k, v := range topic.Configs {
+				after.Configs[k] = fmt.Sprintf("%v", v)
+			}
+
+			return config.ReportChange(cmd, true, fmt.Sprintf("Topic [%s] created", topic.TopicName), nil, after)
 		},
 	}
 
@@ -327,7 +385,7 @@ func NewTopicCreateCommand() *cobra.Command {
 	cmd.Flags().IntVar(&topic.Partitions, "partitions", topic.Partitions, "Number of partitions")
 	cmd.Flags().StringVar(&configsRaw, "configs", "", `Topic configs .e.g. "{\"max.message.bytes\": \"1000010\"}"`)
 	bite.CanBeSilent(cmd)
-	bite.Prepend(cmd, bite.FileBind(&topic))
+	bite.Prepend(cmd, config.ManifestBind(manifest.KindTopic, &topic))
 
 	return cmd
 }
@@ -354,6 +412,14 @@ func NewTopicDeleteCommand() *cobra.Command {
 			}
 
 			if fromPartition >= 0 && toOffset >= 0 {
+				if err := config.Preflight("delete records of topic ["+topicName+"]", config.PermissionDeleteData); err != nil {
+					return err
+				}
+
+				if err := config.Confirm("topic", topicName); err != nil {
+					return err
+				}
+
 				// delete records.
 				if err := client.DeleteTopicRecords(topicName, fromPartition, toOffset); err != nil {
 					golog.Errorf("Failed to delete records topic [%s]. [%s]", topicName, err.Error())
@@ -363,12 +429,32 @@ func NewTopicDeleteCommand() *cobra.Command {
 				return bite.PrintInfo(cmd, "Records from topic [%s] and partition [%d] up to offset [%d], are marked for deletion. This may take a few moments to have effect", topicName, fromPartition, toOffset)
 			}
 
+			if err := config.Preflight("delete topic ["+topicName+"]", config.PermissionDropTopic); err != nil {
+				return err
+			}
+
+			var before interface{}
+			if config.Ansible() {
+				current, err := provider.ReadTopic(client, topicName)
+				if err != nil {
+					if errors.Is(err, provider.ErrNotFound) {
+						return config.ReportChange(cmd, false, fmt.Sprintf("Topic [%s] does not exist", topicName), nil, nil)
+					}
+					return err
+				}
+				before = current
+			}
+
+			if err := config.Confirm("topic", topicName); err != nil {
+				return err
+			}
+
 			if err := client.DeleteTopic(topicName); err != nil {
 				golog.Errorf("Failed to delete topic [%s]. [%s]", topicName, err.Error())
 				return err
 			}
 
-			return bite.PrintInfo(cmd, "Topic [%s] marked for deletion. This may take a few moments to have effect", topicName)
+			return config.ReportChange(cmd, true, fmt.Sprintf("Topic [%s] marked for deletion. This may take a few moments to have effect", topicName), before, nil)
 		},
 	}
 
@@ -432,7 +518,42 @@ func NewTopicUpdateCommand() *cobra.Command {
 	cmd.Flags().StringVar(&configsRaw, "configs", "", `Topic configs .e.g. "{\"key\": \"max.message.bytes\", \"value\": \"1000020\"}"`)
 	cmd.Flags().IntVar(&partitions, "partitions", 0, "Number of partitions (can only be increased)")
 	bite.CanBeSilent(cmd)
-	bite.Prepend(cmd, bite.FileBind(&topic))
+	bite.Prepend(cmd, config.ManifestBind(manifest.KindTopic, &topic))
+
+	return cmd
+}
+
+//NewTopicUpdateConfigCommand creates `topic update-config` command
+func NewTopicUpdateConfigCommand() *cobra.Command {
+	var topicName, patchRaw string
+
+	cmd := &cobra.Command{
+		Use:              "update-config",
+		Short:            "Change only some of the configs of a topic with a JSON merge patch, a null value resets the config to its default",
+		Example:          `topic update-config --name="topic1" --patch='{"retention.ms": "86400000", "cleanup.policy": null}' or topic update-config --name="topic1" --patch=./patch.json`,
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := bite.CheckRequiredFlags(cmd, bite.FlagPair{"name": topicName, "patch": patchRaw}); err != nil {
+				return err
+			}
+
+			patch, err := utils.ParsePatch(patchRaw)
+			if err != nil {
+				return err
+			}
+
+			if _, err = config.Client.PatchTopicConfig(topicName, patch); err != nil {
+				return fmt.Errorf("failed to update topic [%s]. [%s]", topicName, err.Error())
+			}
+
+			return bite.PrintInfo(cmd, "Config updated for topic [%s]", topicName)
+		},
+	}
+
+	cmd.Flags().StringVar(&topicName, "name", "", "Topic to update")
+	cmd.Flags().StringVar(&patchRaw, "patch", "", `The configs to change, a JSON object or a file that contains it, .e.g. "{\"retention.ms\": \"86400000\"}"`)
+	bite.CanBeSilent(cmd)
 
 	return cmd
 }
@@ -0,0 +1,195 @@
+package topic
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/lensesio/bite"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/dedupe"
+	"github.com/lensesio/lenses-go/pkg/objectstore"
+	"github.com/lensesio/lenses-go/pkg/sink"
+	"github.com/lensesio/lenses-go/pkg/transform"
+	"github.com/lensesio/lenses-go/pkg/websocket"
+	"github.com/spf13/cobra"
+)
+
+// exportQuery returns the browse query of the records of the "topic" which are not exported yet.
+func exportQuery(topic string, checkpoint objectstore.Checkpoint) string {
+	sql := fmt.Sprintf("SELECT * FROM `%s`", topic)
+	if filter := checkpoint.Filter(); filter != "" {
+		sql += " WHERE " + filter
+	}
+
+	return sql
+}
+
+// exportTopic browses the records of the "topic" which are not exported yet and adds them to the "exporter",
+// anonymized by the "transformer" if not nil, except the ones of the "duplicates" if not nil.
+func exportTopic(topic string, exporter *objectstore.Exporter, transformer *transform.Transformer, duplicates *dedupe.Filter) error {
+	currentConfig := config.Manager.Config.GetCurrent()
+	conn, err := websocket.OpenLiveConnection(websocket.LiveConfiguration{
+		Host:  currentConfig.Host,
+		Debug: currentConfig.Debug,
+		Message: websocket.Message{
+			Token: config.Client.Config.Token,
+			SQL:   exportQuery(topic, exporter.Checkpoint()),
+		},
+		UseNumber: true,
+	})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// the listeners run in order, so the records are added and flushed by one goroutine.
+	done := make(chan error, 1)
+	finish := func(err error) {
+		select {
+		case done <- err:
+		default:
+		}
+	}
+
+	failed := false
+	conn.OnRecordMessage(func(resp websocket.LiveResponse) error {
+		if failed {
+			return nil
+		}
+
+		record := sink.NewRecord(resp.Data)
+		if duplicates != nil && duplicates.Duplicate(record) {
+			return nil
+		}
+
+		if transformer != nil {
+			key, value, err := transformer.Apply(record.Key, record.Value)
+			if err != nil {
+				failed = true
+				finish(err)
+				return nil
+			}
+			record.Key, record.Value = key, value
+		}
+
+		if err := exporter.Add(record); err != nil {
+			failed = true
+			finish(err)
+		}
+		return nil
+	})
+
+	reporter := func(resp websocket.LiveResponse) error {
+		var errStr string
+		json.Unmarshal(resp.Data.Value, &errStr)
+		finish(fmt.Errorf("[%s]: [%s]", resp.Type, errStr))
+		return nil
+	}
+	conn.OnError(reporter)
+	conn.OnInvalidRequest(reporter)
+	conn.OnEnd(func(websocket.LiveResponse) error {
+		if failed {
+			return nil
+		}
+		finish(exporter.Flush())
+		return nil
+	})
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+
+	select {
+	case err = <-done:
+		return err
+	case err = <-conn.Err():
+		return err
+	case <-interrupt:
+		conn.Close()
+		// keep what is buffered, the next run resumes after it.
+		return exporter.Flush()
+	}
+}
+
+//NewTopicExportCommand creates `topic export` command
+func NewTopicExportCommand() *cobra.Command {
+	var (
+		topicName, out, format, partitionBy string
+		flushSize                           int
+		transformOpts                       transform.Options
+		dedupeOpts                          dedupe.Options
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the records of a topic to a local directory, Amazon S3 or Google Cloud Storage",
+		Long: `Export the records of a topic to a local directory, Amazon S3 or Google Cloud Storage.
+The records are written to objects of up to --flush-size MB, along with a _checkpoint.json of the exported offsets,
+so an interrupted or failed export resumes after the last uploaded records when it runs again with the same --out.
+The S3 credentials are read from the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN and AWS_REGION environment variables,
+AWS_ENDPOINT_URL sets an S3 compatible service. The Google Cloud Storage ones from GOOGLE_OAUTH_ACCESS_TOKEN,
+i.e $(gcloud auth print-access-token), or from the GCS_HMAC_ACCESS_KEY_ID and GCS_HMAC_SECRET HMAC keys.`,
+		Example: `topic export --name payments --out s3://bucket/exports/payments --format json --partition-by date
+topic export --name payments --out gs://bucket/payments --format csv --partition-by partition
+topic export --name payments --out s3://bucket/exports/payments --format parquet --partition-by date
+topic export --name customers --out ./customers --redact email --hash _key.id --drop-field address
+topic export --name customers --out ./customers --dedupe-by key --window 10m`,
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := bite.CheckRequiredFlags(cmd, bite.FlagPair{"name": topicName, "out": out}); err != nil {
+				return err
+			}
+
+			transformer, err := transformOpts.Transformer()
+			if err != nil {
+				return err
+			}
+
+			duplicates, err := dedupeOpts.Filter()
+			if err != nil {
+				return err
+			}
+
+			store, err := objectstore.Open(out)
+			if err != nil {
+				return err
+			}
+
+			exporter, err := objectstore.NewExporter(store, format, partitionBy)
+			if err != nil {
+				return err
+			}
+			exporter.FlushSize = flushSize << 20
+
+			resumed := exporter.Checkpoint()
+			if err = exportTopic(topicName, exporter, transformer, duplicates); err != nil {
+				return err
+			}
+
+			done := exporter.Checkpoint()
+			if duplicates != nil {
+				return bite.PrintInfo(cmd, "Exported [%d] records of [%s] to [%d] objects of [%s], skipped [%d] duplicates",
+					done.Records-resumed.Records, topicName, done.Objects-resumed.Objects, out, duplicates.Skipped())
+			}
+
+			return bite.PrintInfo(cmd, "Exported [%d] records of [%s] to [%d] objects of [%s]",
+				done.Records-resumed.Records, topicName, done.Objects-resumed.Objects, out)
+		},
+	}
+
+	cmd.Flags().StringVar(&topicName, "name", "", "The topic to export")
+	cmd.Flags().StringVar(&out, "out", "", "The destination, s3://bucket/prefix, gs://bucket/prefix or a local directory")
+	cmd.Flags().StringVar(&format, "format", objectstore.FormatJSON, "The format of the objects, json, csv or parquet")
+	cmd.Flags().StringVar(&partitionBy, "partition-by", "", "Partition the objects by the records' date or partition")
+	cmd.Flags().IntVar(&flushSize, "flush-size", objectstore.DefaultFlushSize>>20, "The MB of records to buffer before uploading them")
+	transformOpts.AddFlags(cmd.Flags())
+	dedupeOpts.AddFlags(cmd.Flags())
+
+	bite.CanBeSilent(cmd)
+
+	return cmd
+}
@@ -0,0 +1,116 @@
+package topic
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lensesio/bite"
+	"github.com/lensesio/lenses-go/pkg/exitcode"
+	"github.com/lensesio/lenses-go/pkg/sink"
+	"github.com/lensesio/lenses-go/pkg/websocket"
+	"github.com/spf13/cobra"
+)
+
+// keyLookup is the key of a `topic get`.
+type keyLookup struct {
+	Topic string
+	Key   string
+	// Field compares a field of a structured key, i.e an Avro record, instead of the whole key.
+	Field string
+	// Numeric compares the key as a number, for the INT and LONG key formats.
+	Numeric bool
+}
+
+// query returns the browse query of the records of the key.
+func (k keyLookup) query() string {
+	column := "_key"
+	if k.Field != "" {
+		column += "." + k.Field
+	}
+
+	value := "'" + strings.Replace(k.Key, "'", "''", -1) + "'"
+	if k.Numeric {
+		value = k.Key
+	}
+
+	return fmt.Sprintf("SELECT * FROM `%s` WHERE %s = %s", k.Topic, column, value)
+}
+
+// keyVersions returns the "records" of a key ordered by offset, the latest last.
+// A key is always written to the same partition, so the offsets order its versions.
+func keyVersions(records []websocket.Data) []sink.Record {
+	versions := make([]sink.Record, len(records))
+	for i, r := range records {
+		versions[i] = sink.NewRecord(r)
+	}
+
+	sort.SliceStable(versions, func(i, j int) bool {
+		a, b := versions[i].Metadata, versions[j].Metadata
+		if a.Partition != b.Partition {
+			return a.Partition < b.Partition
+		}
+		return a.Offset < b.Offset
+	})
+
+	return versions
+}
+
+//NewTopicGetCommand creates `topic get` command
+func NewTopicGetCommand() *cobra.Command {
+	var (
+		lookup      keyLookup
+		allVersions bool
+		timeout     time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "get",
+		Short: "Print the latest record of a key of a topic",
+		Long: `Print the latest record of a key of a topic, with its metadata and headers, or all the records of the key with --all-versions,
+i.e the history of a key of a compacted topic. The key is compared as a string, --numeric for the INT and LONG key formats,
+--key-field compares a field of a structured key instead. The records are browsed by SQL, which reads the whole topic.`,
+		Example: `topic get --name customers --key 'customer-42'
+topic get --name orders --key 1001 --numeric --all-versions
+topic get --name payments --key-field id --key 7f1c`,
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := bite.CheckRequiredFlags(cmd, bite.FlagPair{"name": lookup.Topic, "key": lookup.Key}); err != nil {
+				return err
+			}
+
+			records, err := browse(lookup.query(), timeout)
+			if err != nil {
+				return err
+			}
+
+			versions := keyVersions(records)
+			if len(versions) == 0 {
+				return exitcode.WithCode(exitcode.NotFound, fmt.Errorf("key [%s] was not found in topic [%s]", lookup.Key, lookup.Topic))
+			}
+
+			if !allVersions {
+				versions = versions[len(versions)-1:]
+			}
+
+			for _, r := range versions {
+				if err = bite.PrintJSON(cmd, r); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&lookup.Topic, "name", "", "The topic name")
+	cmd.Flags().StringVar(&lookup.Key, "key", "", "The key of the records")
+	cmd.Flags().StringVar(&lookup.Field, "key-field", "", "The field of a structured key to compare, a dotted path for the nested ones")
+	cmd.Flags().BoolVar(&lookup.Numeric, "numeric", false, "Compare the key as a number")
+	cmd.Flags().BoolVar(&allVersions, "all-versions", false, "Print all the records of the key, the oldest first")
+	cmd.Flags().DurationVar(&timeout, "browse-timeout", defaultBrowseTimeout, "The maximum duration of the browse query")
+
+	return cmd
+}
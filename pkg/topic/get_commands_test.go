@@ -0,0 +1,30 @@
+package topic
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/lensesio/lenses-go/pkg/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyLookupQuery(t *testing.T) {
+	assert.Equal(t, "SELECT * FROM `customers` WHERE _key = 'o''neil'", keyLookup{Topic: "customers", Key: "o'neil"}.query())
+	assert.Equal(t, "SELECT * FROM `orders` WHERE _key = 1001", keyLookup{Topic: "orders", Key: "1001", Numeric: true}.query())
+	assert.Equal(t, "SELECT * FROM `payments` WHERE _key.id = '7f1c'", keyLookup{Topic: "payments", Key: "7f1c", Field: "id"}.query())
+}
+
+func TestKeyVersions(t *testing.T) {
+	record := func(offset int, value string) websocket.Data {
+		return websocket.Data{Key: json.RawMessage(`"k"`), Value: json.RawMessage(value), Metadata: websocket.MetaData{Partition: 1, Offset: offset}}
+	}
+
+	versions := keyVersions([]websocket.Data{record(12, `3`), record(3, `1`), record(7, `null`)})
+	if assert.Len(t, versions, 3) {
+		assert.Equal(t, 3, versions[0].Metadata.Offset)
+		assert.Equal(t, 7, versions[1].Metadata.Offset)
+		assert.Equal(t, `3`, string(versions[2].Value), "the latest last")
+	}
+
+	assert.Empty(t, keyVersions(nil))
+}
@@ -0,0 +1,160 @@
+package topic
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lensesio/bite"
+	"github.com/lensesio/lenses-go/pkg/api"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/schemagen"
+	"github.com/lensesio/lenses-go/pkg/websocket"
+	"github.com/spf13/cobra"
+)
+
+// The schema formats of `topic infer-schema`.
+const (
+	schemaFormatAvro       = "avro"
+	schemaFormatJSONSchema = "json-schema"
+)
+
+// defaultInferSample is the default number of the records `topic infer-schema` samples.
+const defaultInferSample = 10000
+
+// inferSchema adds the values, or the keys if "key", of the "records" to a schema `Inferrer`,
+// it returns the number of the ones which are not JSON, which are skipped.
+func inferSchema(records []websocket.Data, key bool) (*schemagen.Inferrer, int) {
+	var (
+		in      = schemagen.NewInferrer()
+		skipped int
+	)
+
+	for _, r := range records {
+		data := r.Value
+		if key {
+			data = r.Key
+		}
+
+		if len(data) == 0 || in.Add(data) != nil {
+			skipped++
+		}
+	}
+
+	return in, skipped
+}
+
+//NewTopicInferSchemaCommand creates `topic infer-schema` command
+func NewTopicInferSchemaCommand() *cobra.Command {
+	var (
+		topicName, format, subject string
+		sample                     int
+		key, report                bool
+		timeout                    time.Duration
+		opts                       schemagen.Options
+	)
+
+	cmd := &cobra.Command{
+		Use:   "infer-schema",
+		Short: "Infer the Avro or JSON Schema of the JSON records of a topic by a sample of them",
+		Long: `Infer the Avro or JSON Schema of the JSON values, or keys, of a topic by a sample of its records, which are browsed by SQL.
+A field which is missing from or null in some of the records is nullable, the integers are longs unless a decimal number was seen
+and a field of more types is a union of them. The --report prints the fields with their types, how often they are present
+and the estimated number of their distinct values instead. The --subject registers the Avro schema to the Schema Registry.`,
+		Example: `topic infer-schema --name payments --sample 10000
+topic infer-schema --name payments --format json-schema
+topic infer-schema --name payments --report
+topic infer-schema --name payments --namespace io.lenses.payments --subject payments-value`,
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := bite.CheckRequiredFlags(cmd, bite.FlagPair{"name": topicName}); err != nil {
+				return err
+			}
+
+			if format != schemaFormatAvro && format != schemaFormatJSONSchema {
+				return fmt.Errorf("unknown schema format [%s], expected avro or json-schema", format)
+			}
+
+			if subject != "" && format != schemaFormatAvro {
+				return fmt.Errorf("only the avro schemas can be registered, --subject requires --format avro")
+			}
+
+			if sample <= 0 {
+				return fmt.Errorf("invalid sample [%d], expected a positive number of records", sample)
+			}
+
+			// a registration is checked and recorded like the one of a mutating command.
+			if subject != "" && !report {
+				cmd.Annotations = map[string]string{config.AnnotationMutating: "true"}
+				if err := config.CheckProtected(cmd); err != nil {
+					return err
+				}
+			}
+
+			records, err := browse(fmt.Sprintf("SELECT * FROM `%s` LIMIT %d", topicName, sample), timeout)
+			if err != nil {
+				return err
+			}
+
+			in, skipped := inferSchema(records, key)
+			if in.Samples() == 0 {
+				return fmt.Errorf("topic [%s] has no JSON records to infer the schema of, [%d] were not JSON", topicName, skipped)
+			}
+
+			if skipped > 0 {
+				bite.PrintInfo(cmd, "Skipped [%d] of the [%d] sampled records which are not JSON", skipped, len(records))
+			}
+
+			if report {
+				return bite.PrintObject(cmd, in.Fields())
+			}
+
+			if format == schemaFormatJSONSchema {
+				schema, err := in.JSONSchema()
+				if err != nil {
+					return err
+				}
+				return bite.PrintJSON(cmd, schema)
+			}
+
+			if opts.Name == "" {
+				opts.Name = topicName
+			}
+
+			schema, err := in.Avro(opts)
+			if err != nil {
+				return err
+			}
+
+			if subject == "" {
+				return bite.PrintJSON(cmd, schema)
+			}
+
+			s, err := schemagen.String(schema)
+			if err != nil {
+				return err
+			}
+
+			if err = config.Client.WriteSchema(subject, api.WriteSchemaReq{Format: "AVRO", Schema: s}); err != nil {
+				return fmt.Errorf("unable to register the schema to subject [%s]: [%v]", subject, err)
+			}
+
+			return bite.PrintInfo(cmd, "Schema inferred from [%d] records of [%s] registered to subject [%s]", in.Samples(), topicName, subject)
+		},
+	}
+
+	cmd.Flags().StringVar(&topicName, "name", "", "The topic name")
+	cmd.Flags().IntVar(&sample, "sample", defaultInferSample, "The number of the records to infer the schema by")
+	cmd.Flags().BoolVar(&key, "key", false, "Infer the schema of the keys instead of the values")
+	cmd.Flags().StringVar(&format, "format", schemaFormatAvro, "The format of the schema, avro or json-schema")
+	cmd.Flags().StringVar(&opts.Namespace, "namespace", "", "The namespace of the Avro record")
+	cmd.Flags().StringVar(&opts.Name, "record-name", "", "The name of the Avro record, the topic name if empty")
+	cmd.Flags().StringVar(&subject, "subject", "", "Register the Avro schema to the subject instead of printing it, i.e payments-value")
+	cmd.Flags().BoolVar(&report, "report", false, "Print the fields with their types, presence and estimated distinct values instead of the schema")
+	cmd.Flags().DurationVar(&timeout, "browse-timeout", defaultBrowseTimeout, "The maximum duration of the browse query")
+
+	bite.CanPrintJSON(cmd)
+	bite.CanBeSilent(cmd)
+
+	return cmd
+}
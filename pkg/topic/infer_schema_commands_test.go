@@ -0,0 +1,59 @@
+package topic
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/schemagen"
+	"github.com/lensesio/lenses-go/pkg/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInferSchema(t *testing.T) {
+	records := []websocket.Data{
+		{Key: json.RawMessage(`{"id":1}`), Value: json.RawMessage(`{"amount":10}`)},
+		{Key: json.RawMessage(`{"id":2}`), Value: json.RawMessage(`{"amount":12.5,"currency":"EUR"}`)},
+		{Key: json.RawMessage(`{"id":3}`), Value: json.RawMessage(`not json`)},
+		{Key: json.RawMessage(`{"id":4}`)},
+	}
+
+	in, skipped := inferSchema(records, false)
+	assert.Equal(t, 2, skipped)
+	assert.Equal(t, int64(2), in.Samples())
+
+	schema, err := in.Avro(schemagen.Options{Name: "payments"})
+	require.NoError(t, err)
+	s, err := schemagen.String(schema)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"record","name":"payments","fields":[
+		{"name":"amount","type":"double"},
+		{"name":"currency","type":["null","string"],"default":null}
+	]}`, s)
+
+	in, skipped = inferSchema(records, true)
+	assert.Zero(t, skipped)
+	assert.Equal(t, []schemagen.InferredField{{Path: "id", Type: "integer", Present: 100, Distinct: 4}}, in.Fields())
+}
+
+func TestTopicInferSchemaCommandProtected(t *testing.T) {
+	defaultManager := config.Manager
+	defer func() { config.Manager = defaultManager }()
+
+	config.Manager = config.NewEmptyConfigManager()
+	config.Manager.Config.CurrentContext = "prod"
+	config.Manager.Config.Contexts["prod"] = &api.ClientConfig{Host: "http://lenses:9991", Protected: true}
+
+	// the registration to a subject is refused before the topic is browsed.
+	cmd := NewTopicInferSchemaCommand()
+	cmd.SetArgs([]string{"--name", "payments", "--subject", "payments-value"})
+	err := cmd.Execute()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "context [prod] is protected")
+	}
+	assert.True(t, config.IsMutating(cmd))
+
+	assert.False(t, config.IsMutating(NewTopicInferSchemaCommand()))
+}
@@ -0,0 +1,233 @@
+package topic
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/lensesio/bite"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/objectstore"
+	"github.com/lensesio/lenses-go/pkg/sink"
+	"github.com/lensesio/lenses-go/pkg/throttle"
+	"github.com/lensesio/lenses-go/pkg/websocket"
+	"github.com/spf13/cobra"
+)
+
+// defaultMirrorBatch is the number of records of each batch of a `topic mirror`.
+const defaultMirrorBatch = 500
+
+// mirrorSummary is the result of a `topic mirror`.
+type mirrorSummary struct {
+	Source   string  `json:"source" header:"Source"`
+	Target   string  `json:"target" header:"Target"`
+	Mirrored int64   `json:"mirrored" header:"Mirrored"`
+	Batches  int     `json:"batches" header:"Batches"`
+	Duration string  `json:"duration" header:"Duration"`
+	Rate     float64 `json:"rate" header:"Records/sec"`
+	// Completed is false if the mirror was interrupted, it resumes from its checkpoint on the next run.
+	Completed bool `json:"completed" header:"Completed"`
+}
+
+// mirrorOptions are the `topic mirror` command's flags.
+type mirrorOptions struct {
+	SourceContext, TargetContext string
+	Topic, TargetTopic           string
+	Batch                        int
+	Reset                        bool
+	Throttle                     throttle.Options
+	sink.Options
+}
+
+// mirrorQuery returns the browse query of the next "batch" records of the "topic" after the "offsets".
+func mirrorQuery(topic string, offsets map[int]int, batch int) string {
+	sql := fmt.Sprintf("SELECT * FROM `%s`", topic)
+	if filter := (objectstore.Checkpoint{Offsets: offsets}).Filter(); filter != "" {
+		sql += " WHERE " + filter
+	}
+
+	return fmt.Sprintf("%s LIMIT %d", sql, batch)
+}
+
+// mirror reads batches of records of the source topic after the "offsets" by "browse", writes them by "write"
+// and saves the new offsets by "save" after each written batch, until the source has no more records or the "stop" is closed.
+func mirror(opts mirrorOptions, offsets map[int]int, browse func(sql string) ([]websocket.Data, error),
+	write func([]sink.Record) error, save func(map[int]int) error, stop <-chan struct{}) (mirrorSummary, error) {
+	summary := mirrorSummary{
+		Source: opts.SourceContext + "/" + opts.Topic,
+		Target: opts.TargetContext + "/" + opts.TargetTopic,
+	}
+	start := time.Now()
+	defer func() {
+		elapsed := time.Since(start)
+		summary.Duration = elapsed.Round(time.Millisecond).String()
+		if seconds := elapsed.Seconds(); seconds > 0 {
+			summary.Rate = float64(int64(float64(summary.Mirrored)/seconds*100)) / 100
+		}
+	}()
+
+	if offsets == nil {
+		offsets = make(map[int]int)
+	}
+
+	for {
+		select {
+		case <-stop:
+			return summary, nil
+		default:
+		}
+
+		data, err := browse(mirrorQuery(opts.Topic, offsets, opts.Batch))
+		if err != nil {
+			return summary, err
+		}
+
+		if len(data) == 0 {
+			summary.Completed = true
+			return summary, nil
+		}
+
+		records := make([]sink.Record, len(data))
+		for i, d := range data {
+			records[i] = sink.NewRecord(d)
+		}
+
+		if err = write(records); err != nil {
+			return summary, err
+		}
+
+		for _, r := range records {
+			if offset, ok := offsets[r.Metadata.Partition]; !ok || r.Metadata.Offset > offset {
+				offsets[r.Metadata.Partition] = r.Metadata.Offset
+			}
+		}
+
+		if err = save(offsets); err != nil {
+			return summary, err
+		}
+
+		summary.Mirrored += int64(len(records))
+		summary.Batches++
+	}
+}
+
+//NewTopicMirrorCommand creates `topic mirror` command
+func NewTopicMirrorCommand() *cobra.Command {
+	var (
+		opts         = mirrorOptions{Batch: defaultMirrorBatch, Options: sink.Options{MaxRetries: 3}}
+		batchTimeout time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "mirror",
+		Short: "Copy the records of a topic to a topic of another context, i.e for a disaster recovery backfill",
+		Long: `Copy the records of a topic of a context of the configuration file to a topic of another context, i.e for a disaster recovery backfill.
+The records are read and produced through Lenses SQL in batches of --batch records, at most --rate records and --max-bytes-per-sec bytes per second,
+the target topic's key and value formats should be STRING or JSON. The offsets of the last copied records are saved locally
+after each batch, so an interrupted or failed mirror resumes after them when it runs again, unless --reset.`,
+		Example: `topic mirror --source-context prod --target-context dr --name payments
+topic mirror --source-context prod --target-context dr --name payments --target-name payments-backfill --rate 1000/s`,
+		// it produces to the target context, which is checked by `config.CheckProtectedContext` too.
+		Annotations:      map[string]string{config.AnnotationMutating: "true"},
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := bite.CheckRequiredFlags(cmd, bite.FlagPair{"source-context": opts.SourceContext,
+				"target-context": opts.TargetContext, "name": opts.Topic}); err != nil {
+				return err
+			}
+
+			if opts.TargetTopic == "" {
+				opts.TargetTopic = opts.Topic
+			}
+
+			if opts.SourceContext == opts.TargetContext && opts.Topic == opts.TargetTopic {
+				return fmt.Errorf("the source and the target are the same topic [%s] of context [%s]", opts.Topic, opts.SourceContext)
+			}
+
+			if err := config.CheckProtectedContext(opts.TargetContext, cmd); err != nil {
+				return err
+			}
+
+			if opts.Batch <= 0 {
+				opts.Batch = defaultMirrorBatch
+			}
+			// a statement per batch.
+			opts.BatchSize = opts.Batch
+			// shared by the batchers of all the batches.
+			opts.Limiter = opts.Throttle.Limiter()
+
+			source, err := config.Manager.ContextClient(opts.SourceContext)
+			if err != nil {
+				return err
+			}
+
+			target, err := config.Manager.ContextClient(opts.TargetContext)
+			if err != nil {
+				return err
+			}
+
+			key := config.MirrorKey(opts.SourceContext, opts.Topic, opts.TargetContext, opts.TargetTopic)
+			var offsets map[int]int
+			if !opts.Reset {
+				if offsets, err = config.MirrorOffsets(key); err != nil {
+					return err
+				}
+			}
+
+			kafka := &sink.KafkaSink{Topic: opts.TargetTopic, Config: liveConfiguration(target, "")}
+			write := func(records []sink.Record) error {
+				batcher := sink.NewBatcher(kafka, opts.Options)
+				for _, r := range records {
+					if err := batcher.Add(r); err != nil {
+						batcher.Close()
+						return err
+					}
+				}
+
+				err := batcher.Close()
+				if _, dropped := batcher.Stats(); dropped > 0 && err == nil {
+					err = fmt.Errorf("[%d] records failed to be produced to [%s]", dropped, opts.TargetTopic)
+				}
+				return err
+			}
+
+			stop := make(chan struct{})
+			interrupt := make(chan os.Signal, 1)
+			signal.Notify(interrupt, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
+			defer signal.Stop(interrupt)
+			go func() {
+				if _, ok := <-interrupt; ok {
+					close(stop)
+				}
+			}()
+
+			summary, err := mirror(opts, offsets, func(sql string) ([]websocket.Data, error) {
+				return browseFrom(source, sql, batchTimeout)
+			}, write, func(offsets map[int]int) error {
+				return config.SaveMirrorOffsets(key, offsets)
+			}, stop)
+			if err != nil {
+				return err
+			}
+
+			return bite.PrintObject(cmd, summary)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.SourceContext, "source-context", "", "The context of the configuration file to copy the records from")
+	cmd.Flags().StringVar(&opts.TargetContext, "target-context", "", "The context of the configuration file to copy the records to")
+	cmd.Flags().StringVar(&opts.Topic, "name", "", "The topic to copy the records of")
+	cmd.Flags().StringVar(&opts.TargetTopic, "target-name", "", "The topic to produce the records to, defaults to the --name")
+	cmd.Flags().IntVar(&opts.Batch, "batch", opts.Batch, "The records of each batch, read and produced at once")
+	cmd.Flags().BoolVar(&opts.Reset, "reset", false, "Copy from the beginning of the topic instead of the saved checkpoint")
+	cmd.Flags().IntVar(&opts.MaxRetries, "retries", opts.MaxRetries, "The retries of a batch which failed to be produced")
+	opts.Throttle.AddFlags(cmd.Flags())
+	cmd.Flags().DurationVar(&batchTimeout, "batch-timeout", defaultBrowseTimeout, "The maximum duration of the browse query of each batch")
+
+	bite.CanPrintJSON(cmd)
+
+	return cmd
+}
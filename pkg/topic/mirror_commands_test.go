@@ -0,0 +1,86 @@
+package topic
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/lensesio/lenses-go/pkg/sink"
+	"github.com/lensesio/lenses-go/pkg/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMirrorQuery(t *testing.T) {
+	assert.Equal(t, "SELECT * FROM `payments` LIMIT 500", mirrorQuery("payments", nil, 500))
+	assert.Equal(t, "SELECT * FROM `payments` WHERE (_meta.partition = 0 AND _meta.offset > 9) OR _meta.partition NOT IN (0) LIMIT 2",
+		mirrorQuery("payments", map[int]int{0: 9}, 2))
+}
+
+func TestMirror(t *testing.T) {
+	record := func(partition, offset int) websocket.Data {
+		return websocket.Data{Value: json.RawMessage(`1`), Metadata: websocket.MetaData{Partition: partition, Offset: offset}}
+	}
+
+	batches := [][]websocket.Data{
+		{record(0, 10), record(1, 3), record(0, 11)},
+		{record(1, 4)},
+		nil,
+	}
+
+	var (
+		queries []string
+		written []sink.Record
+		saved   []map[int]int
+	)
+
+	browse := func(sql string) ([]websocket.Data, error) {
+		queries = append(queries, sql)
+		batch := batches[0]
+		batches = batches[1:]
+		return batch, nil
+	}
+	write := func(records []sink.Record) error {
+		written = append(written, records...)
+		return nil
+	}
+	save := func(offsets map[int]int) error {
+		clone := make(map[int]int)
+		for p, o := range offsets {
+			clone[p] = o
+		}
+		saved = append(saved, clone)
+		return nil
+	}
+
+	opts := mirrorOptions{SourceContext: "prod", TargetContext: "dr", Topic: "payments", TargetTopic: "payments", Batch: 3}
+	summary, err := mirror(opts, map[int]int{0: 9}, browse, write, save, make(chan struct{}))
+	assert.NoError(t, err)
+	assert.True(t, summary.Completed)
+	assert.Equal(t, "prod/payments", summary.Source)
+	assert.Equal(t, "dr/payments", summary.Target)
+	assert.Equal(t, int64(4), summary.Mirrored)
+	assert.Equal(t, 2, summary.Batches)
+	assert.Len(t, written, 4)
+	assert.Equal(t, []map[int]int{{0: 11, 1: 3}, {0: 11, 1: 4}}, saved)
+	assert.Equal(t, []string{
+		"SELECT * FROM `payments` WHERE (_meta.partition = 0 AND _meta.offset > 9) OR _meta.partition NOT IN (0) LIMIT 3",
+		"SELECT * FROM `payments` WHERE (_meta.partition = 0 AND _meta.offset > 11) OR (_meta.partition = 1 AND _meta.offset > 3) OR _meta.partition NOT IN (0, 1) LIMIT 3",
+		"SELECT * FROM `payments` WHERE (_meta.partition = 0 AND _meta.offset > 11) OR (_meta.partition = 1 AND _meta.offset > 4) OR _meta.partition NOT IN (0, 1) LIMIT 3",
+	}, queries)
+
+	// a failed batch is not checkpointed.
+	batches = [][]websocket.Data{{record(0, 12)}}
+	saved = nil
+	_, err = mirror(opts, map[int]int{0: 11}, browse, func([]sink.Record) error {
+		return errors.New("insert failed")
+	}, save, make(chan struct{}))
+	assert.EqualError(t, err, "insert failed")
+	assert.Empty(t, saved)
+
+	// stopped.
+	stop := make(chan struct{})
+	close(stop)
+	summary, err = mirror(opts, nil, browse, write, save, stop)
+	assert.NoError(t, err)
+	assert.False(t, summary.Completed)
+}
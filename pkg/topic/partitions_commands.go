@@ -0,0 +1,92 @@
+package topic
+
+import (
+	"strconv"
+
+	"github.com/lensesio/bite"
+	"github.com/lensesio/lenses-go/pkg/api"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/spf13/cobra"
+)
+
+// partitionView is a row of `topic partitions`.
+type partitionView struct {
+	Partition      int    `json:"partition" yaml:"partition" header:"Partition"`
+	Leader         string `json:"leader" yaml:"leader" header:"Leader"`
+	Replicas       []int  `json:"replicas" yaml:"replicas" header:"Replicas"`
+	InSyncReplicas []int  `json:"isr" yaml:"isr" header:"ISR"`
+	Begin          int64  `json:"begin" yaml:"begin" header:"Begin"`
+	End            int64  `json:"end" yaml:"end" header:"End"`
+	Messages       int64  `json:"messages" yaml:"messages" header:"Messages"`
+	Bytes          int64  `json:"bytes" yaml:"bytes" header:"Size (bytes)"`
+	// UnderReplicated is false if the replicas are unknown.
+	UnderReplicated bool `json:"underReplicated" yaml:"underReplicated" header:"Under Replicated"`
+}
+
+func newPartitionView(p api.TopicPartition) partitionView {
+	leader := strconv.Itoa(p.Leader)
+	switch p.Leader {
+	case api.LeaderNone:
+		leader = "none"
+	case api.LeaderUnknown:
+		leader = "unknown"
+	}
+
+	return partitionView{
+		Partition:       p.Partition,
+		Leader:          leader,
+		Replicas:        p.Replicas,
+		InSyncReplicas:  p.InSyncReplicas,
+		Begin:           p.Begin,
+		End:             p.End,
+		Messages:        p.Messages,
+		Bytes:           p.Bytes,
+		UnderReplicated: p.UnderReplicated(),
+	}
+}
+
+//NewTopicPartitionsCommand creates `topic partitions` command
+func NewTopicPartitionsCommand() *cobra.Command {
+	var (
+		topicName           string
+		underReplicatedOnly bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "partitions",
+		Short: "Print the leader, the replicas, the offsets and the size of each partition of a topic",
+		Long: `Print the leader, the replicas, the in-sync replicas, the begin and end offsets, the messages and the size on disk of each partition of a topic.
+The servers which do not report the replicas print only the offsets and the messages.`,
+		Example: `topic partitions --name payments
+topic partitions --name payments --under-replicated-only`,
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := bite.CheckRequiredFlags(cmd, bite.FlagPair{"name": topicName}); err != nil {
+				return err
+			}
+
+			partitions, err := config.Client.GetTopicPartitions(topicName)
+			if err != nil {
+				return err
+			}
+
+			views := make([]partitionView, 0, len(partitions))
+			for _, p := range partitions {
+				if underReplicatedOnly && !p.UnderReplicated() && !p.Offline() {
+					continue
+				}
+				views = append(views, newPartitionView(p))
+			}
+
+			return bite.PrintObject(cmd, views)
+		},
+	}
+
+	cmd.Flags().StringVar(&topicName, "name", "", "The topic name")
+	cmd.Flags().BoolVar(&underReplicatedOnly, "under-replicated-only", false, "Print only the under-replicated and the offline partitions")
+
+	bite.CanPrintJSON(cmd)
+
+	return cmd
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/lensesio/bite"
 	"github.com/lensesio/lenses-go/pkg/api"
+	v1 "github.com/lensesio/lenses-go/v1"
 	"github.com/spf13/cobra"
 )
 
@@ -15,7 +16,7 @@ type topicMetadataView struct {
 	KeySchema         json.RawMessage `json:"keySchema" yaml:"-"`   // for view-only.
 }
 
-func newTopicView(cmd *cobra.Command, client *api.Client, topic api.Topic) (t topicView) {
+func newTopicView(cmd *cobra.Command, client v1.Client, topic api.Topic) (t topicView) {
 	t.Topic = topic
 	output := strings.ToUpper(bite.GetOutPutFlag(cmd))
 
@@ -0,0 +1,211 @@
+package topic
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/kataras/golog"
+	"github.com/lensesio/bite"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/sink"
+	"github.com/lensesio/lenses-go/pkg/throttle"
+	"github.com/lensesio/lenses-go/pkg/websocket"
+	"github.com/spf13/cobra"
+)
+
+// pipeSummary is the result of a `topic pipe`, printed once the input ends or on interrupt.
+type pipeSummary struct {
+	Topic    string  `json:"topic" header:"Topic"`
+	Read     int64   `json:"read" header:"Read"`
+	Produced int64   `json:"produced" header:"Produced"`
+	Skipped  int64   `json:"skipped" header:"Skipped"`
+	Failed   int64   `json:"failed" header:"Failed"`
+	Duration string  `json:"duration" header:"Duration"`
+	Rate     float64 `json:"rate" header:"Records/sec"`
+}
+
+// pipeOptions are the `topic pipe` command's flags.
+type pipeOptions struct {
+	Topic    string
+	KeyField string
+	// Headers are set to all the produced records.
+	Headers  websocket.Headers
+	Throttle throttle.Options
+	sink.Options
+}
+
+// parseRecordHeaders returns the headers of the "name=value" pairs.
+func parseRecordHeaders(pairs []string) (websocket.Headers, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+
+	headers := make(websocket.Headers, len(pairs))
+	for _, pair := range pairs {
+		idx := strings.IndexByte(pair, '=')
+		if idx <= 0 {
+			return nil, fmt.Errorf("invalid header [%s], expected name=value", pair)
+		}
+		headers[pair[:idx]] = []byte(pair[idx+1:])
+	}
+
+	return headers, nil
+}
+
+// pipeKey returns the "field" of the JSON object "value" as the record's key, nil if the value has no such field.
+func pipeKey(value json.RawMessage, field string) json.RawMessage {
+	if field == "" {
+		return nil
+	}
+
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(value, &fields) != nil {
+		return nil
+	}
+
+	return fields[field]
+}
+
+// pipe adds a record of each JSON line of the "r" to the "batcher", which throttles them,
+// until the input ends or the "stop" is closed. The batcher is closed before it returns.
+func pipe(r io.Reader, batcher *sink.Batcher, opts pipeOptions, stop <-chan struct{}) pipeSummary {
+	summary := pipeSummary{Topic: opts.Topic}
+	start := time.Now()
+
+	lines := make(chan []byte)
+	go func() {
+		defer close(lines)
+
+		sc := bufio.NewScanner(r)
+		sc.Buffer(make([]byte, 64*1024), 16<<20)
+		for sc.Scan() {
+			line := append([]byte(nil), sc.Bytes()...)
+			select {
+			case lines <- line:
+			case <-stop:
+				return
+			}
+		}
+
+		if err := sc.Err(); err != nil {
+			golog.Errorf("pipe: unable to read the input: [%v]", err)
+		}
+	}()
+
+loop:
+	for {
+		select {
+		case <-stop:
+			break loop
+		case line, ok := <-lines:
+			if !ok {
+				break loop
+			}
+
+			if len(line) == 0 {
+				continue
+			}
+
+			summary.Read++
+			if !json.Valid(line) {
+				golog.Warnf("pipe: skipped line [%d], it's not a valid JSON", summary.Read)
+				summary.Skipped++
+				continue
+			}
+
+			value := json.RawMessage(line)
+			if err := batcher.Add(sink.Record{Key: pipeKey(value, opts.KeyField), Value: value, Headers: opts.Headers}); err != nil {
+				golog.Errorf("pipe: %v", err)
+			}
+		}
+	}
+
+	if err := batcher.Close(); err != nil {
+		golog.Errorf("pipe: %v", err)
+	}
+
+	summary.Produced, summary.Failed = batcher.Stats()
+	elapsed := time.Since(start)
+	summary.Duration = elapsed.Round(time.Millisecond).String()
+	if seconds := elapsed.Seconds(); seconds > 0 {
+		summary.Rate = float64(int64(float64(summary.Produced)/seconds*100)) / 100
+	}
+
+	return summary
+}
+
+//NewTopicPipeCommand creates `topic pipe` command
+func NewTopicPipeCommand() *cobra.Command {
+	var (
+		opts    = pipeOptions{Options: sink.Options{MaxRetries: 3}}
+		headers []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "pipe",
+		Short: "Produce the JSON lines of the standard input to a topic, continually, until the input ends or on interrupt",
+		Long: `Produce the JSON lines of the standard input to a topic, continually, until the input ends or on interrupt.
+The lines are produced in batches, through Lenses SQL, as the values of the records, the topic's key and value formats should be STRING or JSON.
+A summary of the produced records is printed at the end.`,
+		Example: `tail -f events.jsonl | topic pipe --name events --key-field id
+kafkacat -C -t orders -e | topic pipe --name orders-copy --key-field orderId --rate 500/s
+cat events.jsonl | topic pipe --name events --header source=backfill --header schema-version=2`,
+		// it produces records, a protected context requires --allow-protected.
+		Annotations:      map[string]string{config.AnnotationMutating: "true"},
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := bite.CheckRequiredFlags(cmd, bite.FlagPair{"name": opts.Topic}); err != nil {
+				return err
+			}
+
+			var err error
+			if opts.Headers, err = parseRecordHeaders(headers); err != nil {
+				return err
+			}
+
+			currentConfig := config.Manager.Config.GetCurrent()
+			kafka := &sink.KafkaSink{
+				Topic: opts.Topic,
+				Config: websocket.LiveConfiguration{
+					Host:    currentConfig.Host,
+					Debug:   currentConfig.Debug,
+					Message: websocket.Message{Token: config.Client.Config.Token},
+				},
+			}
+
+			stop := make(chan struct{})
+			interrupt := make(chan os.Signal, 1)
+			signal.Notify(interrupt, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
+			defer signal.Stop(interrupt)
+			go func() {
+				if _, ok := <-interrupt; ok {
+					close(stop)
+				}
+			}()
+
+			opts.Limiter = opts.Throttle.Limiter()
+			summary := pipe(cmd.InOrStdin(), sink.NewBatcher(kafka, opts.Options), opts, stop)
+			return bite.PrintObject(cmd, summary)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Topic, "name", "", "The topic to produce to")
+	cmd.Flags().StringVar(&opts.KeyField, "key-field", "", "The field of the JSON values to use as the records' key")
+	cmd.Flags().StringArrayVar(&headers, "header", nil, "A name=value Kafka header of the produced records, can be repeated")
+	cmd.Flags().IntVar(&opts.BatchSize, "batch", sink.DefaultBatchSize, "The maximum records of a produced batch")
+	cmd.Flags().DurationVar(&opts.FlushInterval, "flush", sink.DefaultFlushInterval, "The maximum time a record waits for its batch to be full")
+	cmd.Flags().IntVar(&opts.MaxRetries, "retries", opts.MaxRetries, "The retries of a batch which failed to be produced")
+	opts.Throttle.AddFlags(cmd.Flags())
+
+	bite.CanPrintJSON(cmd)
+
+	return cmd
+}
@@ -0,0 +1,82 @@
+package topic
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lensesio/lenses-go/pkg/sink"
+	"github.com/lensesio/lenses-go/pkg/throttle"
+	"github.com/lensesio/lenses-go/pkg/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+type memorySink struct{ records []sink.Record }
+
+func (s *memorySink) Write(records []sink.Record) error {
+	s.records = append(s.records, records...)
+	return nil
+}
+
+func (s *memorySink) Close() error { return nil }
+
+func TestPipe(t *testing.T) {
+	input := `{"id":"a","amount":1}
+
+not json
+{"id":2,"amount":2}
+{"amount":3}
+`
+	s := new(memorySink)
+	opts := pipeOptions{Topic: "payments", KeyField: "id"}
+	batcher := sink.NewBatcher(s, sink.Options{BatchSize: 2, FlushInterval: time.Hour, Limiter: throttle.New(1000, 0)})
+
+	summary := pipe(strings.NewReader(input), batcher, opts, make(chan struct{}))
+	assert.Equal(t, "payments", summary.Topic)
+	assert.Equal(t, int64(4), summary.Read)
+	assert.Equal(t, int64(3), summary.Produced)
+	assert.Equal(t, int64(1), summary.Skipped)
+	assert.Equal(t, int64(0), summary.Failed)
+
+	if assert.Len(t, s.records, 3) {
+		assert.Equal(t, json.RawMessage(`"a"`), s.records[0].Key)
+		assert.Equal(t, json.RawMessage(`2`), s.records[1].Key)
+		assert.Nil(t, s.records[2].Key)
+		assert.Equal(t, `{"amount":3}`, string(s.records[2].Value))
+	}
+}
+
+func TestPipeStop(t *testing.T) {
+	stop := make(chan struct{})
+	close(stop)
+
+	s := new(memorySink)
+	summary := pipe(make(blockingReader), sink.NewBatcher(s, sink.Options{}), pipeOptions{Topic: "payments"}, stop)
+	assert.Equal(t, int64(0), summary.Produced)
+}
+
+// blockingReader never returns, like a standard input which is waiting for more.
+type blockingReader chan struct{}
+
+func (r blockingReader) Read(p []byte) (int, error) {
+	<-r
+	return 0, nil
+}
+
+func TestPipeHeaders(t *testing.T) {
+	headers, err := parseRecordHeaders([]string{"source=backfill", "query=a=b", "empty="})
+	assert.NoError(t, err)
+	assert.Equal(t, websocket.Headers{"source": []byte("backfill"), "query": []byte("a=b"), "empty": []byte("")}, headers)
+
+	_, err = parseRecordHeaders([]string{"=value"})
+	assert.EqualError(t, err, "invalid header [=value], expected name=value")
+
+	s := new(memorySink)
+	opts := pipeOptions{Topic: "payments", Headers: headers}
+	summary := pipe(strings.NewReader("1\n2\n"), sink.NewBatcher(s, sink.Options{}), opts, make(chan struct{}))
+	assert.Equal(t, int64(2), summary.Produced)
+	for _, r := range s.records {
+		assert.Equal(t, "backfill", r.Headers.Get("source"))
+	}
+}
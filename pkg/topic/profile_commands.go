@@ -0,0 +1,114 @@
+package topic
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/lensesio/bite"
+	"github.com/lensesio/lenses-go/pkg/profile"
+	"github.com/lensesio/lenses-go/pkg/websocket"
+	"github.com/spf13/cobra"
+)
+
+// defaultProfileSample is the default number of the records `topic profile` samples.
+const defaultProfileSample = 10000
+
+// profileRecords adds the values, or the keys if "key", of the "records" to a `Profiler`.
+func profileRecords(records []websocket.Data, key bool) *profile.Profiler {
+	p := profile.New()
+	for _, r := range records {
+		data := r.Value
+		if key {
+			data = r.Key
+		}
+
+		// the ones which are not JSON are counted as skipped.
+		p.Add(data)
+	}
+
+	return p
+}
+
+// writeProfileReport writes the HTML report of the "p" profile of the "topic" to the "path" file.
+func writeProfileReport(path, topic string, p *profile.Profiler) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("unable to create the report [%s]: [%v]", path, err)
+	}
+
+	report := profile.Report{Title: topic, Values: p.Values(), Created: time.Now(), Fields: p.Fields()}
+	if err = profile.WriteHTML(f, report); err != nil {
+		f.Close()
+		return fmt.Errorf("unable to write the report [%s]: [%v]", path, err)
+	}
+
+	return f.Close()
+}
+
+//NewTopicProfileCommand creates `topic profile` command
+func NewTopicProfileCommand() *cobra.Command {
+	var (
+		topicName, htmlPath string
+		sample              int
+		key                 bool
+		timeout             time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Print the data quality statistics of the fields of the JSON records of a topic, by a sample of them",
+		Long: `Print the data quality statistics of the fields of the JSON values, or keys, of a topic by a sample of its records, which are browsed by SQL:
+the percentage of the records whose field is null or missing, the estimated number of its distinct values, its min and max
+and the lengths of its strings. The --html writes them, with the length distributions, to an HTML report instead.`,
+		Example: `topic profile --name payments
+topic profile --name payments --sample 50000 --html payments.html
+topic profile --name payments --key --output json`,
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := bite.CheckRequiredFlags(cmd, bite.FlagPair{"name": topicName}); err != nil {
+				return err
+			}
+
+			if sample <= 0 {
+				return fmt.Errorf("invalid sample [%d], expected a positive number of records", sample)
+			}
+
+			records, err := browse(fmt.Sprintf("SELECT * FROM `%s` LIMIT %d", topicName, sample), timeout)
+			if err != nil {
+				return err
+			}
+
+			p := profileRecords(records, key)
+			if p.Values() == 0 {
+				return fmt.Errorf("topic [%s] has no JSON records to profile, [%d] were not JSON", topicName, p.Skipped())
+			}
+
+			if p.Skipped() > 0 {
+				bite.PrintInfo(cmd, "Skipped [%d] of the [%d] sampled records which are not JSON", p.Skipped(), len(records))
+			}
+
+			if htmlPath == "" {
+				return bite.PrintObject(cmd, p.Fields())
+			}
+
+			if err = writeProfileReport(htmlPath, topicName, p); err != nil {
+				return err
+			}
+
+			return bite.PrintInfo(cmd, "Profile of [%d] records of [%s] written to [%s]", p.Values(), topicName, htmlPath)
+		},
+	}
+
+	cmd.Flags().StringVar(&topicName, "name", "", "The topic name")
+	cmd.Flags().IntVar(&sample, "sample", defaultProfileSample, "The number of the records to profile")
+	cmd.Flags().BoolVar(&key, "key", false, "Profile the keys instead of the values")
+	cmd.Flags().StringVar(&htmlPath, "html", "", "Write an HTML report to the file instead of printing the statistics")
+	cmd.Flags().DurationVar(&timeout, "browse-timeout", defaultBrowseTimeout, "The maximum duration of the browse query")
+
+	bite.CanPrintJSON(cmd)
+	bite.CanBeSilent(cmd)
+
+	return cmd
+}
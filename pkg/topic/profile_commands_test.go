@@ -0,0 +1,40 @@
+package topic
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lensesio/lenses-go/pkg/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileRecords(t *testing.T) {
+	records := []websocket.Data{
+		{Key: json.RawMessage(`"a"`), Value: json.RawMessage(`{"amount":10}`)},
+		{Key: json.RawMessage(`"b"`), Value: json.RawMessage(`{"amount":null}`)},
+		{Key: json.RawMessage(`c`), Value: json.RawMessage(`not json`)},
+	}
+
+	p := profileRecords(records, false)
+	assert.Equal(t, int64(2), p.Values())
+	assert.Equal(t, int64(1), p.Skipped())
+
+	fields := p.Fields()
+	require.Len(t, fields, 1)
+	assert.Equal(t, "amount", fields[0].Path)
+	assert.Equal(t, float64(50), fields[0].NullRate)
+
+	dir, err := ioutil.TempDir("", "lenses-cli-profile")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "payments.html")
+	require.NoError(t, writeProfileReport(path, "payments", profileRecords(records, true)))
+	b, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "<h1>Profile of payments</h1>")
+}
@@ -0,0 +1,222 @@
+package topic
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmespath/go-jmespath"
+	"github.com/kataras/golog"
+	"github.com/lensesio/bite"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/sink"
+	"github.com/lensesio/lenses-go/pkg/throttle"
+	"github.com/lensesio/lenses-go/pkg/websocket"
+	"github.com/spf13/cobra"
+)
+
+// replaySummary is the result of a `topic replay`.
+type replaySummary struct {
+	From      string `json:"from" header:"From"`
+	To        string `json:"to" header:"To"`
+	Partition int    `json:"partition" header:"Partition"`
+	Offsets   string `json:"offsets" header:"Offsets"`
+	Read      int64  `json:"read" header:"Read"`
+	// Skipped are the records whose transformed value is null.
+	Skipped  int64 `json:"skipped" header:"Skipped"`
+	Produced int64 `json:"produced" header:"Produced"`
+	Failed   int64 `json:"failed" header:"Failed"`
+	DryRun   bool  `json:"dryRun" header:"Dry Run"`
+}
+
+// replayOptions are the `topic replay` command's flags.
+type replayOptions struct {
+	From, To  string
+	Partition int
+	// Begin and End are the inclusive offsets of the replayed records.
+	Begin, End int64
+	// Transform is a JMESPath expression which replaces the value of the records, optionally.
+	Transform string
+	DryRun    bool
+	Throttle  throttle.Options
+	sink.Options
+}
+
+// parseOffsetRange returns the inclusive offsets of the "begin-end" range.
+func parseOffsetRange(s string) (begin, end int64, err error) {
+	idx := strings.IndexByte(s, '-')
+	if idx <= 0 {
+		return 0, 0, fmt.Errorf("invalid offsets [%s], expected begin-end, i.e 100-200", s)
+	}
+
+	if begin, err = strconv.ParseInt(s[:idx], 10, 64); err == nil {
+		end, err = strconv.ParseInt(s[idx+1:], 10, 64)
+	}
+
+	if err != nil || begin < 0 || end < begin {
+		return 0, 0, fmt.Errorf("invalid offsets [%s], expected begin-end, i.e 100-200", s)
+	}
+
+	return begin, end, nil
+}
+
+// query returns the browse query of the replayed records.
+func (opts replayOptions) query() string {
+	return fmt.Sprintf("SELECT * FROM `%s` WHERE _meta.partition = %d AND _meta.offset >= %d AND _meta.offset <= %d",
+		opts.From, opts.Partition, opts.Begin, opts.End)
+}
+
+// transformValue returns the result of the JMESPath "expr" on the JSON "value", nil if the result is null.
+func transformValue(expr *jmespath.JMESPath, value json.RawMessage) (json.RawMessage, error) {
+	var v interface{}
+	if len(value) > 0 {
+		if err := json.Unmarshal(value, &v); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := expr.Search(v)
+	if err != nil || result == nil {
+		return nil, err
+	}
+
+	return json.Marshal(result)
+}
+
+// replay adds the "records" to the "batcher", their value transformed by the "transform" if not nil,
+// the batcher is nil on a dry run. The batcher is closed before it returns.
+func replay(records []websocket.Data, batcher *sink.Batcher, transform *jmespath.JMESPath, opts replayOptions) (replaySummary, error) {
+	summary := replaySummary{From: opts.From, To: opts.To, Partition: opts.Partition,
+		Offsets: fmt.Sprintf("%d-%d", opts.Begin, opts.End), DryRun: batcher == nil}
+
+	for _, data := range records {
+		summary.Read++
+
+		record := sink.NewRecord(data)
+		if transform != nil {
+			value, err := transformValue(transform, record.Value)
+			if err != nil {
+				if batcher != nil {
+					batcher.Close()
+				}
+				return summary, fmt.Errorf("transform of offset [%d]: [%v]", record.Metadata.Offset, err)
+			}
+			if value == nil {
+				summary.Skipped++
+				continue
+			}
+			record.Value = value
+		}
+
+		if batcher == nil {
+			summary.Produced++
+			continue
+		}
+
+		if err := batcher.Add(record); err != nil {
+			golog.Errorf("replay: %v", err)
+		}
+	}
+
+	if batcher != nil {
+		if err := batcher.Close(); err != nil {
+			golog.Errorf("replay: %v", err)
+		}
+		summary.Produced, summary.Failed = batcher.Stats()
+	}
+
+	return summary, nil
+}
+
+//NewTopicReplayCommand creates `topic replay` command
+func NewTopicReplayCommand() *cobra.Command {
+	var (
+		opts    = replayOptions{Options: sink.Options{MaxRetries: 3}}
+		offsets string
+		timeout time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "replay",
+		Short: "Produce a range of offsets of a partition of a topic to another topic",
+		Long: `Produce the records of an inclusive range of offsets of a partition of a topic to another topic, i.e to recover from a downstream processing error.
+The records are produced through Lenses SQL, the key and value formats of the target topic should be STRING or JSON.
+--transform replaces the values with the result of a JMESPath expression on them, the records whose result is null are skipped.
+--dry-run counts the records which would be produced without producing them.`,
+		Example: `topic replay --from payments --to payments-retry --partition 0 --offsets 100-200 --dry-run
+topic replay --from payments --to payments-retry --partition 0 --offsets 100-200 --transform "{id: id, amount: amount}"
+topic replay --from payments --to payments-retry --partition 0 --offsets 0-1000000 --rate 500/s --max-bytes-per-sec 1048576`,
+		// it produces records, a protected context requires --allow-protected.
+		Annotations:      map[string]string{config.AnnotationMutating: "true"},
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := bite.CheckRequiredFlags(cmd, bite.FlagPair{"from": opts.From, "to": opts.To, "offsets": offsets}); err != nil {
+				return err
+			}
+
+			var err error
+			if opts.Begin, opts.End, err = parseOffsetRange(offsets); err != nil {
+				return err
+			}
+
+			var transform *jmespath.JMESPath
+			if opts.Transform != "" {
+				if transform, err = jmespath.Compile(opts.Transform); err != nil {
+					return fmt.Errorf("invalid transform [%s]: [%v]", opts.Transform, err)
+				}
+			}
+
+			records, err := browse(opts.query(), timeout)
+			if err != nil {
+				return err
+			}
+
+			var batcher *sink.Batcher
+			if !opts.DryRun {
+				opts.Limiter = opts.Throttle.Limiter()
+				currentConfig := config.Manager.Config.GetCurrent()
+				batcher = sink.NewBatcher(&sink.KafkaSink{
+					Topic: opts.To,
+					Config: websocket.LiveConfiguration{
+						Host:    currentConfig.Host,
+						Debug:   currentConfig.Debug,
+						Message: websocket.Message{Token: config.Client.Config.Token},
+					},
+				}, opts.Options)
+			}
+
+			summary, err := replay(records, batcher, transform, opts)
+			if err != nil {
+				return err
+			}
+
+			if err = bite.PrintObject(cmd, summary); err != nil {
+				return err
+			}
+
+			if summary.Failed > 0 {
+				return fmt.Errorf("[%d] records failed to be produced to [%s]", summary.Failed, opts.To)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.From, "from", "", "The topic to replay the records of")
+	cmd.Flags().StringVar(&opts.To, "to", "", "The topic to produce the records to")
+	cmd.Flags().IntVar(&opts.Partition, "partition", 0, "The partition of the records")
+	cmd.Flags().StringVar(&offsets, "offsets", "", "The inclusive range of offsets of the records, i.e 100-200")
+	cmd.Flags().StringVar(&opts.Transform, "transform", "", "A JMESPath expression which replaces the values of the records")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Count the records which would be produced, without producing them")
+	cmd.Flags().IntVar(&opts.BatchSize, "batch", sink.DefaultBatchSize, "The maximum records of a produced batch")
+	cmd.Flags().IntVar(&opts.MaxRetries, "retries", opts.MaxRetries, "The retries of a batch which failed to be produced")
+	opts.Throttle.AddFlags(cmd.Flags())
+	cmd.Flags().DurationVar(&timeout, "browse-timeout", defaultBrowseTimeout, "The maximum duration of the browse query")
+
+	bite.CanPrintJSON(cmd)
+
+	return cmd
+}
@@ -0,0 +1,67 @@
+package topic
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jmespath/go-jmespath"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/sink"
+	"github.com/lensesio/lenses-go/pkg/websocket"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOffsetRange(t *testing.T) {
+	begin, end, err := parseOffsetRange("100-200")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), begin)
+	assert.Equal(t, int64(200), end)
+
+	for _, invalid := range []string{"", "100", "-200", "200-100", "a-b", "100-"} {
+		_, _, err = parseOffsetRange(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	opts := replayOptions{From: "payments", To: "payments-retry", Partition: 2, Begin: 10, End: 12}
+	assert.Equal(t, "SELECT * FROM `payments` WHERE _meta.partition = 2 AND _meta.offset >= 10 AND _meta.offset <= 12", opts.query())
+
+	records := []websocket.Data{
+		{Key: json.RawMessage(`"a"`), Value: json.RawMessage(`{"amount":50}`), Metadata: websocket.MetaData{Partition: 2, Offset: 10}},
+		{Key: json.RawMessage(`"b"`), Value: json.RawMessage(`{"amount":150}`), Metadata: websocket.MetaData{Partition: 2, Offset: 11}},
+		{Key: json.RawMessage(`"c"`), Value: json.RawMessage(`{"amount":250}`), Metadata: websocket.MetaData{Partition: 2, Offset: 12}},
+	}
+	transform := jmespath.MustCompile("amount > `100` && {amount: amount, retried: `true`} || null")
+
+	// dry run.
+	summary, err := replay(records, nil, transform, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, replaySummary{From: "payments", To: "payments-retry", Partition: 2, Offsets: "10-12",
+		Read: 3, Skipped: 1, Produced: 2, DryRun: true}, summary)
+
+	s := new(memorySink)
+	summary, err = replay(records, sink.NewBatcher(s, sink.Options{FlushInterval: time.Hour}), transform, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), summary.Produced)
+	assert.False(t, summary.DryRun)
+	if assert.Len(t, s.records, 2) {
+		assert.Equal(t, `"b"`, string(s.records[0].Key))
+		assert.JSONEq(t, `{"amount":150,"retried":true}`, string(s.records[0].Value))
+	}
+
+	// without a transform the records are produced as they are.
+	s = new(memorySink)
+	summary, err = replay(records[:1], sink.NewBatcher(s, sink.Options{}), nil, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), summary.Produced)
+	assert.Equal(t, `{"amount":50}`, string(s.records[0].Value))
+}
+
+func TestProducingCommandsMutating(t *testing.T) {
+	for _, cmd := range []*cobra.Command{NewTopicReplayCommand(), NewTopicPipeCommand(), NewTopicMirrorCommand()} {
+		assert.True(t, config.IsMutating(cmd), cmd.Name())
+	}
+}
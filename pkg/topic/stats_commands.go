@@ -0,0 +1,158 @@
+package topic
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lensesio/bite"
+	"github.com/lensesio/lenses-go/pkg/api"
+	"github.com/lensesio/lenses-go/pkg/bulk"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/lensesio/lenses-go/pkg/websocket"
+	"github.com/spf13/cobra"
+)
+
+// statsLatestWindow is the number of records before the end offset of a partition which are browsed for its latest timestamp,
+// the last offsets may be transaction markers which are not returned.
+const statsLatestWindow = 10
+
+// partitionStatsView is a row of `topic stats`.
+type partitionStatsView struct {
+	Partition int   `json:"partition" yaml:"partition" header:"Partition"`
+	Begin     int64 `json:"begin" yaml:"begin" header:"Begin"`
+	End       int64 `json:"end" yaml:"end" header:"End"`
+	// Records is approximate, the offsets of the compacted records and of the transaction markers are counted too.
+	Records int64 `json:"records" yaml:"records" header:"Records"`
+	// Earliest and Latest are the timestamps of the first and the last records of the partition, in milliseconds, zero if empty.
+	Earliest int64 `json:"earliest" yaml:"earliest" header:"Earliest,timestamp(ms|utc|02 Jan 2006 15:04)"`
+	Latest   int64 `json:"latest" yaml:"latest" header:"Latest,timestamp(ms|utc|02 Jan 2006 15:04)"`
+}
+
+// earliestQuery returns the browse query of the first record of the "partition".
+func earliestQuery(topic string, partition api.TopicPartition) string {
+	return fmt.Sprintf("SELECT * FROM `%s` WHERE _meta.partition = %d AND _meta.offset >= %d LIMIT 1",
+		topic, partition.Partition, partition.Begin)
+}
+
+// latestQuery returns the browse query of the last records of the "partition", the last of them is its last record.
+func latestQuery(topic string, partition api.TopicPartition) string {
+	from := partition.End - statsLatestWindow
+	if from < partition.Begin {
+		from = partition.Begin
+	}
+
+	return fmt.Sprintf("SELECT * FROM `%s` WHERE _meta.partition = %d AND _meta.offset >= %d LIMIT %d",
+		topic, partition.Partition, from, statsLatestWindow)
+}
+
+// lastTimestamp returns the timestamp of the record of the largest offset of the "records".
+func lastTimestamp(records []websocket.Data) int64 {
+	var (
+		latest int64
+		offset = -1
+	)
+
+	for _, r := range records {
+		if ts, ok := recordTimestamp(r.Metadata); ok && r.Metadata.Offset > offset {
+			latest, offset = ts, r.Metadata.Offset
+		}
+	}
+
+	return latest
+}
+
+// partitionStats returns the stats of the "partition", it browses its first and last records by "browse".
+func partitionStats(topic string, partition api.TopicPartition, browse func(sql string) ([]websocket.Data, error)) (partitionStatsView, error) {
+	stats := partitionStatsView{Partition: partition.Partition, Begin: partition.Begin, End: partition.End,
+		Records: partition.End - partition.Begin}
+	if stats.Records <= 0 {
+		stats.Records = 0
+		return stats, nil
+	}
+
+	earliest, err := browse(earliestQuery(topic, partition))
+	if err != nil {
+		return stats, err
+	}
+	stats.Earliest = lastTimestamp(earliest)
+
+	latest, err := browse(latestQuery(topic, partition))
+	if err != nil {
+		return stats, err
+	}
+	stats.Latest = lastTimestamp(latest)
+
+	return stats, nil
+}
+
+//NewTopicStatsCommand creates `topic stats` command
+func NewTopicStatsCommand() *cobra.Command {
+	var (
+		topicName string
+		timeout   time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Print the approximate number of records and the earliest and latest record timestamps per partition of a topic",
+		Long: `Print the approximate number of records and the earliest and latest record timestamps per partition of a topic.
+The number of records is the difference of the end and the begin offsets, which counts the compacted records and the transaction markers too.
+The timestamps are the ones of the first and the last records of the partitions, by offset, which are browsed by SQL.`,
+		Example:          `topic stats --name payments`,
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := bite.CheckRequiredFlags(cmd, bite.FlagPair{"name": topicName}); err != nil {
+				return err
+			}
+
+			partitions, err := config.Client.GetTopicPartitions(topicName)
+			if err != nil {
+				return err
+			}
+
+			var (
+				mu    sync.Mutex
+				stats = make([]partitionStatsView, 0, len(partitions))
+			)
+
+			tasks := make([]bulk.Task, len(partitions))
+			for i, p := range partitions {
+				p := p
+				tasks[i] = bulk.Task{Name: fmt.Sprintf("partition [%d]", p.Partition), Run: func() error {
+					s, err := partitionStats(topicName, p, func(sql string) ([]websocket.Data, error) {
+						return browse(sql, timeout)
+					})
+					if err != nil {
+						return err
+					}
+
+					mu.Lock()
+					stats = append(stats, s)
+					mu.Unlock()
+					return nil
+				}}
+			}
+
+			if err = bulk.Err(bulk.Run(bulk.Concurrency(cmd), tasks)); err != nil {
+				return err
+			}
+
+			sort.Slice(stats, func(i, j int) bool {
+				return stats[i].Partition < stats[j].Partition
+			})
+
+			return bite.PrintObject(cmd, stats)
+		},
+	}
+
+	cmd.Flags().StringVar(&topicName, "name", "", "The topic name")
+	cmd.Flags().DurationVar(&timeout, "browse-timeout", defaultBrowseTimeout, "The maximum duration of the browse query of each partition")
+
+	bulk.AddConcurrencyFlag(cmd)
+	bite.CanPrintJSON(cmd)
+
+	return cmd
+}
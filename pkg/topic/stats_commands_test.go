@@ -0,0 +1,52 @@
+package topic
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	"github.com/lensesio/lenses-go/pkg/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartitionStats(t *testing.T) {
+	record := func(offset int, ts interface{}) websocket.Data {
+		return websocket.Data{Metadata: websocket.MetaData{Offset: offset, Timestamp: ts}}
+	}
+
+	var queries []string
+	browse := func(sql string) ([]websocket.Data, error) {
+		queries = append(queries, sql)
+		if len(queries) == 1 {
+			return []websocket.Data{record(100, json.Number("1583884800000"))}, nil
+		}
+		// the last offset is a transaction marker.
+		return []websocket.Data{record(196, float64(1583971100000)), record(198, json.Number("1583971200000")), record(197, "1583971150000")}, nil
+	}
+
+	stats, err := partitionStats("payments", api.TopicPartition{Partition: 2, Begin: 100, End: 200}, browse)
+	assert.NoError(t, err)
+	assert.Equal(t, partitionStatsView{Partition: 2, Begin: 100, End: 200, Records: 100,
+		Earliest: 1583884800000, Latest: 1583971200000}, stats)
+	assert.Equal(t, []string{
+		"SELECT * FROM `payments` WHERE _meta.partition = 2 AND _meta.offset >= 100 LIMIT 1",
+		"SELECT * FROM `payments` WHERE _meta.partition = 2 AND _meta.offset >= 190 LIMIT 10",
+	}, queries)
+
+	// empty, not browsed.
+	queries = nil
+	stats, err = partitionStats("payments", api.TopicPartition{Partition: 0, Begin: 50, End: 50}, browse)
+	assert.NoError(t, err)
+	assert.Equal(t, partitionStatsView{Partition: 0, Begin: 50, End: 50}, stats)
+	assert.Empty(t, queries)
+
+	// fewer records than the window.
+	assert.Equal(t, "SELECT * FROM `payments` WHERE _meta.partition = 1 AND _meta.offset >= 5 LIMIT 10",
+		latestQuery("payments", api.TopicPartition{Partition: 1, Begin: 5, End: 8}))
+
+	_, err = partitionStats("payments", api.TopicPartition{Begin: 0, End: 1}, func(string) ([]websocket.Data, error) {
+		return nil, errors.New("[INVALIDREQUEST]: [unknown topic]")
+	})
+	assert.EqualError(t, err, "[INVALIDREQUEST]: [unknown topic]")
+}
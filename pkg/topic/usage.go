@@ -0,0 +1,148 @@
+package topic
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+)
+
+const day = 24 * time.Hour
+
+// usageView is a row of `topics usage`, the size, the growth and the forecast of a topic.
+type usageView struct {
+	Topic       string `json:"topic" yaml:"topic" header:"Topic"`
+	Partitions  int    `json:"partitions" yaml:"partitions" header:"Part"`
+	Replication int    `json:"replication" yaml:"replication" header:"Repl"`
+	// Bytes is the size of the leader replicas.
+	Bytes int64 `json:"bytes" yaml:"bytes" header:"Size (bytes)"`
+	// DiskBytes is the size of all the replicas.
+	DiskBytes int64 `json:"diskBytes" yaml:"diskBytes" header:"Disk (bytes)"`
+	// GrowthPerDay is the growth of the leader replicas since the oldest snapshot, zero if unknown.
+	GrowthPerDay int64 `json:"growthPerDay" yaml:"growthPerDay" header:"Growth/Day (bytes)"`
+	// RetentionMs is -1 if the retention is unlimited.
+	RetentionMs int64 `json:"retentionMs" yaml:"retentionMs" header:"Retention (ms)"`
+	// RetentionBytes is the retention per partition, -1 if unlimited.
+	RetentionBytes int64  `json:"retentionBytes" yaml:"retentionBytes" header:"Retention (bytes)"`
+	CleanupPolicy  string `json:"cleanupPolicy" yaml:"cleanupPolicy" header:"Cleanup"`
+	// ForecastDiskBytes is the size of all the replicas after the forecast days.
+	ForecastDiskBytes int64 `json:"forecastDiskBytes" yaml:"forecastDiskBytes" header:"Forecast Disk (bytes)"`
+}
+
+// usageHeader is the header of the CSV output of `topics usage`.
+var usageHeader = []string{"topic", "partitions", "replication", "bytes", "diskBytes", "growthPerDay",
+	"retentionMs", "retentionBytes", "cleanupPolicy", "forecastDiskBytes"}
+
+func (u usageView) record() []string {
+	return []string{
+		u.Topic,
+		strconv.Itoa(u.Partitions),
+		strconv.Itoa(u.Replication),
+		strconv.FormatInt(u.Bytes, 10),
+		strconv.FormatInt(u.DiskBytes, 10),
+		strconv.FormatInt(u.GrowthPerDay, 10),
+		strconv.FormatInt(u.RetentionMs, 10),
+		strconv.FormatInt(u.RetentionBytes, 10),
+		u.CleanupPolicy,
+		strconv.FormatInt(u.ForecastDiskBytes, 10),
+	}
+}
+
+// topicConfig returns the effective value of the config "name" of the "topic".
+func topicConfig(topic api.Topic, name string) (string, bool) {
+	for _, kv := range topic.Configs {
+		if n, _ := kv["name"].(string); n != name {
+			continue
+		}
+
+		for _, key := range []string{"originalValue", "value", "defaultValue"} {
+			if v, ok := kv[key]; ok && v != nil {
+				switch value := v.(type) {
+				case string:
+					return value, true
+				case float64:
+					return strconv.FormatInt(int64(value), 10), true
+				}
+			}
+		}
+	}
+
+	return "", false
+}
+
+// topicConfigInt returns the config "name" of the "topic" as a number, -1 if it's not set or invalid.
+func topicConfigInt(topic api.Topic, name string) int64 {
+	v, ok := topicConfig(topic, name)
+	if !ok {
+		return -1
+	}
+
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return -1
+	}
+
+	return n
+}
+
+// forecastUsage returns the usage of the "topic" whose leader replicas are "bytes" large,
+// the growth is measured against the oldest of the "snapshots" with the topic
+// and the forecast is the size after "days", capped by the topic's retention.
+func forecastUsage(topic api.Topic, bytes int64, snapshots []config.UsageSnapshot, now time.Time, days int) usageView {
+	replication := topic.Replication
+	if replication < 1 {
+		replication = 1
+	}
+
+	u := usageView{
+		Topic:          topic.TopicName,
+		Partitions:     topic.Partitions,
+		Replication:    topic.Replication,
+		Bytes:          bytes,
+		DiskBytes:      bytes * int64(replication),
+		RetentionMs:    topicConfigInt(topic, "retention.ms"),
+		RetentionBytes: topicConfigInt(topic, "retention.bytes"),
+		CleanupPolicy:  "delete",
+	}
+
+	if policy, ok := topicConfig(topic, "cleanup.policy"); ok && policy != "" {
+		u.CleanupPolicy = policy
+	}
+
+	for _, s := range snapshots {
+		previous, ok := s.Topics[topic.TopicName]
+		if !ok {
+			continue
+		}
+
+		if elapsed := now.Sub(s.Time); elapsed > 0 {
+			u.GrowthPerDay = int64(float64(bytes-previous) / (float64(elapsed) / float64(day)))
+		}
+		break
+	}
+
+	forecast := bytes + u.GrowthPerDay*int64(days)
+	deletes := strings.Contains(u.CleanupPolicy, "delete")
+	// a topic which still fills its retention window grows up to the data of the whole window.
+	if deletes && u.RetentionMs >= 0 && u.GrowthPerDay > 0 {
+		if window := u.GrowthPerDay * u.RetentionMs / int64(day/time.Millisecond); forecast > window {
+			forecast = window
+			if forecast < bytes {
+				forecast = bytes
+			}
+		}
+	}
+	if deletes && u.RetentionBytes >= 0 && topic.Partitions > 0 {
+		if limit := u.RetentionBytes * int64(topic.Partitions); forecast > limit {
+			forecast = limit
+		}
+	}
+	if forecast < 0 {
+		forecast = 0
+	}
+
+	u.ForecastDiskBytes = forecast * int64(replication)
+	return u
+}
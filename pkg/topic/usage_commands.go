@@ -0,0 +1,137 @@
+package topic
+
+import (
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lensesio/bite"
+	"github.com/lensesio/lenses-go/pkg/bulk"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/spf13/cobra"
+)
+
+//NewTopicsUsageCommand creates `topics usage` command
+func NewTopicsUsageCommand() *cobra.Command {
+	var (
+		days       int
+		keep       int
+		noSnapshot bool
+		asCSV      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "usage",
+		Short: "Print the size, the growth and the forecast disk usage of the topics",
+		Long: `Print the size, the growth per day, the retention and the forecast disk usage of the topics, for capacity planning.
+Each run saves a snapshot of the topics' size locally, the growth is measured against the oldest saved snapshot of the current context,
+so it's unknown on the first run. The forecast is the size after --days, capped by the retention of the topics.`,
+		Example: `topics usage
+topics usage --days 90 --csv > usage.csv`,
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client := config.Client
+			context := config.Manager.Config.CurrentContext
+
+			topics, err := client.GetTopics()
+			if err != nil {
+				return err
+			}
+
+			var (
+				mu    sync.Mutex
+				sizes = make(map[string]int64, len(topics))
+			)
+
+			tasks := make([]bulk.Task, 0, len(topics))
+			for _, topic := range topics {
+				if topic.IsControlTopic {
+					continue
+				}
+
+				name := topic.TopicName
+				tasks = append(tasks, bulk.Task{Name: fmt.Sprintf("topic [%s]", name), Run: func() error {
+					partitions, err := client.GetTopicPartitions(name)
+					if err != nil {
+						return err
+					}
+
+					var size int64
+					for _, p := range partitions {
+						size += p.Bytes
+					}
+
+					mu.Lock()
+					sizes[name] = size
+					mu.Unlock()
+					return nil
+				}})
+			}
+
+			if err = bulk.Err(bulk.Run(bulk.Concurrency(cmd), tasks)); err != nil {
+				return err
+			}
+
+			snapshots, err := config.UsageSnapshots(context)
+			if err != nil {
+				return err
+			}
+
+			now := time.Now()
+			usage := make([]usageView, 0, len(sizes))
+			for _, topic := range topics {
+				if size, ok := sizes[topic.TopicName]; ok {
+					usage = append(usage, forecastUsage(topic, size, snapshots, now, days))
+				}
+			}
+
+			sort.Slice(usage, func(i, j int) bool {
+				if usage[i].ForecastDiskBytes != usage[j].ForecastDiskBytes {
+					return usage[i].ForecastDiskBytes > usage[j].ForecastDiskBytes
+				}
+				return usage[i].Topic < usage[j].Topic
+			})
+
+			if !noSnapshot {
+				if err = config.SaveUsageSnapshot(context, config.UsageSnapshot{Time: now, Topics: sizes}, keep); err != nil {
+					return err
+				}
+			}
+
+			if asCSV {
+				return printUsageCSV(cmd, usage)
+			}
+
+			return bite.PrintObject(cmd, usage)
+		},
+	}
+
+	cmd.Flags().IntVar(&days, "days", 30, "The days to forecast the disk usage for")
+	cmd.Flags().IntVar(&keep, "keep", 30, "The number of snapshots to keep locally, the growth is measured against the oldest one")
+	cmd.Flags().BoolVar(&noSnapshot, "no-snapshot", false, "Do not save a snapshot of the topics' size")
+	cmd.Flags().BoolVar(&asCSV, "csv", false, "Print the usage as CSV")
+
+	bulk.AddConcurrencyFlag(cmd)
+	bite.CanPrintJSON(cmd)
+
+	return cmd
+}
+
+func printUsageCSV(cmd *cobra.Command, usage []usageView) error {
+	w := csv.NewWriter(cmd.OutOrStdout())
+	if err := w.Write(usageHeader); err != nil {
+		return err
+	}
+
+	for _, u := range usage {
+		if err := w.Write(u.record()); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,65 @@
+package topic
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	config "github.com/lensesio/lenses-go/pkg/configs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForecastUsage(t *testing.T) {
+	now := time.Date(2020, 3, 11, 0, 0, 0, 0, time.UTC)
+	snapshots := []config.UsageSnapshot{
+		{Time: now.Add(-10 * day), Topics: map[string]int64{"orders": 1000, "payments": 5000}},
+		{Time: now.Add(-5 * day), Topics: map[string]int64{"orders": 1500, "payments": 5000, "refunds": 0}},
+	}
+
+	topic := func(name string, configs ...api.KV) api.Topic {
+		return api.Topic{TopicName: name, Partitions: 2, Replication: 3, Configs: configs}
+	}
+
+	// unlimited retention, grows linearly from the oldest snapshot.
+	orders := forecastUsage(topic("orders", api.KV{"name": "retention.ms", "originalValue": "-1"}), 2000, snapshots, now, 30)
+	assert.Equal(t, int64(6000), orders.DiskBytes)
+	assert.Equal(t, int64(100), orders.GrowthPerDay)
+	assert.Equal(t, int64(-1), orders.RetentionMs)
+	assert.Equal(t, int64((2000+100*30)*3), orders.ForecastDiskBytes)
+
+	// capped by the data of the retention window of 40 days.
+	window := api.KV{"name": "retention.ms", "originalValue": "3456000000"}
+	orders = forecastUsage(topic("orders", window), 2000, snapshots, now, 30)
+	assert.Equal(t, int64(100*40*3), orders.ForecastDiskBytes)
+
+	// the window is already full.
+	window["originalValue"] = "864000000"
+	orders = forecastUsage(topic("orders", window), 2000, snapshots, now, 30)
+	assert.Equal(t, int64(2000*3), orders.ForecastDiskBytes)
+
+	// capped by the retention bytes per partition.
+	limit := api.KV{"name": "retention.bytes", "value": "1500", "isDefault": false}
+	orders = forecastUsage(topic("orders", limit), 2000, snapshots, now, 30)
+	assert.Equal(t, int64(1500*2*3), orders.ForecastDiskBytes)
+
+	// compacted topics are not capped.
+	compacted := api.KV{"name": "cleanup.policy", "originalValue": "compact"}
+	orders = forecastUsage(topic("orders", limit, compacted), 2000, snapshots, now, 30)
+	assert.Equal(t, "compact", orders.CleanupPolicy)
+	assert.Equal(t, int64((2000+100*30)*3), orders.ForecastDiskBytes)
+
+	// shrinking.
+	payments := forecastUsage(topic("payments"), 4000, snapshots, now, 100)
+	assert.Equal(t, int64(-100), payments.GrowthPerDay)
+	assert.Equal(t, int64(0), payments.ForecastDiskBytes)
+
+	// measured from the oldest snapshot with the topic.
+	refunds := forecastUsage(topic("refunds"), 500, snapshots, now, 1)
+	assert.Equal(t, int64(100), refunds.GrowthPerDay)
+
+	// unknown growth, the first run.
+	audit := forecastUsage(topic("audit"), 700, nil, now, 30)
+	assert.Equal(t, int64(0), audit.GrowthPerDay)
+	assert.Equal(t, int64(700*3), audit.ForecastDiskBytes)
+	assert.Equal(t, "delete", audit.CleanupPolicy)
+}
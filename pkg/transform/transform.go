@@ -0,0 +1,192 @@
+// Package transform anonymizes the records on the client side, before they are printed or exported,
+// by redacting, hashing or dropping the fields of their keys and values.
+package transform
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// Redacted replaces the value of a redacted field.
+const Redacted = "[REDACTED]"
+
+// KeyPrefix is the prefix of the field paths of the records' keys, the rest are of their values.
+const KeyPrefix = "_key."
+
+// The actions of a `Rule`.
+const (
+	ActionRedact = "redact"
+	ActionHash   = "hash"
+	ActionDrop   = "drop"
+)
+
+// Rule applies its action to the field of the path, the field is looked up in each element of an array.
+type Rule struct {
+	Action string
+	Path   []string
+	// Key reports whether the path is of the key, the `KeyPrefix`, instead of the value.
+	Key bool
+}
+
+// ParseRule returns the rule of the "action" on the dot separated "path", i.e "customer.email" or "_key.id".
+func ParseRule(action, path string) (Rule, error) {
+	switch action {
+	case ActionRedact, ActionHash, ActionDrop:
+	default:
+		return Rule{}, fmt.Errorf("unknown transform action [%s]", action)
+	}
+
+	rule := Rule{Action: action}
+	if strings.HasPrefix(path, KeyPrefix) {
+		rule.Key = true
+		path = strings.TrimPrefix(path, KeyPrefix)
+	}
+
+	for _, name := range strings.Split(path, ".") {
+		if name == "" {
+			return Rule{}, fmt.Errorf("invalid field path [%s]", path)
+		}
+		rule.Path = append(rule.Path, name)
+	}
+
+	return rule, nil
+}
+
+// Transformer applies its rules, in order, to the records.
+type Transformer struct {
+	Rules []Rule
+	// Salt is the HMAC key of the hashed fields, without it the hashes of guessable values, i.e emails, can be reversed.
+	Salt string
+}
+
+// Apply returns the "key" and the "value" with the rules applied, the ones which are not JSON objects or arrays are returned as they are.
+func (t *Transformer) Apply(key, value json.RawMessage) (json.RawMessage, json.RawMessage, error) {
+	var keyRules, valueRules []Rule
+	for _, r := range t.Rules {
+		if r.Key {
+			keyRules = append(keyRules, r)
+		} else {
+			valueRules = append(valueRules, r)
+		}
+	}
+
+	key, err := t.apply(key, keyRules)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	value, err = t.apply(value, valueRules)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return key, value, nil
+}
+
+func (t *Transformer) apply(data json.RawMessage, rules []Rule) (json.RawMessage, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(rules) == 0 || len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return data, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(trimmed))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("transform: [%v]", err)
+	}
+
+	for _, r := range rules {
+		t.applyRule(v, r.Path, r.Action)
+	}
+
+	return json.Marshal(v)
+}
+
+func (t *Transformer) applyRule(v interface{}, path []string, action string) {
+	switch node := v.(type) {
+	case []interface{}:
+		for _, elem := range node {
+			t.applyRule(elem, path, action)
+		}
+	case map[string]interface{}:
+		field, ok := node[path[0]]
+		if !ok {
+			return
+		}
+
+		if len(path) > 1 {
+			t.applyRule(field, path[1:], action)
+			return
+		}
+
+		switch action {
+		case ActionDrop:
+			delete(node, path[0])
+		case ActionRedact:
+			node[path[0]] = Redacted
+		case ActionHash:
+			node[path[0]] = t.hash(field)
+		}
+	}
+}
+
+// hash returns the hex SHA-256, or the HMAC-SHA256 with the "Salt", of the field's JSON, so equal values have equal hashes.
+func (t *Transformer) hash(v interface{}) string {
+	b, _ := json.Marshal(v)
+	if t.Salt == "" {
+		sum := sha256.Sum256(b)
+		return hex.EncodeToString(sum[:])
+	}
+
+	h := hmac.New(sha256.New, []byte(t.Salt))
+	h.Write(b)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Options are the transform flags of the commands which print or export records.
+type Options struct {
+	Redact   []string
+	Hash     []string
+	Drop     []string
+	HashSalt string
+}
+
+// AddFlags registers the "--redact", "--hash", "--drop-field" and "--hash-salt" flags.
+func (opts *Options) AddFlags(flags *pflag.FlagSet) {
+	flags.StringArrayVar(&opts.Redact, "redact", nil, "Replace the field of the dot separated path with [REDACTED], _key.path for a field of the key, can be repeated")
+	flags.StringArrayVar(&opts.Hash, "hash", nil, "Replace the field of the dot separated path with its SHA-256, _key.path for a field of the key, can be repeated")
+	flags.StringArrayVar(&opts.Drop, "drop-field", nil, "Remove the field of the dot separated path, _key.path for a field of the key, can be repeated")
+	flags.StringVar(&opts.HashSalt, "hash-salt", "", "The secret of the --hash HMAC, so the hashes of guessable values can not be reversed")
+}
+
+// Transformer returns the transformer of the options, nil if there are no rules.
+func (opts Options) Transformer() (*Transformer, error) {
+	t := &Transformer{Salt: opts.HashSalt}
+	for _, group := range []struct {
+		action string
+		paths  []string
+	}{{ActionDrop, opts.Drop}, {ActionRedact, opts.Redact}, {ActionHash, opts.Hash}} {
+		for _, path := range group.paths {
+			rule, err := ParseRule(group.action, path)
+			if err != nil {
+				return nil, err
+			}
+			t.Rules = append(t.Rules, rule)
+		}
+	}
+
+	if len(t.Rules) == 0 {
+		return nil, nil
+	}
+
+	return t, nil
+}
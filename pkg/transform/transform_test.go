@@ -0,0 +1,57 @@
+package transform
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransformer(t *testing.T) {
+	opts := Options{
+		Redact: []string{"customer.email", "_key.user"},
+		Hash:   []string{"items.sku"},
+		Drop:   []string{"customer.address"},
+	}
+	tr, err := opts.Transformer()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	key := json.RawMessage(`{"user":"alice","id":1}`)
+	value := json.RawMessage(`{"customer":{"email":"alice@example.com","address":"1 Main St","age":30},"items":[{"sku":"A1","qty":2},{"qty":1}],"total":12345678901234567890}`)
+
+	key, value, err = tr.Apply(key, value)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"user":"[REDACTED]","id":1}`, string(key))
+
+	sku, _ := json.Marshal("A1")
+	assert.JSONEq(t, `{"customer":{"email":"[REDACTED]","age":30},"items":[{"sku":"`+tr.hash(json.RawMessage(sku))+`","qty":2},{"qty":1}],"total":12345678901234567890}`, string(value))
+	// the numbers keep their precision.
+	assert.Contains(t, string(value), `"total":12345678901234567890`)
+}
+
+func TestTransformerHash(t *testing.T) {
+	tr := &Transformer{}
+	// the SHA-256 of the JSON "A1".
+	assert.Equal(t, "961ea6171fe18f8c510fc30a17ba90b2dc0cca62fd36a1b8749aad14fe357483", tr.hash("A1"))
+	assert.Equal(t, tr.hash("A1"), tr.hash("A1"))
+
+	salted := &Transformer{Salt: "s3cr3t"}
+	assert.NotEqual(t, tr.hash("A1"), salted.hash("A1"))
+}
+
+func TestTransformerPrimitives(t *testing.T) {
+	tr, _ := Options{Redact: []string{"email"}}.Transformer()
+	key, value, err := tr.Apply(json.RawMessage(`"k"`), json.RawMessage(`42`))
+	assert.NoError(t, err)
+	assert.Equal(t, `"k"`, string(key))
+	assert.Equal(t, `42`, string(value))
+
+	tr, err = Options{}.Transformer()
+	assert.NoError(t, err)
+	assert.Nil(t, tr)
+
+	_, err = Options{Drop: []string{"customer..email"}}.Transformer()
+	assert.EqualError(t, err, "invalid field path [customer..email]")
+}
@@ -82,6 +82,7 @@ func NewDeleteConfigurationContextCommand() *cobra.Command {
 		Short:         "Delete a configuration context",
 		Example:       `context delete context_name`,
 		SilenceErrors: true,
+		Annotations:   map[string]string{config.AnnotationMutating: "false"}, // local configuration only.
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) == 0 {
 				return fmt.Errorf("one argument is required for the context name")
@@ -125,6 +126,7 @@ func NewUpdateConfigurationContextCommand() *cobra.Command {
 		Short:         "Edit an existing or add a configuration context e.g. lenses-cli context create my-new-context",
 		Example:       `context edit context_name`,
 		SilenceErrors: true,
+		Annotations:   map[string]string{config.AnnotationMutating: "false"}, // local configuration only.
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) == 0 {
 				return fmt.Errorf("one argument is required for the context name")
@@ -493,22 +495,62 @@ Docs at https://docs.lenses.io
 
 //NewLoginCommand create `login` command
 func NewLoginCommand(app *bite.Application) *cobra.Command {
+	var (
+		store string
+		shell bool
+	)
+
 	cmd := &cobra.Command{
-		Use:              "login",
-		Short:            "Login, generate the access token using the generated configuration via the 'configure' command. ",
-		Example:          `login`,
+		Use:   "login",
+		Short: "Login, cache the session's token and store the current context's credentials encrypted",
+		Long: `Login, cache the session's token and store the current context's credentials encrypted, in the OS keychain or with a passphrase.
+The credentials are the ones of the configuration file or the --host, --user and --pass flags, the missing ones are asked on the terminal.
+The next commands use the cached token and renew it with the stored credentials when it expires.
+With the passphrase store, the passphrase is read from the ` + config.EnvPassphrase + ` environment variable or asked on the terminal.
+SSO logins are not supported, use a service account's token through the --token flag instead.`,
+		Example: `login --host=https://lenses:9991
+login --store=passphrase
+login --context=prod --host=https://lenses.prod:9991 --user=admin --pass=admin
+login --shell`,
 		SilenceErrors:    true,
 		TraverseChildren: true,
-		Hidden:           true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client := config.Client
+			if err := config.ValidateCredentialStore(store); err != nil {
+				return err
+			}
 
-			if _, err := api.OpenConnection(*config.Manager.Config.GetCurrent()); err != nil {
+			current := config.Manager.Config.GetCurrent()
+			if err := askLoginCredentials(current); err != nil {
+				return err
+			}
+
+			// always create a new session.
+			current.Token = ""
+			client, err := api.OpenConnection(*current)
+			if err != nil {
+				return err
+			}
+			config.Client = client
+
+			name := config.Manager.Config.CurrentContext
+			config.Manager.CredentialStore = store
+			if err = config.Manager.Save(); err != nil {
+				return err
+			}
+
+			if err = config.Manager.CacheToken(name, client.Config.Token); err != nil {
 				return err
 			}
 
 			out := cmd.OutOrStdout()
 			signedUser := client.User
+			fmt.Fprintf(out, "Logged in to [%s] as [%s], the credentials of the [%s] context are stored in the [%s]\n",
+				client.Config.Host, signedUser.Name, config.Manager.Config.CurrentContext, store)
+
+			if !shell {
+				return nil
+			}
+
 			fmt.Fprintf(out, "Welcome [%s%s],\ntype 'help' to learn more about the available commands or 'exit' to terminate.\n",
 				signedUser.Name, strings.Join(signedUser.Permissions, ", "))
 
@@ -601,9 +643,101 @@ func NewLoginCommand(app *bite.Application) *cobra.Command {
 
 		}}
 
+	cmd.Flags().StringVar(&store, "store", config.DefaultCredentialStore(), "The credential store, 'keyring' for the OS keychain or 'passphrase' for an encrypted configuration file")
+	cmd.Flags().BoolVar(&shell, "shell", false, "Start an interactive session after login")
+
 	return cmd
 }
 
+//NewLogoutCommand creates `logout` command
+func NewLogoutCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:              "logout",
+		Short:            "Revoke the current context's cached session and remove its stored credentials",
+		Example:          `logout`,
+		SilenceErrors:    true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := config.Manager.Config.CurrentContext
+			if !config.Manager.Config.CurrentContextExists() {
+				return fmt.Errorf("unknown context [%s]", name)
+			}
+
+			// revoke the cached session's token, if it's still valid.
+			if token, err := config.Manager.CachedToken(name); err == nil && token != "" {
+				if client, err := api.OpenConnection(*config.Manager.Config.GetCurrent(), api.UsingToken(token)); err == nil {
+					if err = client.Logout(); err != nil {
+						golog.Debugf("logout: [%v]", err)
+					}
+				}
+			}
+
+			if err := config.Manager.RemoveCachedToken(name); err != nil {
+				return err
+			}
+
+			if err := config.Manager.RemoveSecrets(name); err != nil {
+				return err
+			}
+
+			if err := config.Manager.Save(); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Logged out, the credentials of the [%s] context are removed\n", name)
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// askLoginCredentials asks for the host, the username and the password of the basic authentication, if missing.
+func askLoginCredentials(current *api.ClientConfig) error {
+	if current.Host == "" {
+		if err := survey.AskOne(&survey.Input{
+			Message: "Host",
+			Help:    "This is the Lenses URL, i.e https://lenses:9991.",
+		}, &current.Host, survey.WithValidator(survey.Required)); err != nil {
+			return err
+		}
+	}
+
+	if current.Authentication != nil {
+		if auth, ok := current.IsBasicAuth(); !ok || (auth.Username != "" && auth.Password != "") {
+			return nil
+		}
+	}
+
+	auth, _ := current.IsBasicAuth()
+	qs := []*survey.Question{
+		{
+			Name: "username",
+			Prompt: &survey.Input{
+				Message: "Username",
+				Default: auth.Username,
+				Help:    "This is the user credential used for gain access to the API.",
+			},
+			Validate: survey.Required,
+		},
+		{
+			Name: "password",
+			Prompt: &survey.Password{
+				Message: "Password",
+				Help:    "This is the user's password credential, necessary to gain access to the API.",
+			},
+			Validate: survey.Required,
+		},
+	}
+
+	if err := survey.Ask(qs, &auth); err != nil {
+		return err
+	}
+
+	current.Authentication = auth
+	return nil
+}
+
 func isValidConfigurationContext(name string) bool {
 	currentContext := config.Manager.Config.CurrentContext
 	config.Manager.Config.SetCurrent(name)
@@ -1,6 +1,10 @@
 package user
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/kataras/golog"
 
 	"github.com/lensesio/bite"
@@ -118,3 +122,50 @@ func NewDeleteUserProfilePropertyValueCommand() *cobra.Command {
 
 	return cmd
 }
+
+// whoami is the printable view of the `api.CurrentUser`.
+type whoami struct {
+	Name        string   `json:"user" header:"Name"`
+	Roles       []string `json:"roles" header:"Roles"`
+	Permissions []string `json:"permissions" header:"Permissions"`
+	TokenExpiry string   `json:"tokenExpiry,omitempty" header:"Token Expiry"`
+}
+
+//NewWhoAmICommand creates `whoami` command
+func NewWhoAmICommand() *cobra.Command {
+	var require []string
+
+	cmd := &cobra.Command{
+		Use:   "whoami",
+		Short: "Print the authenticated user, its roles, permissions and the token's expiry",
+		Long: `Print the authenticated user, its roles, permissions and the token's expiry.
+Use --require to fail, before a bulk operation, if the user misses any of the given permissions.`,
+		Example: `whoami
+whoami --require=CreateTopic --require=DeleteTopic --output=json`,
+		TraverseChildren: true,
+		SilenceErrors:    true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			user, err := config.Client.GetCurrentUser()
+			if err != nil {
+				return err
+			}
+
+			if missing := user.MissingPermissions(require...); len(missing) > 0 {
+				return fmt.Errorf("user [%s] misses the required permissions [%s]", user.Name, strings.Join(missing, ", "))
+			}
+
+			out := whoami{Name: user.Name, Roles: user.Roles, Permissions: user.Permissions}
+			if user.TokenExpiry != nil {
+				out.TokenExpiry = user.TokenExpiry.Format(time.RFC3339)
+			}
+
+			return bite.PrintObject(cmd, out)
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&require, "require", nil, "A permission the user must have, the command fails otherwise, repeat it for more")
+
+	bite.CanPrintJSON(cmd)
+
+	return cmd
+}
@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jmespath/go-jmespath"
+	"github.com/lensesio/bite"
+	"github.com/spf13/cobra"
+)
+
+// queryFlag is the JMESPath `--query` flag of the commands which print JSON, see `bite.CanPrintJSON`.
+const queryFlag = "query"
+
+// queryWriter applies a JMESPath expression to each JSON document written to it, the rest of the writes pass through.
+// Each write is expected to be a whole document, as the JSON and the NDJSON printers write them.
+type queryWriter struct {
+	w      io.Writer
+	query  *jmespath.JMESPath
+	pretty bool
+	// stream skips the documents without a result, so a filter expression drops the records it does not match.
+	stream bool
+}
+
+// NewQueryWriter returns a writer which writes the result of the JMESPath "query" on each JSON document written to it to "w".
+// The expression is evaluated on the JSON form of the documents, so its fields are the ones printed.
+// The documents without a result are skipped if "stream" is true, i.e the records of a query which do not match a filter.
+func NewQueryWriter(w io.Writer, query string, pretty, stream bool) (io.Writer, error) {
+	compiled, err := jmespath.Compile(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query [%s]: [%v]", query, err)
+	}
+
+	return &queryWriter{w: w, query: compiled, pretty: pretty, stream: stream}, nil
+}
+
+func (q *queryWriter) Write(p []byte) (int, error) {
+	doc := bytes.TrimSpace(p)
+	if len(doc) == 0 || !json.Valid(doc) {
+		return q.w.Write(p)
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(doc, &v); err != nil {
+		return 0, err
+	}
+
+	result, err := q.query.Search(v)
+	if err != nil {
+		return 0, fmt.Errorf("query: [%v]", err)
+	}
+
+	if result == nil && q.stream {
+		return len(p), nil
+	}
+
+	var b []byte
+	if q.pretty {
+		b, err = json.MarshalIndent(result, "", "  ")
+	} else {
+		b, err = json.Marshal(result)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err = q.w.Write(append(b, '\n')); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Flush flushes the underline writer, if it's buffered, see `PrintNDJSON`.
+func (q *queryWriter) Flush() error {
+	if f, ok := q.w.(interface{ Flush() error }); ok {
+		return f.Flush()
+	}
+
+	return nil
+}
+
+// ApplyJSONQuery evaluates the `--query` JMESPath expression of the "cmd", if any, on the JSON form of its output,
+// instead of on the printed objects, so the expression's fields are the printed ones and it applies to the NDJSON output too.
+// It's a no-op unless the `--output` is json or ndjson.
+func ApplyJSONQuery(cmd *cobra.Command) error {
+	query := bite.GetJSONQueryFlag(cmd)
+	if query == "" {
+		return nil
+	}
+
+	output := strings.ToLower(bite.GetOutPutFlag(cmd))
+	if output != "json" && output != OutputNDJSON {
+		return nil
+	}
+
+	w, err := NewQueryWriter(cmd.OutOrStdout(), query, bite.GetJSONPrettyFlag(cmd), output == OutputNDJSON)
+	if err != nil {
+		return err
+	}
+
+	// the printers do not evaluate it on the objects anymore.
+	if err = cmd.Flags().Set(queryFlag, ""); err != nil {
+		return err
+	}
+
+	cmd.SetOut(w)
+	return nil
+}
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/lensesio/bite"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+type queryRecord struct {
+	TopicName string          `json:"topic"`
+	Value     json.RawMessage `json:"value"`
+}
+
+func newQueryCommand(output, query string) (*cobra.Command, *bytes.Buffer) {
+	var outputValue string
+	cmd := &cobra.Command{Use: "topics"}
+	bite.RegisterOutPutFlag(cmd, &outputValue)
+	bite.CanPrintJSON(cmd)
+	cmd.Flags().Set("output", output)
+	cmd.Flags().Set("query", query)
+
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	return cmd, &out
+}
+
+func TestApplyJSONQuery(t *testing.T) {
+	cmd, out := newQueryCommand("json", "[?value.amount > `100`].topic")
+	defer cmd.Flags().Set("query", "")
+
+	assert.NoError(t, ApplyJSONQuery(cmd))
+	assert.NoError(t, bite.PrintJSON(cmd, []queryRecord{
+		{TopicName: "payments", Value: json.RawMessage(`{"amount":150}`)},
+		{TopicName: "refunds", Value: json.RawMessage(`{"amount":20}`)},
+	}))
+	assert.Equal(t, "[\"payments\"]\n", out.String())
+}
+
+func TestApplyJSONQueryNDJSON(t *testing.T) {
+	cmd, out := newQueryCommand(OutputNDJSON, "[@][?value.amount > `100`] | [0].value")
+	defer cmd.Flags().Set("query", "")
+
+	assert.NoError(t, ApplyJSONQuery(cmd))
+	assert.NoError(t, PrintNDJSON(cmd, queryRecord{TopicName: "payments", Value: json.RawMessage(`{"amount":150}`)}))
+	assert.NoError(t, PrintNDJSON(cmd, queryRecord{TopicName: "payments", Value: json.RawMessage(`{"amount":20}`)}))
+	assert.Equal(t, "{\"amount\":150}\n", out.String())
+}
+
+func TestApplyJSONQueryInvalid(t *testing.T) {
+	cmd, _ := newQueryCommand("json", "[?")
+	defer cmd.Flags().Set("query", "")
+
+	assert.Error(t, ApplyJSONQuery(cmd))
+}
+
+func TestQueryWriterPassThrough(t *testing.T) {
+	var out bytes.Buffer
+	w, err := NewQueryWriter(&out, "id", false, false)
+	assert.NoError(t, err)
+
+	w.Write([]byte("Topic created\n"))
+	w.Write([]byte(`{"id":1}` + "\n"))
+	assert.Equal(t, "Topic created\n1\n", out.String())
+}
@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/lensesio/bite"
+	"github.com/spf13/cobra"
+)
+
+// OutputNDJSON is the `--output` of the streaming commands that writes each record or event as a single line JSON object.
+const OutputNDJSON = "ndjson"
+
+//IsNDJSON reports whether the `--output` of the command is "ndjson"
+func IsNDJSON(cmd *cobra.Command) bool {
+	return strings.ToLower(bite.GetOutPutFlag(cmd)) == OutputNDJSON
+}
+
+//PrintNDJSON writes "v" as a single line JSON object to the command's output and flushes it, if the output is buffered
+func PrintNDJSON(cmd *cobra.Command, v interface{}) error {
+	out := cmd.OutOrStdout()
+
+	enc := json.NewEncoder(out)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+
+	if f, ok := out.(interface{ Flush() error }); ok {
+		return f.Flush()
+	}
+
+	return nil
+}
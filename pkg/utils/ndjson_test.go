@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/lensesio/bite"
+	"github.com/spf13/cobra"
+)
+
+type flushBuffer struct {
+	bytes.Buffer
+	flushed int
+}
+
+func (b *flushBuffer) Flush() error {
+	b.flushed++
+	return nil
+}
+
+func TestPrintNDJSON(t *testing.T) {
+	var output string
+	cmd := &cobra.Command{Use: "query"}
+	bite.RegisterOutPutFlag(cmd, &output)
+
+	if IsNDJSON(cmd) {
+		t.Fatal("expected the default output to not be ndjson")
+	}
+	cmd.Flags().Set("output", "NDJSON")
+	if !IsNDJSON(cmd) {
+		t.Fatal("expected ndjson output")
+	}
+
+	var out flushBuffer
+	cmd.SetOut(&out)
+
+	records := []interface{}{
+		map[string]interface{}{"value": json.RawMessage("{\n  \"id\": 1\n}"), "url": "a&b"},
+		[]int{1, 2},
+	}
+	for _, rec := range records {
+		if err := PrintNDJSON(cmd, rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if expected, got := "{\"url\":\"a&b\",\"value\":{\"id\":1}}\n[1,2]\n", out.String(); expected != got {
+		t.Fatalf("expected:\n%s\nbut got:\n%s", expected, got)
+	}
+	if out.flushed != 2 {
+		t.Fatalf("expected a flush per line but got %d", out.flushed)
+	}
+}
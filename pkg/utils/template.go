@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/lensesio/bite"
+	"github.com/lensesio/lenses-go/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+// DefaultTemplatesDir is the directory of the output templates of the commands, see `ApplyTemplate`.
+var DefaultTemplatesDir = filepath.Join(api.DefaultConfigurationHomeDir, "templates")
+
+// templateFuncs are the functions of the output templates, in addition to the builtin ones of the text/template package.
+var templateFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	// join joins the items of a list, i.e `{{join .partitions ", "}}`.
+	"join": func(items []interface{}, sep string) string {
+		s := make([]string, len(items))
+		for i, item := range items {
+			s[i] = fmt.Sprint(item)
+		}
+		return strings.Join(s, sep)
+	},
+}
+
+// templateName returns the name of the template file of the "cmd", its command path without the root
+// joined by dashes, i.e "topics.tmpl" or "connector-status.tmpl".
+func templateName(cmd *cobra.Command) string {
+	path := strings.Fields(cmd.CommandPath())
+	if len(path) > 1 {
+		path = path[1:]
+	}
+
+	return strings.Join(path, "-") + ".tmpl"
+}
+
+// templateWriter renders each JSON document written to it by a template, the rest of the writes pass through.
+type templateWriter struct {
+	w    io.Writer
+	tmpl *template.Template
+}
+
+// NewTemplateWriter returns a writer which writes each JSON document written to it rendered by the "tmpl" to "w".
+func NewTemplateWriter(w io.Writer, tmpl *template.Template) io.Writer {
+	return &templateWriter{w: w, tmpl: tmpl}
+}
+
+func (t *templateWriter) Write(p []byte) (int, error) {
+	doc := bytes.TrimSpace(p)
+	if len(doc) == 0 || !json.Valid(doc) {
+		return t.w.Write(p)
+	}
+
+	var v interface{}
+	decoder := json.NewDecoder(bytes.NewReader(doc))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err != nil {
+		return 0, err
+	}
+
+	if err := t.tmpl.Execute(t.w, v); err != nil {
+		return 0, fmt.Errorf("template [%s]: [%v]", t.tmpl.Name(), err)
+	}
+
+	return len(p), nil
+}
+
+// ApplyTemplate renders the output of the "cmd" by its template of the `DefaultTemplatesDir`, if any, see `templateName`.
+// The template is executed on the JSON form of each printed object, so its fields are the ones of `--output json`.
+// It's a no-op if the `--output` flag is passed, so `--output table` prints the default table.
+func ApplyTemplate(cmd *cobra.Command) error {
+	if f := cmd.Flags().Lookup(bite.GetOutPutFlagKey()); f == nil || f.Changed {
+		return nil
+	}
+
+	path := filepath.Join(DefaultTemplatesDir, templateName(cmd))
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	tmpl, err := template.New(filepath.Base(path)).Funcs(templateFuncs).Parse(string(b))
+	if err != nil {
+		return fmt.Errorf("invalid template [%s]: [%v]", path, err)
+	}
+
+	if err = cmd.Flags().Set(bite.GetOutPutFlagKey(), "json"); err != nil {
+		return err
+	}
+
+	cmd.SetOut(NewTemplateWriter(cmd.OutOrStdout(), tmpl))
+	return nil
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lensesio/bite"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyTemplate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lenses-cli-templates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	defaultTemplatesDir := DefaultTemplatesDir
+	DefaultTemplatesDir = dir
+	defer func() { DefaultTemplatesDir = defaultTemplatesDir }()
+
+	tmpl := `{{range .}}{{upper .topic}}: {{.partitions}} partitions, {{join .tags ", "}}
+{{end}}`
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "topic-list.tmpl"), []byte(tmpl), 0600))
+
+	newCommand := func(args ...string) (*cobra.Command, *bytes.Buffer) {
+		var output string
+		root := &cobra.Command{Use: "lenses-cli"}
+		bite.RegisterOutPutFlagTo(root.PersistentFlags(), &output)
+		list := &cobra.Command{Use: "list", Run: func(*cobra.Command, []string) {}}
+		topic := &cobra.Command{Use: "topic"}
+		topic.AddCommand(list)
+		root.AddCommand(topic)
+
+		root.SetArgs(append([]string{"topic", "list"}, args...))
+		cmd, err := root.ExecuteC()
+		require.NoError(t, err)
+
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		return cmd, &out
+	}
+
+	topics := []map[string]interface{}{
+		{"topic": "payments", "partitions": 3, "tags": []string{"pii", "finance"}},
+		{"topic": "orders", "partitions": 12, "tags": []string{}},
+	}
+
+	cmd, out := newCommand()
+	require.NoError(t, ApplyTemplate(cmd))
+	assert.Equal(t, "json", bite.GetOutPutFlag(cmd))
+	require.NoError(t, bite.PrintObject(cmd, topics))
+	assert.Equal(t, "PAYMENTS: 3 partitions, pii, finance\nORDERS: 12 partitions, \n", out.String())
+
+	// an explicit output prints the default.
+	cmd, out = newCommand("--output", "json")
+	require.NoError(t, ApplyTemplate(cmd))
+	require.NoError(t, bite.PrintObject(cmd, topics))
+	assert.Contains(t, out.String(), `"topic":"payments"`)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "topic-list.tmpl"), []byte("{{.topic"), 0600))
+	cmd, _ = newCommand()
+	assert.Error(t, ApplyTemplate(cmd))
+}
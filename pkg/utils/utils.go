@@ -95,6 +95,33 @@ func EncryptString(plain string, keyBase string) (string, error) {
 	return base64.URLEncoding.EncodeToString(encrypted), nil
 }
 
+var varExpr = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_.-]*)\}`)
+
+//ExpandVars replaces the `${name}` of "s" with the value of "lookup", `$${name}` is kept as a literal `${name}`.
+//It fails with the names that "lookup" does not find.
+func ExpandVars(s string, lookup func(name string) (string, bool)) (string, error) {
+	var missing []string
+	out := varExpr.ReplaceAllStringFunc(s, func(expr string) string {
+		if strings.HasPrefix(expr, "$$") {
+			return expr[1:]
+		}
+
+		name := varExpr.FindStringSubmatch(expr)[1]
+		if v, ok := lookup(name); ok {
+			return v
+		}
+
+		missing = append(missing, name)
+		return expr
+	})
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("undefined variable [%s]", strings.Join(missing, ", "))
+	}
+
+	return out, nil
+}
+
 //Fetch data from a file with a provided prefix
 func Fetch(fromFile, prefix string) ([]string, error) {
 	var vars []string
@@ -166,6 +193,25 @@ func isValidImportFile(fileName string) bool {
 	return regex.MatchString(fileName)
 }
 
+//ParsePatch reads a JSON merge patch, the "raw" JSON object or the path of a file that contains it.
+func ParsePatch(raw string) (map[string]interface{}, error) {
+	b := []byte(raw)
+	if trimmed := strings.TrimSpace(raw); trimmed != "" && !strings.HasPrefix(trimmed, "{") {
+		contents, err := os.ReadFile(trimmed)
+		if err != nil {
+			return nil, err
+		}
+		b = contents
+	}
+
+	var patch map[string]interface{}
+	if err := json.Unmarshal(b, &patch); err != nil || patch == nil {
+		return nil, fmt.Errorf("invalid patch, a JSON object is expected, i.e {\"retention.ms\": \"86400000\"}")
+	}
+
+	return patch, nil
+}
+
 //PrintLogLines prints lines as logs
 func PrintLogLines(logs []api.LogLine) error {
 	golog.SetTimeFormat("")
@@ -0,0 +1,169 @@
+package websocket
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// AckWindowHeader is the handshake header which requests the at-least-once delivery, with the
+// `LiveConfiguration.AckWindow` as its value. A server which supports the acknowledgements sends it back.
+const AckWindowHeader = "X-Lenses-Ack-Window"
+
+// The flow control messages sent to the server when the acknowledgements are enabled.
+const (
+	// AckRequest acknowledges a record, the server does not redeliver it.
+	AckRequest = "ACK"
+	// NackRequest rejects a record, the server redelivers it.
+	NackRequest = "NACK"
+	// PauseRequest stops the delivery of the records, it's sent when the unacknowledged records reach the window.
+	PauseRequest = "PAUSE"
+	// ResumeRequest resumes the delivery of the records, it's sent when the half of the window is acknowledged.
+	ResumeRequest = "RESUME"
+)
+
+// ErrAckUnsupported is returned by the `LiveResponse.Ack` and `Nack`
+// when the acknowledgements are not enabled or the server does not support them.
+var ErrAckUnsupported = errors.New("live: acknowledgements are not supported")
+
+type (
+	ackRequest struct {
+		Type string   `json:"type"`
+		Data *ackData `json:"data,omitempty"`
+	}
+
+	ackData struct {
+		Partition int `json:"partition"`
+		Offset    int `json:"offset"`
+	}
+)
+
+// acker keeps the unacknowledged records of a connection and pauses and resumes their delivery.
+type acker struct {
+	c      *LiveConnection
+	conn   *websocket.Conn // the acknowledgements belong to the connection that delivered the records.
+	window int
+
+	mu      sync.Mutex
+	unacked int
+	paused  bool
+}
+
+// newAcker returns the acker of the "conn", if the server accepted the `AckWindowHeader` of the handshake.
+func (c *LiveConnection) newAcker(conn *websocket.Conn, resp *http.Response) *acker {
+	if c.config.AckWindow <= 0 || resp == nil || resp.Header.Get(AckWindowHeader) == "" {
+		return nil
+	}
+
+	return &acker{c: c, conn: conn, window: c.config.AckWindow}
+}
+
+func (c *LiveConnection) ackHeader() string {
+	if c.config.AckWindow <= 0 {
+		return ""
+	}
+
+	return strconv.Itoa(c.config.AckWindow)
+}
+
+func (a *acker) send(typ string, data *ackData) error {
+	return a.c.write(a.conn, ackRequest{Type: typ, Data: data})
+}
+
+// delivered counts a delivered record and pauses the delivery when the window is full.
+func (a *acker) delivered() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.unacked++
+	if a.paused || a.unacked < a.window {
+		return nil
+	}
+
+	a.paused = true
+	return a.send(PauseRequest, nil)
+}
+
+// settle acknowledges, or rejects, a delivered record and resumes the delivery when the half of the window is free.
+func (a *acker) settle(typ string, metadata MetaData) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.unacked > 0 {
+		a.unacked--
+	}
+
+	if err := a.send(typ, &ackData{Partition: metadata.Partition, Offset: metadata.Offset}); err != nil {
+		return err
+	}
+
+	if !a.paused || a.unacked > a.window/2 {
+		return nil
+	}
+
+	a.paused = false
+	return a.send(ResumeRequest, nil)
+}
+
+// drop acknowledges a record which was not delivered to the listeners, i.e sampled out.
+func (a *acker) drop(metadata MetaData) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.send(AckRequest, &ackData{Partition: metadata.Partition, Offset: metadata.Offset})
+}
+
+func (a *acker) pending() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.unacked
+}
+
+// Ack acknowledges the record, so the server does not redeliver it, see `LiveConfiguration.AckWindow`.
+// It does nothing for the other response types.
+func (resp LiveResponse) Ack() error {
+	return resp.settle(AckRequest)
+}
+
+// Nack rejects the record, so the server redelivers it, see `LiveConfiguration.AckWindow`.
+// It does nothing for the other response types.
+func (resp LiveResponse) Nack() error {
+	return resp.settle(NackRequest)
+}
+
+func (resp LiveResponse) settle(typ string) error {
+	if resp.Type != RecordMessageResponse {
+		return nil
+	}
+
+	if resp.acks == nil {
+		return ErrAckUnsupported
+	}
+
+	return resp.acks.settle(typ, resp.Data.Metadata)
+}
+
+// Acks reports whether the server accepted the acknowledgements of the current connection, see `LiveConfiguration.AckWindow`.
+func (c *LiveConnection) Acks() bool {
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
+
+	return c.acks != nil
+}
+
+// Unacked returns the number of the delivered records of the current connection which are not acknowledged yet.
+func (c *LiveConnection) Unacked() int {
+	c.connMu.Lock()
+	acks := c.acks
+	c.connMu.Unlock()
+
+	if acks == nil {
+		return 0
+	}
+
+	return acks.pending()
+}
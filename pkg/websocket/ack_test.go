@@ -0,0 +1,122 @@
+package websocket
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+// newAckTestServer starts a websocket server which, if "ack", accepts the acknowledgements,
+// writes the "messages", once "start" is closed, and then sends the flow control messages it receives to the "requests".
+func newAckTestServer(t *testing.T, ack bool, start <-chan struct{}, requests chan<- ackRequest, messages ...string) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var header http.Header
+		if ack {
+			header = http.Header{AckWindowHeader: {r.Header.Get(AckWindowHeader)}}
+		}
+
+		conn, err := upgrader.Upgrade(w, r, header)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+
+		var msg Message
+		if err = conn.ReadJSON(&msg); err != nil {
+			return
+		}
+
+		<-start
+		for _, m := range messages {
+			if err = conn.WriteMessage(websocket.TextMessage, []byte(m)); err != nil {
+				return
+			}
+		}
+
+		for {
+			var req ackRequest
+			if err = conn.ReadJSON(&req); err != nil {
+				return
+			}
+			requests <- req
+		}
+	}))
+}
+
+func TestLiveConnectionAck(t *testing.T) {
+	start := make(chan struct{})
+	requests := make(chan ackRequest, 10)
+	srv := newAckTestServer(t, true, start, requests,
+		`{"type":"RECORD","data":{"value":1,"metadata":{"partition":0,"offset":1}}}`,
+		`{"type":"RECORD","data":{"value":2,"metadata":{"partition":0,"offset":2}}}`,
+	)
+	defer srv.Close()
+
+	conn := openTestConnection(t, srv, LiveConfiguration{AckWindow: 2})
+	defer conn.Close()
+	assert.True(t, conn.Acks())
+
+	records := make(chan LiveResponse, 2)
+	conn.OnRecordMessage(func(resp LiveResponse) error {
+		records <- resp
+		return nil
+	})
+	close(start)
+
+	next := func() ackRequest {
+		select {
+		case req := <-requests:
+			return req
+		case err := <-conn.Err():
+			t.Fatal(err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout")
+		}
+		return ackRequest{}
+	}
+
+	// the window is full.
+	assert.Equal(t, ackRequest{Type: PauseRequest}, next())
+	assert.Equal(t, 2, conn.Unacked())
+
+	first, second := <-records, <-records
+	assert.NoError(t, first.Ack())
+	assert.Equal(t, ackRequest{Type: AckRequest, Data: &ackData{Partition: 0, Offset: 1}}, next())
+	assert.Equal(t, ackRequest{Type: ResumeRequest}, next())
+
+	assert.NoError(t, second.Nack())
+	assert.Equal(t, ackRequest{Type: NackRequest, Data: &ackData{Partition: 0, Offset: 2}}, next())
+	assert.Equal(t, 0, conn.Unacked())
+}
+
+func TestLiveConnectionAckUnsupported(t *testing.T) {
+	start := make(chan struct{})
+	requests := make(chan ackRequest, 1)
+	srv := newAckTestServer(t, false, start, requests, `{"type":"RECORD","data":{"value":1}}`)
+	defer srv.Close()
+
+	conn := openTestConnection(t, srv, LiveConfiguration{AckWindow: 2})
+	defer conn.Close()
+	assert.False(t, conn.Acks())
+
+	records := make(chan LiveResponse, 1)
+	conn.OnRecordMessage(func(resp LiveResponse) error {
+		records <- resp
+		return nil
+	})
+	close(start)
+
+	select {
+	case resp := <-records:
+		assert.Equal(t, ErrAckUnsupported, resp.Ack())
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+}
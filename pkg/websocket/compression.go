@@ -0,0 +1,69 @@
+package websocket
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync/atomic"
+)
+
+// TransferStats reports the bytes received by a live connection,
+// see `LiveConnection.TransferStats`.
+type TransferStats struct {
+	// WireBytes is the number of bytes read from the network, including the handshake and the websocket framing.
+	WireBytes uint64 `json:"wireBytes" header:"Wire Bytes"`
+	// MessageBytes is the number of bytes of the decoded (and decompressed) messages.
+	MessageBytes uint64 `json:"messageBytes" header:"Message Bytes"`
+}
+
+// Savings returns the fraction of the message bytes that was not transferred over the network,
+// i.e 0.8 when compression made the messages five times smaller.
+// It's zero, or negative due to the framing overhead, when compression is disabled.
+func (s TransferStats) Savings() float64 {
+	if s.MessageBytes == 0 {
+		return 0
+	}
+
+	return 1 - float64(s.WireBytes)/float64(s.MessageBytes)
+}
+
+// TransferStats returns the bytes received so far, across reconnects.
+// Compare its `WireBytes` and `MessageBytes` to measure the `LiveConfiguration.EnableCompression` savings.
+func (c *LiveConnection) TransferStats() TransferStats {
+	return TransferStats{
+		WireBytes:    atomic.LoadUint64(&c.wireBytes),
+		MessageBytes: atomic.LoadUint64(&c.messageBytes),
+	}
+}
+
+// dialCounting dials the TCP connection and counts the bytes read from it.
+func (c *LiveConnection) dialCounting(ctx context.Context, network, addr string) (net.Conn, error) {
+	conn, err := new(net.Dialer).DialContext(ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &countingConn{Conn: conn, n: &c.wireBytes}, nil
+}
+
+type countingConn struct {
+	net.Conn
+	n *uint64
+}
+
+func (c *countingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	atomic.AddUint64(c.n, uint64(n))
+	return n, err
+}
+
+type countingReader struct {
+	io.Reader
+	n *uint64
+}
+
+func (r *countingReader) Read(b []byte) (int, error) {
+	n, err := r.Reader.Read(b)
+	atomic.AddUint64(r.n, uint64(n))
+	return n, err
+}
@@ -0,0 +1,109 @@
+package websocket
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	test "github.com/lensesio/lenses-go/test"
+)
+
+// verboseRecord is a JSON record with repetitive field names and values, as most topics have.
+var verboseRecord = fmt.Sprintf(`{"type":"RECORD","data":{"key":"customer-42","value":{"description":%q,"status":"ACTIVE","country":"GB"},"metadata":{"timestamp":1600000000000,"partition":0,"offset":1}}}`,
+	strings.Repeat("the quick brown fox jumps over the lazy dog ", 20))
+
+// newCompressionTestServer starts a websocket server which, when the client negotiated it,
+// compresses the "n" verbose records that it writes after reading the query message.
+func newCompressionTestServer(tb testing.TB, n int) *httptest.Server {
+	upgrader := websocket.Upgrader{EnableCompression: true}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			tb.Error(err)
+			return
+		}
+		defer conn.Close()
+
+		var msg Message
+		if err = conn.ReadJSON(&msg); err != nil {
+			return
+		}
+
+		for i := 0; i < n; i++ {
+			if err = conn.WriteMessage(websocket.TextMessage, []byte(verboseRecord)); err != nil {
+				return
+			}
+		}
+
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"END"}`))
+		conn.ReadMessage()
+	}))
+}
+
+// receiveAll reads the "n" records of the server and returns the connection's transfer stats.
+func receiveAll(tb testing.TB, srv *httptest.Server, compress bool, n int) TransferStats {
+	conn, err := OpenLiveConnection(LiveConfiguration{Host: srv.URL, EnableCompression: compress})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	defer conn.Close()
+
+	end := make(chan struct{})
+	conn.OnEnd(func(LiveResponse) error {
+		close(end)
+		return nil
+	})
+
+	select {
+	case <-end:
+	case err := <-conn.Err():
+		tb.Fatal(err)
+	case <-time.After(10 * time.Second):
+		tb.Fatal("timeout")
+	}
+
+	return conn.TransferStats()
+}
+
+func TestLiveConnectionCompression(t *testing.T) {
+	test.SetupMasterContext()
+
+	srv := newCompressionTestServer(t, 100)
+	defer srv.Close()
+
+	plain := receiveAll(t, srv, false, 100)
+	compressed := receiveAll(t, srv, true, 100)
+
+	if plain.MessageBytes != compressed.MessageBytes {
+		t.Fatalf("expected the same message bytes but got [%d] and [%d]", plain.MessageBytes, compressed.MessageBytes)
+	}
+
+	if plain.Savings() > 0 {
+		t.Fatalf("expected no savings without compression but got [%f]", plain.Savings())
+	}
+
+	if compressed.Savings() < 0.5 {
+		t.Fatalf("expected compression to save more than half of the bytes but saved [%f]", compressed.Savings())
+	}
+}
+
+func BenchmarkLiveConnectionCompression(b *testing.B) {
+	test.SetupMasterContext()
+
+	for _, compress := range []bool{false, true} {
+		b.Run(fmt.Sprintf("compress=%v", compress), func(b *testing.B) {
+			srv := newCompressionTestServer(b, b.N)
+			defer srv.Close()
+
+			b.ResetTimer()
+			stats := receiveAll(b, srv, compress, b.N)
+			b.ReportMetric(float64(stats.WireBytes)/float64(b.N), "wire-B/msg")
+			b.ReportMetric(stats.Savings()*100, "%saved")
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package websocket
+
+// DeadLetter is a response that a listener failed to handle, see `LiveConfiguration.DeadLetter`.
+type DeadLetter struct {
+	// Response is the failed response, it's a copy that can be kept, i.e to retry it later, even if `ReuseBuffers` is set.
+	Response LiveResponse
+	// Err is the error returned by the listener.
+	Err error
+	// Listener is the identifier of the failed listener, as returned by `On`, `OnAsync` or `Once`.
+	Listener ListenerID
+	// Async reports whether the listener was added by `OnAsync`.
+	Async bool
+}
+
+// Error returns the listener's error.
+func (d DeadLetter) Error() string {
+	return d.Err.Error()
+}
+
+// Unwrap returns the listener's error.
+func (d DeadLetter) Unwrap() error {
+	return d.Err
+}
+
+// DeadLetterChannel returns a `LiveConfiguration.DeadLetter` which sends the dead letters to the "ch",
+// it blocks the reader, or the worker of the asynchronous listener, while the "ch" is full.
+func DeadLetterChannel(ch chan<- DeadLetter) func(DeadLetter) {
+	return func(d DeadLetter) {
+		ch <- d
+	}
+}
+
+// listenerFailed reports the "err" of the "sub" listener for the "resp" to the `DeadLetter`, if set, or to the `Err` channel.
+func (c *LiveConnection) listenerFailed(sub subscription, resp LiveResponse, async bool, err error) {
+	if c.config.DeadLetter == nil {
+		c.sendErr(err)
+		return
+	}
+
+	c.config.DeadLetter(DeadLetter{
+		Response: c.detach(resp),
+		Err:      err,
+		Listener: sub.id,
+		Async:    async,
+	})
+}
@@ -0,0 +1,44 @@
+package websocket
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLiveConnectionDeadLetter(t *testing.T) {
+	start := make(chan struct{})
+	srv := newTestServer(t, start,
+		`{"type":"RECORD","data":{"key":"a","value":{"id":1}}}`,
+		`{"type":"RECORD","data":{"key":"b","value":{"id":2}}}`,
+	)
+	defer srv.Close()
+
+	deadLetters := make(chan DeadLetter, 2)
+	conn := openTestConnection(t, srv, LiveConfiguration{ReuseBuffers: true, DeadLetter: DeadLetterChannel(deadLetters)})
+	defer conn.Close()
+
+	errBad := errors.New("bad record")
+	id := conn.OnRecordMessage(func(resp LiveResponse) error {
+		if string(resp.Data.Key) == `"b"` {
+			return errBad
+		}
+		return nil
+	})
+	close(start)
+
+	select {
+	case d := <-deadLetters:
+		assert.Equal(t, id, d.Listener)
+		assert.False(t, d.Async)
+		assert.True(t, errors.Is(d, errBad))
+		assert.Equal(t, `"b"`, string(d.Response.Data.Key))
+		assert.JSONEq(t, `{"id":2}`, string(d.Response.Data.Value))
+	case err := <-conn.Err():
+		t.Fatalf("expected a dead letter, got error: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+}
@@ -0,0 +1,79 @@
+package websocket
+
+import (
+	hexdump "encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+
+	"github.com/gorilla/websocket"
+	"github.com/lensesio/lenses-go/pkg/api"
+)
+
+// debugHandshake writes the handshake request and its response to the `DebugOut`, with the tokens redacted.
+func (c *LiveConnection) debugHandshake(header http.Header, resp *http.Response, err error) {
+	out := c.config.DebugOut
+	if out == nil {
+		return
+	}
+
+	fmt.Fprintf(out, ">>> GET %s\n", c.endpoint)
+	writeHeader(out, api.RedactHeader(header))
+	if protocols := c.config.subprotocols(); len(protocols) > 0 {
+		fmt.Fprintf(out, "Sec-WebSocket-Protocol: %v\n", protocols)
+	}
+
+	if resp != nil {
+		fmt.Fprintf(out, "<<< %s\n", resp.Status)
+		writeHeader(out, api.RedactHeader(resp.Header))
+	}
+
+	if err != nil {
+		fmt.Fprintf(out, "<<< handshake failed: %v\n", err)
+	}
+	fmt.Fprintln(out)
+}
+
+func writeHeader(out io.Writer, header http.Header) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, v := range header[name] {
+			fmt.Fprintf(out, "%s: %s\n", name, v)
+		}
+	}
+}
+
+// debugSent writes the sent "message" to the `DebugOut`, with its token redacted.
+func (c *LiveConnection) debugSent(message interface{}) {
+	if c.config.DebugOut == nil {
+		return
+	}
+
+	b, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+
+	fmt.Fprintf(c.config.DebugOut, "> %s\n", api.RedactJSON(b))
+}
+
+// debugFrame writes a received frame to the `DebugOut`, a binary one, i.e MessagePack, as a hex dump.
+func (c *LiveConnection) debugFrame(typ int, frame []byte) {
+	if c.config.DebugOut == nil {
+		return
+	}
+
+	if typ == websocket.BinaryMessage {
+		fmt.Fprintf(c.config.DebugOut, "< binary message of %d bytes\n%s", len(frame), hexdump.Dump(frame))
+		return
+	}
+
+	fmt.Fprintf(c.config.DebugOut, "< %s\n", api.RedactJSON(frame))
+}
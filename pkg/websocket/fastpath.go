@@ -0,0 +1,188 @@
+package websocket
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// RawListener is the declaration for the raw subscriber, it receives the undecoded frame of every message,
+// before any `LiveListener` and regardless of the message's type.
+// The "frame" is reused for the next message, it must be copied if it's kept after the call.
+//
+// See `OnRaw` too.
+type RawListener func(frame []byte) error
+
+type rawSubscription struct {
+	id ListenerID
+	cb RawListener
+}
+
+// OnRaw adds a raw listener. When the connection has raw listeners only, the messages are never decoded,
+// which is the cheapest way to forward the messages as they are, i.e to a file.
+//
+// It returns the listener's identifier which can be used to remove it, see `OffRaw`.
+func (c *LiveConnection) OnRaw(cb RawListener) ListenerID {
+	id := c.nextID()
+
+	c.mu.Lock()
+	c.rawListeners = append(c.rawListeners[:len(c.rawListeners):len(c.rawListeners)], rawSubscription{id, cb})
+	c.mu.Unlock()
+
+	return id
+}
+
+// OffRaw removes the raw listener of "id", as returned by `OnRaw`.
+// It reports whether a listener was removed.
+func (c *LiveConnection) OffRaw(id ListenerID) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, sub := range c.rawListeners {
+		if sub.id == id {
+			// copy instead of removing in place, the reader may still range over the old slice.
+			subs := make([]rawSubscription, 0, len(c.rawListeners)-1)
+			subs = append(subs, c.rawListeners[:i]...)
+			c.rawListeners = append(subs, c.rawListeners[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasListeners reports whether there are raw listeners and whether there are listeners of decoded messages.
+func (c *LiveConnection) hasListeners() (raw bool, decoded bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, subs := range c.listeners {
+		if len(subs) > 0 {
+			decoded = true
+			break
+		}
+	}
+
+	if !decoded {
+		for _, subs := range c.asyncListeners {
+			if len(subs) > 0 {
+				decoded = true
+				break
+			}
+		}
+	}
+
+	return len(c.rawListeners) > 0, decoded
+}
+
+func (c *LiveConnection) fireRaw(frame []byte) {
+	c.mu.RLock()
+	subs := c.rawListeners
+	c.mu.RUnlock()
+
+	for _, sub := range subs {
+		if err := sub.cb(frame); err != nil {
+			c.sendErr(err)
+		}
+	}
+}
+
+// readFrame reads the whole message of "r" into the connection's reusable frame buffer.
+func (c *LiveConnection) readFrame(r io.Reader) ([]byte, error) {
+	b := c.frame[:0]
+	if cap(b) == 0 {
+		b = make([]byte, 0, 512)
+	}
+
+	for {
+		if len(b) == cap(b) {
+			b = append(b, 0)[:len(b)]
+		}
+
+		n, err := r.Read(b[len(b):cap(b)])
+		b = b[:len(b)+n]
+		if err != nil {
+			c.frame = b
+			if err == io.EOF {
+				err = nil
+			}
+			return b, err
+		}
+	}
+}
+
+// decodeJSON decodes a single response from the "r" message reader.
+func (c *LiveConnection) decodeJSON(r io.Reader, resp *LiveResponse) error {
+	dec := json.NewDecoder(r)
+	if c.config.UseNumber {
+		dec.UseNumber()
+	}
+
+	err := dec.Decode(resp)
+	if err == io.EOF {
+		// each message should contain exactly one response.
+		err = io.ErrUnexpectedEOF
+	}
+
+	return err
+}
+
+// decodeJSONFrame decodes the whole message "frame", without allocating a decoder unless `UseNumber` is required.
+func (c *LiveConnection) decodeJSONFrame(frame []byte, resp *LiveResponse) error {
+	if !c.config.UseNumber {
+		return json.Unmarshal(frame, resp)
+	}
+
+	c.frameSrc.Reset(frame)
+	return c.decodeJSON(&c.frameSrc, resp)
+}
+
+// acquireResponse returns the response to decode the next message into.
+// It's the connection's reusable response, with its key and value buffers, if `LiveConfiguration.ReuseBuffers` is true.
+func (c *LiveConnection) acquireResponse() *LiveResponse {
+	if !c.config.ReuseBuffers {
+		return new(LiveResponse)
+	}
+
+	c.resp = LiveResponse{Data: Data{Key: c.keyBuf[:0], Value: c.valueBuf[:0]}}
+	return &c.resp
+}
+
+// releaseResponse keeps the grown key and value buffers of the reusable response
+// and sets the missing key or value to nil, as a new response would have.
+func (c *LiveConnection) releaseResponse(resp *LiveResponse) {
+	if !c.config.ReuseBuffers {
+		return
+	}
+
+	if cap(resp.Data.Key) > cap(c.keyBuf) {
+		c.keyBuf = resp.Data.Key[:0]
+	}
+
+	if cap(resp.Data.Value) > cap(c.valueBuf) {
+		c.valueBuf = resp.Data.Value[:0]
+	}
+
+	if len(resp.Data.Key) == 0 {
+		resp.Data.Key = nil
+	}
+
+	if len(resp.Data.Value) == 0 {
+		resp.Data.Value = nil
+	}
+}
+
+// detach returns a copy of the response which does not share its key and value with the reusable response.
+func (c *LiveConnection) detach(resp LiveResponse) LiveResponse {
+	if c.config.ReuseBuffers {
+		resp.Data.Key = append(json.RawMessage(nil), resp.Data.Key...)
+		resp.Data.Value = append(json.RawMessage(nil), resp.Data.Value...)
+		if len(resp.Data.Key) == 0 {
+			resp.Data.Key = nil
+		}
+		if len(resp.Data.Value) == 0 {
+			resp.Data.Value = nil
+		}
+	}
+
+	return resp
+}
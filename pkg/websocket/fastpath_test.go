@@ -0,0 +1,148 @@
+package websocket
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	test "github.com/lensesio/lenses-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLiveConnectionOnRaw(t *testing.T) {
+	start := make(chan struct{})
+	srv := newTestServer(t, start,
+		`{"type":"RECORD","data":{"value":1}}`,
+		`not json`,
+		`{"type":"END"}`,
+	)
+	defer srv.Close()
+
+	conn := openTestConnection(t, srv, LiveConfiguration{})
+	defer conn.Close()
+
+	frames := make(chan string, 3)
+	conn.OnRaw(func(frame []byte) error {
+		frames <- string(frame)
+		return nil
+	})
+	close(start)
+
+	var got []string
+	for len(got) < 3 {
+		select {
+		case frame := <-frames:
+			got = append(got, frame)
+		case err := <-conn.Err():
+			// messages are not decoded when there are raw listeners only.
+			t.Fatal(err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout")
+		}
+	}
+
+	assert.Equal(t, []string{`{"type":"RECORD","data":{"value":1}}`, `not json`, `{"type":"END"}`}, got)
+}
+
+func TestLiveConnectionReuseBuffers(t *testing.T) {
+	start := make(chan struct{})
+	srv := newTestServer(t, start,
+		`{"type":"RECORD","data":{"key":"a","value":{"id":1,"name":"first"}}}`,
+		`{"type":"RECORD","data":{"value":2}}`,
+		`{"type":"RECORD","data":{"key":"c","value":3}}`,
+		`{"type":"END"}`,
+	)
+	defer srv.Close()
+
+	conn := openTestConnection(t, srv, LiveConfiguration{ReuseBuffers: true})
+	defer conn.Close()
+
+	var (
+		received []string
+		async    = make(chan string, 3)
+		end      = make(chan struct{})
+	)
+
+	conn.OnRecordMessage(func(resp LiveResponse) error {
+		received = append(received, string(resp.Data.Key)+"="+string(resp.Data.Value))
+		return nil
+	})
+	conn.OnAsync(RecordMessageResponse, func(resp LiveResponse) error {
+		// the async listeners receive copies which are safe to keep.
+		time.Sleep(10 * time.Millisecond)
+		async <- string(resp.Data.Key) + "=" + string(resp.Data.Value)
+		return nil
+	})
+	conn.OnEnd(func(LiveResponse) error {
+		close(end)
+		return nil
+	})
+	close(start)
+
+	select {
+	case <-end:
+	case err := <-conn.Err():
+		t.Fatal(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+
+	expected := []string{`"a"={"id":1,"name":"first"}`, `=2`, `"c"=3`}
+	assert.Equal(t, expected, received)
+
+	for _, exp := range expected {
+		select {
+		case got := <-async:
+			assert.Equal(t, exp, got)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout")
+		}
+	}
+}
+
+func BenchmarkLiveConnectionReadLoop(b *testing.B) {
+	test.SetupMasterContext()
+
+	run := func(b *testing.B, config LiveConfiguration, raw bool) {
+		srv := newCompressionTestServer(b, b.N)
+		defer srv.Close()
+
+		config.Host = srv.URL
+		conn, err := OpenLiveConnection(config)
+		if err != nil {
+			b.Fatal(err)
+		}
+		defer conn.Close()
+
+		end := make(chan struct{})
+		if raw {
+			conn.OnRaw(func(frame []byte) error {
+				if bytes.HasPrefix(frame, []byte(`{"type":"END"`)) {
+					close(end)
+				}
+				return nil
+			})
+		} else {
+			conn.OnRecordMessage(func(LiveResponse) error { return nil })
+			conn.OnEnd(func(LiveResponse) error {
+				close(end)
+				return nil
+			})
+		}
+
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		select {
+		case <-end:
+		case err := <-conn.Err():
+			b.Fatal(err)
+		case <-time.After(time.Minute):
+			b.Fatal("timeout")
+		}
+	}
+
+	b.Run("default", func(b *testing.B) { run(b, LiveConfiguration{}, false) })
+	b.Run("reuse", func(b *testing.B) { run(b, LiveConfiguration{ReuseBuffers: true}, false) })
+	b.Run("raw", func(b *testing.B) { run(b, LiveConfiguration{}, true) })
+}
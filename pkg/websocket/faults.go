@@ -0,0 +1,189 @@
+package websocket
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"time"
+)
+
+// ErrFaultDisconnect is the cause of the `ConnectionClosedError` of a disconnect injected by the `Faults`.
+var ErrFaultDisconnect = errors.New("live: injected disconnect")
+
+// Faults injects artificial failures into the received messages, at random, so the applications can verify
+// their resilience against the stream: disconnects, delayed messages, corrupted JSON and out of order delivery.
+// The rates are the probabilities, from 0 to 1, of each message. The `wstest.Server` injects them on the server side too.
+//
+// It's for testing only, see `LiveConfiguration.Faults`.
+type Faults struct {
+	// DisconnectRate closes the connection before a message, the reader reports a `ConnectionClosedError`
+	// of the `ErrFaultDisconnect` to the `Err` channel.
+	DisconnectRate float64
+	// DelayRate delays a message by a random duration up to the `MaxDelay`.
+	DelayRate float64
+	MaxDelay  time.Duration
+	// CorruptRate truncates a message, its JSON fails to be decoded.
+	CorruptRate float64
+	// ReorderRate holds back a message and delivers it after the next one.
+	ReorderRate float64
+	// Seed seeds the random choices, so a failing run can be repeated, zero seeds them by the time.
+	Seed int64
+}
+
+func (f Faults) validate() error {
+	for name, rate := range map[string]float64{
+		"disconnect": f.DisconnectRate,
+		"delay":      f.DelayRate,
+		"corrupt":    f.CorruptRate,
+		"reorder":    f.ReorderRate,
+	} {
+		if rate < 0 || rate > 1 {
+			return fmt.Errorf("live: %s fault rate [%v] must be between 0 and 1", name, rate)
+		}
+	}
+
+	if f.MaxDelay < 0 {
+		return fmt.Errorf("live: fault max delay [%s] must not be negative", f.MaxDelay)
+	}
+
+	return nil
+}
+
+// FaultAction is what a `FaultInjector` does to a message.
+type FaultAction struct {
+	// Disconnect closes the connection instead of delivering the message.
+	Disconnect bool
+	// Delay is the time to wait before the message is delivered.
+	Delay time.Duration
+	// Frames are the messages to deliver, none if the message is held back,
+	// or the message and the held back one after it.
+	Frames [][]byte
+}
+
+// FaultInjector chooses the faults of each message by its `Faults`, it's not safe for concurrent use.
+type FaultInjector struct {
+	faults Faults
+	rnd    *rand.Rand
+	held   []byte
+}
+
+// NewFaultInjector returns a `FaultInjector` of the "faults".
+func NewFaultInjector(faults Faults) *FaultInjector {
+	seed := faults.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	return &FaultInjector{faults: faults, rnd: rand.New(rand.NewSource(seed))}
+}
+
+func (in *FaultInjector) roll(rate float64) bool {
+	return rate > 0 && in.rnd.Float64() < rate
+}
+
+// Next returns the faults of the next message, the "frame".
+func (in *FaultInjector) Next(frame []byte) FaultAction {
+	if in.roll(in.faults.DisconnectRate) {
+		return FaultAction{Disconnect: true}
+	}
+
+	var action FaultAction
+	if in.roll(in.faults.DelayRate) && in.faults.MaxDelay > 0 {
+		action.Delay = time.Duration(in.rnd.Int63n(int64(in.faults.MaxDelay)) + 1)
+	}
+
+	if in.roll(in.faults.CorruptRate) {
+		frame = frame[:len(frame)/2]
+	}
+
+	if in.held != nil {
+		action.Frames = [][]byte{frame, in.held}
+		in.held = nil
+		return action
+	}
+
+	if in.roll(in.faults.ReorderRate) {
+		in.held = append([]byte(nil), frame...)
+		return action
+	}
+
+	action.Frames = [][]byte{frame}
+	return action
+}
+
+// Flush returns the held back message, nil if there is none.
+func (in *FaultInjector) Flush() []byte {
+	held := in.held
+	in.held = nil
+	return held
+}
+
+// faultConn injects the faults of its `FaultInjector` into the messages of its connection.
+type faultConn struct {
+	frameConn
+	faults *FaultInjector
+
+	typ     int
+	pending [][]byte
+}
+
+// withFaults returns the "conn" with the faults of the `LiveConfiguration.Faults` injected, the "conn" itself if there are none.
+// The choices of the faults continue over the reconnections.
+func (c *LiveConnection) withFaults(conn frameConn) frameConn {
+	if c.config.Faults == nil {
+		return conn
+	}
+
+	if c.faults == nil {
+		c.faults = NewFaultInjector(*c.config.Faults)
+	}
+
+	return &faultConn{frameConn: conn, faults: c.faults}
+}
+
+func (c *faultConn) NextReader() (int, io.Reader, error) {
+	for len(c.pending) == 0 {
+		typ, r, err := c.frameConn.NextReader()
+		if err != nil {
+			if held := c.faults.Flush(); held != nil {
+				// the connection can't be read anymore, the held back message is its last one.
+				return c.typ, bytes.NewReader(held), nil
+			}
+			return typ, r, err
+		}
+
+		frame, err := ioutil.ReadAll(r)
+		if err != nil {
+			return typ, nil, err
+		}
+
+		action := c.faults.Next(frame)
+		if action.Disconnect {
+			c.frameConn.Close()
+			return typ, nil, ErrFaultDisconnect
+		}
+
+		if action.Delay > 0 {
+			time.Sleep(action.Delay)
+		}
+
+		c.typ, c.pending = typ, action.Frames
+	}
+
+	frame := c.pending[0]
+	c.pending = c.pending[1:]
+	return c.typ, bytes.NewReader(frame), nil
+}
+
+// WriteJSON writes to the connection, the faults are injected into the received messages only.
+func (c *faultConn) WriteJSON(v interface{}) error {
+	w, ok := c.frameConn.(jsonWriter)
+	if !ok {
+		return errors.New("live: a recorded session can't send messages")
+	}
+
+	return w.WriteJSON(v)
+}
@@ -0,0 +1,143 @@
+package websocket
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFaultsValidate(t *testing.T) {
+	assert.NoError(t, Faults{DisconnectRate: 1, DelayRate: 0.5, MaxDelay: time.Second}.validate())
+	assert.Error(t, Faults{CorruptRate: 1.5}.validate())
+	assert.Error(t, Faults{ReorderRate: -0.1}.validate())
+	assert.Error(t, Faults{MaxDelay: -time.Second}.validate())
+
+	_, err := OpenLiveConnection(LiveConfiguration{Host: "http://localhost", Faults: &Faults{DelayRate: 2}})
+	assert.Error(t, err)
+}
+
+func TestFaultInjectorSeed(t *testing.T) {
+	faults := Faults{DisconnectRate: 0.2, DelayRate: 0.5, MaxDelay: time.Second, CorruptRate: 0.3, ReorderRate: 0.3, Seed: 42}
+	a, b := NewFaultInjector(faults), NewFaultInjector(faults)
+
+	for i := 0; i < 100; i++ {
+		frame := []byte(`{"type":"HEARTBEAT"}`)
+		assert.Equal(t, a.Next(frame), b.Next(frame))
+	}
+}
+
+func TestFaultInjectorNoFaults(t *testing.T) {
+	in := NewFaultInjector(Faults{})
+	frame := []byte(`{"type":"HEARTBEAT"}`)
+
+	assert.Equal(t, FaultAction{Frames: [][]byte{frame}}, in.Next(frame))
+	assert.Nil(t, in.Flush())
+}
+
+func TestFaultInjectorReorder(t *testing.T) {
+	in := NewFaultInjector(Faults{ReorderRate: 1})
+
+	action := in.Next([]byte("1"))
+	assert.Empty(t, action.Frames)
+
+	// the held back message is delivered after the next one, which is not held back itself.
+	action = in.Next([]byte("2"))
+	assert.Equal(t, [][]byte{[]byte("2"), []byte("1")}, action.Frames)
+
+	in.Next([]byte("3"))
+	assert.Equal(t, []byte("3"), in.Flush())
+	assert.Nil(t, in.Flush())
+}
+
+func TestFaultInjectorCorruptAndDelay(t *testing.T) {
+	in := NewFaultInjector(Faults{CorruptRate: 1, DelayRate: 1, MaxDelay: time.Millisecond})
+
+	action := in.Next([]byte(`{"type":"HEARTBEAT"}`))
+	assert.Equal(t, [][]byte{[]byte(`{"type":"H`)}, action.Frames)
+	assert.True(t, action.Delay > 0 && action.Delay <= time.Millisecond)
+}
+
+func TestFaultInjectorDisconnect(t *testing.T) {
+	in := NewFaultInjector(Faults{DisconnectRate: 1})
+	assert.Equal(t, FaultAction{Disconnect: true}, in.Next([]byte("1")))
+}
+
+func TestLiveConnectionFaultsCorrupt(t *testing.T) {
+	start := make(chan struct{})
+	srv := newTestServer(t, start, `{"type":"RECORD","data":{"value":1}}`)
+	defer srv.Close()
+
+	conn := openTestConnection(t, srv, LiveConfiguration{Faults: &Faults{CorruptRate: 1}})
+	defer conn.Close()
+
+	conn.OnRecordMessage(func(resp LiveResponse) error {
+		t.Errorf("unexpected record [%s]", resp.Data.Value)
+		return nil
+	})
+	close(start)
+
+	select {
+	case err := <-conn.Err():
+		assert.True(t, strings.HasPrefix(err.Error(), "live: read json"), err.Error())
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+}
+
+func TestLiveConnectionFaultsReorder(t *testing.T) {
+	start := make(chan struct{})
+	srv := newTestServer(t, start,
+		`{"type":"RECORD","data":{"value":1}}`,
+		`{"type":"RECORD","data":{"value":2}}`,
+	)
+	defer srv.Close()
+
+	conn := openTestConnection(t, srv, LiveConfiguration{Faults: &Faults{ReorderRate: 1}})
+	defer conn.Close()
+
+	got := make(chan string, 2)
+	conn.OnRecordMessage(func(resp LiveResponse) error {
+		got <- string(resp.Data.Value)
+		return nil
+	})
+	close(start)
+
+	for _, expected := range []string{"2", "1"} {
+		select {
+		case value := <-got:
+			assert.Equal(t, expected, value)
+		case err := <-conn.Err():
+			t.Fatal(err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout")
+		}
+	}
+}
+
+func TestLiveConnectionFaultsDisconnect(t *testing.T) {
+	start := make(chan struct{})
+	srv := newTestServer(t, start, `{"type":"RECORD","data":{"value":1}}`)
+	defer srv.Close()
+
+	conn := openTestConnection(t, srv, LiveConfiguration{Faults: &Faults{DisconnectRate: 1}})
+	defer conn.Close()
+
+	conn.OnRecordMessage(func(resp LiveResponse) error {
+		t.Errorf("unexpected record [%s]", resp.Data.Value)
+		return nil
+	})
+	close(start)
+
+	select {
+	case err := <-conn.Err():
+		var closedErr *ConnectionClosedError
+		if assert.True(t, errors.As(err, &closedErr)) {
+			assert.Equal(t, ErrFaultDisconnect, closedErr.Cause)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+}
@@ -0,0 +1,68 @@
+package websocket
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ParseHeader parses the "Name: value" headers, i.e of the `--ws-header` flags, for the `LiveConfiguration.Header`.
+// A header can be given more than once.
+func ParseHeader(values []string) (http.Header, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	header := make(http.Header, len(values))
+	for _, v := range values {
+		idx := strings.IndexByte(v, ':')
+		if idx <= 0 {
+			return nil, fmt.Errorf("invalid header [%s], expected name: value", v)
+		}
+
+		name, value := strings.TrimSpace(v[:idx]), strings.TrimSpace(v[idx+1:])
+		if name == "" || strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("invalid header [%s], expected name: value", v)
+		}
+
+		header.Add(name, value)
+	}
+
+	return header, nil
+}
+
+// The `LiveConfiguration.TokenHeader` values.
+const (
+	TokenHeaderLenses        = "X-Kafka-Lenses-Token"
+	TokenHeaderAuthorization = "Authorization"
+)
+
+// withTokenHeader returns the "config" with its message's token moved to the `TokenHeader` of the handshake, if set.
+func (config LiveConfiguration) withTokenHeader() LiveConfiguration {
+	if config.TokenHeader == "" || config.Message.Token == "" {
+		return config
+	}
+
+	token := config.Message.Token
+	if strings.EqualFold(config.TokenHeader, TokenHeaderAuthorization) {
+		token = "Bearer " + token
+	}
+
+	header := config.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	header.Set(config.TokenHeader, token)
+
+	config.Header = header
+	config.Message.Token = ""
+	return config
+}
+
+// Subprotocol returns the websocket subprotocol that the server selected for the current connection, if any.
+func (c *LiveConnection) Subprotocol() string {
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
+
+	return c.conn.Subprotocol()
+}
@@ -0,0 +1,325 @@
+package websocket
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"github.com/vmihailenco/msgpack/v5/msgpcode"
+)
+
+// Encoding is the encoding of the messages sent by the server.
+type Encoding string
+
+const (
+	// EncodingJSON is the default encoding, every server supports it.
+	EncodingJSON Encoding = "json"
+	// EncodingMsgpack is the MessagePack encoding, https://github.com/msgpack/msgpack/blob/master/spec.md,
+	// it is used only when the server accepts the "msgpack" subprotocol during the handshake of the
+	// /api/ws/v2/sql/execute endpoint. The server sends the same messages as the JSON protocol, a map of the
+	// "type" and the "data", whose key and value are the records' JSON-like values and whose headers are strings or binaries.
+	// See `LiveConfiguration.PreferMsgpack`.
+	EncodingMsgpack Encoding = "msgpack"
+)
+
+// subprotocols returns the websocket subprotocols to offer to the server, most preferred first.
+func (config LiveConfiguration) subprotocols() []string {
+	if !config.PreferMsgpack {
+		return config.Subprotocols
+	}
+
+	return append([]string{string(EncodingMsgpack), string(EncodingJSON)}, config.Subprotocols...)
+}
+
+// Encoding returns the encoding that was negotiated with the server for the current connection.
+func (c *LiveConnection) Encoding() Encoding {
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
+
+	if c.conn.Subprotocol() == string(EncodingMsgpack) {
+		return EncodingMsgpack
+	}
+
+	return EncodingJSON
+}
+
+// msgpackResponse is the MessagePack form of a `LiveResponse`.
+type msgpackResponse struct {
+	Type string       `msgpack:"type"`
+	Data *msgpackData `msgpack:"data"`
+}
+
+type msgpackData struct {
+	Key      msgpack.RawMessage `msgpack:"key"`
+	Value    msgpack.RawMessage `msgpack:"value"`
+	Metadata *msgpackMetadata   `msgpack:"metadata"`
+	RowNum   int                `msgpack:"rownum"`
+	Headers  map[string][]byte  `msgpack:"headers"`
+}
+
+type msgpackMetadata struct {
+	Timestamp int64 `msgpack:"timestamp"`
+	KeySize   int   `msgpack:"__keysize"`
+	ValueSize int   `msgpack:"__valuesize"`
+	Partition int   `msgpack:"partition"`
+	Offset    int   `msgpack:"offset"`
+}
+
+// decodeMsgpack decodes the MessagePack "frame" into "resp".
+// The record's key and value are transcoded to JSON, so listeners can't tell the difference.
+func (c *LiveConnection) decodeMsgpack(frame []byte, resp *LiveResponse) error {
+	r := bytes.NewReader(frame)
+	dec := msgpack.NewDecoder(r)
+
+	var m msgpackResponse
+	if err := dec.Decode(&m); err != nil {
+		return err
+	}
+
+	if r.Len() > 0 {
+		return fmt.Errorf("msgpack: [%d] unexpected bytes after the response", r.Len())
+	}
+
+	resp.Type = ResponseType(m.Type)
+	if m.Data == nil {
+		return nil
+	}
+
+	var err error
+	data := &resp.Data
+	if data.Key, err = transcodeMsgpack(data.Key[:0], m.Data.Key); err != nil {
+		return err
+	}
+
+	if data.Value, err = transcodeMsgpack(data.Value[:0], m.Data.Value); err != nil {
+		return err
+	}
+
+	data.RowNum = m.Data.RowNum
+	data.Headers = nil
+	if m.Data.Headers != nil {
+		data.Headers = Headers(m.Data.Headers)
+	}
+
+	if md := m.Data.Metadata; md != nil {
+		data.Metadata = MetaData{KeySize: md.KeySize, ValueSize: md.ValueSize, Partition: md.Partition, Offset: md.Offset}
+		if c.config.UseNumber {
+			data.Metadata.Timestamp = json.Number(strconv.FormatInt(md.Timestamp, 10))
+		} else {
+			data.Metadata.Timestamp = float64(md.Timestamp)
+		}
+	}
+
+	return nil
+}
+
+// transcodeMsgpack appends the MessagePack "raw" value as JSON to "dst", an absent value stays nil.
+// The value is transcoded as it is read, its maps keep the order of their keys and the strings are not HTML-escaped.
+func transcodeMsgpack(dst []byte, raw msgpack.RawMessage) ([]byte, error) {
+	if raw == nil {
+		return nil, nil
+	}
+
+	dec := msgpack.GetDecoder()
+	defer msgpack.PutDecoder(dec)
+	dec.Reset(bytes.NewReader(raw))
+
+	b, err := appendMsgpackValue(dst, dec)
+	if err != nil {
+		return dst, fmt.Errorf("msgpack: [%v]", err)
+	}
+
+	return b, nil
+}
+
+// appendMsgpackValue appends the JSON of the next value of the "dec" to "dst".
+func appendMsgpackValue(dst []byte, dec *msgpack.Decoder) ([]byte, error) {
+	c, err := dec.PeekCode()
+	if err != nil {
+		return dst, err
+	}
+
+	switch {
+	case c == msgpcode.Nil:
+		return append(dst, "null"...), dec.DecodeNil()
+	case c == msgpcode.False || c == msgpcode.True:
+		v, err := dec.DecodeBool()
+		return strconv.AppendBool(dst, v), err
+	case c == msgpcode.Float:
+		v, err := dec.DecodeFloat32()
+		if err != nil {
+			return dst, err
+		}
+		return appendJSONFloat(dst, float64(v), 32)
+	case c == msgpcode.Double:
+		v, err := dec.DecodeFloat64()
+		if err != nil {
+			return dst, err
+		}
+		return appendJSONFloat(dst, v, 64)
+	case c == msgpcode.Uint8 || c == msgpcode.Uint16 || c == msgpcode.Uint32 || c == msgpcode.Uint64:
+		v, err := dec.DecodeUint64()
+		return strconv.AppendUint(dst, v, 10), err
+	case msgpcode.IsFixedNum(c) || c == msgpcode.Int8 || c == msgpcode.Int16 || c == msgpcode.Int32 || c == msgpcode.Int64:
+		v, err := dec.DecodeInt64()
+		return strconv.AppendInt(dst, v, 10), err
+	case msgpcode.IsString(c):
+		v, err := dec.DecodeString()
+		return appendJSONString(dst, v), err
+	case msgpcode.IsBin(c):
+		// as the `json.Marshal` of a []byte.
+		v, err := dec.DecodeBytes()
+		if err != nil {
+			return dst, err
+		}
+		dst = append(dst, '"')
+		n := len(dst)
+		dst = append(dst, make([]byte, base64.StdEncoding.EncodedLen(len(v)))...)
+		base64.StdEncoding.Encode(dst[n:], v)
+		return append(dst, '"'), nil
+	case msgpcode.IsFixedArray(c) || c == msgpcode.Array16 || c == msgpcode.Array32:
+		n, err := dec.DecodeArrayLen()
+		if err != nil || n < 0 {
+			return append(dst, "null"...), err
+		}
+
+		dst = append(dst, '[')
+		for i := 0; i < n; i++ {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			if dst, err = appendMsgpackValue(dst, dec); err != nil {
+				return dst, err
+			}
+		}
+		return append(dst, ']'), nil
+	case msgpcode.IsFixedMap(c) || c == msgpcode.Map16 || c == msgpcode.Map32:
+		n, err := dec.DecodeMapLen()
+		if err != nil || n < 0 {
+			return append(dst, "null"...), err
+		}
+
+		dst = append(dst, '{')
+		for i := 0; i < n; i++ {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			if dst, err = appendMsgpackKey(dst, dec); err != nil {
+				return dst, err
+			}
+			dst = append(dst, ':')
+			if dst, err = appendMsgpackValue(dst, dec); err != nil {
+				return dst, err
+			}
+		}
+		return append(dst, '}'), nil
+	default:
+		// the extensions, i.e the timestamps.
+		v, err := dec.DecodeInterfaceLoose()
+		if err != nil {
+			return dst, err
+		}
+
+		b, err := json.Marshal(v)
+		return append(dst, b...), err
+	}
+}
+
+// appendMsgpackKey appends the next map key of the "dec" to "dst" as a JSON string, a number key is quoted.
+func appendMsgpackKey(dst []byte, dec *msgpack.Decoder) ([]byte, error) {
+	c, err := dec.PeekCode()
+	if err != nil {
+		return dst, err
+	}
+
+	if msgpcode.IsString(c) {
+		k, err := dec.DecodeString()
+		return appendJSONString(dst, k), err
+	}
+
+	k, err := dec.DecodeInterfaceLoose()
+	if err != nil {
+		return dst, err
+	}
+
+	switch k := k.(type) {
+	case int64:
+		return appendJSONString(dst, strconv.FormatInt(k, 10)), nil
+	case uint64:
+		return appendJSONString(dst, strconv.FormatUint(k, 10)), nil
+	default:
+		return dst, fmt.Errorf("unsupported map key type [%T]", k)
+	}
+}
+
+// appendJSONFloat appends the "f" of the "bitSize" as the `json.Marshal` does.
+func appendJSONFloat(dst []byte, f float64, bitSize int) ([]byte, error) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return dst, fmt.Errorf("unsupported float value [%v]", f)
+	}
+
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+
+	dst = strconv.AppendFloat(dst, f, format, -1, bitSize)
+	if n := len(dst); format == 'e' && dst[n-4] == 'e' && dst[n-3] == '-' && dst[n-2] == '0' {
+		// 1e-07 to 1e-7.
+		dst[n-2] = dst[n-1]
+		dst = dst[:n-1]
+	}
+
+	return dst, nil
+}
+
+// appendJSONString appends the "s" as a JSON string, without the HTML escaping of the `json.Marshal`.
+func appendJSONString(dst []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if b >= 0x20 && b != '"' && b != '\\' {
+				i++
+				continue
+			}
+
+			dst = append(dst, s[start:i]...)
+			switch b {
+			case '"', '\\':
+				dst = append(dst, '\\', b)
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hex[b>>4], hex[b&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, "\\ufffd"...)
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
@@ -0,0 +1,200 @@
+package websocket
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	test "github.com/lensesio/lenses-go/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// encodeMsgpack returns the MessagePack encoding of the JSON-like "v".
+func encodeMsgpack(t testing.TB, v interface{}) []byte {
+	b, err := msgpack.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
+
+var msgpackRecord = map[string]interface{}{
+	"type": "RECORD",
+	"data": map[string]interface{}{
+		"key": "customer-42",
+		"value": map[string]interface{}{
+			"name":    "Jane \"JD\" Doe\n",
+			"balance": -1250.5,
+			"tags":    []interface{}{"vip", nil, true, 7},
+			"since":   1600000000000,
+		},
+		"metadata": map[string]interface{}{"timestamp": 1600000000000123, "partition": 3, "offset": 42, "__keysize": 11, "__valuesize": 96},
+		"rownum":   1,
+		"headers":  map[string]interface{}{"source": "web", "retried": nil},
+	},
+}
+
+func TestDecodeMsgpack(t *testing.T) {
+	c := &LiveConnection{config: LiveConfiguration{UseNumber: true}}
+
+	var resp LiveResponse
+	if err := c.decodeMsgpack(encodeMsgpack(t, msgpackRecord), &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, RecordMessageResponse, resp.Type)
+	assert.JSONEq(t, `"customer-42"`, string(resp.Data.Key))
+	assert.JSONEq(t, `{"name":"Jane \"JD\" Doe\n","balance":-1250.5,"tags":["vip",null,true,7],"since":1600000000000}`, string(resp.Data.Value))
+	assert.Equal(t, MetaData{Timestamp: json.Number("1600000000000123"), Partition: 3, Offset: 42, KeySize: 11, ValueSize: 96}, resp.Data.Metadata)
+	assert.Equal(t, 1, resp.Data.RowNum)
+	assert.Equal(t, Headers{"source": []byte("web"), "retried": nil}, resp.Data.Headers)
+
+	assert.Error(t, c.decodeMsgpack(encodeMsgpack(t, msgpackRecord)[:40], &LiveResponse{}))
+	assert.Error(t, c.decodeMsgpack(append(encodeMsgpack(t, msgpackRecord), 0xc0), &LiveResponse{}))
+}
+
+func TestTranscodeMsgpack(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected string
+	}{
+		{nil, `null`},
+		{"<a href=\"x\">&</a>", `"<a href=\"x\">&</a>"`},
+		{"tab\tand\x01\xff", `"tab\tand\u0001\ufffd"`},
+		{[]byte("web"), `"d2Vi"`},
+		{uint64(18446744073709551615), `18446744073709551615`},
+		{-7, `-7`},
+		{1.5e-7, `1.5e-7`},
+		{float32(0.25), `0.25`},
+		{map[int]string{1: "a"}, `{"1":"a"}`},
+		{[]interface{}{true, nil, "x"}, `[true,null,"x"]`},
+	}
+
+	for _, tt := range tests {
+		b, err := transcodeMsgpack(nil, encodeMsgpack(t, tt.value))
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expected, string(b))
+	}
+
+	// the keys keep their order, unlike the maps of the `json.Marshal`.
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.EncodeMapLen(2)
+	enc.EncodeString("z")
+	enc.EncodeInt(1)
+	enc.EncodeString("a")
+	enc.EncodeInt(2)
+
+	b, err := transcodeMsgpack([]byte("prefix:"), buf.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, `prefix:{"z":1,"a":2}`, string(b))
+}
+
+// newMsgpackTestServer starts a websocket server which writes the record as MessagePack
+// if the client negotiated it, otherwise as JSON.
+func newMsgpackTestServer(t *testing.T, subprotocols ...string) *httptest.Server {
+	upgrader := websocket.Upgrader{Subprotocols: subprotocols}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+
+		var msg Message
+		if err = conn.ReadJSON(&msg); err != nil {
+			return
+		}
+
+		if conn.Subprotocol() == string(EncodingMsgpack) {
+			err = conn.WriteMessage(websocket.BinaryMessage, encodeMsgpack(t, msgpackRecord))
+		} else {
+			err = conn.WriteJSON(msgpackRecord)
+		}
+		if err != nil {
+			return
+		}
+
+		conn.ReadMessage()
+	}))
+}
+
+func TestLiveConnectionPreferMsgpack(t *testing.T) {
+	test.SetupMasterContext()
+
+	tests := []struct {
+		name         string
+		subprotocols []string
+		expected     Encoding
+	}{
+		{"server supports msgpack", []string{"msgpack"}, EncodingMsgpack},
+		{"server without subprotocols", nil, EncodingJSON},
+		{"server prefers json", []string{"json", "msgpack"}, EncodingJSON},
+	}
+
+	for _, tt := range tests {
+		srv := newMsgpackTestServer(t, tt.subprotocols...)
+
+		conn, err := OpenLiveConnection(LiveConfiguration{Host: srv.URL, PreferMsgpack: true, UseNumber: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := make(chan LiveResponse, 1)
+		conn.OnRecordMessage(func(resp LiveResponse) error {
+			got <- resp
+			return nil
+		})
+
+		select {
+		case resp := <-got:
+			assert.Equal(t, tt.expected, conn.Encoding(), tt.name)
+			assert.JSONEq(t, `"customer-42"`, string(resp.Data.Key), tt.name)
+			assert.Equal(t, json.Number("1600000000000123"), resp.Data.Metadata.Timestamp, tt.name)
+			assert.Equal(t, "web", resp.Data.Headers.Get("source"), tt.name)
+		case err := <-conn.Err():
+			t.Fatalf("[%s] %v", tt.name, err)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("[%s] timeout", tt.name)
+		}
+
+		conn.Close()
+		srv.Close()
+	}
+}
+
+func BenchmarkDecodeRecord(b *testing.B) {
+	jsonRecord, _ := json.Marshal(msgpackRecord)
+	msgpackRecord := encodeMsgpack(b, msgpackRecord)
+
+	b.Run("json", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var resp LiveResponse
+			dec := json.NewDecoder(bytes.NewReader(jsonRecord))
+			dec.UseNumber()
+			if err := dec.Decode(&resp); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("msgpack", func(b *testing.B) {
+		b.ReportAllocs()
+		c := &LiveConnection{config: LiveConfiguration{UseNumber: true}}
+		for i := 0; i < b.N; i++ {
+			var resp LiveResponse
+			if err := c.decodeMsgpack(msgpackRecord, &resp); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package websocket
+
+// DefaultAsyncQueueSize is the default number of messages, per `ResponseType`,
+// that wait for a free worker to run their asynchronous listeners.
+//
+// See `LiveConfiguration.AsyncQueueSize` and `LiveConnection.OnAsync`.
+const DefaultAsyncQueueSize = 1024
+
+// workerPool runs the asynchronous listeners of a single `ResponseType`
+// on a fixed number of go routines.
+type workerPool struct {
+	typ  ResponseType
+	jobs chan LiveResponse
+}
+
+// acquireWorkerPool returns the worker pool for the "typ" `ResponseType`,
+// the pool and its workers are created on the first call.
+// Callers should hold the write lock.
+func (c *LiveConnection) acquireWorkerPool(typ ResponseType) *workerPool {
+	if p, ok := c.pools[typ]; ok {
+		return p
+	}
+
+	workers := c.config.Concurrency[typ]
+	if workers <= 0 {
+		workers = 1
+	}
+
+	queueSize := c.config.AsyncQueueSize
+	if queueSize <= 0 {
+		queueSize = DefaultAsyncQueueSize
+	}
+
+	p := &workerPool{
+		typ:  typ,
+		jobs: make(chan LiveResponse, queueSize),
+	}
+
+	for i := 0; i < workers; i++ {
+		go c.work(p)
+	}
+
+	c.pools[typ] = p
+	return p
+}
+
+// work runs the asynchronous listeners for each queued message until the connection is closed.
+func (c *LiveConnection) work(p *workerPool) {
+	for {
+		select {
+		case <-c.receiveStop:
+			return
+		case resp := <-p.jobs:
+			c.mu.RLock()
+			callbacks := c.asyncListeners[p.typ]
+			c.mu.RUnlock()
+
+			for _, sub := range callbacks {
+				if err := sub.cb(resp); err != nil {
+					c.listenerFailed(sub, resp, true, err)
+				}
+			}
+		}
+	}
+}
+
+// dispatchAsync queues the response to its type's worker pool, if any.
+// It blocks while the queue is full, so a slow listener slows down the reader instead of dropping messages.
+func (c *LiveConnection) dispatchAsync(resp LiveResponse) {
+	c.mu.RLock()
+	p, ok := c.pools[resp.Type]
+	c.mu.RUnlock()
+
+	if !ok {
+		return
+	}
+
+	select {
+	case p.jobs <- c.detach(resp):
+	case <-c.receiveStop:
+	}
+}
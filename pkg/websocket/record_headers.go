@@ -0,0 +1,78 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Headers are the Kafka headers of a record, by name, see `Data.Headers`.
+//
+// They are decoded from a JSON object of strings, or of nulls for the headers without a value,
+// and encoded the same way, so their values are expected to be text.
+type Headers map[string][]byte
+
+// UnmarshalJSON decodes the headers from a JSON object of strings.
+func (h *Headers) UnmarshalJSON(b []byte) error {
+	var values map[string]*string
+	if err := json.Unmarshal(b, &values); err != nil {
+		return fmt.Errorf("headers: expected an object of strings: [%v]", err)
+	}
+
+	if values == nil {
+		*h = nil
+		return nil
+	}
+
+	headers := make(Headers, len(values))
+	for name, v := range values {
+		if v == nil {
+			headers[name] = nil
+			continue
+		}
+		headers[name] = []byte(*v)
+	}
+
+	*h = headers
+	return nil
+}
+
+// MarshalJSON encodes the headers as a JSON object of strings.
+func (h Headers) MarshalJSON() ([]byte, error) {
+	if h == nil {
+		return []byte("null"), nil
+	}
+
+	values := make(map[string]*string, len(h))
+	for name, v := range h {
+		if v == nil {
+			values[name] = nil
+			continue
+		}
+		s := string(v)
+		values[name] = &s
+	}
+
+	return json.Marshal(values)
+}
+
+// Get returns the value of the header "name" as text, empty if it's missing.
+func (h Headers) Get(name string) string {
+	return string(h[name])
+}
+
+// Clone returns a copy of the headers which does not share their values.
+func (h Headers) Clone() Headers {
+	if h == nil {
+		return nil
+	}
+
+	clone := make(Headers, len(h))
+	for name, v := range h {
+		if v != nil {
+			v = append([]byte(nil), v...)
+		}
+		clone[name] = v
+	}
+
+	return clone
+}
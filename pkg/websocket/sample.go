@@ -0,0 +1,78 @@
+package websocket
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var limitClause = regexp.MustCompile(`(?is)\bLIMIT\s+\d+(\s*,\s*\d+)?\s*$`)
+
+// LimitSQL returns the "sql" with a "LIMIT" clause of "limit" records appended,
+// unless the statement ends with a "LIMIT" clause already or "limit" is not positive.
+func LimitSQL(sql string, limit int) string {
+	if limit <= 0 {
+		return sql
+	}
+
+	stmt := strings.TrimSpace(sql)
+	terminated := strings.HasSuffix(stmt, ";")
+	stmt = strings.TrimSpace(strings.TrimSuffix(stmt, ";"))
+
+	if limitClause.MatchString(stmt) {
+		return sql
+	}
+
+	stmt = fmt.Sprintf("%s LIMIT %d", stmt, limit)
+	if terminated {
+		stmt += ";"
+	}
+
+	return stmt
+}
+
+// ParseSampleRate parses a sample rate given as a percentage, i.e "1%", or as a fraction, i.e "0.01".
+// The result is between 0 and 1.
+func ParseSampleRate(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+
+	percent := strings.HasSuffix(s, "%")
+	rate, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid sample rate [%s]: [%v]", s, err)
+	}
+
+	if percent {
+		rate /= 100
+	}
+
+	if rate <= 0 || rate > 1 || math.IsNaN(rate) {
+		return 0, fmt.Errorf("invalid sample rate [%s]: must be greater than 0%% and up to 100%%", s)
+	}
+
+	return rate, nil
+}
+
+// sampled reports whether the record should be kept for the sample "rate".
+// The decision depends only on the record's key, so the records of a key are all kept or all dropped,
+// records without a key are sampled by their value instead.
+func sampled(resp LiveResponse, rate float64) bool {
+	if rate <= 0 || rate >= 1 {
+		return true
+	}
+
+	b := resp.Data.Key
+	if len(b) == 0 || string(b) == "null" {
+		b = resp.Data.Value
+	}
+
+	h := fnv.New32a()
+	h.Write(b)
+	return float64(h.Sum32()) < rate*float64(math.MaxUint32)
+}
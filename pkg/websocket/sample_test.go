@@ -0,0 +1,80 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestLimitSQL(t *testing.T) {
+	tests := []struct {
+		sql      string
+		limit    int
+		expected string
+	}{
+		{"SELECT * FROM topic", 10, "SELECT * FROM topic LIMIT 10"},
+		{"SELECT * FROM topic;", 10, "SELECT * FROM topic LIMIT 10;"},
+		{"SELECT * FROM topic limit 5", 10, "SELECT * FROM topic limit 5"},
+		{"SELECT * FROM topic LIMIT 5;", 10, "SELECT * FROM topic LIMIT 5;"},
+		{"SELECT * FROM topic", 0, "SELECT * FROM topic"},
+	}
+
+	for _, tt := range tests {
+		if got := LimitSQL(tt.sql, tt.limit); got != tt.expected {
+			t.Errorf("[%s] expected [%s] but got [%s]", tt.sql, tt.expected, got)
+		}
+	}
+}
+
+func TestParseSampleRate(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+		err      bool
+	}{
+		{"1%", 0.01, false},
+		{"0.25", 0.25, false},
+		{"100%", 1, false},
+		{"", 0, false},
+		{"0%", 0, true},
+		{"150%", 0, true},
+		{"abc", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSampleRate(tt.input)
+		if (err != nil) != tt.err {
+			t.Errorf("[%s] expected error [%v] but got [%v]", tt.input, tt.err, err)
+			continue
+		}
+
+		if got != tt.expected {
+			t.Errorf("[%s] expected [%v] but got [%v]", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestSampled(t *testing.T) {
+	record := func(key string) LiveResponse {
+		return LiveResponse{Type: RecordMessageResponse, Data: Data{Key: json.RawMessage(key), Value: json.RawMessage(`{}`)}}
+	}
+
+	kept := 0
+	for i := 0; i < 10000; i++ {
+		key := fmt.Sprintf(`"key-%d"`, i)
+		if sampled(record(key), 0.1) {
+			kept++
+			if !sampled(record(key), 0.1) {
+				t.Fatalf("expected the same key [%s] to be kept every time", key)
+			}
+		}
+	}
+
+	if kept < 800 || kept > 1200 {
+		t.Fatalf("expected about 10%% of the records to be kept but kept [%d]", kept)
+	}
+
+	if !sampled(record(`"key"`), 0) || !sampled(record(`"key"`), 1) {
+		t.Fatal("expected all the records to be kept without a sample rate")
+	}
+}
@@ -0,0 +1,264 @@
+package websocket
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// LiveStream is implemented by the `LiveConnection` and the `FileLiveConnection`,
+// so the code that consumes a query's messages can replay a recorded session too.
+type LiveStream interface {
+	On(typ ResponseType, cb LiveListener) ListenerID
+	OnAsync(typ ResponseType, cb LiveListener) ListenerID
+	Once(typ ResponseType, cb LiveListener) ListenerID
+	Off(typ ResponseType, id ListenerID) bool
+	OnRaw(cb RawListener) ListenerID
+	OffRaw(id ListenerID) bool
+
+	OnError(cb LiveListener) ListenerID
+	OnInvalidRequest(cb LiveListener) ListenerID
+	OnRecordMessage(cb LiveListener) ListenerID
+	OnHeartbeat(cb LiveListener) ListenerID
+	OnSuccess(cb LiveListener) ListenerID
+	OnStats(cb LiveListener) ListenerID
+	OnEnd(cb LiveListener) ListenerID
+
+	Encoding() Encoding
+	TransferStats() TransferStats
+	Err() <-chan error
+	Wait(interruptSignal <-chan os.Signal) error
+	Close() error
+}
+
+var (
+	_ LiveStream = (*LiveConnection)(nil)
+	_ LiveStream = (*FileLiveConnection)(nil)
+)
+
+const sessionVersion = 1
+
+// sessionHeader is the first line of a session file.
+type sessionHeader struct {
+	Version  int       `json:"version"`
+	Encoding Encoding  `json:"encoding"`
+	SQL      string    `json:"sql,omitempty"`
+	Recorded time.Time `json:"recorded"`
+}
+
+// sessionFrame is a message of a session file, one per line after the header.
+type sessionFrame struct {
+	// At is the time the message was received, since the start of the recording.
+	At time.Duration `json:"at"`
+	// Frame is the JSON encoded message.
+	Frame json.RawMessage `json:"frame,omitempty"`
+	// Binary is the MessagePack encoded message.
+	Binary []byte `json:"binary,omitempty"`
+}
+
+// Record writes the messages received by the connection, as they were sent by the server, to "w",
+// so they can be replayed later by a `FileLiveConnection`, see `OpenFileLiveConnection`.
+// Each message is written on its own line as soon as it's received, "w" is not buffered.
+// The token of the query message is not recorded.
+//
+// It returns the identifier of the raw listener which records the messages, pass it to `OffRaw` to stop recording.
+func (c *LiveConnection) Record(w io.Writer) (ListenerID, error) {
+	enc := json.NewEncoder(w)
+
+	start := time.Now()
+	header := sessionHeader{
+		Version:  sessionVersion,
+		Encoding: c.Encoding(),
+		SQL:      c.config.Message.SQL,
+		Recorded: start,
+	}
+
+	if err := enc.Encode(header); err != nil {
+		return 0, fmt.Errorf("live: record: [%v]", err)
+	}
+
+	id := c.OnRaw(func(frame []byte) error {
+		f := sessionFrame{At: time.Since(start)}
+		if json.Valid(frame) {
+			f.Frame = frame
+		} else {
+			f.Binary = frame
+		}
+
+		if err := enc.Encode(f); err != nil {
+			return fmt.Errorf("live: record: [%v]", err)
+		}
+		return nil
+	})
+
+	return id, nil
+}
+
+// FileLiveConnection replays a session recorded by `LiveConnection.Record`,
+// its listeners receive the recorded messages as if they were sent by the server.
+type FileLiveConnection struct {
+	*LiveConnection
+	session *sessionConn
+}
+
+// OpenFileLiveConnection opens the session file "filename" for replay.
+// The "speed" multiplies the recorded pace, i.e 2 replays twice as fast, zero replays as fast as possible.
+// The connection and the query fields of the "config" are ignored.
+//
+// The replay starts on `Start` or `Wait`, so the listeners can be added before the first message.
+// After the last message the connection stays open, as a live connection with no more messages does,
+// until it is closed.
+func OpenFileLiveConnection(filename string, config LiveConfiguration, speed float64) (*FileLiveConnection, error) {
+	if config.SampleRate < 0 || config.SampleRate > 1 {
+		return nil, fmt.Errorf("live: sample rate [%v] must be between 0 and 1", config.SampleRate)
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := newSessionConn(f, speed)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("live: replay [%s]: [%v]", filename, err)
+	}
+
+	config.HeartbeatTimeout = 0
+	config.ReconnectOnHeartbeatTimeout = false
+	config.Message.SQL = session.header.SQL
+
+	c := newLiveConnection(config)
+	c.conn = session
+	go c.readLoop()
+
+	return &FileLiveConnection{LiveConnection: c, session: session}, nil
+}
+
+// SQL returns the query of the recorded session.
+func (c *FileLiveConnection) SQL() string {
+	return c.session.header.SQL
+}
+
+// Recorded returns the time the session's recording started.
+func (c *FileLiveConnection) Recorded() time.Time {
+	return c.session.header.Recorded
+}
+
+// Start starts the replay, if it's not started already.
+func (c *FileLiveConnection) Start() {
+	c.session.startOnce.Do(func() { close(c.session.started) })
+}
+
+// Wait starts the replay, see `Start`, and waits until interruptSignal fires.
+func (c *FileLiveConnection) Wait(interruptSignal <-chan os.Signal) error {
+	c.Start()
+	return c.LiveConnection.Wait(interruptSignal)
+}
+
+var errSessionClosed = errors.New("replay closed")
+
+// sessionConn reads the messages of a session file for the `FileLiveConnection`.
+type sessionConn struct {
+	file   io.Closer
+	dec    *json.Decoder
+	header sessionHeader
+	speed  float64
+
+	started   chan struct{}
+	startOnce sync.Once
+	start     time.Time
+	closed    chan struct{}
+	closeOnce sync.Once
+
+	frame  sessionFrame
+	reader bytes.Reader
+}
+
+func newSessionConn(f io.ReadCloser, speed float64) (*sessionConn, error) {
+	s := &sessionConn{
+		file:    f,
+		dec:     json.NewDecoder(f),
+		speed:   speed,
+		started: make(chan struct{}),
+		closed:  make(chan struct{}),
+	}
+
+	if err := s.dec.Decode(&s.header); err != nil {
+		return nil, err
+	}
+
+	if s.header.Version != sessionVersion {
+		return nil, fmt.Errorf("unsupported session version [%d]", s.header.Version)
+	}
+
+	return s, nil
+}
+
+// NextReader returns the next recorded message, at its recorded pace.
+func (s *sessionConn) NextReader() (int, io.Reader, error) {
+	select {
+	case <-s.started:
+	case <-s.closed:
+		return 0, nil, errSessionClosed
+	}
+
+	if s.start.IsZero() {
+		s.start = time.Now()
+	}
+
+	s.frame = sessionFrame{}
+	if err := s.dec.Decode(&s.frame); err != nil {
+		if err != io.EOF {
+			return 0, nil, err
+		}
+
+		// the end of the session, wait like a live connection with no more messages.
+		<-s.closed
+		return 0, nil, errSessionClosed
+	}
+
+	if s.speed > 0 {
+		if wait := time.Until(s.start.Add(time.Duration(float64(s.frame.At) / s.speed))); wait > 0 {
+			t := time.NewTimer(wait)
+			select {
+			case <-t.C:
+			case <-s.closed:
+				t.Stop()
+				return 0, nil, errSessionClosed
+			}
+		}
+	}
+
+	if s.frame.Binary != nil {
+		s.reader.Reset(s.frame.Binary)
+		return websocket.BinaryMessage, &s.reader, nil
+	}
+
+	s.reader.Reset(s.frame.Frame)
+	return websocket.TextMessage, &s.reader, nil
+}
+
+func (s *sessionConn) Subprotocol() string {
+	return string(s.header.Encoding)
+}
+
+func (s *sessionConn) SetReadDeadline(time.Time) error {
+	return nil
+}
+
+func (s *sessionConn) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.closed)
+		err = s.file.Close()
+	})
+	return err
+}
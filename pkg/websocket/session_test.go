@@ -0,0 +1,177 @@
+package websocket
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// tempSessionFile returns the name of a session file in a new temporary directory.
+func tempSessionFile(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "lenses-session")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return filepath.Join(dir, "session.jsonl"), func() { os.RemoveAll(dir) }
+}
+
+func TestLiveConnectionRecordAndReplay(t *testing.T) {
+	messages := []string{
+		`{"type":"RECORD","data":{"key":"a","value":{"id":1},"metadata":{"timestamp":1600000000000123}}}`,
+		`{"type":"RECORD","data":{"value":2}}`,
+		`{"type":"END"}`,
+	}
+
+	start := make(chan struct{})
+	srv := newTestServer(t, start, messages...)
+	defer srv.Close()
+
+	conn := openTestConnection(t, srv, LiveConfiguration{Message: Message{Token: "secret", SQL: "SELECT * FROM a"}})
+	defer conn.Close()
+
+	filename, cleanup := tempSessionFile(t)
+	defer cleanup()
+
+	f, err := os.Create(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if _, err = conn.Record(f); err != nil {
+		t.Fatal(err)
+	}
+
+	end := make(chan struct{})
+	conn.OnEnd(func(LiveResponse) error {
+		close(end)
+		return nil
+	})
+	close(start)
+
+	select {
+	case <-end:
+	case err := <-conn.Err():
+		t.Fatal(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+
+	replay, err := OpenFileLiveConnection(filename, LiveConfiguration{UseNumber: true}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer replay.Close()
+
+	assert.Equal(t, "SELECT * FROM a", replay.SQL())
+	assert.Equal(t, EncodingJSON, replay.Encoding())
+
+	var got []LiveResponse
+	replayEnd := make(chan struct{})
+	replay.OnRecordMessage(func(resp LiveResponse) error {
+		got = append(got, resp)
+		return nil
+	})
+	replay.OnEnd(func(LiveResponse) error {
+		close(replayEnd)
+		return nil
+	})
+	replay.Start()
+
+	select {
+	case <-replayEnd:
+	case err := <-replay.Err():
+		t.Fatal(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+
+	if assert.Len(t, got, 2) {
+		assert.Equal(t, `"a"`, string(got[0].Data.Key))
+		assert.JSONEq(t, `{"id":1}`, string(got[0].Data.Value))
+		assert.Equal(t, json.Number("1600000000000123"), got[0].Data.Metadata.Timestamp)
+		assert.Equal(t, `2`, string(got[1].Data.Value))
+	}
+
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotContains(t, string(b), "secret")
+}
+
+func TestFileLiveConnectionMsgpack(t *testing.T) {
+	srv := newMsgpackTestServer(t, "msgpack")
+	defer srv.Close()
+
+	conn := openTestConnection(t, srv, LiveConfiguration{PreferMsgpack: true})
+	defer conn.Close()
+
+	filename, cleanup := tempSessionFile(t)
+	defer cleanup()
+
+	f, err := os.Create(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	recorded := make(chan struct{})
+	if _, err = conn.Record(f); err != nil {
+		t.Fatal(err)
+	}
+	conn.OnRecordMessage(func(LiveResponse) error {
+		close(recorded)
+		return nil
+	})
+
+	select {
+	case <-recorded:
+	case err := <-conn.Err():
+		t.Fatal(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+
+	replay, err := OpenFileLiveConnection(filename, LiveConfiguration{}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer replay.Close()
+
+	assert.Equal(t, EncodingMsgpack, replay.Encoding())
+
+	got := make(chan LiveResponse, 1)
+	replay.OnRecordMessage(func(resp LiveResponse) error {
+		got <- resp
+		return nil
+	})
+	replay.Start()
+
+	select {
+	case resp := <-got:
+		assert.JSONEq(t, `"customer-42"`, string(resp.Data.Key))
+	case err := <-replay.Err():
+		t.Fatal(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+}
+
+func TestOpenFileLiveConnectionInvalid(t *testing.T) {
+	filename, cleanup := tempSessionFile(t)
+	defer cleanup()
+
+	if err := ioutil.WriteFile(filename, []byte(`{"version":99}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := OpenFileLiveConnection(filename, LiveConfiguration{}, 0)
+	assert.Error(t, err)
+}
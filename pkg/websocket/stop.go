@@ -0,0 +1,100 @@
+package websocket
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// StopRequest is the message type which cancels a running query on the server, see `LiveConnection.Stop`.
+const StopRequest = "STOP"
+
+// DefaultStopTimeout is the default time to wait for the "END" of a stopped query.
+//
+// See `LiveConfiguration.StopTimeout` and `LiveConnection.Stop`.
+const DefaultStopTimeout = 10 * time.Second
+
+// ErrStopTimeout is returned by `LiveConnection.Stop` when the server did not end the query in time.
+var ErrStopTimeout = errors.New("live: query did not end in time after stop")
+
+type stopRequest struct {
+	Type string    `json:"type"`
+	Data *stopData `json:"data,omitempty"`
+}
+
+type stopData struct {
+	ID string `json:"id"`
+}
+
+// jsonWriter is implemented by the *websocket.Conn, a recorded session can't be written.
+type jsonWriter interface {
+	WriteJSON(v interface{}) error
+}
+
+// write sends the "v" as a JSON message to the "conn", the writes of the connection are serialized.
+func (c *LiveConnection) write(conn frameConn, v interface{}) error {
+	w, ok := conn.(jsonWriter)
+	if !ok {
+		return errors.New("live: a recorded session can't send messages")
+	}
+
+	c.debugSent(v)
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return w.WriteJSON(v)
+}
+
+// SubscriptionID returns the id of the connection's query, the `Message.ID`, to pass to `Stop`.
+func (c *LiveConnection) SubscriptionID() string {
+	return c.config.Message.ID
+}
+
+// Stop cancels the running query of the "subscriptionID" on the server and waits for its "END" message,
+// the `LiveConfiguration.StopTimeout` at most, without closing the connection.
+// An empty "subscriptionID" stops the connection's query, see `SubscriptionID`.
+//
+// The "END" listeners are called as usual, so the consumers of the records can finish up.
+func (c *LiveConnection) Stop(subscriptionID string) error {
+	if c.isClosed() {
+		return ErrConnectionClosed
+	}
+
+	ended := make(chan struct{})
+	id := c.Once(EndResponse, func(LiveResponse) error {
+		close(ended)
+		return nil
+	})
+
+	req := stopRequest{Type: StopRequest}
+	if subscriptionID != "" {
+		req.Data = &stopData{ID: subscriptionID}
+	}
+
+	c.connMu.Lock()
+	conn := c.conn
+	c.connMu.Unlock()
+
+	if err := c.write(conn, req); err != nil {
+		c.Off(EndResponse, id)
+		return fmt.Errorf("live: stop [%s]: %w", subscriptionID, err)
+	}
+
+	timeout := c.config.StopTimeout
+	if timeout <= 0 {
+		timeout = DefaultStopTimeout
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-ended:
+		return nil
+	case <-c.receiveStop:
+		return ErrConnectionClosed
+	case <-timer.C:
+		c.Off(EndResponse, id)
+		return ErrStopTimeout
+	}
+}
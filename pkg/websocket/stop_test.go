@@ -0,0 +1,78 @@
+package websocket
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+// newStopTestServer starts a websocket server which sends the stop requests it receives to the "stops"
+// and, if "end", ends the query.
+func newStopTestServer(t *testing.T, end bool, stops chan<- stopRequest) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+
+		var msg Message
+		if err = conn.ReadJSON(&msg); err != nil {
+			return
+		}
+
+		for {
+			var req stopRequest
+			if err = conn.ReadJSON(&req); err != nil {
+				return
+			}
+			stops <- req
+
+			if end {
+				conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"END"}`))
+			}
+		}
+	}))
+}
+
+func TestLiveConnectionStop(t *testing.T) {
+	stops := make(chan stopRequest, 1)
+	srv := newStopTestServer(t, true, stops)
+	defer srv.Close()
+
+	conn := openTestConnection(t, srv, LiveConfiguration{Message: Message{ID: "q1", SQL: "SELECT * FROM payments", Live: true}})
+	defer conn.Close()
+
+	ended := make(chan struct{}, 1)
+	conn.OnEnd(func(LiveResponse) error {
+		ended <- struct{}{}
+		return nil
+	})
+
+	assert.NoError(t, conn.Stop(conn.SubscriptionID()))
+	assert.Equal(t, stopRequest{Type: StopRequest, Data: &stopData{ID: "q1"}}, <-stops)
+	<-ended
+	assert.False(t, conn.isClosed(), "the connection stays open")
+}
+
+func TestLiveConnectionStopTimeout(t *testing.T) {
+	stops := make(chan stopRequest, 1)
+	srv := newStopTestServer(t, false, stops)
+	defer srv.Close()
+
+	conn := openTestConnection(t, srv, LiveConfiguration{StopTimeout: 50 * time.Millisecond})
+	defer conn.Close()
+
+	assert.Equal(t, ErrStopTimeout, conn.Stop(""))
+	assert.Equal(t, stopRequest{Type: StopRequest}, <-stops)
+
+	conn.Close()
+	assert.Equal(t, ErrConnectionClosed, conn.Stop(""))
+}
@@ -1,9 +1,12 @@
 package websocket
 
 import (
+	"bytes"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"os"
@@ -14,6 +17,7 @@ import (
 
 	"github.com/gorilla/websocket"
 	"github.com/kataras/golog"
+	"github.com/lensesio/lenses-go/pkg/api"
 	conf "github.com/lensesio/lenses-go/pkg/configs"
 )
 
@@ -40,6 +44,42 @@ const (
 	EndResponse ResponseType = "END"
 )
 
+// ErrHeartbeatTimeout is sent to the `LiveConnection.Err` channel when no message
+// was received during the `LiveConfiguration.HeartbeatTimeout`.
+var ErrHeartbeatTimeout = fmt.Errorf("live: heartbeat timeout")
+
+// ErrConnectionClosed is the cause of the `ConnectionClosedError`,
+// use `errors.Is(err, ErrConnectionClosed)` to check if the live connection was terminated.
+var ErrConnectionClosed = fmt.Errorf("live: connection closed")
+
+// ConnectionClosedError is sent to the `LiveConnection.Err` channel
+// when the server or the network terminated the live connection.
+type ConnectionClosedError struct {
+	// Cause is the read error, i.e a `*websocket.CloseError` or a `*net.OpError`.
+	Cause error
+}
+
+// Error returns the error message including the cause.
+func (err *ConnectionClosedError) Error() string {
+	return fmt.Sprintf("%s: [%v]", ErrConnectionClosed, err.Cause)
+}
+
+// Unwrap returns the `ErrConnectionClosed`.
+func (err *ConnectionClosedError) Unwrap() error {
+	return ErrConnectionClosed
+}
+
+// responseTypes are the message types that the `WildcardResponse` listeners are subscribed to.
+var responseTypes = []ResponseType{
+	ErrorResponse,
+	InvalidRequestResponse,
+	RecordMessageResponse,
+	HeartbeatResponse,
+	SuccessResponse,
+	StatsResponse,
+	EndResponse,
+}
+
 type (
 	//MetaData is a topic metadata returned by Lenses
 	MetaData struct {
@@ -56,6 +96,8 @@ type (
 		Value    json.RawMessage `json:"value"`
 		Metadata MetaData        `json:"metadata"`
 		RowNum   int             `json:"rownum"`
+		// Headers are the Kafka headers of the record, nil if the server does not include them.
+		Headers Headers `json:"headers,omitempty"`
 	}
 
 	// LiveResponse contains the necessary information that
@@ -68,13 +110,17 @@ type (
 		// Content contains the actual response content.
 		// Each response type has its own content layout.
 		Data Data `json:"data"`
+
+		acks *acker // the acker of the connection that delivered the record, see `Ack`.
 	}
 )
 
 type (
 	//Message for WS
 	Message struct {
-		Token string `json:"token"`
+		// ID identifies the query, to cancel it by `LiveConnection.Stop`, optionally.
+		ID    string `json:"id,omitempty"`
+		Token string `json:"token,omitempty"`
 		SQL   string `json:"sql"`
 		Live  bool   `json:"live"`
 		Stats int    `json:"stats"`
@@ -87,9 +133,12 @@ type (
 	//
 	// See `OpenLiveConnection` for more.
 	LiveConfiguration struct {
-		Host    string `json:"host"`
-		Debug   bool   `json:"debug"`
-		Message Message
+		Host  string `json:"host"`
+		Debug bool   `json:"debug"`
+		// BasePath is the path prefix of a Lenses behind a sub-path proxy, i.e "/lenses".
+		// Defaults to the `BasePath` of the current context.
+		BasePath string `json:"basePath,omitempty"`
+		Message  Message
 		// ws-specific settings, optionally.
 
 		// HandshakeTimeout specifies the duration for the handshake to complete.
@@ -102,26 +151,138 @@ type (
 		// TLSClientConfig specifies the TLS configuration to use with tls.Client.
 		// If nil, the default configuration is used.
 		TLSClientConfig *tls.Config
+		// Header is sent with the handshake request, i.e the "X-Forwarded-User" or the "Cookie"
+		// an authenticating reverse proxy in front of Lenses expects. See `ParseHeader`.
+		Header http.Header
+		// Subprotocols are requested on the handshake, after the encodings of the `PreferMsgpack`.
+		// See `LiveConnection.Subprotocol` for the one the server selected.
+		Subprotocols []string
+		// TokenHeader sends the message's token as that header of the handshake, instead of inside the query message,
+		// so it does not end up in the access logs of the proxies and the server, i.e `TokenHeaderLenses`
+		// or `TokenHeaderAuthorization` which is sent as a "Bearer" token. Defaults to the `WebsocketTokenHeader` of the current context.
+		TokenHeader string
+		// DebugOut, if not nil, receives a dump of the handshake and the raw frames, sent and received,
+		// with the tokens redacted, for troubleshooting API incompatibilities.
+		DebugOut io.Writer
+
+		// MaxMessageSize is the maximum size in bytes for a message read from the server,
+		// a message exceeding that limit terminates the connection with a `websocket.ErrReadLimit` error.
+		// Zero means no limit.
+		MaxMessageSize int64
+		// UseNumber decodes the numbers of the non-raw fields, i.e the record's metadata timestamp,
+		// as `json.Number` instead of float64, so big numbers are not mangled.
+		UseNumber bool
+
+		// Concurrency sets the number of workers that run the asynchronous listeners per `ResponseType`,
+		// i.e {RecordMessageResponse: 4}. Types not listed run on a single worker which keeps the messages order.
+		//
+		// See `LiveConnection.OnAsync` for more.
+		Concurrency map[ResponseType]int
+		// AsyncQueueSize is the number of messages, per `ResponseType`, waiting for a free worker.
+		// When the queue is full the reader waits for a worker to become available.
+		// Defaults to `DefaultAsyncQueueSize`.
+		AsyncQueueSize int
+
+		// HeartbeatTimeout is the maximum duration to wait for the next message, i.e a "HEARTBEAT".
+		// When it passes without any message then the `ErrHeartbeatTimeout` is sent to the `Err` channel
+		// and the connection is closed, unless `ReconnectOnHeartbeatTimeout` is true.
+		// Zero means wait forever.
+		HeartbeatTimeout time.Duration
+		// ReconnectOnHeartbeatTimeout opens a new connection, which re-sends the query message,
+		// when the `HeartbeatTimeout` passes instead of closing the live connection.
+		ReconnectOnHeartbeatTimeout bool
+
+		// EnableCompression negotiates the per message compression (permessage-deflate) with the server,
+		// which shrinks the verbose JSON records many times over slow links, for some extra CPU.
+		// The connection falls back to uncompressed messages if the server does not support it.
+		// See `LiveConnection.TransferStats` to measure the savings.
+		EnableCompression bool
+		// PreferMsgpack asks the server to send the messages encoded as MessagePack, which is cheaper to decode
+		// than JSON for high-throughput queries. The connection falls back to JSON if the server does not support it.
+		// The record's key and value are still given to the listeners as JSON.
+		// See `LiveConnection.Encoding`.
+		PreferMsgpack bool
+
+		// ReuseBuffers reuses the `LiveResponse`, and its key and value, given to the (synchronous) listeners
+		// for the next message, which saves the per message allocations of high-volume queries.
+		// The listeners must copy the key and the value if they keep them after the call.
+		// The asynchronous listeners receive their own copy. See `OnRaw` for the cheapest path.
+		ReuseBuffers bool
+
+		// DeadLetter, if not nil, receives the responses that a listener failed to handle, along with the error
+		// and the listener's identifier, instead of the `Err` channel, so the bad records can be recovered or retried
+		// one by one. It's called on the go routine of the failed listener. See `DeadLetterChannel` too.
+		// The errors of the raw listeners are still sent to the `Err` channel.
+		DeadLetter func(DeadLetter)
+
+		// AckWindow requests the at-least-once delivery from the server, if it supports the acknowledgements.
+		// The listeners acknowledge each record with `LiveResponse.Ack`, or reject it with `Nack` to be redelivered,
+		// and the delivery is paused while that many records are unacknowledged, so a slow consumer applies backpressure
+		// to the server. See `LiveConnection.Acks` to check if the server accepted it. Zero disables the acknowledgements.
+		AckWindow int
+
+		// StopTimeout is the maximum duration `LiveConnection.Stop` waits for the "END" of the stopped query.
+		// Defaults to `DefaultStopTimeout`.
+		StopTimeout time.Duration
+
+		// Limit appends a "LIMIT" clause to the query message, unless it has one already.
+		// Zero means no limit. See `LimitSQL`.
+		Limit int
+		// SampleRate keeps only that fraction, from 0 to 1, of the received records,
+		// chosen by the hash of their key, so a key's records are either all kept or all dropped.
+		// It's applied client-side, zero keeps all the records. See `ParseSampleRate`.
+		SampleRate float64
+
+		// Faults, if not nil, injects artificial disconnects, delays, corrupted and out of order messages
+		// into the received messages, for testing the resilience of the applications. Not for production.
+		Faults *Faults
 	}
 
 	// LiveConnection is the websocket connection.
 	LiveConnection struct {
-		conn   *websocket.Conn
-		config LiveConfiguration
+		conn    frameConn
+		connMu  sync.Mutex // protects the conn while reconnecting.
+		writeMu sync.Mutex // serializes the writes, the query message, the acknowledgements and the stop.
+		config  LiveConfiguration
 
 		receiveStop chan struct{}
 		closed      uint32
 
 		authToken string // generated by the login and `OnSuccess` internal listener.
+		acks      *acker // set by the handshake if the server supports the acknowledgements.
 		endpoint  string // generated by the config's host and the client id.
 
-		listeners map[ResponseType][]LiveListener
-		mu        sync.RWMutex
+		faults *FaultInjector // set on the first connection if the `LiveConfiguration.Faults` is not nil.
+
+		listeners      map[ResponseType][]subscription
+		asyncListeners map[ResponseType][]subscription
+		pools          map[ResponseType]*workerPool
+		lastID         uint64 // the last `ListenerID` given by `On` and `OnAsync`.
+		wireBytes      uint64 // see `TransferStats`.
+		messageBytes   uint64
+		mu             sync.RWMutex
 
 		errors chan error // error comes from reader.
+
+		// the reader's reusable state, see fastpath.go.
+		rawListeners     []rawSubscription
+		msgReader        countingReader
+		frame            []byte
+		frameSrc         bytes.Reader
+		resp             LiveResponse
+		keyBuf, valueBuf json.RawMessage
 	}
 )
 
+// frameConn is the source of the messages of a `LiveConnection`,
+// a *websocket.Conn or a recorded session, see `OpenFileLiveConnection`.
+type frameConn interface {
+	NextReader() (messageType int, r io.Reader, err error)
+	Subprotocol() string
+	SetReadDeadline(t time.Time) error
+	Close() error
+}
+
 // OpenLiveConnection starts the websocket communication
 // and returns the client connection for further operations.
 // An error will be returned if login failed.
@@ -155,6 +316,45 @@ func OpenLiveConnection(config LiveConfiguration) (*LiveConnection, error) {
 		golog.SetLevel("debug")
 	}
 
+	if config.SampleRate < 0 || config.SampleRate > 1 {
+		return nil, fmt.Errorf("live: sample rate [%v] must be between 0 and 1", config.SampleRate)
+	}
+
+	if config.Faults != nil {
+		if err := config.Faults.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	config.Message.SQL = LimitSQL(config.Message.SQL, config.Limit)
+
+	c := newRemoteLiveConnection(config)
+	return c, c.start()
+}
+
+// Handshake connects to the live queries endpoint of the "config" and closes the connection
+// without sending the query message. It checks that the server accepts websocket connections,
+// the query's token is not checked.
+func Handshake(config LiveConfiguration) error {
+	conn, err := newRemoteLiveConnection(config).handshake()
+	if err != nil {
+		return err
+	}
+
+	return conn.Close()
+}
+
+// OpenRawConnection connects to the "endpoint" of the server of the "config", i.e "/api/ws/v1/...",
+// with the handshake of a live connection, its base path, TLS settings, headers and token header, without sending any message.
+// The caller reads and writes the frames, it's for the endpoints and the messages the `LiveConnection` does not handle.
+func OpenRawConnection(config LiveConfiguration, endpoint string) (*websocket.Conn, error) {
+	c := newRemoteLiveConnection(config)
+	c.endpoint = fmt.Sprintf("%s%s/%s", c.config.Host, api.FormatBasePath(c.config.BasePath), strings.TrimPrefix(endpoint, "/"))
+	return c.handshake()
+}
+
+// newRemoteLiveConnection returns a, not yet connected, live connection to the server of the "config".
+func newRemoteLiveConnection(config LiveConfiguration) *LiveConnection {
 	if config.HandshakeTimeout == 0 {
 		config.HandshakeTimeout = 45 * time.Second
 	}
@@ -162,52 +362,171 @@ func OpenLiveConnection(config LiveConfiguration) (*LiveConnection, error) {
 	config.Host = strings.Replace(config.Host, "https://", "wss://", 1)
 	config.Host = strings.Replace(config.Host, "http://", "ws://", 1)
 
-	//ws://localhost:24015/api/ws/v1/sql/execute
-	endpoint := fmt.Sprintf("%s/api/ws/v2/sql/execute", config.Host)
+	// the library's connections have no CLI configuration.
+	current := new(api.ClientConfig)
+	if conf.Manager != nil && conf.Manager.Config != nil {
+		current = conf.Manager.Config.GetCurrent()
+	}
+	if config.BasePath == "" {
+		config.BasePath = current.BasePath
+	}
+
+	//ws://localhost:24015/lenses/api/ws/v2/sql/execute
+	endpoint := fmt.Sprintf("%s%s/api/ws/v2/sql/execute", config.Host, api.FormatBasePath(config.BasePath))
 
-	if conf.Manager.Config.GetCurrent().Insecure == true {
-		config.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	// an invalid TLS configuration fails the REST client first.
+	if tlsConfig, err := current.TLSConfig(); err == nil && tlsConfig != nil {
+		config.TLSClientConfig = tlsConfig
 	}
 
-	c := &LiveConnection{
-		config:      config,
-		endpoint:    endpoint,
-		receiveStop: make(chan struct{}),
-		listeners:   make(map[ResponseType][]LiveListener),
-		errors:      make(chan error),
+	if config.TokenHeader == "" {
+		config.TokenHeader = current.WebsocketTokenHeader
 	}
+	config = config.withTokenHeader()
 
-	return c, c.start()
+	var requestID string
+	userAgent := api.UserAgent()
+	if conf.Manager != nil {
+		if config.DebugOut == nil && conf.Manager.DebugHTTP {
+			config.DebugOut = os.Stderr
+		}
+		requestID = conf.Manager.RequestID
+		if conf.Manager.UserAgent != "" {
+			userAgent = conf.Manager.UserAgent
+		}
+	}
+
+	config.Header = withDefaultHeader(config.Header, api.RequestIDHeader, requestID)
+	config.Header = withDefaultHeader(config.Header, "User-Agent", userAgent)
+
+	c := newLiveConnection(config)
+	c.endpoint = endpoint
+	return c
+}
+
+// withDefaultHeader returns a copy of the "header" with the "name" set to the "value", unless the value is empty
+// or the header has the "name" already.
+func withDefaultHeader(header http.Header, name, value string) http.Header {
+	if value == "" || header.Get(name) != "" {
+		return header
+	}
+
+	header = header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	header.Set(name, value)
+	return header
+}
+
+func newLiveConnection(config LiveConfiguration) *LiveConnection {
+	return &LiveConnection{
+		config:         config,
+		receiveStop:    make(chan struct{}),
+		listeners:      make(map[ResponseType][]subscription),
+		asyncListeners: make(map[ResponseType][]subscription),
+		pools:          make(map[ResponseType]*workerPool),
+		errors:         make(chan error),
+	}
 }
 
 func (c *LiveConnection) start() error {
+	conn, err := c.dial()
+	if err != nil {
+		return err
+	}
+
+	// set the websocket connection.
+	c.conn = c.withFaults(conn)
+
+	go c.readLoop()
+	return nil
+}
+
+// dial connects to the websocket server and sends the configured query message.
+func (c *LiveConnection) dial() (*websocket.Conn, error) {
+	conn, err := c.handshake()
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.write(conn, c.config.Message)
+	if err != nil {
+		golog.Debug(err)
+		conn.Close()
+		return nil, err
+	}
+
+	if c.config.MaxMessageSize > 0 {
+		conn.SetReadLimit(c.config.MaxMessageSize)
+	}
+
+	return conn, nil
+}
+
+// handshake connects to the websocket server.
+func (c *LiveConnection) handshake() (*websocket.Conn, error) {
 	// first connect, handshake with the websocket server for upgrade.
 	dialer := websocket.Dialer{
-		Proxy:            http.ProxyFromEnvironment,
-		HandshakeTimeout: c.config.HandshakeTimeout,
-		ReadBufferSize:   c.config.ReadBufferSize,
-		WriteBufferSize:  c.config.WriteBufferSize,
-		TLSClientConfig:  c.config.TLSClientConfig,
+		Proxy:             http.ProxyFromEnvironment,
+		HandshakeTimeout:  c.config.HandshakeTimeout,
+		ReadBufferSize:    c.config.ReadBufferSize,
+		WriteBufferSize:   c.config.WriteBufferSize,
+		TLSClientConfig:   c.config.TLSClientConfig,
+		EnableCompression: c.config.EnableCompression,
+		NetDialContext:    c.dialCounting,
+		Subprotocols:      c.config.subprotocols(),
 	}
 
-	conn, _, err := dialer.Dial(c.endpoint, nil)
+	header := c.config.Header.Clone()
+	if window := c.ackHeader(); window != "" {
+		if header == nil {
+			header = make(http.Header)
+		}
+		header.Set(AckWindowHeader, window)
+	}
+
+	conn, resp, err := dialer.Dial(c.endpoint, header)
+	c.debugHandshake(header, resp, err)
 
 	if err != nil {
-		err = fmt.Errorf("connect failure for [%s]: %v", c.config.Host, err)
+		if resp != nil && (resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden) {
+			err = fmt.Errorf("connect failure for [%s]: %w", c.config.Host, api.ErrAuthFailed)
+		} else {
+			err = fmt.Errorf("connect failure for [%s]: %v", c.config.Host, err)
+		}
 		golog.Debug(err)
-		return err
+		return nil, err
 	}
 
-	err = conn.WriteJSON(c.config.Message)
+	acks := c.newAcker(conn, resp)
+	c.connMu.Lock()
+	c.acks = acks
+	c.connMu.Unlock()
+
+	return conn, nil
+}
+
+// reconnect replaces the current connection with a new one, the query message is sent again.
+func (c *LiveConnection) reconnect() error {
+	golog.Debugf("reconnecting to [%s]...", c.config.Host)
+
+	conn, err := c.dial()
 	if err != nil {
-		golog.Debug(err)
 		return err
 	}
 
-	// set the websocket connection.
-	c.conn = conn
+	c.connMu.Lock()
+	old := c.conn
+	c.conn = c.withFaults(conn)
+	c.connMu.Unlock()
+
+	old.Close()
+	if c.isClosed() {
+		// closed while dialing.
+		conn.Close()
+	}
 
-	go c.readLoop()
 	return nil
 }
 
@@ -225,7 +544,7 @@ func (c *LiveConnection) Wait(interruptSignal <-chan os.Signal) error {
 // }
 
 // Err can be used to receive the errors coming from the communication,
-// the listeners' errors are sending to that channel too.
+// the listeners' errors are sending to that channel too, unless the `LiveConfiguration.DeadLetter` is set.
 func (c *LiveConnection) Err() <-chan error {
 	return c.errors
 }
@@ -243,19 +562,70 @@ func (c *LiveConnection) readLoop() {
 			golog.Debugf("stop receiving by signal")
 			return
 		default:
-			resp := LiveResponse{}
-			if err := c.conn.ReadJSON(&resp); err != nil {
-				if _, is := err.(*net.OpError); is {
-					// send it as it's and do not exit, caller may want to check if should manage that error or just ignore it.
-					// caused by manual interruption(ctrl/cmd+c) or real network issue(this is why we continue after the error here).
-					c.sendErr(err)
+			resp := c.acquireResponse()
+			decoded, err := c.readResponse(resp)
+			c.releaseResponse(resp)
+			if err != nil {
+				if c.isClosed() {
+					// caused by manual interruption(ctrl/cmd+c), `Close` was called while reading.
+					return
+				}
+
+				var closedErr *ConnectionClosedError
+				if !errors.As(err, &closedErr) {
+					// the message could not be decoded, continue with the next one,
+					// if the connection failed while decoding then the next read reports it.
+					c.sendErr(fmt.Errorf("live: read json: [%v]", err))
+					continue
+				}
+
+				if netErr, ok := closedErr.Cause.(net.Error); ok && netErr.Timeout() && c.config.HeartbeatTimeout > 0 {
+					// the stream is stalled.
+					c.sendErr(ErrHeartbeatTimeout)
+					if !c.config.ReconnectOnHeartbeatTimeout || c.isClosed() {
+						return
+					}
+
+					if err = c.reconnect(); err != nil {
+						c.sendErr(err)
+						return
+					}
 					continue
 				}
-				c.sendErr(fmt.Errorf("live: read json: [%v]", err))
+
+				if closedErr.Cause == websocket.ErrReadLimit {
+					c.sendErr(fmt.Errorf("live: read json: message exceeds the [%d] bytes limit", c.config.MaxMessageSize))
+					return
+				}
+
+				c.sendErr(err)
+				return
+			}
+
+			if !decoded {
+				continue
+			}
+
+			if c.config.Debug {
+				golog.Debugf("read: [%#+v]", *resp)
+			}
+
+			if resp.Type == RecordMessageResponse && !sampled(*resp, c.config.SampleRate) {
+				if c.acks != nil {
+					// dropped on purpose, it should not be redelivered.
+					if err = c.acks.drop(resp.Data.Metadata); err != nil {
+						c.sendErr(err)
+					}
+				}
 				continue
 			}
 
-			golog.Debugf("read: [%#+v]", resp)
+			if resp.Type == RecordMessageResponse && c.acks != nil {
+				resp.acks = c.acks
+				if err = c.acks.delivered(); err != nil {
+					c.sendErr(err)
+				}
+			}
 
 			// fire.
 			c.mu.RLock()
@@ -263,15 +633,62 @@ func (c *LiveConnection) readLoop() {
 			c.mu.RUnlock()
 
 			if ok {
-				for _, cb := range callbacks {
-					if err := cb(resp); err != nil {
+				for _, sub := range callbacks {
+					if err := sub.cb(*resp); err != nil {
 						// return err // break and exit the loop on first failure.
-						c.sendErr(err) // don't break, just add the error.
+						c.listenerFailed(sub, *resp, false, err) // don't break, just add the error.
 					}
 				}
 			}
+
+			c.dispatchAsync(*resp)
+		}
+	}
+}
+
+// readResponse decodes the next message from the connection's frame reader, through a `json.Decoder`,
+// which saves a copy of the frame but still buffers the whole message before decoding it.
+// The frame is read into a buffer first if there are raw listeners, the buffers are reused, the frames are debugged
+// or it's MessagePack encoded. It reports false if the message was not decoded because there are only raw listeners.
+func (c *LiveConnection) readResponse(resp *LiveResponse) (bool, error) {
+	if timeout := c.config.HeartbeatTimeout; timeout > 0 {
+		// any message, not just the heartbeats, shows that the stream is alive.
+		c.conn.SetReadDeadline(time.Now().Add(timeout))
+	}
+
+	typ, r, err := c.conn.NextReader()
+	if err != nil {
+		// the connection can't be read anymore after a failed `NextReader`.
+		return false, &ConnectionClosedError{Cause: err}
+	}
+
+	c.msgReader = countingReader{Reader: r, n: &c.messageBytes}
+	r = &c.msgReader
+
+	msgpack := typ == websocket.BinaryMessage && c.conn.Subprotocol() == string(EncodingMsgpack)
+	raw, decoded := c.hasListeners()
+	if !raw && !msgpack && !c.config.ReuseBuffers && c.config.DebugOut == nil {
+		return true, c.decodeJSON(r, resp)
+	}
+
+	frame, err := c.readFrame(r)
+	if err != nil {
+		return false, err
+	}
+	c.debugFrame(typ, frame)
+
+	if raw {
+		c.fireRaw(frame)
+		if !decoded {
+			return false, nil
 		}
 	}
+
+	if msgpack {
+		return true, c.decodeMsgpack(frame, resp)
+	}
+
+	return true, c.decodeJSONFrame(frame, resp)
 }
 
 // --- Events handles incoming messages with style. ---
@@ -283,45 +700,152 @@ func (c *LiveConnection) readLoop() {
 // See `On` too.
 type LiveListener func(LiveResponse) error
 
+// ListenerID identifies a listener added by `On`, `OnAsync` or `Once`,
+// it can be passed to `Off` in order to remove that listener.
+type ListenerID uint64
+
+// subscription is a registered listener and its identifier.
+type subscription struct {
+	id ListenerID
+	cb LiveListener
+}
+
+// nextID returns a new, unique per connection, `ListenerID`.
+func (c *LiveConnection) nextID() ListenerID {
+	return ListenerID(atomic.AddUint64(&c.lastID, 1))
+}
+
 // On adds a listener, a websocket message subscriber based on the given "typ" `ResponseType`.
 // Use the `WildcardResponse` to subscribe to all message types.
-func (c *LiveConnection) On(typ ResponseType, cb LiveListener) {
+//
+// It returns the listener's identifier which can be used to remove it, see `Off`.
+func (c *LiveConnection) On(typ ResponseType, cb LiveListener) ListenerID {
+	id := c.nextID()
+	c.subscribe(c.listeners, typ, subscription{id, cb})
+	return id
+}
+
+// OnAsync adds a listener like `On` does but the listener runs on the worker pool of the "typ" `ResponseType`
+// instead of the reader's go routine, so a slow listener does not delay the messages of the other types,
+// i.e a slow "RECORD" listener does not stall the heartbeats.
+// The number of workers per type is set by the `LiveConfiguration.Concurrency` field,
+// when more than one worker is used the listener may be called concurrently and out of order.
+//
+// It returns the listener's identifier which can be used to remove it, see `Off`.
+func (c *LiveConnection) OnAsync(typ ResponseType, cb LiveListener) ListenerID {
+	id := c.nextID()
+	c.subscribe(c.asyncListeners, typ, subscription{id, cb})
+
+	c.mu.Lock()
 	if typ == WildcardResponse {
-		c.OnError(cb)
-		c.OnInvalidRequest(cb)
-		c.OnRecordMessage(cb)
-		c.OnHeartbeat(cb)
-		c.OnSuccess(cb)
-		c.OnStats(cb)
-		c.OnEnd(cb)
-		return
+		for _, t := range responseTypes {
+			c.acquireWorkerPool(t)
+		}
+	} else {
+		c.acquireWorkerPool(typ)
+	}
+	c.mu.Unlock()
+
+	return id
+}
+
+// Once adds a listener which is removed after its first call.
+// A `WildcardResponse` listener fires once, on the first message of any type.
+//
+// It returns the listener's identifier which can be used to remove it before it fires, see `Off`.
+func (c *LiveConnection) Once(typ ResponseType, cb LiveListener) ListenerID {
+	var (
+		id    = c.nextID()
+		fired uint32
+	)
+
+	once := func(resp LiveResponse) error {
+		if !atomic.CompareAndSwapUint32(&fired, 0, 1) {
+			return nil
+		}
+
+		c.Off(typ, id)
+		return cb(resp)
+	}
+
+	c.subscribe(c.listeners, typ, subscription{id, once})
+	return id
+}
+
+// Off removes the listener of "id", as returned by `On`, `OnAsync` or `Once`, from the "typ" `ResponseType`.
+// Use the `WildcardResponse` to remove it from all message types.
+//
+// It reports whether a listener was removed.
+func (c *LiveConnection) Off(typ ResponseType, id ListenerID) bool {
+	types := []ResponseType{typ}
+	if typ == WildcardResponse {
+		types = responseTypes
 	}
 
 	c.mu.Lock()
-	c.listeners[typ] = append(c.listeners[typ], cb)
-	c.mu.Unlock()
+	defer c.mu.Unlock()
+
+	removed := false
+	for _, t := range types {
+		for _, listeners := range []map[ResponseType][]subscription{c.listeners, c.asyncListeners} {
+			subs := listeners[t]
+			for i, sub := range subs {
+				if sub.id != id {
+					continue
+				}
+
+				// copy instead of removing in place, the readers may still range over the old slice.
+				newSubs := make([]subscription, 0, len(subs)-1)
+				newSubs = append(newSubs, subs[:i]...)
+				listeners[t] = append(newSubs, subs[i+1:]...)
+				removed = true
+				break
+			}
+		}
+	}
+
+	return removed
+}
+
+// subscribe registers the "sub" to the "listeners" of the "typ" `ResponseType`, or all types if `WildcardResponse`.
+func (c *LiveConnection) subscribe(listeners map[ResponseType][]subscription, typ ResponseType, sub subscription) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if typ == WildcardResponse {
+		for _, t := range responseTypes {
+			listeners[t] = append(listeners[t], sub)
+		}
+		return
+	}
+
+	listeners[typ] = append(listeners[typ], sub)
 }
 
 // OnError adds a listener, a websocket message subscriber based on the "ERROR" `ResponseType`.
-func (c *LiveConnection) OnError(cb LiveListener) { c.On(ErrorResponse, cb) }
+func (c *LiveConnection) OnError(cb LiveListener) ListenerID { return c.On(ErrorResponse, cb) }
 
 // OnInvalidRequest adds a listener, a websocket message subscriber based on the "INVALIDREQUEST" `ResponseType`.
-func (c *LiveConnection) OnInvalidRequest(cb LiveListener) { c.On(InvalidRequestResponse, cb) }
+func (c *LiveConnection) OnInvalidRequest(cb LiveListener) ListenerID {
+	return c.On(InvalidRequestResponse, cb)
+}
 
 // OnRecordMessage adds a listener, a websocket message subscriber based on the "RECORD" `ResponseType`.
-func (c *LiveConnection) OnRecordMessage(cb LiveListener) { c.On(RecordMessageResponse, cb) }
+func (c *LiveConnection) OnRecordMessage(cb LiveListener) ListenerID {
+	return c.On(RecordMessageResponse, cb)
+}
 
 // OnHeartbeat adds a listener, a websocket message subscriber based on the "HEARTBEAT" `ResponseType`.
-func (c *LiveConnection) OnHeartbeat(cb LiveListener) { c.On(HeartbeatResponse, cb) }
+func (c *LiveConnection) OnHeartbeat(cb LiveListener) ListenerID { return c.On(HeartbeatResponse, cb) }
 
 // OnSuccess adds a listener, a websocket message subscriber based on the "SUCCESS" `ResponseType`.
-func (c *LiveConnection) OnSuccess(cb LiveListener) { c.On(SuccessResponse, cb) }
+func (c *LiveConnection) OnSuccess(cb LiveListener) ListenerID { return c.On(SuccessResponse, cb) }
 
 // OnStats adds a listener, a websocket message subscriber based on the "STATS" `ResponseType`.
-func (c *LiveConnection) OnStats(cb LiveListener) { c.On(StatsResponse, cb) }
+func (c *LiveConnection) OnStats(cb LiveListener) ListenerID { return c.On(StatsResponse, cb) }
 
 // OnEnd adds a listener, a websocket message subscriber based on the "END" `ResponseType`.
-func (c *LiveConnection) OnEnd(cb LiveListener) { c.On(EndResponse, cb) }
+func (c *LiveConnection) OnEnd(cb LiveListener) ListenerID { return c.On(EndResponse, cb) }
 
 // Close closes the underline websocket connection
 // and stops receiving any new message from the websocket server.
@@ -338,5 +862,12 @@ func (c *LiveConnection) Close() error {
 
 	atomic.StoreUint32(&c.closed, 1)
 	close(c.receiveStop) // stop receiving, see `readLoop`.
+
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
 	return c.conn.Close()
 }
+
+func (c *LiveConnection) isClosed() bool {
+	return atomic.LoadUint32(&c.closed) > 0
+}
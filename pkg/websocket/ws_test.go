@@ -0,0 +1,379 @@
+package websocket
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/lensesio/lenses-go/pkg/api"
+	test "github.com/lensesio/lenses-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestServer starts a websocket server which reads the query message and then,
+// once "start" is closed, writes the "messages".
+func newTestServer(t *testing.T, start <-chan struct{}, messages ...string) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+
+		var msg Message
+		if err = conn.ReadJSON(&msg); err != nil {
+			return
+		}
+
+		<-start
+		for _, m := range messages {
+			if err = conn.WriteMessage(websocket.TextMessage, []byte(m)); err != nil {
+				return
+			}
+		}
+
+		// keep the connection open until the client closes it.
+		conn.ReadMessage()
+	}))
+}
+
+func openTestConnection(t *testing.T, srv *httptest.Server, config LiveConfiguration) *LiveConnection {
+	test.SetupMasterContext()
+
+	config.Host = srv.URL
+	conn, err := OpenLiveConnection(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return conn
+}
+
+func TestLiveConnectionUseNumber(t *testing.T) {
+	start := make(chan struct{})
+	srv := newTestServer(t, start, `{"type":"RECORD","data":{"value":{"id":1},"metadata":{"timestamp":1600000000000123}}}`)
+	defer srv.Close()
+
+	conn := openTestConnection(t, srv, LiveConfiguration{UseNumber: true})
+	defer conn.Close()
+
+	got := make(chan interface{}, 1)
+	conn.OnRecordMessage(func(resp LiveResponse) error {
+		got <- resp.Data.Metadata.Timestamp
+		return nil
+	})
+	close(start)
+
+	select {
+	case ts := <-got:
+		assert.Equal(t, "1600000000000123", ts.(interface{ String() string }).String())
+	case err := <-conn.Err():
+		t.Fatal(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+}
+
+func TestLiveConnectionOnAsync(t *testing.T) {
+	start := make(chan struct{})
+	srv := newTestServer(t, start,
+		`{"type":"RECORD","data":{"value":1}}`,
+		`{"type":"HEARTBEAT"}`,
+	)
+	defer srv.Close()
+
+	conn := openTestConnection(t, srv, LiveConfiguration{})
+	defer conn.Close()
+
+	var records uint32
+	release := make(chan struct{})
+	conn.OnAsync(RecordMessageResponse, func(resp LiveResponse) error {
+		<-release
+		atomic.AddUint32(&records, 1)
+		return nil
+	})
+
+	heartbeat := make(chan struct{})
+	conn.OnHeartbeat(func(resp LiveResponse) error {
+		close(heartbeat)
+		return nil
+	})
+	close(start)
+
+	select {
+	case <-heartbeat:
+		// the heartbeat arrived while the record's listener still waits.
+		assert.Equal(t, uint32(0), atomic.LoadUint32(&records))
+	case <-time.After(5 * time.Second):
+		t.Fatal("heartbeat was blocked by the record listener")
+	}
+
+	close(release)
+}
+
+func TestLiveConnectionOnceAndOff(t *testing.T) {
+	start := make(chan struct{})
+	srv := newTestServer(t, start,
+		`{"type":"HEARTBEAT"}`,
+		`{"type":"HEARTBEAT"}`,
+		`{"type":"END"}`,
+	)
+	defer srv.Close()
+
+	conn := openTestConnection(t, srv, LiveConfiguration{})
+	defer conn.Close()
+
+	var once, removed, all uint32
+	conn.Once(HeartbeatResponse, func(resp LiveResponse) error {
+		atomic.AddUint32(&once, 1)
+		return nil
+	})
+
+	id := conn.OnHeartbeat(func(resp LiveResponse) error {
+		atomic.AddUint32(&removed, 1)
+		return nil
+	})
+	assert.True(t, conn.Off(HeartbeatResponse, id))
+	assert.False(t, conn.Off(HeartbeatResponse, id))
+
+	conn.OnHeartbeat(func(resp LiveResponse) error {
+		atomic.AddUint32(&all, 1)
+		return nil
+	})
+
+	end := make(chan struct{})
+	conn.OnEnd(func(resp LiveResponse) error {
+		close(end)
+		return nil
+	})
+	close(start)
+
+	select {
+	case <-end:
+		assert.Equal(t, uint32(1), atomic.LoadUint32(&once))
+		assert.Equal(t, uint32(0), atomic.LoadUint32(&removed))
+		assert.Equal(t, uint32(2), atomic.LoadUint32(&all))
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+}
+
+func TestLiveConnectionHeartbeatTimeout(t *testing.T) {
+	start := make(chan struct{})
+	close(start)
+	srv := newTestServer(t, start)
+	defer srv.Close()
+
+	conn := openTestConnection(t, srv, LiveConfiguration{HeartbeatTimeout: 50 * time.Millisecond})
+	defer conn.Close()
+
+	select {
+	case err := <-conn.Err():
+		assert.Equal(t, ErrHeartbeatTimeout, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+}
+
+func TestLiveConnectionReconnectOnHeartbeatTimeout(t *testing.T) {
+	var connections uint32
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		conn.ReadMessage()
+		if atomic.AddUint32(&connections, 1) > 1 {
+			conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"END"}`))
+		}
+		conn.ReadMessage()
+	}))
+	defer srv.Close()
+
+	conn := openTestConnection(t, srv, LiveConfiguration{
+		HeartbeatTimeout:            50 * time.Millisecond,
+		ReconnectOnHeartbeatTimeout: true,
+	})
+	defer conn.Close()
+
+	end := make(chan struct{})
+	conn.OnEnd(func(resp LiveResponse) error {
+		close(end)
+		return nil
+	})
+
+	select {
+	case err := <-conn.Err():
+		assert.Equal(t, ErrHeartbeatTimeout, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+
+	select {
+	case <-end:
+		assert.Equal(t, uint32(2), atomic.LoadUint32(&connections))
+	case <-time.After(5 * time.Second):
+		t.Fatal("did not reconnect")
+	}
+}
+
+func TestLiveConnectionClosedByServer(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+
+		conn.ReadMessage()
+		conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "bye"))
+		conn.Close()
+	}))
+	defer srv.Close()
+
+	conn := openTestConnection(t, srv, LiveConfiguration{})
+	defer conn.Close()
+
+	select {
+	case err := <-conn.Err():
+		assert.True(t, errors.Is(err, ErrConnectionClosed))
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+}
+
+func TestLiveConnectionHeaderAndSubprotocols(t *testing.T) {
+	header := make(chan http.Header, 1)
+	upgrader := websocket.Upgrader{Subprotocols: []string{"lenses.v2"}}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header <- r.Header
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		conn.ReadMessage()
+		conn.ReadMessage()
+	}))
+	defer srv.Close()
+
+	h, err := ParseHeader([]string{"X-Forwarded-User: alice", "Cookie: session=1"})
+	assert.NoError(t, err)
+
+	conn := openTestConnection(t, srv, LiveConfiguration{Header: h, Subprotocols: []string{"lenses.v2"}})
+	defer conn.Close()
+
+	got := <-header
+	assert.Equal(t, "alice", got.Get("X-Forwarded-User"))
+	assert.Equal(t, "session=1", got.Get("Cookie"))
+	assert.Equal(t, api.UserAgent(), got.Get("User-Agent"))
+	assert.Equal(t, "lenses.v2", got.Get("Sec-Websocket-Protocol"))
+	assert.Equal(t, "lenses.v2", conn.Subprotocol())
+	assert.Equal(t, EncodingJSON, conn.Encoding())
+
+	_, err = ParseHeader([]string{"no value"})
+	assert.EqualError(t, err, "invalid header [no value], expected name: value")
+}
+
+func TestLiveConnectionTokenHeader(t *testing.T) {
+	type handshake struct {
+		header http.Header
+		msg    map[string]interface{}
+	}
+
+	got := make(chan handshake, 1)
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+
+		var msg map[string]interface{}
+		conn.ReadJSON(&msg)
+		got <- handshake{r.Header, msg}
+		conn.ReadMessage()
+	}))
+	defer srv.Close()
+
+	for _, name := range []string{TokenHeaderLenses, TokenHeaderAuthorization} {
+		conn := openTestConnection(t, srv, LiveConfiguration{Message: Message{Token: "t0ken", SQL: "SELECT 1"}, TokenHeader: name})
+
+		h := <-got
+		_, inMessage := h.msg["token"]
+		assert.False(t, inMessage, name)
+		assert.Equal(t, "SELECT 1", h.msg["sql"])
+		if name == TokenHeaderAuthorization {
+			assert.Equal(t, "Bearer t0ken", h.header.Get(name))
+		} else {
+			assert.Equal(t, "t0ken", h.header.Get(name))
+		}
+		conn.Close()
+	}
+}
+
+func TestLiveConnectionBasePath(t *testing.T) {
+	paths := make(chan string, 1)
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths <- r.URL.Path
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		conn.ReadMessage()
+	}))
+	defer srv.Close()
+
+	test.SetupMasterContext()
+	assert.NoError(t, Handshake(LiveConfiguration{Host: srv.URL, BasePath: "lenses/"}))
+	assert.Equal(t, "/lenses/api/ws/v2/sql/execute", <-paths)
+}
+
+func TestLiveConnectionDebugOut(t *testing.T) {
+	start := make(chan struct{})
+	srv := newTestServer(t, start, `{"type":"RECORD","data":{"value":{"id":1}}}`)
+	defer srv.Close()
+
+	var out bytes.Buffer
+	conn := openTestConnection(t, srv, LiveConfiguration{Message: Message{Token: "t0ken", SQL: "SELECT 1"}, DebugOut: &out})
+	defer conn.Close()
+
+	got := make(chan struct{}, 1)
+	conn.OnRecordMessage(func(resp LiveResponse) error {
+		got <- struct{}{}
+		return nil
+	})
+	close(start)
+
+	select {
+	case <-got:
+	case err := <-conn.Err():
+		t.Fatal(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+
+	dump := out.String()
+	assert.Contains(t, dump, ">>> GET ws://")
+	assert.Contains(t, dump, "<<< 101 Switching Protocols")
+	assert.Contains(t, dump, `> {"token":"[REDACTED]","sql":"SELECT 1","live":false,"stats":0}`)
+	assert.Contains(t, dump, `< {"type":"RECORD","data":{"value":{"id":1}}}`)
+	assert.NotContains(t, dump, "t0ken")
+}
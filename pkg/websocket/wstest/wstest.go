@@ -0,0 +1,126 @@
+// Package wstest is a mock server of the live queries, for the tests of the applications which consume them
+// through a `websocket.LiveConnection`. It answers each query with canned messages and can inject the
+// `websocket.Faults` on the server side, so the applications can verify their resilience against the stream.
+package wstest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	gorilla "github.com/gorilla/websocket"
+	"github.com/lensesio/lenses-go/pkg/websocket"
+)
+
+// Server is a mock live queries server, its URL is the `websocket.LiveConfiguration.Host` of the connections.
+// It reads the query message of each connection and writes its messages, with the faults of its `Faults`,
+// and keeps the connection open until the client closes it.
+type Server struct {
+	*httptest.Server
+
+	messages []string
+	faults   *websocket.Faults
+
+	mu      sync.Mutex
+	queries []websocket.Message
+}
+
+// NewServer starts a `Server` which writes the "messages" to each connection, see `Record` and `End`.
+func NewServer(messages ...string) *Server {
+	return NewFaultyServer(nil, messages...)
+}
+
+// NewFaultyServer starts a `Server` which writes the "messages" to each connection with the "faults" injected,
+// a disconnect closes the connection. Each connection has its own faults, by the seed of the "faults" if not zero.
+func NewFaultyServer(faults *websocket.Faults, messages ...string) *Server {
+	s := &Server{messages: messages, faults: faults}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// Queries returns the query messages the server received, in order.
+func (s *Server) Queries() []websocket.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]websocket.Message(nil), s.queries...)
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	upgrader := gorilla.Upgrader{}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	var msg websocket.Message
+	if err = conn.ReadJSON(&msg); err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.queries = append(s.queries, msg)
+	s.mu.Unlock()
+
+	var faults *websocket.FaultInjector
+	if s.faults != nil {
+		faults = websocket.NewFaultInjector(*s.faults)
+	}
+
+	for _, m := range s.messages {
+		frames := [][]byte{[]byte(m)}
+		if faults != nil {
+			action := faults.Next([]byte(m))
+			if action.Disconnect {
+				return
+			}
+
+			time.Sleep(action.Delay)
+			frames = action.Frames
+		}
+
+		for _, frame := range frames {
+			if err = conn.WriteMessage(gorilla.TextMessage, frame); err != nil {
+				return
+			}
+		}
+	}
+
+	if faults != nil {
+		if held := faults.Flush(); held != nil {
+			if err = conn.WriteMessage(gorilla.TextMessage, held); err != nil {
+				return
+			}
+		}
+	}
+
+	// keep the connection open until the client closes it.
+	conn.ReadMessage()
+}
+
+// Record returns a "RECORD" message of the "key" and "value" JSON, of the "partition" and "offset",
+// an empty "key" is omitted.
+func Record(partition, offset int, key, value string) string {
+	data := struct {
+		Key      json.RawMessage    `json:"key,omitempty"`
+		Value    json.RawMessage    `json:"value"`
+		Metadata websocket.MetaData `json:"metadata"`
+	}{Value: json.RawMessage(value), Metadata: websocket.MetaData{Partition: partition, Offset: offset}}
+	if key != "" {
+		data.Key = json.RawMessage(key)
+	}
+
+	b, _ := json.Marshal(struct {
+		Type websocket.ResponseType `json:"type"`
+		Data interface{}            `json:"data"`
+	}{Type: websocket.RecordMessageResponse, Data: data})
+	return string(b)
+}
+
+// End returns the "END" message of a query.
+func End() string {
+	return `{"type":"` + string(websocket.EndResponse) + `"}`
+}
@@ -0,0 +1,101 @@
+package wstest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lensesio/lenses-go/pkg/websocket"
+	test "github.com/lensesio/lenses-go/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func openConnection(t *testing.T, srv *Server) *websocket.LiveConnection {
+	test.SetupMasterContext()
+
+	conn, err := websocket.OpenLiveConnection(websocket.LiveConfiguration{Host: srv.URL, Message: websocket.Message{SQL: "SELECT * FROM payments"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return conn
+}
+
+func TestServer(t *testing.T) {
+	srv := NewServer(Record(0, 1, `"a"`, `{"id":1}`), End())
+	defer srv.Close()
+
+	conn := openConnection(t, srv)
+	defer conn.Close()
+
+	records := make(chan websocket.Data, 1)
+	conn.OnRecordMessage(func(resp websocket.LiveResponse) error {
+		records <- resp.Data
+		return nil
+	})
+
+	end := make(chan struct{})
+	conn.OnEnd(func(resp websocket.LiveResponse) error {
+		close(end)
+		return nil
+	})
+
+	select {
+	case <-end:
+	case err := <-conn.Err():
+		t.Fatal(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+
+	record := <-records
+	assert.Equal(t, `"a"`, string(record.Key))
+	assert.Equal(t, `{"id":1}`, string(record.Value))
+	assert.Equal(t, 1, record.Metadata.Offset)
+
+	if queries := srv.Queries(); assert.Len(t, queries, 1) {
+		assert.Equal(t, "SELECT * FROM payments", queries[0].SQL)
+	}
+}
+
+func TestFaultyServerReorder(t *testing.T) {
+	srv := NewFaultyServer(&websocket.Faults{ReorderRate: 1}, Record(0, 1, "", "1"), Record(0, 2, "", "2"), Record(0, 3, "", "3"))
+	defer srv.Close()
+
+	conn := openConnection(t, srv)
+	defer conn.Close()
+
+	offsets := make(chan int, 3)
+	conn.OnRecordMessage(func(resp websocket.LiveResponse) error {
+		offsets <- resp.Data.Metadata.Offset
+		return nil
+	})
+
+	// the third one is held back and flushed after the rest.
+	for _, expected := range []int{2, 1, 3} {
+		select {
+		case offset := <-offsets:
+			assert.Equal(t, expected, offset)
+		case err := <-conn.Err():
+			t.Fatal(err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout")
+		}
+	}
+}
+
+func TestFaultyServerDisconnect(t *testing.T) {
+	srv := NewFaultyServer(&websocket.Faults{DisconnectRate: 1}, Record(0, 1, "", "1"))
+	defer srv.Close()
+
+	conn := openConnection(t, srv)
+	defer conn.Close()
+
+	select {
+	case err := <-conn.Err():
+		var closedErr *websocket.ConnectionClosedError
+		assert.True(t, errors.As(err, &closedErr))
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+}
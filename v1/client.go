@@ -0,0 +1,235 @@
+// Package v1 is the stable client of the Lenses API, the `Client` interface keeps its methods and their signatures
+// within the major version, so a downstream module upgrades the library without breaking on every server feature.
+// The endpoints which are not stable yet are in the experimental package, they are moved here when they are.
+package v1
+
+import (
+	"net/http"
+
+	"github.com/lensesio/lenses-go/pkg/api"
+	"github.com/lensesio/lenses-go/pkg/websocket"
+)
+
+//go:generate mockgen -destination=../pkg/mock/lenses.go -package=mock github.com/lensesio/lenses-go/v1 Client,LiveStream
+
+// Client is the stable Lenses REST API, as implemented by the `api.Client`.
+// Accept a `Client` instead of the *api.Client so the calls can be mocked, see the `go:generate` directive
+// for the gomock stubs.
+type Client interface {
+	// alert channel templates
+	GetAlertChannelTemplates() (response []api.ChannelTemplate, err error)
+
+	// alerts
+	CreateAlertSettingsCondition(alertID, condition string, channels []string) error
+	DeleteAlertEvents(timestamp int64) (err error)
+	DeleteAlertSettingCondition(alertSettingID int, conditionUUID string) error
+	EnableAlertSetting(id int, enable bool) error
+	GetAlertSetting(id int) (setting api.AlertSetting, err error)
+	GetAlertSettingConditions(id int) ([]api.AlertSettingCondition, error)
+	GetAlertSettings() (api.AlertSettings, error)
+	GetAlerts(pageSize int) (alerts []api.Alert, err error)
+	SetAlertSettingsConsumerCondition(alertID string, conditionID string, consumerAlert api.ConsumerAlertConditionRequestv1) error
+	SetAlertSettingsProducerCondition(alertID, conditionID, topic string, threshold api.Threshold, duration string, channels []string) error
+	UpdateAlertSettings(alertSettings api.AlertSettingsPayload) error
+
+	// audit channel templates
+	GetAuditChannelTemplates() (response []api.ChannelTemplate, err error)
+
+	// channels
+	CreateChannel(chnl api.ChannelPayload, channelPath string) error
+	DeleteChannel(path, channelID string) error
+	GetChannels(path string, page int, pageSize int, sortField, sortOrder, templateName, channelName string) (response api.ChannelResponse, err error)
+	GetChannelsWithDetails(path string, page int, pageSize int, sortField, sortOrder, templateName, channelName string) (response api.ChannelResponseWithDetails, err error)
+	UpdateChannel(chnl api.ChannelPayload, channelPath, channelID string) error
+
+	// clusters, topics, connectors, processors, quotas, ACLs, policies and the rest
+	CancelQuery(id int64) (bool, error)
+	CreateConnector(clusterName, name string, config api.ConnectorConfig) (connector api.Connector, err error)
+	CreateOrUpdateACL(acl api.ACL) error
+	CreateOrUpdateQuotaForAllClients(config api.QuotaConfig) error
+	CreateOrUpdateQuotaForAllUsers(config api.QuotaConfig) error
+	CreateOrUpdateQuotaForClient(clientID string, config api.QuotaConfig) error
+	CreateOrUpdateQuotaForUser(user string, config api.QuotaConfig) error
+	CreateOrUpdateQuotaForUserAllClients(user string, config api.QuotaConfig) error
+	CreateOrUpdateQuotaForUserClient(user, clientID string, config api.QuotaConfig) error
+	CreateOrUpdateTopicMetadata(metadata api.TopicMetadata) error
+	CreatePolicy(policy api.DataPolicyRequest) error
+	CreateProcessor(name string, sql string, runners int, clusterName, namespace, pipeline string, processorID string) error
+	CreateTopic(topicName string, replication, partitions int, configs api.KV) error
+	CreateUserProfilePropertyValue(property, value string) error
+	DeleteACL(acl api.ACL) error
+	DeleteAuditEntries(timestamp int64) (err error)
+	DeleteConnector(clusterName, name string) error
+	DeleteDynamicBrokerConfigs(brokerID int, configKeysToBeReseted ...string) error
+	DeleteDynamicClusterConfigs(configKeysToBeReset ...string) error
+	DeletePolicy(id string) error
+	DeleteProcessor(processorNameOrID string) error
+	DeleteQuotaForAllClients(propertiesToRemove ...string) error
+	DeleteQuotaForAllUsers(propertiesToRemove ...string) error
+	DeleteQuotaForClient(clientID string, propertiesToRemove ...string) error
+	DeleteQuotaForUser(user string, propertiesToRemove ...string) error
+	DeleteQuotaForUserAllClients(user string, propertiesToRemove ...string) error
+	DeleteQuotaForUserClient(user, clientID string, propertiesToRemove ...string) error
+	DeleteTopic(topicName string) error
+	DeleteTopicMetadata(topicName string) error
+	DeleteTopicRecords(topicName string, fromPartition int, toOffset int64) error
+	DeleteUserProfilePropertyValue(property, value string) error
+	Do(method, path, contentType string, send []byte, options ...api.RequestOption) (*http.Response, error)
+	GetACLs() ([]api.ACL, error)
+	GetAccessToken() string
+	GetAuditEntries() (entries []api.AuditEntry, err error)
+	GetAuditEntriesLive(handler api.AuditEntryHandler) error
+	GetAvailableTopicConfigKeys() ([]string, error)
+	GetConfig() (cfg api.BoxConfig, err error)
+	GetConfigEntry(outPtr interface{}, keys ...string) error
+	GetConnector(clusterName, name string) (connector api.Connector, err error)
+	GetConnectorConfig(clusterName, name string) (cfg api.ConnectorConfig, err error)
+	GetConnectorPluginConfig(clusterName, connectorClass string) ([]api.ConnectorConfigDefinition, error)
+	GetConnectorPlugins(clusterName string) (cp []api.ConnectorPlugin, err error)
+	GetConnectorStatus(clusterName, name string) (cs api.ConnectorStatus, err error)
+	GetConnectorTaskStatus(clusterName, name string, taskID int) (cst api.ConnectorStatusTask, err error)
+	GetConnectorTasks(clusterName, name string) (m []map[string]interface{}, err error)
+	GetConnectors(clusterName string) (names []string, err error)
+	GetConsumerGroup(groupID string) (group api.ConsumerGroup, err error)
+	GetDeploymentTargets() (api.DeploymentTargets, error)
+	GetDynamicBrokerConfigs(brokerID int) (config api.BrokerConfig, err error)
+	GetDynamicClusterConfigs() (configs api.BrokerConfig, err error)
+	GetExecutionMode() (api.ExecutionMode, error)
+	GetKafkaCluster() (cluster api.KafkaCluster, err error)
+	GetLogsInfo() ([]api.LogLine, error)
+	GetLogsMetrics() ([]api.LogLine, error)
+	GetPolicies() ([]api.DataPolicy, error)
+	GetPolicy(id string) (api.DataPolicy, error)
+	GetPolicyCategory() ([]string, error)
+	GetPolicyImpacts() ([]api.DataImpactType, error)
+	GetPolicyObfuscation() ([]api.DataObfuscationType, error)
+	GetProcessor(processorID string) (api.ProcessorStream, error)
+	GetProcessors() (api.ProcessorsResult, error)
+	GetProcessorsLogs(clusterName, ns, podName string, follow bool, lines int, handler func(level string, log string) error) error
+	GetQuotas() ([]api.Quota, error)
+	GetRunningQueries() ([]api.LSQLRunningQuery, error)
+	GetSupportedConnectors() ([]api.ConnectorInfoUI, error)
+	GetTopic(topicName string) (topic api.Topic, err error)
+	GetTopicExtract(id string) ([]api.TopicExtract, error)
+	GetTopicMetadata(topicName string) (api.TopicMetadata, error)
+	GetTopicPartitions(topicName string) ([]api.TopicPartition, error)
+	GetTopics() (topics []api.Topic, err error)
+	GetTopicsMetadata() ([]api.TopicMetadata, error)
+	GetTopicsNames() ([]string, error)
+	GetUserProfile() (api.UserProfile, error)
+	Logout() error
+	LookupProcessorIdentifier(id, name, clusterName, namespace string) (string, error)
+	PauseConnector(clusterName, name string) error
+	PolicyAsRequest(p api.DataPolicy) api.DataPolicyRequest
+	PolicyForPrint(p api.DataPolicy) api.DataPolicyTablePrint
+	ReadJSON(resp *http.Response, valuePtr interface{}) error
+	ReadResponseBody(resp *http.Response) ([]byte, error)
+	RestartConnector(clusterName, name string) error
+	RestartConnectorTask(clusterName, name string, taskID int) error
+	ResumeConnector(clusterName, name string) error
+	ResumeProcessor(processorID string) error
+	StopProcessor(processorID string) error
+	UpdateConnector(clusterName, name string, config api.ConnectorConfig) (connector api.Connector, err error)
+	UpdateDynamicBrokerConfigs(brokerID int, toAddOrUpdate api.BrokerConfig) error
+	UpdateDynamicClusterConfigs(toAddOrUpdate api.BrokerConfig) error
+	UpdatePolicy(policy api.DataPolicyUpdateRequest) error
+	UpdateProcessorRunners(processorID string, numberOfRunners int) error
+	UpdateTopicConfig(topicName string, configsSlice []api.KV) error
+	UpdateTopicPartitions(topicName string, partitions int) error
+	ValidateLSQL(sql string) (v api.LSQLValidation, err error)
+	ValidateSQL(sql string, caret int) (api.SQLValidationResponse, error)
+
+	// connections
+	CreateConnection(connectionName string, templateName string, configString string, configArray []api.ConnectionConfig, tags []string) (err error)
+	DeleteConnection(connectionName string) (err error)
+	GetConnectClusters() (clusters []string, err error)
+	GetConnection(name string) (response api.Connection, err error)
+	GetConnections() (response []api.ConnectionList, err error)
+	UpdateConnection(connectionName string, newName string, configString string, configArray []api.ConnectionConfig, tags []string) (err error)
+
+	// connection templates
+	GetConnectionTemplates() (response []api.ConnectionTemplate, err error)
+
+	// consumers
+	UpdateMultipleTopicsOffset(groupID, offsetType, target string, topics []string) error
+	UpdateSingleTopicOffset(groupID, topic, partitionID, offsetType string, offset int) error
+
+	// datasets
+	UpdateDatasetDescription(connection, name, description string) (err error)
+	UpdateDatasetTags(connection, name string, tags []string) (err error)
+
+	// elasticsearch
+	GetIndex(connectionName string, indexName string) (index api.Index, err error)
+	GetIndexes(connectionName string, includeSystemIndexes bool) (indexes []api.Index, err error)
+
+	// groups
+	CloneGroup(currentName string, newName string) error
+	CreateGroup(group *api.Group) error
+	DeleteGroup(name string) error
+	GetGroup(name string) (group api.Group, err error)
+	GetGroups() (groups []api.Group, err error)
+	UpdateGroup(group *api.Group) error
+
+	// license
+	GetLicenseInfo() (api.LicenseInfo, error)
+	UpdateLicense(license api.License) error
+
+	// saved queries
+	DeleteSavedQuery(name string) error
+	GetSavedQueries() (queries []api.SavedQuery, err error)
+	SaveQuery(query api.SavedQuery) error
+
+	// schemas
+	CheckSchemaCompatibility(name string, request api.WriteSchemaReq) (response api.SchemaCompatibilityRes, err error)
+	GetGlobalCompatibility() (level api.SchemaCompatibilityLevel, err error)
+	GetSchema(name string) (response api.GetSchemaRes, err error)
+	GetSchemaRegistryMode(name string) (mode api.SchemaRegistryMode, err error)
+	GetSubjects() (subs api.Subjects, err error)
+	HardDeleteSchema(name string) (err error)
+	RemoveSchema(name string) (err error)
+	RemoveSchemaVersion(name string, version string) (err error)
+	SetGlobalCompatibility(request api.SetGlobalCompatibilityReq) (err error)
+	SetSchemaRegistryMode(name string, mode string) (err error)
+	SetSchemaCompatibility(name string, request api.SetSchemaCompatibilityReq) (err error)
+	WriteSchema(name string, request api.WriteSchemaReq) (err error)
+
+	// service accounts
+	CreateServiceAccount(serviceAccount *api.ServiceAccount) (token api.CreateSvcAccPayload, err error)
+	DeleteServiceAccount(name string) error
+	GetServiceAccount(name string) (serviceAccount api.ServiceAccount, err error)
+	GetServiceAccounts() (serviceAccounts []api.ServiceAccount, err error)
+	RevokeServiceAccountToken(name string, newToken string) (token api.CreateSvcAccPayload, err error)
+	UpdateServiceAccount(serviceAccount *api.ServiceAccount) error
+
+	// topic settings
+	GetTopicSettings() (settings api.TopicSettingsResponse, err error)
+	UpdateTopicSettings(settings api.TopicSettingsRequest) error
+
+	// users
+	CreateUser(user *api.UserMember) error
+	DeleteUser(username string) error
+	GetUser(name string) (user api.UserMember, err error)
+	GetUsers() (users []api.UserMember, err error)
+	UpdateUser(user *api.UserMember) error
+	UpdateUserPassword(username, password string) error
+}
+
+// LiveStream is a live query's stream of messages, as implemented by the `websocket.LiveConnection`
+// and the `websocket.FileLiveConnection` which replays a recorded session.
+type LiveStream = websocket.LiveStream
+
+var (
+	_ Client     = (*api.Client)(nil)
+	_ LiveStream = (*websocket.LiveConnection)(nil)
+	_ LiveStream = (*websocket.FileLiveConnection)(nil)
+)
+
+// OpenConnection authenticates to the server of the "cfg" and returns its client, see `api.OpenConnection`.
+func OpenConnection(cfg api.ClientConfig, options ...api.ConnectionOption) (Client, error) {
+	client, err := api.OpenConnection(cfg, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}